@@ -2,20 +2,30 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	_ "github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 	"song-library/internal/api"
 	"song-library/internal/api/handler"
 	"song-library/internal/config"
 	"song-library/internal/migration"
 	"song-library/internal/repository/postgres"
+	"song-library/internal/selfcheck"
 	"song-library/internal/service"
+	"song-library/pkg/events"
 	"song-library/pkg/logger"
+	"song-library/pkg/readonly"
+	"song-library/pkg/shutdown"
 
 	_ "song-library/docs"
 )
@@ -37,36 +47,230 @@ func main() {
 		panic("Ошибка загрузки конфигурации: " + err.Error())
 	}
 
-	log := logger.NewLogger(cfg.LogLevel)
+	log := logger.NewLogger(cfg.LogLevel, cfg.LogFormat)
 	log.Info("Запуск приложения")
 
-	db, err := postgres.NewPostgresDB(cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBPassword, cfg.DBName, log)
+	db, err := postgres.NewPostgresDB(cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBPassword, cfg.DBName, cfg.DBApplicationName, log)
 	if err != nil {
 		log.Error("Ошибка подключения к базе данных", "error", err)
 		os.Exit(1)
 	}
 
-	if err = migration.RunMigrations(db.DB, log); err != nil {
-		log.Error("Ошибка выполнения миграций", "error", err)
+	shutdowns := shutdown.NewRegistry(log)
+	shutdowns.Register("database", func(context.Context) error { return db.Close() })
+
+	db.SetMaxOpenConns(cfg.DBMaxOpenConns)
+	db.SetMaxIdleConns(cfg.DBMaxIdleConns)
+	if err = postgres.RegisterPoolMetrics(db, "primary", cfg.DBMaxIdleConns); err != nil {
+		log.Error("Ошибка регистрации метрик пула соединений", "error", err)
 		os.Exit(1)
 	}
 
-	songRepo := postgres.NewSongRepository(db, log)
-	apiClient := service.NewExternalAPIClient(cfg.ExternalAPIURL, log)
-	songService := service.NewSongService(songRepo, apiClient, log)
+	// Реплика обслуживает запросы чтения; без DB_READ_HOST все запросы идут в основную базу
+	var readDB *sqlx.DB
+	var readPoolStats handler.PoolStatsProvider
+	if cfg.DBReadHost != "" {
+		readDB, err = postgres.NewPostgresDB(cfg.DBReadHost, cfg.DBReadPort, cfg.DBUser, cfg.DBPassword, cfg.DBName, cfg.DBApplicationName, log)
+		if err != nil {
+			log.Error("Ошибка подключения к реплике базы данных", "error", err)
+			os.Exit(1)
+		}
+		shutdowns.Register("database_replica", func(context.Context) error { return readDB.Close() })
+
+		readDB.SetMaxOpenConns(cfg.DBMaxOpenConns)
+		readDB.SetMaxIdleConns(cfg.DBMaxIdleConns)
+		if err = postgres.RegisterPoolMetrics(readDB, "replica", cfg.DBMaxIdleConns); err != nil {
+			log.Error("Ошибка регистрации метрик пула соединений реплики", "error", err)
+			os.Exit(1)
+		}
+		readPoolStats = readDB
+		log.Info("Запросы чтения направляются в реплику", "host", cfg.DBReadHost, "port", cfg.DBReadPort)
+	}
+
+	bus := events.NewBus()
+	readOnly := readonly.NewSwitch(cfg.ReadOnly)
+	if cfg.ReadOnly {
+		log.Warn("Сервис запущен в режиме только для чтения")
+	}
+
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	healthMonitor := postgres.NewHealthMonitor(db, cfg.DBHealthCheckInterval, cfg.DBHealthCheckConsecutiveFailures, bus, log)
+	go healthMonitor.Monitor(workersCtx)
+
+	workerPool := service.NewWorkerPool(cfg.WorkerPoolSize, cfg.WorkerQueueSize, log)
+	shutdowns.Register("worker_pool", workerPool.Shutdown)
+
+	auditLogger := postgres.NewAuditPostgresLogger(db, log)
+	go auditLogger.RunCleanup(workersCtx, cfg.EventCleanupInterval, cfg.EventRetentionDays, readOnly.Enabled)
+	shutdowns.Register("background_workers", func(context.Context) error {
+		stopWorkers()
+		return nil
+	})
+
+	songRepo := postgres.NewSongRepository(db, readDB, postgres.RepositoryConfig{
+		DisableAccessLog: cfg.DisableAccessLog,
+		CopyThreshold:    cfg.CopyThreshold,
+		QueryComments:    cfg.DBQueryComments,
+	}, log)
+	shutdowns.Register("song_repository", func(context.Context) error { return songRepo.Close() })
+	retryableRepo := postgres.NewRetryableRepository(songRepo, cfg.DBRetryMax, cfg.DBRetryDelay, log)
+	apiClient, err := service.NewExternalAPIClient(service.ExternalAPIConfig{
+		BaseURL:   cfg.ExternalAPIURL,
+		CacheSize: cfg.ExternalAPICache,
+		CacheTTL:  cfg.ExternalAPITTL,
+		Headers:   cfg.ExternalAPIHeaders,
+		BasicUser: cfg.ExternalAPIBasicUser,
+		BasicPass: cfg.ExternalAPIBasicPass,
+	}, log)
+	if err != nil {
+		panic("Ошибка настройки внешнего API: " + err.Error())
+	}
+	textAnalyzer, err := service.NewTextAnalyzer(cfg.StopwordsFile)
+	if err != nil {
+		log.Error("Ошибка загрузки стоп-слов", "error", err)
+		os.Exit(1)
+	}
+	songService := service.NewSongService(retryableRepo, apiClient, textAnalyzer, auditLogger, service.ServiceConfig{
+		ExternalAPIBudget: cfg.ExternalAPIBudget,
+		MaxTextLength:     cfg.MaxTextLength,
+		MaxLinkLength:     cfg.MaxLinkLength,
+		StrictUTF8:        cfg.StrictUTF8,
+
+		DefaultSongsPageSize:  cfg.DefaultSongsPageSize,
+		MaxSongsPageSize:      cfg.MaxSongsPageSize,
+		DefaultVersesPageSize: cfg.DefaultVersesPageSize,
+		MaxVersesPageSize:     cfg.MaxVersesPageSize,
+		PaginationWarnOffset:  cfg.PaginationWarnOffset,
+		MaxPaginationOffset:   cfg.MaxPaginationOffset,
+
+		MaxSongsForDuplicateCheck: cfg.MaxSongsForDuplicateCheck,
+
+		InProcessCacheSize: cfg.InProcessCacheSize,
+		InProcessCacheTTL:  cfg.InProcessCacheTTL,
+
+		TrashPurgeBatchSize: cfg.TrashPurgeBatchSize,
+		TrashPurgeMaxPerRun: cfg.TrashPurgeMaxPerRun,
+	}, log)
+	if cfg.TrashRetention > 0 {
+		go songService.RunTrashPurge(workersCtx, cfg.TrashPurgeInterval, cfg.TrashRetention, readOnly.Enabled)
+	}
 	songHandler := handler.NewSongHandler(songService, log)
 
-	router := api.NewRouter(songHandler, log, cfg.Environment)
+	bookmarkSecret := cfg.BookmarkSecret
+	if bookmarkSecret == "" {
+		log.Warn("BOOKMARK_SECRET не задан, закладки будут сброшены после перезапуска")
+		secret := make([]byte, 32)
+		if _, err = rand.Read(secret); err != nil {
+			log.Error("Ошибка генерации секрета закладок", "error", err)
+			os.Exit(1)
+		}
+		bookmarkSecret = hex.EncodeToString(secret)
+	}
+	bookmarkHandler := handler.NewBookmarkHandler(songService, bookmarkSecret, log)
+
+	statsService := service.NewStatsService(retryableRepo, cfg.StatsCacheStaleWindow, log)
+	statsHandler := handler.NewStatsHandler(statsService, log)
+
+	eventService := service.NewEventService(auditLogger, log)
+	adminHandler := handler.NewAdminHandler(eventService, cfg.AdminAPIKey, log)
+	historyHandler := handler.NewHistoryHandler(eventService, log)
+	readyHandler := handler.NewReadyHandler(readOnly)
+
+	var snapshotStatus handler.SnapshotStatusProvider
+	if cfg.SnapshotEnabled {
+		snapshots := service.NewSnapshotScheduler(songService, service.SnapshotConfig{
+			Dir:           cfg.SnapshotDir,
+			Interval:      cfg.SnapshotInterval,
+			RetentionDays: cfg.SnapshotRetentionDays,
+		}, log)
+		snapshots.Start(workersCtx)
+		shutdowns.Register("snapshots", snapshots.Shutdown)
+		snapshotStatus = snapshots
+	}
+
+	router := api.NewRouter(songHandler, log,
+		api.WithEnvironment(cfg.Environment),
+		api.WithSwagger(cfg.EnableSwagger),
+		api.WithPprof(cfg.EnablePprof),
+		api.WithPrettyJSON(cfg.Environment != config.EnvironmentProduction &&
+			(cfg.Environment == config.EnvironmentDevelopment || cfg.LogLevel == "debug")),
+		api.WithBodyLimit(int64(cfg.MaxBodyBytes)),
+		api.WithDecompressionLimit(int64(cfg.MaxDecompressed)),
+		api.WithTimeout(cfg.RequestTimeout),
+		api.WithBodyLogging(cfg.LogRequests),
+		api.WithCache(api.CacheConfig{
+			ListMaxAge: cfg.CacheListMaxAge,
+			ItemMaxAge: cfg.CacheItemMaxAge,
+		}),
+		api.WithBookmarks(bookmarkHandler),
+		api.WithStats(statsHandler),
+		api.WithAdmin(adminHandler),
+		api.WithHistory(historyHandler),
+		api.WithAPIAudit(auditLogger, workerPool),
+		api.WithReadiness(readyHandler),
+		api.WithSnapshots(handler.NewSnapshotHandler(snapshotStatus)),
+		api.WithPoolStats(handler.NewPoolHandler(db, readPoolStats)),
+		api.WithExternalAPIHealth(handler.NewExternalAPIHealthHandler(apiClient, cfg.SelfCheckTimeout, log)),
+		api.WithTrash(handler.NewTrashHandler(songService, log)),
+		api.WithReadOnly(readOnly, handler.NewReadOnlyHandler(readOnly, log)),
+	)
 	router.SetupRoutes()
 
-	server := api.NewServer(router, cfg.ServerPort, log)
+	server := api.NewServer(router, cfg.ServerHost, cfg.ServerPort, log)
+	shutdowns.Register("http_server", server.Shutdown)
+
+	checks := []selfcheck.Check{
+		selfcheck.Database(db),
+		selfcheck.Migrations(
+			func() error { return migration.RunMigrations(db.DB, log) },
+			func(ctx context.Context) (int, error) { return migration.AppliedVersion(ctx, db.DB) },
+			migration.Version(),
+		),
+	}
+	if cfg.SelfCheckExternalAPI {
+		checks = append(checks, selfcheck.ExternalAPI(apiClient, cfg.ExternalAPIURL))
+	}
+	checks = append(checks,
+		selfcheck.Info("cache", fmt.Sprintf("in-process: %d, external API: %d", cfg.InProcessCacheSize, cfg.ExternalAPICache)),
+		selfcheck.Info("profile", cfg.Environment),
+		selfcheck.Info("listen", listenAddress(cfg.ServerHost, cfg.ServerPort)),
+	)
+	report := selfcheck.Run(context.Background(), cfg.SelfCheckTimeout, checks...)
+	switch report.Status {
+	case selfcheck.StatusOK:
+		log.Info("Проверки запуска пройдены", report.LogAttrs()...)
+	case selfcheck.StatusDegraded:
+		log.Warn("Проверки запуска пройдены с ошибками", report.LogAttrs()...)
+	default:
+		log.Error("Проверки запуска не пройдены", report.LogAttrs()...)
+		os.Exit(1)
+	}
+	readyHandler.SetReport(report)
+
+	if cfg.ExternalAPIProbeOnStart {
+		probeCtx, cancelProbe := context.WithTimeout(context.Background(), cfg.SelfCheckTimeout)
+		if err = apiClient.Probe(probeCtx); err != nil {
+			log.Warn("Пробный запрос к внешнему API не выполнен", "url", cfg.ExternalAPIURL, "error", err)
+		} else {
+			log.Info("Пробный запрос к внешнему API выполнен", "url", cfg.ExternalAPIURL)
+		}
+		cancelProbe()
+	}
+
+	prepareCtx, cancelPrepare := context.WithTimeout(context.Background(), cfg.SelfCheckTimeout)
+	if err = songRepo.Prepare(prepareCtx); err != nil {
+		log.Warn("Запросы репозитория не подготовлены, используются неподготовленные запросы", "error", err)
+	}
+	cancelPrepare()
+
 	go func() {
 		if err = server.Run(); err != nil {
 			log.Error("Ошибка запуска HTTP сервера", "error", err)
 		}
 	}()
 
-	log.Info("Сервис успешно запущен", "port", cfg.ServerPort)
+	log.Info("Сервис успешно запущен", "host", cfg.ServerHost, "port", cfg.ServerPort)
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -76,9 +280,18 @@ func main() {
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	if err = server.Shutdown(ctx); err != nil {
-		log.Error("Ошибка остановки сервера", "error", err)
+	if err = shutdowns.Shutdown(ctx); err != nil {
+		log.Error("Сервер остановлен с ошибками", "error", err)
+		return
 	}
 
 	log.Info("Сервер успешно остановлен")
 }
+
+// listenAddress возвращает адрес, который слушает сервер: путь к Unix-сокету или host:port
+func listenAddress(host, port string) string {
+	if strings.HasPrefix(host, "/") {
+		return host
+	}
+	return net.JoinHostPort(host, port)
+}
@@ -18,9 +18,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/songs": {
+        "/admin/audit": {
             "get": {
-                "description": "Получение списка песен с фильтрацией и пагинацией",
+                "description": "Получение журнала изменяющих вызовов API для расследований, новые первыми. Требуется заголовок X-Admin-API-Key",
                 "consumes": [
                     "application/json"
                 ],
@@ -28,20 +28,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "admin"
                 ],
-                "summary": "Получение списка песен",
+                "summary": "Журнал вызовов API",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Фильтр по группе",
-                        "name": "group",
+                        "description": "Ключ административного API",
+                        "name": "X-Admin-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (RFC3339)",
+                        "name": "since",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Фильтр по названию песни",
-                        "name": "song",
+                        "description": "Автор вызова",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "songId",
                         "in": "query"
                     },
                     {
@@ -53,8 +66,8 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "default": 10,
-                        "description": "Размер страницы",
+                        "default": 50,
+                        "description": "Размер страницы (не больше 200)",
                         "name": "page_size",
                         "in": "query"
                     }
@@ -65,7 +78,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/model.Song"
+                                "$ref": "#/definitions/model.APICall"
                             }
                         }
                     },
@@ -75,6 +88,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -82,9 +107,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Добавление новой песни в библиотеку",
+            }
+        },
+        "/admin/events": {
+            "get": {
+                "description": "Получение журнала доменных событий с фильтрацией и пагинацией, новые первыми. Требуется заголовок X-Admin-API-Key",
                 "consumes": [
                     "application/json"
                 ],
@@ -92,25 +119,64 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "admin"
                 ],
-                "summary": "Создание новой песни",
+                "summary": "Журнал событий",
                 "parameters": [
                     {
-                        "description": "Данные песни",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.SongInput"
-                        }
+                        "type": "string",
+                        "description": "Ключ административного API",
+                        "name": "X-Admin-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "song_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Тип события",
+                        "name": "event_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Размер страницы (не больше 200)",
+                        "name": "page_size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.IdResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.SongEvent"
+                            }
                         }
                     },
                     "400": {
@@ -119,6 +185,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -128,9 +206,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/songs/{id}": {
+        "/admin/history": {
             "get": {
-                "description": "Получение данных конкретной песни по ID",
+                "description": "История изменений всех песен за период для аудита, новые записи первыми, с общим количеством записей.\nТребуется заголовок X-Admin-API-Key",
                 "consumes": [
                     "application/json"
                 ],
@@ -138,23 +216,57 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "admin"
                 ],
-                "summary": "Получение песни по ID",
+                "summary": "История изменений за период",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID песни",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Ключ административного API",
+                        "name": "X-Admin-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (RFC3339)",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода (RFC3339)",
+                        "name": "to",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Только изменения поля песни: text, group, song, releaseDate или link",
+                        "name": "field",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Размер страницы (не больше 200)",
+                        "name": "page_size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Song"
+                            "$ref": "#/definitions/model.HistoryListResponse"
                         }
                     },
                     "400": {
@@ -163,8 +275,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -176,9 +294,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/readonly": {
             "put": {
-                "description": "Обновление данных существующей песни",
+                "description": "Включает или выключает режим только для чтения. В этом режиме POST, PUT, PATCH и DELETE отклоняются с 503\nи заголовком Retry-After, GET-запросы обслуживаются, а фоновые задачи, изменяющие данные, приостанавливаются.\nТребуется заголовок X-Admin-API-Key",
                 "consumes": [
                     "application/json"
                 ],
@@ -186,24 +306,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "admin"
                 ],
-                "summary": "Обновление песни",
+                "summary": "Режим только для чтения",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID песни",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Ключ административного API",
+                        "name": "X-Admin-API-Key",
+                        "in": "header",
                         "required": true
                     },
                     {
-                        "description": "Обновленные данные песни",
+                        "description": "Новое состояние режима",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.Song"
+                            "$ref": "#/definitions/handler.ReadOnlyInput"
                         }
                     }
                 ],
@@ -211,7 +331,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.SuccessResponse"
+                            "$ref": "#/definitions/handler.ReadOnlyResponse"
                         }
                     },
                     "400": {
@@ -220,46 +340,59 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/trash": {
             "delete": {
-                "description": "Удаление песни из библиотеки",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Окончательно удаляет песни, помеченные удаленными раньше olderThan назад, вместе с журналом обращений и закладками.\nПесни удаляются пачками с фиксацией каждой пачки; при достижении предела за вызов truncated=true и вызов можно повторить.\nТребуется заголовок X-Admin-API-Key",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "admin"
                 ],
-                "summary": "Удаление песни",
+                "summary": "Очистка корзины",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID песни",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Ключ административного API",
+                        "name": "X-Admin-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Срок нахождения в корзине: дни с суффиксом d (30d) или длительность (12h)",
+                        "name": "olderThan",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Сохранить события удаляемых песен",
+                        "name": "keepHistory",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.SuccessResponse"
+                            "$ref": "#/definitions/model.TrashPurgeResult"
                         }
                     },
                     "400": {
@@ -268,8 +401,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -283,9 +422,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/songs/{id}/verses": {
+        "/bookmarks": {
             "get": {
-                "description": "Получение текста песни с пагинацией по куплетам",
+                "description": "Закладки куплетов клиента с текущим текстом куплета, новые первыми.\nЕсли после изменения текста куплета с сохраненным номером больше нет, закладка возвращается с stale=true и пустым verse",
                 "consumes": [
                     "application/json"
                 ],
@@ -293,37 +432,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
-                ],
-                "summary": "Получение текста песни по куплетам",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID песни",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Номер страницы",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 5,
-                        "description": "Размер страницы",
-                        "name": "page_size",
-                        "in": "query"
-                    }
+                    "bookmarks"
                 ],
+                "summary": "Закладки куплетов",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.VersesResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.VerseBookmark"
+                            }
                         }
                     },
                     "400": {
@@ -332,6 +451,44 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{name}/info": {
+            "get": {
+                "description": "Описание, страна, год основания и ссылки группы. Если сведения не заполнены, возвращается 404",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Сведения о группе",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Название группы",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.GroupInfo"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -345,71 +502,4101 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "handler.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string"
-                }
-            }
-        },
-        "handler.IdResponse": {
-            "type": "object",
-            "properties": {
-                "id": {
-                    "type": "integer"
-                }
-            }
-        },
-        "handler.SuccessResponse": {
-            "type": "object",
-            "properties": {
-                "message": {
-                    "type": "string"
-                }
-            }
-        },
-        "handler.VersesResponse": {
+            },
+            "put": {
+                "description": "Создает сведения о группе или заменяет их целиком. Ссылки должны быть абсолютными адресами http или https",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Сохранение сведений о группе",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Название группы",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Сведения о группе",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.GroupInfoInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сведения обновлены",
+                        "schema": {
+                            "$ref": "#/definitions/model.GroupInfo"
+                        }
+                    },
+                    "201": {
+                        "description": "Сведения созданы",
+                        "schema": {
+                            "$ref": "#/definitions/model.GroupInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{name}/rename": {
+            "post": {
+                "description": "Переименование группы у всех ее песен одной транзакцией; для каждой песни в журнал событий пишется song.group_renamed.\nЕсли в новой группе уже есть песни с такими же названиями, возвращается 409 со списком конфликтов и ничего не изменяется.\nmerge=skip оставляет конфликтующие песни под старым названием, merge=overwrite удаляет песни новой группы",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Переименование группы",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Текущее название группы",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "skip",
+                            "overwrite"
+                        ],
+                        "type": "string",
+                        "description": "Разрешение конфликтов",
+                        "name": "merge",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Новое название группы",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.GroupRenameInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.GroupRenameResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.GroupRenameConflictResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Переименование группы у всех ее песен одной транзакцией; для каждой песни в журнал событий пишется song.group_renamed.\nЕсли в новой группе уже есть песни с такими же названиями, возвращается 409 со списком конфликтов и ничего не изменяется.\nmerge=skip оставляет конфликтующие песни под старым названием, merge=overwrite удаляет песни новой группы",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Переименование группы",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Текущее название группы",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "skip",
+                            "overwrite"
+                        ],
+                        "type": "string",
+                        "description": "Разрешение конфликтов",
+                        "name": "merge",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Новое название группы",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.GroupRenameInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.GroupRenameResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.GroupRenameConflictResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{name}/songbook": {
+            "get": {
+                "description": "Все песни группы по алфавиту названий одним документом для печати: для каждой песни название, дата выхода, ссылка и текст.\nДокумент передается потоком по мере чтения песен; при ошибке после начала передачи он обрывается.\nВ формате md пользовательский текст экранируется, чтобы не ломать разметку",
+                "produces": [
+                    "text/plain",
+                    "text/markdown"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Сборник песен группы",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Название группы",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "txt",
+                            "md"
+                        ],
+                        "type": "string",
+                        "default": "txt",
+                        "description": "Формат документа",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сборник песен",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{name}/songs": {
+            "get": {
+                "description": "Песни группы без текста по алфавиту названий с общим количеством песен.\nПараметр sort_by=release_date упорядочивает песни по дате выхода; песни без даты идут последними",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Песни группы",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Название группы",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "song_name",
+                            "release_date"
+                        ],
+                        "type": "string",
+                        "default": "song_name",
+                        "description": "Порядок песен",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Размер страницы (по умолчанию DEFAULT_SONGS_PAGE_SIZE, не больше MAX_SONGS_PAGE_SIZE)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.GroupSongsResponse"
+                        },
+                        "headers": {
+                            "X-Pagination-Warning": {
+                                "type": "string",
+                                "description": "deep-offset, если смещение страницы больше PAGINATION_WARN_OFFSET"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{name}/stats": {
+            "get": {
+                "description": "Количество песен группы, сумма их просмотров и среднее количество куплетов. Если у группы нет песен, возвращается 404",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Статистика группы",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Название группы",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.GroupStats"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{name}/top-songs": {
+            "get": {
+                "description": "Песни группы с наибольшим количеством просмотров (views), последние измененные (updated_at) или добавленные (created_at)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Рейтинг песен группы",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Название группы",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "views",
+                            "updated_at",
+                            "created_at"
+                        ],
+                        "type": "string",
+                        "default": "views",
+                        "description": "Метрика рейтинга",
+                        "name": "metric",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Количество песен (от 1 до 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Song"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs": {
+            "get": {
+                "description": "Получение списка песен с фильтрацией и пагинацией.\nПри быстром поиске (q) каждая песня получает поле relevance: 1.0 — совпадение в названии песни,\n0.8 — в названии группы, 0.6 — в тексте. Результаты сортируются по relevance, затем по убыванию id\nПри поиске по q или text песни, в тексте которых есть совпадение, получают поле snippet: около 150 символов\nвокруг первого совпадения, экранированные для HTML, с совпадением в теге mark\nПри includeText=false элементы имеют схему model.SongSummary (без поля text).\nСведения об авторских правах (copyright) в списке не возвращаются, только в GET /songs/{id}\nС fields элементы содержат только перечисленные поля, например fields=id,contentHash для сверки\nлокальной копии по хэшу содержимого (contentHash меняется при изменении группы, названия, даты выпуска, текста или ссылки)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Получение списка песен",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Фильтр по группе",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по названию песни",
+                        "name": "song",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Точные названия групп через запятую, не больше 50; не сочетается с group",
+                        "name": "groups",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Точные названия песен через запятую, не больше 50; не сочетается с song",
+                        "name": "songs",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Подстрока текста песни без учета регистра, не короче 3 символов. Поиск использует триграммный индекс; на очень частых подстроках он приближается к полному просмотру таблицы",
+                        "name": "text",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Быстрый поиск по группе, названию и тексту песни (нельзя сочетать с group, song, groups, songs и text)",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Включать ли текст песни в ответ",
+                        "name": "includeText",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Минимальная длительность в секундах",
+                        "name": "duration_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Максимальная длительность в секундах",
+                        "name": "duration_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Минимальный темп в ударах в минуту (20–300)",
+                        "name": "bpm_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Максимальный темп в ударах в минуту (20–300)",
+                        "name": "bpm_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "true — только песни с текстом, false — только без текста",
+                        "name": "has_text",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "true — только песни со ссылкой, false — только без ссылки",
+                        "name": "has_link",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Подстрока сведений об авторских правах без учета регистра",
+                        "name": "copyright_contains",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Имя приглашенного исполнителя, точное совпадение",
+                        "name": "featured_artist",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ok",
+                            "failed",
+                            "pending"
+                        ],
+                        "type": "string",
+                        "description": "Статус получения данных от поставщика: failed — для поиска песен без данных",
+                        "name": "enrichmentStatus",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Песни, добавленные не раньше (RFC3339; без смещения — время сервера)",
+                        "name": "created_at_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Песни, добавленные не позже (RFC3339; без смещения — время сервера)",
+                        "name": "created_at_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Незаполненные поля через запятую: text, link, releaseDate, duration, bpm. Песня должна не иметь всех перечисленных полей",
+                        "name": "missing_fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Поля ответа через запятую, например id,contentHash",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Размер страницы (по умолчанию DEFAULT_SONGS_PAGE_SIZE, не больше MAX_SONGS_PAGE_SIZE)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Song"
+                            }
+                        },
+                        "headers": {
+                            "X-Pagination-Warning": {
+                                "type": "string",
+                                "description": "deep-offset, если смещение страницы больше PAGINATION_WARN_OFFSET"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные фильтры или смещение страницы больше PAGINATION_MAX_OFFSET",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Добавление новой песни в библиотеку. Одновременные запросы на создание одной и той же песни объединяются, и каждый из них получает 201 с id созданной песни",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Создание новой песни",
+                "parameters": [
+                    {
+                        "description": "Данные песни",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SongInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.IdResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/bookmarks": {
+            "get": {
+                "description": "Получение песен, добавленных в закладки текущей сессии",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookmarks"
+                ],
+                "summary": "Получение закладок",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Song"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/bulk": {
+            "post": {
+                "description": "Импорт набора песен без обращения к внешнему API. Все песни сохраняются одной транзакцией",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Массовое создание песен",
+                "parameters": [
+                    {
+                        "description": "Песни для импорта",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.SongImport"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.BulkCreateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ConflictResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/by-copyright": {
+            "get": {
+                "description": "Песни, в сведениях об авторских правах которых встречается holder (без учета регистра), начиная с новых.\nКак и в GET /songs, сведения об авторских правах в списке не возвращаются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Песни правообладателя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Правообладатель",
+                        "name": "holder",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Размер страницы (по умолчанию DEFAULT_SONGS_PAGE_SIZE, не больше MAX_SONGS_PAGE_SIZE)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Song"
+                            }
+                        },
+                        "headers": {
+                            "X-Pagination-Warning": {
+                                "type": "string",
+                                "description": "deep-offset, если смещение страницы больше PAGINATION_WARN_OFFSET"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Не указан holder или смещение страницы больше PAGINATION_MAX_OFFSET",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/chart-toppers": {
+            "get": {
+                "description": "Песни, занимавшие первую позицию в чарте на дату date, по истории позиций. Текст песен не возвращается",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Лидеры чарта",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "2024-01-15",
+                        "description": "Дата в формате ГГГГ-ММ-ДД (по умолчанию текущая дата UTC)",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Song"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат даты",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/deleted": {
+            "get": {
+                "description": "Удаленные песни, начиная с удаленных последними",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Список удаленных песен",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Количество песен на странице",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Song"
+                            }
+                        },
+                        "headers": {
+                            "X-Pagination-Warning": {
+                                "type": "string",
+                                "description": "deep-offset, если смещение страницы больше PAGINATION_WARN_OFFSET"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Смещение страницы больше PAGINATION_MAX_OFFSET",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/duplicates": {
+            "get": {
+                "description": "Группы песен с похожими названиями группы и песни (сходство Джаро — Винклера после нормализации,\nрегистр, знаки препинания и порядок слов не учитываются). Доступно для библиотек не больше MAX_SONGS_FOR_DUPLICATE_CHECK песен",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Поиск дубликатов песен",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "default": 0.8,
+                        "description": "Минимальное сходство (0, 1]",
+                        "name": "threshold",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Количество групп на странице",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.DuplicateGroup"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/export": {
+            "get": {
+                "description": "ZIP-архив всей библиотеки: текст каждой песни в файле Группа/Песня.txt и опись manifest.json с метаданными.\nАрхив передается потоком по мере чтения песен; при ошибке после начала передачи архив обрывается.",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Экспорт библиотеки",
+                "parameters": [
+                    {
+                        "enum": [
+                            "zip"
+                        ],
+                        "type": "string",
+                        "default": "zip",
+                        "description": "Формат экспорта",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ZIP-архив",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/import-one": {
+            "post": {
+                "description": "Создание песни из документа, полученного экспортом, без обращения к внешнему API",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Импорт песни",
+                "parameters": [
+                    {
+                        "description": "Документ песни",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SongDocument"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.IdResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ConflictResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/merge": {
+            "post": {
+                "description": "Объединение дубликатов: целевая песня обновляется по стратегии, исходная помечается удаленной.\nСтратегии: append_verses — дописать текст исходной песни, replace_text — заменить текст, keep_target — сохранить текст и перенести метаданные",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Слияние песен",
+                "parameters": [
+                    {
+                        "description": "Параметры слияния",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.MergeInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.NotFoundResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/most-accessed": {
+            "get": {
+                "description": "Топ-10 песен по количеству обращений за период",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Самые популярные песни",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "day",
+                        "description": "Период: day или week",
+                        "name": "period",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.MostAccessedSong"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/tempo-distribution": {
+            "get": {
+                "description": "Гистограмма темпа с интервалами по 20 ударов в минуту от 20 до 300. Интервал \"60-80\" включает 60 и не включает 80,\nпоследний интервал включает 300. Песни без темпа не учитываются, пустые интервалы возвращаются с count=0",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Распределение песен по темпу",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.TempoBucket"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/total-duration": {
+            "get": {
+                "description": "Суммарная длительность песен, группа которых соответствует фильтру",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Суммарная длительность песен",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Фильтр по группе",
+                        "name": "group",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.TotalDuration"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/trending": {
+            "get": {
+                "description": "Песни с наибольшим количеством обращений за период. Список обновляется не чаще раза в 5 минут",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Трендовые песни",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "day",
+                        "description": "Период: day, week или month",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Количество песен (от 1 до 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Song"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}": {
+            "get": {
+                "description": "Получение данных конкретной песни по ID. Поддерживает условные запросы по If-Modified-Since.\nС format=markdown или Accept: text/markdown песня возвращается файлом Markdown",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/markdown"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Получение песни по ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID песни или публичный UUID (publicId)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "markdown"
+                        ],
+                        "type": "string",
+                        "description": "Формат ответа",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата последнего известного клиенту изменения",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Song"
+                        }
+                    },
+                    "304": {
+                        "description": "Песня не изменялась"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.NotFoundResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Обновление данных существующей песни. Если данные не отличаются от сохраненных, запись не изменяется и возвращается changed=false.\nИзмененные вручную поля теряют происхождение (provenance). С protectEnriched=true изменение полей, заполненных поставщиком данных, отклоняется с 409, если не указан force=true",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Обновление песни",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID песни или публичный UUID (publicId)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Запретить изменение полей, заполненных поставщиком данных",
+                        "name": "protectEnriched",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Изменить защищенные поля несмотря на protectEnriched",
+                        "name": "force",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Обновленные данные песни",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Song"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpdateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.NotFoundResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ConflictResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаление песни из библиотеки. Песня помечается удаленной и может быть восстановлена",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Удаление песни",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID песни или публичный UUID (publicId)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.NotFoundResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/access-log": {
+            "get": {
+                "description": "Получение обращений к песне за период с количеством обращений по действиям",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Журнал обращений к песне",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID песни или публичный UUID (publicId)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.AccessLog"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/bookmark": {
+            "post": {
+                "description": "Добавление песни в закладки текущей сессии (не более 50)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookmarks"
+                ],
+                "summary": "Добавление песни в закладки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID песни или публичный UUID (publicId)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.NotFoundResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаление песни из закладок текущей сессии",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookmarks"
+                ],
+                "summary": "Удаление песни из закладок",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID песни или публичный UUID (publicId)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/bpm": {
+            "patch": {
+                "description": "Установка темпа песни в ударах в минуту, от 20 до 300 (null очищает значение).\nС protectEnriched=true изменение темпа, полученного от поставщика данных, отклоняется с 409, если не указан force=true",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Обновление темпа песни",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID песни или публичный UUID (publicId)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Запретить изменение темпа, полученного от поставщика данных",
+                        "name": "protectEnriched",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Изменить темп несмотря на protectEnriched",
+                        "name": "force",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Темп песни",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.BPMInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.NotFoundResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ConflictResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/chart": {
+            "patch": {
+                "description": "Установка текущей позиции песни в чарте, от 1 до 200. Позиция с текущей датой (UTC) добавляется в историю чарта песни;\nхранятся последние 52 снимка, более старые удаляются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Обновление позиции песни в чарте",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID песни или публичный UUID (publicId)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Позиция в чарте",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ChartInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.NotFoundResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/copyright": {
+            "patch": {
+                "description": "Установка сведений об авторских правах песни, не длиннее 2000 символов (null или пустая строка очищает значение).\nС protectEnriched=true изменение сведений, полученных от поставщика данных, отклоняется с 409, если не указан force=true",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Обновление сведений об авторских правах песни",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID песни или публичный UUID (publicId)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Запретить изменение сведений, полученных от поставщика данных",
+                        "name": "protectEnriched",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Изменить сведения несмотря на protectEnriched",
+                        "name": "force",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Сведения об авторских правах",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CopyrightInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.NotFoundResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ConflictResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/duration": {
+            "patch": {
+                "description": "Установка длительности песни в секундах (null очищает значение).\nС protectEnriched=true изменение длительности, полученной от поставщика данных, отклоняется с 409, если не указан force=true",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Обновление длительности песни",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID песни или публичный UUID (publicId)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Запретить изменение длительности, полученной от поставщика данных",
+                        "name": "protectEnriched",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Изменить длительность несмотря на protectEnriched",
+                        "name": "force",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Длительность песни",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.DurationInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.NotFoundResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ConflictResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/export": {
+            "get": {
+                "description": "Получение песни в виде переносимого JSON-документа для импорта в другую инсталляцию",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Экспорт песни",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID песни или публичный UUID (publicId)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SongDocument"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.NotFoundResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/featured-artists": {
+            "patch": {
+                "description": "Замена списка приглашенных исполнителей песни, не больше 10 имен (пустой список или null очищает его).\nПробелы по краям имен удаляются, пустые и повторяющиеся без учета регистра имена пропускаются.\nС protectEnriched=true изменение списка, полученного от поставщика данных, отклоняется с 409, если не указан force=true",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Обновление приглашенных исполнителей песни",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID песни или публичный UUID (publicId)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Запретить изменение списка, полученного от поставщика данных",
+                        "name": "protectEnriched",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Изменить список несмотря на protectEnriched",
+                        "name": "force",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Приглашенные исполнители",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.FeaturedArtistsInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.NotFoundResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ConflictResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/formatted": {
+            "get": {
+                "description": "Текст песни с переносом строк по границам слов и отступом куплетов. Слова длиннее width не разрываются",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Форматированный текст песни",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID песни или публичный UUID (publicId)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 80,
+                        "description": "Максимальная ширина строки (от 20 до 200)",
+                        "name": "width",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Отступ куплетов в пробелах (от 0 до 20)",
+                        "name": "indent",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Форматированный текст",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.NotFoundResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/history": {
+            "get": {
+                "description": "История изменений песни из журнала событий, новые записи первыми, с общим количеством записей.\noperation ограничивает историю одним типом изменений: updated, duration_updated, deleted и т. д.\nfield оставляет только изменения одного поля песни (снимки до и после изменения различаются в этом поле),\nsince — изменения начиная с момента времени. Записи с одинаковым временем упорядочены по убыванию id.\nИзменения текста возвращаются унифицированным диффом в textDiff (не больше 500 строк),\nостальных полей — парами old/new в changes; full=true возвращает вместо них снимки песни целиком.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "История изменений песни",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID песни или публичный UUID (publicId)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Тип изменения (тип события без префикса song.)",
+                        "name": "operation",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Поле песни: text, group, song, releaseDate или link",
+                        "name": "field",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (RFC3339)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Вернуть снимки песни целиком вместо диффа",
+                        "name": "full",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Размер страницы (не больше 200)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.HistoryListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/restore": {
+            "post": {
+                "description": "Снимает пометку удаления с песни и возвращает ее. Если группа и название заняты другой песней, возвращается 409 с ID этой песни (existing_id) и восстанавливаемой песни (song_id), чтобы их можно было объединить через POST /songs/merge",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Восстановление удаленной песни",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID песни или публичный UUID (publicId)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Song"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.NotFoundResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ConflictResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/verses": {
+            "get": {
+                "description": "Получение текста песни с пагинацией по куплетам или диапазоном куплетов from–to.\nДиапазон, выходящий за пределы текста, обрезается до существующих куплетов.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Получение текста песни по куплетам",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID песни или публичный UUID (publicId)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Размер страницы (по умолчанию DEFAULT_VERSES_PAGE_SIZE, не больше MAX_VERSES_PAGE_SIZE)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Первый куплет диапазона (с 1, нельзя сочетать с page и page_size)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Последний куплет диапазона включительно (нельзя сочетать с page и page_size)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Порядок куплетов: asc или desc",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.VersesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.NotFoundResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/verses/order": {
+            "put": {
+                "description": "Переставляет куплеты песни в указанном порядке. order — перестановка номеров куплетов, начиная с 1:\nкаждый номер от 1 до количества куплетов ровно один раз. Прежний текст сохраняется в истории изменений",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Перестановка куплетов песни",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID песни или публичный UUID (publicId)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новый порядок куплетов",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.VerseOrderInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.VerseOrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.NotFoundResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "order не является перестановкой номеров куплетов",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/verses/{n}": {
+            "put": {
+                "description": "Заменяет куплет с номером n (с 1) новым текстом. Текст куплета не может быть пустым или содержать пустых строк.\nПрежний текст сохраняется в истории изменений",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Изменение куплета песни",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID песни или публичный UUID (publicId)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер куплета (с 1)",
+                        "name": "n",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новый текст куплета",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.VerseInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.VerseEditResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня или куплет не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет куплет с номером n (с 1); следующие куплеты сдвигаются. Единственный куплет песни удалить нельзя.\nПрежний текст сохраняется в истории изменений",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Удаление куплета песни",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID песни или публичный UUID (publicId)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер куплета (с 1)",
+                        "name": "n",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.VerseEditResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные параметры или попытка удалить единственный куплет",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня или куплет не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/verses/{n}/bookmark": {
+            "post": {
+                "description": "Добавление куплета песни в закладки клиента с необязательной заметкой. Повторное добавление заменяет заметку",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookmarks"
+                ],
+                "summary": "Добавление куплета в закладки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID песни или публичный UUID (publicId)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер куплета (с 1)",
+                        "name": "n",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Заметка",
+                        "name": "input",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.VerseBookmarkInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.NotFoundResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаление закладки куплета песни",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookmarks"
+                ],
+                "summary": "Удаление куплета из закладок",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID песни или публичный UUID (publicId)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер куплета (с 1)",
+                        "name": "n",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/word-frequency": {
+            "get": {
+                "description": "Самые частые слова текста песни без учета регистра, знаков препинания и стоп-слов",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Частота слов в тексте песни",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID песни или публичный UUID (publicId)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Количество слов (от 1 до 100)",
+                        "name": "top",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.WordCount"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.NotFoundResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/enrichment": {
+            "get": {
+                "description": "Количество песен по статусу последнего получения данных от поставщика: ok, failed и pending.\nПесни со статусом failed можно найти через GET /songs?enrichmentStatus=failed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Статусы получения данных песен",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.EnrichmentStatusCount"
+                            }
+                        },
+                        "headers": {
+                            "X-Cache-Age": {
+                                "type": "integer",
+                                "description": "Возраст результата в кэше в секундах"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/groups/top": {
+            "get": {
+                "description": "Группы с наибольшим количеством песен или обращений к песням",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Рейтинг групп",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Размер рейтинга (от 1 до 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "songs",
+                        "description": "Показатель: songs или plays",
+                        "name": "by",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.GroupStat"
+                            }
+                        },
+                        "headers": {
+                            "X-Cache-Age": {
+                                "type": "integer",
+                                "description": "Возраст результата в кэше в секундах"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/growth": {
+            "get": {
+                "description": "Количество добавленных песен по интервалам, интервалы без песен заполнены нулями (не более 366 интервалов)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Динамика роста библиотеки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "day",
+                        "description": "Интервал: day, week или month",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода (RFC3339, не включительно)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.GrowthBucket"
+                            }
+                        },
+                        "headers": {
+                            "X-Cache-Age": {
+                                "type": "integer",
+                                "description": "Возраст результата в кэше в секундах"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/snapshots": {
+            "get": {
+                "description": "Время, результат и файл последнего планового снимка библиотеки и количество пропущенных запусков",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Состояние плановых снимков",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SnapshotStatus"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handler.ConflictResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "Песня уже существует"
+                },
+                "existing_id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "song_id": {
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "handler.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "Песня не найдена"
+                }
+            }
+        },
+        "handler.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "text"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "поле text превышает максимальную длину 102400 байт"
+                }
+            }
+        },
+        "handler.GroupRenameConflictResponse": {
+            "type": "object",
+            "properties": {
+                "conflicts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.RenameConflict"
+                    }
+                },
+                "error": {
+                    "type": "string",
+                    "example": "В новой группе уже есть песни с такими названиями"
+                }
+            }
+        },
+        "handler.IdResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "publicId": {
+                    "type": "string",
+                    "example": "0b6a4f1e-8c2d-4f7a-9e3b-5d1c2a7f9e40"
+                }
+            }
+        },
+        "handler.NotFoundResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "Песня не найдена"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "handler.ReadOnlyInput": {
+            "type": "object",
+            "required": [
+                "enabled"
+            ],
+            "properties": {
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.ReadOnlyResponse": {
+            "type": "object",
+            "properties": {
+                "read_only": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.SuccessResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Песня успешно удалена"
+                }
+            }
+        },
+        "handler.UpdateResponse": {
+            "type": "object",
+            "properties": {
+                "changed": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Песня успешно обновлена"
+                },
+                "song": {
+                    "$ref": "#/definitions/model.Song"
+                }
+            }
+        },
+        "handler.ValidationErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "поле text превышает максимальную длину 102400 байт"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.FieldError"
+                    }
+                }
+            }
+        },
+        "handler.VersesResponse": {
             "type": "object",
             "properties": {
+                "total_verses": {
+                    "description": "TotalVerses общее количество куплетов песни, независимо от страницы",
+                    "type": "integer",
+                    "example": 2
+                },
                 "verses": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Первый куплет",
+                        "Второй куплет"
+                    ]
+                }
+            }
+        },
+        "model.APICall": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string",
+                    "example": "192.0.2.10"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "latency_ms": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "method": {
+                    "type": "string",
+                    "example": "PUT"
+                },
+                "occurred_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "3f6c1a9e-7f1b-4c4e-9d2a-1b2c3d4e5f60"
+                },
+                "route": {
+                    "type": "string",
+                    "example": "/api/v1/songs/:id"
+                },
+                "song_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 200
+                }
+            }
+        },
+        "model.AccessLog": {
+            "type": "object",
+            "properties": {
+                "counts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    },
+                    "example": {
+                        "verses": 1,
+                        "view": 2
+                    }
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AccessLogEntry"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "model.AccessLogEntry": {
+            "type": "object",
+            "properties": {
+                "accessed_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "action": {
+                    "type": "string",
+                    "example": "view"
+                }
+            }
+        },
+        "model.BPMInput": {
+            "type": "object",
+            "properties": {
+                "bpm": {
+                    "type": "integer",
+                    "example": 120
+                }
+            }
+        },
+        "model.BulkCreateResponse": {
+            "type": "object",
+            "properties": {
+                "inserted": {
+                    "type": "integer",
+                    "example": 25
+                }
+            }
+        },
+        "model.ChartEntry": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2024-01-15"
+                },
+                "position": {
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "model.ChartInput": {
+            "type": "object",
+            "properties": {
+                "position": {
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "model.CopyrightInput": {
+            "type": "object",
+            "properties": {
+                "copyright": {
+                    "type": "string",
+                    "example": "© 2006 Warner Music UK Limited"
+                }
+            }
+        },
+        "model.DuplicateGroup": {
+            "type": "object",
+            "properties": {
+                "candidates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SongSummary"
+                    }
+                }
+            }
+        },
+        "model.DurationInput": {
+            "type": "object",
+            "properties": {
+                "duration_seconds": {
+                    "type": "integer",
+                    "example": 212
+                }
+            }
+        },
+        "model.EnrichmentStatusCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "status": {
+                    "type": "string",
+                    "example": "failed"
+                }
+            }
+        },
+        "model.FeaturedArtistsInput": {
+            "type": "object",
+            "properties": {
+                "artists": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Guest 1",
+                        "Guest 2"
+                    ]
+                }
+            }
+        },
+        "model.FieldChange": {
+            "type": "object",
+            "properties": {
+                "new": {
+                    "type": "string",
+                    "example": "MUSE"
+                },
+                "old": {
+                    "type": "string",
+                    "example": "Muse"
+                }
+            }
+        },
+        "model.FieldSource": {
+            "type": "object",
+            "properties": {
+                "fetchedAt": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "provider": {
+                    "type": "string",
+                    "example": "external_api"
+                }
+            }
+        },
+        "model.GroupInfo": {
+            "type": "object",
+            "properties": {
+                "country": {
+                    "type": "string",
+                    "example": "United Kingdom"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Британская рок-группа из Тинмута"
+                },
+                "formed_year": {
+                    "type": "integer",
+                    "example": 1994
+                },
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "https://www.muse.mu"
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Muse"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                }
+            }
+        },
+        "model.GroupInfoInput": {
+            "type": "object",
+            "properties": {
+                "country": {
+                    "type": "string",
+                    "example": "United Kingdom"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Британская рок-группа из Тинмута"
+                },
+                "formed_year": {
+                    "type": "integer",
+                    "example": 1994
+                },
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "https://www.muse.mu"
+                    ]
+                }
+            }
+        },
+        "model.GroupRenameInput": {
+            "type": "object",
+            "required": [
+                "newName"
+            ],
+            "properties": {
+                "newName": {
+                    "type": "string",
+                    "example": "Muse (UK)"
+                }
+            }
+        },
+        "model.GroupRenameResult": {
+            "type": "object",
+            "properties": {
+                "overwritten": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "renamed": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "renamed_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        3
+                    ]
+                },
+                "skipped": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "model.GroupSongsResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SongSummary"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "model.GroupStat": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "group": {
+                    "type": "string",
+                    "example": "Muse"
+                },
+                "has_info": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "model.GroupStats": {
+            "type": "object",
+            "properties": {
+                "avg_verse_count": {
+                    "type": "number",
+                    "example": 3.5
+                },
+                "song_count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "total_views": {
+                    "type": "integer",
+                    "example": 340
+                }
+            }
+        },
+        "model.GrowthBucket": {
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "type": "string",
+                    "example": "2024-01-15"
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "model.HistoryListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SongHistoryEntry"
+                    }
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "page_size": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 134
+                },
+                "total_pages": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "model.MergeInput": {
+            "type": "object",
+            "required": [
+                "source_id",
+                "strategy",
+                "target_id"
+            ],
+            "properties": {
+                "source_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "strategy": {
+                    "type": "string",
+                    "example": "append_verses"
+                },
+                "target_id": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "model.MostAccessedSong": {
+            "type": "object",
+            "properties": {
+                "accessCount": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "bpm": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "chartHistory": {
+                    "description": "ChartHistory последние MaxChartHistory снимков позиции в чарте, от старых к новым",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ChartEntry"
+                    }
+                },
+                "chartPosition": {
+                    "description": "ChartPosition текущая позиция песни в чарте; null, если песня не в чарте",
+                    "type": "integer",
+                    "example": 5
+                },
+                "contentHash": {
+                    "type": "string",
+                    "example": "3b7e1f0c9d2a4e5b6c7d8e9f0a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d"
+                },
+                "copyright": {
+                    "type": "string",
+                    "example": "© 2006 Warner Music UK Limited"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "deletedAt": {
+                    "type": "string",
+                    "example": "2024-02-01T08:00:00Z"
+                },
+                "duration": {
+                    "type": "integer",
+                    "example": 212
+                },
+                "enrichedAt": {
+                    "description": "EnrichedAt время последнего успешного получения данных от поставщика; null, если данные не получены",
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "enrichmentStatus": {
+                    "description": "EnrichmentStatus результат последнего получения данных песни от поставщика: ok, failed или pending",
+                    "type": "string",
+                    "example": "ok"
+                },
+                "featuredArtists": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
+                },
+                "group": {
+                    "type": "string",
+                    "example": "Muse"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "link": {
+                    "type": "string",
+                    "example": "https://www.youtube.com/watch?v=Xsp3_a-PMTw"
+                },
+                "provenance": {
+                    "$ref": "#/definitions/model.Provenance"
+                },
+                "publicId": {
+                    "type": "string",
+                    "example": "0b6a4f1e-8c2d-4f7a-9e3b-5d1c2a7f9e40"
+                },
+                "releaseDate": {
+                    "type": "string",
+                    "example": "16.07.2006"
+                },
+                "relevance": {
+                    "type": "number",
+                    "example": 0.8
+                },
+                "snippet": {
+                    "type": "string",
+                    "example": "…don't you know I \u003cmark\u003esuffer\u003c/mark\u003e?…"
+                },
+                "song": {
+                    "type": "string",
+                    "example": "Supermassive Black Hole"
+                },
+                "text": {
+                    "type": "string",
+                    "example": "Ooh baby, don't you know I suffer?\nOoh baby, can you hear me moan?\n\nOoh\nYou set my soul alight"
+                },
+                "textLength": {
+                    "type": "integer",
+                    "example": 98
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "verseCount": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "model.Provenance": {
+            "type": "object",
+            "additionalProperties": {
+                "$ref": "#/definitions/model.FieldSource"
+            }
+        },
+        "model.RenameConflict": {
+            "type": "object",
+            "properties": {
+                "existing_id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "song": {
+                    "type": "string",
+                    "example": "Supermassive Black Hole"
+                },
+                "song_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "model.SnapshotStatus": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "interval": {
+                    "type": "string",
+                    "example": "24h0m0s"
+                },
+                "last_count": {
+                    "type": "integer",
+                    "example": 1250
+                },
+                "last_duration": {
+                    "type": "string",
+                    "example": "1.52s"
+                },
+                "last_error": {
+                    "type": "string",
+                    "example": ""
+                },
+                "last_file": {
+                    "type": "string",
+                    "example": "songs-20240115T030000Z.jsonl"
+                },
+                "last_run_at": {
+                    "type": "string",
+                    "example": "2024-01-15T03:00:00Z"
+                },
+                "last_success_at": {
+                    "type": "string",
+                    "example": "2024-01-15T03:00:02Z"
+                },
+                "running": {
+                    "description": "Running сообщает, что снимок создается прямо сейчас",
+                    "type": "boolean",
+                    "example": false
+                },
+                "skipped": {
+                    "description": "Skipped количество запусков, пропущенных из-за незавершенного предыдущего снимка",
+                    "type": "integer",
+                    "example": 0
                 }
             }
         },
         "model.Song": {
             "type": "object",
             "properties": {
+                "bpm": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "chartHistory": {
+                    "description": "ChartHistory последние MaxChartHistory снимков позиции в чарте, от старых к новым",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ChartEntry"
+                    }
+                },
+                "chartPosition": {
+                    "description": "ChartPosition текущая позиция песни в чарте; null, если песня не в чарте",
+                    "type": "integer",
+                    "example": 5
+                },
+                "contentHash": {
+                    "type": "string",
+                    "example": "3b7e1f0c9d2a4e5b6c7d8e9f0a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d"
+                },
+                "copyright": {
+                    "type": "string",
+                    "example": "© 2006 Warner Music UK Limited"
+                },
                 "createdAt": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "deletedAt": {
+                    "type": "string",
+                    "example": "2024-02-01T08:00:00Z"
+                },
+                "duration": {
+                    "type": "integer",
+                    "example": 212
+                },
+                "enrichedAt": {
+                    "description": "EnrichedAt время последнего успешного получения данных от поставщика; null, если данные не получены",
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "enrichmentStatus": {
+                    "description": "EnrichmentStatus результат последнего получения данных песни от поставщика: ok, failed или pending",
+                    "type": "string",
+                    "example": "ok"
+                },
+                "featuredArtists": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
                 "group": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "Muse"
                 },
                 "id": {
-                    "type": "integer"
+                    "type": "integer",
+                    "example": 1
                 },
                 "link": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "https://www.youtube.com/watch?v=Xsp3_a-PMTw"
+                },
+                "provenance": {
+                    "$ref": "#/definitions/model.Provenance"
+                },
+                "publicId": {
+                    "type": "string",
+                    "example": "0b6a4f1e-8c2d-4f7a-9e3b-5d1c2a7f9e40"
                 },
                 "releaseDate": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "16.07.2006"
+                },
+                "relevance": {
+                    "type": "number",
+                    "example": 0.8
+                },
+                "snippet": {
+                    "type": "string",
+                    "example": "…don't you know I \u003cmark\u003esuffer\u003c/mark\u003e?…"
                 },
                 "song": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "Supermassive Black Hole"
                 },
                 "text": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "Ooh baby, don't you know I suffer?\nOoh baby, can you hear me moan?\n\nOoh\nYou set my soul alight"
+                },
+                "textLength": {
+                    "type": "integer",
+                    "example": 98
                 },
                 "updatedAt": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "verseCount": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "model.SongDocument": {
+            "type": "object",
+            "required": [
+                "formatVersion",
+                "group",
+                "song"
+            ],
+            "properties": {
+                "bpm": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "copyright": {
+                    "type": "string",
+                    "example": "© 2006 Warner Music UK Limited"
+                },
+                "duration": {
+                    "type": "integer",
+                    "example": 212
+                },
+                "featuredArtists": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "formatVersion": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "group": {
+                    "type": "string",
+                    "example": "Muse"
+                },
+                "link": {
+                    "type": "string",
+                    "example": "https://www.youtube.com/watch?v=Xsp3_a-PMTw"
+                },
+                "provenance": {
+                    "$ref": "#/definitions/model.Provenance"
+                },
+                "releaseDate": {
+                    "type": "string",
+                    "example": "16.07.2006"
+                },
+                "song": {
+                    "type": "string",
+                    "example": "Supermassive Black Hole"
+                },
+                "text": {
+                    "type": "string",
+                    "example": "Ooh baby, don't you know I suffer?"
+                }
+            }
+        },
+        "model.SongEvent": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string",
+                    "example": "192.0.2.10"
+                },
+                "event_type": {
+                    "type": "string",
+                    "example": "song.updated"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "occurred_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "song_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "model.SongHistoryEntry": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string",
+                    "example": "192.0.2.10"
+                },
+                "changes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/model.FieldChange"
+                    }
+                },
+                "event_type": {
+                    "type": "string",
+                    "example": "song.updated"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "occurred_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "song_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "textDiff": {
+                    "type": "string",
+                    "example": "--- before\n+++ after\n@@ -1,1 +1,1 @@\n-Ooh baby\n+Oh baby"
+                }
+            }
+        },
+        "model.SongImport": {
+            "type": "object",
+            "required": [
+                "group",
+                "song"
+            ],
+            "properties": {
+                "bpm": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "duration": {
+                    "type": "integer",
+                    "example": 212
+                },
+                "group": {
+                    "type": "string",
+                    "example": "Muse"
+                },
+                "link": {
+                    "type": "string",
+                    "example": "https://www.youtube.com/watch?v=Xsp3_a-PMTw"
+                },
+                "releaseDate": {
+                    "type": "string",
+                    "example": "16.07.2006"
+                },
+                "song": {
+                    "type": "string",
+                    "example": "Supermassive Black Hole"
+                },
+                "text": {
+                    "type": "string",
+                    "example": "Ooh baby, don't you know I suffer?"
                 }
             }
         },
@@ -421,10 +4608,251 @@ const docTemplate = `{
             ],
             "properties": {
                 "group": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "Muse"
+                },
+                "song": {
+                    "type": "string",
+                    "example": "Supermassive Black Hole"
+                }
+            }
+        },
+        "model.SongSummary": {
+            "type": "object",
+            "properties": {
+                "bpm": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "contentHash": {
+                    "type": "string",
+                    "example": "3b7e1f0c9d2a4e5b6c7d8e9f0a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "duration": {
+                    "type": "integer",
+                    "example": 212
+                },
+                "enrichedAt": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "enrichmentStatus": {
+                    "type": "string",
+                    "example": "ok"
+                },
+                "featuredArtists": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "group": {
+                    "type": "string",
+                    "example": "Muse"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "link": {
+                    "type": "string",
+                    "example": "https://www.youtube.com/watch?v=Xsp3_a-PMTw"
+                },
+                "provenance": {
+                    "$ref": "#/definitions/model.Provenance"
+                },
+                "publicId": {
+                    "type": "string",
+                    "example": "0b6a4f1e-8c2d-4f7a-9e3b-5d1c2a7f9e40"
+                },
+                "releaseDate": {
+                    "type": "string",
+                    "example": "16.07.2006"
+                },
+                "relevance": {
+                    "type": "number",
+                    "example": 0.8
+                },
+                "snippet": {
+                    "type": "string",
+                    "example": "…don't you know I \u003cmark\u003esuffer\u003c/mark\u003e?…"
+                },
+                "song": {
+                    "type": "string",
+                    "example": "Supermassive Black Hole"
+                },
+                "textLength": {
+                    "type": "integer",
+                    "example": 98
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "verseCount": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "model.TempoBucket": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "range": {
+                    "type": "string",
+                    "example": "60-80"
+                }
+            }
+        },
+        "model.TotalDuration": {
+            "type": "object",
+            "properties": {
+                "formatted": {
+                    "type": "string",
+                    "example": "3h 27m 15s"
+                },
+                "total_seconds": {
+                    "type": "integer",
+                    "example": 12435
+                }
+            }
+        },
+        "model.TrashPurgeResult": {
+            "type": "object",
+            "properties": {
+                "purged": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "truncated": {
+                    "description": "Truncated сообщает, что достигнут предел песен за один вызов и в корзине могут остаться песни старше срока",
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "model.VerseBookmark": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "group": {
+                    "type": "string",
+                    "example": "Muse"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Любимый припев"
                 },
                 "song": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "Supermassive Black Hole"
+                },
+                "song_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "stale": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "verse": {
+                    "type": "string",
+                    "example": "Ooh\nYou set my soul alight"
+                },
+                "verse_position": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "model.VerseBookmarkInput": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "example": "Любимый припев"
+                }
+            }
+        },
+        "model.VerseEditResponse": {
+            "type": "object",
+            "properties": {
+                "index": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "updated": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "model.VerseInput": {
+            "type": "object",
+            "required": [
+                "text"
+            ],
+            "properties": {
+                "text": {
+                    "type": "string",
+                    "example": "Ooh baby, don't you know I suffer?"
+                }
+            }
+        },
+        "model.VerseOrderInput": {
+            "type": "object",
+            "required": [
+                "order"
+            ],
+            "properties": {
+                "order": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        3,
+                        1,
+                        2,
+                        4
+                    ]
+                }
+            }
+        },
+        "model.VerseOrderResponse": {
+            "type": "object",
+            "properties": {
+                "verse_count": {
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "model.WordCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "word": {
+                    "type": "string",
+                    "example": "baby"
                 }
             }
         }
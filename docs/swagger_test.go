@@ -0,0 +1,48 @@
+package docs
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestSwaggerUpToDate перегенерирует документацию по аннотациям обработчиков и сравнивает ее
+// с docs/swagger.json, чтобы аннотации не расходились с закоммиченной документацией.
+// Без утилиты swag в PATH тест пропускается.
+func TestSwaggerUpToDate(t *testing.T) {
+	swag, err := exec.LookPath("swag")
+	if err != nil {
+		t.Skip("swag не найден в PATH")
+	}
+
+	out := t.TempDir()
+	cmd := exec.Command(swag, "init", "-g", "cmd/server/main.go", "-o", out, "--outputTypes", "json")
+	cmd.Dir = ".."
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("swag init error = %v\n%s", err, output)
+	}
+
+	generated := readSwagger(t, filepath.Join(out, "swagger.json"))
+	committed := readSwagger(t, "swagger.json")
+	if !reflect.DeepEqual(generated, committed) {
+		t.Error("docs/swagger.json не совпадает с аннотациями обработчиков; выполните swag init -g cmd/server/main.go -o docs")
+	}
+}
+
+// readSwagger читает документ Swagger из path без учета форматирования
+func readSwagger(t *testing.T, path string) any {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ошибка чтения %s: %v", path, err)
+	}
+	var doc any
+	if err = json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("ошибка разбора %s: %v", path, err)
+	}
+	return doc
+}
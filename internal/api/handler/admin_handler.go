@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"song-library/internal/model"
+	"song-library/pkg/logger"
+	"strconv"
+)
+
+// adminAPIKeyHeader заголовок с ключом административного API
+const adminAPIKeyHeader = "X-Admin-API-Key"
+
+// EventService интерфейс сервиса журнала событий
+type EventService interface {
+	ListEvents(ctx context.Context, filter model.EventFilter) ([]model.SongEvent, error)
+	ListHistory(ctx context.Context, filter model.EventFilter) (*model.HistoryListResponse, error)
+	ListAPICalls(ctx context.Context, filter model.APICallFilter) ([]model.APICall, error)
+}
+
+// AdminHandler обработчик административных запросов.
+// Доступ разрешен только с ключом apiKey; если ключ не задан, административный API отключен.
+type AdminHandler struct {
+	service EventService
+	apiKey  []byte
+	logger  *logger.Logger
+}
+
+// NewAdminHandler создает новый обработчик административных запросов
+func NewAdminHandler(service EventService, apiKey string, logger *logger.Logger) *AdminHandler {
+	return &AdminHandler{
+		service: service,
+		apiKey:  []byte(apiKey),
+		logger:  logger,
+	}
+}
+
+// RequireAPIKey возвращает middleware, проверяющий ключ административного API
+func (h *AdminHandler) RequireAPIKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if len(h.apiKey) == 0 {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Error: "Административный API отключен"})
+			return
+		}
+
+		key := []byte(c.GetHeader(adminAPIKeyHeader))
+		if subtle.ConstantTimeCompare(key, h.apiKey) != 1 {
+			h.logger.WithContext(c.Request.Context()).Info("Неверный ключ административного API", "path", c.Request.URL.Path)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Error: "Неверный ключ API"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// @Summary Журнал событий
+// @Description Получение журнала доменных событий с фильтрацией и пагинацией, новые первыми. Требуется заголовок X-Admin-API-Key
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-API-Key header string true "Ключ административного API"
+// @Param song_id query int false "ID песни"
+// @Param event_type query string false "Тип события"
+// @Param from query string false "Начало периода (RFC3339)"
+// @Param to query string false "Конец периода (RFC3339)"
+// @Param page query int false "Номер страницы" default(1)
+// @Param page_size query int false "Размер страницы (не больше 200)" default(50)
+// @Success 200 {array} model.SongEvent
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/events [get]
+func (h *AdminHandler) ListEvents(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+
+	filter := model.EventFilter{EventType: c.Query("event_type")}
+
+	if value := c.Query("song_id"); value != "" {
+		songID, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			log.Error("Неверный формат song_id", "error", err)
+			WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат song_id"})
+			return
+		}
+		filter.SongID = &songID
+	}
+
+	var err error
+	if filter.From, err = parseOptionalTime(c.Query("from")); err != nil {
+		log.Error("Неверный формат from", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат from, ожидается RFC3339"})
+		return
+	}
+	if filter.To, err = parseOptionalTime(c.Query("to")); err != nil {
+		log.Error("Неверный формат to", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат to, ожидается RFC3339"})
+		return
+	}
+
+	filter.Pagination = queryPagination(c)
+
+	events, err := h.service.ListEvents(c.Request.Context(), filter)
+	if err != nil {
+		log.Error("Ошибка получения журнала событий", "error", err)
+		writeError(c, err, "Ошибка получения журнала событий")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, events)
+}
+
+// @Summary История изменений за период
+// @Description История изменений всех песен за период для аудита, новые записи первыми, с общим количеством записей.
+// @Description Требуется заголовок X-Admin-API-Key
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-API-Key header string true "Ключ административного API"
+// @Param from query string true "Начало периода (RFC3339)"
+// @Param to query string true "Конец периода (RFC3339)"
+// @Param field query string false "Только изменения поля песни: text, group, song, releaseDate или link"
+// @Param page query int false "Номер страницы" default(1)
+// @Param page_size query int false "Размер страницы (не больше 200)" default(50)
+// @Success 200 {object} model.HistoryListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/history [get]
+func (h *AdminHandler) ListHistory(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+
+	var (
+		filter model.EventFilter
+		err    error
+	)
+	if filter.From, err = parseOptionalTime(c.Query("from")); err != nil {
+		log.Error("Неверный формат from", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат from, ожидается RFC3339"})
+		return
+	}
+	if filter.To, err = parseOptionalTime(c.Query("to")); err != nil {
+		log.Error("Неверный формат to", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат to, ожидается RFC3339"})
+		return
+	}
+
+	filter.Field = c.Query("field")
+	filter.Pagination = queryPagination(c)
+
+	history, err := h.service.ListHistory(c.Request.Context(), filter)
+	if err != nil {
+		log.Error("Ошибка получения истории изменений", "error", err)
+		writeError(c, err, "Ошибка получения истории изменений")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, history)
+}
+
+// @Summary Журнал вызовов API
+// @Description Получение журнала изменяющих вызовов API для расследований, новые первыми. Требуется заголовок X-Admin-API-Key
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-API-Key header string true "Ключ административного API"
+// @Param since query string false "Начало периода (RFC3339)"
+// @Param actor query string false "Автор вызова"
+// @Param songId query int false "ID песни"
+// @Param page query int false "Номер страницы" default(1)
+// @Param page_size query int false "Размер страницы (не больше 200)" default(50)
+// @Success 200 {array} model.APICall
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/audit [get]
+func (h *AdminHandler) ListAPICalls(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+
+	filter := model.APICallFilter{Actor: c.Query("actor")}
+
+	if value := c.Query("songId"); value != "" {
+		songID, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			log.Error("Неверный формат songId", "error", err)
+			WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат songId"})
+			return
+		}
+		filter.SongID = &songID
+	}
+
+	var err error
+	if filter.Since, err = parseOptionalTime(c.Query("since")); err != nil {
+		log.Error("Неверный формат since", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат since, ожидается RFC3339"})
+		return
+	}
+
+	filter.Pagination = queryPagination(c)
+
+	calls, err := h.service.ListAPICalls(c.Request.Context(), filter)
+	if err != nil {
+		log.Error("Ошибка получения журнала вызовов API", "error", err)
+		writeError(c, err, "Ошибка получения журнала вызовов API")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, calls)
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"net/http"
+	"song-library/internal/model"
+	"song-library/pkg/logger"
+	"strconv"
+	"time"
+)
+
+// apiAuditWriteTimeout время на запись одного вызова в журнал
+const apiAuditWriteTimeout = 5 * time.Second
+
+var apiAuditWriteFailures = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "api_audit_write_failures_total",
+	Help: "Количество неудачных записей в журнал изменяющих вызовов API",
+})
+
+// APIAuditRecorder сохраняет записи журнала вызовов API
+type APIAuditRecorder interface {
+	RecordAPICall(ctx context.Context, call *model.APICall) error
+}
+
+// BackgroundRunner выполняет задачи в ограниченном пуле обработчиков
+type BackgroundRunner interface {
+	TrySubmit(ctx context.Context, fn func(ctx context.Context)) error
+}
+
+// APIAudit возвращает middleware, записывающий каждый изменяющий вызов API после выполнения обработчика.
+// Запись выполняется в пуле фоновых задач: ошибка записи или переполнение пула логируется
+// и учитывается в метриках, но не влияет на ответ.
+func APIAudit(recorder APIAuditRecorder, runner BackgroundRunner, logger *logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		started := time.Now()
+		c.Next()
+
+		ctx := c.Request.Context()
+		call := &model.APICall{
+			Actor:      auditActor(ctx),
+			Method:     c.Request.Method,
+			Route:      c.FullPath(),
+			Status:     c.Writer.Status(),
+			LatencyMs:  time.Since(started).Milliseconds(),
+			OccurredAt: started,
+		}
+		if requestID, ok := ctx.Value("requestID").(string); ok {
+			call.RequestID = requestID
+		}
+		if call.Route == "" {
+			call.Route = c.Request.URL.Path
+		}
+		if id, err := strconv.ParseInt(c.Param("id"), 10, 64); err == nil {
+			call.SongID = &id
+		}
+
+		err := runner.TrySubmit(context.WithoutCancel(ctx), func(ctx context.Context) {
+			writeCtx, cancel := context.WithTimeout(ctx, apiAuditWriteTimeout)
+			defer cancel()
+
+			if err := recorder.RecordAPICall(writeCtx, call); err != nil {
+				apiAuditWriteFailures.Inc()
+				logger.WithContext(ctx).Error("Ошибка записи вызова API в журнал", "error", err, "route", call.Route)
+			}
+		})
+		if err != nil {
+			apiAuditWriteFailures.Inc()
+			logger.WithContext(ctx).Error("Вызов API не записан в журнал", "error", err, "route", call.Route)
+		}
+	}
+}
+
+// auditActor возвращает автора запроса, сохраненного в контексте маршрутизатором
+func auditActor(ctx context.Context) string {
+	if actor, ok := ctx.Value("actor").(string); ok && actor != "" {
+		return actor
+	}
+	return "anonymous"
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// BodyLimit возвращает middleware, ограничивающий размер тела запроса maxBytes байтами.
+// Запрос с заявленным Content-Length больше ограничения отклоняется с 413 до чтения тела.
+// Тело без Content-Length (chunked) читается через счетчик байт: при превышении ограничения
+// чтение прерывается ошибкой, которую обработчики возвращают как 413 через writeBindError.
+func BodyLimit(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > maxBytes {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: bodyTooLargeMessage(maxBytes)})
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+		c.Next()
+	}
+}
+
+// writeBindError отвечает на ошибку чтения тела запроса: 413 при превышении ограничения размера, иначе 400
+func writeBindError(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		WriteJSON(c, http.StatusRequestEntityTooLarge, ErrorResponse{Error: bodyTooLargeMessage(tooLarge.Limit)})
+		return
+	}
+	WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат данных"})
+}
+
+// bodyTooLargeMessage возвращает сообщение о превышении размера тела запроса
+func bodyTooLargeMessage(maxBytes int64) string {
+	return fmt.Sprintf("Тело запроса превышает %d байт", maxBytes)
+}
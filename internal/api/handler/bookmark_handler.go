@@ -0,0 +1,425 @@
+package handler
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"song-library/internal/model"
+	"song-library/pkg/logger"
+	"strconv"
+	"strings"
+)
+
+const (
+	// bookmarkCookieName имя cookie с закладками
+	bookmarkCookieName = "bookmarks"
+	// maxBookmarks максимальное количество закладок в одной cookie
+	maxBookmarks = 50
+	// bookmarkCookieMaxAge время жизни cookie с закладками в секундах
+	bookmarkCookieMaxAge = 365 * 24 * 60 * 60
+	// clientCookieName имя cookie с идентификатором клиента для закладок куплетов
+	clientCookieName = "bookmark_client"
+)
+
+var errInvalidBookmarkSignature = errors.New("неверная подпись закладок")
+
+// BookmarkService интерфейс сервиса, необходимый для работы с закладками
+type BookmarkService interface {
+	GetSongByID(ctx context.Context, id int64) (*model.Song, error)
+	GetSongsByIDs(ctx context.Context, ids []int64) ([]*model.Song, error)
+	AddVerseBookmark(ctx context.Context, clientID string, songID int64, position int, note string) error
+	RemoveVerseBookmark(ctx context.Context, clientID string, songID int64, position int) error
+	ListVerseBookmarks(ctx context.Context, clientID string) ([]model.VerseBookmark, error)
+}
+
+// BookmarkHandler обработчик закладок анонимных пользователей.
+// Закладки песен хранятся в подписанной HMAC-SHA256 cookie и не требуют аутентификации.
+// Закладки куплетов хранятся в базе и привязаны к идентификатору клиента из такой же подписанной cookie.
+type BookmarkHandler struct {
+	service BookmarkService
+	secret  []byte
+	logger  *logger.Logger
+}
+
+// NewBookmarkHandler создает новый обработчик закладок
+func NewBookmarkHandler(service BookmarkService, secret string, logger *logger.Logger) *BookmarkHandler {
+	return &BookmarkHandler{
+		service: service,
+		secret:  []byte(secret),
+		logger:  logger,
+	}
+}
+
+// @Summary Получение закладок
+// @Description Получение песен, добавленных в закладки текущей сессии
+// @Tags bookmarks
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.Song
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/bookmarks [get]
+func (h *BookmarkHandler) GetBookmarks(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+
+	ids, err := h.readBookmarks(c)
+	if err != nil {
+		log.Error("Ошибка чтения закладок", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверные данные закладок"})
+		return
+	}
+
+	songs, err := h.service.GetSongsByIDs(c.Request.Context(), ids)
+	if err != nil {
+		log.Error("Ошибка получения песен из закладок", "error", err)
+		WriteJSON(c, http.StatusInternalServerError, ErrorResponse{Error: "Ошибка получения закладок"})
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, songs)
+}
+
+// @Summary Добавление песни в закладки
+// @Description Добавление песни в закладки текущей сессии (не более 50)
+// @Tags bookmarks
+// @Accept json
+// @Produce json
+// @Param id path string true "ID песни или публичный UUID (publicId)"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} NotFoundResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/{id}/bookmark [post]
+func (h *BookmarkHandler) AddBookmark(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Неверный формат ID", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат ID"})
+		return
+	}
+
+	ids, err := h.readBookmarks(c)
+	if err != nil {
+		log.Error("Ошибка чтения закладок", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверные данные закладок"})
+		return
+	}
+
+	for _, bookmarked := range ids {
+		if bookmarked == id {
+			WriteJSON(c, http.StatusOK, SuccessResponse{Message: "Песня уже в закладках"})
+			return
+		}
+	}
+
+	if len(ids) >= maxBookmarks {
+		log.Info("Превышен лимит закладок", "count", len(ids))
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Превышено максимальное количество закладок"})
+		return
+	}
+
+	if _, err = h.service.GetSongByID(c.Request.Context(), id); err != nil {
+		log.Error("Ошибка получения песни", "error", err, "id", id)
+		writeError(c, err, "Ошибка получения песни")
+		return
+	}
+
+	if err = h.writeBookmarks(c, append(ids, id)); err != nil {
+		log.Error("Ошибка сохранения закладок", "error", err)
+		WriteJSON(c, http.StatusInternalServerError, ErrorResponse{Error: "Ошибка сохранения закладок"})
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, SuccessResponse{Message: "Песня добавлена в закладки"})
+}
+
+// @Summary Удаление песни из закладок
+// @Description Удаление песни из закладок текущей сессии
+// @Tags bookmarks
+// @Accept json
+// @Produce json
+// @Param id path string true "ID песни или публичный UUID (publicId)"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/{id}/bookmark [delete]
+func (h *BookmarkHandler) RemoveBookmark(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Неверный формат ID", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат ID"})
+		return
+	}
+
+	ids, err := h.readBookmarks(c)
+	if err != nil {
+		log.Error("Ошибка чтения закладок", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверные данные закладок"})
+		return
+	}
+
+	remaining := make([]int64, 0, len(ids))
+	for _, bookmarked := range ids {
+		if bookmarked != id {
+			remaining = append(remaining, bookmarked)
+		}
+	}
+
+	if err = h.writeBookmarks(c, remaining); err != nil {
+		log.Error("Ошибка сохранения закладок", "error", err)
+		WriteJSON(c, http.StatusInternalServerError, ErrorResponse{Error: "Ошибка сохранения закладок"})
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, SuccessResponse{Message: "Песня удалена из закладок"})
+}
+
+// @Summary Добавление куплета в закладки
+// @Description Добавление куплета песни в закладки клиента с необязательной заметкой. Повторное добавление заменяет заметку
+// @Tags bookmarks
+// @Accept json
+// @Produce json
+// @Param id path string true "ID песни или публичный UUID (publicId)"
+// @Param n path int true "Номер куплета (с 1)"
+// @Param input body model.VerseBookmarkInput false "Заметка"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} NotFoundResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 422 {object} ValidationErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/{id}/verses/{n}/bookmark [post]
+func (h *BookmarkHandler) AddVerseBookmark(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+	id, position, ok := parseVerseParams(c, h.logger)
+	if !ok {
+		return
+	}
+
+	var input model.VerseBookmarkInput
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			log.Error("Ошибка декодирования JSON", "error", err)
+			writeBindError(c, err)
+			return
+		}
+	}
+
+	clientID, err := h.clientID(c, true)
+	if err != nil {
+		log.Error("Ошибка чтения идентификатора клиента", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверные данные закладок"})
+		return
+	}
+
+	if err = h.service.AddVerseBookmark(c.Request.Context(), clientID, id, position, input.Note); err != nil {
+		log.Error("Ошибка добавления закладки куплета", "error", err, "id", id, "verse", position)
+		writeError(c, err, "Ошибка добавления закладки куплета")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, SuccessResponse{Message: "Куплет добавлен в закладки"})
+}
+
+// @Summary Удаление куплета из закладок
+// @Description Удаление закладки куплета песни
+// @Tags bookmarks
+// @Accept json
+// @Produce json
+// @Param id path string true "ID песни или публичный UUID (publicId)"
+// @Param n path int true "Номер куплета (с 1)"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/{id}/verses/{n}/bookmark [delete]
+func (h *BookmarkHandler) RemoveVerseBookmark(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+	id, position, ok := parseVerseParams(c, h.logger)
+	if !ok {
+		return
+	}
+
+	clientID, err := h.clientID(c, false)
+	if err != nil {
+		log.Error("Ошибка чтения идентификатора клиента", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверные данные закладок"})
+		return
+	}
+	if clientID == "" {
+		WriteJSON(c, http.StatusNotFound, ErrorResponse{Error: "Закладка не найдена"})
+		return
+	}
+
+	if err = h.service.RemoveVerseBookmark(c.Request.Context(), clientID, id, position); err != nil {
+		log.Error("Ошибка удаления закладки куплета", "error", err, "id", id, "verse", position)
+		writeError(c, err, "Ошибка удаления закладки куплета")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, SuccessResponse{Message: "Куплет удален из закладок"})
+}
+
+// @Summary Закладки куплетов
+// @Description Закладки куплетов клиента с текущим текстом куплета, новые первыми.
+// @Description Если после изменения текста куплета с сохраненным номером больше нет, закладка возвращается с stale=true и пустым verse
+// @Tags bookmarks
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.VerseBookmark
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bookmarks [get]
+func (h *BookmarkHandler) ListVerseBookmarks(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+
+	clientID, err := h.clientID(c, false)
+	if err != nil {
+		log.Error("Ошибка чтения идентификатора клиента", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверные данные закладок"})
+		return
+	}
+	if clientID == "" {
+		WriteJSON(c, http.StatusOK, []model.VerseBookmark{})
+		return
+	}
+
+	bookmarks, err := h.service.ListVerseBookmarks(c.Request.Context(), clientID)
+	if err != nil {
+		log.Error("Ошибка получения закладок куплетов", "error", err)
+		writeError(c, err, "Ошибка получения закладок куплетов")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, bookmarks)
+}
+
+// parseVerseParams читает ID песни и номер куплета из пути; при ошибке отвечает 400 и возвращает false
+func parseVerseParams(c *gin.Context, logger *logger.Logger) (int64, int, bool) {
+	log := logger.WithContext(c.Request.Context())
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Неверный формат ID", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат ID"})
+		return 0, 0, false
+	}
+	position, err := strconv.Atoi(c.Param("n"))
+	if err != nil {
+		log.Error("Неверный формат номера куплета", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат номера куплета"})
+		return 0, 0, false
+	}
+	return id, position, true
+}
+
+// clientID читает идентификатор клиента из подписанной cookie.
+// Если cookie нет, при create выдается новый идентификатор, иначе возвращается пустая строка.
+func (h *BookmarkHandler) clientID(c *gin.Context, create bool) (string, error) {
+	value, err := c.Cookie(clientCookieName)
+	if err == nil && value != "" {
+		payload, err := h.verify(value)
+		if err != nil {
+			return "", err
+		}
+		return string(payload), nil
+	}
+	if !create {
+		return "", nil
+	}
+
+	clientID := uuid.New().String()
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     clientCookieName,
+		Value:    h.sign([]byte(clientID)),
+		Path:     "/",
+		MaxAge:   bookmarkCookieMaxAge,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+	})
+	return clientID, nil
+}
+
+// readBookmarks читает и проверяет подпись cookie с закладками.
+// Отсутствие cookie означает пустой список закладок.
+func (h *BookmarkHandler) readBookmarks(c *gin.Context) ([]int64, error) {
+	value, err := c.Cookie(bookmarkCookieName)
+	if err != nil || value == "" {
+		return []int64{}, nil
+	}
+
+	payload, err := h.verify(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	if err = json.Unmarshal(payload, &ids); err != nil {
+		return nil, err
+	}
+	if len(ids) > maxBookmarks {
+		return nil, errors.New("превышено максимальное количество закладок")
+	}
+
+	return ids, nil
+}
+
+// writeBookmarks подписывает и сохраняет список закладок в cookie
+func (h *BookmarkHandler) writeBookmarks(c *gin.Context, ids []int64) error {
+	payload, err := json.Marshal(ids)
+	if err != nil {
+		return err
+	}
+
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     bookmarkCookieName,
+		Value:    h.sign(payload),
+		Path:     "/",
+		MaxAge:   bookmarkCookieMaxAge,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+	})
+	return nil
+}
+
+// sign возвращает значение cookie в формате base64(payload).base64(hmac)
+func (h *BookmarkHandler) sign(payload []byte) string {
+	mac := hmac.New(sha256.New, h.secret)
+	mac.Write(payload)
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verify проверяет подпись значения cookie и возвращает исходные данные
+func (h *BookmarkHandler) verify(value string) ([]byte, error) {
+	encodedPayload, encodedSignature, ok := strings.Cut(value, ".")
+	if !ok {
+		return nil, errInvalidBookmarkSignature
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, errInvalidBookmarkSignature
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return nil, errInvalidBookmarkSignature
+	}
+
+	mac := hmac.New(sha256.New, h.secret)
+	mac.Write(payload)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, errInvalidBookmarkSignature
+	}
+
+	return payload, nil
+}
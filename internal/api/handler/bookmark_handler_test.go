@@ -0,0 +1,175 @@
+package handler_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"song-library/internal/api/handler"
+	"song-library/internal/model"
+	"song-library/internal/testutil"
+	"song-library/pkg/logger"
+	"strings"
+	"testing"
+)
+
+// testBookmarkSecret секрет подписи cookie закладок в тестах
+const testBookmarkSecret = "bookmark-secret"
+
+// bookmarkService подменяет сервис закладок: GetSongsByIDs запоминает запрошенные идентификаторы
+type bookmarkService struct {
+	handler.BookmarkService
+
+	requested []int64
+}
+
+func (s *bookmarkService) GetSongByID(_ context.Context, id int64) (*model.Song, error) {
+	return &model.Song{ID: id, Group: "Muse", Song: "Hysteria"}, nil
+}
+
+func (s *bookmarkService) GetSongsByIDs(_ context.Context, ids []int64) ([]*model.Song, error) {
+	s.requested = ids
+	songs := make([]*model.Song, 0, len(ids))
+	for _, id := range ids {
+		songs = append(songs, &model.Song{ID: id, Group: "Muse", Song: "Hysteria"})
+	}
+	return songs, nil
+}
+
+// newBookmarkRouter создает маршрутизатор с обработчиками закладок песен
+func newBookmarkRouter(service handler.BookmarkService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log := &logger.Logger{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	bookmarks := handler.NewBookmarkHandler(service, testBookmarkSecret, log)
+
+	router := gin.New()
+	router.GET("/songs/bookmarks", bookmarks.GetBookmarks)
+	router.POST("/songs/:id/bookmark", bookmarks.AddBookmark)
+	return router
+}
+
+// signBookmarks формирует значение cookie закладок base64(payload).base64(hmac), подписанное secret
+func signBookmarks(t *testing.T, secret string, ids []int64) string {
+	t.Helper()
+
+	payload, err := json.Marshal(ids)
+	if err != nil {
+		t.Fatalf("ошибка кодирования закладок: %v", err)
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// bookmarkIDs возвращает идентификаторы 1..count
+func bookmarkIDs(count int) []int64 {
+	ids := make([]int64, count)
+	for i := range ids {
+		ids[i] = int64(i + 1)
+	}
+	return ids
+}
+
+// getBookmarks запрашивает закладки с cookie value; пустое значение — запрос без cookie
+func getBookmarks(router http.Handler, value string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/songs/bookmarks", nil)
+	if value != "" {
+		req.AddCookie(&http.Cookie{Name: "bookmarks", Value: value})
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestBookmarkRoundTrip(t *testing.T) {
+	service := &bookmarkService{}
+	router := newBookmarkRouter(service)
+
+	recorder := testutil.DoRequest(t, router, http.MethodPost, "/songs/42/bookmark", nil)
+	testutil.AssertStatus(t, recorder, http.StatusOK)
+
+	cookies := recorder.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != "bookmarks" {
+		t.Fatalf("cookies = %v, want одна cookie bookmarks", cookies)
+	}
+	cookie := cookies[0]
+	if !cookie.HttpOnly || !cookie.Secure || cookie.SameSite != http.SameSiteStrictMode {
+		t.Errorf("флаги cookie HttpOnly=%v Secure=%v SameSite=%v, want true, true, Strict",
+			cookie.HttpOnly, cookie.Secure, cookie.SameSite)
+	}
+	if want := signBookmarks(t, testBookmarkSecret, []int64{42}); cookie.Value != want {
+		t.Errorf("cookie = %q, want %q", cookie.Value, want)
+	}
+
+	recorder = getBookmarks(router, cookie.Value)
+	testutil.AssertStatus(t, recorder, http.StatusOK)
+	if want := []int64{42}; !reflect.DeepEqual(service.requested, want) {
+		t.Errorf("GetSongsByIDs(%v), want %v", service.requested, want)
+	}
+}
+
+func TestBookmarkSignatureVerification(t *testing.T) {
+	valid := signBookmarks(t, testBookmarkSecret, []int64{1, 42})
+	payload, signature, _ := strings.Cut(valid, ".")
+	tamperedPayload := base64.RawURLEncoding.EncodeToString([]byte("[1,42,100]")) + "." + signature
+	signatureBytes, _ := base64.RawURLEncoding.DecodeString(signature)
+	signatureBytes[0] ^= 0xff
+	tamperedSignature := payload + "." + base64.RawURLEncoding.EncodeToString(signatureBytes)
+
+	tests := []struct {
+		name       string
+		cookie     string
+		wantStatus int
+		wantIDs    []int64
+	}{
+		{"верная подпись", valid, http.StatusOK, []int64{1, 42}},
+		{"без cookie", "", http.StatusOK, []int64{}},
+		{"измененные данные", tamperedPayload, http.StatusBadRequest, nil},
+		{"измененная подпись", tamperedSignature, http.StatusBadRequest, nil},
+		{"без разделителя", payload + signature, http.StatusBadRequest, nil},
+		{"подпись не в base64", payload + ".!!!", http.StatusBadRequest, nil},
+		{"другой секрет", signBookmarks(t, "other-secret", []int64{1, 42}), http.StatusBadRequest, nil},
+		{"больше 50 закладок", signBookmarks(t, testBookmarkSecret, bookmarkIDs(51)), http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &bookmarkService{}
+
+			recorder := getBookmarks(newBookmarkRouter(service), tt.cookie)
+			testutil.AssertStatus(t, recorder, tt.wantStatus)
+			if tt.wantStatus != http.StatusOK {
+				testutil.AssertJSONField(t, recorder, "error", "Неверные данные закладок")
+				if service.requested != nil {
+					t.Errorf("сервис вызван с %v при неверной cookie", service.requested)
+				}
+				return
+			}
+			if !reflect.DeepEqual(service.requested, tt.wantIDs) {
+				t.Errorf("GetSongsByIDs(%v), want %v", service.requested, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestAddBookmarkLimit(t *testing.T) {
+	router := newBookmarkRouter(&bookmarkService{})
+
+	req := httptest.NewRequest(http.MethodPost, "/songs/51/bookmark", nil)
+	req.AddCookie(&http.Cookie{Name: "bookmarks", Value: signBookmarks(t, testBookmarkSecret, bookmarkIDs(50))})
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	testutil.AssertStatus(t, recorder, http.StatusBadRequest)
+	testutil.AssertJSONField(t, recorder, "error", "Превышено максимальное количество закладок")
+	if cookies := recorder.Result().Cookies(); len(cookies) != 0 {
+		t.Errorf("cookies = %v, want без изменения закладок", cookies)
+	}
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+// CacheControl возвращает middleware, выставляющий заголовок Cache-Control.
+// Для GET и HEAD запросов ответ разрешено кэшировать на стороне клиента на maxAge,
+// для изменяющих запросов кэширование запрещено.
+func CacheControl(maxAge time.Duration) gin.HandlerFunc {
+	readValue := fmt.Sprintf("private, max-age=%d", int(maxAge.Seconds()))
+	if maxAge <= 0 {
+		readValue = "private, no-cache"
+	}
+
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead:
+			c.Header("Cache-Control", readValue)
+		default:
+			c.Header("Cache-Control", "no-store")
+		}
+		c.Next()
+	}
+}
+
+// notModified выставляет заголовок Last-Modified и проверяет If-Modified-Since.
+// Возвращает true и отвечает 304, если ресурс не изменялся с указанного клиентом момента.
+// HTTP-даты имеют точность до секунды, поэтому время изменения усекается до целых секунд.
+func notModified(c *gin.Context, lastModified time.Time) bool {
+	lastModified = lastModified.UTC().Truncate(time.Second)
+	c.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+
+	since, err := http.ParseTime(c.GetHeader("If-Modified-Since"))
+	if err != nil || lastModified.After(since) {
+		return false
+	}
+
+	c.Status(http.StatusNotModified)
+	return true
+}
@@ -0,0 +1,84 @@
+package handler_test
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"song-library/internal/api/handler"
+	"song-library/internal/model"
+	"song-library/internal/testutil"
+	"testing"
+	"time"
+)
+
+func TestCacheControl(t *testing.T) {
+	tests := []struct {
+		name   string
+		maxAge time.Duration
+		method string
+		want   string
+	}{
+		{"GET кэшируется на maxAge", time.Minute, http.MethodGet, "private, max-age=60"},
+		{"HEAD кэшируется на maxAge", 90 * time.Second, http.MethodHead, "private, max-age=90"},
+		{"нулевой maxAge требует перепроверки", 0, http.MethodGet, "private, no-cache"},
+		{"POST не кэшируется", time.Minute, http.MethodPost, "no-store"},
+		{"DELETE не кэшируется", time.Minute, http.MethodDelete, "no-store"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(handler.CacheControl(tt.maxAge))
+			router.Handle(tt.method, "/songs", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			recorder := testutil.DoRequest(t, router, tt.method, "/songs", nil)
+
+			if got := recorder.Header().Get("Cache-Control"); got != tt.want {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSongByIDNotModified(t *testing.T) {
+	// Время изменения с долями секунды: HTTP-дата усекается до секунды, и повторный запрос с ней получает 304
+	updatedAt := time.Date(2024, 1, 15, 10, 30, 0, 500_000_000, time.UTC)
+	service := &mockSongService{getSongByID: func(context.Context, int64) (*model.Song, error) {
+		song := testSong()
+		song.UpdatedAt = updatedAt
+		return song, nil
+	}}
+
+	tests := []struct {
+		name            string
+		ifModifiedSince string
+		wantStatus      int
+	}{
+		{"без условия", "", http.StatusOK},
+		{"не изменялась с момента Last-Modified", "Mon, 15 Jan 2024 10:30:00 GMT", http.StatusNotModified},
+		{"не изменялась с более позднего момента", "Tue, 16 Jan 2024 00:00:00 GMT", http.StatusNotModified},
+		{"изменилась после указанного момента", "Mon, 15 Jan 2024 10:29:59 GMT", http.StatusOK},
+		{"неверная дата", "15.01.2024", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/songs/1", nil)
+			if tt.ifModifiedSince != "" {
+				req.Header.Set("If-Modified-Since", tt.ifModifiedSince)
+			}
+			recorder := httptest.NewRecorder()
+			newTestRouter(service).ServeHTTP(recorder, req)
+
+			testutil.AssertStatus(t, recorder, tt.wantStatus)
+			if got := recorder.Header().Get("Last-Modified"); got != "Mon, 15 Jan 2024 10:30:00 GMT" {
+				t.Errorf("Last-Modified = %q, want %q", got, "Mon, 15 Jan 2024 10:30:00 GMT")
+			}
+			if tt.wantStatus == http.StatusNotModified && recorder.Body.Len() != 0 {
+				t.Errorf("тело ответа 304 = %q, want пустое", recorder.Body.String())
+			}
+		})
+	}
+}
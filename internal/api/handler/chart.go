@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"song-library/internal/model"
+	"strconv"
+)
+
+// @Summary Обновление позиции песни в чарте
+// @Description Установка текущей позиции песни в чарте, от 1 до 200. Позиция с текущей датой (UTC) добавляется в историю чарта песни;
+// @Description хранятся последние 52 снимка, более старые удаляются
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param id path string true "ID песни или публичный UUID (publicId)"
+// @Param input body model.ChartInput true "Позиция в чарте"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} NotFoundResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/{id}/chart [patch]
+func (h *SongHandler) UpdateSongChart(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Неверный формат ID", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат ID"})
+		return
+	}
+
+	var input model.ChartInput
+	if err = c.ShouldBindJSON(&input); err != nil {
+		log.Error("Ошибка декодирования JSON", "error", err)
+		writeBindError(c, err)
+		return
+	}
+
+	if err = h.service.UpdateSongChart(c.Request.Context(), id, input.Position); err != nil {
+		log.Error("Ошибка обновления позиции песни в чарте", "error", err, "id", id)
+		writeError(c, err, "Ошибка обновления позиции песни в чарте")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, SuccessResponse{Message: "Позиция песни в чарте успешно обновлена"})
+}
+
+// @Summary Лидеры чарта
+// @Description Песни, занимавшие первую позицию в чарте на дату date, по истории позиций. Текст песен не возвращается
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param date query string false "Дата в формате ГГГГ-ММ-ДД (по умолчанию текущая дата UTC)" example(2024-01-15)
+// @Success 200 {array} model.Song
+// @Failure 400 {object} ErrorResponse "Неверный формат даты"
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/chart-toppers [get]
+func (h *SongHandler) GetChartToppers(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+
+	songs, err := h.service.GetChartToppers(c.Request.Context(), c.Query("date"))
+	if err != nil {
+		log.Error("Ошибка получения лидеров чарта", "error", err)
+		writeError(c, err, "Ошибка получения лидеров чарта")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, songs)
+}
@@ -0,0 +1,55 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"song-library/internal/model"
+	"testing"
+)
+
+func TestUpdateSongChart(t *testing.T) {
+	runHandlerCases(t, []handlerCase{
+		{"позиция обновлена", http.MethodPatch, "/api/v1/songs/1/chart", `{"position":5}`,
+			&mockSongService{updateSongChart: func(_ context.Context, id int64, position *int) error {
+				if id != 1 || position == nil || *position != 5 {
+					return unexpectedArgs("%d %v", id, position)
+				}
+				return nil
+			}},
+			http.StatusOK, `{"message":"Позиция песни в чарте успешно обновлена"}`},
+		{"позиция вне диапазона", http.MethodPatch, "/api/v1/songs/1/chart", `{"position":201}`,
+			&mockSongService{updateSongChart: func(context.Context, int64, *int) error {
+				return model.NewValidationError("position должен быть от 1 до 200")
+			}},
+			http.StatusBadRequest, `{"error":"position должен быть от 1 до 200"}`},
+		{"некорректный JSON", http.MethodPatch, "/api/v1/songs/1/chart", `{"position":"first"}`, &mockSongService{},
+			http.StatusBadRequest, `{"error":"Неверный формат данных"}`},
+		{"песня не найдена", http.MethodPatch, "/api/v1/songs/2/chart", `{"position":5}`,
+			&mockSongService{updateSongChart: func(_ context.Context, id int64, _ *int) error { return model.NewNotFoundError(id) }},
+			http.StatusNotFound, `{"error":"Песня не найдена","id":2}`},
+		{"внутренняя ошибка", http.MethodPatch, "/api/v1/songs/1/chart", `{"position":5}`,
+			&mockSongService{updateSongChart: func(context.Context, int64, *int) error { return errDatabase }},
+			http.StatusInternalServerError, `{"error":"Ошибка обновления позиции песни в чарте"}`},
+	})
+}
+
+func TestGetChartToppers(t *testing.T) {
+	runHandlerCases(t, []handlerCase{
+		{"лидеры чарта на дату", http.MethodGet, "/api/v1/songs/chart-toppers?date=2024-01-15", "",
+			&mockSongService{getChartToppers: func(_ context.Context, date string) ([]*model.Song, error) {
+				if date != "2024-01-15" {
+					return nil, unexpectedArgs("%q", date)
+				}
+				return []*model.Song{}, nil
+			}},
+			http.StatusOK, `[]`},
+		{"неверная дата", http.MethodGet, "/api/v1/songs/chart-toppers?date=15.01.2024", "",
+			&mockSongService{getChartToppers: func(context.Context, string) ([]*model.Song, error) {
+				return nil, model.NewValidationError("date должна быть в формате YYYY-MM-DD")
+			}},
+			http.StatusBadRequest, `{"error":"date должна быть в формате YYYY-MM-DD"}`},
+		{"внутренняя ошибка", http.MethodGet, "/api/v1/songs/chart-toppers", "",
+			&mockSongService{getChartToppers: func(context.Context, string) ([]*model.Song, error) { return nil, errDatabase }},
+			http.StatusInternalServerError, `{"error":"Ошибка получения лидеров чарта"}`},
+	})
+}
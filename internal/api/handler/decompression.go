@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"compress/gzip"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"song-library/pkg/logger"
+	"strings"
+)
+
+// DecompressionMiddleware возвращает middleware, распаковывающий тело запроса с Content-Encoding: gzip,
+// чтобы обработчики читали обычный JSON. Распакованное тело ограничивается maxBytes байтами (0 — без ограничения):
+// при превышении чтение прерывается ошибкой, которую обработчики возвращают как 413 через writeBindError.
+// Запрос с поврежденным заголовком gzip отклоняется с 400.
+func DecompressionMiddleware(maxBytes int64, logger *logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !strings.EqualFold(strings.TrimSpace(c.GetHeader("Content-Encoding")), "gzip") {
+			c.Next()
+			return
+		}
+
+		compressed := &countingReader{r: c.Request.Body}
+		gz, err := gzip.NewReader(compressed)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Error: "Неверный формат сжатого тела запроса"})
+			return
+		}
+		defer gz.Close()
+
+		decompressed := &countingReader{r: gz}
+		body := io.ReadCloser(io.NopCloser(decompressed))
+		if maxBytes > 0 {
+			body = http.MaxBytesReader(c.Writer, body, maxBytes)
+		}
+		c.Request.Body = body
+		c.Request.ContentLength = -1
+		c.Request.Header.Del("Content-Encoding")
+		c.Request.Header.Del("Content-Length")
+
+		c.Next()
+
+		ratio := 0.0
+		if compressed.n > 0 {
+			ratio = float64(decompressed.n) / float64(compressed.n)
+		}
+		logger.WithContext(c.Request.Context()).Debug("Тело запроса распаковано",
+			"compressed_bytes", compressed.n,
+			"decompressed_bytes", decompressed.n,
+			"ratio", ratio)
+	}
+}
+
+// countingReader считает прочитанные байты
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+// Read читает из исходного reader и увеличивает счетчик
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
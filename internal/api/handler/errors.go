@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"song-library/internal/model"
+)
+
+// writeError отвечает клиенту статусом, соответствующим ошибке сервиса.
+// Ошибки, не относящиеся к известным категориям, возвращаются как 500 с сообщением fallback.
+func writeError(c *gin.Context, err error, fallback string) {
+	var (
+		validationErr *model.ValidationError
+		limitErr      *model.LimitError
+		encodingErr   *model.EncodingError
+		orderErr      *model.VerseOrderError
+		conflictErr   *model.RestoreConflictError
+		notFoundErr   *model.NotFoundError
+		renameErr     *model.GroupRenameConflictError
+	)
+
+	switch {
+	case errors.As(err, &validationErr):
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: validationErr.Message})
+	case errors.As(err, &limitErr):
+		WriteJSON(c, http.StatusUnprocessableEntity, newValidationErrorResponse(limitErr.Field, limitErr.Error()))
+	case errors.As(err, &encodingErr):
+		WriteJSON(c, http.StatusUnprocessableEntity, newValidationErrorResponse(encodingErr.Field, encodingErr.Error()))
+	case errors.As(err, &orderErr):
+		WriteJSON(c, http.StatusUnprocessableEntity, newValidationErrorResponse("order", orderErr.Error()))
+	case errors.As(err, &conflictErr):
+		WriteJSON(c, http.StatusConflict, ConflictResponse{Error: "Песня уже существует", ExistingID: conflictErr.ConflictingID, SongID: conflictErr.SongID})
+	case errors.As(err, &renameErr):
+		WriteJSON(c, http.StatusConflict, GroupRenameConflictResponse{Error: "В новой группе уже есть песни с такими названиями", Conflicts: renameErr.Conflicts})
+	case errors.As(err, &notFoundErr):
+		WriteJSON(c, http.StatusNotFound, NotFoundResponse{Error: "Песня не найдена", ID: notFoundErr.ID})
+	case errors.Is(err, model.ErrSongNotFound):
+		WriteJSON(c, http.StatusNotFound, NotFoundResponse{Error: "Песня не найдена"})
+	case errors.Is(err, model.ErrVerseNotFound):
+		WriteJSON(c, http.StatusNotFound, ErrorResponse{Error: "Куплет не найден"})
+	case errors.Is(err, model.ErrBookmarkNotFound):
+		WriteJSON(c, http.StatusNotFound, ErrorResponse{Error: "Закладка не найдена"})
+	case errors.Is(err, model.ErrGroupInfoNotFound):
+		WriteJSON(c, http.StatusNotFound, ErrorResponse{Error: "Сведения о группе не найдены"})
+	case errors.Is(err, model.ErrGroupNotFound):
+		WriteJSON(c, http.StatusNotFound, ErrorResponse{Error: "Группа не найдена"})
+	case errors.Is(err, model.ErrSongAlreadyExists):
+		WriteJSON(c, http.StatusConflict, ConflictResponse{Error: "Песня уже существует"})
+	case errors.Is(err, model.ErrEnrichedFieldProtected):
+		WriteJSON(c, http.StatusConflict, ConflictResponse{Error: "Поле заполнено поставщиком данных, для изменения укажите force=true"})
+	case errors.Is(err, model.ErrUpstreamTimeout):
+		WriteJSON(c, http.StatusGatewayTimeout, ErrorResponse{Error: "Внешний API не ответил вовремя"})
+	case errors.Is(err, context.DeadlineExceeded):
+		WriteJSON(c, http.StatusGatewayTimeout, ErrorResponse{Error: "Время обработки запроса истекло"})
+	case errors.Is(err, model.ErrServiceBusy):
+		WriteJSON(c, http.StatusServiceUnavailable, ErrorResponse{Error: "Сервис перегружен, повторите запрос позже"})
+	case errors.Is(err, model.ErrUpstreamFailed):
+		WriteJSON(c, http.StatusBadGateway, ErrorResponse{Error: "Ошибка получения данных из внешнего API"})
+	default:
+		WriteJSON(c, http.StatusInternalServerError, ErrorResponse{Error: fallback})
+	}
+}
+
+// newValidationErrorResponse формирует ответ 422 с ошибкой одного поля
+func newValidationErrorResponse(field, message string) ValidationErrorResponse {
+	return ValidationErrorResponse{
+		Error:  message,
+		Errors: []FieldError{{Field: field, Message: message}},
+	}
+}
+
+// FieldError ошибка значения поля
+type FieldError struct {
+	Field   string `json:"field" example:"text"`
+	Message string `json:"message" example:"поле text превышает максимальную длину 102400 байт"`
+}
+
+// ValidationErrorResponse ответ 422 с ошибками отдельных полей
+type ValidationErrorResponse struct {
+	Error  string       `json:"error" example:"поле text превышает максимальную длину 102400 байт"`
+	Errors []FieldError `json:"errors"`
+}
+
+// NotFoundResponse ответ 404 с идентификатором ненайденной песни
+type NotFoundResponse struct {
+	Error string `json:"error" example:"Песня не найдена"`
+	ID    int64  `json:"id,omitempty" example:"42"`
+}
+
+// ConflictResponse ответ 409; existing_id указывает песню, с которой возник конфликт, если она известна.
+// При восстановлении песни song_id содержит идентификатор восстанавливаемой песни
+type ConflictResponse struct {
+	Error      string `json:"error" example:"Песня уже существует"`
+	ExistingID int64  `json:"existing_id,omitempty" example:"42"`
+	SongID     int64  `json:"song_id,omitempty" example:"7"`
+}
+
+// GroupRenameConflictResponse ответ 409 на переименование группы со списком конфликтующих песен
+type GroupRenameConflictResponse struct {
+	Error     string                 `json:"error" example:"В новой группе уже есть песни с такими названиями"`
+	Conflicts []model.RenameConflict `json:"conflicts"`
+}
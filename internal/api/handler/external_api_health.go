@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"song-library/pkg/logger"
+	"time"
+)
+
+// ExternalAPIPinger проверка доступности внешнего API, например *service.ExternalAPIClient
+type ExternalAPIPinger interface {
+	Ping(ctx context.Context) error
+	BaseURL() string
+}
+
+// ExternalAPIHealthHandler отвечает на проверку доступности внешнего API для агентов мониторинга
+type ExternalAPIHealthHandler struct {
+	api     ExternalAPIPinger
+	timeout time.Duration
+	logger  *logger.Logger
+}
+
+// NewExternalAPIHealthHandler создает обработчик проверки внешнего API; проверка ограничена timeout
+func NewExternalAPIHealthHandler(api ExternalAPIPinger, timeout time.Duration, logger *logger.Logger) *ExternalAPIHealthHandler {
+	return &ExternalAPIHealthHandler{api: api, timeout: timeout, logger: logger}
+}
+
+// ExternalAPIHealthResponse ответ 200 проверки доступности внешнего API
+type ExternalAPIHealthResponse struct {
+	Status    string `json:"status" example:"ok"`
+	URL       string `json:"url" example:"http://localhost:8081"`
+	LatencyMs int64  `json:"latency_ms" example:"42"`
+}
+
+// ExternalAPIUnavailableResponse ответ 503 проверки доступности внешнего API
+type ExternalAPIUnavailableResponse struct {
+	Status string `json:"status" example:"unavailable"`
+	Error  string `json:"error" example:"внешний API вернул код состояния 502"`
+}
+
+// GetExternalAPIHealth отвечает 200 с временем ответа, если внешний API доступен, иначе 503 с текстом ошибки
+func (h *ExternalAPIHealthHandler) GetExternalAPIHealth(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.timeout)
+	defer cancel()
+
+	started := time.Now()
+	if err := h.api.Ping(ctx); err != nil {
+		log.Warn("Внешний API недоступен", "error", err)
+		WriteJSON(c, http.StatusServiceUnavailable, ExternalAPIUnavailableResponse{Status: "unavailable", Error: err.Error()})
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, ExternalAPIHealthResponse{
+		Status:    "ok",
+		URL:       h.api.BaseURL(),
+		LatencyMs: time.Since(started).Milliseconds(),
+	})
+}
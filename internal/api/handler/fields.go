@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"song-library/internal/model"
+	"strings"
+)
+
+// songListFields поля песни, которые можно перечислить в параметре fields списка песен
+var songListFields = []string{
+	"id", "group", "song", "releaseDate", "text", "link", "createdAt", "updatedAt", "verseCount", "textLength",
+	"duration", "bpm", "relevance", "snippet", "provenance", "contentHash", "featuredArtists",
+	"enrichmentStatus", "enrichedAt", "chartPosition", "chartHistory",
+}
+
+// validateSongFields проверяет, что все поля из fields доступны в списке песен
+func validateSongFields(fields []string) error {
+	for _, field := range fields {
+		if !slices.Contains(songListFields, field) {
+			return fmt.Errorf("неизвестное поле в fields: %s (допустимы: %s)", field, strings.Join(songListFields, ", "))
+		}
+	}
+	return nil
+}
+
+// selectSongFields возвращает песни, в которых оставлены только поля fields.
+// Поля, отсутствующие в JSON песни (например, relevance без быстрого поиска), пропускаются.
+func selectSongFields(songs []*model.Song, fields []string) ([]map[string]json.RawMessage, error) {
+	items := make([]map[string]json.RawMessage, 0, len(songs))
+	for _, song := range songs {
+		data, err := json.Marshal(song)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка сериализации песни: %w", err)
+		}
+		var all map[string]json.RawMessage
+		if err = json.Unmarshal(data, &all); err != nil {
+			return nil, fmt.Errorf("ошибка сериализации песни: %w", err)
+		}
+
+		item := make(map[string]json.RawMessage, len(fields))
+		for _, field := range fields {
+			if value, ok := all[field]; ok {
+				item[field] = value
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
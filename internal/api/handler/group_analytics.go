@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// defaultTopSongsLimit количество песен в рейтинге песен группы по умолчанию
+const defaultTopSongsLimit = 5
+
+// @Summary Рейтинг песен группы
+// @Description Песни группы с наибольшим количеством просмотров (views), последние измененные (updated_at) или добавленные (created_at)
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Param name path string true "Название группы"
+// @Param metric query string false "Метрика рейтинга" Enums(views, updated_at, created_at) default(views)
+// @Param limit query int false "Количество песен (от 1 до 50)" default(5)
+// @Success 200 {array} model.Song
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /groups/{name}/top-songs [get]
+func (h *SongHandler) GetGroupTopSongs(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+
+	limit := defaultTopSongsLimit
+	if value := c.Query("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil {
+			log.Error("Неверный формат limit", "error", err)
+			WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат limit"})
+			return
+		}
+	}
+
+	songs, err := h.service.GetTopSongsByGroup(c.Request.Context(), c.Param("name"), c.Query("metric"), limit)
+	if err != nil {
+		log.Error("Ошибка получения рейтинга песен группы", "error", err)
+		writeError(c, err, "Ошибка получения рейтинга песен группы")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, songs)
+}
+
+// @Summary Статистика группы
+// @Description Количество песен группы, сумма их просмотров и среднее количество куплетов. Если у группы нет песен, возвращается 404
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Param name path string true "Название группы"
+// @Success 200 {object} model.GroupStats
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /groups/{name}/stats [get]
+func (h *SongHandler) GetGroupStats(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+
+	stats, err := h.service.GetGroupStats(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		log.Error("Ошибка получения статистики группы", "error", err)
+		writeError(c, err, "Ошибка получения статистики группы")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, stats)
+}
@@ -0,0 +1,60 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"song-library/internal/model"
+	"testing"
+)
+
+func TestGetGroupTopSongs(t *testing.T) {
+	runHandlerCases(t, []handlerCase{
+		{"рейтинг по просмотрам", http.MethodGet, "/api/v1/groups/Muse/top-songs?metric=views&limit=3", "",
+			&mockSongService{getTopSongsByGroup: func(_ context.Context, group, metric string, limit int) ([]*model.Song, error) {
+				if group != "Muse" || metric != "views" || limit != 3 {
+					return nil, unexpectedArgs("%q %q %d", group, metric, limit)
+				}
+				return []*model.Song{testSong()}, nil
+			}},
+			http.StatusOK, "[" + testSongJSON + "]"},
+		{"количество по умолчанию", http.MethodGet, "/api/v1/groups/Muse/top-songs", "",
+			&mockSongService{getTopSongsByGroup: func(_ context.Context, _, _ string, limit int) ([]*model.Song, error) {
+				if limit != 5 {
+					return nil, unexpectedArgs("limit %d", limit)
+				}
+				return []*model.Song{}, nil
+			}},
+			http.StatusOK, `[]`},
+		{"неверный limit", http.MethodGet, "/api/v1/groups/Muse/top-songs?limit=all", "", &mockSongService{},
+			http.StatusBadRequest, `{"error":"Неверный формат limit"}`},
+		{"неизвестная метрика", http.MethodGet, "/api/v1/groups/Muse/top-songs?metric=likes", "",
+			&mockSongService{getTopSongsByGroup: func(context.Context, string, string, int) ([]*model.Song, error) {
+				return nil, model.NewValidationError("metric должен быть views или verses")
+			}},
+			http.StatusBadRequest, `{"error":"metric должен быть views или verses"}`},
+		{"группа не найдена", http.MethodGet, "/api/v1/groups/Nobody/top-songs", "",
+			&mockSongService{getTopSongsByGroup: func(context.Context, string, string, int) ([]*model.Song, error) {
+				return nil, model.ErrGroupNotFound
+			}},
+			http.StatusNotFound, `{"error":"Группа не найдена"}`},
+	})
+}
+
+func TestGetGroupStats(t *testing.T) {
+	runHandlerCases(t, []handlerCase{
+		{"статистика группы", http.MethodGet, "/api/v1/groups/Muse/stats", "",
+			&mockSongService{getGroupStats: func(_ context.Context, group string) (*model.GroupStats, error) {
+				if group != "Muse" {
+					return nil, unexpectedArgs("%q", group)
+				}
+				return &model.GroupStats{SongCount: 12, TotalViews: 340, AvgVerseCount: 3.5}, nil
+			}},
+			http.StatusOK, `{"song_count":12,"total_views":340,"avg_verse_count":3.5}`},
+		{"группа не найдена", http.MethodGet, "/api/v1/groups/Nobody/stats", "",
+			&mockSongService{getGroupStats: func(context.Context, string) (*model.GroupStats, error) { return nil, model.ErrGroupNotFound }},
+			http.StatusNotFound, `{"error":"Группа не найдена"}`},
+		{"внутренняя ошибка", http.MethodGet, "/api/v1/groups/Muse/stats", "",
+			&mockSongService{getGroupStats: func(context.Context, string) (*model.GroupStats, error) { return nil, errDatabase }},
+			http.StatusInternalServerError, `{"error":"Ошибка получения статистики группы"}`},
+	})
+}
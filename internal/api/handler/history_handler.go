@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"song-library/internal/model"
+	"song-library/pkg/logger"
+	"strconv"
+)
+
+// HistoryService интерфейс сервиса истории изменений песен
+type HistoryService interface {
+	GetSongHistory(ctx context.Context, songID int64, query model.HistoryQuery) (*model.HistoryListResponse, error)
+}
+
+// HistoryHandler обработчик запросов истории изменений песен
+type HistoryHandler struct {
+	service HistoryService
+	logger  *logger.Logger
+}
+
+// NewHistoryHandler создает новый обработчик истории изменений
+func NewHistoryHandler(service HistoryService, logger *logger.Logger) *HistoryHandler {
+	return &HistoryHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// @Summary История изменений песни
+// @Description История изменений песни из журнала событий, новые записи первыми, с общим количеством записей.
+// @Description operation ограничивает историю одним типом изменений: updated, duration_updated, deleted и т. д.
+// @Description field оставляет только изменения одного поля песни (снимки до и после изменения различаются в этом поле),
+// @Description since — изменения начиная с момента времени. Записи с одинаковым временем упорядочены по убыванию id.
+// @Description Изменения текста возвращаются унифицированным диффом в textDiff (не больше 500 строк),
+// @Description остальных полей — парами old/new в changes; full=true возвращает вместо них снимки песни целиком.
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param id path string true "ID песни или публичный UUID (publicId)"
+// @Param operation query string false "Тип изменения (тип события без префикса song.)"
+// @Param field query string false "Поле песни: text, group, song, releaseDate или link"
+// @Param since query string false "Начало периода (RFC3339)"
+// @Param full query bool false "Вернуть снимки песни целиком вместо диффа" default(false)
+// @Param page query int false "Номер страницы" default(1)
+// @Param page_size query int false "Размер страницы (не больше 200)" default(50)
+// @Success 200 {object} model.HistoryListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/{id}/history [get]
+func (h *HistoryHandler) GetSongHistory(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Неверный формат ID", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат ID"})
+		return
+	}
+
+	query := model.HistoryQuery{
+		Operation:  c.Query("operation"),
+		Field:      c.Query("field"),
+		Full:       c.Query("full") == "true",
+		Pagination: queryPagination(c),
+	}
+	if query.Since, err = parseOptionalTime(c.Query("since")); err != nil {
+		log.Error("Неверный формат since", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат since, ожидается RFC3339"})
+		return
+	}
+
+	history, err := h.service.GetSongHistory(c.Request.Context(), id, query)
+	if err != nil {
+		log.Error("Ошибка получения истории песни", "error", err, "id", id)
+		writeError(c, err, "Ошибка получения истории песни")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, history)
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"song-library/internal/model"
+	"strings"
+	"time"
+)
+
+const (
+	// libraryExportFormatZip формат экспорта библиотеки архивом с текстами песен
+	libraryExportFormatZip = "zip"
+	// libraryManifestFile имя описи внутри архива библиотеки
+	libraryManifestFile = "manifest.json"
+	// maxArchiveNameLength максимальная длина имени файла или каталога в архиве в символах
+	maxArchiveNameLength = 100
+)
+
+// @Summary Экспорт библиотеки
+// @Description ZIP-архив всей библиотеки: текст каждой песни в файле Группа/Песня.txt и опись manifest.json с метаданными.
+// @Description Архив передается потоком по мере чтения песен; при ошибке после начала передачи архив обрывается.
+// @Tags songs
+// @Produce application/zip
+// @Param format query string false "Формат экспорта" Enums(zip) default(zip)
+// @Success 200 {file} file "ZIP-архив"
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/export [get]
+func (h *SongHandler) ExportLibrary(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+
+	if format := c.DefaultQuery("format", libraryExportFormatZip); format != libraryExportFormatZip {
+		log.Info("Неподдерживаемый формат экспорта библиотеки", "format", format)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неподдерживаемый формат экспорта: " + format})
+		return
+	}
+
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", `attachment; filename="songs.zip"`)
+	c.Status(http.StatusOK)
+
+	archive := zip.NewWriter(c.Writer)
+	names := archiveNames{}
+	manifest := model.LibraryManifest{ExportedAt: time.Now().UTC(), Songs: []model.LibraryManifestEntry{}}
+
+	err := h.service.ExportLibrary(c.Request.Context(), func(song *model.Song) error {
+		name := names.unique(archiveName(song.Group) + "/" + archiveName(song.Song))
+		w, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err = w.Write([]byte(song.Text)); err != nil {
+			return err
+		}
+
+		manifest.Songs = append(manifest.Songs, model.LibraryManifestEntry{
+			File:        name,
+			ID:          song.ID,
+			PublicID:    song.PublicID,
+			Group:       song.Group,
+			Song:        song.Song,
+			ReleaseDate: song.ReleaseDate,
+			Link:        song.Link,
+			Duration:    song.Duration,
+			BPM:         song.BPM,
+		})
+		return nil
+	})
+	if err == nil {
+		manifest.Count = len(manifest.Songs)
+		err = writeManifest(archive, manifest)
+	}
+	if err != nil {
+		log.Error("Ошибка экспорта библиотеки", "error", err)
+		// Если клиенту еще ничего не отправлено, вместо оборванного архива возвращается обычная ошибка
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Disposition")
+			writeError(c, err, "Ошибка экспорта библиотеки")
+		}
+		return
+	}
+
+	if err = archive.Close(); err != nil {
+		log.Error("Ошибка завершения архива библиотеки", "error", err)
+	}
+}
+
+// writeManifest добавляет опись в архив библиотеки
+func writeManifest(archive *zip.Writer, manifest model.LibraryManifest) error {
+	w, err := archive.Create(libraryManifestFile)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(manifest)
+}
+
+// archiveName превращает название группы или песни в безопасное имя файла:
+// разделители путей, зарезервированные в Windows и управляющие символы заменяются на "_",
+// пробелы и точки по краям удаляются, длина ограничивается maxArchiveNameLength
+func archiveName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	name = strings.Trim(name, " .")
+	if runes := []rune(name); len(runes) > maxArchiveNameLength {
+		name = strings.TrimRight(string(runes[:maxArchiveNameLength]), " .")
+	}
+	if name == "" {
+		return "_"
+	}
+	return name
+}
+
+// archiveNames занятые пути архива без учета регистра
+type archiveNames map[string]struct{}
+
+// unique возвращает свободный путь base.txt, добавляя к нему " (2)", " (3)" и т.д. при совпадении
+func (n archiveNames) unique(base string) string {
+	name := base + ".txt"
+	for i := 2; ; i++ {
+		key := strings.ToLower(name)
+		if _, taken := n[key]; !taken {
+			n[key] = struct{}{}
+			return name
+		}
+		name = fmt.Sprintf("%s (%d).txt", base, i)
+	}
+}
@@ -0,0 +1,81 @@
+package handler_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"song-library/internal/model"
+	"song-library/internal/testutil"
+	"testing"
+)
+
+func TestExportLibrary(t *testing.T) {
+	service := &mockSongService{exportLibrary: func(_ context.Context, fn func(song *model.Song) error) error {
+		for _, song := range []*model.Song{
+			{ID: 1, Group: "Muse", Song: "Hysteria", Text: "It's bugging me"},
+			{ID: 2, Group: "AC/DC", Song: "T.N.T.", Text: "Oi"},
+			{ID: 3, Group: "muse", Song: "hysteria", Text: "Grating me"},
+		} {
+			if err := fn(song); err != nil {
+				return err
+			}
+		}
+		return nil
+	}}
+
+	recorder := testutil.DoRequest(t, newTestRouter(service), http.MethodGet, "/api/v1/songs/export", nil)
+
+	testutil.AssertStatus(t, recorder, http.StatusOK)
+	if got := recorder.Header().Get("Content-Type"); got != "application/zip" {
+		t.Errorf("Content-Type = %q, want application/zip", got)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(recorder.Body.Bytes()), int64(recorder.Body.Len()))
+	if err != nil {
+		t.Fatalf("ошибка чтения архива: %v", err)
+	}
+
+	files := make(map[string]string, len(archive.File))
+	names := make([]string, 0, len(archive.File))
+	for _, file := range archive.File {
+		r, err := file.Open()
+		if err != nil {
+			t.Fatalf("ошибка открытия %s: %v", file.Name, err)
+		}
+		data, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatalf("ошибка чтения %s: %v", file.Name, err)
+		}
+		files[file.Name] = string(data)
+		names = append(names, file.Name)
+	}
+
+	wantNames := []string{"Muse/Hysteria.txt", "AC_DC/T.N.T.txt", "muse/hysteria (2).txt", "manifest.json"}
+	if !slices.Equal(names, wantNames) {
+		t.Errorf("файлы архива = %v, want %v", names, wantNames)
+	}
+	if got := files["muse/hysteria (2).txt"]; got != "Grating me" {
+		t.Errorf("текст песни 3 = %q, want %q", got, "Grating me")
+	}
+	var manifest model.LibraryManifest
+	if err = json.Unmarshal([]byte(files["manifest.json"]), &manifest); err != nil {
+		t.Fatalf("ошибка разбора описи: %v", err)
+	}
+	if manifest.Count != 3 || len(manifest.Songs) != 3 || manifest.Songs[1].File != "AC_DC/T.N.T.txt" || manifest.Songs[1].ID != 2 {
+		t.Errorf("опись = %+v, want 3 песни, вторая AC_DC/T.N.T.txt", manifest)
+	}
+}
+
+func TestExportLibraryErrors(t *testing.T) {
+	runHandlerCases(t, []handlerCase{
+		{"неподдерживаемый формат", http.MethodGet, "/api/v1/songs/export?format=tar", "", &mockSongService{},
+			http.StatusBadRequest, `{"error":"Неподдерживаемый формат экспорта: tar"}`},
+		{"ошибка до начала архива", http.MethodGet, "/api/v1/songs/export", "",
+			&mockSongService{exportLibrary: func(context.Context, func(*model.Song) error) error { return errDatabase }},
+			http.StatusInternalServerError, `{"error":"Ошибка экспорта библиотеки"}`},
+	})
+}
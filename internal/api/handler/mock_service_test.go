@@ -0,0 +1,210 @@
+package handler_test
+
+import (
+	"context"
+	"song-library/internal/api/handler"
+	"song-library/internal/model"
+	"time"
+)
+
+// mockSongService подменяет сервис песен в тестах обработчиков: каждый метод вызывает одноименное поле-функцию.
+// Методы без заданной функции паникуют через встроенный nil-интерфейс, поэтому лишний вызов сервиса виден сразу.
+type mockSongService struct {
+	handler.SongService
+
+	createSong            func(ctx context.Context, input model.SongInput) (model.SongRef, error)
+	getSongs              func(ctx context.Context, filter model.SongFilter) ([]*model.Song, error)
+	getSongByID           func(ctx context.Context, id int64) (*model.Song, error)
+	getSongsForGroup      func(ctx context.Context, group, sortBy string, page, pageSize int) ([]*model.Song, int64, error)
+	resolvePublicID       func(ctx context.Context, publicID string) (int64, error)
+	updateSong            func(ctx context.Context, song *model.Song, opts model.UpdateOptions) (*model.Song, bool, error)
+	updateSongDuration    func(ctx context.Context, id int64, duration *int, opts model.UpdateOptions) error
+	updateSongChart       func(ctx context.Context, id int64, position *int) error
+	deleteSong            func(ctx context.Context, id int64) error
+	getDeletedSongs       func(ctx context.Context, page, pageSize int) ([]*model.Song, error)
+	restoreSong           func(ctx context.Context, id int64) (*model.Song, error)
+	mergeSongs            func(ctx context.Context, sourceID, targetID int64, strategy string) error
+	getSongVerses         func(ctx context.Context, id int64, pagination model.VersesPagination) ([]string, int, error)
+	reorderVerses         func(ctx context.Context, id int64, order []int) (int, error)
+	updateVerse           func(ctx context.Context, id int64, index int, text string) (int, bool, error)
+	deleteVerse           func(ctx context.Context, id int64, index int) (int, error)
+	getWordFrequency      func(ctx context.Context, id int64, top int) ([]model.WordCount, error)
+	getFormattedText      func(ctx context.Context, id int64, width, indent int) (string, error)
+	findDuplicates        func(ctx context.Context, threshold float64, page, pageSize int) ([]model.DuplicateGroup, error)
+	getTotalDuration      func(ctx context.Context, group string) (*model.TotalDuration, error)
+	updateFeaturedArtists func(ctx context.Context, id int64, artists []string, opts model.UpdateOptions) error
+	bulkCreateSongs       func(ctx context.Context, inputs []model.SongImport) (int64, error)
+	exportSong            func(ctx context.Context, id int64) (*model.SongDocument, error)
+	importSong            func(ctx context.Context, document model.SongDocument) (model.SongRef, error)
+	exportLibrary         func(ctx context.Context, fn func(song *model.Song) error) error
+	exportGroupSongbook   func(ctx context.Context, group string, fn func(song *model.Song) error) error
+	getGroupInfo          func(ctx context.Context, name string) (*model.GroupInfo, error)
+	putGroupInfo          func(ctx context.Context, name string, input model.GroupInfoInput) (*model.GroupInfo, bool, error)
+	getTopSongsByGroup    func(ctx context.Context, group, metric string, limit int) ([]*model.Song, error)
+	getGroupStats         func(ctx context.Context, group string) (*model.GroupStats, error)
+	renameGroup           func(ctx context.Context, name, newName, mergeMode string) (*model.GroupRenameResult, error)
+	updateSongBPM         func(ctx context.Context, id int64, bpm *int, opts model.UpdateOptions) error
+	updateSongCopyright   func(ctx context.Context, id int64, copyright *string, opts model.UpdateOptions) error
+	getSongsByCopyright   func(ctx context.Context, holder string, page, pageSize int) ([]*model.Song, error)
+	getChartToppers       func(ctx context.Context, date string) ([]*model.Song, error)
+	getTempoDistribution  func(ctx context.Context) ([]model.TempoBucket, error)
+	getAccessLog          func(ctx context.Context, id int64, from, to *time.Time) (*model.AccessLog, error)
+	getMostAccessedSongs  func(ctx context.Context, period string) ([]*model.MostAccessedSong, error)
+	getTrendingSongs      func(ctx context.Context, period string, limit int) ([]*model.Song, error)
+}
+
+func (m *mockSongService) CreateSong(ctx context.Context, input model.SongInput) (model.SongRef, error) {
+	return m.createSong(ctx, input)
+}
+
+func (m *mockSongService) GetSongs(ctx context.Context, filter model.SongFilter) ([]*model.Song, error) {
+	return m.getSongs(ctx, filter)
+}
+
+func (m *mockSongService) GetSongByID(ctx context.Context, id int64) (*model.Song, error) {
+	return m.getSongByID(ctx, id)
+}
+
+func (m *mockSongService) GetSongsForGroup(ctx context.Context, group, sortBy string, page, pageSize int) ([]*model.Song, int64, error) {
+	return m.getSongsForGroup(ctx, group, sortBy, page, pageSize)
+}
+
+func (m *mockSongService) ResolvePublicID(ctx context.Context, publicID string) (int64, error) {
+	return m.resolvePublicID(ctx, publicID)
+}
+
+func (m *mockSongService) UpdateSong(ctx context.Context, song *model.Song, opts model.UpdateOptions) (*model.Song, bool, error) {
+	return m.updateSong(ctx, song, opts)
+}
+
+func (m *mockSongService) UpdateSongDuration(ctx context.Context, id int64, duration *int, opts model.UpdateOptions) error {
+	return m.updateSongDuration(ctx, id, duration, opts)
+}
+
+func (m *mockSongService) UpdateSongChart(ctx context.Context, id int64, position *int) error {
+	return m.updateSongChart(ctx, id, position)
+}
+
+func (m *mockSongService) DeleteSong(ctx context.Context, id int64) error {
+	return m.deleteSong(ctx, id)
+}
+
+func (m *mockSongService) GetDeletedSongs(ctx context.Context, page, pageSize int) ([]*model.Song, error) {
+	return m.getDeletedSongs(ctx, page, pageSize)
+}
+
+func (m *mockSongService) RestoreSong(ctx context.Context, id int64) (*model.Song, error) {
+	return m.restoreSong(ctx, id)
+}
+
+func (m *mockSongService) MergeSongs(ctx context.Context, sourceID, targetID int64, strategy string) error {
+	return m.mergeSongs(ctx, sourceID, targetID, strategy)
+}
+
+func (m *mockSongService) GetSongVerses(ctx context.Context, id int64, pagination model.VersesPagination) ([]string, int, error) {
+	return m.getSongVerses(ctx, id, pagination)
+}
+
+func (m *mockSongService) ReorderVerses(ctx context.Context, id int64, order []int) (int, error) {
+	return m.reorderVerses(ctx, id, order)
+}
+
+func (m *mockSongService) UpdateVerse(ctx context.Context, id int64, index int, text string) (int, bool, error) {
+	return m.updateVerse(ctx, id, index, text)
+}
+
+func (m *mockSongService) DeleteVerse(ctx context.Context, id int64, index int) (int, error) {
+	return m.deleteVerse(ctx, id, index)
+}
+
+func (m *mockSongService) GetWordFrequency(ctx context.Context, id int64, top int) ([]model.WordCount, error) {
+	return m.getWordFrequency(ctx, id, top)
+}
+
+func (m *mockSongService) GetFormattedText(ctx context.Context, id int64, width, indent int) (string, error) {
+	return m.getFormattedText(ctx, id, width, indent)
+}
+
+func (m *mockSongService) FindDuplicates(ctx context.Context, threshold float64, page, pageSize int) ([]model.DuplicateGroup, error) {
+	return m.findDuplicates(ctx, threshold, page, pageSize)
+}
+
+func (m *mockSongService) GetTotalDuration(ctx context.Context, group string) (*model.TotalDuration, error) {
+	return m.getTotalDuration(ctx, group)
+}
+
+func (m *mockSongService) UpdateFeaturedArtists(ctx context.Context, id int64, artists []string, opts model.UpdateOptions) error {
+	return m.updateFeaturedArtists(ctx, id, artists, opts)
+}
+
+func (m *mockSongService) BulkCreateSongs(ctx context.Context, inputs []model.SongImport) (int64, error) {
+	return m.bulkCreateSongs(ctx, inputs)
+}
+
+func (m *mockSongService) ExportSong(ctx context.Context, id int64) (*model.SongDocument, error) {
+	return m.exportSong(ctx, id)
+}
+
+func (m *mockSongService) ImportSong(ctx context.Context, document model.SongDocument) (model.SongRef, error) {
+	return m.importSong(ctx, document)
+}
+
+func (m *mockSongService) ExportLibrary(ctx context.Context, fn func(song *model.Song) error) error {
+	return m.exportLibrary(ctx, fn)
+}
+
+func (m *mockSongService) ExportGroupSongbook(ctx context.Context, group string, fn func(song *model.Song) error) error {
+	return m.exportGroupSongbook(ctx, group, fn)
+}
+
+func (m *mockSongService) GetGroupInfo(ctx context.Context, name string) (*model.GroupInfo, error) {
+	return m.getGroupInfo(ctx, name)
+}
+
+func (m *mockSongService) PutGroupInfo(ctx context.Context, name string, input model.GroupInfoInput) (*model.GroupInfo, bool, error) {
+	return m.putGroupInfo(ctx, name, input)
+}
+
+func (m *mockSongService) GetTopSongsByGroup(ctx context.Context, group, metric string, limit int) ([]*model.Song, error) {
+	return m.getTopSongsByGroup(ctx, group, metric, limit)
+}
+
+func (m *mockSongService) GetGroupStats(ctx context.Context, group string) (*model.GroupStats, error) {
+	return m.getGroupStats(ctx, group)
+}
+
+func (m *mockSongService) RenameGroup(ctx context.Context, name, newName, mergeMode string) (*model.GroupRenameResult, error) {
+	return m.renameGroup(ctx, name, newName, mergeMode)
+}
+
+func (m *mockSongService) UpdateSongBPM(ctx context.Context, id int64, bpm *int, opts model.UpdateOptions) error {
+	return m.updateSongBPM(ctx, id, bpm, opts)
+}
+
+func (m *mockSongService) UpdateSongCopyright(ctx context.Context, id int64, copyright *string, opts model.UpdateOptions) error {
+	return m.updateSongCopyright(ctx, id, copyright, opts)
+}
+
+func (m *mockSongService) GetSongsByCopyright(ctx context.Context, holder string, page, pageSize int) ([]*model.Song, error) {
+	return m.getSongsByCopyright(ctx, holder, page, pageSize)
+}
+
+func (m *mockSongService) GetChartToppers(ctx context.Context, date string) ([]*model.Song, error) {
+	return m.getChartToppers(ctx, date)
+}
+
+func (m *mockSongService) GetTempoDistribution(ctx context.Context) ([]model.TempoBucket, error) {
+	return m.getTempoDistribution(ctx)
+}
+
+func (m *mockSongService) GetAccessLog(ctx context.Context, id int64, from, to *time.Time) (*model.AccessLog, error) {
+	return m.getAccessLog(ctx, id, from, to)
+}
+
+func (m *mockSongService) GetMostAccessedSongs(ctx context.Context, period string) ([]*model.MostAccessedSong, error) {
+	return m.getMostAccessedSongs(ctx, period)
+}
+
+func (m *mockSongService) GetTrendingSongs(ctx context.Context, period string, limit int) ([]*model.Song, error) {
+	return m.getTrendingSongs(ctx, period, limit)
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"song-library/internal/model"
+	"strconv"
+)
+
+const (
+	// paginationWarningHeader заголовок с предупреждением о пагинации
+	paginationWarningHeader = "X-Pagination-Warning"
+	// paginationWarningDeepOffset значение заголовка при глубоком смещении страницы
+	paginationWarningDeepOffset = "deep-offset"
+)
+
+// setPaginationWarning добавляет к ответу предупреждение, отмеченное сервисом при выборке
+func setPaginationWarning(c *gin.Context, notice *model.PaginationNotice) {
+	if notice.DeepOffset() {
+		c.Header(paginationWarningHeader, paginationWarningDeepOffset)
+	}
+}
+
+// queryPagination читает page и page_size из строки запроса. Нечисловые и неположительные значения считаются
+// не указанными, а значения по умолчанию и ограничения применяет сервис через pagination.NewPage
+func queryPagination(c *gin.Context) model.Pagination {
+	page, _ := strconv.Atoi(c.Query("page"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size"))
+	return model.Pagination{Page: max(page, 0), PageSize: max(pageSize, 0)}
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"database/sql"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// PoolStatsProvider источник статистики пула соединений, например *sqlx.DB
+type PoolStatsProvider interface {
+	Stats() sql.DBStats
+}
+
+// PoolHandler отдает текущую статистику пулов соединений для настройки их размеров
+type PoolHandler struct {
+	db      PoolStatsProvider
+	replica PoolStatsProvider
+}
+
+// NewPoolHandler создает обработчик статистики пулов соединений.
+// replica — пул соединений с репликой для чтения; nil, если реплика не настроена.
+func NewPoolHandler(db, replica PoolStatsProvider) *PoolHandler {
+	return &PoolHandler{db: db, replica: replica}
+}
+
+// DBPoolStats статистика пула соединений с базой данных; ключи соответствуют полям sql.DBStats
+type DBPoolStats struct {
+	MaxOpen           int   `json:"max_open" example:"25"`
+	Open              int   `json:"open" example:"18"`
+	InUse             int   `json:"in_use" example:"12"`
+	Idle              int   `json:"idle" example:"6"`
+	WaitCount         int64 `json:"wait_count" example:"2"`
+	WaitDurationMs    int64 `json:"wait_duration_ms" example:"45"`
+	MaxIdleClosed     int64 `json:"max_idle_closed" example:"0"`
+	MaxLifetimeClosed int64 `json:"max_lifetime_closed" example:"3"`
+}
+
+// DBPoolResponse статистика основного пула соединений и, если настроена реплика, пула реплики
+type DBPoolResponse struct {
+	DBPoolStats
+	Replica *DBPoolStats `json:"replica,omitempty"`
+}
+
+// newDBPoolStats переводит sql.DBStats в ответ API
+func newDBPoolStats(stats sql.DBStats) DBPoolStats {
+	return DBPoolStats{
+		MaxOpen:           stats.MaxOpenConnections,
+		Open:              stats.OpenConnections,
+		InUse:             stats.InUse,
+		Idle:              stats.Idle,
+		WaitCount:         stats.WaitCount,
+		WaitDurationMs:    stats.WaitDuration.Milliseconds(),
+		MaxIdleClosed:     stats.MaxIdleClosed,
+		MaxLifetimeClosed: stats.MaxLifetimeClosed,
+	}
+}
+
+// GetDBPool отвечает статистикой пулов соединений с базой данных, считанной в момент запроса
+func (h *PoolHandler) GetDBPool(c *gin.Context) {
+	response := DBPoolResponse{DBPoolStats: newDBPoolStats(h.db.Stats())}
+	if h.replica != nil {
+		replica := newDBPoolStats(h.replica.Stats())
+		response.Replica = &replica
+	}
+	WriteJSON(c, http.StatusOK, response)
+}
@@ -0,0 +1,26 @@
+package handler
+
+import "github.com/gin-gonic/gin"
+
+// prettyJSONKey ключ контекста gin с признаком форматированного JSON-ответа
+const prettyJSONKey = "prettyJSON"
+
+// PrettyPrint возвращает middleware, включающий форматированный JSON-ответ по параметру pretty=true.
+// Если enabled равен false (production), параметр молча игнорируется.
+func PrettyPrint(enabled bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if enabled && c.Query("pretty") == "true" {
+			c.Set(prettyJSONKey, true)
+		}
+		c.Next()
+	}
+}
+
+// WriteJSON отвечает объектом obj в JSON: с отступами, если PrettyPrint включил форматирование, иначе компактно
+func WriteJSON(c *gin.Context, status int, obj any) {
+	if c.GetBool(prettyJSONKey) {
+		c.IndentedJSON(status, obj)
+		return
+	}
+	c.JSON(status, obj)
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"strconv"
+)
+
+// ResolvePublicID middleware маршрутов песни: если параметр id содержит публичный UUID песни (publicId),
+// заменяет его числовым идентификатором, поэтому обработчики работают только с числовыми id.
+// Неизвестный UUID отклоняется с 404; значение, не похожее на UUID, передается обработчику без изменений.
+func (h *SongHandler) ResolvePublicID(c *gin.Context) {
+	value := c.Param("id")
+	publicID, err := uuid.Parse(value)
+	if value == "" || err != nil {
+		c.Next()
+		return
+	}
+
+	id, err := h.service.ResolvePublicID(c.Request.Context(), publicID.String())
+	if err != nil {
+		h.logger.WithContext(c.Request.Context()).Info("Ошибка получения песни по публичному идентификатору", "error", err)
+		writeError(c, err, "Ошибка получения песни")
+		c.Abort()
+		return
+	}
+
+	for i := range c.Params {
+		if c.Params[i].Key == "id" {
+			c.Params[i].Value = strconv.FormatInt(id, 10)
+		}
+	}
+	c.Next()
+}
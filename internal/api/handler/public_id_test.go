@@ -0,0 +1,41 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"song-library/internal/model"
+	"testing"
+)
+
+func TestResolvePublicID(t *testing.T) {
+	resolved := func(_ context.Context, publicID string) (int64, error) {
+		if publicID != testPublicID {
+			return 0, unexpectedArgs("%q", publicID)
+		}
+		return 1, nil
+	}
+	byID := func(_ context.Context, id int64) (*model.Song, error) {
+		if id != 1 {
+			return nil, unexpectedArgs("id %d", id)
+		}
+		return testSong(), nil
+	}
+
+	runHandlerCases(t, []handlerCase{
+		{"песня по публичному идентификатору", http.MethodGet, "/api/v1/songs/" + testPublicID, "",
+			&mockSongService{resolvePublicID: resolved, getSongByID: byID},
+			http.StatusOK, testSongJSON},
+		{"публичный идентификатор в верхнем регистре", http.MethodGet, "/api/v1/songs/0B6A4F1E-8C2D-4F7A-9E3B-5D1C2A7F9E40", "",
+			&mockSongService{resolvePublicID: resolved, getSongByID: byID},
+			http.StatusOK, testSongJSON},
+		{"числовой ID не разрешается через сервис", http.MethodGet, "/api/v1/songs/1", "",
+			&mockSongService{getSongByID: byID},
+			http.StatusOK, testSongJSON},
+		{"неизвестный публичный идентификатор", http.MethodGet, "/api/v1/songs/" + testPublicID, "",
+			&mockSongService{resolvePublicID: func(context.Context, string) (int64, error) { return 0, model.ErrSongNotFound }},
+			http.StatusNotFound, `{"error":"Песня не найдена"}`},
+		{"ошибка разрешения", http.MethodDelete, "/api/v1/songs/" + testPublicID, "",
+			&mockSongService{resolvePublicID: func(context.Context, string) (int64, error) { return 0, errDatabase }},
+			http.StatusInternalServerError, `{"error":"Ошибка получения песни"}`},
+	})
+}
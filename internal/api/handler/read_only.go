@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"slices"
+	"song-library/pkg/logger"
+	"song-library/pkg/readonly"
+	"strconv"
+)
+
+// readOnlyRetryAfter значение Retry-After в секундах для запросов, отклоненных в режиме только для чтения
+const readOnlyRetryAfter = 60
+
+// ReadOnly возвращает middleware, отклоняющий изменяющие запросы с 503, пока включен режим только для чтения.
+// GET и HEAD обслуживаются как обычно; маршруты exempt (полные шаблоны gin) не блокируются,
+// чтобы режим можно было выключить через API.
+func ReadOnly(mode *readonly.Switch, exempt ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !mode.Enabled() || !mutatingMethod(c.Request.Method) || slices.Contains(exempt, c.FullPath()) {
+			c.Next()
+			return
+		}
+
+		c.Header("Retry-After", strconv.Itoa(readOnlyRetryAfter))
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, ErrorResponse{Error: "Сервис работает в режиме только для чтения"})
+	}
+}
+
+// mutatingMethod сообщает, изменяет ли запрос с методом method данные
+func mutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// ReadOnlyHandler обработчик переключения режима только для чтения
+type ReadOnlyHandler struct {
+	mode   *readonly.Switch
+	logger *logger.Logger
+}
+
+// NewReadOnlyHandler создает обработчик переключения режима только для чтения
+func NewReadOnlyHandler(mode *readonly.Switch, logger *logger.Logger) *ReadOnlyHandler {
+	return &ReadOnlyHandler{
+		mode:   mode,
+		logger: logger,
+	}
+}
+
+// ReadOnlyInput запрос на переключение режима только для чтения
+type ReadOnlyInput struct {
+	Enabled *bool `json:"enabled" binding:"required" example:"true"`
+}
+
+// ReadOnlyResponse текущее состояние режима только для чтения
+type ReadOnlyResponse struct {
+	ReadOnly bool `json:"read_only" example:"true"`
+}
+
+// @Summary Режим только для чтения
+// @Description Включает или выключает режим только для чтения. В этом режиме POST, PUT, PATCH и DELETE отклоняются с 503
+// @Description и заголовком Retry-After, GET-запросы обслуживаются, а фоновые задачи, изменяющие данные, приостанавливаются.
+// @Description Требуется заголовок X-Admin-API-Key
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-API-Key header string true "Ключ административного API"
+// @Param input body ReadOnlyInput true "Новое состояние режима"
+// @Success 200 {object} ReadOnlyResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /admin/readonly [put]
+func (h *ReadOnlyHandler) SetReadOnly(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+
+	var input ReadOnlyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("Ошибка декодирования JSON", "error", err)
+		writeBindError(c, err)
+		return
+	}
+
+	if previous := h.mode.Set(*input.Enabled); previous != *input.Enabled {
+		log.Warn("Режим только для чтения переключен", "read_only", *input.Enabled)
+	}
+
+	WriteJSON(c, http.StatusOK, ReadOnlyResponse{ReadOnly: *input.Enabled})
+}
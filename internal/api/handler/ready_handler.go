@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"song-library/internal/selfcheck"
+	"song-library/pkg/readonly"
+	"sync/atomic"
+)
+
+// ReadyHandler отвечает на проверку готовности по отчету о проверках запуска
+type ReadyHandler struct {
+	report   atomic.Pointer[selfcheck.Report]
+	readOnly *readonly.Switch
+}
+
+// NewReadyHandler создает обработчик готовности; до вызова SetReport сервис не готов.
+// Режим только для чтения на готовность не влияет, но сообщается в ответе
+func NewReadyHandler(readOnly *readonly.Switch) *ReadyHandler {
+	return &ReadyHandler{readOnly: readOnly}
+}
+
+// SetReport сохраняет отчет о проверках запуска
+func (h *ReadyHandler) SetReport(report *selfcheck.Report) {
+	h.report.Store(report)
+}
+
+// ReadyResponse краткий ответ проверки готовности
+type ReadyResponse struct {
+	Status   string `json:"status" example:"ok"`
+	ReadOnly bool   `json:"read_only" example:"false"`
+}
+
+// verboseReadyResponse полный отчет о проверках запуска с режимом только для чтения
+type verboseReadyResponse struct {
+	*selfcheck.Report
+	ReadOnly bool `json:"read_only"`
+}
+
+// Ready отвечает 200, если проверки запуска завершены без фатальных ошибок, иначе 503.
+// С verbose=true возвращается полный отчет о проверках
+func (h *ReadyHandler) Ready(c *gin.Context) {
+	report := h.report.Load()
+	if report == nil {
+		WriteJSON(c, http.StatusServiceUnavailable, ReadyResponse{Status: "starting", ReadOnly: h.readOnly.Enabled()})
+		return
+	}
+
+	status := http.StatusOK
+	if report.Failed() {
+		status = http.StatusServiceUnavailable
+	}
+
+	if c.Query("verbose") == "true" {
+		WriteJSON(c, status, verboseReadyResponse{Report: report, ReadOnly: h.readOnly.Enabled()})
+		return
+	}
+	WriteJSON(c, status, ReadyResponse{Status: report.Status, ReadOnly: h.readOnly.Enabled()})
+}
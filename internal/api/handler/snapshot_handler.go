@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"song-library/internal/model"
+)
+
+// SnapshotStatusProvider источник состояния плановых снимков библиотеки
+type SnapshotStatusProvider interface {
+	Status() model.SnapshotStatus
+}
+
+// SnapshotHandler обработчик состояния плановых снимков библиотеки
+type SnapshotHandler struct {
+	provider SnapshotStatusProvider
+}
+
+// NewSnapshotHandler создает обработчик состояния снимков; provider равен nil, если снимки выключены
+func NewSnapshotHandler(provider SnapshotStatusProvider) *SnapshotHandler {
+	return &SnapshotHandler{provider: provider}
+}
+
+// @Summary Состояние плановых снимков
+// @Description Время, результат и файл последнего планового снимка библиотеки и количество пропущенных запусков
+// @Tags stats
+// @Produce json
+// @Success 200 {object} model.SnapshotStatus
+// @Router /stats/snapshots [get]
+func (h *SnapshotHandler) GetStatus(c *gin.Context) {
+	if h.provider == nil {
+		WriteJSON(c, http.StatusOK, model.SnapshotStatus{})
+		return
+	}
+	WriteJSON(c, http.StatusOK, h.provider.Status())
+}
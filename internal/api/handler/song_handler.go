@@ -3,20 +3,58 @@ package handler
 import (
 	"context"
 	"github.com/gin-gonic/gin"
+	"mime"
 	"net/http"
+	"slices"
+	"song-library/internal/formatter"
 	"song-library/internal/model"
 	"song-library/pkg/logger"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // SongService интерфейс сервиса песен
 type SongService interface {
-	CreateSong(ctx context.Context, input model.SongInput) (int64, error)
+	CreateSong(ctx context.Context, input model.SongInput) (model.SongRef, error)
+	BulkCreateSongs(ctx context.Context, inputs []model.SongImport) (int64, error)
+	ExportSong(ctx context.Context, id int64) (*model.SongDocument, error)
+	GetGroupInfo(ctx context.Context, name string) (*model.GroupInfo, error)
+	PutGroupInfo(ctx context.Context, name string, input model.GroupInfoInput) (*model.GroupInfo, bool, error)
+	GetSongsForGroup(ctx context.Context, group, sortBy string, page, pageSize int) ([]*model.Song, int64, error)
+	ExportLibrary(ctx context.Context, fn func(song *model.Song) error) error
+	ExportGroupSongbook(ctx context.Context, group string, fn func(song *model.Song) error) error
+	GetTopSongsByGroup(ctx context.Context, group, metric string, limit int) ([]*model.Song, error)
+	GetGroupStats(ctx context.Context, group string) (*model.GroupStats, error)
+	ImportSong(ctx context.Context, document model.SongDocument) (model.SongRef, error)
 	GetSongs(ctx context.Context, filter model.SongFilter) ([]*model.Song, error)
 	GetSongByID(ctx context.Context, id int64) (*model.Song, error)
-	UpdateSong(ctx context.Context, song *model.Song) error
+	ResolvePublicID(ctx context.Context, publicID string) (int64, error)
+	UpdateSong(ctx context.Context, song *model.Song, opts model.UpdateOptions) (*model.Song, bool, error)
+	UpdateSongDuration(ctx context.Context, id int64, duration *int, opts model.UpdateOptions) error
+	UpdateSongBPM(ctx context.Context, id int64, bpm *int, opts model.UpdateOptions) error
+	UpdateSongChart(ctx context.Context, id int64, position *int) error
+	GetChartToppers(ctx context.Context, date string) ([]*model.Song, error)
+	UpdateSongCopyright(ctx context.Context, id int64, copyright *string, opts model.UpdateOptions) error
+	UpdateFeaturedArtists(ctx context.Context, id int64, artists []string, opts model.UpdateOptions) error
+	GetSongsByCopyright(ctx context.Context, holder string, page, pageSize int) ([]*model.Song, error)
+	GetTempoDistribution(ctx context.Context) ([]model.TempoBucket, error)
+	GetTotalDuration(ctx context.Context, group string) (*model.TotalDuration, error)
 	DeleteSong(ctx context.Context, id int64) error
-	GetSongVerses(ctx context.Context, id int64, pagination model.VersesPagination) ([]string, error)
+	GetDeletedSongs(ctx context.Context, page, pageSize int) ([]*model.Song, error)
+	RestoreSong(ctx context.Context, id int64) (*model.Song, error)
+	MergeSongs(ctx context.Context, sourceID, targetID int64, strategy string) error
+	RenameGroup(ctx context.Context, name, newName, mergeMode string) (*model.GroupRenameResult, error)
+	GetSongVerses(ctx context.Context, id int64, pagination model.VersesPagination) ([]string, int, error)
+	ReorderVerses(ctx context.Context, id int64, order []int) (int, error)
+	UpdateVerse(ctx context.Context, id int64, index int, text string) (int, bool, error)
+	DeleteVerse(ctx context.Context, id int64, index int) (int, error)
+	GetAccessLog(ctx context.Context, id int64, from, to *time.Time) (*model.AccessLog, error)
+	GetMostAccessedSongs(ctx context.Context, period string) ([]*model.MostAccessedSong, error)
+	GetTrendingSongs(ctx context.Context, period string, limit int) ([]*model.Song, error)
+	GetWordFrequency(ctx context.Context, id int64, top int) ([]model.WordCount, error)
+	GetFormattedText(ctx context.Context, id int64, width, indent int) (string, error)
+	FindDuplicates(ctx context.Context, threshold float64, page, pageSize int) ([]model.DuplicateGroup, error)
 }
 
 // SongHandler обработчик HTTP запросов для работы с песнями
@@ -34,16 +72,43 @@ func NewSongHandler(service SongService, logger *logger.Logger) *SongHandler {
 }
 
 // @Summary Получение списка песен
-// @Description Получение списка песен с фильтрацией и пагинацией
+// @Description Получение списка песен с фильтрацией и пагинацией.
+// @Description При быстром поиске (q) каждая песня получает поле relevance: 1.0 — совпадение в названии песни,
+// @Description 0.8 — в названии группы, 0.6 — в тексте. Результаты сортируются по relevance, затем по убыванию id
+// @Description При поиске по q или text песни, в тексте которых есть совпадение, получают поле snippet: около 150 символов
+// @Description вокруг первого совпадения, экранированные для HTML, с совпадением в теге mark
+// @Description При includeText=false элементы имеют схему model.SongSummary (без поля text).
+// @Description Сведения об авторских правах (copyright) в списке не возвращаются, только в GET /songs/{id}
+// @Description С fields элементы содержат только перечисленные поля, например fields=id,contentHash для сверки
+// @Description локальной копии по хэшу содержимого (contentHash меняется при изменении группы, названия, даты выпуска, текста или ссылки)
 // @Tags songs
 // @Accept json
 // @Produce json
 // @Param group query string false "Фильтр по группе"
 // @Param song query string false "Фильтр по названию песни"
+// @Param groups query string false "Точные названия групп через запятую, не больше 50; не сочетается с group"
+// @Param songs query string false "Точные названия песен через запятую, не больше 50; не сочетается с song"
+// @Param text query string false "Подстрока текста песни без учета регистра, не короче 3 символов. Поиск использует триграммный индекс; на очень частых подстроках он приближается к полному просмотру таблицы"
+// @Param q query string false "Быстрый поиск по группе, названию и тексту песни (нельзя сочетать с group, song, groups, songs и text)"
+// @Param includeText query bool false "Включать ли текст песни в ответ" default(true)
+// @Param duration_min query int false "Минимальная длительность в секундах"
+// @Param duration_max query int false "Максимальная длительность в секундах"
+// @Param bpm_min query int false "Минимальный темп в ударах в минуту (20–300)"
+// @Param bpm_max query int false "Максимальный темп в ударах в минуту (20–300)"
+// @Param has_text query bool false "true — только песни с текстом, false — только без текста"
+// @Param has_link query bool false "true — только песни со ссылкой, false — только без ссылки"
+// @Param copyright_contains query string false "Подстрока сведений об авторских правах без учета регистра"
+// @Param featured_artist query string false "Имя приглашенного исполнителя, точное совпадение"
+// @Param enrichmentStatus query string false "Статус получения данных от поставщика: failed — для поиска песен без данных" Enums(ok, failed, pending)
+// @Param created_at_from query string false "Песни, добавленные не раньше (RFC3339; без смещения — время сервера)"
+// @Param created_at_to query string false "Песни, добавленные не позже (RFC3339; без смещения — время сервера)"
+// @Param missing_fields query string false "Незаполненные поля через запятую: text, link, releaseDate, duration, bpm. Песня должна не иметь всех перечисленных полей"
+// @Param fields query string false "Поля ответа через запятую, например id,contentHash"
 // @Param page query int false "Номер страницы" default(1)
-// @Param page_size query int false "Размер страницы" default(10)
+// @Param page_size query int false "Размер страницы (по умолчанию DEFAULT_SONGS_PAGE_SIZE, не больше MAX_SONGS_PAGE_SIZE)" default(10)
 // @Success 200 {array} model.Song
-// @Failure 400 {object} ErrorResponse
+// @Header 200 {string} X-Pagination-Warning "deep-offset, если смещение страницы больше PAGINATION_WARN_OFFSET"
+// @Failure 400 {object} ErrorResponse "Неверные фильтры или смещение страницы больше PAGINATION_MAX_OFFSET"
 // @Failure 500 {object} ErrorResponse
 // @Router /songs [get]
 func (h *SongHandler) GetSongs(c *gin.Context) {
@@ -52,39 +117,118 @@ func (h *SongHandler) GetSongs(c *gin.Context) {
 	log.Debug("Получение списка песен")
 
 	filter := model.SongFilter{
-		Group:    c.Query("group"),
-		SongName: c.Query("song"),
-		Page:     1,
-		PageSize: 10,
+		Group:       c.Query("group"),
+		SongName:    c.Query("song"),
+		Text:        c.Query("text"),
+		QuickSearch: c.Query("q"),
+		OmitText:    c.Query("includeText") == "false",
 	}
 
-	if page, err := strconv.Atoi(c.Query("page")); err == nil && page > 0 {
-		filter.Page = page
+	filter.Pagination = queryPagination(c)
+
+	var err error
+	if filter.DurationMin, err = parseOptionalInt(c.Query("duration_min")); err != nil {
+		log.Error("Неверный формат duration_min", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат duration_min"})
+		return
+	}
+	if filter.DurationMax, err = parseOptionalInt(c.Query("duration_max")); err != nil {
+		log.Error("Неверный формат duration_max", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат duration_max"})
+		return
+	}
+	if filter.BPMMin, err = parseOptionalInt(c.Query("bpm_min")); err != nil {
+		log.Error("Неверный формат bpm_min", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат bpm_min"})
+		return
+	}
+	if filter.BPMMax, err = parseOptionalInt(c.Query("bpm_max")); err != nil {
+		log.Error("Неверный формат bpm_max", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат bpm_max"})
+		return
+	}
+	if filter.HasText, err = parseOptionalBool(c.Query("has_text")); err != nil {
+		log.Error("Неверный формат has_text", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат has_text"})
+		return
+	}
+	if filter.HasLink, err = parseOptionalBool(c.Query("has_link")); err != nil {
+		log.Error("Неверный формат has_link", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат has_link"})
+		return
+	}
+	if filter.CreatedAtFrom, err = parseOptionalLocalTime(c.Query("created_at_from")); err != nil {
+		log.Error("Неверный формат created_at_from", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат created_at_from, ожидается RFC3339"})
+		return
+	}
+	if filter.CreatedAtTo, err = parseOptionalLocalTime(c.Query("created_at_to")); err != nil {
+		log.Error("Неверный формат created_at_to", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат created_at_to, ожидается RFC3339"})
+		return
 	}
+	filter.CopyrightContains = c.Query("copyright_contains")
+	filter.FeaturedArtist = strings.TrimSpace(c.Query("featured_artist"))
+	filter.EnrichmentStatus = c.Query("enrichmentStatus")
+	filter.MissingFields = parseList(c.Query("missing_fields"))
+	filter.Groups = parseList(c.Query("groups"))
+	filter.SongNames = parseList(c.Query("songs"))
 
-	if pageSize, err := strconv.Atoi(c.Query("page_size")); err == nil && pageSize > 0 {
-		filter.PageSize = pageSize
+	fields := parseList(c.Query("fields"))
+	if err = validateSongFields(fields); err != nil {
+		log.Info("Неверный параметр fields", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	if len(fields) > 0 && !slices.Contains(fields, "text") {
+		filter.OmitText = true
 	}
 
-	songs, err := h.service.GetSongs(c.Request.Context(), filter)
+	ctx, notice := model.WithPaginationNotice(c.Request.Context())
+	songs, err := h.service.GetSongs(ctx, filter)
 	if err != nil {
 		log.Error("Ошибка получения списка песен", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Ошибка получения списка песен"})
+		writeError(c, err, "Ошибка получения списка песен")
+		return
+	}
+	setPaginationWarning(c, notice)
+
+	if len(fields) > 0 {
+		items, err := selectSongFields(songs, fields)
+		if err != nil {
+			log.Error("Ошибка выбора полей песен", "error", err)
+			WriteJSON(c, http.StatusInternalServerError, ErrorResponse{Error: "Ошибка получения списка песен"})
+			return
+		}
+		WriteJSON(c, http.StatusOK, items)
+		return
+	}
+
+	if filter.OmitText {
+		summaries := make([]model.SongSummary, 0, len(songs))
+		for _, song := range songs {
+			summaries = append(summaries, song.Summary())
+		}
+		WriteJSON(c, http.StatusOK, summaries)
 		return
 	}
 
-	c.JSON(http.StatusOK, songs)
+	WriteJSON(c, http.StatusOK, songs)
 }
 
 // @Summary Получение песни по ID
-// @Description Получение данных конкретной песни по ID
+// @Description Получение данных конкретной песни по ID. Поддерживает условные запросы по If-Modified-Since.
+// @Description С format=markdown или Accept: text/markdown песня возвращается файлом Markdown
 // @Tags songs
 // @Accept json
-// @Produce json
-// @Param id path int true "ID песни"
+// @Produce json,text/markdown
+// @Param id path string true "ID песни или публичный UUID (publicId)"
+// @Param format query string false "Формат ответа" Enums(json, markdown)
+// @Param If-Modified-Since header string false "Дата последнего известного клиенту изменения"
 // @Success 200 {object} model.Song
+// @Success 304 "Песня не изменялась"
 // @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
+// @Failure 404 {object} NotFoundResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /songs/{id} [get]
 func (h *SongHandler) GetSongByID(c *gin.Context) {
@@ -92,59 +236,188 @@ func (h *SongHandler) GetSongByID(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		log.Error("Неверный формат ID", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Неверный формат ID"})
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат ID"})
 		return
 	}
 
 	song, err := h.service.GetSongByID(c.Request.Context(), id)
 	if err != nil {
 		log.Error("Ошибка получения песни", "error", err, "id", id)
-		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Песня не найдена"})
+		writeError(c, err, "Ошибка получения песни")
+		return
+	}
+
+	// Представление зависит от Accept, поэтому кэши должны учитывать этот заголовок
+	c.Header("Vary", "Accept")
+	if notModified(c, song.UpdatedAt) {
+		return
+	}
+
+	if wantsMarkdown(c) {
+		c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+			"filename": archiveName(song.Group) + "-" + archiveName(song.Song) + ".md",
+		}))
+		c.Data(http.StatusOK, markdownContentType, []byte(formatter.ToMarkdown(song)))
 		return
 	}
 
-	c.JSON(http.StatusOK, song)
+	WriteJSON(c, http.StatusOK, song)
+}
+
+// markdownContentType тип содержимого ответа в формате Markdown
+const markdownContentType = "text/markdown; charset=utf-8"
+
+// wantsMarkdown сообщает, запросил ли клиент ответ в формате Markdown параметром format или заголовком Accept
+func wantsMarkdown(c *gin.Context) bool {
+	if format := c.Query("format"); format != "" {
+		return format == "markdown"
+	}
+	return strings.Contains(c.GetHeader("Accept"), "text/markdown")
 }
 
 // @Summary Создание новой песни
-// @Description Добавление новой песни в библиотеку
+// @Description Добавление новой песни в библиотеку. Одновременные запросы на создание одной и той же песни объединяются, и каждый из них получает 201 с id созданной песни
 // @Tags songs
 // @Accept json
 // @Produce json
 // @Param input body model.SongInput true "Данные песни"
 // @Success 201 {object} IdResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 422 {object} ValidationErrorResponse
+// @Failure 413 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse
 // @Router /songs [post]
 func (h *SongHandler) CreateSong(c *gin.Context) {
 	log := h.logger.WithContext(c.Request.Context())
 	var input model.SongInput
 	if err := c.ShouldBindJSON(&input); err != nil {
 		log.Error("Ошибка декодирования JSON", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Неверный формат данных"})
+		writeBindError(c, err)
 		return
 	}
 
-	id, err := h.service.CreateSong(c.Request.Context(), input)
+	ref, err := h.service.CreateSong(c.Request.Context(), input)
 	if err != nil {
 		log.Error("Ошибка создания песни", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Ошибка создания песни"})
+		writeError(c, err, "Ошибка создания песни")
+		return
+	}
+
+	WriteJSON(c, http.StatusCreated, IdResponse{ID: ref.ID, PublicID: ref.PublicID})
+}
+
+// @Summary Экспорт песни
+// @Description Получение песни в виде переносимого JSON-документа для импорта в другую инсталляцию
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param id path string true "ID песни или публичный UUID (publicId)"
+// @Success 200 {object} model.SongDocument
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} NotFoundResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/{id}/export [get]
+func (h *SongHandler) ExportSong(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Неверный формат ID", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат ID"})
+		return
+	}
+
+	document, err := h.service.ExportSong(c.Request.Context(), id)
+	if err != nil {
+		log.Error("Ошибка экспорта песни", "error", err, "id", id)
+		writeError(c, err, "Ошибка экспорта песни")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, document)
+}
+
+// @Summary Импорт песни
+// @Description Создание песни из документа, полученного экспортом, без обращения к внешнему API
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param input body model.SongDocument true "Документ песни"
+// @Success 201 {object} IdResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ConflictResponse
+// @Failure 422 {object} ValidationErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/import-one [post]
+func (h *SongHandler) ImportSong(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+	var document model.SongDocument
+	if err := c.ShouldBindJSON(&document); err != nil {
+		log.Error("Ошибка декодирования JSON", "error", err)
+		writeBindError(c, err)
+		return
+	}
+
+	ref, err := h.service.ImportSong(c.Request.Context(), document)
+	if err != nil {
+		log.Error("Ошибка импорта песни", "error", err)
+		writeError(c, err, "Ошибка импорта песни")
+		return
+	}
+
+	WriteJSON(c, http.StatusCreated, IdResponse{ID: ref.ID, PublicID: ref.PublicID})
+}
+
+// @Summary Массовое создание песен
+// @Description Импорт набора песен без обращения к внешнему API. Все песни сохраняются одной транзакцией
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param input body []model.SongImport true "Песни для импорта"
+// @Success 201 {object} model.BulkCreateResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ConflictResponse
+// @Failure 422 {object} ValidationErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/bulk [post]
+func (h *SongHandler) BulkCreateSongs(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+	var inputs []model.SongImport
+	if err := c.ShouldBindJSON(&inputs); err != nil {
+		log.Error("Ошибка декодирования JSON", "error", err)
+		writeBindError(c, err)
+		return
+	}
+
+	inserted, err := h.service.BulkCreateSongs(c.Request.Context(), inputs)
+	if err != nil {
+		log.Error("Ошибка массового создания песен", "error", err)
+		writeError(c, err, "Ошибка массового создания песен")
 		return
 	}
 
-	c.JSON(http.StatusCreated, IdResponse{ID: id})
+	WriteJSON(c, http.StatusCreated, model.BulkCreateResponse{Inserted: inserted})
 }
 
 // @Summary Обновление песни
-// @Description Обновление данных существующей песни
+// @Description Обновление данных существующей песни. Если данные не отличаются от сохраненных, запись не изменяется и возвращается changed=false.
+// @Description Измененные вручную поля теряют происхождение (provenance). С protectEnriched=true изменение полей, заполненных поставщиком данных, отклоняется с 409, если не указан force=true
 // @Tags songs
 // @Accept json
 // @Produce json
-// @Param id path int true "ID песни"
+// @Param id path string true "ID песни или публичный UUID (publicId)"
+// @Param protectEnriched query bool false "Запретить изменение полей, заполненных поставщиком данных"
+// @Param force query bool false "Изменить защищенные поля несмотря на protectEnriched"
 // @Param input body model.Song true "Обновленные данные песни"
-// @Success 200 {object} SuccessResponse
+// @Success 200 {object} UpdateResponse
 // @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
+// @Failure 404 {object} NotFoundResponse
+// @Failure 409 {object} ConflictResponse
+// @Failure 422 {object} ValidationErrorResponse
+// @Failure 413 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /songs/{id} [put]
 func (h *SongHandler) UpdateSong(c *gin.Context) {
@@ -152,117 +425,1025 @@ func (h *SongHandler) UpdateSong(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		log.Error("Неверный формат ID", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Неверный формат ID"})
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат ID"})
 		return
 	}
 
 	var song model.Song
 	if err = c.ShouldBindJSON(&song); err != nil {
 		log.Error("Ошибка декодирования JSON", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Неверный формат данных"})
+		writeBindError(c, err)
 		return
 	}
 
 	song.ID = id
-	if err = h.service.UpdateSong(c.Request.Context(), &song); err != nil {
+	updated, changed, err := h.service.UpdateSong(c.Request.Context(), &song, updateOptions(c))
+	if err != nil {
 		log.Error("Ошибка обновления песни", "error", err, "id", id)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Ошибка обновления песни"})
+		writeError(c, err, "Ошибка обновления песни")
 		return
 	}
 
-	c.JSON(http.StatusOK, SuccessResponse{Message: "Песня успешно обновлена"})
+	message := "Песня успешно обновлена"
+	if !changed {
+		message = "Данные песни не изменились"
+	}
+
+	WriteJSON(c, http.StatusOK, UpdateResponse{Message: message, Changed: changed, Song: updated})
 }
 
-// @Summary Удаление песни
-// @Description Удаление песни из библиотеки
+// @Summary Обновление длительности песни
+// @Description Установка длительности песни в секундах (null очищает значение).
+// @Description С protectEnriched=true изменение длительности, полученной от поставщика данных, отклоняется с 409, если не указан force=true
 // @Tags songs
 // @Accept json
 // @Produce json
-// @Param id path int true "ID песни"
+// @Param id path string true "ID песни или публичный UUID (publicId)"
+// @Param protectEnriched query bool false "Запретить изменение длительности, полученной от поставщика данных"
+// @Param force query bool false "Изменить длительность несмотря на protectEnriched"
+// @Param input body model.DurationInput true "Длительность песни"
 // @Success 200 {object} SuccessResponse
 // @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
+// @Failure 404 {object} NotFoundResponse
+// @Failure 409 {object} ConflictResponse
+// @Failure 413 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
-// @Router /songs/{id} [delete]
-func (h *SongHandler) DeleteSong(c *gin.Context) {
+// @Router /songs/{id}/duration [patch]
+func (h *SongHandler) UpdateSongDuration(c *gin.Context) {
 	log := h.logger.WithContext(c.Request.Context())
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		log.Error("Неверный формат ID", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Неверный формат ID"})
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат ID"})
 		return
 	}
 
-	if err = h.service.DeleteSong(c.Request.Context(), id); err != nil {
-		log.Error("Ошибка удаления песни", "error", err, "id", id)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Ошибка удаления песни"})
+	var input model.DurationInput
+	if err = c.ShouldBindJSON(&input); err != nil {
+		log.Error("Ошибка декодирования JSON", "error", err)
+		writeBindError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, SuccessResponse{Message: "Песня успешно удалена"})
+	if err = h.service.UpdateSongDuration(c.Request.Context(), id, input.DurationSeconds, updateOptions(c)); err != nil {
+		log.Error("Ошибка обновления длительности песни", "error", err, "id", id)
+		writeError(c, err, "Ошибка обновления длительности песни")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, SuccessResponse{Message: "Длительность песни успешно обновлена"})
 }
 
-// @Summary Получение текста песни по куплетам
-// @Description Получение текста песни с пагинацией по куплетам
+// @Summary Обновление темпа песни
+// @Description Установка темпа песни в ударах в минуту, от 20 до 300 (null очищает значение).
+// @Description С protectEnriched=true изменение темпа, полученного от поставщика данных, отклоняется с 409, если не указан force=true
 // @Tags songs
 // @Accept json
 // @Produce json
-// @Param id path int true "ID песни"
-// @Param page query int false "Номер страницы" default(1)
-// @Param page_size query int false "Размер страницы" default(5)
-// @Success 200 {object} VersesResponse
+// @Param id path string true "ID песни или публичный UUID (publicId)"
+// @Param protectEnriched query bool false "Запретить изменение темпа, полученного от поставщика данных"
+// @Param force query bool false "Изменить темп несмотря на protectEnriched"
+// @Param input body model.BPMInput true "Темп песни"
+// @Success 200 {object} SuccessResponse
 // @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
+// @Failure 404 {object} NotFoundResponse
+// @Failure 409 {object} ConflictResponse
+// @Failure 413 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
-// @Router /songs/{id}/verses [get]
-func (h *SongHandler) GetSongVerses(c *gin.Context) {
+// @Router /songs/{id}/bpm [patch]
+func (h *SongHandler) UpdateSongBPM(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Неверный формат ID", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат ID"})
+		return
+	}
+
+	var input model.BPMInput
+	if err = c.ShouldBindJSON(&input); err != nil {
+		log.Error("Ошибка декодирования JSON", "error", err)
+		writeBindError(c, err)
+		return
+	}
+
+	if err = h.service.UpdateSongBPM(c.Request.Context(), id, input.BPM, updateOptions(c)); err != nil {
+		log.Error("Ошибка обновления темпа песни", "error", err, "id", id)
+		writeError(c, err, "Ошибка обновления темпа песни")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, SuccessResponse{Message: "Темп песни успешно обновлен"})
+}
+
+// @Summary Обновление сведений об авторских правах песни
+// @Description Установка сведений об авторских правах песни, не длиннее 2000 символов (null или пустая строка очищает значение).
+// @Description С protectEnriched=true изменение сведений, полученных от поставщика данных, отклоняется с 409, если не указан force=true
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param id path string true "ID песни или публичный UUID (publicId)"
+// @Param protectEnriched query bool false "Запретить изменение сведений, полученных от поставщика данных"
+// @Param force query bool false "Изменить сведения несмотря на protectEnriched"
+// @Param input body model.CopyrightInput true "Сведения об авторских правах"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} NotFoundResponse
+// @Failure 409 {object} ConflictResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/{id}/copyright [patch]
+func (h *SongHandler) UpdateSongCopyright(c *gin.Context) {
 	log := h.logger.WithContext(c.Request.Context())
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		log.Error("Неверный формат ID", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Неверный формат ID"})
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат ID"})
+		return
+	}
+
+	var input model.CopyrightInput
+	if err = c.ShouldBindJSON(&input); err != nil {
+		log.Error("Ошибка декодирования JSON", "error", err)
+		writeBindError(c, err)
 		return
 	}
 
-	pagination := model.VersesPagination{
-		Page:     1,
-		PageSize: 5,
+	if err = h.service.UpdateSongCopyright(c.Request.Context(), id, input.Copyright, updateOptions(c)); err != nil {
+		log.Error("Ошибка обновления сведений об авторских правах", "error", err, "id", id)
+		writeError(c, err, "Ошибка обновления сведений об авторских правах")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, SuccessResponse{Message: "Сведения об авторских правах успешно обновлены"})
+}
+
+// @Summary Обновление приглашенных исполнителей песни
+// @Description Замена списка приглашенных исполнителей песни, не больше 10 имен (пустой список или null очищает его).
+// @Description Пробелы по краям имен удаляются, пустые и повторяющиеся без учета регистра имена пропускаются.
+// @Description С protectEnriched=true изменение списка, полученного от поставщика данных, отклоняется с 409, если не указан force=true
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param id path string true "ID песни или публичный UUID (publicId)"
+// @Param protectEnriched query bool false "Запретить изменение списка, полученного от поставщика данных"
+// @Param force query bool false "Изменить список несмотря на protectEnriched"
+// @Param input body model.FeaturedArtistsInput true "Приглашенные исполнители"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} NotFoundResponse
+// @Failure 409 {object} ConflictResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/{id}/featured-artists [patch]
+func (h *SongHandler) UpdateFeaturedArtists(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Неверный формат ID", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат ID"})
+		return
 	}
 
-	if page, err := strconv.Atoi(c.Query("page")); err == nil && page > 0 {
-		pagination.Page = page
+	var input model.FeaturedArtistsInput
+	if err = c.ShouldBindJSON(&input); err != nil {
+		log.Error("Ошибка декодирования JSON", "error", err)
+		writeBindError(c, err)
+		return
 	}
 
-	if pageSize, err := strconv.Atoi(c.Query("page_size")); err == nil && pageSize > 0 {
-		pagination.PageSize = pageSize
+	if err = h.service.UpdateFeaturedArtists(c.Request.Context(), id, input.Artists, updateOptions(c)); err != nil {
+		log.Error("Ошибка обновления приглашенных исполнителей", "error", err, "id", id)
+		writeError(c, err, "Ошибка обновления приглашенных исполнителей")
+		return
 	}
 
-	verses, err := h.service.GetSongVerses(c.Request.Context(), id, pagination)
+	WriteJSON(c, http.StatusOK, SuccessResponse{Message: "Приглашенные исполнители успешно обновлены"})
+}
+
+// @Summary Песни правообладателя
+// @Description Песни, в сведениях об авторских правах которых встречается holder (без учета регистра), начиная с новых.
+// @Description Как и в GET /songs, сведения об авторских правах в списке не возвращаются
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param holder query string true "Правообладатель"
+// @Param page query int false "Номер страницы" default(1)
+// @Param page_size query int false "Размер страницы (по умолчанию DEFAULT_SONGS_PAGE_SIZE, не больше MAX_SONGS_PAGE_SIZE)" default(10)
+// @Success 200 {array} model.Song
+// @Header 200 {string} X-Pagination-Warning "deep-offset, если смещение страницы больше PAGINATION_WARN_OFFSET"
+// @Failure 400 {object} ErrorResponse "Не указан holder или смещение страницы больше PAGINATION_MAX_OFFSET"
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/by-copyright [get]
+func (h *SongHandler) GetSongsByCopyright(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+
+	pagination := queryPagination(c)
+
+	ctx, notice := model.WithPaginationNotice(c.Request.Context())
+	songs, err := h.service.GetSongsByCopyright(ctx, c.Query("holder"), pagination.Page, pagination.PageSize)
 	if err != nil {
-		log.Error("Ошибка получения куплетов песни", "error", err, "id", id)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Ошибка получения куплетов песни"})
+		log.Error("Ошибка получения песен правообладателя", "error", err)
+		writeError(c, err, "Ошибка получения песен правообладателя")
 		return
 	}
+	setPaginationWarning(c, notice)
 
-	c.JSON(http.StatusOK, VersesResponse{Verses: verses})
+	WriteJSON(c, http.StatusOK, songs)
 }
 
-// IdResponse ответ с идентификатором
-type IdResponse struct {
-	ID int64 `json:"id"`
+// @Summary Распределение песен по темпу
+// @Description Гистограмма темпа с интервалами по 20 ударов в минуту от 20 до 300. Интервал "60-80" включает 60 и не включает 80,
+// @Description последний интервал включает 300. Песни без темпа не учитываются, пустые интервалы возвращаются с count=0
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.TempoBucket
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/tempo-distribution [get]
+func (h *SongHandler) GetTempoDistribution(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+
+	buckets, err := h.service.GetTempoDistribution(c.Request.Context())
+	if err != nil {
+		log.Error("Ошибка получения распределения песен по темпу", "error", err)
+		writeError(c, err, "Ошибка получения распределения песен по темпу")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, buckets)
 }
 
-// SuccessResponse ответ с сообщением об успехе
-type SuccessResponse struct {
-	Message string `json:"message"`
+// @Summary Суммарная длительность песен
+// @Description Суммарная длительность песен, группа которых соответствует фильтру
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param group query string false "Фильтр по группе"
+// @Success 200 {object} model.TotalDuration
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/total-duration [get]
+func (h *SongHandler) GetTotalDuration(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+
+	total, err := h.service.GetTotalDuration(c.Request.Context(), c.Query("group"))
+	if err != nil {
+		log.Error("Ошибка получения суммарной длительности песен", "error", err)
+		writeError(c, err, "Ошибка получения суммарной длительности песен")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, total)
+}
+
+// @Summary Слияние песен
+// @Description Объединение дубликатов: целевая песня обновляется по стратегии, исходная помечается удаленной.
+// @Description Стратегии: append_verses — дописать текст исходной песни, replace_text — заменить текст, keep_target — сохранить текст и перенести метаданные
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param input body model.MergeInput true "Параметры слияния"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} NotFoundResponse
+// @Failure 422 {object} ValidationErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/merge [post]
+func (h *SongHandler) MergeSongs(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+	var input model.MergeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("Ошибка декодирования JSON", "error", err)
+		writeBindError(c, err)
+		return
+	}
+
+	if err := h.service.MergeSongs(c.Request.Context(), input.SourceID, input.TargetID, input.Strategy); err != nil {
+		log.Error("Ошибка слияния песен", "error", err, "source_id", input.SourceID, "target_id", input.TargetID)
+		writeError(c, err, "Ошибка слияния песен")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, SuccessResponse{Message: "Песни успешно объединены"})
+}
+
+// @Summary Переименование группы
+// @Description Переименование группы у всех ее песен одной транзакцией; для каждой песни в журнал событий пишется song.group_renamed.
+// @Description Если в новой группе уже есть песни с такими же названиями, возвращается 409 со списком конфликтов и ничего не изменяется.
+// @Description merge=skip оставляет конфликтующие песни под старым названием, merge=overwrite удаляет песни новой группы
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Param name path string true "Текущее название группы"
+// @Param merge query string false "Разрешение конфликтов" Enums(skip, overwrite)
+// @Param input body model.GroupRenameInput true "Новое название группы"
+// @Success 200 {object} model.GroupRenameResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} GroupRenameConflictResponse
+// @Failure 422 {object} ValidationErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /groups/{name}/rename [post]
+// @Router /groups/{name}/rename [patch]
+func (h *SongHandler) RenameGroup(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+	name := c.Param("name")
+
+	var input model.GroupRenameInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("Ошибка декодирования JSON", "error", err)
+		writeBindError(c, err)
+		return
+	}
+
+	result, err := h.service.RenameGroup(c.Request.Context(), name, input.NewName, c.Query("merge"))
+	if err != nil {
+		log.Error("Ошибка переименования группы", "error", err, "group", name)
+		writeError(c, err, "Ошибка переименования группы")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, result)
+}
+
+// @Summary Песни группы
+// @Description Песни группы без текста по алфавиту названий с общим количеством песен.
+// @Description Параметр sort_by=release_date упорядочивает песни по дате выхода; песни без даты идут последними
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Param name path string true "Название группы"
+// @Param sort_by query string false "Порядок песен" Enums(song_name, release_date) default(song_name)
+// @Param page query int false "Номер страницы" default(1)
+// @Param page_size query int false "Размер страницы (по умолчанию DEFAULT_SONGS_PAGE_SIZE, не больше MAX_SONGS_PAGE_SIZE)" default(10)
+// @Success 200 {object} model.GroupSongsResponse
+// @Header 200 {string} X-Pagination-Warning "deep-offset, если смещение страницы больше PAGINATION_WARN_OFFSET"
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /groups/{name}/songs [get]
+func (h *SongHandler) GetGroupSongs(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+
+	pagination := queryPagination(c)
+
+	ctx, notice := model.WithPaginationNotice(c.Request.Context())
+	songs, total, err := h.service.GetSongsForGroup(ctx, c.Param("name"), c.Query("sort_by"), pagination.Page, pagination.PageSize)
+	if err != nil {
+		log.Error("Ошибка получения песен группы", "error", err)
+		writeError(c, err, "Ошибка получения песен группы")
+		return
+	}
+	setPaginationWarning(c, notice)
+
+	items := make([]model.SongSummary, 0, len(songs))
+	for _, song := range songs {
+		items = append(items, song.Summary())
+	}
+
+	WriteJSON(c, http.StatusOK, model.GroupSongsResponse{Items: items, Total: total})
+}
+
+// @Summary Сведения о группе
+// @Description Описание, страна, год основания и ссылки группы. Если сведения не заполнены, возвращается 404
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Param name path string true "Название группы"
+// @Success 200 {object} model.GroupInfo
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /groups/{name}/info [get]
+func (h *SongHandler) GetGroupInfo(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+	name := c.Param("name")
+
+	info, err := h.service.GetGroupInfo(c.Request.Context(), name)
+	if err != nil {
+		log.Error("Ошибка получения сведений о группе", "error", err, "group", name)
+		writeError(c, err, "Ошибка получения сведений о группе")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, info)
+}
+
+// @Summary Сохранение сведений о группе
+// @Description Создает сведения о группе или заменяет их целиком. Ссылки должны быть абсолютными адресами http или https
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Param name path string true "Название группы"
+// @Param input body model.GroupInfoInput true "Сведения о группе"
+// @Success 200 {object} model.GroupInfo "Сведения обновлены"
+// @Success 201 {object} model.GroupInfo "Сведения созданы"
+// @Failure 400 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 422 {object} ValidationErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /groups/{name}/info [put]
+func (h *SongHandler) PutGroupInfo(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+	name := c.Param("name")
+
+	var input model.GroupInfoInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("Ошибка декодирования JSON", "error", err)
+		writeBindError(c, err)
+		return
+	}
+
+	info, created, err := h.service.PutGroupInfo(c.Request.Context(), name, input)
+	if err != nil {
+		log.Error("Ошибка сохранения сведений о группе", "error", err, "group", name)
+		writeError(c, err, "Ошибка сохранения сведений о группе")
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	WriteJSON(c, status, info)
+}
+
+// @Summary Удаление песни
+// @Description Удаление песни из библиотеки. Песня помечается удаленной и может быть восстановлена
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param id path string true "ID песни или публичный UUID (publicId)"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} NotFoundResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/{id} [delete]
+func (h *SongHandler) DeleteSong(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Неверный формат ID", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат ID"})
+		return
+	}
+
+	if err = h.service.DeleteSong(c.Request.Context(), id); err != nil {
+		log.Error("Ошибка удаления песни", "error", err, "id", id)
+		writeError(c, err, "Ошибка удаления песни")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, SuccessResponse{Message: "Песня успешно удалена"})
+}
+
+// @Summary Список удаленных песен
+// @Description Удаленные песни, начиная с удаленных последними
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param page query int false "Номер страницы" default(1)
+// @Param page_size query int false "Количество песен на странице" default(10)
+// @Success 200 {array} model.Song
+// @Header 200 {string} X-Pagination-Warning "deep-offset, если смещение страницы больше PAGINATION_WARN_OFFSET"
+// @Failure 400 {object} ErrorResponse "Смещение страницы больше PAGINATION_MAX_OFFSET"
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/deleted [get]
+func (h *SongHandler) GetDeletedSongs(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+
+	pagination := queryPagination(c)
+
+	ctx, notice := model.WithPaginationNotice(c.Request.Context())
+	songs, err := h.service.GetDeletedSongs(ctx, pagination.Page, pagination.PageSize)
+	if err != nil {
+		log.Error("Ошибка получения удаленных песен", "error", err)
+		writeError(c, err, "Ошибка получения удаленных песен")
+		return
+	}
+	setPaginationWarning(c, notice)
+
+	WriteJSON(c, http.StatusOK, songs)
+}
+
+// @Summary Восстановление удаленной песни
+// @Description Снимает пометку удаления с песни и возвращает ее. Если группа и название заняты другой песней, возвращается 409 с ID этой песни (existing_id) и восстанавливаемой песни (song_id), чтобы их можно было объединить через POST /songs/merge
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param id path string true "ID песни или публичный UUID (publicId)"
+// @Success 200 {object} model.Song
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} NotFoundResponse
+// @Failure 409 {object} ConflictResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/{id}/restore [post]
+func (h *SongHandler) RestoreSong(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Неверный формат ID", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат ID"})
+		return
+	}
+
+	song, err := h.service.RestoreSong(c.Request.Context(), id)
+	if err != nil {
+		log.Error("Ошибка восстановления песни", "error", err, "id", id)
+		writeError(c, err, "Ошибка восстановления песни")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, song)
+}
+
+// @Summary Получение текста песни по куплетам
+// @Description Получение текста песни с пагинацией по куплетам или диапазоном куплетов from–to.
+// @Description Диапазон, выходящий за пределы текста, обрезается до существующих куплетов.
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param id path string true "ID песни или публичный UUID (publicId)"
+// @Param page query int false "Номер страницы" default(1)
+// @Param page_size query int false "Размер страницы (по умолчанию DEFAULT_VERSES_PAGE_SIZE, не больше MAX_VERSES_PAGE_SIZE)" default(5)
+// @Param from query int false "Первый куплет диапазона (с 1, нельзя сочетать с page и page_size)"
+// @Param to query int false "Последний куплет диапазона включительно (нельзя сочетать с page и page_size)"
+// @Param order query string false "Порядок куплетов: asc или desc" default(asc)
+// @Success 200 {object} VersesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} NotFoundResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/{id}/verses [get]
+func (h *SongHandler) GetSongVerses(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Неверный формат ID", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат ID"})
+		return
+	}
+
+	var pagination model.VersesPagination
+
+	pagination.Pagination = queryPagination(c)
+
+	if pagination.From, err = parseOptionalInt(c.Query("from")); err != nil {
+		log.Error("Неверный формат from", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат from"})
+		return
+	}
+	if pagination.To, err = parseOptionalInt(c.Query("to")); err != nil {
+		log.Error("Неверный формат to", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат to"})
+		return
+	}
+
+	switch c.DefaultQuery("order", "asc") {
+	case "asc":
+	case "desc":
+		pagination.Descending = true
+	default:
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "order должен быть asc или desc"})
+		return
+	}
+
+	verses, total, err := h.service.GetSongVerses(c.Request.Context(), id, pagination)
+	if err != nil {
+		log.Error("Ошибка получения куплетов песни", "error", err, "id", id)
+		writeError(c, err, "Ошибка получения куплетов песни")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, VersesResponse{Verses: verses, TotalVerses: total})
+}
+
+// @Summary Перестановка куплетов песни
+// @Description Переставляет куплеты песни в указанном порядке. order — перестановка номеров куплетов, начиная с 1:
+// @Description каждый номер от 1 до количества куплетов ровно один раз. Прежний текст сохраняется в истории изменений
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param id path string true "ID песни или публичный UUID (publicId)"
+// @Param input body model.VerseOrderInput true "Новый порядок куплетов"
+// @Success 200 {object} model.VerseOrderResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} NotFoundResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 422 {object} ValidationErrorResponse "order не является перестановкой номеров куплетов"
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/{id}/verses/order [put]
+func (h *SongHandler) ReorderVerses(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Неверный формат ID", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат ID"})
+		return
+	}
+
+	var input model.VerseOrderInput
+	if err = c.ShouldBindJSON(&input); err != nil {
+		log.Error("Ошибка декодирования JSON", "error", err)
+		writeBindError(c, err)
+		return
+	}
+
+	verseCount, err := h.service.ReorderVerses(c.Request.Context(), id, input.Order)
+	if err != nil {
+		log.Error("Ошибка перестановки куплетов песни", "error", err, "id", id)
+		writeError(c, err, "Ошибка перестановки куплетов песни")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, model.VerseOrderResponse{VerseCount: verseCount})
+}
+
+// @Summary Изменение куплета песни
+// @Description Заменяет куплет с номером n (с 1) новым текстом. Текст куплета не может быть пустым или содержать пустых строк.
+// @Description Прежний текст сохраняется в истории изменений
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param id path string true "ID песни или публичный UUID (publicId)"
+// @Param n path int true "Номер куплета (с 1)"
+// @Param input body model.VerseInput true "Новый текст куплета"
+// @Success 200 {object} model.VerseEditResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse "Песня или куплет не найдены"
+// @Failure 413 {object} ErrorResponse
+// @Failure 422 {object} ValidationErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/{id}/verses/{n} [put]
+func (h *SongHandler) UpdateVerse(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+	id, index, ok := parseVerseParams(c, h.logger)
+	if !ok {
+		return
+	}
+
+	var input model.VerseInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Error("Ошибка декодирования JSON", "error", err)
+		writeBindError(c, err)
+		return
+	}
+
+	total, updated, err := h.service.UpdateVerse(c.Request.Context(), id, index, input.Text)
+	if err != nil {
+		log.Error("Ошибка изменения куплета песни", "error", err, "id", id, "verse", index)
+		writeError(c, err, "Ошибка изменения куплета песни")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, model.VerseEditResponse{Index: index, Total: total, Updated: updated})
+}
+
+// @Summary Удаление куплета песни
+// @Description Удаляет куплет с номером n (с 1); следующие куплеты сдвигаются. Единственный куплет песни удалить нельзя.
+// @Description Прежний текст сохраняется в истории изменений
+// @Tags songs
+// @Produce json
+// @Param id path string true "ID песни или публичный UUID (publicId)"
+// @Param n path int true "Номер куплета (с 1)"
+// @Success 200 {object} model.VerseEditResponse
+// @Failure 400 {object} ErrorResponse "Неверные параметры или попытка удалить единственный куплет"
+// @Failure 404 {object} ErrorResponse "Песня или куплет не найдены"
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/{id}/verses/{n} [delete]
+func (h *SongHandler) DeleteVerse(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+	id, index, ok := parseVerseParams(c, h.logger)
+	if !ok {
+		return
+	}
+
+	total, err := h.service.DeleteVerse(c.Request.Context(), id, index)
+	if err != nil {
+		log.Error("Ошибка удаления куплета песни", "error", err, "id", id, "verse", index)
+		writeError(c, err, "Ошибка удаления куплета песни")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, model.VerseEditResponse{Index: index, Total: total, Updated: true})
+}
+
+// @Summary Журнал обращений к песне
+// @Description Получение обращений к песне за период с количеством обращений по действиям
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param id path string true "ID песни или публичный UUID (publicId)"
+// @Param from query string false "Начало периода (RFC3339)"
+// @Param to query string false "Конец периода (RFC3339)"
+// @Success 200 {object} model.AccessLog
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/{id}/access-log [get]
+func (h *SongHandler) GetAccessLog(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Неверный формат ID", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат ID"})
+		return
+	}
+
+	from, err := parseOptionalTime(c.Query("from"))
+	if err != nil {
+		log.Error("Неверный формат from", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат from, ожидается RFC3339"})
+		return
+	}
+	to, err := parseOptionalTime(c.Query("to"))
+	if err != nil {
+		log.Error("Неверный формат to", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат to, ожидается RFC3339"})
+		return
+	}
+
+	accessLog, err := h.service.GetAccessLog(c.Request.Context(), id, from, to)
+	if err != nil {
+		log.Error("Ошибка получения журнала обращений", "error", err, "id", id)
+		writeError(c, err, "Ошибка получения журнала обращений")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, accessLog)
+}
+
+// @Summary Самые популярные песни
+// @Description Топ-10 песен по количеству обращений за период
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param period query string false "Период: day или week" default(day)
+// @Success 200 {array} model.MostAccessedSong
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/most-accessed [get]
+func (h *SongHandler) GetMostAccessedSongs(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+
+	songs, err := h.service.GetMostAccessedSongs(c.Request.Context(), c.Query("period"))
+	if err != nil {
+		log.Error("Ошибка получения самых популярных песен", "error", err)
+		writeError(c, err, "Ошибка получения самых популярных песен")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, songs)
+}
+
+// @Summary Трендовые песни
+// @Description Песни с наибольшим количеством обращений за период. Список обновляется не чаще раза в 5 минут
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param period query string false "Период: day, week или month" default(day)
+// @Param limit query int false "Количество песен (от 1 до 100)" default(10)
+// @Success 200 {array} model.Song
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/trending [get]
+func (h *SongHandler) GetTrendingSongs(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+
+	limit := 10
+	if value := c.Query("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil {
+			log.Error("Неверный формат limit", "error", err)
+			WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат limit"})
+			return
+		}
+	}
+
+	songs, err := h.service.GetTrendingSongs(c.Request.Context(), c.Query("period"), limit)
+	if err != nil {
+		log.Error("Ошибка получения трендовых песен", "error", err)
+		writeError(c, err, "Ошибка получения трендовых песен")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, songs)
+}
+
+// @Summary Поиск дубликатов песен
+// @Description Группы песен с похожими названиями группы и песни (сходство Джаро — Винклера после нормализации,
+// @Description регистр, знаки препинания и порядок слов не учитываются). Доступно для библиотек не больше MAX_SONGS_FOR_DUPLICATE_CHECK песен
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param threshold query number false "Минимальное сходство (0, 1]" default(0.8)
+// @Param page query int false "Номер страницы" default(1)
+// @Param page_size query int false "Количество групп на странице" default(10)
+// @Success 200 {array} model.DuplicateGroup
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/duplicates [get]
+func (h *SongHandler) FindDuplicates(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+
+	threshold, err := strconv.ParseFloat(c.DefaultQuery("threshold", "0.8"), 64)
+	if err != nil {
+		log.Error("Неверный формат threshold", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат threshold"})
+		return
+	}
+
+	pagination := queryPagination(c)
+
+	groups, err := h.service.FindDuplicates(c.Request.Context(), threshold, pagination.Page, pagination.PageSize)
+	if err != nil {
+		log.Error("Ошибка поиска дубликатов", "error", err)
+		writeError(c, err, "Ошибка поиска дубликатов")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, groups)
+}
+
+// @Summary Частота слов в тексте песни
+// @Description Самые частые слова текста песни без учета регистра, знаков препинания и стоп-слов
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param id path string true "ID песни или публичный UUID (publicId)"
+// @Param top query int false "Количество слов (от 1 до 100)" default(20)
+// @Success 200 {array} model.WordCount
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} NotFoundResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/{id}/word-frequency [get]
+func (h *SongHandler) GetWordFrequency(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Неверный формат ID", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат ID"})
+		return
+	}
+
+	top := 20
+	if value := c.Query("top"); value != "" {
+		if top, err = strconv.Atoi(value); err != nil {
+			log.Error("Неверный формат top", "error", err)
+			WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат top"})
+			return
+		}
+	}
+
+	words, err := h.service.GetWordFrequency(c.Request.Context(), id, top)
+	if err != nil {
+		log.Error("Ошибка получения частоты слов", "error", err, "id", id)
+		writeError(c, err, "Ошибка получения частоты слов")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, words)
+}
+
+// @Summary Форматированный текст песни
+// @Description Текст песни с переносом строк по границам слов и отступом куплетов. Слова длиннее width не разрываются
+// @Tags songs
+// @Produce plain
+// @Param id path string true "ID песни или публичный UUID (publicId)"
+// @Param width query int false "Максимальная ширина строки (от 20 до 200)" default(80)
+// @Param indent query int false "Отступ куплетов в пробелах (от 0 до 20)" default(0)
+// @Success 200 {string} string "Форматированный текст"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} NotFoundResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /songs/{id}/formatted [get]
+func (h *SongHandler) GetFormattedText(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error("Неверный формат ID", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат ID"})
+		return
+	}
+
+	width, err := strconv.Atoi(c.DefaultQuery("width", "80"))
+	if err != nil {
+		log.Error("Неверный формат width", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат width"})
+		return
+	}
+	indent, err := strconv.Atoi(c.DefaultQuery("indent", "0"))
+	if err != nil {
+		log.Error("Неверный формат indent", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат indent"})
+		return
+	}
+
+	text, err := h.service.GetFormattedText(c.Request.Context(), id, width, indent)
+	if err != nil {
+		log.Error("Ошибка форматирования текста песни", "error", err, "id", id)
+		writeError(c, err, "Ошибка форматирования текста песни")
+		return
+	}
+
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(text))
+}
+
+// parseOptionalTime разбирает необязательный параметр запроса в формате RFC3339.
+// Время приводится к локальному поясу сервера, в котором хранятся временные метки в базе.
+func parseOptionalTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	parsed = parsed.Local()
+	return &parsed, nil
+}
+
+// localTimeLayout формат времени без смещения часового пояса
+const localTimeLayout = "2006-01-02T15:04:05"
+
+// parseOptionalLocalTime разбирает необязательный параметр запроса в формате RFC3339, как parseOptionalTime.
+// Время без смещения часового пояса (2006-01-02T15:04:05) считается временем сервера.
+func parseOptionalLocalTime(value string) (*time.Time, error) {
+	if parsed, err := time.ParseInLocation(localTimeLayout, value, time.Local); err == nil {
+		return &parsed, nil
+	}
+	return parseOptionalTime(value)
+}
+
+// parseOptionalInt разбирает необязательный целочисленный параметр запроса
+func parseOptionalInt(value string) (*int, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, err
+	}
+	return &number, nil
+}
+
+// parseOptionalBool разбирает необязательный логический параметр запроса
+func parseOptionalBool(value string) (*bool, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	flag, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, err
+	}
+	return &flag, nil
+}
+
+// parseList разбирает список значений через запятую, пропуская пустые элементы
+func parseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// updateOptions читает параметры защиты полей, заполненных поставщиком данных
+func updateOptions(c *gin.Context) model.UpdateOptions {
+	return model.UpdateOptions{
+		ProtectEnriched: c.Query("protectEnriched") == "true",
+		Force:           c.Query("force") == "true",
+	}
+}
+
+// IdResponse ответ с идентификаторами созданной песни
+type IdResponse struct {
+	ID       int64  `json:"id" example:"1"`
+	PublicID string `json:"publicId" example:"0b6a4f1e-8c2d-4f7a-9e3b-5d1c2a7f9e40"`
+}
+
+// SuccessResponse ответ с сообщением об успехе
+type SuccessResponse struct {
+	Message string `json:"message" example:"Песня успешно удалена"`
+}
+
+// UpdateResponse ответ на обновление песни
+type UpdateResponse struct {
+	Message string      `json:"message" example:"Песня успешно обновлена"`
+	Changed bool        `json:"changed" example:"true"`
+	Song    *model.Song `json:"song"`
 }
 
 // ErrorResponse ответ с сообщением об ошибке
 type ErrorResponse struct {
-	Error string `json:"error"`
+	Error string `json:"error" example:"Песня не найдена"`
 }
 
 // VersesResponse ответ с куплетами песни
 type VersesResponse struct {
-	Verses []string `json:"verses"`
+	Verses []string `json:"verses" example:"Первый куплет,Второй куплет"`
+	// TotalVerses общее количество куплетов песни, независимо от страницы
+	TotalVerses int `json:"total_verses" example:"2"`
 }
@@ -0,0 +1,998 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"song-library/internal/api/handler"
+	"song-library/internal/model"
+	"song-library/internal/testutil"
+	"song-library/pkg/logger"
+	"testing"
+	"time"
+)
+
+// testPublicID публичный идентификатор тестовой песни
+const testPublicID = "0b6a4f1e-8c2d-4f7a-9e3b-5d1c2a7f9e40"
+
+// testSongJSON ожидаемое представление testSong в ответе
+const testSongJSON = `{"id":1,"publicId":"0b6a4f1e-8c2d-4f7a-9e3b-5d1c2a7f9e40","group":"Muse","song":"Hysteria",` +
+	`"releaseDate":"01.12.2003","text":"It's bugging me\n\nGrating me","link":"https://example.com/hysteria",` +
+	`"createdAt":"2024-01-15T10:30:00Z","updatedAt":"2024-01-15T10:30:00Z","verseCount":2,"textLength":26,` +
+	`"duration":212,"bpm":null,"contentHash":"abc","featuredArtists":null,"enrichmentStatus":"ok","enrichedAt":null,` +
+	`"chartPosition":null,"chartHistory":null}`
+
+// testSong возвращает песню, которую тестовый сервис отдает обработчикам
+func testSong() *model.Song {
+	duration := 212
+	stamp := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	return &model.Song{
+		ID:               1,
+		PublicID:         testPublicID,
+		Group:            "Muse",
+		Song:             "Hysteria",
+		ReleaseDate:      "01.12.2003",
+		Text:             "It's bugging me\n\nGrating me",
+		Link:             "https://example.com/hysteria",
+		CreatedAt:        stamp,
+		UpdatedAt:        stamp,
+		VerseCount:       2,
+		TextLength:       26,
+		Duration:         &duration,
+		ContentHash:      "abc",
+		EnrichmentStatus: model.EnrichmentStatusOK,
+	}
+}
+
+// errDatabase ошибка, которую сервис не относит ни к одной известной категории
+var errDatabase = errors.New("connection refused")
+
+// unexpectedArgs сообщает о неожиданных аргументах вызова сервиса; обработчик ответит 500, и тест увидит расхождение
+func unexpectedArgs(format string, args ...any) error {
+	return fmt.Errorf("неожиданные аргументы сервиса: "+format, args...)
+}
+
+// newTestRouter создает маршрутизатор API с обработчиком песен поверх service
+func newTestRouter(service handler.SongService) *gin.Engine {
+	log := &logger.Logger{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	return testutil.NewTestRouter(handler.NewSongHandler(service, log), log)
+}
+
+// handlerCase запрос к API и ожидаемый ответ
+type handlerCase struct {
+	name       string
+	method     string
+	path       string
+	body       string
+	service    *mockSongService
+	wantStatus int
+	wantBody   string
+}
+
+// runHandlerCases выполняет запросы и сравнивает код состояния и тело ответа целиком
+func runHandlerCases(t *testing.T, cases []handlerCase) {
+	t.Helper()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var body any
+			if tc.body != "" {
+				body = tc.body
+			}
+			recorder := testutil.DoRequest(t, newTestRouter(tc.service), tc.method, tc.path, body)
+
+			testutil.AssertStatus(t, recorder, tc.wantStatus)
+			if got := recorder.Body.String(); got != tc.wantBody {
+				t.Errorf("body = %s\nwant %s", got, tc.wantBody)
+			}
+		})
+	}
+}
+
+func TestCreateSong(t *testing.T) {
+	created := func(ctx context.Context, input model.SongInput) (model.SongRef, error) {
+		if input.Group != "Muse" || input.Song != "Hysteria" {
+			return model.SongRef{}, unexpectedArgs("%+v", input)
+		}
+		return model.SongRef{ID: 1, PublicID: testPublicID}, nil
+	}
+	failing := func(err error) func(context.Context, model.SongInput) (model.SongRef, error) {
+		return func(context.Context, model.SongInput) (model.SongRef, error) { return model.SongRef{}, err }
+	}
+
+	runHandlerCases(t, []handlerCase{
+		{"песня создана", http.MethodPost, "/api/v1/songs", `{"group":"Muse","song":"Hysteria"}`,
+			&mockSongService{createSong: created},
+			http.StatusCreated, `{"id":1,"publicId":"` + testPublicID + `"}`},
+		{"некорректный JSON", http.MethodPost, "/api/v1/songs", `{"group":`,
+			&mockSongService{},
+			http.StatusBadRequest, `{"error":"Неверный формат данных"}`},
+		{"ошибка валидации", http.MethodPost, "/api/v1/songs", `{"group":"Muse","song":" "}`,
+			&mockSongService{createSong: failing(model.NewValidationError("название песни не может быть пустым"))},
+			http.StatusBadRequest, `{"error":"название песни не может быть пустым"}`},
+		{"некорректный UTF-8", http.MethodPost, "/api/v1/songs", `{"group":"Muse","song":"Hysteria"}`,
+			&mockSongService{createSong: failing(&model.EncodingError{Field: "song"})},
+			http.StatusUnprocessableEntity, `{"error":"поле song содержит некорректную последовательность UTF-8",` +
+				`"errors":[{"field":"song","message":"поле song содержит некорректную последовательность UTF-8"}]}`},
+		{"песня уже существует", http.MethodPost, "/api/v1/songs", `{"group":"Muse","song":"Hysteria"}`,
+			&mockSongService{createSong: failing(fmt.Errorf("ошибка создания песни: %w", model.ErrSongAlreadyExists))},
+			http.StatusConflict, `{"error":"Песня уже существует"}`},
+		{"внешний API не ответил", http.MethodPost, "/api/v1/songs", `{"group":"Muse","song":"Hysteria"}`,
+			&mockSongService{createSong: failing(fmt.Errorf("%w: %w", model.ErrUpstreamTimeout, context.DeadlineExceeded))},
+			http.StatusGatewayTimeout, `{"error":"Внешний API не ответил вовремя"}`},
+		{"ошибка внешнего API", http.MethodPost, "/api/v1/songs", `{"group":"Muse","song":"Hysteria"}`,
+			&mockSongService{createSong: failing(fmt.Errorf("%w: код 503", model.ErrUpstreamFailed))},
+			http.StatusBadGateway, `{"error":"Ошибка получения данных из внешнего API"}`},
+		{"сервис перегружен", http.MethodPost, "/api/v1/songs", `{"group":"Muse","song":"Hysteria"}`,
+			&mockSongService{createSong: failing(model.ErrServiceBusy)},
+			http.StatusServiceUnavailable, `{"error":"Сервис перегружен, повторите запрос позже"}`},
+		{"внутренняя ошибка", http.MethodPost, "/api/v1/songs", `{"group":"Muse","song":"Hysteria"}`,
+			&mockSongService{createSong: failing(errDatabase)},
+			http.StatusInternalServerError, `{"error":"Ошибка создания песни"}`},
+	})
+}
+
+func TestGetSongs(t *testing.T) {
+	runHandlerCases(t, []handlerCase{
+		{"список песен с фильтрами", http.MethodGet, "/api/v1/songs?group=Muse&duration_min=60&has_text=true&page=2&page_size=5", "",
+			&mockSongService{getSongs: func(_ context.Context, filter model.SongFilter) ([]*model.Song, error) {
+				if filter.Group != "Muse" || filter.DurationMin == nil || *filter.DurationMin != 60 ||
+					filter.HasText == nil || !*filter.HasText || filter.Page != 2 || filter.PageSize != 5 {
+					return nil, unexpectedArgs("%+v", filter)
+				}
+				return []*model.Song{testSong()}, nil
+			}},
+			http.StatusOK, "[" + testSongJSON + "]"},
+		{"списки групп и названий", http.MethodGet, "/api/v1/songs?groups=Muse,%20Queen,&songs=Hysteria", "",
+			&mockSongService{getSongs: func(_ context.Context, filter model.SongFilter) ([]*model.Song, error) {
+				if !reflect.DeepEqual(filter.Groups, []string{"Muse", "Queen"}) || !reflect.DeepEqual(filter.SongNames, []string{"Hysteria"}) {
+					return nil, unexpectedArgs("%+v", filter)
+				}
+				return []*model.Song{}, nil
+			}},
+			http.StatusOK, "[]"},
+		{"только выбранные поля", http.MethodGet, "/api/v1/songs?fields=id,contentHash", "",
+			&mockSongService{getSongs: func(context.Context, model.SongFilter) ([]*model.Song, error) {
+				return []*model.Song{testSong()}, nil
+			}},
+			http.StatusOK, `[{"contentHash":"abc","id":1}]`},
+		{"неверная длительность", http.MethodGet, "/api/v1/songs?duration_min=abc", "", &mockSongService{},
+			http.StatusBadRequest, `{"error":"Неверный формат duration_min"}`},
+		{"неверный has_text", http.MethodGet, "/api/v1/songs?has_text=maybe", "", &mockSongService{},
+			http.StatusBadRequest, `{"error":"Неверный формат has_text"}`},
+		{"неверный has_link", http.MethodGet, "/api/v1/songs?has_link=yes", "", &mockSongService{},
+			http.StatusBadRequest, `{"error":"Неверный формат has_link"}`},
+		{"неверное время создания", http.MethodGet, "/api/v1/songs?created_at_from=yesterday", "", &mockSongService{},
+			http.StatusBadRequest, `{"error":"Неверный формат created_at_from, ожидается RFC3339"}`},
+		{"конфликтующие фильтры", http.MethodGet, "/api/v1/songs?q=muse&group=Muse", "",
+			&mockSongService{getSongs: func(context.Context, model.SongFilter) ([]*model.Song, error) {
+				return nil, model.NewValidationError("conflicting filters")
+			}},
+			http.StatusBadRequest, `{"error":"conflicting filters"}`},
+		{"внутренняя ошибка", http.MethodGet, "/api/v1/songs", "",
+			&mockSongService{getSongs: func(context.Context, model.SongFilter) ([]*model.Song, error) { return nil, errDatabase }},
+			http.StatusInternalServerError, `{"error":"Ошибка получения списка песен"}`},
+	})
+}
+
+func TestGetSongsPaginationWarning(t *testing.T) {
+	// service отмечает глубокое смещение после 10-й страницы и отклоняет страницы после 100-й, как checkOffset
+	service := &mockSongService{getSongs: func(ctx context.Context, filter model.SongFilter) ([]*model.Song, error) {
+		if filter.Page > 100 {
+			return nil, model.NewValidationError("смещение страницы не может превышать 990 записей, сузьте выборку фильтрами")
+		}
+		if filter.Page > 10 {
+			model.MarkDeepOffset(ctx)
+		}
+		return []*model.Song{}, nil
+	}}
+
+	tests := []struct {
+		name        string
+		page        string
+		wantStatus  int
+		wantWarning string
+	}{
+		{"обычная страница", "10", http.StatusOK, ""},
+		{"глубокое смещение", "11", http.StatusOK, "deep-offset"},
+		{"смещение выше ограничения", "101", http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := testutil.DoRequest(t, newTestRouter(service), http.MethodGet, "/api/v1/songs?page="+tt.page, nil)
+
+			testutil.AssertStatus(t, recorder, tt.wantStatus)
+			if got := recorder.Header().Get("X-Pagination-Warning"); got != tt.wantWarning {
+				t.Errorf("X-Pagination-Warning = %q, want %q", got, tt.wantWarning)
+			}
+			if tt.wantStatus == http.StatusBadRequest {
+				testutil.AssertJSONField(t, recorder, "error", "смещение страницы не может превышать 990 записей, сузьте выборку фильтрами")
+			}
+		})
+	}
+}
+
+func TestGetSongsPresenceFilters(t *testing.T) {
+	yes, no := true, false
+
+	tests := []struct {
+		name        string
+		query       string
+		wantHasText *bool
+		wantHasLink *bool
+	}{
+		{"без фильтров", "", nil, nil},
+		{"только песни без текста", "has_text=false", &no, nil},
+		{"только песни с текстом", "has_text=1", &yes, nil},
+		{"без текста и без ссылки", "has_text=0&has_link=false", &no, &no},
+		{"со ссылкой", "has_link=TRUE", nil, &yes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got model.SongFilter
+			service := &mockSongService{getSongs: func(_ context.Context, filter model.SongFilter) ([]*model.Song, error) {
+				got = filter
+				return []*model.Song{}, nil
+			}}
+
+			recorder := testutil.DoRequest(t, newTestRouter(service), http.MethodGet, "/api/v1/songs?"+tt.query, nil)
+
+			testutil.AssertStatus(t, recorder, http.StatusOK)
+			if !reflect.DeepEqual(got.HasText, tt.wantHasText) {
+				t.Errorf("HasText = %v, want %v", got.HasText, tt.wantHasText)
+			}
+			if !reflect.DeepEqual(got.HasLink, tt.wantHasLink) {
+				t.Errorf("HasLink = %v, want %v", got.HasLink, tt.wantHasLink)
+			}
+		})
+	}
+}
+
+func TestGetSongsCreatedAtRange(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantFrom   *time.Time
+		wantTo     *time.Time
+	}{
+		{"без ограничений", "", http.StatusOK, nil, nil},
+		{"RFC3339 со смещением", "created_at_from=2024-01-15T10:30:00%2B03:00&created_at_to=2024-02-01T00:00:00Z", http.StatusOK,
+			timePtr(time.Date(2024, 1, 15, 10, 30, 0, 0, moscow)), timePtr(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))},
+		{"время без смещения считается временем сервера", "created_at_to=2024-01-15T10:30:00", http.StatusOK,
+			nil, timePtr(time.Date(2024, 1, 15, 10, 30, 0, 0, time.Local))},
+		{"неверный created_at_to", "created_at_to=2024-01-15", http.StatusBadRequest, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got model.SongFilter
+			service := &mockSongService{getSongs: func(_ context.Context, filter model.SongFilter) ([]*model.Song, error) {
+				got = filter
+				return []*model.Song{}, nil
+			}}
+
+			recorder := testutil.DoRequest(t, newTestRouter(service), http.MethodGet, "/api/v1/songs?"+tt.query, nil)
+
+			testutil.AssertStatus(t, recorder, tt.wantStatus)
+			if tt.wantStatus != http.StatusOK {
+				testutil.AssertJSONField(t, recorder, "error", "Неверный формат created_at_to, ожидается RFC3339")
+				return
+			}
+			if !sameTime(got.CreatedAtFrom, tt.wantFrom) {
+				t.Errorf("CreatedAtFrom = %v, want %v", got.CreatedAtFrom, tt.wantFrom)
+			}
+			if !sameTime(got.CreatedAtTo, tt.wantTo) {
+				t.Errorf("CreatedAtTo = %v, want %v", got.CreatedAtTo, tt.wantTo)
+			}
+		})
+	}
+}
+
+func timePtr(v time.Time) *time.Time { return &v }
+
+// sameTime сообщает, что оба значения nil или обозначают один момент времени
+func sameTime(got, want *time.Time) bool {
+	if got == nil || want == nil {
+		return got == want
+	}
+	return got.Equal(*want)
+}
+
+func TestGetSongByID(t *testing.T) {
+	found := func(_ context.Context, id int64) (*model.Song, error) {
+		if id != 1 {
+			return nil, unexpectedArgs("id %d", id)
+		}
+		return testSong(), nil
+	}
+
+	runHandlerCases(t, []handlerCase{
+		{"песня найдена", http.MethodGet, "/api/v1/songs/1", "", &mockSongService{getSongByID: found},
+			http.StatusOK, testSongJSON},
+		{"неверный ID", http.MethodGet, "/api/v1/songs/abc", "", &mockSongService{},
+			http.StatusBadRequest, `{"error":"Неверный формат ID"}`},
+		{"песня не найдена", http.MethodGet, "/api/v1/songs/7", "",
+			&mockSongService{getSongByID: func(_ context.Context, id int64) (*model.Song, error) { return nil, model.NewNotFoundError(id) }},
+			http.StatusNotFound, `{"error":"Песня не найдена","id":7}`},
+		{"внутренняя ошибка", http.MethodGet, "/api/v1/songs/1", "",
+			&mockSongService{getSongByID: func(context.Context, int64) (*model.Song, error) { return nil, errDatabase }},
+			http.StatusInternalServerError, `{"error":"Ошибка получения песни"}`},
+	})
+}
+
+func TestUpdateSong(t *testing.T) {
+	updated := func(changed bool) func(context.Context, *model.Song, model.UpdateOptions) (*model.Song, bool, error) {
+		return func(_ context.Context, song *model.Song, opts model.UpdateOptions) (*model.Song, bool, error) {
+			if song.ID != 1 || song.Group != "Muse" || !opts.ProtectEnriched {
+				return nil, false, unexpectedArgs("%+v %+v", song, opts)
+			}
+			return testSong(), changed, nil
+		}
+	}
+	failing := func(err error) func(context.Context, *model.Song, model.UpdateOptions) (*model.Song, bool, error) {
+		return func(context.Context, *model.Song, model.UpdateOptions) (*model.Song, bool, error) {
+			return nil, false, err
+		}
+	}
+	const body = `{"group":"Muse","song":"Hysteria","text":"It's bugging me\n\nGrating me"}`
+
+	runHandlerCases(t, []handlerCase{
+		{"песня обновлена", http.MethodPut, "/api/v1/songs/1?protectEnriched=true", body,
+			&mockSongService{updateSong: updated(true)},
+			http.StatusOK, `{"message":"Песня успешно обновлена","changed":true,"song":` + testSongJSON + `}`},
+		{"данные не изменились", http.MethodPut, "/api/v1/songs/1?protectEnriched=true", body,
+			&mockSongService{updateSong: updated(false)},
+			http.StatusOK, `{"message":"Данные песни не изменились","changed":false,"song":` + testSongJSON + `}`},
+		{"некорректный JSON", http.MethodPut, "/api/v1/songs/1", `[`, &mockSongService{},
+			http.StatusBadRequest, `{"error":"Неверный формат данных"}`},
+		{"превышена длина текста", http.MethodPut, "/api/v1/songs/1", body,
+			&mockSongService{updateSong: failing(&model.LimitError{Field: "text", Limit: 10})},
+			http.StatusUnprocessableEntity, `{"error":"поле text превышает максимальную длину 10 байт",` +
+				`"errors":[{"field":"text","message":"поле text превышает максимальную длину 10 байт"}]}`},
+		{"поле поставщика защищено", http.MethodPut, "/api/v1/songs/1", body,
+			&mockSongService{updateSong: failing(fmt.Errorf("%w: text", model.ErrEnrichedFieldProtected))},
+			http.StatusConflict, `{"error":"Поле заполнено поставщиком данных, для изменения укажите force=true"}`},
+		{"песня не найдена", http.MethodPut, "/api/v1/songs/9", body,
+			&mockSongService{updateSong: failing(model.NewNotFoundError(9))},
+			http.StatusNotFound, `{"error":"Песня не найдена","id":9}`},
+		{"внутренняя ошибка", http.MethodPut, "/api/v1/songs/1", body,
+			&mockSongService{updateSong: failing(errDatabase)},
+			http.StatusInternalServerError, `{"error":"Ошибка обновления песни"}`},
+	})
+}
+
+func TestUpdateSongDuration(t *testing.T) {
+	runHandlerCases(t, []handlerCase{
+		{"длительность обновлена", http.MethodPatch, "/api/v1/songs/1/duration", `{"duration_seconds":212}`,
+			&mockSongService{updateSongDuration: func(_ context.Context, id int64, duration *int, _ model.UpdateOptions) error {
+				if id != 1 || duration == nil || *duration != 212 {
+					return unexpectedArgs("%d %v", id, duration)
+				}
+				return nil
+			}},
+			http.StatusOK, `{"message":"Длительность песни успешно обновлена"}`},
+		{"длительность очищена", http.MethodPatch, "/api/v1/songs/1/duration", `{"duration_seconds":null}`,
+			&mockSongService{updateSongDuration: func(_ context.Context, _ int64, duration *int, _ model.UpdateOptions) error {
+				if duration != nil {
+					return unexpectedArgs("%v", *duration)
+				}
+				return nil
+			}},
+			http.StatusOK, `{"message":"Длительность песни успешно обновлена"}`},
+		{"отрицательная длительность", http.MethodPatch, "/api/v1/songs/1/duration", `{"duration_seconds":-1}`,
+			&mockSongService{updateSongDuration: func(context.Context, int64, *int, model.UpdateOptions) error {
+				return model.NewValidationError("длительность не может быть отрицательной")
+			}},
+			http.StatusBadRequest, `{"error":"длительность не может быть отрицательной"}`},
+		{"песня не найдена", http.MethodPatch, "/api/v1/songs/5/duration", `{"duration_seconds":212}`,
+			&mockSongService{updateSongDuration: func(_ context.Context, id int64, _ *int, _ model.UpdateOptions) error {
+				return model.NewNotFoundError(id)
+			}},
+			http.StatusNotFound, `{"error":"Песня не найдена","id":5}`},
+	})
+}
+
+func TestDeleteSong(t *testing.T) {
+	runHandlerCases(t, []handlerCase{
+		{"песня удалена", http.MethodDelete, "/api/v1/songs/1", "",
+			&mockSongService{deleteSong: func(context.Context, int64) error { return nil }},
+			http.StatusOK, `{"message":"Песня успешно удалена"}`},
+		{"неверный ID", http.MethodDelete, "/api/v1/songs/1.5", "", &mockSongService{},
+			http.StatusBadRequest, `{"error":"Неверный формат ID"}`},
+		{"песня не найдена", http.MethodDelete, "/api/v1/songs/3", "",
+			&mockSongService{deleteSong: func(_ context.Context, id int64) error { return model.NewNotFoundError(id) }},
+			http.StatusNotFound, `{"error":"Песня не найдена","id":3}`},
+		{"внутренняя ошибка", http.MethodDelete, "/api/v1/songs/1", "",
+			&mockSongService{deleteSong: func(context.Context, int64) error { return errDatabase }},
+			http.StatusInternalServerError, `{"error":"Ошибка удаления песни"}`},
+	})
+}
+
+func TestGetDeletedSongs(t *testing.T) {
+	runHandlerCases(t, []handlerCase{
+		{"страница корзины", http.MethodGet, "/api/v1/songs/deleted?page=3&page_size=20", "",
+			&mockSongService{getDeletedSongs: func(_ context.Context, page, pageSize int) ([]*model.Song, error) {
+				if page != 3 || pageSize != 20 {
+					return nil, unexpectedArgs("page %d, page_size %d", page, pageSize)
+				}
+				return []*model.Song{}, nil
+			}},
+			http.StatusOK, `[]`},
+		{"отрицательная страница приводится к значению по умолчанию", http.MethodGet, "/api/v1/songs/deleted?page=-1", "",
+			&mockSongService{getDeletedSongs: func(_ context.Context, page, pageSize int) ([]*model.Song, error) {
+				if page != 0 || pageSize != 0 {
+					return nil, unexpectedArgs("page %d, page_size %d", page, pageSize)
+				}
+				return []*model.Song{}, nil
+			}},
+			http.StatusOK, `[]`},
+		{"смещение больше допустимого", http.MethodGet, "/api/v1/songs/deleted?page=100000", "",
+			&mockSongService{getDeletedSongs: func(context.Context, int, int) ([]*model.Song, error) {
+				return nil, model.NewValidationError("смещение страницы больше допустимого")
+			}},
+			http.StatusBadRequest, `{"error":"смещение страницы больше допустимого"}`},
+	})
+}
+
+func TestRestoreSong(t *testing.T) {
+	runHandlerCases(t, []handlerCase{
+		{"песня восстановлена", http.MethodPost, "/api/v1/songs/1/restore", "",
+			&mockSongService{restoreSong: func(context.Context, int64) (*model.Song, error) { return testSong(), nil }},
+			http.StatusOK, testSongJSON},
+		{"конфликт с активной песней", http.MethodPost, "/api/v1/songs/3/restore", "",
+			&mockSongService{restoreSong: func(_ context.Context, id int64) (*model.Song, error) {
+				return nil, &model.RestoreConflictError{SongID: id, ConflictingID: 5}
+			}},
+			http.StatusConflict, `{"error":"Песня уже существует","existing_id":5,"song_id":3}`},
+		{"песни нет в корзине", http.MethodPost, "/api/v1/songs/4/restore", "",
+			&mockSongService{restoreSong: func(_ context.Context, id int64) (*model.Song, error) { return nil, model.NewNotFoundError(id) }},
+			http.StatusNotFound, `{"error":"Песня не найдена","id":4}`},
+	})
+}
+
+func TestMergeSongs(t *testing.T) {
+	runHandlerCases(t, []handlerCase{
+		{"песни объединены", http.MethodPost, "/api/v1/songs/merge", `{"source_id":1,"target_id":2,"strategy":"append_verses"}`,
+			&mockSongService{mergeSongs: func(_ context.Context, sourceID, targetID int64, strategy string) error {
+				if sourceID != 1 || targetID != 2 || strategy != model.MergeAppendVerses {
+					return unexpectedArgs("%d %d %s", sourceID, targetID, strategy)
+				}
+				return nil
+			}},
+			http.StatusOK, `{"message":"Песни успешно объединены"}`},
+		{"не указана стратегия", http.MethodPost, "/api/v1/songs/merge", `{"source_id":1,"target_id":2}`, &mockSongService{},
+			http.StatusBadRequest, `{"error":"Неверный формат данных"}`},
+		{"слияние песни с собой", http.MethodPost, "/api/v1/songs/merge", `{"source_id":1,"target_id":1,"strategy":"append_verses"}`,
+			&mockSongService{mergeSongs: func(context.Context, int64, int64, string) error {
+				return model.NewValidationError("source_id и target_id должны различаться")
+			}},
+			http.StatusBadRequest, `{"error":"source_id и target_id должны различаться"}`},
+		{"исходная песня не найдена", http.MethodPost, "/api/v1/songs/merge", `{"source_id":8,"target_id":2,"strategy":"keep_target"}`,
+			&mockSongService{mergeSongs: func(_ context.Context, sourceID, _ int64, _ string) error { return model.NewNotFoundError(sourceID) }},
+			http.StatusNotFound, `{"error":"Песня не найдена","id":8}`},
+	})
+}
+
+func TestGetSongVerses(t *testing.T) {
+	runHandlerCases(t, []handlerCase{
+		{"страница куплетов", http.MethodGet, "/api/v1/songs/1/verses?page=2&page_size=1", "",
+			&mockSongService{getSongVerses: func(_ context.Context, id int64, p model.VersesPagination) ([]string, int, error) {
+				if id != 1 || p.Page != 2 || p.PageSize != 1 || p.From != nil || p.Descending {
+					return nil, 0, unexpectedArgs("%d %+v", id, p)
+				}
+				return []string{"Grating me"}, 2, nil
+			}},
+			http.StatusOK, `{"verses":["Grating me"],"total_verses":2}`},
+		{"диапазон в обратном порядке", http.MethodGet, "/api/v1/songs/1/verses?from=1&to=2&order=desc", "",
+			&mockSongService{getSongVerses: func(_ context.Context, _ int64, p model.VersesPagination) ([]string, int, error) {
+				if p.From == nil || *p.From != 1 || p.To == nil || *p.To != 2 || !p.Descending {
+					return nil, 0, unexpectedArgs("%+v", p)
+				}
+				return []string{"Grating me", "It's bugging me"}, 2, nil
+			}},
+			http.StatusOK, `{"verses":["Grating me","It's bugging me"],"total_verses":2}`},
+		{"неверный порядок", http.MethodGet, "/api/v1/songs/1/verses?order=random", "", &mockSongService{},
+			http.StatusBadRequest, `{"error":"order должен быть asc или desc"}`},
+		{"неверное начало диапазона", http.MethodGet, "/api/v1/songs/1/verses?from=first", "", &mockSongService{},
+			http.StatusBadRequest, `{"error":"Неверный формат from"}`},
+		{"песня не найдена", http.MethodGet, "/api/v1/songs/6/verses", "",
+			&mockSongService{getSongVerses: func(_ context.Context, id int64, _ model.VersesPagination) ([]string, int, error) {
+				return nil, 0, model.NewNotFoundError(id)
+			}},
+			http.StatusNotFound, `{"error":"Песня не найдена","id":6}`},
+	})
+}
+
+func TestEditVerses(t *testing.T) {
+	runHandlerCases(t, []handlerCase{
+		{"куплеты переставлены", http.MethodPut, "/api/v1/songs/1/verses/order", `{"order":[2,1]}`,
+			&mockSongService{reorderVerses: func(_ context.Context, _ int64, order []int) (int, error) {
+				if !reflect.DeepEqual(order, []int{2, 1}) {
+					return 0, unexpectedArgs("%v", order)
+				}
+				return 2, nil
+			}},
+			http.StatusOK, `{"verse_count":2}`},
+		{"порядок не является перестановкой", http.MethodPut, "/api/v1/songs/1/verses/order", `{"order":[1,1]}`,
+			&mockSongService{reorderVerses: func(context.Context, int64, []int) (int, error) {
+				return 0, model.NewVerseOrderError("номер куплета 1 повторяется")
+			}},
+			http.StatusUnprocessableEntity, `{"error":"номер куплета 1 повторяется","errors":[{"field":"order","message":"номер куплета 1 повторяется"}]}`},
+		{"порядок не передан", http.MethodPut, "/api/v1/songs/1/verses/order", `{}`, &mockSongService{},
+			http.StatusBadRequest, `{"error":"Неверный формат данных"}`},
+		{"куплет изменен", http.MethodPut, "/api/v1/songs/1/verses/2", `{"text":"Grating you"}`,
+			&mockSongService{updateVerse: func(_ context.Context, _ int64, index int, text string) (int, bool, error) {
+				if index != 2 || text != "Grating you" {
+					return 0, false, unexpectedArgs("%d %q", index, text)
+				}
+				return 2, true, nil
+			}},
+			http.StatusOK, `{"index":2,"total":2,"updated":true}`},
+		{"неверный номер куплета", http.MethodPut, "/api/v1/songs/1/verses/two", `{"text":"Grating you"}`, &mockSongService{},
+			http.StatusBadRequest, `{"error":"Неверный формат номера куплета"}`},
+		{"куплет удален", http.MethodDelete, "/api/v1/songs/1/verses/1", "",
+			&mockSongService{deleteVerse: func(context.Context, int64, int) (int, error) { return 1, nil }},
+			http.StatusOK, `{"index":1,"total":1,"updated":true}`},
+		{"куплет не найден", http.MethodDelete, "/api/v1/songs/1/verses/9", "",
+			&mockSongService{deleteVerse: func(context.Context, int64, int) (int, error) { return 0, model.ErrVerseNotFound }},
+			http.StatusNotFound, `{"error":"Куплет не найден"}`},
+	})
+}
+
+func TestTextAnalytics(t *testing.T) {
+	runHandlerCases(t, []handlerCase{
+		{"частота слов", http.MethodGet, "/api/v1/songs/1/word-frequency?top=2", "",
+			&mockSongService{getWordFrequency: func(_ context.Context, _ int64, top int) ([]model.WordCount, error) {
+				if top != 2 {
+					return nil, unexpectedArgs("top %d", top)
+				}
+				return []model.WordCount{{Word: "me", Count: 2}, {Word: "bugging", Count: 1}}, nil
+			}},
+			http.StatusOK, `[{"word":"me","count":2},{"word":"bugging","count":1}]`},
+		{"неверный top", http.MethodGet, "/api/v1/songs/1/word-frequency?top=all", "", &mockSongService{},
+			http.StatusBadRequest, `{"error":"Неверный формат top"}`},
+		{"форматированный текст", http.MethodGet, "/api/v1/songs/1/formatted?width=40&indent=2", "",
+			&mockSongService{getFormattedText: func(_ context.Context, _ int64, width, indent int) (string, error) {
+				if width != 40 || indent != 2 {
+					return "", unexpectedArgs("width %d, indent %d", width, indent)
+				}
+				return "  It's bugging me\n\n  Grating me", nil
+			}},
+			http.StatusOK, "  It's bugging me\n\n  Grating me"},
+		{"неверная ширина", http.MethodGet, "/api/v1/songs/1/formatted?width=wide", "", &mockSongService{},
+			http.StatusBadRequest, `{"error":"Неверный формат width"}`},
+		{"ширина вне диапазона", http.MethodGet, "/api/v1/songs/1/formatted?width=5", "",
+			&mockSongService{getFormattedText: func(context.Context, int64, int, int) (string, error) {
+				return "", model.NewValidationError("width должен быть от 20 до 200")
+			}},
+			http.StatusBadRequest, `{"error":"width должен быть от 20 до 200"}`},
+	})
+}
+
+func TestLibraryAnalytics(t *testing.T) {
+	runHandlerCases(t, []handlerCase{
+		{"поиск дубликатов", http.MethodGet, "/api/v1/songs/duplicates?threshold=0.9&page=1&page_size=10", "",
+			&mockSongService{findDuplicates: func(_ context.Context, threshold float64, page, pageSize int) ([]model.DuplicateGroup, error) {
+				if threshold != 0.9 || page != 1 || pageSize != 10 {
+					return nil, unexpectedArgs("%v %d %d", threshold, page, pageSize)
+				}
+				return []model.DuplicateGroup{{Candidates: []model.SongSummary{}}}, nil
+			}},
+			http.StatusOK, `[{"candidates":[]}]`},
+		{"неверный порог", http.MethodGet, "/api/v1/songs/duplicates?threshold=high", "", &mockSongService{},
+			http.StatusBadRequest, `{"error":"Неверный формат threshold"}`},
+		{"библиотека слишком велика", http.MethodGet, "/api/v1/songs/duplicates", "",
+			&mockSongService{findDuplicates: func(context.Context, float64, int, int) ([]model.DuplicateGroup, error) {
+				return nil, model.ErrServiceBusy
+			}},
+			http.StatusServiceUnavailable, `{"error":"Сервис перегружен, повторите запрос позже"}`},
+		{"суммарная длительность", http.MethodGet, "/api/v1/songs/total-duration?group=Muse", "",
+			&mockSongService{getTotalDuration: func(_ context.Context, group string) (*model.TotalDuration, error) {
+				if group != "Muse" {
+					return nil, unexpectedArgs("%q", group)
+				}
+				return &model.TotalDuration{TotalSeconds: 12435, Formatted: "3h 27m 15s"}, nil
+			}},
+			http.StatusOK, `{"total_seconds":12435,"formatted":"3h 27m 15s"}`},
+		{"группа не найдена", http.MethodGet, "/api/v1/songs/total-duration?group=Nobody", "",
+			&mockSongService{getTotalDuration: func(context.Context, string) (*model.TotalDuration, error) {
+				return nil, model.ErrGroupNotFound
+			}},
+			http.StatusNotFound, `{"error":"Группа не найдена"}`},
+	})
+}
+
+func TestGetGroupSongs(t *testing.T) {
+	runHandlerCases(t, []handlerCase{
+		{"песни группы по дате выхода", http.MethodGet, "/api/v1/groups/Muse/songs?sort_by=release_date&page=2&page_size=5", "",
+			&mockSongService{getSongsForGroup: func(_ context.Context, group, sortBy string, page, pageSize int) ([]*model.Song, int64, error) {
+				if group != "Muse" || sortBy != model.GroupSongsSortReleaseDate || page != 2 || pageSize != 5 {
+					return nil, 0, unexpectedArgs("%q %q %d %d", group, sortBy, page, pageSize)
+				}
+				return []*model.Song{{ID: 7, Group: "Muse", Song: "Hysteria", Text: "It's bugging me"}}, 6, nil
+			}},
+			http.StatusOK, `{"items":[{"id":7,"publicId":"","group":"Muse","song":"Hysteria","releaseDate":"","link":"",` +
+				`"createdAt":"0001-01-01T00:00:00Z","updatedAt":"0001-01-01T00:00:00Z","verseCount":0,"textLength":0,` +
+				`"duration":null,"bpm":null,"contentHash":"","featuredArtists":null,"enrichmentStatus":"","enrichedAt":null}],"total":6}`},
+		{"у группы нет песен", http.MethodGet, "/api/v1/groups/Nobody/songs", "",
+			&mockSongService{getSongsForGroup: func(_ context.Context, group, sortBy string, page, pageSize int) ([]*model.Song, int64, error) {
+				if group != "Nobody" || sortBy != "" || page != 0 || pageSize != 0 {
+					return nil, 0, unexpectedArgs("%q %q %d %d", group, sortBy, page, pageSize)
+				}
+				return nil, 0, nil
+			}},
+			http.StatusOK, `{"items":[],"total":0}`},
+		{"неизвестная сортировка", http.MethodGet, "/api/v1/groups/Muse/songs?sort_by=views", "",
+			&mockSongService{getSongsForGroup: func(context.Context, string, string, int, int) ([]*model.Song, int64, error) {
+				return nil, 0, model.NewValidationError("sort_by должен быть song_name или release_date")
+			}},
+			http.StatusBadRequest, `{"error":"sort_by должен быть song_name или release_date"}`},
+	})
+}
+
+func TestUpdateFeaturedArtists(t *testing.T) {
+	runHandlerCases(t, []handlerCase{
+		{"исполнители обновлены", http.MethodPatch, "/api/v1/songs/1/featured-artists", `{"artists":["Guest 1","Guest 2"]}`,
+			&mockSongService{updateFeaturedArtists: func(_ context.Context, _ int64, artists []string, _ model.UpdateOptions) error {
+				if !reflect.DeepEqual(artists, []string{"Guest 1", "Guest 2"}) {
+					return unexpectedArgs("%v", artists)
+				}
+				return nil
+			}},
+			http.StatusOK, `{"message":"Приглашенные исполнители успешно обновлены"}`},
+		{"некорректный JSON", http.MethodPatch, "/api/v1/songs/1/featured-artists", `{"artists":"Guest"}`, &mockSongService{},
+			http.StatusBadRequest, `{"error":"Неверный формат данных"}`},
+		{"защищенное поле без force", http.MethodPatch, "/api/v1/songs/1/featured-artists?protectEnriched=true", `{"artists":[]}`,
+			&mockSongService{updateFeaturedArtists: func(_ context.Context, _ int64, _ []string, opts model.UpdateOptions) error {
+				if !opts.ProtectEnriched || opts.Force {
+					return unexpectedArgs("%+v", opts)
+				}
+				return model.ErrEnrichedFieldProtected
+			}},
+			http.StatusConflict, `{"error":"Поле заполнено поставщиком данных, для изменения укажите force=true"}`},
+	})
+}
+
+func TestGetSongsResponseRoundTrip(t *testing.T) {
+	service := &mockSongService{getSongs: func(context.Context, model.SongFilter) ([]*model.Song, error) {
+		return []*model.Song{testSong()}, nil
+	}}
+
+	recorder := testutil.DoRequest(t, newTestRouter(service), http.MethodGet, "/api/v1/songs", nil)
+	testutil.AssertStatus(t, recorder, http.StatusOK)
+
+	songs := testutil.DecodeJSON[[]*model.Song](t, recorder)
+	if !reflect.DeepEqual(songs, []*model.Song{testSong()}) {
+		t.Errorf("songs = %+v, want %+v", songs, testSong())
+	}
+}
+
+func TestErrorResponseFields(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantFields map[string]any
+	}{
+		{"ошибка длины поля", &model.LimitError{Field: "text", Limit: 10}, http.StatusUnprocessableEntity, map[string]any{
+			"errors.0.field":   "text",
+			"errors.0.message": "поле text превышает максимальную длину 10 байт",
+		}},
+		{"конфликт восстановления", &model.RestoreConflictError{SongID: 1, ConflictingID: 5}, http.StatusConflict, map[string]any{
+			"existing_id": 5,
+			"song_id":     1,
+		}},
+		{"песня не найдена", model.NewNotFoundError(1), http.StatusNotFound, map[string]any{
+			"error": "Песня не найдена",
+			"id":    1,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mockSongService{restoreSong: func(context.Context, int64) (*model.Song, error) { return nil, tt.err }}
+
+			recorder := testutil.DoRequest(t, newTestRouter(service), http.MethodPost, "/api/v1/songs/1/restore", nil)
+			testutil.AssertStatus(t, recorder, tt.wantStatus)
+			for path, want := range tt.wantFields {
+				testutil.AssertJSONField(t, recorder, path, want)
+			}
+		})
+	}
+}
+
+func TestBulkCreateSongs(t *testing.T) {
+	runHandlerCases(t, []handlerCase{
+		{"песни созданы", http.MethodPost, "/api/v1/songs/bulk", `[{"group":"Muse","song":"Hysteria"},{"group":"Muse","song":"Uprising"}]`,
+			&mockSongService{bulkCreateSongs: func(_ context.Context, inputs []model.SongImport) (int64, error) {
+				if len(inputs) != 2 || inputs[1].Song != "Uprising" {
+					return 0, unexpectedArgs("%+v", inputs)
+				}
+				return 2, nil
+			}},
+			http.StatusCreated, `{"inserted":2}`},
+		{"некорректный JSON", http.MethodPost, "/api/v1/songs/bulk", `{"group":"Muse"}`, &mockSongService{},
+			http.StatusBadRequest, `{"error":"Неверный формат данных"}`},
+		{"пустой список", http.MethodPost, "/api/v1/songs/bulk", `[]`,
+			&mockSongService{bulkCreateSongs: func(context.Context, []model.SongImport) (int64, error) {
+				return 0, model.NewValidationError("список песен не может быть пустым")
+			}},
+			http.StatusBadRequest, `{"error":"список песен не может быть пустым"}`},
+		{"внутренняя ошибка", http.MethodPost, "/api/v1/songs/bulk", `[{"group":"Muse","song":"Hysteria"}]`,
+			&mockSongService{bulkCreateSongs: func(context.Context, []model.SongImport) (int64, error) { return 0, errDatabase }},
+			http.StatusInternalServerError, `{"error":"Ошибка массового создания песен"}`},
+	})
+}
+
+func TestExportImportSong(t *testing.T) {
+	const document = `{"formatVersion":1,"group":"Muse","song":"Hysteria","releaseDate":"01.12.2003","text":"It's bugging me",` +
+		`"link":"","duration":212,"bpm":null,"copyright":"© 2003 Muse","featuredArtists":["Guest"]}`
+	copyright := "© 2003 Muse"
+	duration := 212
+
+	runHandlerCases(t, []handlerCase{
+		{"песня экспортирована", http.MethodGet, "/api/v1/songs/1/export", "",
+			&mockSongService{exportSong: func(_ context.Context, id int64) (*model.SongDocument, error) {
+				if id != 1 {
+					return nil, unexpectedArgs("id %d", id)
+				}
+				return &model.SongDocument{
+					FormatVersion: 1,
+					SongImport: model.SongImport{Group: "Muse", Song: "Hysteria", ReleaseDate: "01.12.2003",
+						Text: "It's bugging me", Duration: &duration},
+					Copyright:       &copyright,
+					FeaturedArtists: model.Artists{"Guest"},
+				}, nil
+			}},
+			http.StatusOK, document},
+		{"экспорт: песня не найдена", http.MethodGet, "/api/v1/songs/7/export", "",
+			&mockSongService{exportSong: func(_ context.Context, id int64) (*model.SongDocument, error) {
+				return nil, model.NewNotFoundError(id)
+			}},
+			http.StatusNotFound, `{"error":"Песня не найдена","id":7}`},
+		{"песня импортирована", http.MethodPost, "/api/v1/songs/import-one", document,
+			&mockSongService{importSong: func(_ context.Context, doc model.SongDocument) (model.SongRef, error) {
+				if doc.FormatVersion != 1 || doc.Song != "Hysteria" || doc.Copyright == nil || *doc.Copyright != copyright ||
+					!reflect.DeepEqual(doc.FeaturedArtists, model.Artists{"Guest"}) {
+					return model.SongRef{}, unexpectedArgs("%+v", doc)
+				}
+				return model.SongRef{ID: 2, PublicID: testPublicID}, nil
+			}},
+			http.StatusCreated, `{"id":2,"publicId":"` + testPublicID + `"}`},
+		{"импорт без версии формата", http.MethodPost, "/api/v1/songs/import-one", `{"group":"Muse","song":"Hysteria"}`,
+			&mockSongService{},
+			http.StatusBadRequest, `{"error":"Неверный формат данных"}`},
+		{"неподдерживаемая версия формата", http.MethodPost, "/api/v1/songs/import-one", `{"formatVersion":2,"group":"Muse","song":"Hysteria"}`,
+			&mockSongService{importSong: func(context.Context, model.SongDocument) (model.SongRef, error) {
+				return model.SongRef{}, model.NewValidationError("неподдерживаемая версия формата документа: 2")
+			}},
+			http.StatusBadRequest, `{"error":"неподдерживаемая версия формата документа: 2"}`},
+		{"песня уже существует", http.MethodPost, "/api/v1/songs/import-one", document,
+			&mockSongService{importSong: func(context.Context, model.SongDocument) (model.SongRef, error) {
+				return model.SongRef{}, fmt.Errorf("ошибка импорта песни: %w", model.ErrSongAlreadyExists)
+			}},
+			http.StatusConflict, `{"error":"Песня уже существует"}`},
+		{"внутренняя ошибка импорта", http.MethodPost, "/api/v1/songs/import-one", document,
+			&mockSongService{importSong: func(context.Context, model.SongDocument) (model.SongRef, error) {
+				return model.SongRef{}, errDatabase
+			}},
+			http.StatusInternalServerError, `{"error":"Ошибка импорта песни"}`},
+	})
+}
+
+func TestUpdateSongBPM(t *testing.T) {
+	runHandlerCases(t, []handlerCase{
+		{"темп обновлен", http.MethodPatch, "/api/v1/songs/1/bpm", `{"bpm":120}`,
+			&mockSongService{updateSongBPM: func(_ context.Context, id int64, bpm *int, _ model.UpdateOptions) error {
+				if id != 1 || bpm == nil || *bpm != 120 {
+					return unexpectedArgs("%d %v", id, bpm)
+				}
+				return nil
+			}},
+			http.StatusOK, `{"message":"Темп песни успешно обновлен"}`},
+		{"некорректный JSON", http.MethodPatch, "/api/v1/songs/1/bpm", `{"bpm":"fast"}`, &mockSongService{},
+			http.StatusBadRequest, `{"error":"Неверный формат данных"}`},
+		{"темп вне диапазона", http.MethodPatch, "/api/v1/songs/1/bpm", `{"bpm":500}`,
+			&mockSongService{updateSongBPM: func(context.Context, int64, *int, model.UpdateOptions) error {
+				return model.NewValidationError("bpm должен быть от 20 до 300")
+			}},
+			http.StatusBadRequest, `{"error":"bpm должен быть от 20 до 300"}`},
+		{"песня не найдена", http.MethodPatch, "/api/v1/songs/5/bpm", `{"bpm":120}`,
+			&mockSongService{updateSongBPM: func(_ context.Context, id int64, _ *int, _ model.UpdateOptions) error {
+				return model.NewNotFoundError(id)
+			}},
+			http.StatusNotFound, `{"error":"Песня не найдена","id":5}`},
+		{"внутренняя ошибка", http.MethodPatch, "/api/v1/songs/1/bpm", `{"bpm":120}`,
+			&mockSongService{updateSongBPM: func(context.Context, int64, *int, model.UpdateOptions) error { return errDatabase }},
+			http.StatusInternalServerError, `{"error":"Ошибка обновления темпа песни"}`},
+	})
+}
+
+func TestSongCopyright(t *testing.T) {
+	runHandlerCases(t, []handlerCase{
+		{"сведения обновлены", http.MethodPatch, "/api/v1/songs/1/copyright", `{"copyright":"© 2003 Muse"}`,
+			&mockSongService{updateSongCopyright: func(_ context.Context, id int64, copyright *string, _ model.UpdateOptions) error {
+				if id != 1 || copyright == nil || *copyright != "© 2003 Muse" {
+					return unexpectedArgs("%d %v", id, copyright)
+				}
+				return nil
+			}},
+			http.StatusOK, `{"message":"Сведения об авторских правах успешно обновлены"}`},
+		{"некорректный JSON", http.MethodPatch, "/api/v1/songs/1/copyright", `{"copyright":2003}`, &mockSongService{},
+			http.StatusBadRequest, `{"error":"Неверный формат данных"}`},
+		{"песня не найдена", http.MethodPatch, "/api/v1/songs/5/copyright", `{"copyright":null}`,
+			&mockSongService{updateSongCopyright: func(_ context.Context, id int64, _ *string, _ model.UpdateOptions) error {
+				return model.NewNotFoundError(id)
+			}},
+			http.StatusNotFound, `{"error":"Песня не найдена","id":5}`},
+		{"внутренняя ошибка обновления", http.MethodPatch, "/api/v1/songs/1/copyright", `{"copyright":null}`,
+			&mockSongService{updateSongCopyright: func(context.Context, int64, *string, model.UpdateOptions) error { return errDatabase }},
+			http.StatusInternalServerError, `{"error":"Ошибка обновления сведений об авторских правах"}`},
+		{"песни правообладателя", http.MethodGet, "/api/v1/songs/by-copyright?holder=Warner&page=2&page_size=5", "",
+			&mockSongService{getSongsByCopyright: func(_ context.Context, holder string, page, pageSize int) ([]*model.Song, error) {
+				if holder != "Warner" || page != 2 || pageSize != 5 {
+					return nil, unexpectedArgs("%q %d %d", holder, page, pageSize)
+				}
+				return []*model.Song{testSong()}, nil
+			}},
+			http.StatusOK, "[" + testSongJSON + "]"},
+		{"правообладатель не указан", http.MethodGet, "/api/v1/songs/by-copyright", "",
+			&mockSongService{getSongsByCopyright: func(context.Context, string, int, int) ([]*model.Song, error) {
+				return nil, model.NewValidationError("holder не может быть пустым")
+			}},
+			http.StatusBadRequest, `{"error":"holder не может быть пустым"}`},
+		{"внутренняя ошибка поиска", http.MethodGet, "/api/v1/songs/by-copyright?holder=Warner", "",
+			&mockSongService{getSongsByCopyright: func(context.Context, string, int, int) ([]*model.Song, error) { return nil, errDatabase }},
+			http.StatusInternalServerError, `{"error":"Ошибка получения песен правообладателя"}`},
+	})
+}
+
+func TestAccessAnalytics(t *testing.T) {
+	runHandlerCases(t, []handlerCase{
+		{"журнал обращений", http.MethodGet, "/api/v1/songs/1/access-log?from=2024-01-01T00:00:00Z", "",
+			&mockSongService{getAccessLog: func(_ context.Context, id int64, from, to *time.Time) (*model.AccessLog, error) {
+				if id != 1 || from == nil || !from.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) || to != nil {
+					return nil, unexpectedArgs("%d %v %v", id, from, to)
+				}
+				return &model.AccessLog{Total: 2, Counts: map[string]int{"view": 2}, Entries: []model.AccessLogEntry{}}, nil
+			}},
+			http.StatusOK, `{"total":2,"counts":{"view":2},"entries":[]}`},
+		{"неверное время журнала", http.MethodGet, "/api/v1/songs/1/access-log?to=yesterday", "", &mockSongService{},
+			http.StatusBadRequest, `{"error":"Неверный формат to, ожидается RFC3339"}`},
+		{"журнал: песня не найдена", http.MethodGet, "/api/v1/songs/9/access-log", "",
+			&mockSongService{getAccessLog: func(_ context.Context, id int64, _, _ *time.Time) (*model.AccessLog, error) {
+				return nil, model.NewNotFoundError(id)
+			}},
+			http.StatusNotFound, `{"error":"Песня не найдена","id":9}`},
+		{"самые популярные песни", http.MethodGet, "/api/v1/songs/most-accessed?period=week", "",
+			&mockSongService{getMostAccessedSongs: func(_ context.Context, period string) ([]*model.MostAccessedSong, error) {
+				if period != "week" {
+					return nil, unexpectedArgs("%q", period)
+				}
+				return []*model.MostAccessedSong{}, nil
+			}},
+			http.StatusOK, `[]`},
+		{"неизвестный период популярности", http.MethodGet, "/api/v1/songs/most-accessed?period=year", "",
+			&mockSongService{getMostAccessedSongs: func(context.Context, string) ([]*model.MostAccessedSong, error) {
+				return nil, model.NewValidationError("period должен быть day, week или month")
+			}},
+			http.StatusBadRequest, `{"error":"period должен быть day, week или month"}`},
+		{"трендовые песни", http.MethodGet, "/api/v1/songs/trending?period=day&limit=3", "",
+			&mockSongService{getTrendingSongs: func(_ context.Context, period string, limit int) ([]*model.Song, error) {
+				if period != "day" || limit != 3 {
+					return nil, unexpectedArgs("%q %d", period, limit)
+				}
+				return []*model.Song{testSong()}, nil
+			}},
+			http.StatusOK, "[" + testSongJSON + "]"},
+		{"неверный limit трендов", http.MethodGet, "/api/v1/songs/trending?limit=many", "", &mockSongService{},
+			http.StatusBadRequest, `{"error":"Неверный формат limit"}`},
+		{"внутренняя ошибка трендов", http.MethodGet, "/api/v1/songs/trending", "",
+			&mockSongService{getTrendingSongs: func(_ context.Context, _ string, limit int) ([]*model.Song, error) {
+				if limit != 10 {
+					return nil, unexpectedArgs("limit %d", limit)
+				}
+				return nil, errDatabase
+			}},
+			http.StatusInternalServerError, `{"error":"Ошибка получения трендовых песен"}`},
+		{"распределение по темпу", http.MethodGet, "/api/v1/songs/tempo-distribution", "",
+			&mockSongService{getTempoDistribution: func(context.Context) ([]model.TempoBucket, error) {
+				return []model.TempoBucket{{Range: "60-80", Count: 12}}, nil
+			}},
+			http.StatusOK, `[{"range":"60-80","count":12}]`},
+		{"внутренняя ошибка распределения", http.MethodGet, "/api/v1/songs/tempo-distribution", "",
+			&mockSongService{getTempoDistribution: func(context.Context) ([]model.TempoBucket, error) { return nil, errDatabase }},
+			http.StatusInternalServerError, `{"error":"Ошибка получения распределения песен по темпу"}`},
+	})
+}
+
+func TestGroupInfo(t *testing.T) {
+	formed := 1994
+	stamp := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	info := &model.GroupInfo{Name: "Muse", Country: "United Kingdom", FormedYear: &formed, Links: []string{}, CreatedAt: stamp, UpdatedAt: stamp}
+	const infoJSON = `{"name":"Muse","description":"","country":"United Kingdom","formed_year":1994,"links":[],` +
+		`"created_at":"2024-01-15T10:30:00Z","updated_at":"2024-01-15T10:30:00Z"}`
+	saved := func(created bool) func(context.Context, string, model.GroupInfoInput) (*model.GroupInfo, bool, error) {
+		return func(_ context.Context, name string, input model.GroupInfoInput) (*model.GroupInfo, bool, error) {
+			if name != "Muse" || input.Country != "United Kingdom" || input.FormedYear == nil || *input.FormedYear != 1994 {
+				return nil, false, unexpectedArgs("%q %+v", name, input)
+			}
+			return info, created, nil
+		}
+	}
+	const body = `{"country":"United Kingdom","formed_year":1994}`
+
+	runHandlerCases(t, []handlerCase{
+		{"сведения получены", http.MethodGet, "/api/v1/groups/Muse/info", "",
+			&mockSongService{getGroupInfo: func(_ context.Context, name string) (*model.GroupInfo, error) {
+				if name != "Muse" {
+					return nil, unexpectedArgs("%q", name)
+				}
+				return info, nil
+			}},
+			http.StatusOK, infoJSON},
+		{"сведений нет", http.MethodGet, "/api/v1/groups/Nobody/info", "",
+			&mockSongService{getGroupInfo: func(context.Context, string) (*model.GroupInfo, error) { return nil, model.ErrGroupInfoNotFound }},
+			http.StatusNotFound, `{"error":"Сведения о группе не найдены"}`},
+		{"сведения созданы", http.MethodPut, "/api/v1/groups/Muse/info", body, &mockSongService{putGroupInfo: saved(true)},
+			http.StatusCreated, infoJSON},
+		{"сведения обновлены", http.MethodPut, "/api/v1/groups/Muse/info", body, &mockSongService{putGroupInfo: saved(false)},
+			http.StatusOK, infoJSON},
+		{"некорректный JSON", http.MethodPut, "/api/v1/groups/Muse/info", `{"formed_year":"1994"}`, &mockSongService{},
+			http.StatusBadRequest, `{"error":"Неверный формат данных"}`},
+		{"неверный год основания", http.MethodPut, "/api/v1/groups/Muse/info", `{"formed_year":3000}`,
+			&mockSongService{putGroupInfo: func(context.Context, string, model.GroupInfoInput) (*model.GroupInfo, bool, error) {
+				return nil, false, model.NewValidationError("formed_year не может быть в будущем")
+			}},
+			http.StatusBadRequest, `{"error":"formed_year не может быть в будущем"}`},
+		{"внутренняя ошибка", http.MethodPut, "/api/v1/groups/Muse/info", body,
+			&mockSongService{putGroupInfo: func(context.Context, string, model.GroupInfoInput) (*model.GroupInfo, bool, error) {
+				return nil, false, errDatabase
+			}},
+			http.StatusInternalServerError, `{"error":"Ошибка сохранения сведений о группе"}`},
+	})
+}
+
+func TestRenameGroup(t *testing.T) {
+	runHandlerCases(t, []handlerCase{
+		{"группа переименована", http.MethodPost, "/api/v1/groups/Muse/rename", `{"newName":"Muse (UK)"}`,
+			&mockSongService{renameGroup: func(_ context.Context, name, newName, mergeMode string) (*model.GroupRenameResult, error) {
+				if name != "Muse" || newName != "Muse (UK)" || mergeMode != "" {
+					return nil, unexpectedArgs("%q %q %q", name, newName, mergeMode)
+				}
+				return &model.GroupRenameResult{Renamed: 2, RenamedIDs: []int64{1, 2}}, nil
+			}},
+			http.StatusOK, `{"renamed":2,"skipped":0,"overwritten":0,"renamed_ids":[1,2]}`},
+		{"объединение с пропуском", http.MethodPatch, "/api/v1/groups/Muse/rename?merge=skip", `{"newName":"Muse (UK)"}`,
+			&mockSongService{renameGroup: func(_ context.Context, _, _, mergeMode string) (*model.GroupRenameResult, error) {
+				if mergeMode != "skip" {
+					return nil, unexpectedArgs("%q", mergeMode)
+				}
+				return &model.GroupRenameResult{Renamed: 1, Skipped: 1, RenamedIDs: []int64{2}}, nil
+			}},
+			http.StatusOK, `{"renamed":1,"skipped":1,"overwritten":0,"renamed_ids":[2]}`},
+		{"без нового названия", http.MethodPost, "/api/v1/groups/Muse/rename", `{}`, &mockSongService{},
+			http.StatusBadRequest, `{"error":"Неверный формат данных"}`},
+		{"конфликт названий", http.MethodPost, "/api/v1/groups/Muse/rename", `{"newName":"Muse (UK)"}`,
+			&mockSongService{renameGroup: func(context.Context, string, string, string) (*model.GroupRenameResult, error) {
+				return nil, &model.GroupRenameConflictError{Conflicts: []model.RenameConflict{{Song: "Hysteria", SongID: 1, ExistingID: 42}}}
+			}},
+			http.StatusConflict, `{"error":"В новой группе уже есть песни с такими названиями",` +
+				`"conflicts":[{"song":"Hysteria","song_id":1,"existing_id":42}]}`},
+		{"группа не найдена", http.MethodPost, "/api/v1/groups/Nobody/rename", `{"newName":"Somebody"}`,
+			&mockSongService{renameGroup: func(context.Context, string, string, string) (*model.GroupRenameResult, error) {
+				return nil, model.ErrGroupNotFound
+			}},
+			http.StatusNotFound, `{"error":"Группа не найдена"}`},
+	})
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"mime"
+	"net/http"
+	"song-library/internal/formatter"
+	"song-library/internal/model"
+)
+
+// @Summary Сборник песен группы
+// @Description Все песни группы по алфавиту названий одним документом для печати: для каждой песни название, дата выхода, ссылка и текст.
+// @Description Документ передается потоком по мере чтения песен; при ошибке после начала передачи он обрывается.
+// @Description В формате md пользовательский текст экранируется, чтобы не ломать разметку
+// @Tags groups
+// @Produce plain,text/markdown
+// @Param name path string true "Название группы"
+// @Param format query string false "Формат документа" Enums(txt, md) default(txt)
+// @Success 200 {file} file "Сборник песен"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /groups/{name}/songbook [get]
+func (h *SongHandler) GetGroupSongbook(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+	name := c.Param("name")
+
+	format := c.DefaultQuery("format", formatter.SongbookFormatText)
+	contentType := "text/plain; charset=utf-8"
+	switch format {
+	case formatter.SongbookFormatText:
+	case formatter.SongbookFormatMarkdown:
+		contentType = markdownContentType
+	default:
+		log.Info("Неподдерживаемый формат сборника", "format", format)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неподдерживаемый формат сборника: " + format})
+		return
+	}
+
+	songbook := formatter.NewSongbookWriter(c.Writer, format)
+	err := h.service.ExportGroupSongbook(c.Request.Context(), name, func(song *model.Song) error {
+		// Заголовки отправляются вместе с первой песней, чтобы для пустой группы можно было ответить 404
+		if songbook.Count() == 0 {
+			c.Header("Content-Type", contentType)
+			c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+				"filename": archiveName(song.Group) + "." + format,
+			}))
+			c.Status(http.StatusOK)
+		}
+		return songbook.WriteSong(song)
+	})
+	if err != nil {
+		log.Error("Ошибка экспорта сборника группы", "error", err, "group", name)
+		// Если клиенту еще ничего не отправлено, вместо оборванного документа возвращается обычная ошибка
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Disposition")
+			writeError(c, err, "Ошибка экспорта сборника группы")
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"song-library/internal/model"
+	"song-library/internal/testutil"
+	"strings"
+	"testing"
+)
+
+func TestGetGroupSongbook(t *testing.T) {
+	tests := []struct {
+		name            string
+		format          string
+		wantContentType string
+		wantFilename    string
+	}{
+		{"текст по умолчанию", "", "text/plain; charset=utf-8", "Muse.txt"},
+		{"Markdown", "md", "text/markdown; charset=utf-8", "Muse.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mockSongService{exportGroupSongbook: func(_ context.Context, group string, fn func(song *model.Song) error) error {
+				if group != "Muse" {
+					return unexpectedArgs("%q", group)
+				}
+				return fn(testSong())
+			}}
+
+			path := "/api/v1/groups/Muse/songbook"
+			if tt.format != "" {
+				path += "?format=" + tt.format
+			}
+			recorder := testutil.DoRequest(t, newTestRouter(service), http.MethodGet, path, nil)
+
+			testutil.AssertStatus(t, recorder, http.StatusOK)
+			if got := recorder.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantContentType)
+			}
+			if got, want := recorder.Header().Get("Content-Disposition"), `attachment; filename=`+tt.wantFilename; got != want {
+				t.Errorf("Content-Disposition = %q, want %q", got, want)
+			}
+			if body := recorder.Body.String(); !strings.Contains(body, "Hysteria") || !strings.Contains(body, "Grating me") {
+				t.Errorf("сборник не содержит песню: %s", body)
+			}
+		})
+	}
+}
+
+func TestGetGroupSongbookErrors(t *testing.T) {
+	runHandlerCases(t, []handlerCase{
+		{"неподдерживаемый формат", http.MethodGet, "/api/v1/groups/Muse/songbook?format=pdf", "", &mockSongService{},
+			http.StatusBadRequest, `{"error":"Неподдерживаемый формат сборника: pdf"}`},
+		{"группа не найдена", http.MethodGet, "/api/v1/groups/Nobody/songbook", "",
+			&mockSongService{exportGroupSongbook: func(context.Context, string, func(*model.Song) error) error {
+				return model.ErrGroupNotFound
+			}},
+			http.StatusNotFound, `{"error":"Группа не найдена"}`},
+		{"внутренняя ошибка до первой песни", http.MethodGet, "/api/v1/groups/Muse/songbook", "",
+			&mockSongService{exportGroupSongbook: func(context.Context, string, func(*model.Song) error) error { return errDatabase }},
+			http.StatusInternalServerError, `{"error":"Ошибка экспорта сборника группы"}`},
+	})
+}
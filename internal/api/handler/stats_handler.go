@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"song-library/internal/model"
+	"song-library/pkg/logger"
+	"strconv"
+	"time"
+)
+
+// StatsService интерфейс сервиса статистики библиотеки
+type StatsService interface {
+	GetGrowth(ctx context.Context, interval string, from, to *time.Time) ([]model.GrowthBucket, time.Duration, error)
+	GetTopGroups(ctx context.Context, by string, limit int) ([]model.GroupStat, time.Duration, error)
+	GetEnrichmentStatuses(ctx context.Context) ([]model.EnrichmentStatusCount, time.Duration, error)
+}
+
+// StatsHandler обработчик HTTP запросов статистики для дашбордов
+type StatsHandler struct {
+	service StatsService
+	logger  *logger.Logger
+}
+
+// NewStatsHandler создает новый обработчик статистики
+func NewStatsHandler(service StatsService, logger *logger.Logger) *StatsHandler {
+	return &StatsHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// @Summary Динамика роста библиотеки
+// @Description Количество добавленных песен по интервалам, интервалы без песен заполнены нулями (не более 366 интервалов)
+// @Tags stats
+// @Accept json
+// @Produce json
+// @Param interval query string false "Интервал: day, week или month" default(day)
+// @Param from query string false "Начало периода (RFC3339)"
+// @Param to query string false "Конец периода (RFC3339, не включительно)"
+// @Success 200 {array} model.GrowthBucket
+// @Header 200 {integer} X-Cache-Age "Возраст результата в кэше в секундах"
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /stats/growth [get]
+func (h *StatsHandler) GetGrowth(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+
+	from, err := parseOptionalTime(c.Query("from"))
+	if err != nil {
+		log.Error("Неверный формат from", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат from, ожидается RFC3339"})
+		return
+	}
+	to, err := parseOptionalTime(c.Query("to"))
+	if err != nil {
+		log.Error("Неверный формат to", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат to, ожидается RFC3339"})
+		return
+	}
+
+	growth, age, err := h.service.GetGrowth(c.Request.Context(), c.Query("interval"), from, to)
+	if err != nil {
+		log.Error("Ошибка получения динамики роста", "error", err)
+		writeError(c, err, "Ошибка получения динамики роста")
+		return
+	}
+
+	setCacheAge(c, age)
+	WriteJSON(c, http.StatusOK, growth)
+}
+
+// @Summary Рейтинг групп
+// @Description Группы с наибольшим количеством песен или обращений к песням
+// @Tags stats
+// @Accept json
+// @Produce json
+// @Param limit query int false "Размер рейтинга (от 1 до 100)" default(10)
+// @Param by query string false "Показатель: songs или plays" default(songs)
+// @Success 200 {array} model.GroupStat
+// @Header 200 {integer} X-Cache-Age "Возраст результата в кэше в секундах"
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /stats/groups/top [get]
+func (h *StatsHandler) GetTopGroups(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		log.Error("Неверный формат limit", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат limit"})
+		return
+	}
+
+	groups, age, err := h.service.GetTopGroups(c.Request.Context(), c.DefaultQuery("by", "songs"), limit)
+	if err != nil {
+		log.Error("Ошибка получения рейтинга групп", "error", err)
+		writeError(c, err, "Ошибка получения рейтинга групп")
+		return
+	}
+
+	setCacheAge(c, age)
+	WriteJSON(c, http.StatusOK, groups)
+}
+
+// @Summary Статусы получения данных песен
+// @Description Количество песен по статусу последнего получения данных от поставщика: ok, failed и pending.
+// @Description Песни со статусом failed можно найти через GET /songs?enrichmentStatus=failed
+// @Tags stats
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.EnrichmentStatusCount
+// @Header 200 {integer} X-Cache-Age "Возраст результата в кэше в секундах"
+// @Failure 500 {object} ErrorResponse
+// @Router /stats/enrichment [get]
+func (h *StatsHandler) GetEnrichmentStatuses(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+
+	counts, age, err := h.service.GetEnrichmentStatuses(c.Request.Context())
+	if err != nil {
+		log.Error("Ошибка получения количества песен по статусам получения данных", "error", err)
+		writeError(c, err, "Ошибка получения статистики получения данных")
+		return
+	}
+
+	setCacheAge(c, age)
+	WriteJSON(c, http.StatusOK, counts)
+}
+
+// setCacheAge сообщает клиенту возраст результата в кэше сервиса в целых секундах
+func setCacheAge(c *gin.Context, age time.Duration) {
+	c.Header("X-Cache-Age", strconv.FormatInt(int64(age/time.Second), 10))
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"song-library/internal/model"
+	"song-library/pkg/logger"
+	"time"
+)
+
+// TrashService интерфейс сервиса очистки корзины
+type TrashService interface {
+	PurgeTrash(ctx context.Context, olderThan time.Duration, keepHistory bool) (*model.TrashPurgeResult, error)
+}
+
+// TrashHandler обработчик административной очистки корзины
+type TrashHandler struct {
+	service TrashService
+	logger  *logger.Logger
+}
+
+// NewTrashHandler создает новый обработчик очистки корзины
+func NewTrashHandler(service TrashService, logger *logger.Logger) *TrashHandler {
+	return &TrashHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// @Summary Очистка корзины
+// @Description Окончательно удаляет песни, помеченные удаленными раньше olderThan назад, вместе с журналом обращений и закладками.
+// @Description Песни удаляются пачками с фиксацией каждой пачки; при достижении предела за вызов truncated=true и вызов можно повторить.
+// @Description Требуется заголовок X-Admin-API-Key
+// @Tags admin
+// @Produce json
+// @Param X-Admin-API-Key header string true "Ключ административного API"
+// @Param olderThan query string true "Срок нахождения в корзине: дни с суффиксом d (30d) или длительность (12h)"
+// @Param keepHistory query bool false "Сохранить события удаляемых песен" default(false)
+// @Success 200 {object} model.TrashPurgeResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/trash [delete]
+func (h *TrashHandler) PurgeTrash(c *gin.Context) {
+	log := h.logger.WithContext(c.Request.Context())
+
+	olderThan, err := model.ParseRetention(c.Query("olderThan"))
+	if err != nil {
+		log.Error("Неверный формат olderThan", "error", err)
+		WriteJSON(c, http.StatusBadRequest, ErrorResponse{Error: "Неверный формат olderThan, ожидается например 30d"})
+		return
+	}
+
+	result, err := h.service.PurgeTrash(c.Request.Context(), olderThan, c.Query("keepHistory") == "true")
+	if err != nil {
+		log.Error("Ошибка очистки корзины", "error", err)
+		writeError(c, err, "Ошибка очистки корзины")
+		return
+	}
+
+	WriteJSON(c, http.StatusOK, result)
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"song-library/pkg/logger"
+	"time"
+)
+
+// requestContext добавляет в контекст запроса идентификатор запроса и адрес клиента.
+// Идентификатор берется из заголовка X-Request-ID или генерируется и возвращается клиенту в том же заголовке.
+func requestContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader("X-Request-ID")
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+
+		ctx := context.WithValue(c.Request.Context(), "requestID", requestID)
+		ctx = context.WithValue(ctx, "actor", c.ClientIP())
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Header("X-Request-ID", requestID)
+		c.Next()
+	}
+}
+
+// responseSizeMetrics учитывает размер тела ответа в метрике http_response_size_bytes
+func responseSizeMetrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		// Size учитывает все записанные байты тела, включая потоковые ответы, и известен только после завершения обработчика
+		httpResponseSize.WithLabelValues(routeGroup(c.Request.URL.Path)).Observe(float64(max(c.Writer.Size(), 0)))
+	}
+}
+
+// requestLogging логирует начало и завершение запроса с размерами тел запроса и ответа
+func requestLogging(log *logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		l := log.WithContext(c.Request.Context())
+
+		l.Info("HTTP запрос", "method", c.Request.Method, "path", c.Request.URL.Path,
+			"request_bytes", c.Request.ContentLength)
+		started := time.Now()
+		c.Next()
+
+		l.Info("HTTP запрос выполнен",
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", c.Writer.Status(),
+			"duration_ms", time.Since(started).Milliseconds(),
+			"response_bytes", max(c.Writer.Size(), 0))
+	}
+}
+
+// requestTimeout отменяет контекст запроса через timeout; обработчики и репозиторий прерывают работу по отмене контекста
+func requestTimeout(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// responseSizeSample возвращает количество наблюдений и сумму http_response_size_bytes для группы маршрутов group
+func responseSizeSample(t *testing.T, group string) (uint64, float64) {
+	t.Helper()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("ошибка сбора метрик: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != "http_response_size_bytes" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "route_group" && label.GetValue() == group {
+					return metric.GetHistogram().GetSampleCount(), metric.GetHistogram().GetSampleSum()
+				}
+			}
+		}
+	}
+	return 0, 0
+}
+
+func TestResponseSize(t *testing.T) {
+	const size = 4096
+
+	var out bytes.Buffer
+	engine := newOptionsTestRouter(&listSongsService{}, &out, WithBodyLogging(true))
+	// Тело записывается частями, как в потоковых ответах
+	engine.GET("/api/v1/blob", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+		for range size / 1024 {
+			c.Writer.Write(bytes.Repeat([]byte("a"), 1024))
+			c.Writer.Flush()
+		}
+	})
+	countBefore, sumBefore := responseSizeSample(t, "api")
+
+	recorder := serve(engine, http.MethodGet, "/api/v1/blob")
+
+	if recorder.Body.Len() != size {
+		t.Fatalf("длина тела = %d, want %d", recorder.Body.Len(), size)
+	}
+	if want := fmt.Sprintf("response_bytes=%d", size); !strings.Contains(out.String(), want) {
+		t.Errorf("лог не содержит %s: %s", want, out.String())
+	}
+	count, sum := responseSizeSample(t, "api")
+	if count-countBefore != 1 || sum-sumBefore != size {
+		t.Errorf("http_response_size_bytes{route_group=\"api\"} прирост count = %d, sum = %v, want 1, %d",
+			count-countBefore, sum-sumBefore, size)
+	}
+}
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"song-library/internal/api/handler"
+	"song-library/pkg/readonly"
+	"time"
+)
+
+// routerConfig необязательные параметры маршрутизатора
+type routerConfig struct {
+	environment     string
+	swagger         bool
+	pprof           bool
+	prettyJSON      bool
+	bodyLogging     bool
+	timeout         time.Duration
+	bookmarkHandler *handler.BookmarkHandler
+	statsHandler    *handler.StatsHandler
+	adminHandler    *handler.AdminHandler
+	historyHandler  *handler.HistoryHandler
+	auditRecorder   handler.APIAuditRecorder
+	auditRunner     handler.BackgroundRunner
+	cache           CacheConfig
+	maxBodyBytes    int64
+	maxDecompressed int64
+	readyHandler    *handler.ReadyHandler
+	snapshotHandler *handler.SnapshotHandler
+	poolHandler     *handler.PoolHandler
+	externalAPI     *handler.ExternalAPIHealthHandler
+	trashHandler    *handler.TrashHandler
+	readOnly        *readonly.Switch
+	readOnlyHandler *handler.ReadOnlyHandler
+}
+
+// RouterOption настраивает маршрутизатор при создании
+type RouterOption func(*routerConfig)
+
+// WithEnvironment задает окружение; в production gin работает в release-режиме
+func WithEnvironment(environment string) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.environment = environment
+	}
+}
+
+// WithSwagger подключает документацию Swagger по /swagger
+func WithSwagger(enabled bool) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.swagger = enabled
+	}
+}
+
+// WithPprof подключает профилировщик net/http/pprof по /debug/pprof
+func WithPprof(enabled bool) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.pprof = enabled
+	}
+}
+
+// WithPrettyJSON разрешает форматированные JSON-ответы по параметру pretty=true; предназначено для разработки
+func WithPrettyJSON(enabled bool) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.prettyJSON = enabled
+	}
+}
+
+// WithBodyLogging включает логирование начала и завершения каждого запроса с размерами тел запроса и ответа
+func WithBodyLogging(enabled bool) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.bodyLogging = enabled
+	}
+}
+
+// WithTimeout ограничивает время обработки запросов API: по истечении timeout контекст запроса отменяется (0 — без ограничения)
+func WithTimeout(timeout time.Duration) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.timeout = timeout
+	}
+}
+
+// WithBookmarks подключает маршруты закладок
+func WithBookmarks(bookmarkHandler *handler.BookmarkHandler) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.bookmarkHandler = bookmarkHandler
+	}
+}
+
+// WithStats подключает маршруты статистики
+func WithStats(statsHandler *handler.StatsHandler) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.statsHandler = statsHandler
+	}
+}
+
+// WithAdmin подключает административные маршруты
+func WithAdmin(adminHandler *handler.AdminHandler) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.adminHandler = adminHandler
+	}
+}
+
+// WithHistory подключает маршрут истории изменений песни
+func WithHistory(historyHandler *handler.HistoryHandler) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.historyHandler = historyHandler
+	}
+}
+
+// WithAPIAudit включает запись изменяющих запросов в журнал аудита; записи выполняются через runner
+func WithAPIAudit(recorder handler.APIAuditRecorder, runner handler.BackgroundRunner) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.auditRecorder = recorder
+		cfg.auditRunner = runner
+	}
+}
+
+// WithCache задает время HTTP-кэширования ответов
+func WithCache(cache CacheConfig) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.cache = cache
+	}
+}
+
+// WithBodyLimit ограничивает размер тела запросов API maxBytes байтами (0 — без ограничения)
+func WithBodyLimit(maxBytes int64) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.maxBodyBytes = maxBytes
+	}
+}
+
+// WithDecompressionLimit ограничивает размер распакованного тела сжатых запросов API maxBytes байтами (0 — без ограничения)
+func WithDecompressionLimit(maxBytes int64) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.maxDecompressed = maxBytes
+	}
+}
+
+// WithReadiness подключает маршрут проверки готовности /readyz
+func WithReadiness(readyHandler *handler.ReadyHandler) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.readyHandler = readyHandler
+	}
+}
+
+// WithExternalAPIHealth подключает маршрут проверки доступности внешнего API /health/external-api
+func WithExternalAPIHealth(externalAPIHandler *handler.ExternalAPIHealthHandler) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.externalAPI = externalAPIHandler
+	}
+}
+
+// WithSnapshots подключает маршрут состояния плановых снимков библиотеки
+func WithSnapshots(snapshotHandler *handler.SnapshotHandler) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.snapshotHandler = snapshotHandler
+	}
+}
+
+// WithTrash подключает административную очистку корзины; требует WithAdmin
+func WithTrash(trashHandler *handler.TrashHandler) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.trashHandler = trashHandler
+	}
+}
+
+// WithReadOnly подключает режим только для чтения: изменяющие запросы /api/v1 отклоняются, пока mode включен.
+// Переключатель режима доступен в административном API и требует WithAdmin
+func WithReadOnly(mode *readonly.Switch, readOnlyHandler *handler.ReadOnlyHandler) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.readOnly = mode
+		cfg.readOnlyHandler = readOnlyHandler
+	}
+}
+
+// WithPoolStats подключает статистику пулов соединений по /health; маршруты требуют ключ административного API
+func WithPoolStats(poolHandler *handler.PoolHandler) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.poolHandler = poolHandler
+	}
+}
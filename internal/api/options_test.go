@@ -0,0 +1,451 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"song-library/internal/api/handler"
+	"song-library/internal/model"
+	"song-library/pkg/logger"
+	"strings"
+	"testing"
+	"time"
+)
+
+// listSongsService реализует только GetSongs; остальные методы паникуют через встроенный nil-интерфейс
+type listSongsService struct {
+	handler.SongService
+	getSongs func(ctx context.Context) ([]*model.Song, error)
+}
+
+// GetSongs вызывает getSongs
+func (s *listSongsService) GetSongs(ctx context.Context, _ model.SongFilter) ([]*model.Song, error) {
+	return s.getSongs(ctx)
+}
+
+// createSongService реализует только CreateSong и запоминает полученные данные песни
+type createSongService struct {
+	handler.SongService
+	input model.SongInput
+}
+
+// CreateSong запоминает данные песни
+func (s *createSongService) CreateSong(_ context.Context, input model.SongInput) (model.SongRef, error) {
+	s.input = input
+	return model.SongRef{ID: 1}, nil
+}
+
+// newOptionsTestRouter создает маршрутизатор с сервисом service и опциями opts; логи пишутся в out
+func newOptionsTestRouter(service handler.SongService, out io.Writer, opts ...RouterOption) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log := &logger.Logger{Logger: slog.New(slog.NewTextHandler(out, nil))}
+	router := NewRouter(handler.NewSongHandler(service, log), log, opts...)
+	router.SetupRoutes()
+	return router.GetEngine()
+}
+
+// serve выполняет запрос к маршрутизатору
+func serve(engine *gin.Engine, method, path string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+	return recorder
+}
+
+func TestWithTimeout(t *testing.T) {
+	tests := []struct {
+		name         string
+		timeout      time.Duration
+		wantStatus   int
+		wantBody     string
+		wantDeadline bool
+	}{
+		{"без ограничения", 0, http.StatusOK, "[]", false},
+		{"запрос укладывается в ограничение", time.Minute, http.StatusOK, "[]", true},
+		{"ограничение истекло", 20 * time.Millisecond, http.StatusGatewayTimeout, `{"error":"Время обработки запроса истекло"}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hasDeadline bool
+			service := &listSongsService{getSongs: func(ctx context.Context) ([]*model.Song, error) {
+				var deadline time.Time
+				deadline, hasDeadline = ctx.Deadline()
+				if hasDeadline && time.Until(deadline) < time.Second {
+					<-ctx.Done()
+					return nil, ctx.Err()
+				}
+				return []*model.Song{}, nil
+			}}
+			engine := newOptionsTestRouter(service, io.Discard, WithTimeout(tt.timeout))
+
+			recorder := serve(engine, http.MethodGet, "/api/v1/songs")
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if got := recorder.Body.String(); got != tt.wantBody {
+				t.Errorf("body = %s, want %s", got, tt.wantBody)
+			}
+			if hasDeadline != tt.wantDeadline {
+				t.Errorf("контекст с ограничением времени = %v, want %v", hasDeadline, tt.wantDeadline)
+			}
+		})
+	}
+}
+
+func TestWithBodyLogging(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+	}{
+		{"включено", true},
+		{"выключено", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			service := &listSongsService{getSongs: func(context.Context) ([]*model.Song, error) {
+				return []*model.Song{}, nil
+			}}
+			engine := newOptionsTestRouter(service, &out, WithBodyLogging(tt.enabled))
+
+			if recorder := serve(engine, http.MethodGet, "/api/v1/songs"); recorder.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", recorder.Code, http.StatusOK)
+			}
+
+			logged := strings.Contains(out.String(), "HTTP запрос выполнен")
+			if logged != tt.enabled {
+				t.Errorf("запрос залогирован = %v, want %v; log:\n%s", logged, tt.enabled, out.String())
+			}
+			if tt.enabled && !strings.Contains(out.String(), "response_bytes=2") {
+				t.Errorf("лог не содержит размер ответа: %s", out.String())
+			}
+		})
+	}
+}
+
+func TestWithPrettyJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		enabled    bool
+		path       string
+		wantPretty bool
+	}{
+		{"форматирование по pretty=true", true, "/api/v1/songs?pretty=true", true},
+		{"без параметра ответ компактный", true, "/api/v1/songs", false},
+		{"другое значение параметра", true, "/api/v1/songs?pretty=1", false},
+		{"в production параметр игнорируется", false, "/api/v1/songs?pretty=true", false},
+		{"ошибка форматируется", true, "/api/v1/songs?pretty=true&has_text=maybe", true},
+		{"ошибка в production компактная", false, "/api/v1/songs?pretty=true&has_text=maybe", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &listSongsService{getSongs: func(context.Context) ([]*model.Song, error) {
+				return []*model.Song{{ID: 1, Group: "Muse", Song: "Hysteria"}}, nil
+			}}
+			engine := newOptionsTestRouter(service, io.Discard, WithPrettyJSON(tt.enabled))
+
+			body := serve(engine, http.MethodGet, tt.path).Body.String()
+			if !json.Valid([]byte(body)) {
+				t.Fatalf("body не является JSON: %s", body)
+			}
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, []byte(body)); err != nil {
+				t.Fatalf("json.Compact() error = %v", err)
+			}
+			if pretty := body != compact.String(); pretty != tt.wantPretty {
+				t.Errorf("ответ с отступами = %v, want %v; body:\n%s", pretty, tt.wantPretty, body)
+			}
+			if tt.wantPretty && !strings.Contains(body, "\n    ") {
+				t.Errorf("body не содержит отступа в 4 пробела:\n%s", body)
+			}
+		})
+	}
+}
+
+// gzipBody сжимает data в gzip
+func gzipBody(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		t.Fatalf("ошибка сжатия тела запроса: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("ошибка сжатия тела запроса: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestWithDecompressionLimit(t *testing.T) {
+	plain := []byte(`{"group":"Muse","song":"Hysteria"}`)
+	// Длинная повторяющаяся строка сжимается в несколько десятков байт
+	large := []byte(`{"group":"Muse","song":"` + strings.Repeat("a", 4096) + `"}`)
+
+	tests := []struct {
+		name       string
+		encoding   string
+		body       []byte
+		opts       []RouterOption
+		wantStatus int
+		wantBody   string
+	}{
+		{"несжатое тело", "", plain, nil, http.StatusCreated, ""},
+		{"тело в gzip", "gzip", gzipBody(t, plain), nil, http.StatusCreated, ""},
+		{"кодировка без учета регистра", " GZIP ", gzipBody(t, plain), nil, http.StatusCreated, ""},
+		{"поврежденный gzip", "gzip", plain, nil, http.StatusBadRequest, `{"error":"Неверный формат сжатого тела запроса"}`},
+		{"распакованное тело больше ограничения", "gzip", gzipBody(t, large),
+			[]RouterOption{WithBodyLimit(1024), WithDecompressionLimit(1024)},
+			http.StatusRequestEntityTooLarge, `{"error":"Тело запроса превышает 1024 байт"}`},
+		{"распакованное тело без ограничения", "gzip", gzipBody(t, large),
+			[]RouterOption{WithBodyLimit(1024)}, http.StatusCreated, ""},
+		{"сжатое тело больше MAX_BODY_BYTES", "gzip", gzipBody(t, plain),
+			[]RouterOption{WithBodyLimit(16), WithDecompressionLimit(1024)},
+			http.StatusRequestEntityTooLarge, `{"error":"Тело запроса превышает 16 байт"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &createSongService{}
+			engine := newOptionsTestRouter(service, io.Discard, tt.opts...)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/songs", bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.encoding != "" {
+				req.Header.Set("Content-Encoding", tt.encoding)
+			}
+			recorder := httptest.NewRecorder()
+			engine.ServeHTTP(recorder, req)
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d; body: %s", recorder.Code, tt.wantStatus, recorder.Body.String())
+			}
+			if tt.wantBody != "" {
+				if got := recorder.Body.String(); got != tt.wantBody {
+					t.Errorf("body = %s, want %s", got, tt.wantBody)
+				}
+				return
+			}
+			if service.input.Group != "Muse" || service.input.Song == "" {
+				t.Errorf("CreateSong() получил %+v, want распакованные данные песни", service.input)
+			}
+		})
+	}
+}
+
+// countingReader считает прочитанные из тела запроса байты
+type countingReader struct {
+	r    io.Reader
+	read int
+}
+
+// Read читает из r и увеличивает счетчик
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.read += n
+	return n, err
+}
+
+func TestWithBodyLimit(t *testing.T) {
+	const limit = 64
+	small := `{"group":"Muse","song":"Hysteria"}`
+	large := `{"group":"Muse","song":"` + strings.Repeat("a", 2*limit) + `"}`
+
+	tests := []struct {
+		name          string
+		body          string
+		contentLength int64
+		wantStatus    int
+		wantBody      string
+		// wantUnread тело не должно читаться: запрос отклоняется по заявленному Content-Length
+		wantUnread bool
+	}{
+		{"тело в пределах ограничения", small, int64(len(small)), http.StatusCreated, "", false},
+		{"chunked тело в пределах ограничения", small, -1, http.StatusCreated, "", false},
+		{"заявленный Content-Length больше ограничения", large, int64(len(large)), http.StatusRequestEntityTooLarge,
+			`{"error":"Тело запроса превышает 64 байт"}`, true},
+		{"chunked тело больше ограничения", large, -1, http.StatusRequestEntityTooLarge,
+			`{"error":"Тело запроса превышает 64 байт"}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &createSongService{}
+			engine := newOptionsTestRouter(service, io.Discard, WithBodyLimit(limit))
+
+			body := &countingReader{r: strings.NewReader(tt.body)}
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/songs", body)
+			req.Header.Set("Content-Type", "application/json")
+			// -1 означает длину, неизвестную заранее, как у тела с Transfer-Encoding: chunked
+			req.ContentLength = tt.contentLength
+			recorder := httptest.NewRecorder()
+			engine.ServeHTTP(recorder, req)
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d; body: %s", recorder.Code, tt.wantStatus, recorder.Body.String())
+			}
+			if tt.wantUnread && body.read != 0 {
+				t.Errorf("прочитано %d байт тела, want 0", body.read)
+			}
+			if tt.wantBody == "" {
+				if service.input.Song != "Hysteria" {
+					t.Errorf("CreateSong() получил %+v, want данные песни", service.input)
+				}
+				return
+			}
+			if got := recorder.Body.String(); got != tt.wantBody {
+				t.Errorf("body = %s, want %s", got, tt.wantBody)
+			}
+			if service.input != (model.SongInput{}) {
+				t.Errorf("CreateSong() вызван с %+v при превышении ограничения", service.input)
+			}
+		})
+	}
+}
+
+func TestWithPprof(t *testing.T) {
+	tests := []struct {
+		name       string
+		enabled    bool
+		wantStatus int
+	}{
+		{"включен", true, http.StatusOK},
+		{"выключен", false, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newOptionsTestRouter(&listSongsService{}, io.Discard, WithPprof(tt.enabled))
+
+			if recorder := serve(engine, http.MethodGet, "/debug/pprof/"); recorder.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// poolStats возвращает фиксированную статистику пула соединений
+type poolStats sql.DBStats
+
+// Stats возвращает статистику пула
+func (s poolStats) Stats() sql.DBStats { return sql.DBStats(s) }
+
+func TestWithPoolStats(t *testing.T) {
+	primary := poolStats{MaxOpenConnections: 25, OpenConnections: 18, InUse: 12, Idle: 6, WaitCount: 2,
+		WaitDuration: 45 * time.Millisecond, MaxLifetimeClosed: 3}
+	replica := poolStats{MaxOpenConnections: 10, OpenConnections: 4, InUse: 1, Idle: 3}
+	wantKeys := []string{"idle", "in_use", "max_idle_closed", "max_lifetime_closed", "max_open", "open", "wait_count", "wait_duration_ms"}
+
+	tests := []struct {
+		name        string
+		replica     handler.PoolStatsProvider
+		key         string
+		wantStatus  int
+		wantReplica bool
+	}{
+		{"без реплики", nil, "admin-key", http.StatusOK, false},
+		{"с репликой", replica, "admin-key", http.StatusOK, true},
+		{"без ключа API", nil, "", http.StatusUnauthorized, false},
+		{"неверный ключ API", nil, "wrong-key", http.StatusUnauthorized, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := &logger.Logger{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+			engine := newOptionsTestRouter(&listSongsService{}, io.Discard,
+				WithAdmin(handler.NewAdminHandler(nil, "admin-key", log)),
+				WithPoolStats(handler.NewPoolHandler(primary, tt.replica)))
+
+			req := httptest.NewRequest(http.MethodGet, "/health/db-pool", nil)
+			if tt.key != "" {
+				req.Header.Set("X-Admin-API-Key", tt.key)
+			}
+			recorder := httptest.NewRecorder()
+			engine.ServeHTTP(recorder, req)
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d; body = %s", recorder.Code, tt.wantStatus, recorder.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var body map[string]json.RawMessage
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+				t.Fatalf("ошибка разбора ответа: %v", err)
+			}
+			replicaJSON, hasReplica := body["replica"]
+			if hasReplica != tt.wantReplica {
+				t.Errorf("replica в ответе = %v, want %v", hasReplica, tt.wantReplica)
+			}
+			delete(body, "replica")
+			if keys := slices.Sorted(maps.Keys(body)); !slices.Equal(keys, wantKeys) {
+				t.Errorf("ключи ответа = %v, want %v", keys, wantKeys)
+			}
+			if want := `{"max_open":25,"open":18,"in_use":12,"idle":6,"wait_count":2,"wait_duration_ms":45,` +
+				`"max_idle_closed":0,"max_lifetime_closed":3`; !strings.HasPrefix(recorder.Body.String(), want) {
+				t.Errorf("body = %s, want префикс %s", recorder.Body.String(), want)
+			}
+			if !tt.wantReplica {
+				return
+			}
+			var replicaBody map[string]json.RawMessage
+			if err := json.Unmarshal(replicaJSON, &replicaBody); err != nil {
+				t.Fatalf("ошибка разбора replica: %v", err)
+			}
+			if keys := slices.Sorted(maps.Keys(replicaBody)); !slices.Equal(keys, wantKeys) {
+				t.Errorf("ключи replica = %v, want %v", keys, wantKeys)
+			}
+			if string(replicaBody["max_open"]) != "10" {
+				t.Errorf("replica.max_open = %s, want 10", replicaBody["max_open"])
+			}
+		})
+	}
+}
+
+func TestRequestIDAlwaysSet(t *testing.T) {
+	tests := []struct {
+		name      string
+		requestID string
+	}{
+		{"идентификатор клиента", "client-request-1"},
+		{"сгенерированный идентификатор", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen interface{}
+			service := &listSongsService{getSongs: func(ctx context.Context) ([]*model.Song, error) {
+				seen = ctx.Value("requestID")
+				return []*model.Song{}, nil
+			}}
+			engine := newOptionsTestRouter(service, io.Discard)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/songs", nil)
+			if tt.requestID != "" {
+				req.Header.Set("X-Request-ID", tt.requestID)
+			}
+			recorder := httptest.NewRecorder()
+			engine.ServeHTTP(recorder, req)
+
+			got := recorder.Header().Get("X-Request-ID")
+			if got == "" || (tt.requestID != "" && got != tt.requestID) {
+				t.Errorf("X-Request-ID = %q, want %q", got, tt.requestID)
+			}
+			if seen != got {
+				t.Errorf("requestID в контексте = %v, want %q", seen, got)
+			}
+		})
+	}
+}
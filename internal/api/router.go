@@ -1,49 +1,81 @@
 package api
 
 import (
-	"context"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"net/http/pprof"
 	"song-library/internal/api/handler"
 	"song-library/pkg/logger"
+	"strings"
+	"time"
 )
 
+// httpResponseSize размер тел ответов по группам маршрутов: Swagger отдает крупные
+// статические файлы и учитывается отдельно, чтобы не искажать распределение ответов API
+var httpResponseSize = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "http_response_size_bytes",
+	Help:    "Размер тела HTTP-ответа в байтах",
+	Buckets: []float64{100, 1000, 10000, 100000, 1000000},
+}, []string{"route_group"})
+
+// routeGroup возвращает группу маршрута для метрик размера ответа
+func routeGroup(path string) string {
+	switch {
+	case strings.HasPrefix(path, "/swagger/"):
+		return "swagger"
+	case strings.HasPrefix(path, "/api/"):
+		return "api"
+	default:
+		return "other"
+	}
+}
+
 // Router структура для маршрутизации API
 type Router struct {
 	engine      *gin.Engine
 	songHandler *handler.SongHandler
+	cfg         routerConfig
 	logger      *logger.Logger
 }
 
-// NewRouter создает и настраивает новый маршрутизатор
-func NewRouter(songHandler *handler.SongHandler, log *logger.Logger, environment string) *Router {
-	if environment == "production" {
+// CacheConfig настройки HTTP-кэширования для групп маршрутов
+type CacheConfig struct {
+	// ListMaxAge время кэширования списков песен
+	ListMaxAge time.Duration
+	// ItemMaxAge время кэширования отдельных песен и связанных с ними ресурсов
+	ItemMaxAge time.Duration
+}
+
+// NewRouter создает и настраивает новый маршрутизатор.
+// Маршруты закладок, истории, статистики и администрирования подключаются только при передаче соответствующих опций.
+func NewRouter(songHandler *handler.SongHandler, log *logger.Logger, opts ...RouterOption) *Router {
+	var cfg routerConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	engine := gin.New()
 	engine.Use(gin.Recovery())
+	engine.Use(handler.PrettyPrint(cfg.prettyJSON))
 
-	engine.Use(func(c *gin.Context) {
-		requestID := c.GetHeader("X-Request-ID")
-		if requestID == "" {
-			requestID = uuid.New().String()
-		}
-
-		ctx := context.WithValue(c.Request.Context(), "requestID", requestID)
-		c.Request = c.Request.WithContext(ctx)
-
-		c.Header("X-Request-ID", requestID)
-
-		log.Info("HTTP запрос", "method", c.Request.Method, "path", c.Request.URL.Path, "requestID", requestID)
-		c.Next()
-	})
+	engine.Use(requestContext())
+	engine.Use(responseSizeMetrics())
+	if cfg.bodyLogging {
+		engine.Use(requestLogging(log))
+	}
 
 	return &Router{
 		engine:      engine,
 		songHandler: songHandler,
+		cfg:         cfg,
 		logger:      log,
 	}
 }
@@ -51,19 +83,133 @@ func NewRouter(songHandler *handler.SongHandler, log *logger.Logger, environment
 // SetupRoutes настраивает все маршруты API
 func (r *Router) SetupRoutes() {
 	api := r.engine.Group("/api/v1")
+	if r.cfg.timeout > 0 {
+		api.Use(requestTimeout(r.cfg.timeout))
+	}
+	if r.cfg.maxBodyBytes > 0 {
+		api.Use(handler.BodyLimit(r.cfg.maxBodyBytes))
+	}
+	// Распаковка после BodyLimit: MAX_BODY_BYTES ограничивает сжатое тело, а распакованное — отдельный предел
+	api.Use(handler.DecompressionMiddleware(r.cfg.maxDecompressed, r.logger))
+	if r.cfg.readOnly != nil {
+		// Переключатель режима не блокируется, иначе режим нельзя было бы выключить
+		api.Use(handler.ReadOnly(r.cfg.readOnly, "/api/v1/admin/readonly"))
+	}
+	if r.cfg.auditRecorder != nil {
+		api.Use(handler.APIAudit(r.cfg.auditRecorder, r.cfg.auditRunner, r.logger))
+	}
 	{
-		songs := api.Group("/songs")
+		songs := api.Group("/songs", handler.CacheControl(r.cfg.cache.ItemMaxAge), r.songHandler.ResolvePublicID)
 		{
-			songs.GET("", r.songHandler.GetSongs)
+			songs.GET("", handler.CacheControl(r.cfg.cache.ListMaxAge), r.songHandler.GetSongs)
 			songs.POST("", r.songHandler.CreateSong)
+			songs.POST("/bulk", r.songHandler.BulkCreateSongs)
+			songs.POST("/merge", r.songHandler.MergeSongs)
+			songs.POST("/import-one", r.songHandler.ImportSong)
+			songs.GET("/export", handler.CacheControl(r.cfg.cache.ListMaxAge), r.songHandler.ExportLibrary)
+			songs.GET("/:id/export", r.songHandler.ExportSong)
 			songs.GET("/:id", r.songHandler.GetSongByID)
 			songs.PUT("/:id", r.songHandler.UpdateSong)
 			songs.DELETE("/:id", r.songHandler.DeleteSong)
+			songs.GET("/deleted", r.songHandler.GetDeletedSongs)
+			songs.POST("/:id/restore", r.songHandler.RestoreSong)
 			songs.GET("/:id/verses", r.songHandler.GetSongVerses)
+			songs.PUT("/:id/verses/order", r.songHandler.ReorderVerses)
+			songs.PUT("/:id/verses/:n", r.songHandler.UpdateVerse)
+			songs.DELETE("/:id/verses/:n", r.songHandler.DeleteVerse)
+			songs.GET("/total-duration", r.songHandler.GetTotalDuration)
+			songs.GET("/tempo-distribution", r.songHandler.GetTempoDistribution)
+			songs.GET("/most-accessed", r.songHandler.GetMostAccessedSongs)
+			songs.GET("/trending", r.songHandler.GetTrendingSongs)
+			songs.GET("/duplicates", r.songHandler.FindDuplicates)
+			songs.GET("/by-copyright", handler.CacheControl(r.cfg.cache.ListMaxAge), r.songHandler.GetSongsByCopyright)
+			songs.GET("/:id/access-log", r.songHandler.GetAccessLog)
+			songs.GET("/:id/word-frequency", r.songHandler.GetWordFrequency)
+			songs.GET("/:id/formatted", r.songHandler.GetFormattedText)
+			songs.PATCH("/:id/duration", r.songHandler.UpdateSongDuration)
+			songs.PATCH("/:id/bpm", r.songHandler.UpdateSongBPM)
+			songs.PATCH("/:id/chart", r.songHandler.UpdateSongChart)
+			songs.GET("/chart-toppers", r.songHandler.GetChartToppers)
+			songs.PATCH("/:id/copyright", r.songHandler.UpdateSongCopyright)
+			songs.PATCH("/:id/featured-artists", r.songHandler.UpdateFeaturedArtists)
+
+			if bookmarks := r.cfg.bookmarkHandler; bookmarks != nil {
+				songs.GET("/bookmarks", bookmarks.GetBookmarks)
+				songs.POST("/:id/bookmark", bookmarks.AddBookmark)
+				songs.DELETE("/:id/bookmark", bookmarks.RemoveBookmark)
+				songs.POST("/:id/verses/:n/bookmark", bookmarks.AddVerseBookmark)
+				songs.DELETE("/:id/verses/:n/bookmark", bookmarks.RemoveVerseBookmark)
+			}
+
+			if history := r.cfg.historyHandler; history != nil {
+				songs.GET("/:id/history", history.GetSongHistory)
+			}
+		}
+
+		if bookmarks := r.cfg.bookmarkHandler; bookmarks != nil {
+			api.GET("/bookmarks", bookmarks.ListVerseBookmarks)
+		}
+
+		groups := api.Group("/groups")
+		groups.POST("/:name/rename", r.songHandler.RenameGroup)
+		groups.PATCH("/:name/rename", r.songHandler.RenameGroup)
+		groups.GET("/:name/songs", handler.CacheControl(r.cfg.cache.ListMaxAge), r.songHandler.GetGroupSongs)
+		groups.GET("/:name/songbook", r.songHandler.GetGroupSongbook)
+		groups.GET("/:name/top-songs", r.songHandler.GetGroupTopSongs)
+		groups.GET("/:name/stats", r.songHandler.GetGroupStats)
+		groups.GET("/:name/info", r.songHandler.GetGroupInfo)
+		groups.PUT("/:name/info", r.songHandler.PutGroupInfo)
+
+		if r.cfg.statsHandler != nil {
+			stats := api.Group("/stats")
+			stats.GET("/growth", r.cfg.statsHandler.GetGrowth)
+			stats.GET("/groups/top", r.cfg.statsHandler.GetTopGroups)
+			stats.GET("/enrichment", r.cfg.statsHandler.GetEnrichmentStatuses)
+		}
+		if r.cfg.snapshotHandler != nil {
+			api.GET("/stats/snapshots", r.cfg.snapshotHandler.GetStatus)
+		}
+
+		if r.cfg.adminHandler != nil {
+			admin := api.Group("/admin", r.cfg.adminHandler.RequireAPIKey())
+			admin.GET("/events", r.cfg.adminHandler.ListEvents)
+			admin.GET("/audit", r.cfg.adminHandler.ListAPICalls)
+			admin.GET("/history", r.cfg.adminHandler.ListHistory)
+			if r.cfg.trashHandler != nil {
+				admin.DELETE("/trash", r.cfg.trashHandler.PurgeTrash)
+			}
+			if r.cfg.readOnlyHandler != nil {
+				admin.PUT("/readonly", r.cfg.readOnlyHandler.SetReadOnly)
+			}
 		}
 	}
 
-	r.engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	if r.cfg.swagger {
+		r.engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	}
+	if r.cfg.pprof {
+		debug := r.engine.Group("/debug/pprof")
+		debug.GET("/", gin.WrapF(pprof.Index))
+		debug.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+		debug.GET("/profile", gin.WrapF(pprof.Profile))
+		debug.GET("/symbol", gin.WrapF(pprof.Symbol))
+		debug.POST("/symbol", gin.WrapF(pprof.Symbol))
+		debug.GET("/trace", gin.WrapF(pprof.Trace))
+		// Именованные профили (heap, goroutine, allocs и др.) обслуживает pprof.Index
+		debug.GET("/:name", gin.WrapF(pprof.Index))
+	}
+	r.engine.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	if r.cfg.readyHandler != nil {
+		r.engine.GET("/readyz", r.cfg.readyHandler.Ready)
+	}
+	if r.cfg.externalAPI != nil {
+		// Доступен без ключа API: проверку выполняют агенты мониторинга
+		r.engine.GET("/health/external-api", r.cfg.externalAPI.GetExternalAPIHealth)
+	}
+	if r.cfg.poolHandler != nil && r.cfg.adminHandler != nil {
+		health := r.engine.Group("/health", r.cfg.adminHandler.RequireAPIKey())
+		health.GET("/db-pool", r.cfg.poolHandler.GetDBPool)
+	}
 }
 
 // GetEngine возвращает настроенный экземпляр gin.Engine
@@ -2,22 +2,30 @@ package api
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"os"
 	"song-library/pkg/logger"
+	"strings"
+	"syscall"
 	"time"
 )
 
 // Server представляет HTTP сервер приложения
 type Server struct {
 	httpServer *http.Server
+	// socketPath путь к Unix-сокету; пустой, если сервер слушает TCP-адрес
+	socketPath string
 	logger     *logger.Logger
 }
 
-// NewServer создает новый экземпляр сервера
-func NewServer(router *Router, port string, logger *logger.Logger) *Server {
-	return &Server{
+// NewServer создает новый экземпляр сервера.
+// Если host начинается с "/", сервер слушает Unix-сокет по этому пути, а port не используется.
+func NewServer(router *Router, host, port string, logger *logger.Logger) *Server {
+	server := &Server{
 		httpServer: &http.Server{
-			Addr:           ":" + port,
 			Handler:        router.GetEngine(),
 			ReadTimeout:    10 * time.Second,
 			WriteTimeout:   10 * time.Second,
@@ -25,12 +33,56 @@ func NewServer(router *Router, port string, logger *logger.Logger) *Server {
 		},
 		logger: logger,
 	}
+
+	if strings.HasPrefix(host, "/") {
+		server.socketPath = host
+	} else {
+		server.httpServer.Addr = net.JoinHostPort(host, port)
+	}
+	return server
 }
 
 // Run запускает HTTP сервер
 func (s *Server) Run() error {
-	s.logger.Info("Запуск HTTP сервера", "port", s.httpServer.Addr)
-	return s.httpServer.ListenAndServe()
+	if s.socketPath == "" {
+		s.logger.Info("Запуск HTTP сервера", "addr", s.httpServer.Addr)
+		return s.httpServer.ListenAndServe()
+	}
+
+	s.logger.Info("Запуск HTTP сервера на Unix-сокете", "path", s.socketPath)
+
+	if err := removeStaleSocket(s.socketPath); err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("unix", s.socketPath)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия Unix-сокета: %w", err)
+	}
+	return s.httpServer.Serve(listener)
+}
+
+// removeStaleSocket удаляет сокет, оставшийся после аварийного завершения и мешающий повторному запуску.
+// Сокет удаляется, только если к нему никто не принимает подключения: сокет работающего экземпляра не трогается.
+func removeStaleSocket(path string) error {
+	info, err := os.Stat(path)
+	if err != nil || info.Mode()&os.ModeSocket == 0 {
+		return nil
+	}
+
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err == nil {
+		conn.Close()
+		return fmt.Errorf("сокет %s уже обслуживается другим процессом", path)
+	}
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		return fmt.Errorf("ошибка проверки старого сокета: %w", err)
+	}
+
+	if err = os.Remove(path); err != nil {
+		return fmt.Errorf("ошибка удаления старого сокета: %w", err)
+	}
+	return nil
 }
 
 // Shutdown останавливает HTTP сервер
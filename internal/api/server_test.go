@@ -0,0 +1,156 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"song-library/pkg/logger"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newSocketTestServer создает сервер на Unix-сокете path с единственным маршрутом GET /ping
+func newSocketTestServer(path string) *Server {
+	gin.SetMode(gin.TestMode)
+	log := &logger.Logger{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	router := NewRouter(nil, log)
+	router.GetEngine().GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+	return NewServer(router, path, "8080", log)
+}
+
+// socketPath возвращает путь к сокету во временном каталоге. Каталог создается вне t.TempDir,
+// потому что длина пути Unix-сокета ограничена, а t.TempDir содержит имя теста
+func socketPath(t *testing.T) string {
+	t.Helper()
+
+	dir, err := os.MkdirTemp("", "sock")
+	if err != nil {
+		t.Fatalf("ошибка создания временного каталога: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "api.sock")
+}
+
+// getPing выполняет GET /ping через Unix-сокет path
+func getPing(path string) (string, error) {
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", path)
+		},
+	}}
+	resp, err := client.Get("http://unix/ping")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	return string(body), err
+}
+
+// waitForPing ожидает, пока сервер на Unix-сокете path начнет отвечать, и возвращает тело ответа /ping
+func waitForPing(t *testing.T, path string) string {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		body, err := getPing(path)
+		if err == nil {
+			return body
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("сервер не отвечает на %s: %v", path, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestNewServerBindingMode(t *testing.T) {
+	tests := []struct {
+		name       string
+		host       string
+		wantAddr   string
+		wantSocket string
+	}{
+		{"IPv4", "127.0.0.1", "127.0.0.1:8080", ""},
+		{"IPv6", "::1", "[::1]:8080", ""},
+		{"имя хоста", "localhost", "localhost:8080", ""},
+		{"Unix-сокет", "/run/song-library/api.sock", "", "/run/song-library/api.sock"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			log := &logger.Logger{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+			server := NewServer(NewRouter(nil, log), tt.host, "8080", log)
+			if server.httpServer.Addr != tt.wantAddr || server.socketPath != tt.wantSocket {
+				t.Errorf("NewServer(%q) addr = %q, socket = %q, want %q, %q",
+					tt.host, server.httpServer.Addr, server.socketPath, tt.wantAddr, tt.wantSocket)
+			}
+		})
+	}
+}
+
+func TestServerRunUnixSocket(t *testing.T) {
+	tests := []struct {
+		name string
+		// prepare готовит путь сокета до запуска сервера
+		prepare func(t *testing.T, path string)
+	}{
+		{"сокета нет", func(*testing.T, string) {}},
+		{"старый сокет после аварийного завершения", func(t *testing.T, path string) {
+			listener, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+			if err != nil {
+				t.Fatalf("ошибка создания сокета: %v", err)
+			}
+			// Процесс завершился, не удалив файл сокета
+			listener.SetUnlinkOnClose(false)
+			listener.Close()
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := socketPath(t)
+			tt.prepare(t, path)
+			server := newSocketTestServer(path)
+
+			errCh := make(chan error, 1)
+			go func() { errCh <- server.Run() }()
+
+			if body := waitForPing(t, path); body != "pong" {
+				t.Errorf("GET /ping = %q, want %q", body, "pong")
+			}
+
+			if err := server.Shutdown(context.Background()); err != nil {
+				t.Fatalf("Shutdown() error = %v", err)
+			}
+			if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+				t.Errorf("Run() error = %v, want %v", err, http.ErrServerClosed)
+			}
+		})
+	}
+}
+
+func TestServerRunDoesNotStealLiveSocket(t *testing.T) {
+	path := socketPath(t)
+	running := newSocketTestServer(path)
+	go running.Run()
+	defer running.Shutdown(context.Background())
+
+	waitForPing(t, path)
+
+	err := newSocketTestServer(path).Run()
+	if err == nil || !strings.Contains(err.Error(), "уже обслуживается") {
+		t.Fatalf("Run() второго сервера error = %v, want отказ занять сокет", err)
+	}
+	if body, err := getPing(path); err != nil || body != "pong" {
+		t.Errorf("первый сервер после запуска второго: %q, %v", body, err)
+	}
+}
@@ -3,20 +3,108 @@ package config
 import (
 	"fmt"
 	"github.com/joho/godotenv"
+	"net"
+	"net/http"
 	"os"
+	"regexp"
+	"song-library/internal/model"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Config содержит все настройки приложения
 type Config struct {
-	ServerPort     string
-	DBHost         string
-	DBPort         string
-	DBUser         string
-	DBPassword     string
-	DBName         string
-	ExternalAPIURL string
-	LogLevel       string
-	Environment    string
+	ServerHost        string
+	ServerPort        string
+	DBHost            string
+	DBPort            string
+	DBUser            string
+	DBPassword        string
+	DBName            string
+	DBReadHost        string
+	DBReadPort        string
+	ExternalAPIURL    string
+	ExternalAPIBudget time.Duration
+	ExternalAPICache  int
+	ExternalAPITTL    time.Duration
+	MaxTextLength     int
+	MaxLinkLength     int
+	MaxBodyBytes      int
+	MaxDecompressed   int
+	StrictUTF8        bool
+	StopwordsFile     string
+	LogLevel          string
+	LogFormat         string
+	Environment       string
+	BookmarkSecret    string
+
+	// ExternalAPIHeaders заголовки, добавляемые к каждому запросу к внешнему API
+	ExternalAPIHeaders map[string]string
+	// ExternalAPIBasicUser и ExternalAPIBasicPass учетные данные HTTP Basic Auth внешнего API; пустой пользователь — без авторизации
+	ExternalAPIBasicUser string
+	ExternalAPIBasicPass string
+
+	EnableSwagger bool
+	EnablePprof   bool
+
+	DBHealthCheckInterval            time.Duration
+	DBHealthCheckConsecutiveFailures int
+
+	CacheListMaxAge time.Duration
+	CacheItemMaxAge time.Duration
+
+	// RequestTimeout максимальное время обработки запроса API (0 — без ограничения)
+	RequestTimeout time.Duration
+	// LogRequests включает логирование каждого HTTP-запроса с размерами тел запроса и ответа
+	LogRequests bool
+
+	DisableAccessLog  bool
+	CopyThreshold     int
+	DBApplicationName string
+	DBQueryComments   bool
+	DBRetryMax        int
+	DBRetryDelay      time.Duration
+	DBMaxOpenConns    int
+	DBMaxIdleConns    int
+
+	WorkerPoolSize  int
+	WorkerQueueSize int
+
+	DefaultSongsPageSize  int
+	MaxSongsPageSize      int
+	DefaultVersesPageSize int
+	MaxVersesPageSize     int
+	PaginationWarnOffset  int
+	MaxPaginationOffset   int
+
+	MaxSongsForDuplicateCheck int
+
+	InProcessCacheSize int
+	InProcessCacheTTL  time.Duration
+
+	StatsCacheStaleWindow time.Duration
+
+	ReadOnly bool
+
+	AdminAPIKey          string
+	EventRetentionDays   int
+	EventCleanupInterval time.Duration
+
+	SnapshotEnabled       bool
+	SnapshotInterval      time.Duration
+	SnapshotDir           string
+	SnapshotRetentionDays int
+
+	TrashRetention      time.Duration
+	TrashPurgeInterval  time.Duration
+	TrashPurgeBatchSize int
+	TrashPurgeMaxPerRun int
+
+	SelfCheckTimeout     time.Duration
+	SelfCheckExternalAPI bool
+
+	ExternalAPIProbeOnStart bool
 }
 
 // LoadConfig загружает конфигурацию из .env файла
@@ -25,17 +113,147 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("ошибка загрузки .env файла: %w", err)
 	}
 
-	return &Config{
-		ServerPort:     getEnv("SERVER_PORT", "8080"),
-		DBHost:         getEnv("DB_HOST", "localhost"),
-		DBPort:         getEnv("DB_PORT", "5432"),
-		DBUser:         getEnv("DB_USER", "postgres"),
-		DBPassword:     getEnv("DB_PASSWORD", "postgres"),
-		DBName:         getEnv("DB_NAME", "song_library"),
-		ExternalAPIURL: getEnv("EXTERNAL_API_URL", "http://localhost:8081"),
-		LogLevel:       getEnv("LOG_LEVEL", "info"),
-		Environment:    getEnv("ENVIRONMENT", "development"),
-	}, nil
+	environment := getEnv("ENVIRONMENT", EnvironmentDevelopment)
+	prof, err := profileFor(environment)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &envReader{}
+	cfg := &Config{
+		ServerHost:        getEnv("SERVER_HOST", "0.0.0.0"),
+		ServerPort:        getEnv("SERVER_PORT", "8080"),
+		DBHost:            getEnv("DB_HOST", "localhost"),
+		DBPort:            getEnv("DB_PORT", "5432"),
+		DBUser:            getEnv("DB_USER", "postgres"),
+		DBPassword:        getEnv("DB_PASSWORD", "postgres"),
+		DBName:            getEnv("DB_NAME", "song_library"),
+		DBReadHost:        getEnv("DB_READ_HOST", ""),
+		ExternalAPIURL:    getEnv("EXTERNAL_API_URL", "http://localhost:8081"),
+		ExternalAPIBudget: env.duration("EXTERNAL_API_BUDGET", 5*time.Second),
+		ExternalAPICache:  env.nonNegativeInt("EXTERNAL_API_CACHE_SIZE", 1000),
+		ExternalAPITTL:    env.seconds("EXTERNAL_API_CACHE_TTL_SECONDS", 3600),
+		MaxTextLength:     env.nonNegativeInt("MAX_TEXT_LENGTH", 100*1024),
+		MaxLinkLength:     env.nonNegativeInt("MAX_LINK_LENGTH", 2048),
+		MaxBodyBytes:      env.nonNegativeInt("MAX_BODY_BYTES", 10*1024*1024),
+		MaxDecompressed:   env.nonNegativeInt("MAX_DECOMPRESSED_BODY_BYTES", 10*1024*1024),
+		StrictUTF8:        env.boolean("STRICT_UTF8", false),
+		StopwordsFile:     getEnv("STOPWORDS_FILE", ""),
+		LogLevel:          getEnv("LOG_LEVEL", prof.logLevel),
+		LogFormat:         getEnv("LOG_FORMAT", prof.logFormat),
+		Environment:       environment,
+		BookmarkSecret:    getEnv("BOOKMARK_SECRET", ""),
+
+		ExternalAPIHeaders:   env.headers("EXTERNAL_API_HEADERS"),
+		ExternalAPIBasicUser: getEnv("EXTERNAL_API_BASIC_USER", ""),
+		ExternalAPIBasicPass: getEnv("EXTERNAL_API_BASIC_PASS", ""),
+
+		EnableSwagger: env.boolean("ENABLE_SWAGGER", prof.enableSwagger),
+		EnablePprof:   env.boolean("ENABLE_PPROF", prof.enablePprof),
+
+		DBHealthCheckInterval:            env.seconds("DB_HEALTH_CHECK_INTERVAL_SECONDS", 30),
+		DBHealthCheckConsecutiveFailures: env.positiveInt("DB_HEALTH_CHECK_CONSECUTIVE_FAILURES", 3),
+
+		CacheListMaxAge: time.Duration(env.nonNegativeInt("CACHE_LIST_MAX_AGE_SECONDS", 30)) * time.Second,
+		CacheItemMaxAge: time.Duration(env.nonNegativeInt("CACHE_ITEM_MAX_AGE_SECONDS", 0)) * time.Second,
+
+		RequestTimeout: time.Duration(env.nonNegativeInt("REQUEST_TIMEOUT_SECONDS", 0)) * time.Second,
+		LogRequests:    env.boolean("LOG_REQUESTS", true),
+
+		DisableAccessLog:  env.boolean("DISABLE_ACCESS_LOG", false),
+		CopyThreshold:     env.positiveInt("COPY_THRESHOLD", 100),
+		DBApplicationName: getEnv("DB_APPLICATION_NAME", "song-library"),
+		DBQueryComments:   env.boolean("DB_QUERY_COMMENTS", false),
+		DBRetryMax:        env.nonNegativeInt("DB_RETRY_MAX", 3),
+		DBRetryDelay:      time.Duration(env.nonNegativeInt("DB_RETRY_DELAY_MS", 200)) * time.Millisecond,
+		DBMaxOpenConns:    env.nonNegativeInt("DB_MAX_OPEN_CONNS", 0),
+		DBMaxIdleConns:    env.nonNegativeInt("DB_MAX_IDLE_CONNS", 2),
+
+		WorkerPoolSize:  env.positiveInt("WORKER_POOL_SIZE", 8),
+		WorkerQueueSize: env.nonNegativeInt("WORKER_QUEUE_SIZE", 256),
+
+		DefaultSongsPageSize:  env.positiveInt("DEFAULT_SONGS_PAGE_SIZE", 10),
+		MaxSongsPageSize:      env.positiveInt("MAX_SONGS_PAGE_SIZE", 100),
+		DefaultVersesPageSize: env.positiveInt("DEFAULT_VERSES_PAGE_SIZE", 5),
+		MaxVersesPageSize:     env.positiveInt("MAX_VERSES_PAGE_SIZE", 50),
+		PaginationWarnOffset:  env.nonNegativeInt("PAGINATION_WARN_OFFSET", 10000),
+		MaxPaginationOffset:   env.nonNegativeInt("PAGINATION_MAX_OFFSET", 0),
+
+		MaxSongsForDuplicateCheck: env.positiveInt("MAX_SONGS_FOR_DUPLICATE_CHECK", 1000),
+
+		InProcessCacheSize: env.nonNegativeInt("IN_PROCESS_CACHE_SIZE", 0),
+		InProcessCacheTTL:  env.seconds("IN_PROCESS_CACHE_TTL_SECONDS", 60),
+
+		StatsCacheStaleWindow: env.duration("STATS_CACHE_STALE_WINDOW", 5*time.Minute),
+
+		ReadOnly: env.boolean("READ_ONLY", false),
+
+		AdminAPIKey:          getEnv("ADMIN_API_KEY", ""),
+		EventRetentionDays:   env.positiveInt("EVENT_RETENTION_DAYS", 90),
+		EventCleanupInterval: env.duration("EVENT_CLEANUP_INTERVAL", time.Hour),
+
+		SnapshotEnabled:       env.boolean("SNAPSHOT_ENABLED", false),
+		SnapshotInterval:      env.duration("SNAPSHOT_INTERVAL", 24*time.Hour),
+		SnapshotDir:           getEnv("SNAPSHOT_DIR", "snapshots"),
+		SnapshotRetentionDays: env.positiveInt("SNAPSHOT_RETENTION_DAYS", 7),
+
+		TrashRetention:      env.retention("TRASH_RETENTION"),
+		TrashPurgeInterval:  env.duration("TRASH_PURGE_INTERVAL", time.Hour),
+		TrashPurgeBatchSize: env.positiveInt("TRASH_PURGE_BATCH_SIZE", 500),
+		TrashPurgeMaxPerRun: env.positiveInt("TRASH_PURGE_MAX_PER_RUN", 10000),
+
+		SelfCheckTimeout:     env.duration("SELFCHECK_TIMEOUT", 5*time.Second),
+		SelfCheckExternalAPI: env.boolean("SELFCHECK_EXTERNAL_API", true),
+
+		ExternalAPIProbeOnStart: env.boolean("EXTERNAL_API_PROBE_ON_START", false),
+	}
+	if env.err != nil {
+		return nil, env.err
+	}
+	cfg.DBReadPort = getEnv("DB_READ_PORT", cfg.DBPort)
+
+	if err = prof.validate(cfg); err != nil {
+		return nil, err
+	}
+	if !validServerHost(cfg.ServerHost) {
+		return nil, fmt.Errorf("неверное значение SERVER_HOST: %q", cfg.ServerHost)
+	}
+	if cfg.DefaultSongsPageSize > cfg.MaxSongsPageSize {
+		return nil, fmt.Errorf("DEFAULT_SONGS_PAGE_SIZE не может быть больше MAX_SONGS_PAGE_SIZE")
+	}
+	if cfg.DefaultVersesPageSize > cfg.MaxVersesPageSize {
+		return nil, fmt.Errorf("DEFAULT_VERSES_PAGE_SIZE не может быть больше MAX_VERSES_PAGE_SIZE")
+	}
+	if cfg.MaxPaginationOffset > 0 && cfg.PaginationWarnOffset > cfg.MaxPaginationOffset {
+		return nil, fmt.Errorf("PAGINATION_WARN_OFFSET не может быть больше PAGINATION_MAX_OFFSET")
+	}
+	if cfg.ExternalAPIBasicUser == "" && cfg.ExternalAPIBasicPass != "" {
+		return nil, fmt.Errorf("EXTERNAL_API_BASIC_PASS задан без EXTERNAL_API_BASIC_USER")
+	}
+	if _, ok := cfg.ExternalAPIHeaders["Authorization"]; ok && cfg.ExternalAPIBasicUser != "" {
+		return nil, fmt.Errorf("EXTERNAL_API_BASIC_USER нельзя сочетать с заголовком Authorization в EXTERNAL_API_HEADERS")
+	}
+
+	return cfg, nil
+}
+
+// hostnameLabel допустимая метка имени хоста (RFC 1123)
+var hostnameLabel = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?$`)
+
+// validServerHost проверяет, что адрес сервера является IP-адресом, именем хоста или путем к Unix-сокету
+func validServerHost(host string) bool {
+	if strings.HasPrefix(host, "/") || net.ParseIP(host) != nil {
+		return true
+	}
+	if len(host) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(host, ".") {
+		if !hostnameLabel.MatchString(label) {
+			return false
+		}
+	}
+	return true
 }
 
 // getEnv получает значение переменной окружения или возвращает значение по умолчанию
@@ -46,3 +264,113 @@ func getEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+// envReader читает типизированные переменные окружения и запоминает первую ошибку разбора
+type envReader struct {
+	err error
+}
+
+// duration получает положительную длительность (например, "5s") или возвращает значение по умолчанию
+func (e *envReader) duration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		e.fail(key, value)
+		return defaultValue
+	}
+	return duration
+}
+
+// retention получает срок хранения в формате model.ParseRetention; пустое значение возвращает 0
+func (e *envReader) retention(key string) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0
+	}
+
+	retention, err := model.ParseRetention(value)
+	if err != nil {
+		e.fail(key, value)
+		return 0
+	}
+	return retention
+}
+
+// seconds получает положительное количество секунд и возвращает его как длительность
+func (e *envReader) seconds(key string, defaultValue int) time.Duration {
+	return time.Duration(e.positiveInt(key, defaultValue)) * time.Second
+}
+
+// positiveInt получает положительное целое число или возвращает значение по умолчанию
+func (e *envReader) positiveInt(key string, defaultValue int) int {
+	number := e.nonNegativeInt(key, defaultValue)
+	if number == 0 {
+		e.fail(key, os.Getenv(key))
+		return defaultValue
+	}
+	return number
+}
+
+// nonNegativeInt получает неотрицательное целое число или возвращает значение по умолчанию
+func (e *envReader) nonNegativeInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 0 {
+		e.fail(key, value)
+		return defaultValue
+	}
+	return number
+}
+
+// boolean получает логическое значение (true/false, 1/0) или возвращает значение по умолчанию
+func (e *envReader) boolean(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	flag, err := strconv.ParseBool(value)
+	if err != nil {
+		e.fail(key, value)
+		return defaultValue
+	}
+	return flag
+}
+
+// headers получает заголовки HTTP в формате ключ1:значение1,ключ2:значение2. Имена приводятся к каноническому виду;
+// значение может содержать двоеточия, но не запятые. В сообщение об ошибке значение не попадает, так как содержит секреты
+func (e *envReader) headers(key string) map[string]string {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		name, headerValue, ok := strings.Cut(pair, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			if e.err == nil {
+				e.err = fmt.Errorf("неверное значение %s: ожидается формат ключ1:значение1,ключ2:значение2", key)
+			}
+			return nil
+		}
+		headers[http.CanonicalHeaderKey(name)] = strings.TrimSpace(headerValue)
+	}
+	return headers
+}
+
+// fail запоминает ошибку разбора, если она еще не была записана
+func (e *envReader) fail(key, value string) {
+	if e.err == nil {
+		e.err = fmt.Errorf("неверное значение %s: %q", key, value)
+	}
+}
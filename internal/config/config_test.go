@@ -0,0 +1,120 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// profileVariables переменные окружения, которые задает профиль; тесты сбрасывают их перед загрузкой
+var profileVariables = []string{"ENVIRONMENT", "LOG_LEVEL", "LOG_FORMAT", "ENABLE_SWAGGER", "ENABLE_PPROF", "ADMIN_API_KEY"}
+
+// loadTestConfig загружает конфигурацию из переменных env и пустого .env во временном каталоге
+func loadTestConfig(t *testing.T, env map[string]string) (*Config, error) {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".env"), nil, 0o600); err != nil {
+		t.Fatalf("ошибка создания .env: %v", err)
+	}
+	t.Chdir(dir)
+	for _, key := range profileVariables {
+		t.Setenv(key, "")
+	}
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+	return LoadConfig()
+}
+
+func TestLoadConfigProfiles(t *testing.T) {
+	tests := []struct {
+		name          string
+		env           map[string]string
+		wantLogLevel  string
+		wantLogFormat string
+		wantSwagger   bool
+		wantPprof     bool
+	}{
+		{"окружение по умолчанию", nil, "debug", "text", true, true},
+		{"development", map[string]string{"ENVIRONMENT": "development"}, "debug", "text", true, true},
+		{"production", map[string]string{"ENVIRONMENT": "production", "ADMIN_API_KEY": "secret"}, "info", "json", false, false},
+		{"test", map[string]string{"ENVIRONMENT": "test"}, "warn", "text", true, false},
+		{"переменные переопределяют профиль production", map[string]string{
+			"ENVIRONMENT": "production", "ADMIN_API_KEY": "secret", "LOG_LEVEL": "debug", "LOG_FORMAT": "text",
+			"ENABLE_SWAGGER": "true", "ENABLE_PPROF": "1",
+		}, "debug", "text", true, true},
+		{"переменные переопределяют профиль development", map[string]string{
+			"ENVIRONMENT": "development", "LOG_LEVEL": "error", "LOG_FORMAT": "json", "ENABLE_SWAGGER": "false", "ENABLE_PPROF": "0",
+		}, "error", "json", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := loadTestConfig(t, tt.env)
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if cfg.LogLevel != tt.wantLogLevel || cfg.LogFormat != tt.wantLogFormat {
+				t.Errorf("LogLevel, LogFormat = %q, %q, want %q, %q", cfg.LogLevel, cfg.LogFormat, tt.wantLogLevel, tt.wantLogFormat)
+			}
+			if cfg.EnableSwagger != tt.wantSwagger || cfg.EnablePprof != tt.wantPprof {
+				t.Errorf("EnableSwagger, EnablePprof = %v, %v, want %v, %v", cfg.EnableSwagger, cfg.EnablePprof, tt.wantSwagger, tt.wantPprof)
+			}
+		})
+	}
+}
+
+func TestLoadConfigProfileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string
+	}{
+		{"production без ADMIN_API_KEY", map[string]string{"ENVIRONMENT": "production"}, "ADMIN_API_KEY обязателен"},
+		{"неизвестное окружение", map[string]string{"ENVIRONMENT": "staging"}, "неверное значение ENVIRONMENT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadTestConfig(t, tt.env)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadConfig() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidServerHost(t *testing.T) {
+	tests := []struct {
+		name string
+		host string
+		want bool
+	}{
+		{"IPv4", "127.0.0.1", true},
+		{"все интерфейсы IPv4", "0.0.0.0", true},
+		{"IPv6", "::1", true},
+		{"полный IPv6", "2001:db8::8a2e:370:7334", true},
+		{"имя хоста", "localhost", true},
+		{"полное имя хоста", "api.song-library.example.com", true},
+		{"абсолютный путь к сокету", "/run/song-library/api.sock", true},
+		{"пустая строка", "", false},
+		{"относительный путь", "run/api.sock", false},
+		{"хост с портом", "localhost:8080", false},
+		{"IPv6 в скобках", "[::1]", false},
+		{"пробел", "local host", false},
+		{"метка с дефисом в начале", "-api.example.com", false},
+		{"пустая метка", "api..example.com", false},
+		{"схема", "http://localhost", false},
+		{"слишком длинное имя", strings.Repeat("a.", 127) + "a", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validServerHost(tt.host); got != tt.want {
+				t.Errorf("validServerHost(%q) = %v, want %v", tt.host, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package config
+
+import "fmt"
+
+// Окружения приложения
+const (
+	EnvironmentDevelopment = "development"
+	EnvironmentProduction  = "production"
+	EnvironmentTest        = "test"
+)
+
+// profile значения по умолчанию для окружения. Каждое из них можно переопределить
+// соответствующей переменной окружения.
+type profile struct {
+	logLevel      string
+	logFormat     string
+	enableSwagger bool
+	enablePprof   bool
+	// requireAdminKey запрещает запуск без ADMIN_API_KEY
+	requireAdminKey bool
+}
+
+// profileFor возвращает профиль окружения
+func profileFor(environment string) (profile, error) {
+	switch environment {
+	case EnvironmentDevelopment:
+		return profile{logLevel: "debug", logFormat: "text", enableSwagger: true, enablePprof: true}, nil
+	case EnvironmentProduction:
+		return profile{logLevel: "info", logFormat: "json", requireAdminKey: true}, nil
+	case EnvironmentTest:
+		return profile{logLevel: "warn", logFormat: "text", enableSwagger: true}, nil
+	default:
+		return profile{}, fmt.Errorf("неверное значение ENVIRONMENT: %q (ожидается %s, %s или %s)",
+			environment, EnvironmentDevelopment, EnvironmentProduction, EnvironmentTest)
+	}
+}
+
+// validate проверяет настройки, обязательные для профиля
+func (p profile) validate(cfg *Config) error {
+	if p.requireAdminKey && cfg.AdminAPIKey == "" {
+		return fmt.Errorf("ADMIN_API_KEY обязателен в окружении %s", cfg.Environment)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package formatter
+
+import (
+	"song-library/internal/model"
+	"strings"
+)
+
+// markdownEscaper экранирует символы, имеющие особое значение в Markdown
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `{`, `\{`, `}`, `\}`, `[`, `\[`, `]`, `\]`,
+	`<`, `\<`, `>`, `\>`, `(`, `\(`, `)`, `\)`, `#`, `\#`, `+`, `\+`, `-`, `\-`, `.`, `\.`,
+	`!`, `\!`, `|`, `\|`, `~`, `\~`,
+)
+
+// EscapeMarkdown экранирует специальные символы Markdown, чтобы строка выводилась как обычный текст
+func EscapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// ToMarkdown возвращает песню в виде документа Markdown: заголовок с названием и группой,
+// дата выпуска и ссылка, если они заполнены, и текст, куплеты которого разделены пустой строкой
+func ToMarkdown(song *model.Song) string {
+	var b strings.Builder
+
+	b.WriteString("# " + EscapeMarkdown(song.Song) + " by " + EscapeMarkdown(song.Group) + "\n")
+
+	var meta []string
+	if song.ReleaseDate != "" {
+		meta = append(meta, "**Release Date:** "+song.ReleaseDate)
+	}
+	if song.Link != "" {
+		meta = append(meta, "**Link:** <"+song.Link+">")
+	}
+	if len(meta) > 0 {
+		b.WriteString("\n" + strings.Join(meta, "\n") + "\n")
+	}
+
+	b.WriteString("\n## Lyrics\n")
+	for _, verse := range strings.Split(strings.ReplaceAll(song.Text, "\r\n", "\n"), model.VerseDelimiter) {
+		if verse = strings.Trim(verse, "\n"); verse != "" {
+			b.WriteString("\n" + verse + "\n")
+		}
+	}
+
+	return b.String()
+}
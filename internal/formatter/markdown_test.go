@@ -0,0 +1,74 @@
+package formatter
+
+import (
+	"song-library/internal/model"
+	"testing"
+)
+
+func TestEscapeMarkdown(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"обычный текст", "Muse", "Muse"},
+		{"кириллица", "Ёлка", "Ёлка"},
+		{"выделение и ссылки", "*Hello* [world](url)", `\*Hello\* \[world\]\(url\)`},
+		{"заголовок и список", "# Title - item + item", `\# Title \- item \+ item`},
+		{"обратная косая черта экранируется первой", `a\*b`, `a\\\*b`},
+		{"таблица и код", "a|b `c` ~d~", "a\\|b \\`c\\` \\~d\\~"},
+		{"HTML", "<b>!</b>", `\<b\>\!\</b\>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EscapeMarkdown(tt.value); got != tt.want {
+				t.Errorf("EscapeMarkdown(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		song model.Song
+		want string
+	}{
+		{
+			name: "все поля",
+			song: model.Song{Group: "Muse", Song: "Hysteria", ReleaseDate: "01.12.2003", Link: "https://example.com/hysteria",
+				Text: "It's bugging me\nGrating me\n\nAnd twisting me around"},
+			want: "# Hysteria by Muse\n\n**Release Date:** 01.12.2003\n**Link:** <https://example.com/hysteria>\n\n## Lyrics\n\n" +
+				"It's bugging me\nGrating me\n\nAnd twisting me around\n",
+		},
+		{
+			name: "без даты и ссылки",
+			song: model.Song{Group: "Muse", Song: "Hysteria", Text: "It's bugging me"},
+			want: "# Hysteria by Muse\n\n## Lyrics\n\nIt's bugging me\n",
+		},
+		{
+			name: "без текста",
+			song: model.Song{Group: "Muse", Song: "Hysteria", Link: "https://example.com/hysteria"},
+			want: "# Hysteria by Muse\n\n**Link:** <https://example.com/hysteria>\n\n## Lyrics\n",
+		},
+		{
+			name: "название и группа экранируются",
+			song: model.Song{Group: "AC/DC", Song: "T.N.T.", Text: "Oi"},
+			want: "# T\\.N\\.T\\. by AC/DC\n\n## Lyrics\n\nOi\n",
+		},
+		{
+			name: "переводы строк Windows и лишние пустые куплеты",
+			song: model.Song{Group: "Muse", Song: "Hysteria", Text: "first\r\nline\r\n\r\n\r\n\r\nsecond\r\n"},
+			want: "# Hysteria by Muse\n\n## Lyrics\n\nfirst\nline\n\nsecond\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToMarkdown(&tt.song); got != tt.want {
+				t.Errorf("ToMarkdown() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
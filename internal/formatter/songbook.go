@@ -0,0 +1,107 @@
+package formatter
+
+import (
+	"io"
+	"song-library/internal/model"
+	"strings"
+)
+
+// Форматы сборника песен группы
+const (
+	SongbookFormatText     = "txt"
+	SongbookFormatMarkdown = "md"
+)
+
+// songbookTextSeparator разделитель песен в текстовом сборнике
+var songbookTextSeparator = strings.Repeat("=", 40)
+
+// markdownLinkEscaper кодирует символы, которые обрывают автоссылку Markdown вида <url>
+var markdownLinkEscaper = strings.NewReplacer("<", "%3C", ">", "%3E", " ", "%20")
+
+// SongbookWriter записывает песни одной группы в сборник формата txt или md по одной песне,
+// не накапливая документ в памяти. Название группы выводится заголовком перед первой песней.
+type SongbookWriter struct {
+	w        io.Writer
+	markdown bool
+	count    int
+}
+
+// NewSongbookWriter создает запись сборника в w. Неизвестный формат считается текстовым
+func NewSongbookWriter(w io.Writer, format string) *SongbookWriter {
+	return &SongbookWriter{w: w, markdown: format == SongbookFormatMarkdown}
+}
+
+// Count возвращает количество записанных песен
+func (s *SongbookWriter) Count() int {
+	return s.count
+}
+
+// WriteSong дописывает песню в сборник: заголовок с названием, датой выхода и ссылкой, если они заполнены, и текст
+func (s *SongbookWriter) WriteSong(song *model.Song) error {
+	var b strings.Builder
+	if s.markdown {
+		s.markdownSong(&b, song)
+	} else {
+		s.textSong(&b, song)
+	}
+	if _, err := io.WriteString(s.w, b.String()); err != nil {
+		return err
+	}
+	s.count++
+	return nil
+}
+
+// textSong формирует песню текстового сборника
+func (s *SongbookWriter) textSong(b *strings.Builder, song *model.Song) {
+	if s.count == 0 {
+		b.WriteString(song.Group + "\n\n")
+	} else {
+		b.WriteString("\n" + songbookTextSeparator + "\n\n")
+	}
+
+	b.WriteString(song.Song + "\n")
+	if song.ReleaseDate != "" {
+		b.WriteString("Release Date: " + song.ReleaseDate + "\n")
+	}
+	if song.Link != "" {
+		b.WriteString("Link: " + song.Link + "\n")
+	}
+	if text := strings.Trim(strings.ReplaceAll(song.Text, "\r\n", "\n"), "\n"); text != "" {
+		b.WriteString("\n" + text + "\n")
+	}
+}
+
+// markdownSong формирует песню сборника Markdown. Все пользовательские строки экранируются,
+// а строки куплета завершаются жестким переносом, чтобы Markdown не склеивал их в один абзац
+func (s *SongbookWriter) markdownSong(b *strings.Builder, song *model.Song) {
+	if s.count == 0 {
+		b.WriteString("# " + EscapeMarkdown(song.Group) + "\n\n")
+	} else {
+		b.WriteString("\n---\n\n")
+	}
+
+	b.WriteString("## " + EscapeMarkdown(song.Song) + "\n")
+
+	var meta []string
+	if song.ReleaseDate != "" {
+		meta = append(meta, "**Release Date:** "+EscapeMarkdown(song.ReleaseDate))
+	}
+	if song.Link != "" {
+		meta = append(meta, "**Link:** <"+markdownLinkEscaper.Replace(song.Link)+">")
+	}
+	if len(meta) > 0 {
+		b.WriteString("\n" + strings.Join(meta, "  \n") + "\n")
+	}
+
+	for _, verse := range strings.Split(strings.ReplaceAll(song.Text, "\r\n", "\n"), model.VerseDelimiter) {
+		if verse = strings.Trim(verse, "\n"); verse == "" {
+			continue
+		}
+		lines := strings.Split(verse, "\n")
+		for i, line := range lines {
+			// Отступ в начале строки превратил бы ее в блок кода
+			lines[i] = EscapeMarkdown(strings.TrimLeft(line, " \t"))
+		}
+		b.WriteString("\n" + strings.Join(lines, "  \n") + "\n")
+	}
+}
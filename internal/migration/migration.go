@@ -1,6 +1,7 @@
 package migration
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"song-library/pkg/logger"
@@ -19,24 +20,160 @@ var migrations = []string{
 		updated_at TIMESTAMP NOT NULL,
 		CONSTRAINT unique_group_song UNIQUE (group_name, song_name)
 	);`,
+	`ALTER TABLE songs ADD COLUMN IF NOT EXISTS duration_seconds INT;`,
+	`CREATE TABLE IF NOT EXISTS song_access_log (
+		id BIGSERIAL PRIMARY KEY,
+		song_id BIGINT NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+		action VARCHAR(20) NOT NULL,
+		accessed_at TIMESTAMP NOT NULL
+	);`,
+	`CREATE INDEX IF NOT EXISTS idx_song_access_log_song_accessed ON song_access_log (song_id, accessed_at);`,
+	`CREATE INDEX IF NOT EXISTS idx_song_access_log_accessed ON song_access_log (accessed_at);`,
+	`ALTER TABLE songs ALTER COLUMN link TYPE TEXT;`,
+	`ALTER TABLE songs ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;`,
+	`ALTER TABLE songs ADD COLUMN IF NOT EXISTS merged_into_id INT REFERENCES songs(id) ON DELETE SET NULL;`,
+	`ALTER TABLE songs DROP CONSTRAINT IF EXISTS unique_group_song;`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS unique_group_song_active ON songs (group_name, song_name) WHERE deleted_at IS NULL;`,
+	`CREATE TABLE IF NOT EXISTS song_events (
+		id BIGSERIAL PRIMARY KEY,
+		event_type VARCHAR(50) NOT NULL,
+		song_id BIGINT,
+		actor VARCHAR(255) NOT NULL,
+		payload JSONB NOT NULL,
+		occurred_at TIMESTAMP NOT NULL
+	);`,
+	`CREATE INDEX IF NOT EXISTS idx_song_events_occurred ON song_events (occurred_at);`,
+	`CREATE INDEX IF NOT EXISTS idx_song_events_song_occurred ON song_events (song_id, occurred_at);`,
+	`CREATE TABLE IF NOT EXISTS api_audit (
+		id BIGSERIAL PRIMARY KEY,
+		actor VARCHAR(255) NOT NULL,
+		method VARCHAR(10) NOT NULL,
+		route VARCHAR(255) NOT NULL,
+		song_id BIGINT,
+		status INT NOT NULL,
+		latency_ms BIGINT NOT NULL,
+		request_id VARCHAR(255) NOT NULL,
+		occurred_at TIMESTAMP NOT NULL
+	);`,
+	`CREATE INDEX IF NOT EXISTS idx_api_audit_occurred ON api_audit (occurred_at);`,
+	`CREATE EXTENSION IF NOT EXISTS unaccent;`,
+	`CREATE EXTENSION IF NOT EXISTS pg_trgm;`,
+	`ALTER TABLE songs ADD COLUMN IF NOT EXISTS group_name_norm VARCHAR(255);`,
+	`ALTER TABLE songs ADD COLUMN IF NOT EXISTS song_name_norm VARCHAR(255);`,
+	`UPDATE songs SET group_name_norm = lower(unaccent(group_name)), song_name_norm = lower(unaccent(song_name))
+		WHERE group_name_norm IS NULL OR song_name_norm IS NULL;`,
+	`CREATE INDEX IF NOT EXISTS idx_songs_group_name_norm ON songs USING gin (group_name_norm gin_trgm_ops);`,
+	`CREATE INDEX IF NOT EXISTS idx_songs_song_name_norm ON songs USING gin (song_name_norm gin_trgm_ops);`,
+	`ALTER TABLE songs ADD COLUMN IF NOT EXISTS source JSONB NOT NULL DEFAULT '{}';`,
+	`CREATE INDEX IF NOT EXISTS idx_songs_text_trgm ON songs USING gin (text gin_trgm_ops);`,
+	`ALTER TABLE songs ADD COLUMN IF NOT EXISTS bpm SMALLINT CHECK (bpm BETWEEN 20 AND 300);`,
+	`CREATE TABLE IF NOT EXISTS bookmarks (
+		client_id VARCHAR(64) NOT NULL,
+		song_id BIGINT NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+		verse_position INT NOT NULL CHECK (verse_position > 0),
+		note TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+		PRIMARY KEY (client_id, song_id, verse_position)
+	);`,
+	`CREATE TABLE IF NOT EXISTS groups (
+		name VARCHAR(255) PRIMARY KEY,
+		description TEXT NOT NULL DEFAULT '',
+		country VARCHAR(100) NOT NULL DEFAULT '',
+		formed_year SMALLINT,
+		links TEXT[] NOT NULL DEFAULT '{}',
+		created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+		updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+	);`,
+	`ALTER TABLE songs ADD COLUMN IF NOT EXISTS copyright TEXT;`,
+	`CREATE INDEX IF NOT EXISTS idx_songs_copyright_trgm ON songs USING gin (copyright gin_trgm_ops);`,
+	`ALTER TABLE songs ADD COLUMN IF NOT EXISTS content_hash CHAR(64);`,
+	// Значение совпадает с model.Song.ComputeContentHash: поля, разделенные нулевым байтом
+	`UPDATE songs SET content_hash = encode(sha256(convert_to(group_name, 'UTF8') || '\x00'::bytea
+		|| convert_to(song_name, 'UTF8') || '\x00'::bytea || convert_to(release_date, 'UTF8') || '\x00'::bytea
+		|| convert_to(text, 'UTF8') || '\x00'::bytea || convert_to(link, 'UTF8')), 'hex')
+		WHERE content_hash IS NULL;`,
+	`ALTER TABLE songs ADD COLUMN IF NOT EXISTS featured_artists JSONB NOT NULL DEFAULT '[]';`,
+	`CREATE INDEX IF NOT EXISTS idx_songs_featured_artists ON songs USING gin (featured_artists);`,
+	`ALTER TABLE songs ADD COLUMN IF NOT EXISTS enrichment_status VARCHAR(10) NOT NULL DEFAULT 'pending';`,
+	`ALTER TABLE songs ADD COLUMN IF NOT EXISTS enriched_at TIMESTAMP;`,
+	`UPDATE songs SET enrichment_status = 'ok', enriched_at = created_at
+		WHERE enrichment_status = 'pending' AND enriched_at IS NULL AND source <> '{}'::jsonb;`,
+	`CREATE INDEX IF NOT EXISTS idx_songs_enrichment_not_ok ON songs (enrichment_status) WHERE enrichment_status <> 'ok';`,
+	`ALTER TABLE songs ADD COLUMN IF NOT EXISTS public_id UUID NOT NULL DEFAULT gen_random_uuid();`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_songs_public_id ON songs (public_id);`,
+	`ALTER TABLE songs ADD COLUMN IF NOT EXISTS chart_position SMALLINT;`,
+	`ALTER TABLE songs ADD COLUMN IF NOT EXISTS chart_history JSONB NOT NULL DEFAULT '[]';`,
+	`CREATE INDEX IF NOT EXISTS idx_songs_chart_history ON songs USING gin (chart_history jsonb_path_ops);`,
+}
+
+// Version возвращает версию схемы, которую ожидает приложение, — количество миграций
+func Version() int {
+	return len(migrations)
+}
+
+// createVersionTable создает таблицу примененных миграций: одна строка на каждую примененную миграцию
+const createVersionTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version INT PRIMARY KEY,
+	applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);`
+
+// AppliedVersion возвращает версию схемы по таблице примененных миграций; 0, если миграции не применялись
+func AppliedVersion(ctx context.Context, db *sql.DB) (int, error) {
+	var version int
+	if err := db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("ошибка получения версии схемы: %w", err)
+	}
+	return version, nil
 }
 
-// RunMigrations выполняет все миграции базы данных
+// RunMigrations выполняет миграции базы данных, которые еще не отмечены в schema_migrations.
+// Каждая миграция выполняется в отдельной транзакции вместе с записью ее версии.
+// Миграции идемпотентны, поэтому база, созданная до появления schema_migrations, проходит их повторно без изменений.
 func RunMigrations(db *sql.DB, logger *logger.Logger) error {
 	logger.Info("Запуск миграций базы данных")
 
-	for i, migration := range migrations {
+	if _, err := db.Exec(createVersionTable); err != nil {
+		logger.Error("Ошибка создания таблицы версий схемы", "error", err)
+		return fmt.Errorf("ошибка создания таблицы версий схемы: %w", err)
+	}
+	applied, err := AppliedVersion(context.Background(), db)
+	if err != nil {
+		logger.Error("Ошибка получения версии схемы", "error", err)
+		return err
+	}
+	if applied > len(migrations) {
+		logger.Error("Версия схемы новее приложения", "applied", applied, "expected", len(migrations))
+		return fmt.Errorf("версия схемы %d новее версии приложения %d", applied, len(migrations))
+	}
+
+	for i := applied; i < len(migrations); i++ {
 		logger.Debug("Выполнение миграции", "index", i)
 
-		_, err := db.Exec(migration)
-		if err != nil {
+		if err = applyMigration(db, i); err != nil {
 			logger.Error("Ошибка выполнения миграции", "index", i, "error", err)
 			return fmt.Errorf("ошибка выполнения миграции %d: %w", i, err)
 		}
 
 		logger.Debug("Миграция успешно выполнена", "index", i)
 	}
-	
-	logger.Info("Все миграции успешно выполнены")
+
+	logger.Info("Все миграции успешно выполнены", "version", len(migrations), "applied", len(migrations)-applied)
 	return nil
 }
+
+// applyMigration выполняет миграцию с индексом i и отмечает версию i+1 одной транзакцией
+func applyMigration(db *sql.DB, i int) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.Exec(migrations[i]); err != nil {
+		return err
+	}
+	if _, err = tx.Exec(`INSERT INTO schema_migrations (version) VALUES ($1)`, i+1); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
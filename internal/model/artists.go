@@ -0,0 +1,45 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Artists список исполнителей, хранящийся в колонке JSONB массивом строк
+type Artists []string
+
+// Value сериализует список в JSON для колонки JSONB; nil сохраняется пустым массивом
+func (a Artists) Value() (driver.Value, error) {
+	if a == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]string(a))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сериализации списка исполнителей: %w", err)
+	}
+	return string(data), nil
+}
+
+// Scan читает список из колонки JSONB
+func (a *Artists) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*a = Artists{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("неподдерживаемый тип списка исполнителей: %T", src)
+	}
+
+	artists := Artists{}
+	if err := json.Unmarshal(data, &artists); err != nil {
+		return fmt.Errorf("ошибка разбора списка исполнителей: %w", err)
+	}
+	*a = artists
+	return nil
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// VerseBookmarkInput модель запроса на добавление закладки куплета
+type VerseBookmarkInput struct {
+	Note string `json:"note" example:"Любимый припев"`
+}
+
+// VerseBookmark закладка куплета песни.
+// Verse содержит текущий текст куплета; если после изменения текста куплета с такой позицией
+// больше нет, Stale равно true, а Verse пуст.
+type VerseBookmark struct {
+	SongID        int64     `json:"song_id" db:"song_id" example:"1"`
+	Group         string    `json:"group" db:"group_name" example:"Muse"`
+	Song          string    `json:"song" db:"song_name" example:"Supermassive Black Hole"`
+	VersePosition int       `json:"verse_position" db:"verse_position" example:"2"`
+	Note          string    `json:"note" db:"note" example:"Любимый припев"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at" example:"2024-01-15T10:30:00Z"`
+	Verse         string    `json:"verse" db:"-" example:"Ooh\nYou set my soul alight"`
+	Stale         bool      `json:"stale" db:"-" example:"false"`
+	// Text текст песни для определения куплета; клиенту не передается
+	Text string `json:"-" db:"text"`
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Допустимый диапазон позиции песни в чарте
+const (
+	MinChartPosition = 1
+	MaxChartPosition = 200
+)
+
+// MaxChartHistory количество хранимых снимков позиции в чарте; при превышении удаляются самые старые
+const MaxChartHistory = 52
+
+// ChartEntry снимок позиции песни в чарте на дату
+type ChartEntry struct {
+	Position int16  `json:"position" example:"5"`
+	Date     string `json:"date" example:"2024-01-15"`
+}
+
+// ChartHistory история позиций песни в чарте от старых снимков к новым, хранящаяся в колонке JSONB
+type ChartHistory []ChartEntry
+
+// Value сериализует историю в JSON для колонки JSONB; nil сохраняется пустым массивом
+func (h ChartHistory) Value() (driver.Value, error) {
+	if h == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]ChartEntry(h))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сериализации истории позиций в чарте: %w", err)
+	}
+	return string(data), nil
+}
+
+// Scan читает историю из колонки JSONB
+func (h *ChartHistory) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*h = ChartHistory{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("неподдерживаемый тип истории позиций в чарте: %T", src)
+	}
+
+	history := ChartHistory{}
+	if err := json.Unmarshal(data, &history); err != nil {
+		return fmt.Errorf("ошибка разбора истории позиций в чарте: %w", err)
+	}
+	*h = history
+	return nil
+}
+
+// ChartInput модель для обновления позиции песни в чарте
+type ChartInput struct {
+	Position *int `json:"position" example:"5"`
+}
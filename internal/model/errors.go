@@ -0,0 +1,138 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrSongNotFound возвращается, когда песня с указанным идентификатором не найдена
+	ErrSongNotFound = errors.New("песня не найдена")
+	// ErrSongAlreadyExists возвращается, когда песня с такими группой и названием уже существует
+	ErrSongAlreadyExists = errors.New("песня уже существует")
+	// ErrValidation возвращается, когда входные данные не прошли проверку
+	ErrValidation = errors.New("ошибка валидации")
+	// ErrUpstreamTimeout возвращается, когда внешний API не ответил за отведенное время
+	ErrUpstreamTimeout = errors.New("внешний API не ответил вовремя")
+	// ErrUpstreamFailed возвращается, когда запрос к внешнему API завершился ошибкой
+	ErrUpstreamFailed = errors.New("ошибка внешнего API")
+	// ErrEnrichedFieldProtected возвращается при попытке изменить поле, заполненное поставщиком данных, в защищенном режиме
+	ErrEnrichedFieldProtected = errors.New("поле заполнено поставщиком данных")
+	// ErrServiceBusy возвращается, когда очередь фоновых задач заполнена
+	ErrServiceBusy = errors.New("сервис перегружен")
+	// ErrBookmarkNotFound возвращается, когда закладка куплета не найдена
+	ErrBookmarkNotFound = errors.New("закладка не найдена")
+	// ErrGroupInfoNotFound возвращается, когда у группы нет сведений
+	ErrGroupInfoNotFound = errors.New("сведения о группе не найдены")
+	// ErrVerseNotFound возвращается, когда в песне нет куплета с указанным номером
+	ErrVerseNotFound = errors.New("куплет не найден")
+	// ErrGroupNotFound возвращается, когда у группы нет ни одной песни
+	ErrGroupNotFound = errors.New("группа не найдена")
+)
+
+// NotFoundError ошибка отсутствия песни с указанным идентификатором
+type NotFoundError struct {
+	ID int64
+}
+
+// NewNotFoundError создает ошибку отсутствия песни id
+func NewNotFoundError(id int64) error {
+	return &NotFoundError{ID: id}
+}
+
+// Error возвращает текст ошибки с идентификатором песни
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s: id %d", ErrSongNotFound, e.ID)
+}
+
+// Is позволяет сравнивать ошибку с ErrSongNotFound через errors.Is
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrSongNotFound
+}
+
+// RestoreConflictError ошибка восстановления песни SongID, группа и название которой заняты активной песней ConflictingID
+type RestoreConflictError struct {
+	SongID        int64
+	ConflictingID int64
+}
+
+// Error возвращает текст ошибки с идентификатором конфликтующей песни
+func (e *RestoreConflictError) Error() string {
+	return fmt.Sprintf("песня с такими группой и названием уже существует (id %d)", e.ConflictingID)
+}
+
+// Is позволяет сравнивать ошибку с ErrSongAlreadyExists через errors.Is
+func (e *RestoreConflictError) Is(target error) bool {
+	return target == ErrSongAlreadyExists
+}
+
+// GroupRenameConflictError ошибка переименования группы, при котором названия песен совпадают с песнями новой группы
+type GroupRenameConflictError struct {
+	Conflicts []RenameConflict
+}
+
+// Error возвращает текст ошибки с количеством конфликтов
+func (e *GroupRenameConflictError) Error() string {
+	return fmt.Sprintf("в новой группе уже есть песни с такими названиями: %d", len(e.Conflicts))
+}
+
+// Is позволяет сравнивать ошибку с ErrSongAlreadyExists через errors.Is
+func (e *GroupRenameConflictError) Is(target error) bool {
+	return target == ErrSongAlreadyExists
+}
+
+// ValidationError ошибка валидации с сообщением для клиента
+type ValidationError struct {
+	Message string
+}
+
+// NewValidationError создает новую ошибку валидации
+func NewValidationError(message string) error {
+	return &ValidationError{Message: message}
+}
+
+// Error возвращает текст ошибки
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// Is позволяет сравнивать ошибку с ErrValidation через errors.Is
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// LimitError ошибка превышения максимальной длины поля
+type LimitError struct {
+	Field string
+	Limit int
+}
+
+// Error возвращает текст ошибки с указанием ограничения
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("поле %s превышает максимальную длину %d байт", e.Field, e.Limit)
+}
+
+// VerseOrderError ошибка порядка куплетов, не являющегося перестановкой номеров куплетов песни
+type VerseOrderError struct {
+	Message string
+}
+
+// NewVerseOrderError создает ошибку порядка куплетов
+func NewVerseOrderError(message string) error {
+	return &VerseOrderError{Message: message}
+}
+
+// Error возвращает текст ошибки
+func (e *VerseOrderError) Error() string {
+	return e.Message
+}
+
+// EncodingError ошибка некорректной кодировки значения поля
+type EncodingError struct {
+	Field string
+}
+
+// Error возвращает текст ошибки с указанием поля
+func (e *EncodingError) Error() string {
+	return fmt.Sprintf("поле %s содержит некорректную последовательность UTF-8", e.Field)
+}
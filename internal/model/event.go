@@ -0,0 +1,134 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Типы доменных событий журнала аудита
+const (
+	EventSongCreated          = "song.created"
+	EventSongsBulkCreated     = "songs.bulk_created"
+	EventSongImported         = "song.imported"
+	EventSongUpdated          = "song.updated"
+	EventSongDurationUpdated  = "song.duration_updated"
+	EventSongBPMUpdated       = "song.bpm_updated"
+	EventSongVersesReordered  = "song.verses_reordered"
+	EventSongVerseUpdated     = "song.verse_updated"
+	EventSongVerseDeleted     = "song.verse_deleted"
+	EventSongCopyrightUpdated = "song.copyright_updated"
+	EventSongArtistsUpdated   = "song.featured_artists_updated"
+	EventSongChartUpdated     = "song.chart_updated"
+	EventSongDeleted          = "song.deleted"
+	EventSongMerged           = "song.merged"
+	EventSongRestored         = "song.restored"
+	EventSongGroupRenamed     = "song.group_renamed"
+	EventTrashPurged          = "songs.trash_purged"
+)
+
+// SongEvent запись журнала доменных событий
+type SongEvent struct {
+	ID         int64           `json:"id" db:"id" example:"1"`
+	EventType  string          `json:"event_type" db:"event_type" example:"song.updated"`
+	SongID     *int64          `json:"song_id,omitempty" db:"song_id" example:"1"`
+	Actor      string          `json:"actor" db:"actor" example:"192.0.2.10"`
+	Payload    json.RawMessage `json:"payload" db:"payload" swaggertype:"object"`
+	OccurredAt time.Time       `json:"occurred_at" db:"occurred_at" example:"2024-01-15T10:30:00Z"`
+}
+
+// SongHistoryEntry запись истории изменений песни.
+// Автор изменения заполняется только в административном API.
+// TextDiff и Changes вычисляются при чтении из снимков песни в Payload; со снимками целиком они не заполняются.
+type SongHistoryEntry struct {
+	ID         int64                  `json:"id" example:"1"`
+	SongID     *int64                 `json:"song_id,omitempty" example:"1"`
+	EventType  string                 `json:"event_type" example:"song.updated"`
+	Actor      string                 `json:"actor,omitempty" example:"192.0.2.10"`
+	Payload    json.RawMessage        `json:"payload" swaggertype:"object"`
+	TextDiff   string                 `json:"textDiff,omitempty" example:"--- before\n+++ after\n@@ -1,1 +1,1 @@\n-Ooh baby\n+Oh baby"`
+	Changes    map[string]FieldChange `json:"changes,omitempty"`
+	OccurredAt time.Time              `json:"occurred_at" example:"2024-01-15T10:30:00Z"`
+}
+
+// SongSnapshot состояние полей песни до или после изменения, сохраняемое в событии song.updated
+type SongSnapshot struct {
+	Group       string `json:"group"`
+	Song        string `json:"song"`
+	ReleaseDate string `json:"releaseDate"`
+	Text        string `json:"text"`
+	Link        string `json:"link"`
+}
+
+// NewSongSnapshot возвращает снимок полей песни
+func NewSongSnapshot(song *Song) SongSnapshot {
+	return SongSnapshot{
+		Group:       song.Group,
+		Song:        song.Song,
+		ReleaseDate: song.ReleaseDate,
+		Text:        song.Text,
+		Link:        song.Link,
+	}
+}
+
+// FieldChange старое и новое значение поля песни в истории изменений
+type FieldChange struct {
+	Old string `json:"old" example:"Muse"`
+	New string `json:"new" example:"MUSE"`
+}
+
+// HistoryListResponse страница истории изменений с общим количеством записей
+type HistoryListResponse struct {
+	Items      []SongHistoryEntry `json:"items"`
+	Total      int64              `json:"total" example:"134"`
+	Page       int                `json:"page" example:"1"`
+	PageSize   int                `json:"page_size" example:"50"`
+	TotalPages int                `json:"total_pages" example:"3"`
+}
+
+// EventFilter параметры выборки журнала событий
+type EventFilter struct {
+	SongID    *int64
+	EventType string
+	From      *time.Time
+	To        *time.Time
+	// Field поле песни из HistoryFields: отбираются только события, изменившие его; пустое — без фильтра
+	Field string
+	Pagination
+}
+
+// HistoryFields поля песни, по изменению которых фильтруется история: ключи снимка песни SongSnapshot
+var HistoryFields = []string{"text", "group", "song", "releaseDate", "link"}
+
+// HistoryQuery параметры выборки истории изменений одной песни
+type HistoryQuery struct {
+	// Operation тип изменения (тип события без префикса song.); пустой — все изменения
+	Operation string
+	// Field поле песни из HistoryFields, изменения которого нужно вернуть; пустое — все изменения
+	Field string
+	// Since начало периода включительно; nil — без ограничения
+	Since *time.Time
+	// Full возвращает снимки песни целиком вместо диффа
+	Full bool
+	Pagination
+}
+
+// APICall запись журнала изменяющих вызовов API
+type APICall struct {
+	ID         int64     `json:"id" db:"id" example:"1"`
+	Actor      string    `json:"actor" db:"actor" example:"192.0.2.10"`
+	Method     string    `json:"method" db:"method" example:"PUT"`
+	Route      string    `json:"route" db:"route" example:"/api/v1/songs/:id"`
+	SongID     *int64    `json:"song_id,omitempty" db:"song_id" example:"1"`
+	Status     int       `json:"status" db:"status" example:"200"`
+	LatencyMs  int64     `json:"latency_ms" db:"latency_ms" example:"12"`
+	RequestID  string    `json:"request_id" db:"request_id" example:"3f6c1a9e-7f1b-4c4e-9d2a-1b2c3d4e5f60"`
+	OccurredAt time.Time `json:"occurred_at" db:"occurred_at" example:"2024-01-15T10:30:00Z"`
+}
+
+// APICallFilter параметры выборки журнала вызовов API
+type APICallFilter struct {
+	Since  *time.Time
+	Actor  string
+	SongID *int64
+	Pagination
+}
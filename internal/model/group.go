@@ -0,0 +1,66 @@
+package model
+
+import "time"
+
+// Ограничения сведений о группе
+const (
+	// MinFormedYear самый ранний допустимый год основания группы
+	MinFormedYear = 1000
+	// MaxGroupLinks максимальное количество ссылок группы
+	MaxGroupLinks = 20
+	// MaxGroupCountryLength максимальная длина страны группы в символах
+	MaxGroupCountryLength = 100
+)
+
+// GroupInfo сведения о группе. Песни ссылаются на группу по названию, поэтому сведения необязательны.
+type GroupInfo struct {
+	Name        string    `json:"name" example:"Muse"`
+	Description string    `json:"description" example:"Британская рок-группа из Тинмута"`
+	Country     string    `json:"country" example:"United Kingdom"`
+	FormedYear  *int      `json:"formed_year" example:"1994"`
+	Links       []string  `json:"links" example:"https://www.muse.mu"`
+	CreatedAt   time.Time `json:"created_at" example:"2024-01-15T10:30:00Z"`
+	UpdatedAt   time.Time `json:"updated_at" example:"2024-01-15T10:30:00Z"`
+}
+
+// GroupInfoInput модель запроса на сохранение сведений о группе
+type GroupInfoInput struct {
+	Description string   `json:"description" example:"Британская рок-группа из Тинмута"`
+	Country     string   `json:"country" example:"United Kingdom"`
+	FormedYear  *int     `json:"formed_year" example:"1994"`
+	Links       []string `json:"links" example:"https://www.muse.mu"`
+}
+
+// Порядок песен группы
+const (
+	// GroupSongsSortName сортировка по названию песни
+	GroupSongsSortName = "song_name"
+	// GroupSongsSortReleaseDate сортировка по дате выхода; песни без даты или с датой в другом формате идут последними
+	GroupSongsSortReleaseDate = "release_date"
+)
+
+// Метрики рейтинга песен группы
+const (
+	// TopSongsMetricViews количество просмотров песни за все время хранения журнала обращений
+	TopSongsMetricViews = "views"
+	// TopSongsMetricUpdatedAt время последнего изменения песни
+	TopSongsMetricUpdatedAt = "updated_at"
+	// TopSongsMetricCreatedAt время добавления песни
+	TopSongsMetricCreatedAt = "created_at"
+)
+
+// MaxTopSongsLimit максимальное количество песен в рейтинге песен группы
+const MaxTopSongsLimit = 50
+
+// GroupStats сводная статистика песен группы
+type GroupStats struct {
+	SongCount     int64   `json:"song_count" db:"song_count" example:"12"`
+	TotalViews    int64   `json:"total_views" db:"total_views" example:"340"`
+	AvgVerseCount float64 `json:"avg_verse_count" db:"avg_verse_count" example:"3.5"`
+}
+
+// GroupSongsResponse страница песен группы с общим количеством песен
+type GroupSongsResponse struct {
+	Items []SongSummary `json:"items"`
+	Total int64         `json:"total" example:"42"`
+}
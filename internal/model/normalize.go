@@ -0,0 +1,23 @@
+package model
+
+import (
+	"golang.org/x/text/unicode/norm"
+	"strings"
+	"unicode"
+)
+
+// NormalizeName приводит название группы или песни к форме для поиска без учета регистра и диакритики:
+// символы раскладываются (NFD), комбинируемые знаки удаляются, результат переводится в нижний регистр.
+// Преобразование совпадает с lower(unaccent(...)) в PostgreSQL для латиницы и кириллицы
+// ("Motörhead" → "motorhead", "Ёлка" → "елка").
+func NormalizeName(value string) string {
+	var b strings.Builder
+	b.Grow(len(value))
+	for _, r := range norm.NFD.String(value) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return norm.NFC.String(b.String())
+}
@@ -0,0 +1,28 @@
+package model
+
+import "testing"
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"нижний регистр", "MUSE", "muse"},
+		{"латинская диакритика", "Motörhead", "motorhead"},
+		{"несколько знаков", "Mötley Crüe", "motley crue"},
+		{"разложенная форма", "Moto\u0308rhead", "motorhead"},
+		{"буква ё", "Ёлка", "елка"},
+		{"буква й", "Чайф", "чаиф"},
+		{"знаки препинания и пробелы сохраняются", "AC/DC  Live", "ac/dc  live"},
+		{"пустая строка", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeName(tt.value); got != tt.want {
+				t.Errorf("NormalizeName(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
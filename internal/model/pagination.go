@@ -0,0 +1,36 @@
+package model
+
+import (
+	"context"
+	"song-library/pkg/pagination"
+)
+
+// Pagination номер страницы (с 1) и размер страницы списка, встраиваемые в фильтры выборок.
+// Значения по умолчанию и ограничения применяет pagination.NewPage.
+type Pagination = pagination.Page
+
+// paginationNoticeKey ключ контекста для PaginationNotice
+type paginationNoticeKey struct{}
+
+// PaginationNotice сведения о выполненной выборке, которые обработчик передает клиенту в заголовках
+type PaginationNotice struct {
+	deepOffset bool
+}
+
+// DeepOffset сообщает, что смещение выборки превысило порог предупреждения
+func (n *PaginationNotice) DeepOffset() bool {
+	return n.deepOffset
+}
+
+// WithPaginationNotice возвращает контекст, в котором сервис отмечает глубокое смещение выборки
+func WithPaginationNotice(ctx context.Context) (context.Context, *PaginationNotice) {
+	notice := &PaginationNotice{}
+	return context.WithValue(ctx, paginationNoticeKey{}, notice), notice
+}
+
+// MarkDeepOffset отмечает глубокое смещение в PaginationNotice из контекста, если он там есть
+func MarkDeepOffset(ctx context.Context) {
+	if notice, ok := ctx.Value(paginationNoticeKey{}).(*PaginationNotice); ok {
+		notice.deepOffset = true
+	}
+}
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ProviderExternalAPI имя поставщика данных для сведений, полученных из внешнего API
+const ProviderExternalAPI = "external_api"
+
+// Поля песни, которые могут быть заполнены поставщиком данных
+const (
+	FieldReleaseDate = "releaseDate"
+	FieldText        = "text"
+	FieldLink        = "link"
+	FieldDuration    = "duration"
+	FieldBPM         = "bpm"
+	FieldCopyright   = "copyright"
+	// FieldFeaturedArtists приглашенные исполнители песни
+	FieldFeaturedArtists = "featuredArtists"
+)
+
+// FieldSource сведения о поставщике, заполнившем поле песни
+type FieldSource struct {
+	Provider  string    `json:"provider" example:"external_api"`
+	FetchedAt time.Time `json:"fetchedAt" example:"2024-01-15T10:30:00Z"`
+}
+
+// Provenance происхождение полей песни: имя поля → поставщик и время получения.
+// Поля, заполненные или измененные вручную, в Provenance отсутствуют.
+type Provenance map[string]FieldSource
+
+// Value сериализует происхождение в JSON для колонки JSONB
+func (p Provenance) Value() (driver.Value, error) {
+	if p == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сериализации происхождения полей: %w", err)
+	}
+	return string(data), nil
+}
+
+// Scan читает происхождение из колонки JSONB
+func (p *Provenance) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*p = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("неподдерживаемый тип происхождения полей: %T", src)
+	}
+	return json.Unmarshal(data, p)
+}
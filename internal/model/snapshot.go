@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// SnapshotStatus состояние плановых снимков библиотеки
+type SnapshotStatus struct {
+	Enabled bool `json:"enabled" example:"true"`
+	// Running сообщает, что снимок создается прямо сейчас
+	Running       bool       `json:"running" example:"false"`
+	Interval      string     `json:"interval,omitempty" example:"24h0m0s"`
+	LastRunAt     *time.Time `json:"last_run_at,omitempty" example:"2024-01-15T03:00:00Z"`
+	LastSuccessAt *time.Time `json:"last_success_at,omitempty" example:"2024-01-15T03:00:02Z"`
+	LastDuration  string     `json:"last_duration,omitempty" example:"1.52s"`
+	LastFile      string     `json:"last_file,omitempty" example:"songs-20240115T030000Z.jsonl"`
+	LastCount     int        `json:"last_count" example:"1250"`
+	LastError     string     `json:"last_error,omitempty" example:""`
+	// Skipped количество запусков, пропущенных из-за незавершенного предыдущего снимка
+	Skipped int64 `json:"skipped" example:"0"`
+}
@@ -1,42 +1,496 @@
 package model
 
-import "time"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// VerseDelimiter разделитель куплетов в тексте песни
+const VerseDelimiter = "\n\n"
 
 // Song представляет песню в библиотеке
 type Song struct {
-	ID          int64     `json:"id" db:"id"`
-	Group       string    `json:"group" db:"group_name"`
-	Song        string    `json:"song" db:"song_name"`
-	ReleaseDate string    `json:"releaseDate" db:"release_date"`
-	Text        string    `json:"text" db:"text"`
-	Link        string    `json:"link" db:"link"`
-	CreatedAt   time.Time `json:"createdAt" db:"created_at"`
-	UpdatedAt   time.Time `json:"updatedAt" db:"updated_at"`
+	ID              int64      `json:"id" db:"id" example:"1"`
+	PublicID        string     `json:"publicId" db:"public_id" example:"0b6a4f1e-8c2d-4f7a-9e3b-5d1c2a7f9e40"`
+	Group           string     `json:"group" db:"group_name" example:"Muse"`
+	Song            string     `json:"song" db:"song_name" example:"Supermassive Black Hole"`
+	ReleaseDate     string     `json:"releaseDate" db:"release_date" example:"16.07.2006"`
+	Text            string     `json:"text" db:"text" example:"Ooh baby, don't you know I suffer?\nOoh baby, can you hear me moan?\n\nOoh\nYou set my soul alight"`
+	Link            string     `json:"link" db:"link" example:"https://www.youtube.com/watch?v=Xsp3_a-PMTw"`
+	CreatedAt       time.Time  `json:"createdAt" db:"created_at" example:"2024-01-15T10:30:00Z"`
+	UpdatedAt       time.Time  `json:"updatedAt" db:"updated_at" example:"2024-01-15T10:30:00Z"`
+	VerseCount      int        `json:"verseCount" db:"verse_count" example:"2"`
+	TextLength      int        `json:"textLength" db:"text_length" example:"98"`
+	Duration        *int       `json:"duration" db:"duration_seconds" example:"212"`
+	BPM             *int16     `json:"bpm" db:"bpm" example:"120"`
+	Copyright       *string    `json:"copyright,omitempty" db:"copyright" example:"© 2006 Warner Music UK Limited"`
+	Relevance       *float64   `json:"relevance,omitempty" db:"relevance" example:"0.8"`
+	Snippet         string     `json:"snippet,omitempty" db:"-" example:"…don't you know I <mark>suffer</mark>?…"`
+	Provenance      Provenance `json:"provenance,omitempty" db:"source"`
+	DeletedAt       *time.Time `json:"deletedAt,omitempty" db:"deleted_at" example:"2024-02-01T08:00:00Z"`
+	ContentHash     string     `json:"contentHash" db:"content_hash" example:"3b7e1f0c9d2a4e5b6c7d8e9f0a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d"`
+	FeaturedArtists Artists    `json:"featuredArtists" db:"featured_artists"`
+	// EnrichmentStatus результат последнего получения данных песни от поставщика: ok, failed или pending
+	EnrichmentStatus string `json:"enrichmentStatus" db:"enrichment_status" example:"ok"`
+	// EnrichedAt время последнего успешного получения данных от поставщика; null, если данные не получены
+	EnrichedAt *time.Time `json:"enrichedAt" db:"enriched_at" example:"2024-01-15T10:30:00Z"`
+
+	// ChartPosition текущая позиция песни в чарте; null, если песня не в чарте
+	ChartPosition *int16 `json:"chartPosition" db:"chart_position" example:"5"`
+	// ChartHistory последние MaxChartHistory снимков позиции в чарте, от старых к новым
+	ChartHistory ChartHistory `json:"chartHistory" db:"chart_history"`
+}
+
+// Статусы получения данных песни от поставщика
+const (
+	// EnrichmentStatusOK данные получены
+	EnrichmentStatusOK = "ok"
+	// EnrichmentStatusFailed последняя попытка получить данные завершилась ошибкой
+	EnrichmentStatusFailed = "failed"
+	// EnrichmentStatusPending данные еще не запрашивались, например у песен из импорта
+	EnrichmentStatusPending = "pending"
+)
+
+// EnrichmentStatuses допустимые статусы получения данных песни
+var EnrichmentStatuses = []string{EnrichmentStatusOK, EnrichmentStatusFailed, EnrichmentStatusPending}
+
+// ComputeContentHash пересчитывает ContentHash: SHA-256 в hex от группы, названия, даты выпуска, текста и ссылки,
+// разделенных нулевым байтом. Строки PostgreSQL не содержат нулевых байтов, поэтому разделение однозначно;
+// миграция заполнения вычисляет то же значение в SQL.
+func (s *Song) ComputeContentHash() {
+	sum := sha256.Sum256([]byte(s.Group + "\x00" + s.Song + "\x00" + s.ReleaseDate + "\x00" + s.Text + "\x00" + s.Link))
+	s.ContentHash = hex.EncodeToString(sum[:])
+}
+
+// ComputeTextStats пересчитывает количество куплетов и длину текста песни
+func (s *Song) ComputeTextStats() {
+	s.TextLength = utf8.RuneCountInString(s.Text)
+	s.VerseCount = 0
+	if s.Text != "" {
+		s.VerseCount = len(strings.Split(s.Text, VerseDelimiter))
+	}
+}
+
+// Clone возвращает глубокую копию песни: указатели, список исполнителей, история чарта и происхождение не разделяются с оригиналом
+func (s *Song) Clone() *Song {
+	c := *s
+	c.Duration = clonePtr(s.Duration)
+	c.BPM = clonePtr(s.BPM)
+	c.Copyright = clonePtr(s.Copyright)
+	c.Relevance = clonePtr(s.Relevance)
+	c.DeletedAt = clonePtr(s.DeletedAt)
+	c.EnrichedAt = clonePtr(s.EnrichedAt)
+	c.ChartPosition = clonePtr(s.ChartPosition)
+	if s.FeaturedArtists != nil {
+		c.FeaturedArtists = append(Artists{}, s.FeaturedArtists...)
+	}
+	if s.ChartHistory != nil {
+		c.ChartHistory = append(ChartHistory{}, s.ChartHistory...)
+	}
+	if s.Provenance != nil {
+		c.Provenance = make(Provenance, len(s.Provenance))
+		for field, source := range s.Provenance {
+			c.Provenance[field] = source
+		}
+	}
+	return &c
+}
+
+// clonePtr возвращает указатель на копию значения или nil
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+// SongSummary песня без текста для облегченных ответов со списками
+type SongSummary struct {
+	ID               int64      `json:"id" example:"1"`
+	PublicID         string     `json:"publicId" example:"0b6a4f1e-8c2d-4f7a-9e3b-5d1c2a7f9e40"`
+	Group            string     `json:"group" example:"Muse"`
+	Song             string     `json:"song" example:"Supermassive Black Hole"`
+	ReleaseDate      string     `json:"releaseDate" example:"16.07.2006"`
+	Link             string     `json:"link" example:"https://www.youtube.com/watch?v=Xsp3_a-PMTw"`
+	CreatedAt        time.Time  `json:"createdAt" example:"2024-01-15T10:30:00Z"`
+	UpdatedAt        time.Time  `json:"updatedAt" example:"2024-01-15T10:30:00Z"`
+	VerseCount       int        `json:"verseCount" example:"2"`
+	TextLength       int        `json:"textLength" example:"98"`
+	Duration         *int       `json:"duration" example:"212"`
+	BPM              *int16     `json:"bpm" example:"120"`
+	Relevance        *float64   `json:"relevance,omitempty" example:"0.8"`
+	Snippet          string     `json:"snippet,omitempty" example:"…don't you know I <mark>suffer</mark>?…"`
+	Provenance       Provenance `json:"provenance,omitempty"`
+	ContentHash      string     `json:"contentHash" example:"3b7e1f0c9d2a4e5b6c7d8e9f0a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d"`
+	FeaturedArtists  Artists    `json:"featuredArtists"`
+	EnrichmentStatus string     `json:"enrichmentStatus" example:"ok"`
+	EnrichedAt       *time.Time `json:"enrichedAt" example:"2024-01-15T10:30:00Z"`
+}
+
+// Summary возвращает представление песни без текста
+func (s *Song) Summary() SongSummary {
+	return SongSummary{
+		ID:               s.ID,
+		PublicID:         s.PublicID,
+		Group:            s.Group,
+		Song:             s.Song,
+		ReleaseDate:      s.ReleaseDate,
+		Link:             s.Link,
+		CreatedAt:        s.CreatedAt,
+		UpdatedAt:        s.UpdatedAt,
+		VerseCount:       s.VerseCount,
+		TextLength:       s.TextLength,
+		Duration:         s.Duration,
+		BPM:              s.BPM,
+		Relevance:        s.Relevance,
+		Snippet:          s.Snippet,
+		Provenance:       s.Provenance,
+		ContentHash:      s.ContentHash,
+		FeaturedArtists:  s.FeaturedArtists,
+		EnrichmentStatus: s.EnrichmentStatus,
+		EnrichedAt:       s.EnrichedAt,
+	}
 }
 
 // SongInput модель для добавления новой песни
 type SongInput struct {
-	Group string `json:"group" binding:"required"`
-	Song  string `json:"song" binding:"required"`
+	Group string `json:"group" binding:"required" example:"Muse"`
+	Song  string `json:"song" binding:"required" example:"Supermassive Black Hole"`
+}
+
+// SongImport модель песни для массового импорта без обращения к внешнему API
+type SongImport struct {
+	Group       string `json:"group" binding:"required" example:"Muse"`
+	Song        string `json:"song" binding:"required" example:"Supermassive Black Hole"`
+	ReleaseDate string `json:"releaseDate" example:"16.07.2006"`
+	Text        string `json:"text" example:"Ooh baby, don't you know I suffer?"`
+	Link        string `json:"link" example:"https://www.youtube.com/watch?v=Xsp3_a-PMTw"`
+	Duration    *int   `json:"duration" example:"212"`
+	BPM         *int16 `json:"bpm" example:"120"`
+}
+
+// SongRef идентификаторы песни: внутренний последовательный и публичный UUID
+type SongRef struct {
+	ID       int64
+	PublicID string
+}
+
+// SongDocumentFormatVersion текущая версия формата переносимого документа песни.
+// Новые поля добавляются без повышения версии; версия повышается только при несовместимых изменениях.
+const SongDocumentFormatVersion = 1
+
+// SongDocument переносимый документ песни для переноса между инсталляциями.
+// Не содержит идентификатор, временные метки и историю изменений.
+type SongDocument struct {
+	FormatVersion int `json:"formatVersion" binding:"required" example:"1"`
+	SongImport
+	Copyright       *string    `json:"copyright,omitempty" example:"© 2006 Warner Music UK Limited"`
+	FeaturedArtists Artists    `json:"featuredArtists,omitempty"`
+	Provenance      Provenance `json:"provenance,omitempty"`
+}
+
+// LibraryManifest опись архива библиотеки: метаданные песен и пути их текстов внутри архива
+type LibraryManifest struct {
+	ExportedAt time.Time              `json:"exportedAt" example:"2024-01-15T10:30:00Z"`
+	Count      int                    `json:"count" example:"1"`
+	Songs      []LibraryManifestEntry `json:"songs"`
+}
+
+// LibraryManifestEntry метаданные песни в описи архива библиотеки
+type LibraryManifestEntry struct {
+	File        string `json:"file" example:"Muse/Supermassive Black Hole.txt"`
+	ID          int64  `json:"id" example:"1"`
+	PublicID    string `json:"publicId" example:"0b6a4f1e-8c2d-4f7a-9e3b-5d1c2a7f9e40"`
+	Group       string `json:"group" example:"Muse"`
+	Song        string `json:"song" example:"Supermassive Black Hole"`
+	ReleaseDate string `json:"releaseDate" example:"16.07.2006"`
+	Link        string `json:"link" example:"https://www.youtube.com/watch?v=Xsp3_a-PMTw"`
+	Duration    *int   `json:"duration" example:"212"`
+	BPM         *int16 `json:"bpm" example:"120"`
+}
+
+// BulkCreateResponse результат массового создания песен
+type BulkCreateResponse struct {
+	Inserted int64 `json:"inserted" example:"25"`
 }
 
 // SongDetail ответ от внешнего API
 type SongDetail struct {
-	ReleaseDate string `json:"releaseDate"`
-	Text        string `json:"text"`
-	Link        string `json:"link"`
+	ReleaseDate     string   `json:"releaseDate"`
+	Text            string   `json:"text"`
+	Link            string   `json:"link"`
+	DurationSeconds *int     `json:"durationSeconds"`
+	BPM             *int     `json:"bpm"`
+	Copyright       string   `json:"copyright"`
+	FeaturedArtists []string `json:"featuredArtists"`
 }
 
 // SongFilter параметры фильтрации для списка песен
 type SongFilter struct {
-	Group    string
-	SongName string
-	Page     int
-	PageSize int
+	Group       string
+	SongName    string
+	Text        string
+	QuickSearch string
+	OmitText    bool
+	DurationMin *int
+	DurationMax *int
+	BPMMin      *int
+	BPMMax      *int
+	// HasText отбирает песни с текстом (true) или без него (false); nil — без фильтра
+	HasText *bool
+	// HasLink отбирает песни со ссылкой (true) или без нее (false); nil — без фильтра
+	HasLink *bool
+	// CopyrightContains подстрока сведений об авторских правах без учета регистра
+	CopyrightContains string
+	// MissingFields поля из MissingFilterFields, которые у песни должны быть не заполнены
+	MissingFields []string
+	// CreatedAtFrom и CreatedAtTo ограничивают время добавления песни включительно; nil — без ограничения
+	CreatedAtFrom *time.Time
+	CreatedAtTo   *time.Time
+	// FeaturedArtist имя приглашенного исполнителя, точное совпадение
+	FeaturedArtist string
+	// EnrichmentStatus статус получения данных от поставщика из EnrichmentStatuses; пустой — без фильтра
+	EnrichmentStatus string
+	// Groups точные названия групп, любое из которых должно совпасть; не сочетается с Group
+	Groups []string
+	// SongNames точные названия песен, любое из которых должно совпасть; не сочетается с SongName
+	SongNames []string
+	Pagination
+}
+
+// MaxFilterListItems максимальное количество значений в списочных фильтрах Groups и SongNames
+const MaxFilterListItems = 50
+
+// MissingFilterFields поля песни, по отсутствию значения которых фильтруется список песен
+var MissingFilterFields = []string{FieldText, FieldLink, FieldReleaseDate, FieldDuration, FieldBPM}
+
+// UpdateOptions параметры обновления песни
+type UpdateOptions struct {
+	// ProtectEnriched запрещает изменение полей, заполненных поставщиком данных
+	ProtectEnriched bool
+	// Force разрешает изменение защищенных полей при ProtectEnriched
+	Force bool
+}
+
+// DurationInput модель для обновления длительности песни
+type DurationInput struct {
+	DurationSeconds *int `json:"duration_seconds" example:"212"`
+}
+
+// MaxCopyrightLength максимальная длина сведений об авторских правах в символах
+const MaxCopyrightLength = 2000
+
+// MaxFeaturedArtists максимальное количество приглашенных исполнителей песни
+const MaxFeaturedArtists = 10
+
+// FeaturedArtistsInput модель для замены списка приглашенных исполнителей песни
+type FeaturedArtistsInput struct {
+	Artists []string `json:"artists" example:"Guest 1,Guest 2"`
+}
+
+// CopyrightInput модель для обновления сведений об авторских правах песни
+type CopyrightInput struct {
+	Copyright *string `json:"copyright" example:"© 2006 Warner Music UK Limited"`
+}
+
+// Допустимый диапазон темпа песни в ударах в минуту
+const (
+	MinBPM = 20
+	MaxBPM = 300
+)
+
+// TempoBucketWidth ширина интервала гистограммы темпа в ударах в минуту
+const TempoBucketWidth = 20
+
+// BPMInput модель для обновления темпа песни
+type BPMInput struct {
+	BPM *int `json:"bpm" example:"120"`
+}
+
+// TempoBucket количество песен с темпом в интервале Range ("60-80": от 60 включительно до 80 не включительно)
+type TempoBucket struct {
+	Range string `json:"range" example:"60-80"`
+	Count int64  `json:"count" example:"12"`
 }
 
-// VersesPagination параметры пагинации для куплетов
+// TempoRange интервал гистограммы темпа [Low, High); последний интервал включает MaxBPM
+type TempoRange struct {
+	Low  int
+	High int
+}
+
+// Label возвращает подпись интервала вида "60-80"
+func (r TempoRange) Label() string {
+	return strconv.Itoa(r.Low) + "-" + strconv.Itoa(r.High)
+}
+
+// TempoRanges возвращает интервалы гистограммы темпа в порядке возрастания
+func TempoRanges() []TempoRange {
+	var ranges []TempoRange
+	for low := MinBPM; low < MaxBPM; low += TempoBucketWidth {
+		ranges = append(ranges, TempoRange{Low: low, High: min(low+TempoBucketWidth, MaxBPM)})
+	}
+	return ranges
+}
+
+// TotalDuration суммарная длительность песен
+type TotalDuration struct {
+	TotalSeconds int64  `json:"total_seconds" example:"12435"`
+	Formatted    string `json:"formatted" example:"3h 27m 15s"`
+}
+
+// VerseOrderInput новый порядок куплетов: номера куплетов с 1 в желаемом порядке
+type VerseOrderInput struct {
+	Order []int `json:"order" binding:"required" example:"3,1,2,4"`
+}
+
+// VerseOrderResponse результат перестановки куплетов
+type VerseOrderResponse struct {
+	VerseCount int `json:"verse_count" example:"4"`
+}
+
+// VerseInput новый текст одного куплета
+type VerseInput struct {
+	Text string `json:"text" binding:"required" example:"Ooh baby, don't you know I suffer?"`
+}
+
+// VerseEditResponse результат изменения или удаления куплета: номер куплета и количество куплетов после изменения
+type VerseEditResponse struct {
+	Index   int  `json:"index" example:"2"`
+	Total   int  `json:"total" example:"4"`
+	Updated bool `json:"updated" example:"true"`
+}
+
+// VersesPagination параметры выборки куплетов: страница или диапазон From–To (нумерация с 1, включительно)
 type VersesPagination struct {
-	Page     int
-	PageSize int
+	Pagination
+	From *int
+	To   *int
+	// Descending возвращает куплеты в обратном порядке; страницы при этом отсчитываются с конца текста
+	Descending bool
+}
+
+// HasRange сообщает, задан ли диапазон куплетов вместо страницы
+func (p VersesPagination) HasRange() bool {
+	return p.From != nil || p.To != nil
+}
+
+const (
+	// AccessActionView просмотр песни
+	AccessActionView = "view"
+	// AccessActionVerses получение куплетов песни
+	AccessActionVerses = "verses"
+)
+
+// AccessLogEntry запись об обращении к песне
+type AccessLogEntry struct {
+	Action     string    `json:"action" db:"action" example:"view"`
+	AccessedAt time.Time `json:"accessed_at" db:"accessed_at" example:"2024-01-15T10:30:00Z"`
+}
+
+// AccessLog журнал обращений к песне с количеством обращений по действиям
+type AccessLog struct {
+	Total   int              `json:"total" example:"3"`
+	Counts  map[string]int   `json:"counts" example:"view:2,verses:1"`
+	Entries []AccessLogEntry `json:"entries"`
+}
+
+// MostAccessedSong песня с количеством обращений за период
+type MostAccessedSong struct {
+	Song
+	AccessCount int64 `json:"accessCount" db:"access_count" example:"42"`
+}
+
+// Периоды списка трендовых песен
+const (
+	TrendingPeriodDay   = "day"
+	TrendingPeriodWeek  = "week"
+	TrendingPeriodMonth = "month"
+)
+
+// DuplicateGroup группа песен, которые могут быть дубликатами друг друга
+type DuplicateGroup struct {
+	Candidates []SongSummary `json:"candidates"`
+}
+
+// WordCount количество употреблений слова в тексте песни
+type WordCount struct {
+	Word  string `json:"word" example:"baby"`
+	Count int    `json:"count" example:"4"`
+}
+
+// Стратегии слияния песен
+const (
+	// MergeAppendVerses добавляет текст исходной песни в конец текста целевой через разделитель куплетов
+	MergeAppendVerses = "append_verses"
+	// MergeReplaceText заменяет текст целевой песни текстом исходной
+	MergeReplaceText = "replace_text"
+	// MergeKeepTarget сохраняет текст целевой песни и переносит из исходной только метаданные
+	MergeKeepTarget = "keep_target"
+)
+
+// MergeInput модель запроса на слияние двух песен
+type MergeInput struct {
+	SourceID int64  `json:"source_id" binding:"required" example:"1"`
+	TargetID int64  `json:"target_id" binding:"required" example:"2"`
+	Strategy string `json:"strategy" binding:"required" example:"append_verses"`
+}
+
+// Режимы разрешения конфликтов при переименовании группы
+const (
+	// RenameMergeSkip оставляет под старым названием группы песни, название которых уже занято в новой группе
+	RenameMergeSkip = "skip"
+	// RenameMergeOverwrite удаляет песни новой группы, с которыми конфликтуют переименовываемые
+	RenameMergeOverwrite = "overwrite"
+)
+
+// GroupRenameInput модель запроса на переименование группы
+type GroupRenameInput struct {
+	NewName string `json:"newName" binding:"required" example:"Muse (UK)"`
+}
+
+// RenameConflict пара песен, мешающая переименованию: песня группы и песня с тем же названием в новой группе
+type RenameConflict struct {
+	Song       string `json:"song" db:"song_name" example:"Supermassive Black Hole"`
+	SongID     int64  `json:"song_id" db:"song_id" example:"1"`
+	ExistingID int64  `json:"existing_id" db:"existing_id" example:"42"`
+}
+
+// GroupRenameResult результат переименования группы
+type GroupRenameResult struct {
+	Renamed     int     `json:"renamed" example:"12"`
+	Skipped     int     `json:"skipped" example:"0"`
+	Overwritten int     `json:"overwritten" example:"0"`
+	RenamedIDs  []int64 `json:"renamed_ids" example:"1,2,3"`
+}
+
+// Интервалы группировки динамики роста библиотеки
+const (
+	GrowthIntervalDay   = "day"
+	GrowthIntervalWeek  = "week"
+	GrowthIntervalMonth = "month"
+)
+
+// GrowthBucket количество песен, добавленных в интервале, начинающемся с Bucket (YYYY-MM-DD)
+type GrowthBucket struct {
+	Bucket string `json:"bucket" example:"2024-01-15"`
+	Count  int64  `json:"count" example:"5"`
+}
+
+// EnrichmentStatusCount количество активных песен с одним статусом получения данных от поставщика
+type EnrichmentStatusCount struct {
+	Status string `json:"status" db:"enrichment_status" example:"failed"`
+	Count  int64  `json:"count" db:"count" example:"3"`
+}
+
+// GroupStat показатель группы в рейтинге (количество песен или обращений)
+type GroupStat struct {
+	Group   string `json:"group" db:"group_name" example:"Muse"`
+	Count   int64  `json:"count" db:"count" example:"12"`
+	HasInfo bool   `json:"has_info" db:"has_info" example:"true"`
 }
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TrashPurgeResult результат окончательного удаления песен из корзины
+type TrashPurgeResult struct {
+	Purged int64 `json:"purged" example:"120"`
+	// Truncated сообщает, что достигнут предел песен за один вызов и в корзине могут остаться песни старше срока
+	Truncated bool `json:"truncated" example:"false"`
+}
+
+// ParseRetention разбирает срок хранения: количество дней с суффиксом d ("30d") или длительность Go ("12h")
+func ParseRetention(value string) (time.Duration, error) {
+	var (
+		retention time.Duration
+		err       error
+	)
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		var count int
+		count, err = strconv.Atoi(days)
+		retention = time.Duration(count) * 24 * time.Hour
+	} else {
+		retention, err = time.ParseDuration(value)
+	}
+	if err != nil || retention <= 0 {
+		return 0, fmt.Errorf("неверный срок хранения %q: ожидается число дней с суффиксом d или длительность", value)
+	}
+	return retention, nil
+}
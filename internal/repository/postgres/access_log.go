@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"song-library/internal/model"
+	"time"
+)
+
+// recordAccess записывает обращение к песне в song_access_log.
+// Ошибка записи не прерывает основной запрос и только логируется.
+func (r *SongRepository) recordAccess(ctx context.Context, songID int64, action string) {
+	if r.cfg.DisableAccessLog {
+		return
+	}
+
+	log := r.logger.WithFields(ctx, "song_id", songID)
+
+	query := `INSERT INTO song_access_log (song_id, action, accessed_at) VALUES ($1, $2, $3)`
+	if _, err := r.conn(ctx).ExecContext(ctx, query, songID, action, time.Now()); err != nil {
+		log.Error("Ошибка записи обращения к песне", "error", err, "action", action)
+	}
+}
+
+// RecordAccess записывает обращение к песне, полученной не из базы данных (например, из кэша).
+// Ошибка записи логируется и не возвращается, как и при чтении песни из базы.
+func (r *SongRepository) RecordAccess(ctx context.Context, songID int64, action string) {
+	r.recordAccess(ctx, songID, action)
+}
+
+// GetAccessLog получает обращения к песне за период. Нулевые границы периода не ограничивают выборку
+func (r *SongRepository) GetAccessLog(ctx context.Context, songID int64, from, to *time.Time) ([]model.AccessLogEntry, error) {
+	log := r.logger.WithFields(ctx, "song_id", songID)
+
+	log.Debug("Получение журнала обращений к песне")
+
+	query := `SELECT action, accessed_at FROM song_access_log
+		WHERE song_id = $1
+			AND ($2::timestamp IS NULL OR accessed_at >= $2)
+			AND ($3::timestamp IS NULL OR accessed_at <= $3)
+		ORDER BY accessed_at DESC, id DESC`
+
+	entries := []model.AccessLogEntry{}
+	if err := sqlx.SelectContext(ctx, r.readConn(ctx), &entries, query, songID, from, to); err != nil {
+		log.Error("Ошибка получения журнала обращений", "error", err)
+		return nil, fmt.Errorf("ошибка получения журнала обращений: %w", err)
+	}
+
+	log.Info("Журнал обращений успешно получен", "count", len(entries))
+	return entries, nil
+}
+
+// GetMostAccessedSongs получает песни с наибольшим количеством обращений начиная с since
+func (r *SongRepository) GetMostAccessedSongs(ctx context.Context, since time.Time, limit int) ([]*model.MostAccessedSong, error) {
+	log := r.logger.WithContext(ctx)
+
+	log.Debug("Получение самых популярных песен", "since", since, "limit", limit)
+
+	query := `SELECT ` + songColumnsPrefixed + `, a.access_count
+		FROM songs s
+		JOIN (
+			SELECT song_id, COUNT(*) AS access_count
+			FROM song_access_log
+			WHERE accessed_at >= $1
+			GROUP BY song_id
+		) a ON a.song_id = s.id
+		WHERE s.deleted_at IS NULL
+		ORDER BY a.access_count DESC, s.id DESC
+		LIMIT $2`
+
+	songs := []*model.MostAccessedSong{}
+	if err := sqlx.SelectContext(ctx, r.readConn(ctx), &songs, query, since, limit); err != nil {
+		log.Error("Ошибка получения самых популярных песен", "error", err)
+		return nil, fmt.Errorf("ошибка получения самых популярных песен: %w", err)
+	}
+
+	log.Info("Самые популярные песни успешно получены", "count", len(songs))
+	return songs, nil
+}
+
+// trendingIntervals интервалы PostgreSQL для периодов списка трендовых песен
+var trendingIntervals = map[string]string{
+	model.TrendingPeriodDay:   "1 day",
+	model.TrendingPeriodWeek:  "7 days",
+	model.TrendingPeriodMonth: "1 month",
+}
+
+// GetTrendingSongs получает до limit песен с наибольшим количеством обращений за период day, week или month
+func (r *SongRepository) GetTrendingSongs(ctx context.Context, period string, limit int) ([]*model.Song, error) {
+	log := r.logger.WithContext(ctx)
+
+	log.Debug("Получение трендовых песен", "period", period, "limit", limit)
+
+	interval, ok := trendingIntervals[period]
+	if !ok {
+		log.Error("Неизвестный период трендовых песен", "period", period)
+		return nil, fmt.Errorf("неизвестный период трендовых песен: %s", period)
+	}
+
+	query := `SELECT ` + songColumnsPrefixed + `
+		FROM songs s
+		JOIN (
+			SELECT song_id, COUNT(*) AS access_count
+			FROM song_access_log
+			WHERE accessed_at > NOW() - $1::interval
+			GROUP BY song_id
+		) a ON a.song_id = s.id
+		WHERE s.deleted_at IS NULL
+		ORDER BY a.access_count DESC, s.id DESC
+		LIMIT $2`
+
+	songs := []*model.Song{}
+	if err := sqlx.SelectContext(ctx, r.readConn(ctx), &songs, query, interval, limit); err != nil {
+		log.Error("Ошибка получения трендовых песен", "error", err)
+		return nil, fmt.Errorf("ошибка получения трендовых песен: %w", err)
+	}
+
+	log.Info("Трендовые песни успешно получены", "count", len(songs))
+	return songs, nil
+}
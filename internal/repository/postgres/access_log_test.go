@@ -0,0 +1,172 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"reflect"
+	"song-library/internal/model"
+	"testing"
+	"time"
+)
+
+func TestRecordAccess(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         RepositoryConfig
+		wantEntries int
+	}{
+		{"журнал включен", RepositoryConfig{}, 2},
+		{"журнал отключен", RepositoryConfig{DisableAccessLog: true}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newTestRepository(t, tt.cfg)
+			song := mustCreateSong(t, repo, newTestSong("Muse", "Hysteria"))
+
+			repo.RecordAccess(ctx, song.ID, model.AccessActionView)
+			repo.RecordAccess(ctx, song.ID, model.AccessActionVerses)
+			// Ошибка записи, например для отсутствующей песни, не прерывает запрос
+			repo.RecordAccess(ctx, 999, model.AccessActionView)
+
+			entries, err := repo.GetAccessLog(ctx, song.ID, nil, nil)
+			if err != nil {
+				t.Fatalf("GetAccessLog() error = %v", err)
+			}
+			if len(entries) != tt.wantEntries {
+				t.Errorf("GetAccessLog() = %+v, want %d записей", entries, tt.wantEntries)
+			}
+		})
+	}
+}
+
+func TestGetAccessLog(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	song := mustCreateSong(t, repo, newTestSong("Muse", "Hysteria"))
+
+	for _, at := range []string{"2024-01-10 10:00", "2024-01-15 10:00", "2024-01-20 10:00"} {
+		mustExec(t, `INSERT INTO song_access_log (song_id, action, accessed_at) VALUES ($1, 'view', $2)`, song.ID, at)
+	}
+	from := time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 18, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		songID   int64
+		from, to *time.Time
+		wantDays []int
+	}{
+		{"без границ, новые первыми", song.ID, nil, nil, []int{20, 15, 10}},
+		{"с начала периода", song.ID, &from, nil, []int{20, 15}},
+		{"до конца периода", song.ID, nil, &to, []int{15, 10}},
+		{"период", song.ID, &from, &to, []int{15}},
+		{"песня без обращений", 999, nil, nil, []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := repo.GetAccessLog(ctx, tt.songID, tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("GetAccessLog() error = %v", err)
+			}
+			days := []int{}
+			for _, entry := range entries {
+				days = append(days, entry.AccessedAt.Day())
+			}
+			if !reflect.DeepEqual(days, tt.wantDays) {
+				t.Errorf("GetAccessLog() дни = %v, want %v", days, tt.wantDays)
+			}
+		})
+	}
+}
+
+// seedAccessedSongs создает три песни с 3, 1 и 2 обращениями и удаленную песню с 5 обращениями.
+// Обращение к песне 2 сделано 10 дней назад
+func seedAccessedSongs(t *testing.T, repo *SongRepository) {
+	t.Helper()
+
+	for _, name := range []string{"Hysteria", "Uprising", "Starlight", "Deleted"} {
+		mustCreateSong(t, repo, newTestSong("Muse", name))
+	}
+	if err := repo.DeleteSong(context.Background(), 4); err != nil {
+		t.Fatalf("DeleteSong() error = %v", err)
+	}
+
+	accesses := map[int64]int{1: 3, 3: 2, 4: 5}
+	for id, count := range accesses {
+		for range count {
+			mustExec(t, `INSERT INTO song_access_log (song_id, action, accessed_at) VALUES ($1, 'view', now()::timestamp)`, id)
+		}
+	}
+	mustExec(t, `INSERT INTO song_access_log (song_id, action, accessed_at) VALUES (2, 'view', now()::timestamp - interval '10 days')`)
+}
+
+func TestGetMostAccessedSongs(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	seedAccessedSongs(t, repo)
+
+	tests := []struct {
+		name       string
+		since      time.Time
+		limit      int
+		wantIDs    []int64
+		wantCounts []int64
+	}{
+		{"за все время", time.Time{}, 10, []int64{1, 3, 2}, []int64{3, 2, 1}},
+		{"ограничение количества", time.Time{}, 1, []int64{1}, []int64{3}},
+		{"за последние дни", time.Now().Add(-48 * time.Hour), 10, []int64{1, 3}, []int64{3, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			songs, err := repo.GetMostAccessedSongs(ctx, tt.since, tt.limit)
+			if err != nil {
+				t.Fatalf("GetMostAccessedSongs() error = %v", err)
+			}
+			ids, counts := []int64{}, []int64{}
+			for _, song := range songs {
+				ids = append(ids, song.ID)
+				counts = append(counts, song.AccessCount)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) || !reflect.DeepEqual(counts, tt.wantCounts) {
+				t.Errorf("GetMostAccessedSongs() = %v, %v, want %v, %v", ids, counts, tt.wantIDs, tt.wantCounts)
+			}
+		})
+	}
+}
+
+func TestGetTrendingSongs(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	seedAccessedSongs(t, repo)
+
+	tests := []struct {
+		name    string
+		period  string
+		wantIDs []int64
+	}{
+		{"день", model.TrendingPeriodDay, []int64{1, 3}},
+		{"неделя", model.TrendingPeriodWeek, []int64{1, 3}},
+		{"месяц", model.TrendingPeriodMonth, []int64{1, 3, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			songs, err := repo.GetTrendingSongs(ctx, tt.period, 10)
+			if err != nil {
+				t.Fatalf("GetTrendingSongs() error = %v", err)
+			}
+			if got := songIDs(songs); !reflect.DeepEqual(got, tt.wantIDs) {
+				t.Errorf("GetTrendingSongs() ids = %v, want %v", got, tt.wantIDs)
+			}
+		})
+	}
+
+	if _, err := repo.GetTrendingSongs(ctx, "year", 10); err == nil {
+		t.Error("GetTrendingSongs(year) error = nil")
+	}
+}
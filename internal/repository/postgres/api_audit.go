@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"song-library/internal/model"
+)
+
+// RecordAPICall сохраняет запись об изменяющем вызове API в таблицу api_audit
+func (l *AuditPostgresLogger) RecordAPICall(ctx context.Context, call *model.APICall) error {
+	query := `INSERT INTO api_audit (actor, method, route, song_id, status, latency_ms, request_id, occurred_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id`
+
+	err := l.db.QueryRowxContext(ctx, query,
+		call.Actor,
+		call.Method,
+		call.Route,
+		call.SongID,
+		call.Status,
+		call.LatencyMs,
+		call.RequestID,
+		call.OccurredAt,
+	).Scan(&call.ID)
+	if err != nil {
+		return fmt.Errorf("ошибка записи вызова API в журнал: %w", err)
+	}
+	return nil
+}
+
+// ListAPICalls получает записи журнала вызовов API с фильтрацией и пагинацией, новые первыми
+func (l *AuditPostgresLogger) ListAPICalls(ctx context.Context, filter model.APICallFilter) ([]model.APICall, error) {
+	log := l.logger.WithContext(ctx)
+
+	log.Debug("Получение журнала вызовов API",
+		"since", filter.Since,
+		"actor", filter.Actor,
+		"song_id", filter.SongID,
+		"page", filter.Page,
+		"pageSize", filter.PageSize)
+
+	query := `SELECT id, actor, method, route, song_id, status, latency_ms, request_id, occurred_at
+		FROM api_audit
+		WHERE ($1::TIMESTAMP IS NULL OR occurred_at >= $1)
+			AND ($2 = '' OR actor = $2)
+			AND ($3::BIGINT IS NULL OR song_id = $3)
+		ORDER BY occurred_at DESC, id DESC
+		LIMIT $4 OFFSET $5`
+
+	offset := filter.Offset()
+	calls := []model.APICall{}
+	err := sqlx.SelectContext(ctx, l.db, &calls, query, filter.Since, filter.Actor, filter.SongID, filter.PageSize, offset)
+	if err != nil {
+		log.Error("Ошибка получения журнала вызовов API", "error", err)
+		return nil, fmt.Errorf("ошибка получения журнала вызовов API: %w", err)
+	}
+
+	log.Info("Журнал вызовов API успешно получен", "count", len(calls))
+	return calls, nil
+}
@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"song-library/internal/model"
+	"song-library/pkg/logger"
+	"time"
+)
+
+// AuditPostgresLogger записывает доменные события в таблицу song_events.
+// Если в контексте есть транзакция репозитория, событие записывается в ней.
+type AuditPostgresLogger struct {
+	db     *sqlx.DB
+	logger *logger.Logger
+}
+
+// NewAuditPostgresLogger создает новый журнал событий в PostgreSQL
+func NewAuditPostgresLogger(db *sqlx.DB, logger *logger.Logger) *AuditPostgresLogger {
+	return &AuditPostgresLogger{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// LogEvent сохраняет событие в журнал
+func (l *AuditPostgresLogger) LogEvent(ctx context.Context, event *model.SongEvent) error {
+	log := l.logger.WithContext(ctx)
+
+	query := `INSERT INTO song_events (event_type, song_id, actor, payload, occurred_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`
+
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	payload := []byte(event.Payload)
+	if len(payload) == 0 {
+		payload = []byte("{}")
+	}
+
+	err := txOrDB(ctx, l.db).QueryRowxContext(ctx, query,
+		event.EventType,
+		event.SongID,
+		event.Actor,
+		payload,
+		event.OccurredAt,
+	).Scan(&event.ID)
+	if err != nil {
+		log.Error("Ошибка записи события в журнал", "error", err, "event_type", event.EventType)
+		return fmt.Errorf("ошибка записи события в журнал: %w", err)
+	}
+
+	log.Debug("Событие записано в журнал", "id", event.ID, "event_type", event.EventType)
+	return nil
+}
+
+// ListEvents получает события журнала с фильтрацией и пагинацией, новые первыми
+func (l *AuditPostgresLogger) ListEvents(ctx context.Context, filter model.EventFilter) ([]model.SongEvent, error) {
+	log := l.logger.WithContext(ctx)
+
+	log.Debug("Получение журнала событий",
+		"song_id", filter.SongID,
+		"event_type", filter.EventType,
+		"page", filter.Page,
+		"pageSize", filter.PageSize)
+
+	query := `SELECT id, event_type, song_id, actor, payload, occurred_at
+		FROM song_events
+		WHERE ($1::BIGINT IS NULL OR song_id = $1)
+			AND ($2 = '' OR event_type = $2)
+			AND ($3::TIMESTAMP IS NULL OR occurred_at >= $3)
+			AND ($4::TIMESTAMP IS NULL OR occurred_at <= $4)
+		ORDER BY occurred_at DESC, id DESC
+		LIMIT $5 OFFSET $6`
+
+	offset := filter.Offset()
+	events := []model.SongEvent{}
+	err := sqlx.SelectContext(ctx, txOrDB(ctx, l.db), &events, query,
+		filter.SongID, filter.EventType, filter.From, filter.To, filter.PageSize, offset)
+	if err != nil {
+		log.Error("Ошибка получения журнала событий", "error", err)
+		return nil, fmt.Errorf("ошибка получения журнала событий: %w", err)
+	}
+
+	log.Info("Журнал событий успешно получен", "count", len(events))
+	return events, nil
+}
+
+// ListEventsPaged получает страницу событий журнала и общее количество событий, подходящих под фильтр
+func (l *AuditPostgresLogger) ListEventsPaged(ctx context.Context, filter model.EventFilter) ([]model.SongEvent, int64, error) {
+	log := l.logger.WithContext(ctx)
+
+	log.Debug("Получение страницы журнала событий",
+		"song_id", filter.SongID,
+		"event_type", filter.EventType,
+		"field", filter.Field,
+		"page", filter.Page,
+		"pageSize", filter.PageSize)
+
+	// Поле считается измененным, если оно различается в снимках before и after события song.updated.
+	// Изменения куплетов хранят текст до и после изменения в previous_text и text, а переименование группы — отдельным событием
+	where := `WHERE ($1::BIGINT IS NULL OR song_id = $1)
+			AND ($2 = '' OR event_type = $2)
+			AND ($3::TIMESTAMP IS NULL OR occurred_at >= $3)
+			AND ($4::TIMESTAMP IS NULL OR occurred_at <= $4)
+			AND ($5::TEXT = ''
+				OR payload->'before'->$5::TEXT IS DISTINCT FROM payload->'after'->$5::TEXT
+				OR ($5::TEXT = 'text' AND payload ? 'previous_text' AND payload->'previous_text' IS DISTINCT FROM payload->'text')
+				OR ($5::TEXT = 'group' AND event_type = '` + model.EventSongGroupRenamed + `'))`
+	query := `SELECT id, event_type, song_id, actor, payload, occurred_at, COUNT(*) OVER() AS total
+		FROM song_events
+		` + where + `
+		ORDER BY occurred_at DESC, id DESC
+		LIMIT $6 OFFSET $7`
+
+	offset := filter.Offset()
+	var rows []struct {
+		model.SongEvent
+		Total int64 `db:"total"`
+	}
+	err := sqlx.SelectContext(ctx, txOrDB(ctx, l.db), &rows, query,
+		filter.SongID, filter.EventType, filter.From, filter.To, filter.Field, filter.PageSize, offset)
+	if err != nil {
+		log.Error("Ошибка получения страницы журнала событий", "error", err)
+		return nil, 0, fmt.Errorf("ошибка получения страницы журнала событий: %w", err)
+	}
+
+	events := make([]model.SongEvent, 0, len(rows))
+	var total int64
+	for _, row := range rows {
+		events = append(events, row.SongEvent)
+		total = row.Total
+	}
+
+	// За последней страницей оконная функция не возвращает ни одной строки, поэтому количество считается отдельно
+	if len(rows) == 0 && offset > 0 {
+		err = txOrDB(ctx, l.db).QueryRowxContext(ctx, `SELECT COUNT(*) FROM song_events `+where,
+			filter.SongID, filter.EventType, filter.From, filter.To, filter.Field).Scan(&total)
+		if err != nil {
+			log.Error("Ошибка подсчета событий журнала", "error", err)
+			return nil, 0, fmt.Errorf("ошибка подсчета событий журнала: %w", err)
+		}
+	}
+
+	log.Info("Страница журнала событий успешно получена", "count", len(events), "total", total)
+	return events, total, nil
+}
+
+// DeleteEventsOlderThan удаляет события старше retentionDays дней
+func (l *AuditPostgresLogger) DeleteEventsOlderThan(ctx context.Context, retentionDays int) (int64, error) {
+	query := `DELETE FROM song_events WHERE occurred_at < NOW() - make_interval(days => $1)`
+
+	result, err := txOrDB(ctx, l.db).ExecContext(ctx, query, retentionDays)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка удаления старых событий: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("ошибка получения количества затронутых строк: %w", err)
+	}
+	return deleted, nil
+}
+
+// RunCleanup периодически удаляет события старше retentionDays дней и блокируется до отмены ctx.
+// Пока paused возвращает true (режим только для чтения), очистка пропускается
+func (l *AuditPostgresLogger) RunCleanup(ctx context.Context, interval time.Duration, retentionDays int, paused func() bool) {
+	l.logger.Info("Запуск очистки журнала событий", "interval", interval.String(), "retention_days", retentionDays)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			l.logger.Info("Очистка журнала событий остановлена")
+			return
+		case <-ticker.C:
+		}
+		if paused() {
+			l.logger.Debug("Очистка журнала событий пропущена в режиме только для чтения")
+			continue
+		}
+
+		deleted, err := l.DeleteEventsOlderThan(ctx, retentionDays)
+		if err != nil {
+			if ctx.Err() == nil {
+				l.logger.Error("Ошибка очистки журнала событий", "error", err)
+			}
+			continue
+		}
+		if deleted > 0 {
+			l.logger.Info("Старые события удалены из журнала", "count", deleted)
+		}
+	}
+}
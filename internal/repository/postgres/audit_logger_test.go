@@ -0,0 +1,296 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"song-library/internal/model"
+	"testing"
+	"time"
+)
+
+// newTestAuditLogger очищает таблицы временной схемы и создает журнал событий поверх testDB
+func newTestAuditLogger(t *testing.T) (*AuditPostgresLogger, *SongRepository) {
+	t.Helper()
+
+	repo := newTestRepository(t, RepositoryConfig{})
+	return NewAuditPostgresLogger(testDB, testLogger), repo
+}
+
+// eventIDs возвращает идентификаторы событий в порядке списка
+func eventIDs(events []model.SongEvent) []int64 {
+	ids := make([]int64, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+// mustLogEvent записывает событие типа eventType песни songID в момент occurredAt и возвращает его идентификатор
+func mustLogEvent(t *testing.T, audit *AuditPostgresLogger, eventType string, songID *int64, occurredAt time.Time, payload string) int64 {
+	t.Helper()
+
+	event := &model.SongEvent{
+		EventType:  eventType,
+		SongID:     songID,
+		Actor:      "192.0.2.10",
+		Payload:    json.RawMessage(payload),
+		OccurredAt: occurredAt,
+	}
+	if err := audit.LogEvent(context.Background(), event); err != nil {
+		t.Fatalf("LogEvent(%s) error = %v", eventType, err)
+	}
+	return event.ID
+}
+
+func TestLogEvent(t *testing.T) {
+	ctx := context.Background()
+	audit, repo := newTestAuditLogger(t)
+	songID := int64(1)
+
+	// Без времени и тела события подставляются текущее время и пустой объект
+	event := &model.SongEvent{EventType: model.EventSongCreated, SongID: &songID, Actor: "192.0.2.10"}
+	if err := audit.LogEvent(ctx, event); err != nil {
+		t.Fatalf("LogEvent() error = %v", err)
+	}
+	if event.ID == 0 || event.OccurredAt.IsZero() {
+		t.Errorf("LogEvent() ID = %d, OccurredAt = %v, want заполненные", event.ID, event.OccurredAt)
+	}
+
+	// Событие в транзакции репозитория откатывается вместе с ней
+	errRollback := errors.New("rollback")
+	err := repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := audit.LogEvent(ctx, &model.SongEvent{EventType: model.EventSongUpdated, Actor: "192.0.2.10"}); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("WithinTransaction() error = %v, want %v", err, errRollback)
+	}
+
+	events, err := audit.ListEvents(ctx, model.EventFilter{Pagination: model.Pagination{Page: 1, PageSize: 10}})
+	if err != nil {
+		t.Fatalf("ListEvents() error = %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("ListEvents() = %+v, want одно событие", events)
+	}
+	got := events[0]
+	if got.ID != event.ID || got.EventType != model.EventSongCreated || got.SongID == nil || *got.SongID != songID ||
+		got.Actor != "192.0.2.10" || string(got.Payload) != "{}" {
+		t.Errorf("ListEvents()[0] = %+v, want событие %d с пустым объектом", got, event.ID)
+	}
+}
+
+func TestListEvents(t *testing.T) {
+	ctx := context.Background()
+	audit, _ := newTestAuditLogger(t)
+	song1, song2 := int64(1), int64(2)
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 10, 0, 0, 0, time.UTC) }
+
+	created1 := mustLogEvent(t, audit, model.EventSongCreated, &song1, day(10), `{}`)
+	created2 := mustLogEvent(t, audit, model.EventSongCreated, &song2, day(12), `{}`)
+	updated1 := mustLogEvent(t, audit, model.EventSongUpdated, &song1, day(15), `{}`)
+	bulk := mustLogEvent(t, audit, model.EventSongsBulkCreated, nil, day(20), `{"count": 2}`)
+	from, to := day(11), day(16)
+
+	tests := []struct {
+		name   string
+		filter model.EventFilter
+		want   []int64
+	}{
+		{"без фильтра, новые первыми", model.EventFilter{}, []int64{bulk, updated1, created2, created1}},
+		{"по песне", model.EventFilter{SongID: &song1}, []int64{updated1, created1}},
+		{"по типу события", model.EventFilter{EventType: model.EventSongCreated}, []int64{created2, created1}},
+		{"с начала периода", model.EventFilter{From: &from}, []int64{bulk, updated1, created2}},
+		{"до конца периода", model.EventFilter{To: &to}, []int64{updated1, created2, created1}},
+		{"период и песня", model.EventFilter{SongID: &song1, From: &from, To: &to}, []int64{updated1}},
+		{"вторая страница", model.EventFilter{Pagination: model.Pagination{Page: 2, PageSize: 3}}, []int64{created1}},
+		{"за последней страницей", model.EventFilter{Pagination: model.Pagination{Page: 3, PageSize: 3}}, []int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := tt.filter
+			if filter.PageSize == 0 {
+				filter.Pagination = model.Pagination{Page: 1, PageSize: 10}
+			}
+
+			events, err := audit.ListEvents(ctx, filter)
+			if err != nil {
+				t.Fatalf("ListEvents() error = %v", err)
+			}
+			if got := eventIDs(events); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListEvents() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListEventsPaged(t *testing.T) {
+	ctx := context.Background()
+	audit, _ := newTestAuditLogger(t)
+	songID := int64(1)
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 10, 0, 0, 0, time.UTC) }
+
+	created := mustLogEvent(t, audit, model.EventSongCreated, &songID, day(10), `{}`)
+	textUpdated := mustLogEvent(t, audit, model.EventSongUpdated, &songID, day(11),
+		`{"before": {"text": "Ooh", "link": "https://a"}, "after": {"text": "Ooh baby", "link": "https://a"}}`)
+	linkUpdated := mustLogEvent(t, audit, model.EventSongUpdated, &songID, day(12),
+		`{"before": {"text": "Ooh baby", "link": "https://a"}, "after": {"text": "Ooh baby", "link": "https://b"}}`)
+	verseUpdated := mustLogEvent(t, audit, model.EventSongVerseUpdated, &songID, day(13),
+		`{"index": 0, "previous_text": "Ooh", "text": "Ooh yeah"}`)
+	groupRenamed := mustLogEvent(t, audit, model.EventSongGroupRenamed, &songID, day(14),
+		`{"group": "Muse", "new_group": "MUSE"}`)
+
+	tests := []struct {
+		name      string
+		filter    model.EventFilter
+		want      []int64
+		wantTotal int64
+	}{
+		{"без фильтра", model.EventFilter{}, []int64{groupRenamed, verseUpdated, linkUpdated, textUpdated, created}, 5},
+		{"изменение текста", model.EventFilter{Field: "text"}, []int64{verseUpdated, textUpdated}, 2},
+		{"изменение ссылки", model.EventFilter{Field: "link"}, []int64{linkUpdated}, 1},
+		{"переименование группы", model.EventFilter{Field: "group"}, []int64{groupRenamed}, 1},
+		{"поле без изменений", model.EventFilter{Field: "releaseDate"}, []int64{}, 0},
+		{"страница меньше выборки", model.EventFilter{Pagination: model.Pagination{Page: 2, PageSize: 2}},
+			[]int64{linkUpdated, textUpdated}, 5},
+		// За последней страницей количество считается отдельным запросом
+		{"за последней страницей", model.EventFilter{Field: "text", Pagination: model.Pagination{Page: 3, PageSize: 2}},
+			[]int64{}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := tt.filter
+			if filter.PageSize == 0 {
+				filter.Pagination = model.Pagination{Page: 1, PageSize: 10}
+			}
+
+			events, total, err := audit.ListEventsPaged(ctx, filter)
+			if err != nil {
+				t.Fatalf("ListEventsPaged() error = %v", err)
+			}
+			if got := eventIDs(events); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListEventsPaged() = %v, want %v", got, tt.want)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("ListEventsPaged() total = %d, want %d", total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestDeleteEventsOlderThan(t *testing.T) {
+	ctx := context.Background()
+	audit, _ := newTestAuditLogger(t)
+	now := time.Now()
+
+	mustLogEvent(t, audit, model.EventSongCreated, nil, now.AddDate(0, 0, -100), `{}`)
+	mustLogEvent(t, audit, model.EventSongCreated, nil, now.AddDate(0, 0, -40), `{}`)
+	recent := mustLogEvent(t, audit, model.EventSongCreated, nil, now.AddDate(0, 0, -1), `{}`)
+
+	deleted, err := audit.DeleteEventsOlderThan(ctx, 30)
+	if err != nil {
+		t.Fatalf("DeleteEventsOlderThan() error = %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("DeleteEventsOlderThan() = %d, want 2", deleted)
+	}
+
+	events, err := audit.ListEvents(ctx, model.EventFilter{Pagination: model.Pagination{Page: 1, PageSize: 10}})
+	if err != nil {
+		t.Fatalf("ListEvents() error = %v", err)
+	}
+	if got := eventIDs(events); !reflect.DeepEqual(got, []int64{recent}) {
+		t.Errorf("события после очистки = %v, want %v", got, []int64{recent})
+	}
+
+	// Повторная очистка ничего не удаляет
+	if deleted, err = audit.DeleteEventsOlderThan(ctx, 30); err != nil || deleted != 0 {
+		t.Errorf("повторный DeleteEventsOlderThan() = %d, %v, want 0, nil", deleted, err)
+	}
+}
+
+func TestAPICalls(t *testing.T) {
+	ctx := context.Background()
+	audit, _ := newTestAuditLogger(t)
+	songID := int64(7)
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 10, 0, 0, 0, time.UTC) }
+
+	record := func(actor, method, route string, songID *int64, occurredAt time.Time) int64 {
+		t.Helper()
+
+		call := &model.APICall{
+			Actor:      actor,
+			Method:     method,
+			Route:      route,
+			SongID:     songID,
+			Status:     200,
+			LatencyMs:  12,
+			RequestID:  "3f6c1a9e-7f1b-4c4e-9d2a-1b2c3d4e5f60",
+			OccurredAt: occurredAt,
+		}
+		if err := audit.RecordAPICall(ctx, call); err != nil {
+			t.Fatalf("RecordAPICall(%s %s) error = %v", method, route, err)
+		}
+		if call.ID == 0 {
+			t.Fatalf("RecordAPICall(%s %s) не присвоил идентификатор", method, route)
+		}
+		return call.ID
+	}
+
+	created := record("192.0.2.10", "POST", "/api/v1/songs", nil, day(10))
+	updated := record("192.0.2.10", "PUT", "/api/v1/songs/:id", &songID, day(12))
+	deleted := record("192.0.2.20", "DELETE", "/api/v1/songs/:id", &songID, day(15))
+	since := day(11)
+
+	tests := []struct {
+		name   string
+		filter model.APICallFilter
+		want   []int64
+	}{
+		{"без фильтра, новые первыми", model.APICallFilter{}, []int64{deleted, updated, created}},
+		{"с начала периода", model.APICallFilter{Since: &since}, []int64{deleted, updated}},
+		{"по автору", model.APICallFilter{Actor: "192.0.2.10"}, []int64{updated, created}},
+		{"по песне", model.APICallFilter{SongID: &songID}, []int64{deleted, updated}},
+		{"автор и песня", model.APICallFilter{Actor: "192.0.2.10", SongID: &songID}, []int64{updated}},
+		{"вторая страница", model.APICallFilter{Pagination: model.Pagination{Page: 2, PageSize: 2}}, []int64{created}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := tt.filter
+			if filter.PageSize == 0 {
+				filter.Pagination = model.Pagination{Page: 1, PageSize: 10}
+			}
+
+			calls, err := audit.ListAPICalls(ctx, filter)
+			if err != nil {
+				t.Fatalf("ListAPICalls() error = %v", err)
+			}
+			ids := make([]int64, 0, len(calls))
+			for _, call := range calls {
+				ids = append(ids, call.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("ListAPICalls() = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+
+	calls, err := audit.ListAPICalls(ctx, model.APICallFilter{SongID: &songID, Pagination: model.Pagination{Page: 1, PageSize: 1}})
+	if err != nil || len(calls) != 1 {
+		t.Fatalf("ListAPICalls() = %+v, %v", calls, err)
+	}
+	got := calls[0]
+	if got.Method != "DELETE" || got.Route != "/api/v1/songs/:id" || got.Status != 200 || got.LatencyMs != 12 ||
+		got.RequestID == "" || !got.OccurredAt.Equal(day(15)) {
+		t.Errorf("ListAPICalls()[0] = %+v", got)
+	}
+}
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"song-library/internal/model"
+)
+
+// AddVerseBookmark сохраняет закладку куплета клиента; повторное добавление заменяет заметку
+func (r *SongRepository) AddVerseBookmark(ctx context.Context, clientID string, songID int64, position int, note string) error {
+	log := r.logger.WithFields(ctx, "song_id", songID, "verse_position", position)
+
+	log.Debug("Добавление закладки куплета")
+
+	query := `INSERT INTO bookmarks (client_id, song_id, verse_position, note)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (client_id, song_id, verse_position) DO UPDATE SET note = EXCLUDED.note`
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, clientID, songID, position, note); err != nil {
+		log.Error("Ошибка добавления закладки куплета", "error", err)
+		return fmt.Errorf("ошибка добавления закладки куплета: %w", err)
+	}
+
+	log.Info("Закладка куплета успешно добавлена")
+	return nil
+}
+
+// RemoveVerseBookmark удаляет закладку куплета клиента. Возвращает false, если закладки не было.
+func (r *SongRepository) RemoveVerseBookmark(ctx context.Context, clientID string, songID int64, position int) (bool, error) {
+	log := r.logger.WithFields(ctx, "song_id", songID, "verse_position", position)
+
+	log.Debug("Удаление закладки куплета")
+
+	query := `DELETE FROM bookmarks WHERE client_id = $1 AND song_id = $2 AND verse_position = $3`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, clientID, songID, position)
+	if err != nil {
+		log.Error("Ошибка удаления закладки куплета", "error", err)
+		return false, fmt.Errorf("ошибка удаления закладки куплета: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Error("Ошибка получения количества удаленных строк", "error", err)
+		return false, fmt.Errorf("ошибка получения количества удаленных строк: %w", err)
+	}
+
+	log.Info("Закладка куплета удалена", "removed", rowsAffected > 0)
+	return rowsAffected > 0, nil
+}
+
+// ListVerseBookmarks получает закладки куплетов клиента вместе с текстом песен, новые первыми.
+// Закладки удаленных песен не возвращаются.
+func (r *SongRepository) ListVerseBookmarks(ctx context.Context, clientID string) ([]model.VerseBookmark, error) {
+	log := r.logger.WithContext(ctx)
+
+	log.Debug("Получение закладок куплетов")
+
+	query := `SELECT b.song_id, s.group_name, s.song_name, b.verse_position, b.note, b.created_at, s.text
+		FROM bookmarks b
+		JOIN songs s ON s.id = b.song_id AND s.deleted_at IS NULL
+		WHERE b.client_id = $1
+		ORDER BY b.created_at DESC, b.song_id, b.verse_position`
+
+	bookmarks := []model.VerseBookmark{}
+	if err := sqlx.SelectContext(ctx, r.conn(ctx), &bookmarks, query, clientID); err != nil {
+		log.Error("Ошибка получения закладок куплетов", "error", err)
+		return nil, fmt.Errorf("ошибка получения закладок куплетов: %w", err)
+	}
+
+	log.Info("Закладки куплетов успешно получены", "count", len(bookmarks))
+	return bookmarks, nil
+}
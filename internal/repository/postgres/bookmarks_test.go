@@ -0,0 +1,78 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+)
+
+func TestVerseBookmarks(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	song := mustCreateSong(t, repo, newTestSong("Muse", "Hysteria"))
+	deleted := mustCreateSong(t, repo, newTestSong("Muse", "Uprising"))
+
+	if err := repo.AddVerseBookmark(ctx, "client-1", song.ID, 2, "припев"); err != nil {
+		t.Fatalf("AddVerseBookmark() error = %v", err)
+	}
+	// Повторное добавление заменяет заметку
+	if err := repo.AddVerseBookmark(ctx, "client-1", song.ID, 2, "любимый припев"); err != nil {
+		t.Fatalf("повторный AddVerseBookmark() error = %v", err)
+	}
+	if err := repo.AddVerseBookmark(ctx, "client-1", deleted.ID, 1, ""); err != nil {
+		t.Fatalf("AddVerseBookmark() error = %v", err)
+	}
+	if err := repo.AddVerseBookmark(ctx, "client-2", song.ID, 1, ""); err != nil {
+		t.Fatalf("AddVerseBookmark() error = %v", err)
+	}
+	if err := repo.AddVerseBookmark(ctx, "client-1", song.ID, 0, ""); err == nil {
+		t.Error("AddVerseBookmark() с позицией 0 error = nil, want нарушение CHECK")
+	}
+	if err := repo.AddVerseBookmark(ctx, "client-1", 999, 1, ""); err == nil {
+		t.Error("AddVerseBookmark() для отсутствующей песни error = nil, want нарушение внешнего ключа")
+	}
+	if err := repo.DeleteSong(ctx, deleted.ID); err != nil {
+		t.Fatalf("DeleteSong() error = %v", err)
+	}
+
+	// Закладки удаленных песен и других клиентов не возвращаются
+	bookmarks, err := repo.ListVerseBookmarks(ctx, "client-1")
+	if err != nil {
+		t.Fatalf("ListVerseBookmarks() error = %v", err)
+	}
+	if len(bookmarks) != 1 {
+		t.Fatalf("ListVerseBookmarks() = %+v, want 1 закладку", bookmarks)
+	}
+	got := bookmarks[0]
+	if got.SongID != song.ID || got.VersePosition != 2 || got.Note != "любимый припев" || got.Text != song.Text || got.Group != "Muse" {
+		t.Errorf("ListVerseBookmarks()[0] = %+v", got)
+	}
+
+	tests := []struct {
+		name        string
+		clientID    string
+		position    int
+		wantRemoved bool
+	}{
+		{"закладка есть", "client-1", 2, true},
+		{"закладка уже удалена", "client-1", 2, false},
+		{"закладка другого клиента", "client-3", 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			removed, err := repo.RemoveVerseBookmark(ctx, tt.clientID, song.ID, tt.position)
+			if err != nil {
+				t.Fatalf("RemoveVerseBookmark() error = %v", err)
+			}
+			if removed != tt.wantRemoved {
+				t.Errorf("RemoveVerseBookmark() = %v, want %v", removed, tt.wantRemoved)
+			}
+		})
+	}
+
+	if bookmarks, err = repo.ListVerseBookmarks(ctx, "client-1"); err != nil || len(bookmarks) != 0 {
+		t.Errorf("ListVerseBookmarks() после удаления = %+v, %v, want пустой список", bookmarks, err)
+	}
+}
@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"song-library/internal/model"
+	"strings"
+	"time"
+)
+
+// uniqueViolationCode код ошибки PostgreSQL при нарушении ограничения уникальности
+const uniqueViolationCode = "23505"
+
+// BulkInsertSongs вставляет песни одной транзакцией. Для наборов от CopyThreshold
+// записей используется протокол COPY, для меньших — многострочный INSERT.
+func (r *SongRepository) BulkInsertSongs(ctx context.Context, songs []*model.Song) (int64, error) {
+	if len(songs) == 0 {
+		return 0, nil
+	}
+
+	if r.cfg.CopyThreshold > 0 && len(songs) >= r.cfg.CopyThreshold {
+		return r.BulkInsertViaCopy(ctx, songs)
+	}
+	return r.bulkInsertViaValues(ctx, songs)
+}
+
+// BulkInsertViaCopy вставляет песни через протокол COPY и возвращает количество вставленных строк
+func (r *SongRepository) BulkInsertViaCopy(ctx context.Context, songs []*model.Song) (int64, error) {
+	log := r.logger.WithContext(ctx)
+
+	log.Debug("Массовая вставка песен через COPY", "count", len(songs))
+
+	var inserted int64
+	err := r.WithinTransaction(ctx, func(ctx context.Context) error {
+		tx := ctx.Value(txKey{}).(*sqlx.Tx)
+
+		stmt, err := tx.PrepareContext(ctx, pq.CopyIn("songs",
+			"group_name", "song_name", "release_date", "text", "link", "created_at", "updated_at", "duration_seconds",
+			"group_name_norm", "song_name_norm", "source", "bpm", "content_hash"))
+		if err != nil {
+			return fmt.Errorf("ошибка подготовки COPY: %w", err)
+		}
+		defer stmt.Close()
+
+		now := time.Now()
+		for _, song := range songs {
+			song.CreatedAt = now
+			song.UpdatedAt = now
+			song.ComputeContentHash()
+			if _, err = stmt.ExecContext(ctx, song.Group, song.Song, song.ReleaseDate, song.Text, song.Link,
+				song.CreatedAt, song.UpdatedAt, song.Duration,
+				model.NormalizeName(song.Group), model.NormalizeName(song.Song), song.Provenance, song.BPM, song.ContentHash); err != nil {
+				return fmt.Errorf("ошибка передачи строки COPY: %w", err)
+			}
+		}
+
+		if _, err = stmt.ExecContext(ctx); err != nil {
+			return fmt.Errorf("ошибка завершения COPY: %w", err)
+		}
+
+		inserted = int64(len(songs))
+		return nil
+	})
+	if err != nil {
+		log.Error("Ошибка массовой вставки песен через COPY", "error", err)
+		return 0, wrapUniqueViolation(err)
+	}
+
+	log.Info("Песни успешно вставлены через COPY", "count", inserted)
+	return inserted, nil
+}
+
+// bulkInsertColumns количество вставляемых столбцов одной песни
+const bulkInsertColumns = 13
+
+// maxValuesRows максимальное количество строк одного многострочного INSERT:
+// PostgreSQL допускает не больше 65535 параметров в запросе
+const maxValuesRows = 65535 / bulkInsertColumns
+
+// bulkInsertViaValues вставляет песни многострочными INSERT не больше maxValuesRows строк
+// каждый. Все части выполняются в одной транзакции.
+func (r *SongRepository) bulkInsertViaValues(ctx context.Context, songs []*model.Song) (int64, error) {
+	log := r.logger.WithContext(ctx)
+
+	log.Debug("Массовая вставка песен через INSERT", "count", len(songs))
+
+	now := time.Now()
+	for _, song := range songs {
+		song.CreatedAt = now
+		song.UpdatedAt = now
+		song.ComputeContentHash()
+	}
+
+	var inserted int64
+	err := r.WithinTransaction(ctx, func(ctx context.Context) error {
+		for start := 0; start < len(songs); start += maxValuesRows {
+			query, params := valuesInsertQuery(songs[start:min(start+maxValuesRows, len(songs))])
+			result, err := r.conn(ctx).ExecContext(ctx, query, params...)
+			if err != nil {
+				return fmt.Errorf("ошибка массовой вставки песен: %w", err)
+			}
+			affected, err := result.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("ошибка получения количества затронутых строк: %w", err)
+			}
+			inserted += affected
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error("Ошибка массовой вставки песен", "error", err)
+		return 0, wrapUniqueViolation(err)
+	}
+
+	log.Info("Песни успешно вставлены", "count", inserted)
+	return inserted, nil
+}
+
+// valuesInsertQuery формирует многострочный INSERT песен и его параметры
+func valuesInsertQuery(songs []*model.Song) (string, []interface{}) {
+	placeholders := make([]string, 0, len(songs))
+	params := make([]interface{}, 0, len(songs)*bulkInsertColumns)
+	for i, song := range songs {
+		base := i * bulkInsertColumns
+		placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
+			base+1, base+2, base+3, base+4, base+5, base+6, base+7, base+8, base+9, base+10, base+11, base+12, base+13))
+		params = append(params, song.Group, song.Song, song.ReleaseDate, song.Text, song.Link,
+			song.CreatedAt, song.UpdatedAt, song.Duration,
+			model.NormalizeName(song.Group), model.NormalizeName(song.Song), song.Provenance, song.BPM, song.ContentHash)
+	}
+
+	query := `INSERT INTO songs (group_name, song_name, release_date, text, link, created_at, updated_at, duration_seconds,
+			group_name_norm, song_name_norm, source, bpm, content_hash)
+		VALUES ` + strings.Join(placeholders, ", ")
+	return query, params
+}
+
+// wrapUniqueViolation дополняет ошибку нарушения уникальности сигнальной ошибкой ErrSongAlreadyExists
+func wrapUniqueViolation(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolationCode {
+		return fmt.Errorf("%w: %w", model.ErrSongAlreadyExists, err)
+	}
+	return err
+}
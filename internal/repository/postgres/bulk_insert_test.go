@@ -0,0 +1,143 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"song-library/internal/model"
+	"testing"
+)
+
+// newTestSongs возвращает count песен группы group с названиями Song 1, Song 2, ...
+func newTestSongs(group string, count int) []*model.Song {
+	songs := make([]*model.Song, 0, count)
+	for i := range count {
+		songs = append(songs, newTestSong(group, fmt.Sprintf("Song %d", i+1)))
+	}
+	return songs
+}
+
+func TestBulkInsertSongs(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   RepositoryConfig
+		count int
+	}{
+		{"пустой набор", RepositoryConfig{}, 0},
+		{"многострочный INSERT", RepositoryConfig{}, 5},
+		{"многострочный INSERT из нескольких частей", RepositoryConfig{}, maxValuesRows + 1},
+		{"меньше порога COPY", RepositoryConfig{CopyThreshold: 10}, 9},
+		{"COPY с порога", RepositoryConfig{CopyThreshold: 10}, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newTestRepository(t, tt.cfg)
+			songs := newTestSongs("Muse", tt.count)
+
+			inserted, err := repo.BulkInsertSongs(ctx, songs)
+			if err != nil {
+				t.Fatalf("BulkInsertSongs() error = %v", err)
+			}
+			if inserted != int64(tt.count) {
+				t.Errorf("BulkInsertSongs() = %d, want %d", inserted, tt.count)
+			}
+			assertBulkInserted(t, repo, songs)
+		})
+	}
+}
+
+func TestBulkInsertViaCopy(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	songs := newTestSongs("Muse", 3)
+	songs[1].Group = "Motörhead"
+
+	inserted, err := repo.BulkInsertViaCopy(ctx, songs)
+	if err != nil {
+		t.Fatalf("BulkInsertViaCopy() error = %v", err)
+	}
+	if inserted != 3 {
+		t.Errorf("BulkInsertViaCopy() = %d, want 3", inserted)
+	}
+	assertBulkInserted(t, repo, songs)
+
+	// Нормализованная группа, записанная через COPY, находится поиском
+	found, err := repo.GetSongs(ctx, model.SongFilter{Group: "motorhead", Pagination: model.Pagination{Page: 1, PageSize: 10}})
+	if err != nil || len(found) != 1 || found[0].Song != "Song 2" {
+		t.Errorf("GetSongs(motorhead) = %v, %v", found, err)
+	}
+}
+
+func TestBulkInsertDuplicates(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    RepositoryConfig
+		insert func(repo *SongRepository, songs []*model.Song) (int64, error)
+	}{
+		{"многострочный INSERT", RepositoryConfig{}, func(repo *SongRepository, songs []*model.Song) (int64, error) {
+			return repo.BulkInsertSongs(context.Background(), songs)
+		}},
+		{"COPY", RepositoryConfig{}, func(repo *SongRepository, songs []*model.Song) (int64, error) {
+			return repo.BulkInsertViaCopy(context.Background(), songs)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestRepository(t, tt.cfg)
+			mustCreateSong(t, repo, newTestSong("Muse", "Song 2"))
+
+			// Дубликат активной песни отменяет вставку всего набора
+			if _, err := tt.insert(repo, newTestSongs("Muse", 3)); !errors.Is(err, model.ErrSongAlreadyExists) {
+				t.Fatalf("вставка с дубликатом error = %v, want %v", err, model.ErrSongAlreadyExists)
+			}
+			var count int
+			if err := testDB.Get(&count, `SELECT count(*) FROM songs`); err != nil {
+				t.Fatalf("ошибка подсчета песен: %v", err)
+			}
+			if count != 1 {
+				t.Errorf("песен после неудачной вставки = %d, want 1", count)
+			}
+
+			// Дубликат внутри набора тоже нарушает уникальность
+			songs := []*model.Song{newTestSong("Queen", "Innuendo"), newTestSong("Queen", "Innuendo")}
+			if _, err := tt.insert(repo, songs); !errors.Is(err, model.ErrSongAlreadyExists) {
+				t.Errorf("вставка набора с повтором error = %v, want %v", err, model.ErrSongAlreadyExists)
+			}
+		})
+	}
+}
+
+// assertBulkInserted проверяет, что песни сохранены с хэшем содержимого и публичным UUID
+func assertBulkInserted(t *testing.T, repo *SongRepository, songs []*model.Song) {
+	t.Helper()
+
+	var stored []struct {
+		Group       string `db:"group_name"`
+		Song        string `db:"song_name"`
+		PublicID    string `db:"public_id"`
+		ContentHash string `db:"content_hash"`
+	}
+	if err := testDB.Select(&stored, `SELECT group_name, song_name, public_id, content_hash FROM songs ORDER BY id`); err != nil {
+		t.Fatalf("ошибка чтения песен: %v", err)
+	}
+	if len(stored) != len(songs) {
+		t.Fatalf("сохранено песен = %d, want %d", len(stored), len(songs))
+	}
+
+	publicIDs := make(map[string]bool, len(stored))
+	for i, row := range stored {
+		if row.Group != songs[i].Group || row.Song != songs[i].Song || row.ContentHash != songs[i].ContentHash {
+			t.Errorf("песня %d = %+v, want %s, %s, %s", i, row, songs[i].Group, songs[i].Song, songs[i].ContentHash)
+		}
+		if _, err := uuid.Parse(row.PublicID); err != nil || publicIDs[row.PublicID] {
+			t.Errorf("песня %d public_id = %q: не уникальный UUID", i, row.PublicID)
+		}
+		publicIDs[row.PublicID] = true
+	}
+}
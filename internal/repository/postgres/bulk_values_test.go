@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"fmt"
+	"song-library/internal/model"
+	"strings"
+	"testing"
+)
+
+func TestValuesInsertQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		count    int
+		wantLast string
+	}{
+		{"одна строка", 1, "$13)"},
+		{"три строки", 3, "$39)"},
+		{"максимальная часть", maxValuesRows, fmt.Sprintf("$%d)", maxValuesRows*bulkInsertColumns)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			songs := make([]*model.Song, tt.count)
+			for i := range songs {
+				songs[i] = &model.Song{Group: "Muse", Song: fmt.Sprintf("Song %d", i+1)}
+			}
+
+			query, params := valuesInsertQuery(songs)
+			if len(params) != tt.count*bulkInsertColumns {
+				t.Errorf("количество параметров = %d, want %d", len(params), tt.count*bulkInsertColumns)
+			}
+			if len(params) > 65535 {
+				t.Errorf("количество параметров %d превышает ограничение PostgreSQL", len(params))
+			}
+			if got := strings.Count(query, "($"); got != tt.count {
+				t.Errorf("количество строк VALUES = %d, want %d", got, tt.count)
+			}
+			if !strings.HasSuffix(query, tt.wantLast) {
+				t.Errorf("запрос заканчивается на %q, want %q", query[len(query)-10:], tt.wantLast)
+			}
+		})
+	}
+}
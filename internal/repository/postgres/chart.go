@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"song-library/internal/model"
+	"time"
+)
+
+// UpdateSongChart устанавливает позицию песни в чарте и добавляет снимок позиции на дату date в историю.
+// В истории остаются последние model.MaxChartHistory снимков
+func (r *SongRepository) UpdateSongChart(ctx context.Context, id int64, position int16, date string) error {
+	log := r.logger.WithFields(ctx, "id", id)
+
+	log.Debug("Обновление позиции песни в чарте", "position", position, "date", date)
+
+	// Снимки нумеруются после добавления нового, и сохраняются только последние $3 из них в исходном порядке
+	query := `UPDATE songs SET chart_position = $1, updated_at = $4,
+		chart_history = (
+			SELECT COALESCE(jsonb_agg(entry ORDER BY ord), '[]'::jsonb)
+			FROM (
+				SELECT entry, ord
+				FROM jsonb_array_elements(chart_history || jsonb_build_array(jsonb_build_object('position', $1::smallint, 'date', $2::text)))
+					WITH ORDINALITY AS h(entry, ord)
+				ORDER BY ord DESC
+				LIMIT $3
+			) latest
+		)
+		WHERE id = $5 AND deleted_at IS NULL`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, position, date, model.MaxChartHistory, time.Now(), id)
+	if err != nil {
+		log.Error("Ошибка обновления позиции песни в чарте", "error", err)
+		return fmt.Errorf("ошибка обновления позиции песни в чарте: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Error("Ошибка получения количества затронутых строк", "error", err)
+		return fmt.Errorf("ошибка получения количества затронутых строк: %w", err)
+	}
+	if rowsAffected == 0 {
+		log.Info("Песня для обновления позиции в чарте не найдена")
+		return model.NewNotFoundError(id)
+	}
+
+	log.Info("Позиция песни в чарте успешно обновлена")
+	return nil
+}
+
+// GetChartToppers получает активные песни без текста, занимавшие первую позицию в чарте на дату date
+func (r *SongRepository) GetChartToppers(ctx context.Context, date string) ([]*model.Song, error) {
+	log := r.logger.WithContext(ctx)
+
+	log.Debug("Получение лидеров чарта", "date", date)
+
+	snapshot, err := json.Marshal(model.ChartHistory{{Position: 1, Date: date}})
+	if err != nil {
+		log.Error("Ошибка сериализации снимка чарта", "error", err)
+		return nil, fmt.Errorf("ошибка сериализации снимка чарта: %w", err)
+	}
+
+	query := `SELECT ` + songColumnsWithoutText + ` FROM songs
+		WHERE chart_history @> $1::jsonb AND deleted_at IS NULL
+		ORDER BY group_name_norm, song_name_norm, id`
+
+	songs := []*model.Song{}
+	if err = sqlx.SelectContext(ctx, r.readConn(ctx), &songs, query, string(snapshot)); err != nil {
+		log.Error("Ошибка получения лидеров чарта", "error", err)
+		return nil, fmt.Errorf("ошибка получения лидеров чарта: %w", err)
+	}
+
+	log.Info("Лидеры чарта успешно получены", "count", len(songs))
+	return songs, nil
+}
@@ -0,0 +1,107 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"song-library/internal/model"
+	"testing"
+	"time"
+)
+
+func TestUpdateSongChart(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	song := mustCreateSong(t, repo, newTestSong("Muse", "Hysteria"))
+
+	// Снимков больше, чем хранится: самые старые удаляются, порядок остается от старых к новым
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	total := model.MaxChartHistory + 3
+	for i := range total {
+		date := start.AddDate(0, 0, 7*i).Format(time.DateOnly)
+		if err := repo.UpdateSongChart(ctx, song.ID, int16(i%model.MaxChartPosition+1), date); err != nil {
+			t.Fatalf("UpdateSongChart() #%d error = %v", i, err)
+		}
+	}
+
+	got, err := repo.GetSongByID(ctx, song.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetSongByID() = %v, %v", got, err)
+	}
+	if len(got.ChartHistory) != model.MaxChartHistory {
+		t.Fatalf("len(ChartHistory) = %d, want %d", len(got.ChartHistory), model.MaxChartHistory)
+	}
+	first := model.ChartEntry{Position: 4, Date: start.AddDate(0, 0, 21).Format(time.DateOnly)}
+	last := model.ChartEntry{Position: int16(total), Date: start.AddDate(0, 0, 7*(total-1)).Format(time.DateOnly)}
+	if got.ChartHistory[0] != first || got.ChartHistory[len(got.ChartHistory)-1] != last {
+		t.Errorf("ChartHistory первый и последний снимки = %+v, %+v, want %+v, %+v",
+			got.ChartHistory[0], got.ChartHistory[len(got.ChartHistory)-1], first, last)
+	}
+	if got.ChartPosition == nil || *got.ChartPosition != last.Position {
+		t.Errorf("ChartPosition = %v, want %d", got.ChartPosition, last.Position)
+	}
+
+	if err = repo.UpdateSongChart(ctx, 999, 1, "2024-01-01"); !isNotFound(999)(err) {
+		t.Errorf("UpdateSongChart(999) error = %v", err)
+	}
+}
+
+func TestGetChartToppers(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+
+	positions := []struct {
+		group, song string
+		chart       map[string]int16
+	}{
+		{"Queen", "Innuendo", map[string]int16{"2024-01-01": 1, "2024-01-08": 2}},
+		{"Muse", "Hysteria", map[string]int16{"2024-01-01": 1, "2024-01-08": 1}},
+		{"Muse", "Uprising", map[string]int16{"2024-01-01": 3}},
+		{"Muse", "Deleted", map[string]int16{"2024-01-01": 1}},
+	}
+	for _, p := range positions {
+		song := mustCreateSong(t, repo, newTestSong(p.group, p.song))
+		for _, date := range []string{"2024-01-01", "2024-01-08"} {
+			if position, ok := p.chart[date]; ok {
+				if err := repo.UpdateSongChart(ctx, song.ID, position, date); err != nil {
+					t.Fatalf("UpdateSongChart() error = %v", err)
+				}
+			}
+		}
+		if p.song == "Deleted" {
+			if err := repo.DeleteSong(ctx, song.ID); err != nil {
+				t.Fatalf("DeleteSong() error = %v", err)
+			}
+		}
+	}
+
+	tests := []struct {
+		date      string
+		wantSongs []string
+	}{
+		{"2024-01-01", []string{"Muse/Hysteria", "Queen/Innuendo"}},
+		{"2024-01-08", []string{"Muse/Hysteria"}},
+		{"2024-01-15", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			songs, err := repo.GetChartToppers(ctx, tt.date)
+			if err != nil {
+				t.Fatalf("GetChartToppers() error = %v", err)
+			}
+			got := []string{}
+			for _, song := range songs {
+				got = append(got, fmt.Sprintf("%s/%s", song.Group, song.Song))
+				if song.Text != "" {
+					t.Errorf("GetChartToppers() вернул текст песни %s", song.Song)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.wantSongs) {
+				t.Errorf("GetChartToppers() = %v, want %v", got, tt.wantSongs)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"song-library/internal/model"
+)
+
+// GetDeletedSongs получает удаленные песни, начиная с удаленных последними
+func (r *SongRepository) GetDeletedSongs(ctx context.Context, page model.Pagination) ([]*model.Song, error) {
+	log := r.logger.WithContext(ctx)
+
+	log.Debug("Получение удаленных песен", "page", page.Page, "pageSize", page.PageSize)
+
+	query := `SELECT ` + songListColumns + `, deleted_at FROM songs WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id DESC LIMIT $1 OFFSET $2`
+
+	songs := []*model.Song{}
+	if err := sqlx.SelectContext(ctx, r.conn(ctx), &songs, query, page.PageSize, page.Offset()); err != nil {
+		log.Error("Ошибка получения удаленных песен", "error", err)
+		return nil, fmt.Errorf("ошибка получения удаленных песен: %w", err)
+	}
+
+	log.Info("Удаленные песни успешно получены", "count", len(songs))
+	return songs, nil
+}
+
+// GetDeletedSongByIDForUpdate получает удаленную песню по идентификатору и блокирует строку до конца транзакции.
+// Возвращает nil, если песня не найдена или не удалена.
+func (r *SongRepository) GetDeletedSongByIDForUpdate(ctx context.Context, id int64) (*model.Song, error) {
+	log := r.logger.WithFields(ctx, "id", id)
+
+	log.Debug("Получение удаленной песни по ID с блокировкой")
+
+	query := `SELECT ` + songColumns + `, deleted_at FROM songs WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE`
+
+	return r.getSong(ctx, r.conn(ctx), query, id)
+}
+
+// FindActiveSongID возвращает идентификатор неудаленной песни с указанными группой и названием или 0, если ее нет
+func (r *SongRepository) FindActiveSongID(ctx context.Context, group, song string) (int64, error) {
+	log := r.logger.WithFields(ctx, "group", group, "song", song)
+
+	query := `SELECT id FROM songs WHERE group_name = $1 AND song_name = $2 AND deleted_at IS NULL`
+
+	var id int64
+	err := r.conn(ctx).QueryRowxContext(ctx, query, group, song).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		log.Error("Ошибка поиска активной песни", "error", err)
+		return 0, fmt.Errorf("ошибка поиска активной песни: %w", err)
+	}
+	return id, nil
+}
+
+// RestoreSong снимает пометку удаления с песни
+func (r *SongRepository) RestoreSong(ctx context.Context, id int64) error {
+	log := r.logger.WithFields(ctx, "id", id)
+
+	log.Debug("Восстановление песни")
+
+	query := `UPDATE songs SET deleted_at = NULL, merged_into_id = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, id)
+	if err != nil {
+		log.Error("Ошибка восстановления песни", "error", err)
+		return wrapUniqueViolation(fmt.Errorf("ошибка восстановления песни: %w", err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Error("Ошибка получения количества затронутых строк", "error", err)
+		return fmt.Errorf("ошибка получения количества затронутых строк: %w", err)
+	}
+	if rowsAffected == 0 {
+		log.Info("Удаленная песня для восстановления не найдена")
+		return model.NewNotFoundError(id)
+	}
+
+	log.Info("Песня успешно восстановлена")
+	return nil
+}
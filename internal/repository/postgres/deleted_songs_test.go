@@ -0,0 +1,165 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"song-library/internal/model"
+	"testing"
+)
+
+func TestGetDeletedSongs(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	for _, name := range []string{"Hysteria", "Uprising", "Starlight", "Active"} {
+		mustCreateSong(t, repo, newTestSong("Muse", name))
+	}
+	// Песни удаляются в порядке 2, 1, 3; время удаления задается явно, чтобы порядок не зависел от точности часов
+	for i, id := range []int64{2, 1, 3} {
+		mustExec(t, `UPDATE songs SET deleted_at = '2024-01-01'::timestamp + $1::int * interval '1 hour' WHERE id = $2`, i, id)
+	}
+
+	tests := []struct {
+		name    string
+		page    model.Pagination
+		wantIDs []int64
+	}{
+		{"удаленные последними первыми", model.Pagination{Page: 1, PageSize: 10}, []int64{3, 1, 2}},
+		{"вторая страница", model.Pagination{Page: 2, PageSize: 2}, []int64{2}},
+		{"страница за пределами корзины", model.Pagination{Page: 3, PageSize: 2}, []int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			songs, err := repo.GetDeletedSongs(ctx, tt.page)
+			if err != nil {
+				t.Fatalf("GetDeletedSongs() error = %v", err)
+			}
+			if got := songIDs(songs); !reflect.DeepEqual(got, tt.wantIDs) {
+				t.Errorf("GetDeletedSongs() ids = %v, want %v", got, tt.wantIDs)
+			}
+			for _, song := range songs {
+				if song.DeletedAt == nil {
+					t.Errorf("песня %d DeletedAt = nil", song.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestGetDeletedSongByIDForUpdate(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	active := mustCreateSong(t, repo, newTestSong("Muse", "Hysteria"))
+	deleted := mustCreateSong(t, repo, newTestSong("Muse", "Uprising"))
+	if err := repo.DeleteSong(ctx, deleted.ID); err != nil {
+		t.Fatalf("DeleteSong() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		id        int64
+		wantFound bool
+	}{
+		{"удаленная песня", deleted.ID, true},
+		{"активная песня", active.ID, false},
+		{"отсутствующая песня", 999, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := repo.WithinTransaction(ctx, func(ctx context.Context) error {
+				song, err := repo.GetDeletedSongByIDForUpdate(ctx, tt.id)
+				if err != nil {
+					return err
+				}
+				if (song != nil) != tt.wantFound {
+					t.Errorf("GetDeletedSongByIDForUpdate() = %v, want найдена %v", song, tt.wantFound)
+				}
+				if song != nil && song.DeletedAt == nil {
+					t.Error("DeletedAt = nil")
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("WithinTransaction() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestFindActiveSongID(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	active := mustCreateSong(t, repo, newTestSong("Muse", "Hysteria"))
+	deleted := mustCreateSong(t, repo, newTestSong("Muse", "Uprising"))
+	if err := repo.DeleteSong(ctx, deleted.ID); err != nil {
+		t.Fatalf("DeleteSong() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		group, song string
+		wantID      int64
+	}{
+		{"активная песня", "Muse", "Hysteria", active.ID},
+		{"удаленная песня", "Muse", "Uprising", 0},
+		{"точное совпадение с учетом регистра", "muse", "hysteria", 0},
+		{"отсутствующая песня", "Queen", "Innuendo", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := repo.FindActiveSongID(ctx, tt.group, tt.song)
+			if err != nil {
+				t.Fatalf("FindActiveSongID() error = %v", err)
+			}
+			if id != tt.wantID {
+				t.Errorf("FindActiveSongID() = %d, want %d", id, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestRestoreSong(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	restorable := mustCreateSong(t, repo, newTestSong("Muse", "Hysteria"))
+	merged := mustCreateSong(t, repo, newTestSong("Muse", "Uprising"))
+	target := mustCreateSong(t, repo, newTestSong("Muse", "Starlight"))
+	if err := repo.DeleteSong(ctx, restorable.ID); err != nil {
+		t.Fatalf("DeleteSong() error = %v", err)
+	}
+	if err := repo.MarkSongMerged(ctx, merged.ID, target.ID); err != nil {
+		t.Fatalf("MarkSongMerged() error = %v", err)
+	}
+	// Название объединенной песни занято новой песней: восстановление нарушает частичный уникальный индекс
+	mustCreateSong(t, repo, newTestSong("Muse", "Uprising"))
+
+	tests := []struct {
+		name    string
+		id      int64
+		wantErr func(error) bool
+	}{
+		{"песня в корзине", restorable.ID, func(err error) bool { return err == nil }},
+		{"песня уже восстановлена", restorable.ID, isNotFound(restorable.ID)},
+		{"активная песня", target.ID, isNotFound(target.ID)},
+		{"название занято", merged.ID, func(err error) bool { return errors.Is(err, model.ErrSongAlreadyExists) }},
+		{"отсутствующая песня", 999, isNotFound(999)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := repo.RestoreSong(ctx, tt.id); !tt.wantErr(err) {
+				t.Errorf("RestoreSong() error = %v", err)
+			}
+		})
+	}
+
+	song, err := repo.GetSongByID(ctx, restorable.ID)
+	if err != nil || song == nil {
+		t.Fatalf("GetSongByID() восстановленной песни = %v, %v", song, err)
+	}
+}
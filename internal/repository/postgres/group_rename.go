@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"song-library/internal/model"
+	"time"
+)
+
+// FindGroupRenameConflicts находит песни группы oldName, название которых уже занято активной песней группы newName.
+// Строки обеих групп блокируются до конца транзакции.
+func (r *SongRepository) FindGroupRenameConflicts(ctx context.Context, oldName, newName string) ([]model.RenameConflict, error) {
+	log := r.logger.WithFields(ctx, "group", oldName, "newName", newName)
+
+	log.Debug("Поиск конфликтов переименования группы")
+
+	query := `SELECT s.song_name, s.id AS song_id, e.id AS existing_id
+		FROM songs s
+		JOIN songs e ON e.song_name = s.song_name AND e.group_name = $2 AND e.deleted_at IS NULL
+		WHERE s.group_name = $1 AND s.deleted_at IS NULL
+		ORDER BY s.id
+		FOR UPDATE`
+
+	conflicts := []model.RenameConflict{}
+	if err := sqlx.SelectContext(ctx, r.conn(ctx), &conflicts, query, oldName, newName); err != nil {
+		log.Error("Ошибка поиска конфликтов переименования группы", "error", err)
+		return nil, fmt.Errorf("ошибка поиска конфликтов переименования группы: %w", err)
+	}
+
+	log.Info("Конфликты переименования группы найдены", "count", len(conflicts))
+	return conflicts, nil
+}
+
+// RenameGroup переименовывает группу у всех ее активных песен, кроме excludeIDs, одним запросом.
+// Возвращает идентификаторы переименованных песен.
+func (r *SongRepository) RenameGroup(ctx context.Context, oldName, newName string, excludeIDs []int64) ([]int64, error) {
+	log := r.logger.WithFields(ctx, "group", oldName, "newName", newName)
+
+	log.Debug("Переименование группы", "excluded", len(excludeIDs))
+
+	if excludeIDs == nil {
+		excludeIDs = []int64{}
+	}
+
+	// Хэш содержимого пересчитывается с новым названием группы так же, как в model.Song.ComputeContentHash
+	query := `UPDATE songs SET group_name = $1, group_name_norm = $2, updated_at = $3,
+			content_hash = encode(sha256(convert_to($6, 'UTF8') || '\x00'::bytea || convert_to(song_name, 'UTF8')
+				|| '\x00'::bytea || convert_to(release_date, 'UTF8') || '\x00'::bytea || convert_to(text, 'UTF8')
+				|| '\x00'::bytea || convert_to(link, 'UTF8')), 'hex')
+		WHERE group_name = $4 AND deleted_at IS NULL AND NOT (id = ANY($5))
+		RETURNING id`
+
+	ids := []int64{}
+	err := sqlx.SelectContext(ctx, r.conn(ctx), &ids, query,
+		newName, model.NormalizeName(newName), time.Now(), oldName, pq.Array(excludeIDs), newName)
+	if err != nil {
+		log.Error("Ошибка переименования группы", "error", err)
+		return nil, wrapUniqueViolation(fmt.Errorf("ошибка переименования группы: %w", err))
+	}
+
+	log.Info("Группа успешно переименована", "count", len(ids))
+	return ids, nil
+}
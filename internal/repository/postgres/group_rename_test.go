@@ -0,0 +1,90 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"song-library/internal/model"
+	"testing"
+)
+
+func TestFindGroupRenameConflicts(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	for _, s := range [][2]string{{"Muse", "Hysteria"}, {"Muse", "Uprising"}, {"Muse UK", "Uprising"}, {"Muse UK", "Deleted"}, {"Muse", "Deleted"}} {
+		mustCreateSong(t, repo, newTestSong(s[0], s[1]))
+	}
+	if err := repo.DeleteSong(ctx, 4); err != nil {
+		t.Fatalf("DeleteSong() error = %v", err)
+	}
+
+	tests := []struct {
+		name             string
+		oldName, newName string
+		want             []model.RenameConflict
+	}{
+		{"название занято активной песней", "Muse", "Muse UK", []model.RenameConflict{{Song: "Uprising", SongID: 2, ExistingID: 3}}},
+		{"новая группа пуста", "Muse", "Queen", []model.RenameConflict{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var conflicts []model.RenameConflict
+			err := repo.WithinTransaction(ctx, func(ctx context.Context) error {
+				var err error
+				conflicts, err = repo.FindGroupRenameConflicts(ctx, tt.oldName, tt.newName)
+				return err
+			})
+			if err != nil {
+				t.Fatalf("FindGroupRenameConflicts() error = %v", err)
+			}
+			if !reflect.DeepEqual(conflicts, tt.want) {
+				t.Errorf("FindGroupRenameConflicts() = %+v, want %+v", conflicts, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenameGroup(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	for _, s := range [][2]string{{"Muse", "Hysteria"}, {"Muse", "Uprising"}, {"Muse", "Starlight"}, {"Mötley Crüe", "Uprising"}} {
+		mustCreateSong(t, repo, newTestSong(s[0], s[1]))
+	}
+
+	// Конфликт с песней новой группы нарушает уникальность
+	if _, err := repo.RenameGroup(ctx, "Muse", "Mötley Crüe", nil); !errors.Is(err, model.ErrSongAlreadyExists) {
+		t.Fatalf("RenameGroup() с конфликтом error = %v, want %v", err, model.ErrSongAlreadyExists)
+	}
+
+	ids, err := repo.RenameGroup(ctx, "Muse", "Mötley Crüe", []int64{2})
+	if err != nil {
+		t.Fatalf("RenameGroup() error = %v", err)
+	}
+	if !reflect.DeepEqual(slices.Sorted(slices.Values(ids)), []int64{1, 3}) {
+		t.Errorf("RenameGroup() = %v, want [1 3]", ids)
+	}
+
+	// Нормализованная группа и хэш содержимого пересчитываются так же, как при сохранении песни
+	songs, err := repo.GetSongs(ctx, model.SongFilter{Group: "motley crue", Pagination: model.Pagination{Page: 1, PageSize: 10}})
+	if err != nil {
+		t.Fatalf("GetSongs() error = %v", err)
+	}
+	if got := songIDs(songs); !reflect.DeepEqual(got, []int64{4, 3, 1}) {
+		t.Errorf("GetSongs(motley crue) ids = %v, want [4 3 1]", got)
+	}
+	for _, song := range songs {
+		stored := song.ContentHash
+		song.ComputeContentHash()
+		if stored != song.ContentHash {
+			t.Errorf("песня %d content_hash = %s, want %s", song.ID, stored, song.ContentHash)
+		}
+	}
+
+	if ids, err = repo.RenameGroup(ctx, "Nobody", "Queen", nil); err != nil || len(ids) != 0 {
+		t.Errorf("RenameGroup() отсутствующей группы = %v, %v, want пустой список", ids, err)
+	}
+}
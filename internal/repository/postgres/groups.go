@@ -0,0 +1,231 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"song-library/internal/model"
+	"time"
+)
+
+// groupInfoRow строка таблицы groups
+type groupInfoRow struct {
+	Name        string         `db:"name"`
+	Description string         `db:"description"`
+	Country     string         `db:"country"`
+	FormedYear  *int           `db:"formed_year"`
+	Links       pq.StringArray `db:"links"`
+	CreatedAt   time.Time      `db:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at"`
+}
+
+// toModel преобразует строку таблицы в сведения о группе
+func (row groupInfoRow) toModel() *model.GroupInfo {
+	links := []string(row.Links)
+	if links == nil {
+		links = []string{}
+	}
+	return &model.GroupInfo{
+		Name:        row.Name,
+		Description: row.Description,
+		Country:     row.Country,
+		FormedYear:  row.FormedYear,
+		Links:       links,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+}
+
+// groupInfoColumns колонки таблицы groups
+const groupInfoColumns = `name, description, country, formed_year, links, created_at, updated_at`
+
+// GetGroupInfo получает сведения о группе. Возвращает nil, если сведений нет.
+func (r *SongRepository) GetGroupInfo(ctx context.Context, name string) (*model.GroupInfo, error) {
+	log := r.logger.WithFields(ctx, "group", name)
+
+	log.Debug("Получение сведений о группе")
+
+	query := `SELECT ` + groupInfoColumns + ` FROM groups WHERE name = $1`
+
+	var row groupInfoRow
+	if err := sqlx.GetContext(ctx, r.conn(ctx), &row, query, name); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Info("Сведения о группе не найдены")
+			return nil, nil
+		}
+		log.Error("Ошибка получения сведений о группе", "error", err)
+		return nil, fmt.Errorf("ошибка получения сведений о группе: %w", err)
+	}
+
+	log.Info("Сведения о группе успешно получены")
+	return row.toModel(), nil
+}
+
+// UpsertGroupInfo создает или заменяет сведения о группе info.Name.
+// Возвращает сохраненные сведения и true, если они были созданы.
+func (r *SongRepository) UpsertGroupInfo(ctx context.Context, info *model.GroupInfo) (*model.GroupInfo, bool, error) {
+	log := r.logger.WithFields(ctx, "group", info.Name)
+
+	log.Debug("Сохранение сведений о группе")
+
+	query := `INSERT INTO groups (name, description, country, formed_year, links)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (name) DO UPDATE SET
+			description = EXCLUDED.description,
+			country = EXCLUDED.country,
+			formed_year = EXCLUDED.formed_year,
+			links = EXCLUDED.links,
+			updated_at = now()
+		RETURNING ` + groupInfoColumns + `, (xmax = 0) AS created`
+
+	var row struct {
+		groupInfoRow
+		Created bool `db:"created"`
+	}
+	err := sqlx.GetContext(ctx, r.conn(ctx), &row, query,
+		info.Name, info.Description, info.Country, info.FormedYear, pq.StringArray(info.Links))
+	if err != nil {
+		log.Error("Ошибка сохранения сведений о группе", "error", err)
+		return nil, false, fmt.Errorf("ошибка сохранения сведений о группе: %w", err)
+	}
+
+	log.Info("Сведения о группе успешно сохранены", "created", row.Created)
+	return row.groupInfoRow.toModel(), row.Created, nil
+}
+
+// RenameGroupInfo переносит сведения о группе oldName на newName.
+// Если у newName уже есть сведения, они сохраняются, а сведения oldName остаются без изменений.
+func (r *SongRepository) RenameGroupInfo(ctx context.Context, oldName, newName string) error {
+	log := r.logger.WithFields(ctx, "group", oldName, "newName", newName)
+
+	log.Debug("Перенос сведений о группе")
+
+	query := `UPDATE groups SET name = $2, updated_at = now()
+		WHERE name = $1 AND NOT EXISTS (SELECT 1 FROM groups WHERE name = $2)`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, oldName, newName)
+	if err != nil {
+		log.Error("Ошибка переноса сведений о группе", "error", err)
+		return fmt.Errorf("ошибка переноса сведений о группе: %w", err)
+	}
+
+	moved, err := result.RowsAffected()
+	if err != nil {
+		log.Error("Ошибка получения количества затронутых строк", "error", err)
+		return fmt.Errorf("ошибка получения количества затронутых строк: %w", err)
+	}
+
+	log.Info("Перенос сведений о группе завершен", "moved", moved > 0)
+	return nil
+}
+
+// groupSongsOrder выражения сортировки песен группы по допустимым значениям sortBy.
+// Дата выхода хранится строкой ДД.ММ.ГГГГ, поэтому сортируется после преобразования в дату
+var groupSongsOrder = sortOrders{
+	model.GroupSongsSortName: `song_name_norm`,
+	model.GroupSongsSortReleaseDate: `CASE WHEN release_date ~ '^\d{2}\.\d{2}\.\d{4}$' THEN to_date(release_date, 'DD.MM.YYYY') END NULLS LAST,
+		song_name_norm`,
+}
+
+// GetGroupSongs получает страницу активных песен группы без текста в порядке sortBy и общее количество песен группы
+func (r *SongRepository) GetGroupSongs(ctx context.Context, group, sortBy string, page model.Pagination) ([]*model.Song, int64, error) {
+	log := r.logger.WithFields(ctx, "group", group)
+
+	log.Debug("Получение песен группы", "sort_by", sortBy, "page", page.Page, "pageSize", page.PageSize)
+
+	orderBy, err := groupSongsOrder.orderBy(sortBy, "")
+	if err != nil {
+		log.Error("Неизвестный порядок песен группы", "sort_by", sortBy)
+		return nil, 0, fmt.Errorf("ошибка получения песен группы: %w", err)
+	}
+
+	var total int64
+	err = r.readConn(ctx).QueryRowxContext(ctx,
+		`SELECT count(*) FROM songs WHERE group_name = $1 AND deleted_at IS NULL`, group).Scan(&total)
+	if err != nil {
+		log.Error("Ошибка подсчета песен группы", "error", err)
+		return nil, 0, fmt.Errorf("ошибка подсчета песен группы: %w", err)
+	}
+
+	query := `SELECT ` + songColumnsWithoutText + ` FROM songs WHERE group_name = $1 AND deleted_at IS NULL
+		` + orderBy + ` LIMIT $2 OFFSET $3`
+
+	songs := []*model.Song{}
+	if err = sqlx.SelectContext(ctx, r.readConn(ctx), &songs, query, group, page.PageSize, page.Offset()); err != nil {
+		log.Error("Ошибка получения песен группы", "error", err)
+		return nil, 0, fmt.Errorf("ошибка получения песен группы: %w", err)
+	}
+
+	log.Info("Песни группы успешно получены", "count", len(songs), "total", total)
+	return songs, total, nil
+}
+
+// topSongsOrder выражения сортировки рейтинга песен группы по допустимым метрикам
+var topSongsOrder = sortOrders{
+	model.TopSongsMetricViews:     `COALESCE(v.view_count, 0) DESC`,
+	model.TopSongsMetricUpdatedAt: `s.updated_at DESC`,
+	model.TopSongsMetricCreatedAt: `s.created_at DESC`,
+}
+
+// groupViewsJoin присоединяет к песням группы $1 количество просмотров из журнала обращений как v.view_count
+const groupViewsJoin = `LEFT JOIN (
+		SELECT song_id, count(*) AS view_count
+		FROM song_access_log
+		WHERE action = '` + model.AccessActionView + `'
+			AND song_id IN (SELECT id FROM songs WHERE group_name = $1)
+		GROUP BY song_id
+	) v ON v.song_id = s.id`
+
+// GetTopSongsByGroup получает до limit активных песен группы с наибольшим значением метрики metric
+func (r *SongRepository) GetTopSongsByGroup(ctx context.Context, group, metric string, limit int) ([]*model.Song, error) {
+	log := r.logger.WithFields(ctx, "group", group)
+
+	log.Debug("Получение рейтинга песен группы", "metric", metric, "limit", limit)
+
+	orderBy, err := topSongsOrder.orderBy(metric, "DESC")
+	if err != nil {
+		log.Error("Неизвестная метрика рейтинга песен группы", "metric", metric)
+		return nil, fmt.Errorf("ошибка получения рейтинга песен группы: %w", err)
+	}
+
+	query := `SELECT ` + songColumnsPrefixed + `
+		FROM songs s
+		` + groupViewsJoin + `
+		WHERE s.group_name = $1 AND s.deleted_at IS NULL
+		` + orderBy + ` LIMIT $2`
+
+	songs := []*model.Song{}
+	if err = sqlx.SelectContext(ctx, r.readConn(ctx), &songs, query, group, limit); err != nil {
+		log.Error("Ошибка получения рейтинга песен группы", "error", err)
+		return nil, fmt.Errorf("ошибка получения рейтинга песен группы: %w", err)
+	}
+
+	log.Info("Рейтинг песен группы успешно получен", "count", len(songs))
+	return songs, nil
+}
+
+// GetGroupStats получает количество активных песен группы, сумму их просмотров и среднее количество куплетов
+func (r *SongRepository) GetGroupStats(ctx context.Context, group string) (*model.GroupStats, error) {
+	log := r.logger.WithFields(ctx, "group", group)
+
+	log.Debug("Получение статистики группы")
+
+	query := `SELECT count(*) AS song_count,
+			COALESCE(sum(v.view_count), 0) AS total_views,
+			COALESCE(avg(CASE WHEN s.text = '' THEN 0 ELSE array_length(string_to_array(s.text, E'\n\n'), 1) END), 0) AS avg_verse_count
+		FROM songs s
+		` + groupViewsJoin + `
+		WHERE s.group_name = $1 AND s.deleted_at IS NULL`
+
+	var stats model.GroupStats
+	if err := sqlx.GetContext(ctx, r.readConn(ctx), &stats, query, group); err != nil {
+		log.Error("Ошибка получения статистики группы", "error", err)
+		return nil, fmt.Errorf("ошибка получения статистики группы: %w", err)
+	}
+
+	log.Info("Статистика группы успешно получена", "song_count", stats.SongCount)
+	return &stats, nil
+}
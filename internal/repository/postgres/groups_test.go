@@ -0,0 +1,240 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"reflect"
+	"song-library/internal/model"
+	"testing"
+)
+
+func TestGroupInfo(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+
+	info, err := repo.GetGroupInfo(ctx, "Muse")
+	if err != nil || info != nil {
+		t.Fatalf("GetGroupInfo() отсутствующей группы = %v, %v, want nil, nil", info, err)
+	}
+
+	formed := 1994
+	input := &model.GroupInfo{Name: "Muse", Description: "Английская рок-группа", Country: "UK", FormedYear: &formed, Links: []string{"https://muse.mu"}}
+	saved, created, err := repo.UpsertGroupInfo(ctx, input)
+	if err != nil {
+		t.Fatalf("UpsertGroupInfo() error = %v", err)
+	}
+	if !created {
+		t.Error("UpsertGroupInfo() created = false, want true")
+	}
+
+	input.Description = "Английская альтернативная рок-группа"
+	input.FormedYear = nil
+	input.Links = []string{}
+	updated, created, err := repo.UpsertGroupInfo(ctx, input)
+	if err != nil {
+		t.Fatalf("UpsertGroupInfo() повторно error = %v", err)
+	}
+	if created {
+		t.Error("UpsertGroupInfo() повторно created = true, want false")
+	}
+	if !updated.CreatedAt.Equal(saved.CreatedAt) || updated.UpdatedAt.Before(saved.UpdatedAt) {
+		t.Errorf("UpsertGroupInfo() CreatedAt = %v, UpdatedAt = %v после %v, %v",
+			updated.CreatedAt, updated.UpdatedAt, saved.CreatedAt, saved.UpdatedAt)
+	}
+
+	got, err := repo.GetGroupInfo(ctx, "Muse")
+	if err != nil || got == nil {
+		t.Fatalf("GetGroupInfo() = %v, %v", got, err)
+	}
+	if got.Description != input.Description || got.Country != "UK" || got.FormedYear != nil || !reflect.DeepEqual(got.Links, []string{}) {
+		t.Errorf("GetGroupInfo() = %+v, want %+v", got, input)
+	}
+}
+
+func TestRenameGroupInfo(t *testing.T) {
+	tests := []struct {
+		name        string
+		existing    []string
+		wantGroups  []string
+		wantCountry map[string]string
+	}{
+		{"новое название свободно", []string{"Muse"}, []string{"Muse UK"}, map[string]string{"Muse UK": "Muse"}},
+		{"сведения нового названия сохраняются", []string{"Muse", "Muse UK"}, []string{"Muse", "Muse UK"}, map[string]string{"Muse": "Muse", "Muse UK": "Muse UK"}},
+		{"сведений нет", nil, []string{}, map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newTestRepository(t, RepositoryConfig{})
+			// Страна группы совпадает с ее исходным названием, чтобы отличать перенесенные сведения
+			for _, name := range tt.existing {
+				if _, _, err := repo.UpsertGroupInfo(ctx, &model.GroupInfo{Name: name, Country: name, Links: []string{}}); err != nil {
+					t.Fatalf("UpsertGroupInfo() error = %v", err)
+				}
+			}
+
+			if err := repo.RenameGroupInfo(ctx, "Muse", "Muse UK"); err != nil {
+				t.Fatalf("RenameGroupInfo() error = %v", err)
+			}
+
+			groups := []string{}
+			if err := testDB.Select(&groups, `SELECT name FROM groups ORDER BY name`); err != nil {
+				t.Fatalf("ошибка чтения групп: %v", err)
+			}
+			if !reflect.DeepEqual(groups, tt.wantGroups) {
+				t.Errorf("группы после RenameGroupInfo() = %v, want %v", groups, tt.wantGroups)
+			}
+			for name, country := range tt.wantCountry {
+				info, err := repo.GetGroupInfo(ctx, name)
+				if err != nil || info == nil || info.Country != country {
+					t.Errorf("GetGroupInfo(%s) = %+v, %v, want страну %s", name, info, err, country)
+				}
+			}
+		})
+	}
+}
+
+func TestGetGroupSongs(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	for _, s := range []struct{ name, releaseDate string }{
+		{"Uprising", "07.09.2009"},
+		{"Hysteria", "01.12.2003"},
+		{"Ásylum", "неизвестно"},
+		{"Starlight", "05.09.2006"},
+	} {
+		song := newTestSong("Muse", s.name)
+		song.ReleaseDate = s.releaseDate
+		mustCreateSong(t, repo, song)
+	}
+	mustCreateSong(t, repo, newTestSong("Queen", "Innuendo"))
+	deleted := mustCreateSong(t, repo, newTestSong("Muse", "Deleted"))
+	if err := repo.DeleteSong(ctx, deleted.ID); err != nil {
+		t.Fatalf("DeleteSong() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		group     string
+		sortBy    string
+		page      model.Pagination
+		wantSongs []string
+		wantTotal int64
+		wantErr   bool
+	}{
+		{"по названию без учета диакритики", "Muse", model.GroupSongsSortName, model.Pagination{Page: 1, PageSize: 10},
+			[]string{"Ásylum", "Hysteria", "Starlight", "Uprising"}, 4, false},
+		{"по дате выхода, неизвестная дата последней", "Muse", model.GroupSongsSortReleaseDate, model.Pagination{Page: 1, PageSize: 10},
+			[]string{"Hysteria", "Starlight", "Uprising", "Ásylum"}, 4, false},
+		{"вторая страница", "Muse", model.GroupSongsSortName, model.Pagination{Page: 2, PageSize: 3},
+			[]string{"Uprising"}, 4, false},
+		{"страница за пределами списка", "Muse", model.GroupSongsSortName, model.Pagination{Page: 5, PageSize: 3},
+			[]string{}, 4, false},
+		{"точное название группы", "muse", model.GroupSongsSortName, model.Pagination{Page: 1, PageSize: 10},
+			[]string{}, 0, false},
+		{"неизвестный порядок", "Muse", "views", model.Pagination{Page: 1, PageSize: 10}, nil, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			songs, total, err := repo.GetGroupSongs(ctx, tt.group, tt.sortBy, tt.page)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetGroupSongs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got := []string{}
+			for _, song := range songs {
+				got = append(got, song.Song)
+				if song.Text != "" {
+					t.Errorf("GetGroupSongs() вернул текст песни %s", song.Song)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.wantSongs) || total != tt.wantTotal {
+				t.Errorf("GetGroupSongs() = %v, %d, want %v, %d", got, total, tt.wantSongs, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestGetTopSongsByGroup(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	for _, name := range []string{"Hysteria", "Uprising", "Starlight"} {
+		mustCreateSong(t, repo, newTestSong("Muse", name))
+	}
+	mustCreateSong(t, repo, newTestSong("Queen", "Innuendo"))
+	// Starlight просматривали чаще всех, Hysteria один раз; получение куплетов не считается просмотром
+	for _, id := range []int64{3, 3, 1, 4, 4, 4} {
+		repo.RecordAccess(ctx, id, model.AccessActionView)
+	}
+	repo.RecordAccess(ctx, 2, model.AccessActionVerses)
+	mustExec(t, `UPDATE songs SET created_at = '2024-01-01'::timestamp + id * interval '1 day', updated_at = '2024-02-01'`)
+	mustExec(t, `UPDATE songs SET updated_at = '2030-01-01' WHERE id = 2`)
+
+	tests := []struct {
+		name    string
+		metric  string
+		limit   int
+		wantIDs []int64
+		wantErr bool
+	}{
+		{"по просмотрам", model.TopSongsMetricViews, 10, []int64{3, 1, 2}, false},
+		{"по просмотрам с ограничением", model.TopSongsMetricViews, 1, []int64{3}, false},
+		{"по времени изменения", model.TopSongsMetricUpdatedAt, 1, []int64{2}, false},
+		{"по времени добавления", model.TopSongsMetricCreatedAt, 10, []int64{3, 2, 1}, false},
+		{"неизвестная метрика", "likes", 10, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			songs, err := repo.GetTopSongsByGroup(ctx, "Muse", tt.metric, tt.limit)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetTopSongsByGroup() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := songIDs(songs); !reflect.DeepEqual(got, tt.wantIDs) {
+				t.Errorf("GetTopSongsByGroup() ids = %v, want %v", got, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestGetGroupStats(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	hysteria := mustCreateSong(t, repo, newTestSong("Muse", "Hysteria"))
+	instrumental := newTestSong("Muse", "Instrumental")
+	instrumental.Text = ""
+	mustCreateSong(t, repo, instrumental)
+	mustCreateSong(t, repo, newTestSong("Queen", "Innuendo"))
+	for range 3 {
+		repo.RecordAccess(ctx, hysteria.ID, model.AccessActionView)
+	}
+
+	tests := []struct {
+		name  string
+		group string
+		want  model.GroupStats
+	}{
+		{"группа с песнями", "Muse", model.GroupStats{SongCount: 2, TotalViews: 3, AvgVerseCount: 1}},
+		{"группа без песен", "Nobody", model.GroupStats{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := repo.GetGroupStats(ctx, tt.group)
+			if err != nil {
+				t.Fatalf("GetGroupStats() error = %v", err)
+			}
+			if *stats != tt.want {
+				t.Errorf("GetGroupStats() = %+v, want %+v", *stats, tt.want)
+			}
+		})
+	}
+}
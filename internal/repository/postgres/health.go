@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"song-library/pkg/events"
+	"song-library/pkg/logger"
+	"time"
+)
+
+const (
+	// EventDBUnhealthy публикуется, когда база данных не отвечает несколько проверок подряд
+	EventDBUnhealthy = "db.unhealthy"
+	// EventDBHealthy публикуется, когда база данных снова отвечает после сбоя
+	EventDBHealthy = "db.healthy"
+)
+
+var (
+	dbPingFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "db_ping_failures_total",
+		Help: "Количество неудачных проверок соединения с базой данных",
+	})
+	dbHealthy = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "db_healthy",
+		Help: "Текущее состояние базы данных (1 — доступна, 0 — недоступна)",
+	})
+)
+
+// HealthMonitor периодически проверяет доступность базы данных
+type HealthMonitor struct {
+	db               *sqlx.DB
+	interval         time.Duration
+	failureThreshold int
+	bus              *events.Bus
+	logger           *logger.Logger
+}
+
+// NewHealthMonitor создает новый монитор состояния базы данных
+func NewHealthMonitor(db *sqlx.DB, interval time.Duration, failureThreshold int, bus *events.Bus, logger *logger.Logger) *HealthMonitor {
+	return &HealthMonitor{
+		db:               db,
+		interval:         interval,
+		failureThreshold: failureThreshold,
+		bus:              bus,
+		logger:           logger,
+	}
+}
+
+// Monitor запускает периодическую проверку базы данных и блокируется до отмены ctx
+func (m *HealthMonitor) Monitor(ctx context.Context) {
+	m.logger.Info("Запуск мониторинга базы данных", "interval", m.interval.String())
+	dbHealthy.Set(1)
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	failures := 0
+	unhealthy := false
+	for {
+		select {
+		case <-ctx.Done():
+			m.logger.Info("Мониторинг базы данных остановлен")
+			return
+		case <-ticker.C:
+		}
+
+		pingCtx, cancel := context.WithTimeout(ctx, m.interval)
+		err := m.db.PingContext(pingCtx)
+		cancel()
+
+		if err == nil {
+			m.logger.Debug("Проверка соединения с базой данных выполнена успешно")
+			failures = 0
+			dbHealthy.Set(1)
+			if unhealthy {
+				unhealthy = false
+				m.logger.Info("Соединение с базой данных восстановлено")
+				m.bus.Publish(ctx, EventDBHealthy, nil)
+			}
+			continue
+		}
+
+		if ctx.Err() != nil {
+			continue
+		}
+
+		failures++
+		dbPingFailures.Inc()
+		m.logger.Error("Ошибка проверки соединения с базой данных", "error", err, "consecutive_failures", failures)
+
+		if failures >= m.failureThreshold && !unhealthy {
+			unhealthy = true
+			dbHealthy.Set(0)
+			m.logger.Error("База данных недоступна", "consecutive_failures", failures)
+			m.bus.Publish(ctx, EventDBUnhealthy, err)
+		}
+	}
+}
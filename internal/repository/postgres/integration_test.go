@@ -0,0 +1,174 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"song-library/internal/migration"
+	"song-library/internal/model"
+	"song-library/pkg/logger"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Интеграционные тесты репозитория выполняются против настоящего PostgreSQL:
+//
+//	go test -tags=integration ./internal/repository/postgres/
+//
+// Если задан TEST_DATABASE_URL, тесты используют эту базу, иначе запускают контейнер PostgreSQL через Docker.
+// Миграции применяются в отдельной временной схеме, которая удаляется после тестов, поэтому данные базы не затрагиваются.
+
+// testPostgresImage образ PostgreSQL для контейнера; contrib-расширения unaccent и pg_trgm входят в образ
+const testPostgresImage = "postgres:16-alpine"
+
+// testDB пул соединений с временной схемой тестов
+var testDB *sqlx.DB
+
+// testLogger логгер тестов без вывода
+var testLogger = &logger.Logger{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+func TestMain(m *testing.M) {
+	os.Exit(runIntegrationTests(m))
+}
+
+// runIntegrationTests подготавливает базу, выполняет тесты и удаляет временную схему и контейнер
+func runIntegrationTests(m *testing.M) int {
+	ctx := context.Background()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		container, err := tcpostgres.Run(ctx, testPostgresImage,
+			tcpostgres.WithDatabase("song_library"),
+			tcpostgres.WithUsername("song_library"),
+			tcpostgres.WithPassword("song_library"),
+			tcpostgres.BasicWaitStrategies(),
+		)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ошибка запуска контейнера PostgreSQL: %v\n", err)
+			return 1
+		}
+		defer func() {
+			if err := testcontainers.TerminateContainer(container); err != nil {
+				fmt.Fprintf(os.Stderr, "ошибка остановки контейнера PostgreSQL: %v\n", err)
+			}
+		}()
+
+		if dsn, err = container.ConnectionString(ctx, "sslmode=disable"); err != nil {
+			fmt.Fprintf(os.Stderr, "ошибка получения адреса PostgreSQL: %v\n", err)
+			return 1
+		}
+	}
+
+	admin, err := sqlx.Open("postgres", dsn)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ошибка подключения к PostgreSQL: %v\n", err)
+		return 1
+	}
+	defer admin.Close()
+
+	schema := fmt.Sprintf("song_library_test_%d", time.Now().UnixNano())
+	if _, err = admin.ExecContext(ctx, `CREATE SCHEMA `+schema); err != nil {
+		fmt.Fprintf(os.Stderr, "ошибка создания схемы %s: %v\n", schema, err)
+		return 1
+	}
+	defer func() {
+		if _, err := admin.ExecContext(ctx, `DROP SCHEMA `+schema+` CASCADE`); err != nil {
+			fmt.Fprintf(os.Stderr, "ошибка удаления схемы %s: %v\n", schema, err)
+		}
+	}()
+
+	// Расширения создаются в первой схеме search_path и удаляются вместе с ней;
+	// уже установленные в public расширения остаются доступны через public
+	testDB, err = sqlx.Open("postgres", withSearchPath(dsn, schema+",public"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ошибка подключения к PostgreSQL: %v\n", err)
+		return 1
+	}
+	defer testDB.Close()
+
+	if err = migration.RunMigrations(testDB.DB, testLogger); err != nil {
+		fmt.Fprintf(os.Stderr, "ошибка миграций: %v\n", err)
+		return 1
+	}
+
+	return m.Run()
+}
+
+// withSearchPath добавляет к строке подключения параметр search_path; lib/pq передает его серверу при подключении.
+// Поддерживаются строки вида postgres://... и key=value
+func withSearchPath(dsn, searchPath string) string {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err == nil {
+			q := u.Query()
+			q.Set("search_path", searchPath)
+			u.RawQuery = q.Encode()
+			return u.String()
+		}
+	}
+	return dsn + " search_path=" + searchPath
+}
+
+// newTestRepository очищает таблицы временной схемы и создает репозиторий поверх testDB.
+// Реплика указывает на ту же базу, чтобы запросы чтения проходили через readConn
+func newTestRepository(t testing.TB, cfg RepositoryConfig) *SongRepository {
+	t.Helper()
+
+	_, err := testDB.Exec(`TRUNCATE songs, song_access_log, song_events, api_audit, bookmarks, groups RESTART IDENTITY CASCADE`)
+	if err != nil {
+		t.Fatalf("ошибка очистки таблиц: %v", err)
+	}
+	return NewSongRepository(testDB, testDB, cfg, testLogger)
+}
+
+// newTestSong возвращает песню группы group с названием name и текстом из двух куплетов
+func newTestSong(group, name string) *model.Song {
+	duration := 200
+	return &model.Song{
+		Group:       group,
+		Song:        name,
+		ReleaseDate: "16.07.2006",
+		Text:        "Ooh baby, don't you know I suffer?\n\nOoh\nYou set my soul alight",
+		Link:        "https://example.com/" + strings.ReplaceAll(strings.ToLower(name), " ", "-"),
+		Duration:    &duration,
+	}
+}
+
+// mustCreateSong сохраняет песню и возвращает ее с присвоенными идентификаторами
+func mustCreateSong(t testing.TB, repo *SongRepository, song *model.Song) *model.Song {
+	t.Helper()
+
+	id, err := repo.CreateSong(context.Background(), song)
+	if err != nil {
+		t.Fatalf("CreateSong(%s, %s) error = %v", song.Group, song.Song, err)
+	}
+	song.ID = id
+	return song
+}
+
+// mustExec выполняет служебный запрос подготовки данных
+func mustExec(t testing.TB, query string, args ...interface{}) {
+	t.Helper()
+
+	if _, err := testDB.Exec(query, args...); err != nil {
+		t.Fatalf("ошибка запроса %q: %v", query, err)
+	}
+}
+
+// songIDs возвращает идентификаторы песен в порядке списка
+func songIDs(songs []*model.Song) []int64 {
+	ids := make([]int64, 0, len(songs))
+	for _, song := range songs {
+		ids = append(ids, song.ID)
+	}
+	return ids
+}
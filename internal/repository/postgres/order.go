@@ -0,0 +1,27 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sortOrders допустимые порядки сортировки списка: значение параметра сортировки → выражения ORDER BY.
+// Выражения не включают id: его добавляет orderBy.
+type sortOrders map[string]string
+
+// orderBy возвращает предложение ORDER BY для порядка key, завершенное id в направлении idDirection
+// ("" или "DESC"). Уникальный id разрешает равенство значений сортировки, поэтому страницы списка
+// не пересекаются и не теряют строк. Порядок не из списка допустимых возвращает ошибку.
+func (o sortOrders) orderBy(key, idDirection string) (string, error) {
+	expr, ok := o[key]
+	if !ok {
+		return "", fmt.Errorf("неизвестный порядок сортировки: %q", key)
+	}
+
+	keys := []string{}
+	if expr != "" {
+		keys = append(keys, expr)
+	}
+	keys = append(keys, strings.TrimSpace("id "+idDirection))
+	return " ORDER BY " + strings.Join(keys, ", "), nil
+}
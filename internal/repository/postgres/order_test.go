@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSortOrdersOrderBy(t *testing.T) {
+	orders := sortOrders{
+		"newest":    "",
+		"relevance": "relevance DESC",
+	}
+
+	tests := []struct {
+		name        string
+		key         string
+		idDirection string
+		want        string
+		wantErr     bool
+	}{
+		{"только id", "newest", "DESC", " ORDER BY id DESC", false},
+		{"выражение и id по возрастанию", "relevance", "", " ORDER BY relevance DESC, id", false},
+		{"выражение и id по убыванию", "relevance", "DESC", " ORDER BY relevance DESC, id DESC", false},
+		{"порядок не из списка", "name; DROP TABLE songs", "", "", true},
+		{"пустой порядок не из списка", "", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := orders.orderBy(tt.key, tt.idDirection)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("orderBy(%q) error = %v, wantErr %v", tt.key, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("orderBy(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListOrdersEndWithID(t *testing.T) {
+	lists := []struct {
+		name        string
+		orders      sortOrders
+		idDirection string
+	}{
+		{"список песен", songsOrder, "DESC"},
+		{"песни группы", groupSongsOrder, ""},
+		{"рейтинг песен группы", topSongsOrder, "DESC"},
+	}
+
+	for _, list := range lists {
+		for key := range list.orders {
+			t.Run(list.name+"/"+key, func(t *testing.T) {
+				got, err := list.orders.orderBy(key, list.idDirection)
+				if err != nil {
+					t.Fatalf("orderBy(%q) error = %v", key, err)
+				}
+				if want := strings.TrimSpace("id " + list.idDirection); !strings.HasSuffix(got, " "+want) {
+					t.Errorf("orderBy(%q) = %q, want завершение %q", key, got, want)
+				}
+			})
+		}
+	}
+}
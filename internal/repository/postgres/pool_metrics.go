@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// poolStatsCollector экспортирует статистику пула соединений db.Stats(), считываемую при каждом сборе метрик
+type poolStatsCollector struct {
+	db      *sqlx.DB
+	maxIdle int
+
+	open          *prometheus.Desc
+	inUse         *prometheus.Desc
+	idle          *prometheus.Desc
+	waitCount     *prometheus.Desc
+	waitDuration  *prometheus.Desc
+	maxIdleClosed *prometheus.Desc
+	maxConns      *prometheus.Desc
+}
+
+// RegisterPoolMetrics регистрирует метрики пула соединений db с меткой pool, например primary или replica.
+// maxIdle — настроенный предел простаивающих соединений, который не доступен через db.Stats().
+func RegisterPoolMetrics(db *sqlx.DB, pool string, maxIdle int) error {
+	labels := prometheus.Labels{"pool": pool}
+	return prometheus.Register(&poolStatsCollector{
+		db:      db,
+		maxIdle: maxIdle,
+
+		open: prometheus.NewDesc("db_pool_open_connections",
+			"Количество открытых соединений с базой данных", nil, labels),
+		inUse: prometheus.NewDesc("db_pool_in_use_connections",
+			"Количество соединений, занятых запросами", nil, labels),
+		idle: prometheus.NewDesc("db_pool_idle_connections",
+			"Количество простаивающих соединений", nil, labels),
+		waitCount: prometheus.NewDesc("db_pool_wait_count_total",
+			"Количество ожиданий свободного соединения", nil, labels),
+		waitDuration: prometheus.NewDesc("db_pool_wait_duration_seconds_total",
+			"Суммарное время ожидания свободного соединения", nil, labels),
+		maxIdleClosed: prometheus.NewDesc("db_pool_max_idle_closed_total",
+			"Количество соединений, закрытых из-за предела простаивающих соединений", nil, labels),
+		maxConns: prometheus.NewDesc("db_pool_max_connections",
+			"Настроенный предел соединений (0 — без ограничения)", []string{"limit"}, labels),
+	})
+}
+
+// Describe передает описания метрик пула
+func (c *poolStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.open
+	ch <- c.inUse
+	ch <- c.idle
+	ch <- c.waitCount
+	ch <- c.waitDuration
+	ch <- c.maxIdleClosed
+	ch <- c.maxConns
+}
+
+// Collect считывает текущую статистику пула
+func (c *poolStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.db.Stats()
+
+	ch <- prometheus.MustNewConstMetric(c.open, prometheus.GaugeValue, float64(stats.OpenConnections))
+	ch <- prometheus.MustNewConstMetric(c.inUse, prometheus.GaugeValue, float64(stats.InUse))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(stats.Idle))
+	ch <- prometheus.MustNewConstMetric(c.waitCount, prometheus.CounterValue, float64(stats.WaitCount))
+	ch <- prometheus.MustNewConstMetric(c.waitDuration, prometheus.CounterValue, stats.WaitDuration.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.maxIdleClosed, prometheus.CounterValue, float64(stats.MaxIdleClosed))
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(stats.MaxOpenConnections), "open")
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(c.maxIdle), "idle")
+}
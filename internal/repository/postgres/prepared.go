@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+)
+
+// Часто выполняемые запросы, которые Prepare подготавливает заранее
+const (
+	getSongByIDQuery = `SELECT ` + songColumns + ` FROM songs WHERE id = $1 AND deleted_at IS NULL`
+
+	createSongQuery = `INSERT INTO songs (group_name, song_name, release_date, text, link, created_at, updated_at, duration_seconds,
+			group_name_norm, song_name_norm, source, bpm, copyright, content_hash, featured_artists, enrichment_status, enriched_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		RETURNING id, public_id`
+
+	updateSongQuery = `UPDATE songs SET group_name = $1, song_name = $2, release_date = $3, text = $4, link = $5, updated_at = $6, duration_seconds = $7,
+		group_name_norm = $8, song_name_norm = $9, source = $10, bpm = $11, content_hash = $12 WHERE id = $13 AND deleted_at IS NULL`
+
+	deleteSongQuery = `UPDATE songs SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+)
+
+// preparedQueries запросы, подготавливаемые Prepare
+var preparedQueries = []string{getSongByIDQuery, createSongQuery, updateSongQuery, deleteSongQuery}
+
+// Prepare подготавливает часто выполняемые запросы. Вызывается один раз до начала обслуживания запросов.
+// При ошибке подготовленные выражения закрываются, и репозиторий продолжает выполнять запросы без подготовки.
+// С QueryComments текст запросов меняется для каждого запроса API, поэтому подготовка не выполняется.
+func (r *SongRepository) Prepare(ctx context.Context) error {
+	log := r.logger.WithContext(ctx)
+
+	if r.cfg.QueryComments {
+		log.Info("Подготовка запросов пропущена: включены комментарии к запросам")
+		return nil
+	}
+
+	stmts := make(map[string]*sqlx.Stmt, len(preparedQueries))
+	for _, query := range preparedQueries {
+		stmt, err := r.db.PreparexContext(ctx, query)
+		if err != nil {
+			closeStmts(stmts)
+			return fmt.Errorf("ошибка подготовки запроса: %w", err)
+		}
+		stmts[query] = stmt
+	}
+	r.stmts = stmts
+
+	log.Info("Запросы репозитория подготовлены", "count", len(stmts))
+	return nil
+}
+
+// Close закрывает подготовленные выражения репозитория. Вызывается после остановки обслуживания запросов.
+func (r *SongRepository) Close() error {
+	return closeStmts(r.stmts)
+}
+
+// closeStmts закрывает выражения и возвращает все ошибки закрытия
+func closeStmts(stmts map[string]*sqlx.Stmt) error {
+	var errs []error
+	for _, stmt := range stmts {
+		if err := stmt.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("ошибка закрытия подготовленных запросов: %w", err)
+	}
+	return nil
+}
+
+// preparedConn выполняет подготовленные запросы через их выражения, а остальные — через исходное соединение.
+// Внутри транзакции выражение привязывается к ней.
+type preparedConn struct {
+	sqlx.ExtContext
+	stmts map[string]*sqlx.Stmt
+}
+
+// stmt возвращает подготовленное выражение для запроса или nil, если запрос не подготовлен
+func (c preparedConn) stmt(ctx context.Context, query string) *sqlx.Stmt {
+	stmt, ok := c.stmts[query]
+	if !ok {
+		return nil
+	}
+	if tx, ok := c.ExtContext.(*sqlx.Tx); ok {
+		return tx.StmtxContext(ctx, stmt)
+	}
+	return stmt
+}
+
+func (c preparedConn) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if stmt := c.stmt(ctx, query); stmt != nil {
+		return stmt.QueryContext(ctx, args...)
+	}
+	return c.ExtContext.QueryContext(ctx, query, args...)
+}
+
+func (c preparedConn) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	if stmt := c.stmt(ctx, query); stmt != nil {
+		return stmt.QueryxContext(ctx, args...)
+	}
+	return c.ExtContext.QueryxContext(ctx, query, args...)
+}
+
+func (c preparedConn) QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row {
+	if stmt := c.stmt(ctx, query); stmt != nil {
+		return stmt.QueryRowxContext(ctx, args...)
+	}
+	return c.ExtContext.QueryRowxContext(ctx, query, args...)
+}
+
+func (c preparedConn) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if stmt := c.stmt(ctx, query); stmt != nil {
+		return stmt.ExecContext(ctx, args...)
+	}
+	return c.ExtContext.ExecContext(ctx, query, args...)
+}
@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"github.com/jmoiron/sqlx"
+)
+
+// commentingConn добавляет к каждому запросу комментарий /* request_id=... */,
+// чтобы запросы в pg_stat_activity и логах PostgreSQL можно было сопоставить с запросами API
+type commentingConn struct {
+	sqlx.ExtContext
+}
+
+func (c commentingConn) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return c.ExtContext.QueryContext(ctx, withRequestComment(ctx, query), args...)
+}
+
+func (c commentingConn) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	return c.ExtContext.QueryxContext(ctx, withRequestComment(ctx, query), args...)
+}
+
+func (c commentingConn) QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row {
+	return c.ExtContext.QueryRowxContext(ctx, withRequestComment(ctx, query), args...)
+}
+
+func (c commentingConn) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return c.ExtContext.ExecContext(ctx, withRequestComment(ctx, query), args...)
+}
+
+// withRequestComment добавляет в начало запроса идентификатор запроса из контекста.
+// Идентификатор приходит из заголовка X-Request-ID, поэтому при недопустимых символах
+// комментарий не добавляется, чтобы исключить выход за пределы комментария
+func withRequestComment(ctx context.Context, query string) string {
+	requestID, ok := ctx.Value("requestID").(string)
+	if !ok || !isSafeRequestID(requestID) {
+		return query
+	}
+	return "/* request_id=" + requestID + " */ " + query
+}
+
+// maxRequestIDLength максимальная длина идентификатора запроса в комментарии
+const maxRequestIDLength = 128
+
+// isSafeRequestID проверяет, что идентификатор состоит только из букв, цифр, '-', '_' и '.'
+func isSafeRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range requestID {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,452 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"io"
+	"net"
+	"song-library/internal/model"
+	"song-library/pkg/logger"
+	"time"
+)
+
+const (
+	// adminShutdownCode код ошибки PostgreSQL при остановке сервера администратором
+	adminShutdownCode = "57P01"
+	// connectionFailureCode код ошибки PostgreSQL при потере соединения
+	connectionFailureCode = "08006"
+)
+
+// RetryableRepository оборачивает SongRepository и повторяет вызовы, завершившиеся ошибкой соединения,
+// например при кратковременном перезапуске PostgreSQL. Прочие ошибки возвращаются сразу.
+// Вызовы внутри транзакции не повторяются по отдельности: повторяется вся транзакция WithinTransaction.
+type RetryableRepository struct {
+	repo       *SongRepository
+	maxRetries int
+	delay      time.Duration
+	logger     *logger.Logger
+}
+
+// NewRetryableRepository создает репозиторий с повтором до maxRetries раз с паузой delay
+func NewRetryableRepository(repo *SongRepository, maxRetries int, delay time.Duration, logger *logger.Logger) *RetryableRepository {
+	return &RetryableRepository{
+		repo:       repo,
+		maxRetries: maxRetries,
+		delay:      delay,
+		logger:     logger,
+	}
+}
+
+// isRetryable сообщает, вызвана ли ошибка потерей соединения с базой данных: кодом PostgreSQL
+// об остановке сервера или обрыве соединения, закрытым драйвером соединением, обрывом потока
+// или сетевой ошибкой
+func isRetryable(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == adminShutdownCode || pqErr.Code == connectionFailureCode
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// withRetry выполняет fn, повторяя ее при ошибках соединения. Внутри транзакции повтор не выполняется,
+// так как транзакция после потери соединения уже прервана.
+func withRetry[T any](ctx context.Context, r *RetryableRepository, operation string, fn func() (T, error)) (T, error) {
+	log := r.logger.WithContext(ctx)
+
+	result, err := fn()
+	if _, inTx := ctx.Value(txKey{}).(*sqlx.Tx); inTx {
+		return result, err
+	}
+
+	attempt := 1
+	for ; err != nil && isRetryable(err) && attempt <= r.maxRetries; attempt++ {
+		log.Warn("Ошибка соединения с базой данных, повтор запроса",
+			"operation", operation, "attempt", attempt, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return result, err
+		case <-time.After(r.delay):
+		}
+
+		result, err = fn()
+	}
+
+	if err != nil && isRetryable(err) {
+		return result, fmt.Errorf("%s: попыток %d: %w", operation, attempt, err)
+	}
+	return result, err
+}
+
+// withRetryErr выполняет fn без результата с повтором при ошибках соединения
+func withRetryErr(ctx context.Context, r *RetryableRepository, operation string, fn func() error) error {
+	_, err := withRetry(ctx, r, operation, func() (struct{}, error) {
+		return struct{}{}, fn()
+	})
+	return err
+}
+
+// CreateSong создает новую песню
+func (r *RetryableRepository) CreateSong(ctx context.Context, song *model.Song) (int64, error) {
+	return withRetry(ctx, r, "создание песни", func() (int64, error) {
+		return r.repo.CreateSong(ctx, song)
+	})
+}
+
+// BulkInsertSongs вставляет набор песен
+func (r *RetryableRepository) BulkInsertSongs(ctx context.Context, songs []*model.Song) (int64, error) {
+	return withRetry(ctx, r, "массовая вставка песен", func() (int64, error) {
+		return r.repo.BulkInsertSongs(ctx, songs)
+	})
+}
+
+// GetSongs получает список песен с фильтрацией и пагинацией
+func (r *RetryableRepository) GetSongs(ctx context.Context, filter model.SongFilter) ([]*model.Song, error) {
+	return withRetry(ctx, r, "получение списка песен", func() ([]*model.Song, error) {
+		return r.repo.GetSongs(ctx, filter)
+	})
+}
+
+// GetSongByID получает песню по идентификатору
+func (r *RetryableRepository) GetSongByID(ctx context.Context, id int64) (*model.Song, error) {
+	return withRetry(ctx, r, "получение песни", func() (*model.Song, error) {
+		return r.repo.GetSongByID(ctx, id)
+	})
+}
+
+// GetSongByIDPrimary получает песню по идентификатору из основной базы
+func (r *RetryableRepository) GetSongByIDPrimary(ctx context.Context, id int64) (*model.Song, error) {
+	return withRetry(ctx, r, "получение песни из основной базы", func() (*model.Song, error) {
+		return r.repo.GetSongByIDPrimary(ctx, id)
+	})
+}
+
+// GetSongsByIDs получает песни по списку идентификаторов
+func (r *RetryableRepository) GetSongsByIDs(ctx context.Context, ids []int64) ([]*model.Song, error) {
+	return withRetry(ctx, r, "получение песен по списку ID", func() ([]*model.Song, error) {
+		return r.repo.GetSongsByIDs(ctx, ids)
+	})
+}
+
+// GetSongSummaries получает песни без текста
+func (r *RetryableRepository) GetSongSummaries(ctx context.Context, limit int) ([]*model.Song, error) {
+	return withRetry(ctx, r, "получение песен без текста", func() ([]*model.Song, error) {
+		return r.repo.GetSongSummaries(ctx, limit)
+	})
+}
+
+// IterateSongs передает fn песни библиотеки по одной.
+// Вызов не повторяется: к моменту ошибки fn мог уже обработать часть песен.
+func (r *RetryableRepository) IterateSongs(ctx context.Context, fn func(song *model.Song) error) error {
+	return r.repo.IterateSongs(ctx, fn)
+}
+
+// IterateGroupSongs передает fn песни группы по одной.
+// Вызов не повторяется по той же причине, что и IterateSongs.
+func (r *RetryableRepository) IterateGroupSongs(ctx context.Context, group string, fn func(song *model.Song) error) error {
+	return r.repo.IterateGroupSongs(ctx, group, fn)
+}
+
+// GetSongByIDForUpdate получает песню с блокировкой строки
+func (r *RetryableRepository) GetSongByIDForUpdate(ctx context.Context, id int64) (*model.Song, error) {
+	return withRetry(ctx, r, "получение песни с блокировкой", func() (*model.Song, error) {
+		return r.repo.GetSongByIDForUpdate(ctx, id)
+	})
+}
+
+// UpdateSong обновляет данные песни
+func (r *RetryableRepository) UpdateSong(ctx context.Context, song *model.Song) error {
+	return withRetryErr(ctx, r, "обновление песни", func() error {
+		return r.repo.UpdateSong(ctx, song)
+	})
+}
+
+// UpdateSongDuration обновляет длительность песни
+func (r *RetryableRepository) UpdateSongDuration(ctx context.Context, id int64, duration *int) error {
+	return withRetryErr(ctx, r, "обновление длительности песни", func() error {
+		return r.repo.UpdateSongDuration(ctx, id, duration)
+	})
+}
+
+// UpdateSongBPM обновляет темп песни
+func (r *RetryableRepository) UpdateSongBPM(ctx context.Context, id int64, bpm *int16) error {
+	return withRetryErr(ctx, r, "обновление темпа песни", func() error {
+		return r.repo.UpdateSongBPM(ctx, id, bpm)
+	})
+}
+
+// UpdateSongChart обновляет позицию песни в чарте
+func (r *RetryableRepository) UpdateSongChart(ctx context.Context, id int64, position int16, date string) error {
+	return withRetryErr(ctx, r, "обновление позиции песни в чарте", func() error {
+		return r.repo.UpdateSongChart(ctx, id, position, date)
+	})
+}
+
+// GetChartToppers получает лидеров чарта на дату
+func (r *RetryableRepository) GetChartToppers(ctx context.Context, date string) ([]*model.Song, error) {
+	return withRetry(ctx, r, "получение лидеров чарта", func() ([]*model.Song, error) {
+		return r.repo.GetChartToppers(ctx, date)
+	})
+}
+
+// UpdateSongCopyright обновляет сведения об авторских правах песни
+func (r *RetryableRepository) UpdateSongCopyright(ctx context.Context, id int64, copyright *string) error {
+	return withRetryErr(ctx, r, "обновление сведений об авторских правах песни", func() error {
+		return r.repo.UpdateSongCopyright(ctx, id, copyright)
+	})
+}
+
+// GetSongIDByPublicID получает идентификатор песни по публичному UUID
+func (r *RetryableRepository) GetSongIDByPublicID(ctx context.Context, publicID string) (int64, error) {
+	return withRetry(ctx, r, "получение ID песни по публичному идентификатору", func() (int64, error) {
+		return r.repo.GetSongIDByPublicID(ctx, publicID)
+	})
+}
+
+// UpdateFeaturedArtists заменяет список приглашенных исполнителей песни
+func (r *RetryableRepository) UpdateFeaturedArtists(ctx context.Context, id int64, artists model.Artists) error {
+	return withRetryErr(ctx, r, "обновление приглашенных исполнителей песни", func() error {
+		return r.repo.UpdateFeaturedArtists(ctx, id, artists)
+	})
+}
+
+// GetTotalDuration возвращает суммарную длительность песен
+func (r *RetryableRepository) GetTotalDuration(ctx context.Context, group string) (int64, error) {
+	return withRetry(ctx, r, "получение суммарной длительности", func() (int64, error) {
+		return r.repo.GetTotalDuration(ctx, group)
+	})
+}
+
+// DeleteSong удаляет песню
+func (r *RetryableRepository) DeleteSong(ctx context.Context, id int64) error {
+	return withRetryErr(ctx, r, "удаление песни", func() error {
+		return r.repo.DeleteSong(ctx, id)
+	})
+}
+
+// MarkSongMerged помечает песню удаленной после слияния
+func (r *RetryableRepository) MarkSongMerged(ctx context.Context, id, targetID int64) error {
+	return withRetryErr(ctx, r, "пометка песни как объединенной", func() error {
+		return r.repo.MarkSongMerged(ctx, id, targetID)
+	})
+}
+
+// GetDeletedSongs получает удаленные песни
+func (r *RetryableRepository) GetDeletedSongs(ctx context.Context, page model.Pagination) ([]*model.Song, error) {
+	return withRetry(ctx, r, "получение удаленных песен", func() ([]*model.Song, error) {
+		return r.repo.GetDeletedSongs(ctx, page)
+	})
+}
+
+// GetDeletedSongByIDForUpdate получает удаленную песню с блокировкой строки
+func (r *RetryableRepository) GetDeletedSongByIDForUpdate(ctx context.Context, id int64) (*model.Song, error) {
+	return withRetry(ctx, r, "получение удаленной песни с блокировкой", func() (*model.Song, error) {
+		return r.repo.GetDeletedSongByIDForUpdate(ctx, id)
+	})
+}
+
+// FindActiveSongID возвращает идентификатор активной песни с указанными группой и названием
+func (r *RetryableRepository) FindActiveSongID(ctx context.Context, group, song string) (int64, error) {
+	return withRetry(ctx, r, "поиск активной песни", func() (int64, error) {
+		return r.repo.FindActiveSongID(ctx, group, song)
+	})
+}
+
+// RestoreSong снимает пометку удаления с песни
+func (r *RetryableRepository) RestoreSong(ctx context.Context, id int64) error {
+	return withRetryErr(ctx, r, "восстановление песни", func() error {
+		return r.repo.RestoreSong(ctx, id)
+	})
+}
+
+// FindGroupRenameConflicts находит конфликты переименования группы
+func (r *RetryableRepository) FindGroupRenameConflicts(ctx context.Context, oldName, newName string) ([]model.RenameConflict, error) {
+	return withRetry(ctx, r, "поиск конфликтов переименования группы", func() ([]model.RenameConflict, error) {
+		return r.repo.FindGroupRenameConflicts(ctx, oldName, newName)
+	})
+}
+
+// RenameGroup переименовывает группу у всех ее песен
+func (r *RetryableRepository) RenameGroup(ctx context.Context, oldName, newName string, excludeIDs []int64) ([]int64, error) {
+	return withRetry(ctx, r, "переименование группы", func() ([]int64, error) {
+		return r.repo.RenameGroup(ctx, oldName, newName, excludeIDs)
+	})
+}
+
+// versesPage результат GetSongVerses для передачи через withRetry
+type versesPage struct {
+	verses []string
+	total  int
+}
+
+// GetSongVerses получает куплеты песни и общее количество куплетов
+func (r *RetryableRepository) GetSongVerses(ctx context.Context, id int64, pagination model.VersesPagination) ([]string, int, error) {
+	page, err := withRetry(ctx, r, "получение куплетов песни", func() (versesPage, error) {
+		verses, total, err := r.repo.GetSongVerses(ctx, id, pagination)
+		return versesPage{verses: verses, total: total}, err
+	})
+	return page.verses, page.total, err
+}
+
+// GetAccessLog получает журнал обращений к песне
+func (r *RetryableRepository) GetAccessLog(ctx context.Context, songID int64, from, to *time.Time) ([]model.AccessLogEntry, error) {
+	return withRetry(ctx, r, "получение журнала обращений", func() ([]model.AccessLogEntry, error) {
+		return r.repo.GetAccessLog(ctx, songID, from, to)
+	})
+}
+
+// RecordAccess записывает обращение к песне. Ошибки записи не возвращаются, поэтому повтор не выполняется
+func (r *RetryableRepository) RecordAccess(ctx context.Context, songID int64, action string) {
+	r.repo.RecordAccess(ctx, songID, action)
+}
+
+// GetMostAccessedSongs получает самые популярные песни
+func (r *RetryableRepository) GetMostAccessedSongs(ctx context.Context, since time.Time, limit int) ([]*model.MostAccessedSong, error) {
+	return withRetry(ctx, r, "получение самых популярных песен", func() ([]*model.MostAccessedSong, error) {
+		return r.repo.GetMostAccessedSongs(ctx, since, limit)
+	})
+}
+
+// GetTrendingSongs получает песни с наибольшим количеством обращений за период
+func (r *RetryableRepository) GetTrendingSongs(ctx context.Context, period string, limit int) ([]*model.Song, error) {
+	return withRetry(ctx, r, "получение трендовых песен", func() ([]*model.Song, error) {
+		return r.repo.GetTrendingSongs(ctx, period, limit)
+	})
+}
+
+// GetSongGrowth получает количество добавленных песен по интервалам
+func (r *RetryableRepository) GetSongGrowth(ctx context.Context, interval string, from, to time.Time) ([]model.GrowthBucket, error) {
+	return withRetry(ctx, r, "получение динамики роста библиотеки", func() ([]model.GrowthBucket, error) {
+		return r.repo.GetSongGrowth(ctx, interval, from, to)
+	})
+}
+
+// GetTopGroupsBySongs получает группы с наибольшим количеством песен
+func (r *RetryableRepository) GetTopGroupsBySongs(ctx context.Context, limit int) ([]model.GroupStat, error) {
+	return withRetry(ctx, r, "получение рейтинга групп", func() ([]model.GroupStat, error) {
+		return r.repo.GetTopGroupsBySongs(ctx, limit)
+	})
+}
+
+// GetTopGroupsByPlays получает группы с наибольшим количеством обращений
+func (r *RetryableRepository) GetTopGroupsByPlays(ctx context.Context, limit int) ([]model.GroupStat, error) {
+	return withRetry(ctx, r, "получение рейтинга групп", func() ([]model.GroupStat, error) {
+		return r.repo.GetTopGroupsByPlays(ctx, limit)
+	})
+}
+
+// GetEnrichmentStatusCounts получает количество песен по статусам получения данных
+func (r *RetryableRepository) GetEnrichmentStatusCounts(ctx context.Context) ([]model.EnrichmentStatusCount, error) {
+	return withRetry(ctx, r, "получение количества песен по статусам получения данных", func() ([]model.EnrichmentStatusCount, error) {
+		return r.repo.GetEnrichmentStatusCounts(ctx)
+	})
+}
+
+// GetTempoDistribution получает распределение песен по темпу
+func (r *RetryableRepository) GetTempoDistribution(ctx context.Context) ([]model.TempoBucket, error) {
+	return withRetry(ctx, r, "получение распределения песен по темпу", func() ([]model.TempoBucket, error) {
+		return r.repo.GetTempoDistribution(ctx)
+	})
+}
+
+// AddVerseBookmark сохраняет закладку куплета клиента
+func (r *RetryableRepository) AddVerseBookmark(ctx context.Context, clientID string, songID int64, position int, note string) error {
+	return withRetryErr(ctx, r, "добавление закладки куплета", func() error {
+		return r.repo.AddVerseBookmark(ctx, clientID, songID, position, note)
+	})
+}
+
+// RemoveVerseBookmark удаляет закладку куплета клиента
+func (r *RetryableRepository) RemoveVerseBookmark(ctx context.Context, clientID string, songID int64, position int) (bool, error) {
+	return withRetry(ctx, r, "удаление закладки куплета", func() (bool, error) {
+		return r.repo.RemoveVerseBookmark(ctx, clientID, songID, position)
+	})
+}
+
+// ListVerseBookmarks получает закладки куплетов клиента
+func (r *RetryableRepository) ListVerseBookmarks(ctx context.Context, clientID string) ([]model.VerseBookmark, error) {
+	return withRetry(ctx, r, "получение закладок куплетов", func() ([]model.VerseBookmark, error) {
+		return r.repo.ListVerseBookmarks(ctx, clientID)
+	})
+}
+
+// GetGroupInfo получает сведения о группе
+func (r *RetryableRepository) GetGroupInfo(ctx context.Context, name string) (*model.GroupInfo, error) {
+	return withRetry(ctx, r, "получение сведений о группе", func() (*model.GroupInfo, error) {
+		return r.repo.GetGroupInfo(ctx, name)
+	})
+}
+
+// groupInfoUpsert результат UpsertGroupInfo для передачи через withRetry
+type groupInfoUpsert struct {
+	info    *model.GroupInfo
+	created bool
+}
+
+// UpsertGroupInfo создает или заменяет сведения о группе
+func (r *RetryableRepository) UpsertGroupInfo(ctx context.Context, info *model.GroupInfo) (*model.GroupInfo, bool, error) {
+	result, err := withRetry(ctx, r, "сохранение сведений о группе", func() (groupInfoUpsert, error) {
+		saved, created, err := r.repo.UpsertGroupInfo(ctx, info)
+		return groupInfoUpsert{info: saved, created: created}, err
+	})
+	return result.info, result.created, err
+}
+
+// RenameGroupInfo переносит сведения о группе на новое название
+func (r *RetryableRepository) RenameGroupInfo(ctx context.Context, oldName, newName string) error {
+	return withRetryErr(ctx, r, "перенос сведений о группе", func() error {
+		return r.repo.RenameGroupInfo(ctx, oldName, newName)
+	})
+}
+
+// PurgeDeletedSongs окончательно удаляет пачку песен из корзины
+func (r *RetryableRepository) PurgeDeletedSongs(ctx context.Context, cutoff time.Time, limit int, keepHistory bool) (int64, error) {
+	return withRetry(ctx, r, "удаление песен из корзины", func() (int64, error) {
+		return r.repo.PurgeDeletedSongs(ctx, cutoff, limit, keepHistory)
+	})
+}
+
+// groupSongsPage результат GetGroupSongs для передачи через withRetry
+type groupSongsPage struct {
+	songs []*model.Song
+	total int64
+}
+
+// GetGroupSongs получает страницу песен группы и их общее количество
+func (r *RetryableRepository) GetGroupSongs(ctx context.Context, group, sortBy string, page model.Pagination) ([]*model.Song, int64, error) {
+	result, err := withRetry(ctx, r, "получение песен группы", func() (groupSongsPage, error) {
+		songs, total, err := r.repo.GetGroupSongs(ctx, group, sortBy, page)
+		return groupSongsPage{songs: songs, total: total}, err
+	})
+	return result.songs, result.total, err
+}
+
+// GetTopSongsByGroup получает рейтинг песен группы по метрике
+func (r *RetryableRepository) GetTopSongsByGroup(ctx context.Context, group, metric string, limit int) ([]*model.Song, error) {
+	return withRetry(ctx, r, "получение рейтинга песен группы", func() ([]*model.Song, error) {
+		return r.repo.GetTopSongsByGroup(ctx, group, metric, limit)
+	})
+}
+
+// GetGroupStats получает статистику песен группы
+func (r *RetryableRepository) GetGroupStats(ctx context.Context, group string) (*model.GroupStats, error) {
+	return withRetry(ctx, r, "получение статистики группы", func() (*model.GroupStats, error) {
+		return r.repo.GetGroupStats(ctx, group)
+	})
+}
+
+// WithinTransaction выполняет fn в транзакции. При ошибке соединения транзакция повторяется целиком,
+// если она не вложена в уже открытую транзакцию.
+func (r *RetryableRepository) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return withRetryErr(ctx, r, "транзакция", func() error {
+		return r.repo.WithinTransaction(ctx, fn)
+	})
+}
@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"song-library/pkg/logger"
+	"testing"
+	"time"
+)
+
+// newTestRetryableRepository создает обертку с повтором без репозитория: тесты вызывают withRetry напрямую
+func newTestRetryableRepository(maxRetries int) *RetryableRepository {
+	log := &logger.Logger{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	return NewRetryableRepository(nil, maxRetries, time.Millisecond, log)
+}
+
+// flakyRepository возвращает ошибку failures раз подряд, затем успешный результат
+type flakyRepository struct {
+	failures int
+	err      error
+	calls    int
+}
+
+// GetSongCount имитирует запрос репозитория
+func (r *flakyRepository) GetSongCount() (int64, error) {
+	r.calls++
+	if r.calls <= r.failures {
+		return 0, r.err
+	}
+	return 42, nil
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"остановка сервера администратором", &pq.Error{Code: adminShutdownCode}, true},
+		{"обрыв соединения", &pq.Error{Code: connectionFailureCode}, true},
+		{"нарушение уникальности", &pq.Error{Code: "23505"}, false},
+		{"закрытое соединение драйвера", driver.ErrBadConn, true},
+		{"обернутое закрытое соединение", fmt.Errorf("запрос: %w", driver.ErrBadConn), true},
+		{"конец потока", io.EOF, true},
+		{"неожиданный конец потока", io.ErrUnexpectedEOF, true},
+		{"сетевая ошибка", &net.OpError{Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded}, true},
+		{"обернутая сетевая ошибка", fmt.Errorf("запрос: %w", &net.DNSError{Err: "no such host", Name: "db"}), true},
+		{"строка не найдена", sql.ErrNoRows, false},
+		{"отмена контекста", context.Canceled, false},
+		{"произвольная ошибка", errors.New("boom"), false},
+		{"нет ошибки", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.err); got != tt.want {
+				t.Errorf("isRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxRetries int
+		failures   int
+		err        error
+		wantCalls  int
+		wantErr    bool
+	}{
+		{"успех с первой попытки", 3, 0, nil, 1, false},
+		{"успех после обрыва соединения", 3, 2, driver.ErrBadConn, 3, false},
+		{"успех после неожиданного конца потока", 3, 3, io.ErrUnexpectedEOF, 4, false},
+		{"успех после сетевой ошибки", 1, 1, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, 2, false},
+		{"попытки исчерпаны", 2, 5, io.EOF, 3, true},
+		{"ошибка без повтора", 3, 5, sql.ErrNoRows, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRetryableRepository(tt.maxRetries)
+			repo := &flakyRepository{failures: tt.failures, err: tt.err}
+
+			count, err := withRetry(context.Background(), r, "получение количества песен", repo.GetSongCount)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("withRetry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("withRetry() error = %v, want wrapped %v", err, tt.err)
+			}
+			if err == nil && count != 42 {
+				t.Errorf("withRetry() = %d, want 42", count)
+			}
+			if repo.calls != tt.wantCalls {
+				t.Errorf("вызовов = %d, want %d", repo.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestWithRetryInsideTransaction(t *testing.T) {
+	r := newTestRetryableRepository(3)
+	repo := &flakyRepository{failures: 1, err: driver.ErrBadConn}
+	ctx := context.WithValue(context.Background(), txKey{}, &sqlx.Tx{})
+
+	if _, err := withRetry(ctx, r, "получение количества песен", repo.GetSongCount); !errors.Is(err, driver.ErrBadConn) {
+		t.Fatalf("withRetry() error = %v, want driver.ErrBadConn", err)
+	}
+	if repo.calls != 1 {
+		t.Errorf("вызовов внутри транзакции = %d, want 1", repo.calls)
+	}
+}
+
+func TestWithRetryStopsOnCanceledContext(t *testing.T) {
+	r := newTestRetryableRepository(3)
+	r.delay = time.Hour
+	repo := &flakyRepository{failures: 5, err: io.EOF}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := withRetry(ctx, r, "получение количества песен", repo.GetSongCount); !errors.Is(err, io.EOF) {
+		t.Fatalf("withRetry() error = %v, want io.EOF", err)
+	}
+	if repo.calls != 1 {
+		t.Errorf("вызовов после отмены контекста = %d, want 1", repo.calls)
+	}
+}
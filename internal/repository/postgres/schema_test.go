@@ -0,0 +1,104 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"song-library/internal/migration"
+	"song-library/internal/model"
+	"strings"
+	"testing"
+)
+
+func TestMigrationsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	mustCreateSong(t, repo, newTestSong("Motörhead", "Ace of Spades"))
+
+	tests := []struct {
+		name  string
+		reset string
+	}{
+		{"повторный запуск", ``},
+		// База, созданная до появления schema_migrations, проходит все миграции повторно
+		{"без таблицы версий", `DELETE FROM schema_migrations`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.reset != "" {
+				mustExec(t, tt.reset)
+			}
+			if err := migration.RunMigrations(testDB.DB, testLogger); err != nil {
+				t.Fatalf("RunMigrations() error = %v", err)
+			}
+			version, err := migration.AppliedVersion(ctx, testDB.DB)
+			if err != nil {
+				t.Fatalf("AppliedVersion() error = %v", err)
+			}
+			if version != migration.Version() {
+				t.Errorf("AppliedVersion() = %d, want %d", version, migration.Version())
+			}
+			song, err := repo.GetSongByID(ctx, 1)
+			if err != nil || song == nil || song.Group != "Motörhead" {
+				t.Errorf("GetSongByID() после миграций = %v, %v", song, err)
+			}
+		})
+	}
+}
+
+func TestNormalizeNameMatchesUnaccent(t *testing.T) {
+	// Миграция заполняет нормализованные названия через lower(unaccent(...)), а приложение через model.NormalizeName:
+	// поиск по group_name_norm и song_name_norm работает, только если они совпадают
+	names := []string{"Motörhead", "Mötley Crüe", "Beyoncé", "Ёлка", "Sigur Rós", "MUSE", "Би-2"}
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			var want string
+			if err := testDB.Get(&want, `SELECT lower(unaccent($1))`, name); err != nil {
+				t.Fatalf("ошибка вызова unaccent: %v", err)
+			}
+			if got := model.NormalizeName(name); got != want {
+				t.Errorf("NormalizeName(%q) = %q, want %q", name, got, want)
+			}
+		})
+	}
+}
+
+func TestTrigramIndexUsed(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	seedFilterSongs(t, repo)
+
+	tests := []struct {
+		name      string
+		condition string
+		wantIndex string
+	}{
+		{"группа", `group_name_norm LIKE '%mus%'`, "idx_songs_group_name_norm"},
+		{"название", `song_name_norm LIKE '%rising%'`, "idx_songs_song_name_norm"},
+		{"текст", `text ILIKE '%paranoia%'`, "idx_songs_text_trgm"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := testDB.BeginTxx(ctx, nil)
+			if err != nil {
+				t.Fatalf("ошибка начала транзакции: %v", err)
+			}
+			defer tx.Rollback()
+
+			// На нескольких строках планировщик предпочитает последовательное чтение, поэтому оно отключается
+			if _, err = tx.ExecContext(ctx, `SET LOCAL enable_seqscan = off`); err != nil {
+				t.Fatalf("ошибка отключения последовательного чтения: %v", err)
+			}
+			var plan []string
+			if err = tx.SelectContext(ctx, &plan, `EXPLAIN SELECT id FROM songs WHERE `+tt.condition); err != nil {
+				t.Fatalf("ошибка EXPLAIN: %v", err)
+			}
+			if !strings.Contains(strings.Join(plan, "\n"), tt.wantIndex) {
+				t.Errorf("план запроса не использует %s:\n%s", tt.wantIndex, strings.Join(plan, "\n"))
+			}
+		})
+	}
+}
@@ -3,42 +3,152 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"slices"
 	"song-library/internal/model"
 	"song-library/pkg/logger"
 	"strings"
 	"time"
 )
 
+// songColumns список колонок песни, включая вычисляемые количество куплетов и длину текста
+const songColumns = songListColumns + `, copyright`
+
+// songListColumns список колонок песни для списков: без сведений об авторских правах, которые бывают длинными
+const songListColumns = `id, public_id, group_name, song_name, release_date, text, link, created_at, updated_at, duration_seconds, bpm, source, content_hash, featured_artists, enrichment_status, enriched_at, chart_position, chart_history,
+	CASE WHEN text = '' THEN 0 ELSE array_length(string_to_array(text, E'\n\n'), 1) END AS verse_count,
+	char_length(text) AS text_length`
+
+// songColumnsWithoutText список колонок песни без текста для облегченных списков
+const songColumnsWithoutText = `id, public_id, group_name, song_name, release_date, '' AS text, link, created_at, updated_at, duration_seconds, bpm, source, content_hash, featured_artists, enrichment_status, enriched_at, chart_position, chart_history,
+	CASE WHEN text = '' THEN 0 ELSE array_length(string_to_array(text, E'\n\n'), 1) END AS verse_count,
+	char_length(text) AS text_length`
+
+// RepositoryConfig настройки репозитория песен
+type RepositoryConfig struct {
+	// DisableAccessLog отключает запись обращений к песням в song_access_log
+	DisableAccessLog bool
+	// CopyThreshold минимальное количество песен, начиная с которого массовая вставка идет через COPY
+	CopyThreshold int
+	// QueryComments добавляет к запросам комментарий с идентификатором запроса API.
+	// Меняет текст запросов, из-за чего pg_stat_statements группирует их по каждому идентификатору
+	QueryComments bool
+}
+
+// songColumnsPrefixed список колонок песни для запросов, где таблица songs имеет псевдоним s
+const songColumnsPrefixed = `s.id, s.public_id, s.group_name, s.song_name, s.release_date, s.text, s.link, s.created_at, s.updated_at, s.duration_seconds, s.bpm, s.source, s.content_hash, s.featured_artists, s.enrichment_status, s.enriched_at, s.chart_position, s.chart_history,
+	CASE WHEN s.text = '' THEN 0 ELSE array_length(string_to_array(s.text, E'\n\n'), 1) END AS verse_count,
+	char_length(s.text) AS text_length`
+
 // SongRepository представляет репозиторий для работы с песнями в PostgreSQL
 type SongRepository struct {
-	db     *sqlx.DB
+	db *sqlx.DB
+	// readDB пул соединений с репликой для чтения; nil, если реплика не настроена
+	readDB *sqlx.DB
+	cfg    RepositoryConfig
 	logger *logger.Logger
+	// stmts подготовленные запросы по тексту запроса; nil, если Prepare не вызывался или завершился ошибкой
+	stmts map[string]*sqlx.Stmt
 }
 
-// NewSongRepository создает новый репозиторий песен
-func NewSongRepository(db *sqlx.DB, logger *logger.Logger) *SongRepository {
+// NewSongRepository создает новый репозиторий песен.
+// readDB — пул соединений с репликой для запросов чтения; nil, если все запросы идут в основную базу.
+func NewSongRepository(db, readDB *sqlx.DB, cfg RepositoryConfig, logger *logger.Logger) *SongRepository {
 	return &SongRepository{
 		db:     db,
+		readDB: readDB,
+		cfg:    cfg,
 		logger: logger,
 	}
 }
 
-// NewPostgresDB устанавливает соединение с базой данных PostgreSQL
-func NewPostgresDB(host, port, user, password, dbname string, logger *logger.Logger) (*sqlx.DB, error) {
+// txKey ключ контекста, под которым хранится текущая транзакция
+type txKey struct{}
+
+// WithinTransaction выполняет fn в рамках одной транзакции.
+// Методы репозитория, вызванные с переданным в fn контекстом, используют эту транзакцию.
+func (r *SongRepository) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	log := r.logger.WithContext(ctx)
+
+	if _, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error("Ошибка начала транзакции", "error", err)
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Error("Ошибка отката транзакции", "error", rbErr)
+		}
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error("Ошибка фиксации транзакции", "error", err)
+		return fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+
+	return nil
+}
+
+// txOrDB возвращает транзакцию из контекста, если она есть, иначе пул соединений db
+func txOrDB(ctx context.Context, db *sqlx.DB) sqlx.ExtContext {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// conn возвращает транзакцию из контекста, если она есть, иначе пул соединений.
+// Запросы, подготовленные Prepare, выполняются через подготовленные выражения.
+func (r *SongRepository) conn(ctx context.Context) sqlx.ExtContext {
+	conn := txOrDB(ctx, r.db)
+	if r.stmts != nil {
+		conn = preparedConn{ExtContext: conn, stmts: r.stmts}
+	}
+	if r.cfg.QueryComments {
+		return commentingConn{conn}
+	}
+	return conn
+}
+
+// readConn возвращает соединение для запросов чтения, допускающих отставание реплики.
+// Внутри транзакции и без настроенной реплики совпадает с conn.
+// Подготовленные выражения относятся к основной базе, поэтому запросы к реплике выполняются без подготовки.
+func (r *SongRepository) readConn(ctx context.Context) sqlx.ExtContext {
+	if _, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok || r.readDB == nil {
+		return r.conn(ctx)
+	}
+	if r.cfg.QueryComments {
+		return commentingConn{r.readDB}
+	}
+	return r.readDB
+}
+
+// NewPostgresDB открывает пул соединений с базой данных PostgreSQL.
+// Соединение устанавливается при первом запросе; доступность базы проверяет selfcheck при запуске.
+// applicationName отображается в pg_stat_activity и логах PostgreSQL
+func NewPostgresDB(host, port, user, password, dbname, applicationName string, logger *logger.Logger) (*sqlx.DB, error) {
 	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		host, port, user, password, dbname)
+	if applicationName != "" {
+		connStr += fmt.Sprintf(" application_name='%s'", strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(applicationName))
+	}
 
 	logger.Debug("Подключение к базе данных", "connection_string", connStr)
-	db, err := sqlx.Connect("postgres", connStr)
+	db, err := sqlx.Open("postgres", connStr)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка подключения к базе данных: %w", err)
 	}
 
-	logger.Info("Успешное подключение к базе данных")
-
 	return db, nil
 }
 
@@ -46,20 +156,21 @@ func NewPostgresDB(host, port, user, password, dbname string, logger *logger.Log
 func (r *SongRepository) CreateSong(ctx context.Context, song *model.Song) (int64, error) {
 	log := r.logger.WithContext(ctx)
 
-	query := `INSERT INTO songs (group_name, song_name, release_date, text, link, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id`
-
 	log.Debug("Создание новой песни", "group", song.Group, "song", song.Song)
 
 	now := time.Now()
 	song.CreatedAt = now
 	song.UpdatedAt = now
+	song.ComputeContentHash()
+	if song.EnrichmentStatus == "" {
+		// Песни, добавленные без обращения к поставщику данных, ожидают получения данных
+		song.EnrichmentStatus = model.EnrichmentStatusPending
+	}
 
 	var id int64
-	err := r.db.QueryRowContext(
+	err := r.conn(ctx).QueryRowxContext(
 		ctx,
-		query,
+		createSongQuery,
 		song.Group,
 		song.Song,
 		song.ReleaseDate,
@@ -67,16 +178,78 @@ func (r *SongRepository) CreateSong(ctx context.Context, song *model.Song) (int6
 		song.Link,
 		song.CreatedAt,
 		song.UpdatedAt,
-	).Scan(&id)
+		song.Duration,
+		model.NormalizeName(song.Group),
+		model.NormalizeName(song.Song),
+		song.Provenance,
+		song.BPM,
+		song.Copyright,
+		song.ContentHash,
+		song.FeaturedArtists,
+		song.EnrichmentStatus,
+		song.EnrichedAt,
+	).Scan(&id, &song.PublicID)
 	if err != nil {
 		log.Error("Ошибка создания песни", "error", err)
-		return 0, fmt.Errorf("ошибка создания песни: %w", err)
+		return 0, wrapUniqueViolation(fmt.Errorf("ошибка создания песни: %w", err))
 	}
 
 	log.Info("Песня успешно создана", "id", id)
 	return id, nil
 }
 
+// GetSongIDByPublicID получает идентификатор песни, включая удаленные в корзину, по публичному UUID.
+// Возвращает 0, если песни нет. Запрос выполняется на основной базе: клиент обращается по публичному
+// идентификатору сразу после создания песни, и отставание реплики приводило бы к 404.
+func (r *SongRepository) GetSongIDByPublicID(ctx context.Context, publicID string) (int64, error) {
+	log := r.logger.WithFields(ctx, "public_id", publicID)
+
+	log.Debug("Получение ID песни по публичному идентификатору")
+
+	var id int64
+	err := r.conn(ctx).QueryRowxContext(ctx, `SELECT id FROM songs WHERE public_id = $1`, publicID).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Info("Песня с публичным идентификатором не найдена")
+			return 0, nil
+		}
+		log.Error("Ошибка получения ID песни по публичному идентификатору", "error", err)
+		return 0, fmt.Errorf("ошибка получения ID песни по публичному идентификатору: %w", err)
+	}
+
+	log.Debug("ID песни по публичному идентификатору получен", "id", id)
+	return id, nil
+}
+
+// missingFieldConditions условия незаполненности полей песни: пустая строка для текстовых колонок и NULL для необязательных
+var missingFieldConditions = map[string]string{
+	model.FieldText:        "text = ''",
+	model.FieldLink:        "link = ''",
+	model.FieldReleaseDate: "release_date = ''",
+	model.FieldDuration:    "duration_seconds IS NULL",
+	model.FieldBPM:         "bpm IS NULL",
+}
+
+// presenceCondition возвращает условие заполненности (present) или пустоты текстовой колонки
+func presenceCondition(column string, present bool) string {
+	if present {
+		return column + " <> ''"
+	}
+	return column + " = ''"
+}
+
+// Порядки сортировки списка песен
+const (
+	songsOrderNewest    = "newest"
+	songsOrderRelevance = "relevance"
+)
+
+// songsOrder порядки списка песен: по умолчанию новые песни первыми, при быстром поиске — по релевантности
+var songsOrder = sortOrders{
+	songsOrderNewest:    "",
+	songsOrderRelevance: "relevance DESC",
+}
+
 // GetSongs получает список песен с фильтрацией и пагинацией
 func (r *SongRepository) GetSongs(ctx context.Context, filter model.SongFilter) ([]*model.Song, error) {
 	log := r.logger.WithContext(ctx)
@@ -84,40 +257,163 @@ func (r *SongRepository) GetSongs(ctx context.Context, filter model.SongFilter)
 	log.Debug("Получение списка песен с фильтром",
 		"group", filter.Group,
 		"song", filter.SongName,
+		"text", filter.Text,
+		"q", filter.QuickSearch,
 		"page", filter.Page,
 		"pageSize", filter.PageSize)
 
-	query := `SELECT id, group_name, song_name, release_date, text, link, created_at, updated_at 
-		FROM songs WHERE 1=1`
+	columns := songListColumns
+	if filter.OmitText {
+		columns = songColumnsWithoutText
+	}
+
+	where := ` WHERE deleted_at IS NULL`
+	order := songsOrderNewest
 	params := []interface{}{}
 	paramCount := 1
 
 	if filter.Group != "" {
-		query += fmt.Sprintf(" AND group_name ILIKE $%d", paramCount)
+		where += fmt.Sprintf(" AND group_name_norm LIKE $%d", paramCount)
 		params = append(params, "%"+filter.Group+"%")
 		paramCount++
 	}
 
 	if filter.SongName != "" {
-		query += fmt.Sprintf(" AND song_name ILIKE $%d", paramCount)
+		where += fmt.Sprintf(" AND song_name_norm LIKE $%d", paramCount)
 		params = append(params, "%"+filter.SongName+"%")
 		paramCount++
 	}
 
-	offset := (filter.Page - 1) * filter.PageSize
-	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d OFFSET $%d", paramCount, paramCount+1)
+	if len(filter.Groups) > 0 {
+		where += fmt.Sprintf(" AND group_name = ANY($%d)", paramCount)
+		params = append(params, pq.Array(filter.Groups))
+		paramCount++
+	}
+
+	if len(filter.SongNames) > 0 {
+		where += fmt.Sprintf(" AND song_name = ANY($%d)", paramCount)
+		params = append(params, pq.Array(filter.SongNames))
+		paramCount++
+	}
+
+	if filter.Text != "" {
+		where += fmt.Sprintf(" AND text ILIKE $%d", paramCount)
+		params = append(params, "%"+filter.Text+"%")
+		paramCount++
+	}
+
+	if filter.QuickSearch != "" {
+		where += fmt.Sprintf(" AND (group_name ILIKE $%[1]d OR song_name ILIKE $%[1]d OR text ILIKE $%[1]d)", paramCount)
+		columns += fmt.Sprintf(`, CASE WHEN song_name ILIKE $%[1]d THEN 1.0
+			WHEN group_name ILIKE $%[1]d THEN 0.8
+			WHEN text ILIKE $%[1]d THEN 0.6
+			ELSE 0 END AS relevance`, paramCount)
+		order = songsOrderRelevance
+		params = append(params, "%"+filter.QuickSearch+"%")
+		paramCount++
+	}
+
+	switch {
+	case filter.DurationMin != nil && filter.DurationMax != nil:
+		where += fmt.Sprintf(" AND duration_seconds BETWEEN $%d AND $%d", paramCount, paramCount+1)
+		params = append(params, *filter.DurationMin, *filter.DurationMax)
+		paramCount += 2
+	case filter.DurationMin != nil:
+		where += fmt.Sprintf(" AND duration_seconds >= $%d", paramCount)
+		params = append(params, *filter.DurationMin)
+		paramCount++
+	case filter.DurationMax != nil:
+		where += fmt.Sprintf(" AND duration_seconds <= $%d", paramCount)
+		params = append(params, *filter.DurationMax)
+		paramCount++
+	}
+
+	switch {
+	case filter.BPMMin != nil && filter.BPMMax != nil:
+		where += fmt.Sprintf(" AND bpm BETWEEN $%d AND $%d", paramCount, paramCount+1)
+		params = append(params, *filter.BPMMin, *filter.BPMMax)
+		paramCount += 2
+	case filter.BPMMin != nil:
+		where += fmt.Sprintf(" AND bpm >= $%d", paramCount)
+		params = append(params, *filter.BPMMin)
+		paramCount++
+	case filter.BPMMax != nil:
+		where += fmt.Sprintf(" AND bpm <= $%d", paramCount)
+		params = append(params, *filter.BPMMax)
+		paramCount++
+	}
+
+	if filter.FeaturedArtist != "" {
+		artist, err := json.Marshal([]string{filter.FeaturedArtist})
+		if err != nil {
+			log.Error("Ошибка сериализации фильтра приглашенного исполнителя", "error", err)
+			return nil, fmt.Errorf("ошибка сериализации фильтра приглашенного исполнителя: %w", err)
+		}
+		where += fmt.Sprintf(" AND featured_artists @> $%d::jsonb", paramCount)
+		params = append(params, string(artist))
+		paramCount++
+	}
+
+	if filter.EnrichmentStatus != "" {
+		where += fmt.Sprintf(" AND enrichment_status = $%d", paramCount)
+		params = append(params, filter.EnrichmentStatus)
+		paramCount++
+	}
+
+	if filter.CopyrightContains != "" {
+		where += fmt.Sprintf(" AND copyright ILIKE $%d", paramCount)
+		params = append(params, "%"+filter.CopyrightContains+"%")
+		paramCount++
+	}
+
+	if filter.CreatedAtFrom != nil {
+		where += fmt.Sprintf(" AND created_at >= $%d", paramCount)
+		params = append(params, *filter.CreatedAtFrom)
+		paramCount++
+	}
+	if filter.CreatedAtTo != nil {
+		where += fmt.Sprintf(" AND created_at <= $%d", paramCount)
+		params = append(params, *filter.CreatedAtTo)
+		paramCount++
+	}
+
+	if filter.HasText != nil {
+		where += " AND " + presenceCondition("text", *filter.HasText)
+	}
+	if filter.HasLink != nil {
+		where += " AND " + presenceCondition("link", *filter.HasLink)
+	}
+
+	for _, field := range filter.MissingFields {
+		condition, ok := missingFieldConditions[field]
+		if !ok {
+			log.Error("Неизвестное поле в фильтре незаполненных полей", "field", field)
+			return nil, fmt.Errorf("неизвестное поле в фильтре незаполненных полей: %s", field)
+		}
+		where += " AND " + condition
+	}
+
+	orderBy, err := songsOrder.orderBy(order, "DESC")
+	if err != nil {
+		log.Error("Ошибка построения порядка списка песен", "error", err)
+		return nil, err
+	}
+
+	offset := filter.Offset()
+	query := `SELECT ` + columns + ` FROM songs` + where + orderBy +
+		fmt.Sprintf(" LIMIT $%d OFFSET $%d", paramCount, paramCount+1)
 	params = append(params, filter.PageSize, offset)
 
 	log.Debug("Выполнение запроса", "query", query, "params", params)
 
-	rows, err := r.db.QueryxContext(ctx, query, params...)
+	rows, err := r.readConn(ctx).QueryxContext(ctx, query, params...)
 	if err != nil {
 		log.Error("Ошибка получения списка песен", "error", err)
 		return nil, fmt.Errorf("ошибка получения списка песен: %w", err)
 	}
 	defer rows.Close()
 
-	var songs []*model.Song
+	songs := []*model.Song{}
 	for rows.Next() {
 		var song model.Song
 		if err = rows.StructScan(&song); err != nil {
@@ -131,47 +427,181 @@ func (r *SongRepository) GetSongs(ctx context.Context, filter model.SongFilter)
 	return songs, nil
 }
 
+// GetSongsByIDs получает песни по списку идентификаторов
+func (r *SongRepository) GetSongsByIDs(ctx context.Context, ids []int64) ([]*model.Song, error) {
+	log := r.logger.WithContext(ctx)
+
+	log.Debug("Получение песен по списку ID", "ids", ids)
+
+	query := `SELECT ` + songColumns + ` FROM songs WHERE id = ANY($1) AND deleted_at IS NULL ORDER BY id DESC`
+
+	songs := []*model.Song{}
+	if err := sqlx.SelectContext(ctx, r.readConn(ctx), &songs, query, pq.Array(ids)); err != nil {
+		log.Error("Ошибка получения песен по списку ID", "error", err)
+		return nil, fmt.Errorf("ошибка получения песен по списку ID: %w", err)
+	}
+
+	log.Info("Успешно получены песни по списку ID", "count", len(songs))
+	return songs, nil
+}
+
+// GetSongSummaries получает до limit песен без текста в порядке возрастания id
+func (r *SongRepository) GetSongSummaries(ctx context.Context, limit int) ([]*model.Song, error) {
+	log := r.logger.WithContext(ctx)
+
+	log.Debug("Получение песен без текста", "limit", limit)
+
+	query := `SELECT ` + songColumnsWithoutText + ` FROM songs WHERE deleted_at IS NULL ORDER BY id LIMIT $1`
+
+	songs := []*model.Song{}
+	if err := sqlx.SelectContext(ctx, r.readConn(ctx), &songs, query, limit); err != nil {
+		log.Error("Ошибка получения песен без текста", "error", err)
+		return nil, fmt.Errorf("ошибка получения песен без текста: %w", err)
+	}
+
+	log.Info("Успешно получены песни без текста", "count", len(songs))
+	return songs, nil
+}
+
+// IterateSongs передает fn песни библиотеки по одной в порядке возрастания id, не загружая их в память целиком.
+// Ошибка fn или отмена ctx прерывает чтение и возвращается вызывающему.
+func (r *SongRepository) IterateSongs(ctx context.Context, fn func(song *model.Song) error) error {
+	log := r.logger.WithContext(ctx)
+
+	log.Debug("Потоковое чтение песен")
+
+	query := `SELECT ` + songColumns + ` FROM songs WHERE deleted_at IS NULL ORDER BY id`
+	return r.iterateSongs(ctx, fn, query)
+}
+
+// IterateGroupSongs передает fn песни группы по одной в порядке названий, не загружая их в память целиком.
+// Ошибка fn или отмена ctx прерывает чтение и возвращается вызывающему.
+func (r *SongRepository) IterateGroupSongs(ctx context.Context, group string, fn func(song *model.Song) error) error {
+	log := r.logger.WithFields(ctx, "group", group)
+
+	log.Debug("Потоковое чтение песен группы")
+
+	query := `SELECT ` + songColumns + ` FROM songs WHERE group_name = $1 AND deleted_at IS NULL ORDER BY song_name_norm, id`
+	return r.iterateSongs(ctx, fn, query, group)
+}
+
+// iterateSongs выполняет запрос песен и передает fn строки результата по одной
+func (r *SongRepository) iterateSongs(ctx context.Context, fn func(song *model.Song) error, query string, args ...interface{}) error {
+	log := r.logger.WithContext(ctx)
+
+	rows, err := r.readConn(ctx).QueryxContext(ctx, query, args...)
+	if err != nil {
+		log.Error("Ошибка потокового чтения песен", "error", err)
+		return fmt.Errorf("ошибка потокового чтения песен: %w", err)
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		var song model.Song
+		if err = rows.StructScan(&song); err != nil {
+			log.Error("Ошибка сканирования песни", "error", err)
+			return fmt.Errorf("ошибка сканирования песни: %w", err)
+		}
+		if err = fn(&song); err != nil {
+			log.Info("Потоковое чтение песен прервано", "count", count, "error", err)
+			return err
+		}
+		count++
+	}
+	if err = rows.Err(); err != nil {
+		log.Error("Ошибка потокового чтения песен", "error", err)
+		return fmt.Errorf("ошибка потокового чтения песен: %w", err)
+	}
+
+	log.Info("Потоковое чтение песен завершено", "count", count)
+	return nil
+}
+
 // GetSongByID получает песню по идентификатору
 func (r *SongRepository) GetSongByID(ctx context.Context, id int64) (*model.Song, error) {
-	log := r.logger.WithContext(ctx)
+	log := r.logger.WithFields(ctx, "id", id)
+
+	log.Debug("Получение песни по ID")
+
+	song, err := r.getSong(ctx, r.readConn(ctx), getSongByIDQuery, id)
+	if err != nil || song == nil {
+		return song, err
+	}
+
+	r.recordAccess(ctx, id, model.AccessActionView)
+	return song, nil
+}
+
+// GetSongByIDPrimary получает песню по идентификатору из основной базы, минуя реплику.
+// Используется там, где отставание реплики недопустимо, например при заполнении кэша процесса после записи.
+func (r *SongRepository) GetSongByIDPrimary(ctx context.Context, id int64) (*model.Song, error) {
+	log := r.logger.WithFields(ctx, "id", id)
 
-	log.Debug("Получение песни по ID", "id", id)
+	log.Debug("Получение песни по ID из основной базы")
 
-	query := `SELECT id, group_name, song_name, release_date, text, link, created_at, updated_at FROM songs WHERE id = $1`
+	song, err := r.getSong(ctx, r.conn(ctx), getSongByIDQuery, id)
+	if err != nil || song == nil {
+		return song, err
+	}
+
+	r.recordAccess(ctx, id, model.AccessActionView)
+	return song, nil
+}
+
+// GetSongByIDForUpdate получает песню по идентификатору и блокирует строку до конца транзакции
+func (r *SongRepository) GetSongByIDForUpdate(ctx context.Context, id int64) (*model.Song, error) {
+	log := r.logger.WithFields(ctx, "id", id)
+
+	log.Debug("Получение песни по ID с блокировкой")
+
+	query := `SELECT ` + songColumns + ` FROM songs WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+
+	return r.getSong(ctx, r.conn(ctx), query, id)
+}
+
+// getSong выполняет запрос одной песни по идентификатору через соединение conn
+func (r *SongRepository) getSong(ctx context.Context, conn sqlx.ExtContext, query string, id int64) (*model.Song, error) {
+	log := r.logger.WithFields(ctx, "id", id)
 
 	var song model.Song
-	err := r.db.GetContext(ctx, &song, query, id)
+	err := sqlx.GetContext(ctx, conn, &song, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			log.Info("Песня не найдена", "id", id)
+			log.Info("Песня не найдена")
 			return nil, nil
 		}
 		log.Error("Ошибка получения песни", "error", err)
 		return nil, fmt.Errorf("ошибка получения песни: %w", err)
 	}
 
-	log.Info("Песня успешно получена", "id", id)
+	log.Info("Песня успешно получена")
 	return &song, nil
 }
 
 // UpdateSong обновляет данные песни
 func (r *SongRepository) UpdateSong(ctx context.Context, song *model.Song) error {
-	log := r.logger.WithContext(ctx)
-
-	log.Debug("Обновление песни", "id", song.ID)
+	log := r.logger.WithFields(ctx, "id", song.ID)
 
-	query := `UPDATE songs SET group_name = $1, song_name = $2, release_date = $3, text = $4, link = $5, updated_at = $6 WHERE id = $7`
+	log.Debug("Обновление песни")
 
 	song.UpdatedAt = time.Now()
-	result, err := r.db.ExecContext(
+	song.ComputeContentHash()
+	result, err := r.conn(ctx).ExecContext(
 		ctx,
-		query,
+		updateSongQuery,
 		song.Group,
 		song.Song,
 		song.ReleaseDate,
 		song.Text,
 		song.Link,
 		song.UpdatedAt,
+		song.Duration,
+		model.NormalizeName(song.Group),
+		model.NormalizeName(song.Song),
+		song.Provenance,
+		song.BPM,
+		song.ContentHash,
 		song.ID,
 	)
 
@@ -187,23 +617,155 @@ func (r *SongRepository) UpdateSong(ctx context.Context, song *model.Song) error
 	}
 
 	if rowsAffected == 0 {
-		log.Info("Песня для обновления не найдена", "id", song.ID)
-		return fmt.Errorf("песня с id %d не найдена", song.ID)
+		log.Info("Песня для обновления не найдена")
+		return model.NewNotFoundError(song.ID)
 	}
 
-	log.Info("Песня успешно обновлена", "id", song.ID)
+	log.Info("Песня успешно обновлена")
 	return nil
 }
 
-// DeleteSong удаляет песню из базы данных
-func (r *SongRepository) DeleteSong(ctx context.Context, id int64) error {
-	log := r.logger.WithContext(ctx)
+// UpdateSongDuration обновляет длительность песни
+func (r *SongRepository) UpdateSongDuration(ctx context.Context, id int64, duration *int) error {
+	log := r.logger.WithFields(ctx, "id", id)
 
-	log.Debug("Удаление песни", "id", id)
+	log.Debug("Обновление длительности песни")
 
-	query := `DELETE FROM songs WHERE id = $1`
+	// Длительность, заданная вручную, больше не считается полученной от поставщика данных
+	query := `UPDATE songs SET duration_seconds = $1, updated_at = $2, source = source - 'duration' WHERE id = $3 AND deleted_at IS NULL`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := r.conn(ctx).ExecContext(ctx, query, duration, time.Now(), id)
+	if err != nil {
+		log.Error("Ошибка обновления длительности песни", "error", err)
+		return fmt.Errorf("ошибка обновления длительности песни: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Error("Ошибка получения количества затронутых строк", "error", err)
+		return fmt.Errorf("ошибка получения количества затронутых строк: %w", err)
+	}
+	if rowsAffected == 0 {
+		log.Info("Песня для обновления длительности не найдена")
+		return model.NewNotFoundError(id)
+	}
+
+	log.Info("Длительность песни успешно обновлена")
+	return nil
+}
+
+// UpdateSongBPM обновляет темп песни
+func (r *SongRepository) UpdateSongBPM(ctx context.Context, id int64, bpm *int16) error {
+	log := r.logger.WithFields(ctx, "id", id)
+
+	log.Debug("Обновление темпа песни")
+
+	// Темп, заданный вручную, больше не считается полученным от поставщика данных
+	query := `UPDATE songs SET bpm = $1, updated_at = $2, source = source - 'bpm' WHERE id = $3 AND deleted_at IS NULL`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, bpm, time.Now(), id)
+	if err != nil {
+		log.Error("Ошибка обновления темпа песни", "error", err)
+		return fmt.Errorf("ошибка обновления темпа песни: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Error("Ошибка получения количества затронутых строк", "error", err)
+		return fmt.Errorf("ошибка получения количества затронутых строк: %w", err)
+	}
+	if rowsAffected == 0 {
+		log.Info("Песня для обновления темпа не найдена")
+		return model.NewNotFoundError(id)
+	}
+
+	log.Info("Темп песни успешно обновлен")
+	return nil
+}
+
+// UpdateSongCopyright обновляет сведения об авторских правах песни
+func (r *SongRepository) UpdateSongCopyright(ctx context.Context, id int64, copyright *string) error {
+	log := r.logger.WithFields(ctx, "id", id)
+
+	log.Debug("Обновление сведений об авторских правах песни")
+
+	// Сведения, заданные вручную, больше не считаются полученными от поставщика данных
+	query := `UPDATE songs SET copyright = $1, updated_at = $2, source = source - 'copyright' WHERE id = $3 AND deleted_at IS NULL`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, copyright, time.Now(), id)
+	if err != nil {
+		log.Error("Ошибка обновления сведений об авторских правах песни", "error", err)
+		return fmt.Errorf("ошибка обновления сведений об авторских правах песни: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Error("Ошибка получения количества затронутых строк", "error", err)
+		return fmt.Errorf("ошибка получения количества затронутых строк: %w", err)
+	}
+	if rowsAffected == 0 {
+		log.Info("Песня для обновления сведений об авторских правах не найдена")
+		return model.NewNotFoundError(id)
+	}
+
+	log.Info("Сведения об авторских правах песни успешно обновлены")
+	return nil
+}
+
+// UpdateFeaturedArtists заменяет список приглашенных исполнителей песни
+func (r *SongRepository) UpdateFeaturedArtists(ctx context.Context, id int64, artists model.Artists) error {
+	log := r.logger.WithFields(ctx, "id", id)
+
+	log.Debug("Обновление приглашенных исполнителей песни")
+
+	// Список, заданный вручную, больше не считается полученным от поставщика данных
+	query := `UPDATE songs SET featured_artists = $1, updated_at = $2, source = source - 'featuredArtists' WHERE id = $3 AND deleted_at IS NULL`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, artists, time.Now(), id)
+	if err != nil {
+		log.Error("Ошибка обновления приглашенных исполнителей песни", "error", err)
+		return fmt.Errorf("ошибка обновления приглашенных исполнителей песни: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Error("Ошибка получения количества затронутых строк", "error", err)
+		return fmt.Errorf("ошибка получения количества затронутых строк: %w", err)
+	}
+	if rowsAffected == 0 {
+		log.Info("Песня для обновления приглашенных исполнителей не найдена")
+		return model.NewNotFoundError(id)
+	}
+
+	log.Info("Приглашенные исполнители песни успешно обновлены", "count", len(artists))
+	return nil
+}
+
+// GetTotalDuration возвращает суммарную длительность песен, группа которых соответствует фильтру
+func (r *SongRepository) GetTotalDuration(ctx context.Context, group string) (int64, error) {
+	log := r.logger.WithFields(ctx, "group", group)
+
+	log.Debug("Получение суммарной длительности песен")
+
+	query := `SELECT COALESCE(SUM(duration_seconds), 0) FROM songs WHERE group_name_norm LIKE $1 AND deleted_at IS NULL`
+
+	var total int64
+	if err := r.readConn(ctx).QueryRowxContext(ctx, query, "%"+group+"%").Scan(&total); err != nil {
+		log.Error("Ошибка получения суммарной длительности песен", "error", err)
+		return 0, fmt.Errorf("ошибка получения суммарной длительности песен: %w", err)
+	}
+
+	log.Info("Суммарная длительность песен успешно получена", "total_seconds", total)
+	return total, nil
+}
+
+// DeleteSong помечает песню удаленной. Запись сохраняется и может быть восстановлена через RestoreSong
+func (r *SongRepository) DeleteSong(ctx context.Context, id int64) error {
+	log := r.logger.WithFields(ctx, "id", id)
+
+	log.Debug("Удаление песни")
+
+	result, err := r.conn(ctx).ExecContext(ctx, deleteSongQuery, time.Now(), id)
 	if err != nil {
 		log.Error("Ошибка удаления песни", "error", err)
 		return fmt.Errorf("ошибка удаления песни: %w", err)
@@ -215,42 +777,96 @@ func (r *SongRepository) DeleteSong(ctx context.Context, id int64) error {
 		return fmt.Errorf("ошибка получения количества затронутых строк: %w", err)
 	}
 	if rowsAffected == 0 {
-		log.Info("Песня для удаления не найдена", "id", id)
-		return fmt.Errorf("песня с id %d не найдена", id)
+		log.Info("Песня для удаления не найдена")
+		return model.NewNotFoundError(id)
 	}
 
-	log.Info("Песня успешно удалена", "id", id)
+	log.Info("Песня успешно удалена")
 	return nil
 }
 
-// GetSongVerses получает куплеты песни с пагинацией
-func (r *SongRepository) GetSongVerses(ctx context.Context, id int64, pagination model.VersesPagination) ([]string, error) {
-	log := r.logger.WithContext(ctx)
+// MarkSongMerged помечает песню удаленной после слияния с песней targetID
+func (r *SongRepository) MarkSongMerged(ctx context.Context, id, targetID int64) error {
+	log := r.logger.WithFields(ctx, "id", id)
+
+	log.Debug("Пометка песни как объединенной", "target_id", targetID)
 
-	log.Debug("Получение куплетов песни", "id", id, "page", pagination.Page, "pageSize", pagination.PageSize)
+	query := `UPDATE songs SET deleted_at = $1, merged_into_id = $2 WHERE id = $3 AND deleted_at IS NULL`
 
-	song, err := r.GetSongByID(ctx, id)
+	result, err := r.conn(ctx).ExecContext(ctx, query, time.Now(), targetID, id)
 	if err != nil {
-		return nil, err
+		log.Error("Ошибка пометки песни как объединенной", "error", err)
+		return fmt.Errorf("ошибка пометки песни как объединенной: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Error("Ошибка получения количества затронутых строк", "error", err)
+		return fmt.Errorf("ошибка получения количества затронутых строк: %w", err)
+	}
+	if rowsAffected == 0 {
+		log.Info("Песня для слияния не найдена")
+		return model.NewNotFoundError(id)
+	}
+
+	log.Info("Песня помечена как объединенная", "target_id", targetID)
+	return nil
+}
+
+// GetSongVerses получает куплеты песни с пагинацией и общее количество куплетов
+func (r *SongRepository) GetSongVerses(ctx context.Context, id int64, pagination model.VersesPagination) ([]string, int, error) {
+	log := r.logger.WithFields(ctx, "id", id)
+
+	log.Debug("Получение куплетов песни", "page", pagination.Page, "pageSize", pagination.PageSize)
+
+	song, err := r.getSong(ctx, r.readConn(ctx), `SELECT `+songColumns+` FROM songs WHERE id = $1 AND deleted_at IS NULL`, id)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	if song == nil {
-		log.Info("Песня не найдена", "id", id)
-		return nil, fmt.Errorf("песня с id %d не найдена", id)
+		log.Info("Песня не найдена")
+		return nil, 0, model.NewNotFoundError(id)
+	}
+
+	r.recordAccess(ctx, id, model.AccessActionVerses)
+
+	verses := strings.Split(song.Text, model.VerseDelimiter)
+	start, end := verseBounds(len(verses), pagination)
+	if start >= end {
+		log.Info("Запрошенные куплеты выходят за пределы", "verses_count", len(verses), "start", start, "end", end)
+		return []string{}, len(verses), nil
 	}
 
-	verses := strings.Split(song.Text, "\n\n")
-	start := (pagination.Page - 1) * pagination.PageSize
-	end := start + pagination.PageSize
-	if start >= len(verses) {
-		log.Info("Пагинация выходит за пределы", "verses_count", len(verses), "start", start)
-		return []string{}, nil
+	selected := verses[start:end]
+	if pagination.Descending {
+		slices.Reverse(selected)
 	}
 
-	if end > len(verses) {
-		end = len(verses)
+	log.Info("Успешно получены куплеты песни", "verses_count", len(selected))
+	return selected, len(verses), nil
+}
+
+// verseBounds возвращает границы [start, end) выбранных куплетов в исходном порядке, ограниченные количеством куплетов.
+// При обратном порядке страницы отсчитываются с конца текста.
+func verseBounds(total int, pagination model.VersesPagination) (int, int) {
+	var start, end int
+	switch {
+	case pagination.HasRange():
+		start, end = 0, total
+		if pagination.From != nil {
+			start = *pagination.From - 1
+		}
+		if pagination.To != nil {
+			end = *pagination.To
+		}
+	case pagination.Descending:
+		end = total - pagination.Offset()
+		start = end - pagination.PageSize
+	default:
+		start = pagination.Offset()
+		end = start + pagination.PageSize
 	}
 
-	log.Info("Успешно получены куплеты песни", "verses_count", len(verses[start:end]))
-	return verses[start:end], nil
+	return max(start, 0), min(end, total)
 }
@@ -0,0 +1,883 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"reflect"
+	"song-library/internal/model"
+	"testing"
+	"time"
+)
+
+func TestCreateSong(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+
+	song := mustCreateSong(t, repo, newTestSong("Muse", "Hysteria"))
+	if song.ID == 0 {
+		t.Fatal("CreateSong() id = 0")
+	}
+	if _, err := uuid.Parse(song.PublicID); err != nil {
+		t.Errorf("PublicID = %q, не UUID: %v", song.PublicID, err)
+	}
+
+	got, err := repo.GetSongByID(ctx, song.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetSongByID() = %v, %v", got, err)
+	}
+	if got.PublicID != song.PublicID || got.Group != "Muse" || got.Song != "Hysteria" || got.Text != song.Text {
+		t.Errorf("GetSongByID() = %+v, want %+v", got, song)
+	}
+	if got.VerseCount != 2 || got.TextLength != len([]rune(song.Text)) {
+		t.Errorf("VerseCount, TextLength = %d, %d, want 2, %d", got.VerseCount, got.TextLength, len([]rune(song.Text)))
+	}
+	if got.ContentHash != song.ContentHash || got.EnrichmentStatus != model.EnrichmentStatusPending {
+		t.Errorf("ContentHash, EnrichmentStatus = %q, %q, want %q, %q",
+			got.ContentHash, got.EnrichmentStatus, song.ContentHash, model.EnrichmentStatusPending)
+	}
+	if got.FeaturedArtists == nil || len(got.FeaturedArtists) != 0 || got.ChartHistory == nil || len(got.ChartHistory) != 0 {
+		t.Errorf("FeaturedArtists, ChartHistory = %v, %v, want пустые списки", got.FeaturedArtists, got.ChartHistory)
+	}
+
+	other := mustCreateSong(t, repo, newTestSong("Muse", "Uprising"))
+	if other.PublicID == song.PublicID {
+		t.Errorf("gen_random_uuid() выдал одинаковые публичные идентификаторы %s", song.PublicID)
+	}
+}
+
+func TestCreateSongUniqueness(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	song := mustCreateSong(t, repo, newTestSong("Muse", "Hysteria"))
+
+	if _, err := repo.CreateSong(ctx, newTestSong("Muse", "Hysteria")); !errors.Is(err, model.ErrSongAlreadyExists) {
+		t.Fatalf("CreateSong() дубликата error = %v, want %v", err, model.ErrSongAlreadyExists)
+	}
+
+	// Уникальный индекс учитывает регистр
+	mustCreateSong(t, repo, newTestSong("muse", "hysteria"))
+
+	// Частичный индекс не учитывает удаленные песни: после удаления название можно занять снова
+	if err := repo.DeleteSong(ctx, song.ID); err != nil {
+		t.Fatalf("DeleteSong() error = %v", err)
+	}
+	again := mustCreateSong(t, repo, newTestSong("Muse", "Hysteria"))
+	if again.ID == song.ID {
+		t.Errorf("CreateSong() после удаления вернул id удаленной песни %d", song.ID)
+	}
+}
+
+func TestGetSongIDByPublicID(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	active := mustCreateSong(t, repo, newTestSong("Muse", "Hysteria"))
+	deleted := mustCreateSong(t, repo, newTestSong("Muse", "Uprising"))
+	if err := repo.DeleteSong(ctx, deleted.ID); err != nil {
+		t.Fatalf("DeleteSong() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		publicID string
+		wantID   int64
+	}{
+		{"активная песня", active.PublicID, active.ID},
+		{"песня в корзине", deleted.PublicID, deleted.ID},
+		{"неизвестный идентификатор", uuid.NewString(), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := repo.GetSongIDByPublicID(ctx, tt.publicID)
+			if err != nil {
+				t.Fatalf("GetSongIDByPublicID() error = %v", err)
+			}
+			if id != tt.wantID {
+				t.Errorf("GetSongIDByPublicID() = %d, want %d", id, tt.wantID)
+			}
+		})
+	}
+}
+
+// seedFilterSongs создает песни для проверки фильтров списка; идентификаторы 1–4 активны, 5 удалена
+func seedFilterSongs(t *testing.T, repo *SongRepository) {
+	t.Helper()
+
+	hysteria := newTestSong("Muse", "Hysteria")
+	hysteria.Text = "It's bugging me\n\nGrating me"
+	hysteria.Duration = intPtr(227)
+	hysteria.BPM = int16Ptr(94)
+	hysteria.FeaturedArtists = model.Artists{"Guest"}
+	hysteria.EnrichmentStatus = model.EnrichmentStatusOK
+	hysteria.Copyright = stringPtr("© 2003 Taste Media")
+	mustCreateSong(t, repo, hysteria)
+
+	uprising := newTestSong("Muse", "Uprising")
+	uprising.Text = "Paranoia is in bloom"
+	uprising.Duration = intPtr(305)
+	uprising.BPM = int16Ptr(128)
+	uprising.EnrichmentStatus = model.EnrichmentStatusFailed
+	mustCreateSong(t, repo, uprising)
+
+	ace := newTestSong("Motörhead", "Ace of Spades")
+	ace.Text = ""
+	ace.Link = ""
+	ace.Duration = intPtr(169)
+	ace.BPM = int16Ptr(160)
+	mustCreateSong(t, repo, ace)
+
+	rhapsody := newTestSong("Queen", "Bohemian Rhapsody")
+	rhapsody.Text = "Is this the real life?\n\nMy muse"
+	rhapsody.Duration = nil
+	mustCreateSong(t, repo, rhapsody)
+
+	deleted := mustCreateSong(t, repo, newTestSong("Muse", "Deleted"))
+	if err := repo.DeleteSong(context.Background(), deleted.ID); err != nil {
+		t.Fatalf("DeleteSong() error = %v", err)
+	}
+}
+
+func TestGetSongsFilters(t *testing.T) {
+	repo := newTestRepository(t, RepositoryConfig{})
+	seedFilterSongs(t, repo)
+
+	page := model.Pagination{Page: 1, PageSize: 10}
+	hour := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		filter  model.SongFilter
+		wantIDs []int64
+	}{
+		{"пустой фильтр", model.SongFilter{Pagination: page}, []int64{4, 3, 2, 1}},
+		{"группа без учета регистра", model.SongFilter{Group: "muse", Pagination: page}, []int64{2, 1}},
+		{"группа без диакритики", model.SongFilter{Group: "motorhead", Pagination: page}, []int64{3}},
+		{"название", model.SongFilter{SongName: "spades", Pagination: page}, []int64{3}},
+		{"список групп", model.SongFilter{Groups: []string{"Muse", "Queen"}, Pagination: page}, []int64{4, 2, 1}},
+		{"список названий", model.SongFilter{SongNames: []string{"Hysteria", "Unknown"}, Pagination: page}, []int64{1}},
+		{"текст ILIKE", model.SongFilter{Text: "BUGGING", Pagination: page}, []int64{1}},
+		{"быстрый поиск по релевантности", model.SongFilter{QuickSearch: "muse", Pagination: page}, []int64{2, 1, 4}},
+		{"длительность в диапазоне", model.SongFilter{DurationMin: intPtr(200), DurationMax: intPtr(300), Pagination: page}, []int64{1}},
+		{"минимальная длительность", model.SongFilter{DurationMin: intPtr(300), Pagination: page}, []int64{2}},
+		{"максимальная длительность", model.SongFilter{DurationMax: intPtr(200), Pagination: page}, []int64{3}},
+		{"темп в диапазоне", model.SongFilter{BPMMin: intPtr(100), BPMMax: intPtr(150), Pagination: page}, []int64{2}},
+		{"минимальный темп", model.SongFilter{BPMMin: intPtr(100), Pagination: page}, []int64{3, 2}},
+		{"максимальный темп", model.SongFilter{BPMMax: intPtr(100), Pagination: page}, []int64{1}},
+		{"приглашенный исполнитель", model.SongFilter{FeaturedArtist: "Guest", Pagination: page}, []int64{1}},
+		{"статус получения данных", model.SongFilter{EnrichmentStatus: model.EnrichmentStatusFailed, Pagination: page}, []int64{2}},
+		{"авторские права", model.SongFilter{CopyrightContains: "taste", Pagination: page}, []int64{1}},
+		{"созданные после", model.SongFilter{CreatedAtFrom: &hour, Pagination: page}, []int64{}},
+		{"созданные до", model.SongFilter{CreatedAtTo: &past, Pagination: page}, []int64{}},
+		{"созданные в периоде", model.SongFilter{CreatedAtFrom: &past, CreatedAtTo: &hour, Pagination: page}, []int64{4, 3, 2, 1}},
+		{"без текста", model.SongFilter{HasText: boolPtr(false), Pagination: page}, []int64{3}},
+		{"с текстом", model.SongFilter{HasText: boolPtr(true), Pagination: page}, []int64{4, 2, 1}},
+		{"без ссылки", model.SongFilter{HasLink: boolPtr(false), Pagination: page}, []int64{3}},
+		{"не заполнены текст и ссылка", model.SongFilter{MissingFields: []string{model.FieldText, model.FieldLink}, Pagination: page}, []int64{3}},
+		{"не заполнена длительность", model.SongFilter{MissingFields: []string{model.FieldDuration}, Pagination: page}, []int64{4}},
+		{"не заполнен темп", model.SongFilter{MissingFields: []string{model.FieldBPM}, Pagination: page}, []int64{4}},
+		{"вторая страница", model.SongFilter{Pagination: model.Pagination{Page: 2, PageSize: 2}}, []int64{2, 1}},
+		{"страница за пределами выборки", model.SongFilter{Pagination: model.Pagination{Page: 5, PageSize: 2}}, []int64{}},
+		{"фильтр без совпадений", model.SongFilter{Group: "nobody", Pagination: page}, []int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			songs, err := repo.GetSongs(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatalf("GetSongs() error = %v", err)
+			}
+			if got := songIDs(songs); !reflect.DeepEqual(got, tt.wantIDs) {
+				t.Errorf("GetSongs() ids = %v, want %v", got, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestGetSongsOptions(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	seedFilterSongs(t, repo)
+
+	songs, err := repo.GetSongs(ctx, model.SongFilter{OmitText: true, Pagination: model.Pagination{Page: 1, PageSize: 1}})
+	if err != nil || len(songs) != 1 {
+		t.Fatalf("GetSongs() без текста = %v, %v", songs, err)
+	}
+	if songs[0].Text != "" || songs[0].VerseCount != 2 {
+		t.Errorf("GetSongs() без текста Text, VerseCount = %q, %d, want пустой текст и 2 куплета", songs[0].Text, songs[0].VerseCount)
+	}
+
+	songs, err = repo.GetSongs(ctx, model.SongFilter{QuickSearch: "muse", Pagination: model.Pagination{Page: 1, PageSize: 10}})
+	if err != nil || len(songs) != 3 {
+		t.Fatalf("GetSongs() быстрый поиск = %v, %v", songs, err)
+	}
+	for i, want := range []float64{0.8, 0.8, 0.6} {
+		if songs[i].Relevance == nil || *songs[i].Relevance != want {
+			t.Errorf("songs[%d].Relevance = %v, want %v", i, songs[i].Relevance, want)
+		}
+	}
+
+	_, err = repo.GetSongs(ctx, model.SongFilter{MissingFields: []string{"cover_image_url"}, Pagination: model.Pagination{Page: 1, PageSize: 10}})
+	if err == nil {
+		t.Error("GetSongs() с неизвестным полем error = nil")
+	}
+}
+
+func TestPageWalkWithTies(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+
+	// Одинаковые группа, текст и дата выхода: значения сортировки равны, порядок задает только id
+	const total = 7
+	for i := range total {
+		mustCreateSong(t, repo, newTestSong("Muse", fmt.Sprintf("Song %d", i+1)))
+	}
+
+	tests := []struct {
+		name  string
+		fetch func(page model.Pagination) ([]*model.Song, error)
+	}{
+		{"быстрый поиск с равной релевантностью", func(page model.Pagination) ([]*model.Song, error) {
+			return repo.GetSongs(ctx, model.SongFilter{QuickSearch: "muse", Pagination: page})
+		}},
+		{"песни группы с равной датой выхода", func(page model.Pagination) ([]*model.Song, error) {
+			songs, _, err := repo.GetGroupSongs(ctx, "Muse", model.GroupSongsSortReleaseDate, page)
+			return songs, err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen := make(map[int64]bool)
+			for page := 1; ; page++ {
+				songs, err := tt.fetch(model.Pagination{Page: page, PageSize: 3})
+				if err != nil {
+					t.Fatalf("страница %d: error = %v", page, err)
+				}
+				if len(songs) == 0 {
+					break
+				}
+				for _, song := range songs {
+					if seen[song.ID] {
+						t.Errorf("песня %d повторяется на странице %d", song.ID, page)
+					}
+					seen[song.ID] = true
+				}
+			}
+			if len(seen) != total {
+				t.Errorf("обход страниц вернул %d песен, want %d", len(seen), total)
+			}
+		})
+	}
+}
+
+func TestGetSongsByIDs(t *testing.T) {
+	repo := newTestRepository(t, RepositoryConfig{})
+	seedFilterSongs(t, repo)
+
+	tests := []struct {
+		name    string
+		ids     []int64
+		wantIDs []int64
+	}{
+		{"активные, удаленная и отсутствующая", []int64{1, 3, 5, 999}, []int64{3, 1}},
+		{"пустой список", []int64{}, []int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			songs, err := repo.GetSongsByIDs(context.Background(), tt.ids)
+			if err != nil {
+				t.Fatalf("GetSongsByIDs() error = %v", err)
+			}
+			if got := songIDs(songs); !reflect.DeepEqual(got, tt.wantIDs) {
+				t.Errorf("GetSongsByIDs() ids = %v, want %v", got, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestGetSongSummaries(t *testing.T) {
+	repo := newTestRepository(t, RepositoryConfig{})
+	seedFilterSongs(t, repo)
+
+	songs, err := repo.GetSongSummaries(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("GetSongSummaries() error = %v", err)
+	}
+	if got := songIDs(songs); !reflect.DeepEqual(got, []int64{1, 2}) {
+		t.Errorf("GetSongSummaries() ids = %v, want [1 2]", got)
+	}
+	for _, song := range songs {
+		if song.Text != "" {
+			t.Errorf("GetSongSummaries() song %d Text = %q, want пустой", song.ID, song.Text)
+		}
+	}
+}
+
+func TestIterateSongs(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	seedFilterSongs(t, repo)
+
+	var all []int64
+	if err := repo.IterateSongs(ctx, func(song *model.Song) error {
+		all = append(all, song.ID)
+		return nil
+	}); err != nil {
+		t.Fatalf("IterateSongs() error = %v", err)
+	}
+	if !reflect.DeepEqual(all, []int64{1, 2, 3, 4}) {
+		t.Errorf("IterateSongs() ids = %v, want [1 2 3 4]", all)
+	}
+
+	errStop := errors.New("stop")
+	var seen int
+	err := repo.IterateSongs(ctx, func(*model.Song) error {
+		seen++
+		if seen == 2 {
+			return errStop
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) || seen != 2 {
+		t.Errorf("IterateSongs() с остановкой = %v после %d песен, want %v после 2", err, seen, errStop)
+	}
+
+	var group []string
+	if err = repo.IterateGroupSongs(ctx, "Muse", func(song *model.Song) error {
+		group = append(group, song.Song)
+		return nil
+	}); err != nil {
+		t.Fatalf("IterateGroupSongs() error = %v", err)
+	}
+	if !reflect.DeepEqual(group, []string{"Hysteria", "Uprising"}) {
+		t.Errorf("IterateGroupSongs() = %v, want [Hysteria Uprising]", group)
+	}
+}
+
+func TestGetSongByID(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	active := mustCreateSong(t, repo, newTestSong("Muse", "Hysteria"))
+	deleted := mustCreateSong(t, repo, newTestSong("Muse", "Uprising"))
+	if err := repo.DeleteSong(ctx, deleted.ID); err != nil {
+		t.Fatalf("DeleteSong() error = %v", err)
+	}
+
+	getters := map[string]func(context.Context, int64) (*model.Song, error){
+		"GetSongByID":        repo.GetSongByID,
+		"GetSongByIDPrimary": repo.GetSongByIDPrimary,
+	}
+	for name, get := range getters {
+		t.Run(name, func(t *testing.T) {
+			if song, err := get(ctx, active.ID); err != nil || song == nil || song.ID != active.ID {
+				t.Errorf("%s(%d) = %v, %v", name, active.ID, song, err)
+			}
+			if song, err := get(ctx, deleted.ID); err != nil || song != nil {
+				t.Errorf("%s(удаленная) = %v, %v, want nil, nil", name, song, err)
+			}
+			if song, err := get(ctx, 999); err != nil || song != nil {
+				t.Errorf("%s(999) = %v, %v, want nil, nil", name, song, err)
+			}
+		})
+	}
+
+	// Каждое успешное чтение записывается в журнал обращений
+	entries, err := repo.GetAccessLog(ctx, active.ID, nil, nil)
+	if err != nil {
+		t.Fatalf("GetAccessLog() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].Action != model.AccessActionView {
+		t.Errorf("GetAccessLog() = %+v, want 2 просмотра", entries)
+	}
+}
+
+func TestGetSongByIDForUpdate(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	song := mustCreateSong(t, repo, newTestSong("Muse", "Hysteria"))
+
+	err := repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		locked, err := repo.GetSongByIDForUpdate(ctx, song.ID)
+		if err != nil || locked == nil {
+			t.Fatalf("GetSongByIDForUpdate() = %v, %v", locked, err)
+		}
+
+		// Строка заблокирована до конца транзакции: другое соединение не может ее заблокировать
+		_, err = testDB.ExecContext(ctx, `SELECT id FROM songs WHERE id = $1 FOR UPDATE NOWAIT`, song.ID)
+		var pqErr *pq.Error
+		if !errors.As(err, &pqErr) || pqErr.Code != "55P03" {
+			t.Errorf("блокировка другим соединением error = %v, want lock_not_available", err)
+		}
+
+		missing, err := repo.GetSongByIDForUpdate(ctx, 999)
+		if err != nil || missing != nil {
+			t.Errorf("GetSongByIDForUpdate(999) = %v, %v, want nil, nil", missing, err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithinTransaction() error = %v", err)
+	}
+}
+
+func TestWithinTransaction(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	errRollback := errors.New("rollback")
+
+	var id int64
+	err := repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Вложенный вызов использует ту же транзакцию
+		return repo.WithinTransaction(ctx, func(ctx context.Context) error {
+			var err error
+			if id, err = repo.CreateSong(ctx, newTestSong("Muse", "Hysteria")); err != nil {
+				return err
+			}
+			return errRollback
+		})
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("WithinTransaction() error = %v, want %v", err, errRollback)
+	}
+	if song, err := repo.GetSongByID(ctx, id); err != nil || song != nil {
+		t.Errorf("песня после отката = %v, %v, want nil, nil", song, err)
+	}
+
+	err = repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		id, err = repo.CreateSong(ctx, newTestSong("Muse", "Hysteria"))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("WithinTransaction() error = %v", err)
+	}
+	if song, err := repo.GetSongByID(ctx, id); err != nil || song == nil {
+		t.Errorf("песня после фиксации = %v, %v", song, err)
+	}
+}
+
+func TestUpdateSong(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	song := mustCreateSong(t, repo, newTestSong("Muse", "Hysteria"))
+	mustCreateSong(t, repo, newTestSong("Muse", "Uprising"))
+	deleted := mustCreateSong(t, repo, newTestSong("Muse", "Deleted"))
+	if err := repo.DeleteSong(ctx, deleted.ID); err != nil {
+		t.Fatalf("DeleteSong() error = %v", err)
+	}
+
+	song.Group = "Mötley Crüe"
+	song.Text = "One\n\nTwo\n\nThree"
+	if err := repo.UpdateSong(ctx, song); err != nil {
+		t.Fatalf("UpdateSong() error = %v", err)
+	}
+	got, err := repo.GetSongByID(ctx, song.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetSongByID() = %v, %v", got, err)
+	}
+	if got.Group != "Mötley Crüe" || got.VerseCount != 3 || got.ContentHash != song.ContentHash {
+		t.Errorf("после UpdateSong() = %+v", got)
+	}
+	found, err := repo.GetSongs(ctx, model.SongFilter{Group: "motley crue", Pagination: model.Pagination{Page: 1, PageSize: 10}})
+	if err != nil || !reflect.DeepEqual(songIDs(found), []int64{song.ID}) {
+		t.Errorf("поиск по нормализованной группе после UpdateSong() = %v, %v", songIDs(found), err)
+	}
+
+	tests := []struct {
+		name    string
+		song    *model.Song
+		wantErr func(error) bool
+	}{
+		{"отсутствующая песня", &model.Song{ID: 999, Group: "Muse", Song: "Missing"}, isNotFound(999)},
+		{"удаленная песня", &model.Song{ID: deleted.ID, Group: "Muse", Song: "Deleted"}, isNotFound(deleted.ID)},
+		{"название занято", &model.Song{ID: song.ID, Group: "Muse", Song: "Uprising"}, isUniqueViolation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := repo.UpdateSong(ctx, tt.song); !tt.wantErr(err) {
+				t.Errorf("UpdateSong() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestUpdateSongFields(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+
+	song := newTestSong("Muse", "Hysteria")
+	fetched := model.FieldSource{Provider: model.ProviderExternalAPI, FetchedAt: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)}
+	song.Provenance = model.Provenance{
+		model.FieldDuration:        fetched,
+		model.FieldBPM:             fetched,
+		model.FieldCopyright:       fetched,
+		model.FieldFeaturedArtists: fetched,
+		model.FieldText:            fetched,
+	}
+	song = mustCreateSong(t, repo, song)
+
+	tests := []struct {
+		name   string
+		field  string
+		update func(id int64) error
+		check  func(song *model.Song) bool
+	}{
+		{"длительность", model.FieldDuration,
+			func(id int64) error { return repo.UpdateSongDuration(ctx, id, intPtr(300)) },
+			func(s *model.Song) bool { return s.Duration != nil && *s.Duration == 300 }},
+		{"темп", model.FieldBPM,
+			func(id int64) error { return repo.UpdateSongBPM(ctx, id, int16Ptr(120)) },
+			func(s *model.Song) bool { return s.BPM != nil && *s.BPM == 120 }},
+		{"авторские права", model.FieldCopyright,
+			func(id int64) error { return repo.UpdateSongCopyright(ctx, id, stringPtr("© 2003")) },
+			func(s *model.Song) bool { return s.Copyright != nil && *s.Copyright == "© 2003" }},
+		{"приглашенные исполнители", model.FieldFeaturedArtists,
+			func(id int64) error { return repo.UpdateFeaturedArtists(ctx, id, model.Artists{"Guest 1", "Guest 2"}) },
+			func(s *model.Song) bool {
+				return reflect.DeepEqual(s.FeaturedArtists, model.Artists{"Guest 1", "Guest 2"})
+			}},
+		{"очистка длительности", model.FieldDuration,
+			func(id int64) error { return repo.UpdateSongDuration(ctx, id, nil) },
+			func(s *model.Song) bool { return s.Duration == nil }},
+		{"очистка исполнителей", model.FieldFeaturedArtists,
+			func(id int64) error { return repo.UpdateFeaturedArtists(ctx, id, nil) },
+			func(s *model.Song) bool { return len(s.FeaturedArtists) == 0 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.update(song.ID); err != nil {
+				t.Fatalf("обновление error = %v", err)
+			}
+			got, err := repo.GetSongByID(ctx, song.ID)
+			if err != nil || got == nil {
+				t.Fatalf("GetSongByID() = %v, %v", got, err)
+			}
+			if !tt.check(got) {
+				t.Errorf("песня после обновления = %+v", got)
+			}
+			// Значение, заданное вручную, больше не считается полученным от поставщика
+			if _, ok := got.Provenance[tt.field]; ok {
+				t.Errorf("Provenance содержит %s после ручного обновления", tt.field)
+			}
+			if _, ok := got.Provenance[model.FieldText]; !ok {
+				t.Errorf("Provenance потерял %s: %v", model.FieldText, got.Provenance)
+			}
+			if err = tt.update(999); !isNotFound(999)(err) {
+				t.Errorf("обновление отсутствующей песни error = %v", err)
+			}
+		})
+	}
+
+	if err := repo.UpdateSongBPM(ctx, song.ID, int16Ptr(10)); err == nil {
+		t.Error("UpdateSongBPM(10) error = nil, want нарушение CHECK")
+	}
+}
+
+func TestGetTotalDuration(t *testing.T) {
+	repo := newTestRepository(t, RepositoryConfig{})
+	seedFilterSongs(t, repo)
+
+	tests := []struct {
+		name  string
+		group string
+		want  int64
+	}{
+		{"группа", "muse", 227 + 305},
+		{"все группы", "", 227 + 305 + 169},
+		{"неизвестная группа", "nobody", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total, err := repo.GetTotalDuration(context.Background(), tt.group)
+			if err != nil {
+				t.Fatalf("GetTotalDuration() error = %v", err)
+			}
+			if total != tt.want {
+				t.Errorf("GetTotalDuration() = %d, want %d", total, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeleteSong(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	song := mustCreateSong(t, repo, newTestSong("Muse", "Hysteria"))
+
+	if err := repo.DeleteSong(ctx, song.ID); err != nil {
+		t.Fatalf("DeleteSong() error = %v", err)
+	}
+	if got, err := repo.GetSongByID(ctx, song.ID); err != nil || got != nil {
+		t.Errorf("GetSongByID() после удаления = %v, %v, want nil, nil", got, err)
+	}
+	if err := repo.DeleteSong(ctx, song.ID); !isNotFound(song.ID)(err) {
+		t.Errorf("повторный DeleteSong() error = %v", err)
+	}
+	if err := repo.DeleteSong(ctx, 999); !isNotFound(999)(err) {
+		t.Errorf("DeleteSong(999) error = %v", err)
+	}
+}
+
+func TestMarkSongMerged(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	source := mustCreateSong(t, repo, newTestSong("Muse", "Hysteria"))
+	target := mustCreateSong(t, repo, newTestSong("Muse", "Uprising"))
+
+	if err := repo.MarkSongMerged(ctx, source.ID, target.ID); err != nil {
+		t.Fatalf("MarkSongMerged() error = %v", err)
+	}
+
+	var mergedInto *int64
+	if err := testDB.Get(&mergedInto, `SELECT merged_into_id FROM songs WHERE id = $1 AND deleted_at IS NOT NULL`, source.ID); err != nil {
+		t.Fatalf("ошибка чтения merged_into_id: %v", err)
+	}
+	if mergedInto == nil || *mergedInto != target.ID {
+		t.Errorf("merged_into_id = %v, want %d", mergedInto, target.ID)
+	}
+	if err := repo.MarkSongMerged(ctx, source.ID, target.ID); !isNotFound(source.ID)(err) {
+		t.Errorf("повторный MarkSongMerged() error = %v", err)
+	}
+}
+
+func TestGetSongVerses(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	song := newTestSong("Muse", "Hysteria")
+	song.Text = "first\n\nsecond\n\nthird"
+	song = mustCreateSong(t, repo, song)
+
+	tests := []struct {
+		name       string
+		pagination model.VersesPagination
+		want       []string
+	}{
+		{"первая страница", model.VersesPagination{Pagination: model.Pagination{Page: 1, PageSize: 2}}, []string{"first", "second"}},
+		{"последняя неполная страница", model.VersesPagination{Pagination: model.Pagination{Page: 2, PageSize: 2}}, []string{"third"}},
+		{"страница за пределами текста", model.VersesPagination{Pagination: model.Pagination{Page: 3, PageSize: 2}}, []string{}},
+		{"обратный порядок", model.VersesPagination{Pagination: model.Pagination{Page: 1, PageSize: 2}, Descending: true}, []string{"third", "second"}},
+		{"диапазон", model.VersesPagination{From: intPtr(2), To: intPtr(3)}, []string{"second", "third"}},
+		{"диапазон больше текста", model.VersesPagination{From: intPtr(3), To: intPtr(10)}, []string{"third"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verses, total, err := repo.GetSongVerses(ctx, song.ID, tt.pagination)
+			if err != nil {
+				t.Fatalf("GetSongVerses() error = %v", err)
+			}
+			if !reflect.DeepEqual(verses, tt.want) || total != 3 {
+				t.Errorf("GetSongVerses() = %q, %d, want %q, 3", verses, total, tt.want)
+			}
+		})
+	}
+
+	if _, _, err := repo.GetSongVerses(ctx, 999, model.VersesPagination{}); !isNotFound(999)(err) {
+		t.Errorf("GetSongVerses(999) error = %v", err)
+	}
+	entries, err := repo.GetAccessLog(ctx, song.ID, nil, nil)
+	if err != nil || len(entries) != len(tests) || entries[0].Action != model.AccessActionVerses {
+		t.Errorf("GetAccessLog() = %+v, %v, want %d обращений к куплетам", entries, err, len(tests))
+	}
+}
+
+func TestPreparedAndCommentedQueries(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       RepositoryConfig
+		prepare   bool
+		wantStmts bool
+	}{
+		{"подготовленные запросы", RepositoryConfig{}, true, true},
+		{"комментарии отключают подготовку", RepositoryConfig{QueryComments: true}, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), "requestID", "req-1")
+			repo := newTestRepository(t, tt.cfg)
+			if err := repo.Prepare(ctx); err != nil {
+				t.Fatalf("Prepare() error = %v", err)
+			}
+			defer repo.Close()
+			if (repo.stmts != nil) != tt.wantStmts {
+				t.Errorf("подготовленные выражения = %v, want %v", repo.stmts != nil, tt.wantStmts)
+			}
+
+			// Подготовленные выражения работают и вне транзакции, и внутри нее
+			song := mustCreateSong(t, repo, newTestSong("Muse", "Hysteria"))
+			err := repo.WithinTransaction(ctx, func(ctx context.Context) error {
+				locked, err := repo.GetSongByIDForUpdate(ctx, song.ID)
+				if err != nil {
+					return err
+				}
+				locked.Text = "updated"
+				return repo.UpdateSong(ctx, locked)
+			})
+			if err != nil {
+				t.Fatalf("WithinTransaction() error = %v", err)
+			}
+			got, err := repo.GetSongByID(ctx, song.ID)
+			if err != nil || got == nil || got.Text != "updated" {
+				t.Fatalf("GetSongByID() = %v, %v", got, err)
+			}
+			if err = repo.DeleteSong(ctx, song.ID); err != nil {
+				t.Errorf("DeleteSong() error = %v", err)
+			}
+		})
+	}
+}
+
+// isNotFound возвращает проверку ошибки отсутствия песни id
+func isNotFound(id int64) func(error) bool {
+	return func(err error) bool {
+		var notFound *model.NotFoundError
+		return errors.As(err, &notFound) && notFound.ID == id && errors.Is(err, model.ErrSongNotFound)
+	}
+}
+
+// isUniqueViolation сообщает, является ли err нарушением ограничения уникальности
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolationCode
+}
+
+func intPtr(v int) *int { return &v }
+
+func int16Ptr(v int16) *int16 { return &v }
+
+func stringPtr(v string) *string { return &v }
+
+func boolPtr(v bool) *bool { return &v }
+
+// benchmarkSongs количество песен в библиотеке для бенчмарков
+const benchmarkSongs = 10000
+
+// newBenchmarkRepository создает репозиторий поверх библиотеки из benchmarkSongs песен 100 групп.
+// Библиотека заполняется через COPY, только если в таблице другое количество песен
+func newBenchmarkRepository(b *testing.B) *SongRepository {
+	b.Helper()
+
+	var count int
+	if err := testDB.Get(&count, `SELECT count(*) FROM songs`); err != nil {
+		b.Fatalf("ошибка подсчета песен: %v", err)
+	}
+	if count == benchmarkSongs {
+		return NewSongRepository(testDB, testDB, RepositoryConfig{}, testLogger)
+	}
+
+	repo := newTestRepository(b, RepositoryConfig{})
+	songs := make([]*model.Song, 0, benchmarkSongs)
+	for i := range benchmarkSongs {
+		song := newTestSong(fmt.Sprintf("Group %03d", i%100), fmt.Sprintf("Song %d", i))
+		song.Text = fmt.Sprintf("Verse %d\nfirst line\n\nChorus %d\nsecond line", i, i%50)
+		songs = append(songs, song)
+	}
+	if _, err := repo.BulkInsertViaCopy(context.Background(), songs); err != nil {
+		b.Fatalf("BulkInsertViaCopy() error = %v", err)
+	}
+	mustExec(b, `ANALYZE songs`)
+	return repo
+}
+
+func BenchmarkGetSongs(b *testing.B) {
+	repo := newBenchmarkRepository(b)
+	page := model.Pagination{Page: 1, PageSize: 20}
+
+	benchmarks := []struct {
+		name   string
+		filter model.SongFilter
+	}{
+		{"без фильтра", model.SongFilter{Pagination: page}},
+		{"группа", model.SongFilter{Group: "group 042", Pagination: page}},
+		{"группа и название", model.SongFilter{Group: "group 042", SongName: "song 1", Pagination: page}},
+		{"текст", model.SongFilter{Text: "chorus 7\n", Pagination: page}},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			ctx := context.Background()
+			var songs int
+			b.ReportAllocs()
+			b.ResetTimer()
+			for range b.N {
+				result, err := repo.GetSongs(ctx, bm.filter)
+				if err != nil {
+					b.Fatalf("GetSongs() error = %v", err)
+				}
+				songs = len(result)
+			}
+			b.ReportMetric(float64(songs), "songs/op")
+		})
+	}
+}
+
+func BenchmarkGetSongByID(b *testing.B) {
+	repo := newBenchmarkRepository(b)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		id := int64(i%benchmarkSongs + 1)
+		song, err := repo.GetSongByID(ctx, id)
+		if err != nil || song == nil {
+			b.Fatalf("GetSongByID(%d) = %v, %v", id, song, err)
+		}
+	}
+}
+
+func BenchmarkCreateSong(b *testing.B) {
+	repo := newTestRepository(b, RepositoryConfig{})
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		if _, err := repo.CreateSong(ctx, newTestSong("Muse", fmt.Sprintf("Song %d", i))); err != nil {
+			b.Fatalf("CreateSong() error = %v", err)
+		}
+	}
+}
+
+// bulkInsertBenchmarkSize количество песен в одной массовой вставке бенчмарков
+const bulkInsertBenchmarkSize = 1000
+
+func BenchmarkBulkInsertViaCopy(b *testing.B) {
+	benchmarkBulkInsert(b, func(repo *SongRepository, songs []*model.Song) (int64, error) {
+		return repo.BulkInsertViaCopy(context.Background(), songs)
+	})
+}
+
+func BenchmarkBulkInsertViaValues(b *testing.B) {
+	benchmarkBulkInsert(b, func(repo *SongRepository, songs []*model.Song) (int64, error) {
+		return repo.bulkInsertViaValues(context.Background(), songs)
+	})
+}
+
+// benchmarkBulkInsert измеряет вставку bulkInsertBenchmarkSize песен способом insert
+func benchmarkBulkInsert(b *testing.B, insert func(repo *SongRepository, songs []*model.Song) (int64, error)) {
+	repo := newTestRepository(b, RepositoryConfig{})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		b.StopTimer()
+		songs := newTestSongs(fmt.Sprintf("Group %d", i), bulkInsertBenchmarkSize)
+		b.StartTimer()
+
+		if _, err := insert(repo, songs); err != nil {
+			b.Fatalf("массовая вставка error = %v", err)
+		}
+	}
+	b.ReportMetric(float64(bulkInsertBenchmarkSize), "songs/op")
+}
@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"song-library/internal/model"
+	"strings"
+	"time"
+)
+
+// GetSongGrowth получает количество песен, созданных в каждом интервале [from, to).
+// Интервалы без новых песен в результат не попадают.
+func (r *SongRepository) GetSongGrowth(ctx context.Context, interval string, from, to time.Time) ([]model.GrowthBucket, error) {
+	log := r.logger.WithContext(ctx)
+
+	log.Debug("Получение динамики роста библиотеки", "interval", interval, "from", from, "to", to)
+
+	query := `SELECT date_trunc($1, created_at) AS bucket, COUNT(*) AS count
+		FROM songs
+		WHERE created_at >= $2 AND created_at < $3 AND deleted_at IS NULL
+		GROUP BY bucket
+		ORDER BY bucket`
+
+	var rows []struct {
+		Bucket time.Time `db:"bucket"`
+		Count  int64     `db:"count"`
+	}
+	if err := sqlx.SelectContext(ctx, r.readConn(ctx), &rows, query, interval, from, to); err != nil {
+		log.Error("Ошибка получения динамики роста библиотеки", "error", err)
+		return nil, fmt.Errorf("ошибка получения динамики роста библиотеки: %w", err)
+	}
+
+	buckets := make([]model.GrowthBucket, 0, len(rows))
+	for _, row := range rows {
+		buckets = append(buckets, model.GrowthBucket{Bucket: row.Bucket.Format(time.DateOnly), Count: row.Count})
+	}
+
+	log.Info("Динамика роста библиотеки успешно получена", "buckets", len(buckets))
+	return buckets, nil
+}
+
+// tempoBucketCase возвращает выражение CASE, относящее темп песни к интервалу гистограммы
+func tempoBucketCase() string {
+	var b strings.Builder
+	b.WriteString("CASE")
+	ranges := model.TempoRanges()
+	for i, r := range ranges {
+		last := r.High - 1
+		if i == len(ranges)-1 {
+			last = r.High
+		}
+		fmt.Fprintf(&b, " WHEN bpm BETWEEN %d AND %d THEN '%s'", r.Low, last, r.Label())
+	}
+	b.WriteString(" END")
+	return b.String()
+}
+
+// GetTempoDistribution получает количество песен в каждом интервале темпа.
+// Песни без темпа не учитываются, пустые интервалы в результат не попадают.
+func (r *SongRepository) GetTempoDistribution(ctx context.Context) ([]model.TempoBucket, error) {
+	log := r.logger.WithContext(ctx)
+
+	log.Debug("Получение распределения песен по темпу")
+
+	query := `SELECT ` + tempoBucketCase() + ` AS range, COUNT(*) AS count
+		FROM songs
+		WHERE bpm IS NOT NULL AND deleted_at IS NULL
+		GROUP BY 1`
+
+	buckets := []model.TempoBucket{}
+	if err := sqlx.SelectContext(ctx, r.readConn(ctx), &buckets, query); err != nil {
+		log.Error("Ошибка получения распределения песен по темпу", "error", err)
+		return nil, fmt.Errorf("ошибка получения распределения песен по темпу: %w", err)
+	}
+
+	log.Info("Распределение песен по темпу успешно получено", "buckets", len(buckets))
+	return buckets, nil
+}
+
+// GetTopGroupsBySongs получает группы с наибольшим количеством песен
+func (r *SongRepository) GetTopGroupsBySongs(ctx context.Context, limit int) ([]model.GroupStat, error) {
+	query := `SELECT group_name, COUNT(*) AS count,
+			EXISTS (SELECT 1 FROM groups g WHERE g.name = group_name) AS has_info
+		FROM songs
+		WHERE deleted_at IS NULL
+		GROUP BY group_name
+		ORDER BY count DESC, group_name
+		LIMIT $1`
+
+	return r.getTopGroups(ctx, query, limit)
+}
+
+// GetTopGroupsByPlays получает группы с наибольшим количеством обращений к их песням
+func (r *SongRepository) GetTopGroupsByPlays(ctx context.Context, limit int) ([]model.GroupStat, error) {
+	query := `SELECT s.group_name, COUNT(*) AS count,
+			EXISTS (SELECT 1 FROM groups g WHERE g.name = s.group_name) AS has_info
+		FROM song_access_log a
+		JOIN songs s ON s.id = a.song_id
+		WHERE s.deleted_at IS NULL
+		GROUP BY s.group_name
+		ORDER BY count DESC, s.group_name
+		LIMIT $1`
+
+	return r.getTopGroups(ctx, query, limit)
+}
+
+// GetEnrichmentStatusCounts получает количество активных песен по статусам получения данных от поставщика.
+// Статусы без песен в результат не попадают.
+func (r *SongRepository) GetEnrichmentStatusCounts(ctx context.Context) ([]model.EnrichmentStatusCount, error) {
+	log := r.logger.WithContext(ctx)
+
+	log.Debug("Получение количества песен по статусам получения данных")
+
+	query := `SELECT enrichment_status, COUNT(*) AS count
+		FROM songs
+		WHERE deleted_at IS NULL
+		GROUP BY enrichment_status`
+
+	counts := []model.EnrichmentStatusCount{}
+	if err := sqlx.SelectContext(ctx, r.readConn(ctx), &counts, query); err != nil {
+		log.Error("Ошибка получения количества песен по статусам получения данных", "error", err)
+		return nil, fmt.Errorf("ошибка получения количества песен по статусам получения данных: %w", err)
+	}
+
+	log.Info("Количество песен по статусам получения данных успешно получено", "count", len(counts))
+	return counts, nil
+}
+
+// getTopGroups выполняет запрос рейтинга групп
+func (r *SongRepository) getTopGroups(ctx context.Context, query string, limit int) ([]model.GroupStat, error) {
+	log := r.logger.WithContext(ctx)
+
+	log.Debug("Получение рейтинга групп", "limit", limit)
+
+	groups := []model.GroupStat{}
+	if err := sqlx.SelectContext(ctx, r.readConn(ctx), &groups, query, limit); err != nil {
+		log.Error("Ошибка получения рейтинга групп", "error", err)
+		return nil, fmt.Errorf("ошибка получения рейтинга групп: %w", err)
+	}
+
+	log.Info("Рейтинг групп успешно получен", "count", len(groups))
+	return groups, nil
+}
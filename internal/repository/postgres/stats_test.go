@@ -0,0 +1,182 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"reflect"
+	"song-library/internal/model"
+	"testing"
+	"time"
+)
+
+func TestGetSongGrowth(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	created := map[string]string{
+		"Hysteria":  "2024-01-01 10:00",
+		"Uprising":  "2024-01-01 23:59",
+		"Starlight": "2024-01-03 08:00",
+		"Madness":   "2024-02-10 12:00",
+		"Deleted":   "2024-01-03 09:00",
+	}
+	for name, createdAt := range created {
+		song := mustCreateSong(t, repo, newTestSong("Muse", name))
+		mustExec(t, `UPDATE songs SET created_at = $1 WHERE id = $2`, createdAt, song.ID)
+		if name == "Deleted" {
+			if err := repo.DeleteSong(ctx, song.ID); err != nil {
+				t.Fatalf("DeleteSong() error = %v", err)
+			}
+		}
+	}
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		interval string
+		to       time.Time
+		want     []model.GrowthBucket
+		wantErr  bool
+	}{
+		{"по дням", "day", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			[]model.GrowthBucket{{Bucket: "2024-01-01", Count: 2}, {Bucket: "2024-01-03", Count: 1}, {Bucket: "2024-02-10", Count: 1}}, false},
+		{"по месяцам", "month", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			[]model.GrowthBucket{{Bucket: "2024-01-01", Count: 3}, {Bucket: "2024-02-01", Count: 1}}, false},
+		{"граница to не включается", "week", time.Date(2024, 2, 10, 12, 0, 0, 0, time.UTC),
+			[]model.GrowthBucket{{Bucket: "2024-01-01", Count: 3}}, false},
+		{"пустой период", "day", from, []model.GrowthBucket{}, false},
+		{"неизвестный интервал", "fortnight", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buckets, err := repo.GetSongGrowth(ctx, tt.interval, from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetSongGrowth() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(buckets, tt.want) {
+				t.Errorf("GetSongGrowth() = %v, want %v", buckets, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetTempoDistribution(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+
+	distribution, err := repo.GetTempoDistribution(ctx)
+	if err != nil || len(distribution) != 0 {
+		t.Fatalf("GetTempoDistribution() пустой библиотеки = %v, %v", distribution, err)
+	}
+
+	// Границы интервалов: верхняя граница относится к следующему интервалу, кроме последнего
+	for i, bpm := range []*int16{int16Ptr(model.MinBPM), int16Ptr(39), int16Ptr(40), int16Ptr(128), int16Ptr(model.MaxBPM), nil} {
+		song := newTestSong("Muse", "Song "+string(rune('A'+i)))
+		song.BPM = bpm
+		mustCreateSong(t, repo, song)
+	}
+	deleted := newTestSong("Muse", "Deleted")
+	deleted.BPM = int16Ptr(128)
+	mustCreateSong(t, repo, deleted)
+	if err = repo.DeleteSong(ctx, deleted.ID); err != nil {
+		t.Fatalf("DeleteSong() error = %v", err)
+	}
+
+	distribution, err = repo.GetTempoDistribution(ctx)
+	if err != nil {
+		t.Fatalf("GetTempoDistribution() error = %v", err)
+	}
+	got := make(map[string]int64, len(distribution))
+	for _, bucket := range distribution {
+		got[bucket.Range] = bucket.Count
+	}
+	ranges := model.TempoRanges()
+	want := map[string]int64{
+		ranges[0].Label():             2,
+		ranges[1].Label():             1,
+		ranges[5].Label():             1,
+		ranges[len(ranges)-1].Label(): 1,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetTempoDistribution() = %v, want %v", got, want)
+	}
+}
+
+// seedTopGroups создает песни трех групп, сведения о группе Queen и обращения к песням
+func seedTopGroups(t *testing.T, repo *SongRepository) {
+	t.Helper()
+
+	ctx := context.Background()
+	for _, s := range [][2]string{
+		{"Muse", "Hysteria"}, {"Muse", "Uprising"}, {"Muse", "Starlight"},
+		{"Queen", "Innuendo"}, {"Queen", "Bohemian Rhapsody"},
+		{"ABBA", "Waterloo"}, {"ABBA", "Deleted 1"}, {"ABBA", "Deleted 2"},
+	} {
+		mustCreateSong(t, repo, newTestSong(s[0], s[1]))
+	}
+	for _, id := range []int64{7, 8} {
+		if err := repo.DeleteSong(ctx, id); err != nil {
+			t.Fatalf("DeleteSong() error = %v", err)
+		}
+	}
+	if _, _, err := repo.UpsertGroupInfo(ctx, &model.GroupInfo{Name: "Queen", Links: []string{}}); err != nil {
+		t.Fatalf("UpsertGroupInfo() error = %v", err)
+	}
+	// Обращения к удаленным песням не учитываются
+	for _, id := range []int64{4, 4, 4, 5, 1, 1, 6, 7, 7, 7, 7} {
+		repo.RecordAccess(ctx, id, model.AccessActionView)
+	}
+}
+
+func TestGetTopGroups(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	seedTopGroups(t, repo)
+
+	tests := []struct {
+		name  string
+		query func(limit int) ([]model.GroupStat, error)
+		limit int
+		want  []model.GroupStat
+	}{
+		{"по количеству песен", func(limit int) ([]model.GroupStat, error) { return repo.GetTopGroupsBySongs(ctx, limit) }, 10,
+			[]model.GroupStat{{Group: "Muse", Count: 3}, {Group: "Queen", Count: 2, HasInfo: true}, {Group: "ABBA", Count: 1}}},
+		{"по количеству песен с ограничением", func(limit int) ([]model.GroupStat, error) { return repo.GetTopGroupsBySongs(ctx, limit) }, 1,
+			[]model.GroupStat{{Group: "Muse", Count: 3}}},
+		{"по обращениям", func(limit int) ([]model.GroupStat, error) { return repo.GetTopGroupsByPlays(ctx, limit) }, 10,
+			[]model.GroupStat{{Group: "Queen", Count: 4, HasInfo: true}, {Group: "Muse", Count: 2}, {Group: "ABBA", Count: 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups, err := tt.query(tt.limit)
+			if err != nil {
+				t.Fatalf("рейтинг групп error = %v", err)
+			}
+			if !reflect.DeepEqual(groups, tt.want) {
+				t.Errorf("рейтинг групп = %+v, want %+v", groups, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetEnrichmentStatusCounts(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, RepositoryConfig{})
+	seedFilterSongs(t, repo)
+
+	counts, err := repo.GetEnrichmentStatusCounts(ctx)
+	if err != nil {
+		t.Fatalf("GetEnrichmentStatusCounts() error = %v", err)
+	}
+	got := make(map[string]int64, len(counts))
+	for _, c := range counts {
+		got[c.Status] = c.Count
+	}
+	// Удаленная песня в статусе pending не учитывается
+	want := map[string]int64{model.EnrichmentStatusOK: 1, model.EnrichmentStatusFailed: 1, model.EnrichmentStatusPending: 2}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetEnrichmentStatusCounts() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PurgeDeletedSongs окончательно удаляет не больше limit песен, помеченных удаленными раньше cutoff, и возвращает их количество.
+// Журнал обращений и закладки удаляются каскадно; события песен удаляются, если keepHistory равен false.
+// Каждый вызов выполняется одним запросом, поэтому блокировки держатся только на время удаления пачки.
+func (r *SongRepository) PurgeDeletedSongs(ctx context.Context, cutoff time.Time, limit int, keepHistory bool) (int64, error) {
+	log := r.logger.WithContext(ctx)
+
+	log.Debug("Удаление пачки песен из корзины", "cutoff", cutoff, "limit", limit, "keep_history", keepHistory)
+
+	query := `WITH purged AS (
+			DELETE FROM songs WHERE id IN (
+				SELECT id FROM songs WHERE deleted_at IS NOT NULL AND deleted_at < $1
+				ORDER BY deleted_at LIMIT $2 FOR UPDATE SKIP LOCKED)
+			RETURNING id),
+		history AS (
+			DELETE FROM song_events WHERE NOT $3 AND song_id IN (SELECT id FROM purged))
+		SELECT count(*) FROM purged`
+
+	var purged int64
+	if err := r.conn(ctx).QueryRowxContext(ctx, query, cutoff, limit, keepHistory).Scan(&purged); err != nil {
+		log.Error("Ошибка удаления песен из корзины", "error", err)
+		return 0, fmt.Errorf("ошибка удаления песен из корзины: %w", err)
+	}
+
+	log.Debug("Пачка песен удалена из корзины", "count", purged)
+	return purged, nil
+}
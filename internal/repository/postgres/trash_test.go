@@ -0,0 +1,73 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPurgeDeletedSongs(t *testing.T) {
+	tests := []struct {
+		name        string
+		limit       int
+		keepHistory bool
+		wantPurged  int64
+		wantEvents  int
+	}{
+		{"все устаревшие песни без истории", 10, false, 2, 1},
+		{"пачка ограничена", 1, false, 1, 2},
+		{"история сохраняется", 10, true, 2, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newTestRepository(t, RepositoryConfig{})
+			for _, name := range []string{"Old 1", "Old 2", "Recent", "Active"} {
+				mustCreateSong(t, repo, newTestSong("Muse", name))
+			}
+			mustExec(t, `UPDATE songs SET deleted_at = '2024-01-01'::timestamp + id * interval '1 day' WHERE id IN (1, 2)`)
+			mustExec(t, `UPDATE songs SET deleted_at = '2024-03-01' WHERE id = 3`)
+			for _, id := range []int64{1, 2, 3} {
+				mustExec(t, `INSERT INTO song_events (event_type, song_id, actor, payload, occurred_at)
+					VALUES ('song.deleted', $1, 'test', '{}', now())`, id)
+			}
+			repo.RecordAccess(ctx, 1, "view")
+			if err := repo.AddVerseBookmark(ctx, "client-1", 1, 1, ""); err != nil {
+				t.Fatalf("AddVerseBookmark() error = %v", err)
+			}
+
+			cutoff := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+			purged, err := repo.PurgeDeletedSongs(ctx, cutoff, tt.limit, tt.keepHistory)
+			if err != nil {
+				t.Fatalf("PurgeDeletedSongs() error = %v", err)
+			}
+			if purged != tt.wantPurged {
+				t.Errorf("PurgeDeletedSongs() = %d, want %d", purged, tt.wantPurged)
+			}
+
+			var songs, events, related int
+			if err = testDB.Get(&songs, `SELECT count(*) FROM songs`); err != nil {
+				t.Fatalf("ошибка подсчета песен: %v", err)
+			}
+			if err = testDB.Get(&events, `SELECT count(*) FROM song_events`); err != nil {
+				t.Fatalf("ошибка подсчета событий: %v", err)
+			}
+			if err = testDB.Get(&related, `SELECT (SELECT count(*) FROM song_access_log) + (SELECT count(*) FROM bookmarks)`); err != nil {
+				t.Fatalf("ошибка подсчета связанных записей: %v", err)
+			}
+			if songs != 4-int(tt.wantPurged) {
+				t.Errorf("песен осталось = %d, want %d", songs, 4-tt.wantPurged)
+			}
+			if events != tt.wantEvents {
+				t.Errorf("событий осталось = %d, want %d", events, tt.wantEvents)
+			}
+			// Песня 1 удалена раньше всех и попадает в любую пачку: журнал и закладки удаляются каскадно
+			if related != 0 {
+				t.Errorf("записей журнала и закладок осталось = %d, want 0", related)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"song-library/internal/model"
+	"testing"
+)
+
+func TestVerseBounds(t *testing.T) {
+	// position возвращает указатель на номер куплета; intPtr объявлен только в интеграционных тестах
+	position := func(n int) *int { return &n }
+	page := func(page, size int) model.Pagination { return model.Pagination{Page: page, PageSize: size} }
+
+	tests := []struct {
+		name       string
+		total      int
+		pagination model.VersesPagination
+		wantStart  int
+		wantEnd    int
+	}{
+		{"первая страница", 10, model.VersesPagination{Pagination: page(1, 3)}, 0, 3},
+		{"вторая страница", 10, model.VersesPagination{Pagination: page(2, 3)}, 3, 6},
+		{"неполная последняя страница", 10, model.VersesPagination{Pagination: page(4, 3)}, 9, 10},
+		{"страница за пределами", 10, model.VersesPagination{Pagination: page(5, 3)}, 0, 0},
+		{"обратный порядок: первая страница с конца", 10, model.VersesPagination{Pagination: page(1, 3), Descending: true}, 7, 10},
+		{"обратный порядок: неполная последняя страница", 10, model.VersesPagination{Pagination: page(4, 3), Descending: true}, 0, 1},
+		{"обратный порядок: страница за пределами", 10, model.VersesPagination{Pagination: page(5, 3), Descending: true}, 0, 0},
+		{"диапазон", 10, model.VersesPagination{From: position(2), To: position(4)}, 1, 4},
+		{"диапазон без конца", 10, model.VersesPagination{From: position(8)}, 7, 10},
+		{"диапазон без начала", 10, model.VersesPagination{To: position(2)}, 0, 2},
+		{"диапазон за пределами текста", 3, model.VersesPagination{From: position(2), To: position(10)}, 1, 3},
+		{"диапазон важнее страницы", 10, model.VersesPagination{Pagination: page(3, 2), From: position(1), To: position(1)}, 0, 1},
+		{"песня без куплетов", 0, model.VersesPagination{Pagination: page(1, 3)}, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := verseBounds(tt.total, tt.pagination)
+			// Пустую выборку GetSongVerses определяет по start >= end, сами границы при этом не важны
+			if tt.wantStart == tt.wantEnd {
+				if start < end {
+					t.Errorf("verseBounds(%d) = [%d, %d), want пустой диапазон", tt.total, start, end)
+				}
+				return
+			}
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("verseBounds(%d) = [%d, %d), want [%d, %d)", tt.total, start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package selfcheck
+
+import (
+	"context"
+	"fmt"
+)
+
+// Pinger проверяет доступность зависимости
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// Database проверяет доступность базы данных
+func Database(db Pinger) Check {
+	return Check{
+		Name:     "database",
+		Severity: SeverityFatal,
+		Run: func(ctx context.Context) (string, error) {
+			if err := db.PingContext(ctx); err != nil {
+				return "", fmt.Errorf("база данных недоступна: %w", err)
+			}
+			return "доступна", nil
+		},
+	}
+}
+
+// Migrations применяет миграции базы данных функцией migrate и сообщает версию схемы, прочитанную функцией applied.
+// Расхождение примененной версии с ожидаемой expected считается фатальной ошибкой
+func Migrations(migrate func() error, applied func(ctx context.Context) (int, error), expected int) Check {
+	return Check{
+		Name:     "migrations",
+		Severity: SeverityFatal,
+		Run: func(ctx context.Context) (string, error) {
+			if err := migrate(); err != nil {
+				return "", err
+			}
+			version, err := applied(ctx)
+			if err != nil {
+				return "", err
+			}
+			if version != expected {
+				return fmt.Sprintf("версия %d", version), fmt.Errorf("версия схемы %d не совпадает с ожидаемой %d", version, expected)
+			}
+			return fmt.Sprintf("версия %d", version), nil
+		},
+	}
+}
+
+// ExternalAPI проверяет доступность внешнего API. Недоступность не мешает запуску:
+// песни без обращения к внешнему API (импорт, чтение) продолжают работать
+func ExternalAPI(api Pinger, url string) Check {
+	return Check{
+		Name:     "external_api",
+		Severity: SeverityDegraded,
+		Run: func(ctx context.Context) (string, error) {
+			if err := api.PingContext(ctx); err != nil {
+				return url, fmt.Errorf("внешний API недоступен: %w", err)
+			}
+			return url, nil
+		},
+	}
+}
+
+// Info добавляет в отчет сведения о конфигурации без проверки
+func Info(name, detail string) Check {
+	return Check{
+		Name:     name,
+		Severity: SeverityDegraded,
+		Run: func(ctx context.Context) (string, error) {
+			return detail, nil
+		},
+	}
+}
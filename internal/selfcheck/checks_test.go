@@ -0,0 +1,49 @@
+package selfcheck
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMigrations(t *testing.T) {
+	tests := []struct {
+		name        string
+		migrateErr  error
+		applied     int
+		appliedErr  error
+		expected    int
+		wantStatus  string
+		wantDetail  string
+		wantErrText string
+	}{
+		{"версия совпадает", nil, 42, nil, 42, StatusOK, "версия 42", ""},
+		{"версия схемы отстает", nil, 41, nil, 42, StatusFailed, "версия 41", "версия схемы 41 не совпадает с ожидаемой 42"},
+		{"версия схемы опережает", nil, 43, nil, 42, StatusFailed, "версия 43", "версия схемы 43 не совпадает с ожидаемой 42"},
+		{"ошибка миграции", errors.New("syntax error"), 0, nil, 42, StatusFailed, "", "syntax error"},
+		{"ошибка чтения версии", nil, 0, errors.New("relation does not exist"), 42, StatusFailed, "", "relation does not exist"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := Migrations(
+				func() error { return tt.migrateErr },
+				func(context.Context) (int, error) { return tt.applied, tt.appliedErr },
+				tt.expected,
+			)
+
+			report := Run(context.Background(), time.Second, check)
+			result := report.Checks[0]
+			if report.Status != tt.wantStatus || result.Status != tt.wantStatus {
+				t.Errorf("status = %s/%s, want %s", report.Status, result.Status, tt.wantStatus)
+			}
+			if result.Detail != tt.wantDetail {
+				t.Errorf("detail = %q, want %q", result.Detail, tt.wantDetail)
+			}
+			if result.Error != tt.wantErrText {
+				t.Errorf("error = %q, want %q", result.Error, tt.wantErrText)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package selfcheck
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Severity определяет, как неудача проверки влияет на запуск сервиса
+type Severity string
+
+const (
+	// SeverityFatal неудача останавливает запуск сервиса
+	SeverityFatal Severity = "fatal"
+	// SeverityDegraded сервис запускается, но часть функций может не работать
+	SeverityDegraded Severity = "degraded"
+)
+
+// Статусы проверок и отчета
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+	StatusFailed   = "failed"
+)
+
+// Check проверка, выполняемая при запуске. Run возвращает описание результата для отчета
+type Check struct {
+	Name     string
+	Severity Severity
+	Run      func(ctx context.Context) (string, error)
+}
+
+// Result результат одной проверки
+type Result struct {
+	Name       string   `json:"name" example:"database"`
+	Status     string   `json:"status" example:"ok"`
+	Severity   Severity `json:"severity" example:"fatal"`
+	Detail     string   `json:"detail,omitempty" example:"доступна"`
+	Error      string   `json:"error,omitempty" example:"dial tcp 127.0.0.1:5432: connect: connection refused"`
+	DurationMs int64    `json:"duration_ms" example:"3"`
+}
+
+// Report отчет о проверках запуска. Status — failed, если не прошла хотя бы одна фатальная проверка,
+// degraded, если не прошла хотя бы одна нефатальная, иначе ok
+type Report struct {
+	Status    string    `json:"status" example:"ok"`
+	CheckedAt time.Time `json:"checked_at" example:"2024-01-15T10:30:00Z"`
+	Checks    []Result  `json:"checks"`
+}
+
+// Run выполняет проверки по порядку, ограничивая каждую временем timeout
+func Run(ctx context.Context, timeout time.Duration, checks ...Check) *Report {
+	report := &Report{Status: StatusOK, CheckedAt: time.Now(), Checks: make([]Result, 0, len(checks))}
+
+	for _, check := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, timeout)
+		started := time.Now()
+		detail, err := check.Run(checkCtx)
+		cancel()
+
+		result := Result{
+			Name:       check.Name,
+			Status:     StatusOK,
+			Severity:   check.Severity,
+			Detail:     detail,
+			DurationMs: time.Since(started).Milliseconds(),
+		}
+		if err != nil {
+			result.Error = err.Error()
+			result.Status = StatusFailed
+			if check.Severity == SeverityFatal {
+				report.Status = StatusFailed
+			} else {
+				if report.Status == StatusOK {
+					report.Status = StatusDegraded
+				}
+				result.Status = StatusDegraded
+			}
+		}
+		report.Checks = append(report.Checks, result)
+	}
+
+	return report
+}
+
+// Failed сообщает, не прошла ли хотя бы одна фатальная проверка
+func (r *Report) Failed() bool {
+	return r.Status == StatusFailed
+}
+
+// LogAttrs возвращает атрибуты для записи отчета одной строкой лога: каждая проверка — отдельная группа
+func (r *Report) LogAttrs() []any {
+	attrs := []any{slog.String("status", r.Status)}
+	for _, result := range r.Checks {
+		group := []any{
+			slog.String("status", result.Status),
+			slog.Int64("duration_ms", result.DurationMs),
+		}
+		if result.Detail != "" {
+			group = append(group, slog.String("detail", result.Detail))
+		}
+		if result.Error != "" {
+			group = append(group, slog.String("error", result.Error), slog.String("severity", string(result.Severity)))
+		}
+		attrs = append(attrs, slog.Group(result.Name, group...))
+	}
+	return attrs
+}
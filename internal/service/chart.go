@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"song-library/internal/model"
+	"time"
+)
+
+// UpdateSongChart устанавливает позицию песни в чарте и добавляет в историю снимок позиции на текущую дату (UTC)
+func (s *SongService) UpdateSongChart(ctx context.Context, id int64, position *int) error {
+	log := s.logger.WithFields(ctx, "id", id)
+
+	log.Debug("Обновление позиции песни в чарте")
+
+	if position == nil {
+		return model.NewValidationError("position обязателен")
+	}
+	if *position < model.MinChartPosition || *position > model.MaxChartPosition {
+		return model.NewValidationError(fmt.Sprintf("position должен быть от %d до %d", model.MinChartPosition, model.MaxChartPosition))
+	}
+
+	date := time.Now().UTC().Format(time.DateOnly)
+	if err := s.repo.UpdateSongChart(ctx, id, int16(*position), date); err != nil {
+		log.Error("Ошибка обновления позиции песни в чарте в репозитории", "error", err)
+		return fmt.Errorf("ошибка обновления позиции песни в чарте: %w", err)
+	}
+	s.invalidateSongs(id)
+
+	_ = s.logEvent(ctx, model.EventSongChartUpdated, &id, map[string]interface{}{"position": *position, "date": date})
+
+	log.Info("Позиция песни в чарте успешно обновлена", "position", *position)
+	return nil
+}
+
+// GetChartToppers получает песни, занимавшие первую позицию в чарте на дату date (ГГГГ-ММ-ДД).
+// Пустая дата означает текущую дату (UTC)
+func (s *SongService) GetChartToppers(ctx context.Context, date string) ([]*model.Song, error) {
+	log := s.logger.WithContext(ctx)
+
+	log.Debug("Получение лидеров чарта", "date", date)
+
+	if date == "" {
+		date = time.Now().UTC().Format(time.DateOnly)
+	} else if _, err := time.Parse(time.DateOnly, date); err != nil {
+		return nil, model.NewValidationError("date должен быть в формате ГГГГ-ММ-ДД")
+	}
+
+	songs, err := s.repo.GetChartToppers(ctx, date)
+	if err != nil {
+		log.Error("Ошибка получения лидеров чарта из репозитория", "error", err)
+		return nil, fmt.Errorf("ошибка получения лидеров чарта: %w", err)
+	}
+
+	log.Info("Лидеры чарта успешно получены", "count", len(songs))
+	return songs, nil
+}
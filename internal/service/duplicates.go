@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"song-library/internal/model"
+	"song-library/pkg/pagination"
+)
+
+// FindDuplicates находит группы песен, названия которых похожи друг на друга не меньше threshold.
+// Сходство пары — меньшее из сходства названий групп и названий песен. Группы строятся
+// как компоненты связности графа похожих пар. Сравнение квадратичное, поэтому библиотека больше
+// MaxSongsForDuplicateCheck песен отклоняется.
+func (s *SongService) FindDuplicates(ctx context.Context, threshold float64, page, size int) ([]model.DuplicateGroup, error) {
+	log := s.logger.WithContext(ctx)
+
+	log.Debug("Поиск дубликатов песен", "threshold", threshold, "page", page, "pageSize", size)
+
+	if threshold <= 0 || threshold > 1 {
+		return nil, model.NewValidationError("threshold должен быть в диапазоне (0, 1]")
+	}
+	window := pagination.NewPage(page, size, s.cfg.DefaultSongsPageSize, s.cfg.MaxSongsPageSize)
+
+	songs, err := s.repo.GetSongSummaries(ctx, s.cfg.MaxSongsForDuplicateCheck+1)
+	if err != nil {
+		log.Error("Ошибка получения песен из репозитория", "error", err)
+		return nil, fmt.Errorf("ошибка поиска дубликатов: %w", err)
+	}
+	if len(songs) > s.cfg.MaxSongsForDuplicateCheck {
+		log.Info("Библиотека слишком велика для поиска дубликатов", "limit", s.cfg.MaxSongsForDuplicateCheck)
+		return nil, model.NewValidationError(fmt.Sprintf("поиск дубликатов доступен для библиотек не больше %d песен", s.cfg.MaxSongsForDuplicateCheck))
+	}
+
+	groups := clusterDuplicates(songs, threshold)
+
+	start := min(window.Offset(), len(groups))
+	end := min(start+window.PageSize, len(groups))
+
+	log.Info("Поиск дубликатов завершен", "groups", len(groups), "returned", end-start)
+	return groups[start:end], nil
+}
+
+// clusterDuplicates объединяет похожие песни в группы через систему непересекающихся множеств.
+// Группы и песни в них упорядочены по возрастанию id, одиночные песни не возвращаются.
+func clusterDuplicates(songs []*model.Song, threshold float64) []model.DuplicateGroup {
+	parent := make([]int, len(songs))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	groupNames := make([]string, len(songs))
+	songNames := make([]string, len(songs))
+	for i, song := range songs {
+		groupNames[i] = normalizeName(song.Group)
+		songNames[i] = normalizeName(song.Song)
+	}
+
+	for i := range songs {
+		for j := i + 1; j < len(songs); j++ {
+			if find(i) == find(j) {
+				continue
+			}
+			similarity := min(jaroWinkler(groupNames[i], groupNames[j]), jaroWinkler(songNames[i], songNames[j]))
+			if similarity >= threshold {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	// песни отсортированы по id, поэтому порядок первого появления корня совпадает с порядком групп
+	index := make(map[int]int)
+	var groups []model.DuplicateGroup
+	for i, song := range songs {
+		root := find(i)
+		position, ok := index[root]
+		if !ok {
+			position = len(groups)
+			index[root] = position
+			groups = append(groups, model.DuplicateGroup{})
+		}
+		groups[position].Candidates = append(groups[position].Candidates, song.Summary())
+	}
+
+	duplicates := []model.DuplicateGroup{}
+	for _, group := range groups {
+		if len(group.Candidates) > 1 {
+			duplicates = append(duplicates, group)
+		}
+	}
+	return duplicates
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"song-library/internal/model"
+	"testing"
+)
+
+// summariesRepository возвращает из GetSongSummaries не больше limit заранее заданных песен
+type summariesRepository struct {
+	SongRepository
+	songs []*model.Song
+	limit int
+}
+
+// GetSongSummaries запоминает limit и возвращает первые limit песен
+func (r *summariesRepository) GetSongSummaries(_ context.Context, limit int) ([]*model.Song, error) {
+	r.limit = limit
+	return r.songs[:min(limit, len(r.songs))], nil
+}
+
+// duplicateSongs песни по возрастанию id: три написания одной песни, три версии другой и одиночная песня
+func duplicateSongs() []*model.Song {
+	return []*model.Song{
+		{ID: 1, Group: "The Beatles", Song: "Yesterday"},
+		{ID: 2, Group: "Muse", Song: "Hysteria"},
+		{ID: 3, Group: "Beatles, The", Song: "Yesterday!"},
+		{ID: 4, Group: "Queen", Song: "Bohemian Rhapsody"},
+		{ID: 5, Group: "Muse", Song: "Hysteria (Live)"},
+		{ID: 6, Group: "Muse", Song: "Hysteria (Live, Wembley)"},
+		{ID: 7, Group: "The Beatles", Song: "Yesterdya"},
+	}
+}
+
+// candidateIDs возвращает идентификаторы песен каждой группы дубликатов
+func candidateIDs(groups []model.DuplicateGroup) [][]int64 {
+	ids := make([][]int64, 0, len(groups))
+	for _, group := range groups {
+		var groupIDs []int64
+		for _, candidate := range group.Candidates {
+			groupIDs = append(groupIDs, candidate.ID)
+		}
+		ids = append(ids, groupIDs)
+	}
+	return ids
+}
+
+func TestClusterDuplicates(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold float64
+		want      [][]int64
+	}{
+		{"точное совпадение после нормализации", 1, [][]int64{{1, 3}}},
+		{"опечатка в названии", 0.95, [][]int64{{1, 3, 7}}},
+		// Сходство 2 и 6 около 0.88, но обе похожи на 5: группа объединяет транзитивно связанные песни
+		{"транзитивное объединение", 0.9, [][]int64{{1, 3, 7}, {2, 5, 6}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := candidateIDs(clusterDuplicates(duplicateSongs(), tt.threshold)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("clusterDuplicates(%v) = %v, want %v", tt.threshold, got, tt.want)
+			}
+		})
+	}
+
+	if got := clusterDuplicates(nil, 0.9); got == nil || len(got) != 0 {
+		t.Errorf("clusterDuplicates(nil) = %#v, want пустой срез", got)
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold float64
+		page      int
+		size      int
+		maxSongs  int
+		want      [][]int64
+		wantErr   bool
+	}{
+		{"все группы", 0.9, 1, 10, 100, [][]int64{{1, 3, 7}, {2, 5, 6}}, false},
+		{"вторая страница", 0.9, 2, 1, 100, [][]int64{{2, 5, 6}}, false},
+		{"страница за пределами", 0.9, 3, 1, 100, [][]int64{}, false},
+		{"библиотека ровно на ограничении", 0.9, 1, 10, 7, [][]int64{{1, 3, 7}, {2, 5, 6}}, false},
+		{"нулевой порог", 0, 1, 10, 100, nil, true},
+		{"порог больше единицы", 1.5, 1, 10, 100, nil, true},
+		{"библиотека больше ограничения", 0.9, 1, 10, 6, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &summariesRepository{songs: duplicateSongs()}
+			svc := NewSongService(repo, nil, nil, nopEventLogger{}, ServiceConfig{
+				DefaultSongsPageSize:      10,
+				MaxSongsPageSize:          100,
+				MaxSongsForDuplicateCheck: tt.maxSongs,
+			}, newTestLogger())
+
+			groups, err := svc.FindDuplicates(context.Background(), tt.threshold, tt.page, tt.size)
+			if tt.wantErr {
+				if !errors.Is(err, model.ErrValidation) {
+					t.Errorf("FindDuplicates() error = %v, want %v", err, model.ErrValidation)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FindDuplicates() error = %v", err)
+			}
+			if got := candidateIDs(groups); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindDuplicates() = %v, want %v", got, tt.want)
+			}
+			// Песня сверх ограничения запрашивается, чтобы отличить библиотеку ровно на ограничении от большей
+			if repo.limit != tt.maxSongs+1 {
+				t.Errorf("GetSongSummaries(limit) = %d, want %d", repo.limit, tt.maxSongs+1)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"song-library/internal/model"
+	"song-library/pkg/logger"
+	"song-library/pkg/pagination"
+	"strings"
+)
+
+// anonymousActor автор событий, если он не определен в контексте запроса
+const anonymousActor = "anonymous"
+
+// EventLogger журнал доменных событий
+type EventLogger interface {
+	LogEvent(ctx context.Context, event *model.SongEvent) error
+}
+
+// logEvent записывает событие изменения песни в журнал.
+// Ошибка записи логируется и возвращается; вызывающие внутри транзакции должны ее вернуть,
+// так как неудачный запрос прерывает транзакцию PostgreSQL.
+func (s *SongService) logEvent(ctx context.Context, eventType string, songID *int64, payload map[string]interface{}) error {
+	log := s.logger.WithContext(ctx)
+
+	if payload == nil {
+		payload = map[string]interface{}{}
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Error("Ошибка сериализации события", "error", err, "event_type", eventType)
+		return fmt.Errorf("ошибка сериализации события: %w", err)
+	}
+
+	event := &model.SongEvent{
+		EventType: eventType,
+		SongID:    songID,
+		Actor:     actorFromContext(ctx),
+		Payload:   data,
+	}
+	if err = s.events.LogEvent(ctx, event); err != nil {
+		log.Error("Ошибка записи события в журнал", "error", err, "event_type", eventType)
+		return err
+	}
+	return nil
+}
+
+// actorFromContext возвращает автора запроса, сохраненного в контексте маршрутизатором
+func actorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value("actor").(string); ok && actor != "" {
+		return actor
+	}
+	return anonymousActor
+}
+
+// EventReader интерфейс чтения журнала событий
+type EventReader interface {
+	ListEvents(ctx context.Context, filter model.EventFilter) ([]model.SongEvent, error)
+	ListEventsPaged(ctx context.Context, filter model.EventFilter) ([]model.SongEvent, int64, error)
+	ListAPICalls(ctx context.Context, filter model.APICallFilter) ([]model.APICall, error)
+}
+
+// songEventPrefix префикс типов событий отдельной песни; операция истории — тип события без префикса
+const songEventPrefix = "song."
+
+const (
+	// defaultEventsPageSize размер страницы журнала событий по умолчанию
+	defaultEventsPageSize = 50
+	// maxEventsPageSize максимальный размер страницы журнала событий
+	maxEventsPageSize = 200
+)
+
+// EventService сервис чтения журнала доменных событий
+type EventService struct {
+	reader EventReader
+	logger *logger.Logger
+}
+
+// NewEventService создает новый сервис журнала событий
+func NewEventService(reader EventReader, logger *logger.Logger) *EventService {
+	return &EventService{reader: reader, logger: logger}
+}
+
+// ListEvents получает события журнала с фильтрацией и пагинацией
+func (s *EventService) ListEvents(ctx context.Context, filter model.EventFilter) ([]model.SongEvent, error) {
+	log := s.logger.WithContext(ctx)
+
+	log.Debug("Получение журнала событий", "song_id", filter.SongID, "event_type", filter.EventType)
+
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return nil, model.NewValidationError("from не может быть позже to")
+	}
+	filter.Pagination = pagination.NewPage(filter.Page, filter.PageSize, defaultEventsPageSize, maxEventsPageSize)
+
+	events, err := s.reader.ListEvents(ctx, filter)
+	if err != nil {
+		log.Error("Ошибка получения журнала событий из репозитория", "error", err)
+		return nil, fmt.Errorf("ошибка получения журнала событий: %w", err)
+	}
+
+	log.Info("Журнал событий успешно получен", "count", len(events))
+	return events, nil
+}
+
+// GetSongHistory получает страницу истории изменений песни, новые записи первыми.
+// Operation ограничивает историю одним типом изменений, например updated для song.updated,
+// Field — изменениями одного поля песни, Since — изменениями начиная с момента времени.
+// Без Full снимки текста в записях заменяются диффом, а остальные поля — парами старое/новое значение.
+func (s *EventService) GetSongHistory(ctx context.Context, songID int64, query model.HistoryQuery) (*model.HistoryListResponse, error) {
+	log := s.logger.WithFields(ctx, "song_id", songID)
+
+	log.Debug("Получение истории песни", "operation", query.Operation, "field", query.Field, "since", query.Since,
+		"page", query.Page, "pageSize", query.PageSize, "full", query.Full)
+
+	filter := model.EventFilter{SongID: &songID, Field: query.Field, From: query.Since, Pagination: query.Pagination}
+	if query.Operation != "" {
+		filter.EventType = songEventPrefix + query.Operation
+	}
+	return s.listHistory(ctx, filter, false, query.Full)
+}
+
+// ListHistory получает историю изменений всех песен за период для аудита, новые записи первыми.
+// Записи содержат снимки песни целиком.
+func (s *EventService) ListHistory(ctx context.Context, filter model.EventFilter) (*model.HistoryListResponse, error) {
+	log := s.logger.WithContext(ctx)
+
+	log.Debug("Получение истории изменений за период", "from", filter.From, "to", filter.To)
+
+	if filter.From == nil || filter.To == nil {
+		return nil, model.NewValidationError("from и to обязательны")
+	}
+	return s.listHistory(ctx, filter, true, true)
+}
+
+// listHistory получает страницу журнала событий в виде истории изменений.
+// Автор изменения включается в ответ только при includeActor, снимки песни целиком — только при full.
+func (s *EventService) listHistory(ctx context.Context, filter model.EventFilter, includeActor, full bool) (*model.HistoryListResponse, error) {
+	log := s.logger.WithContext(ctx)
+
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return nil, model.NewValidationError("from не может быть позже to")
+	}
+	if filter.Field != "" && !slices.Contains(model.HistoryFields, filter.Field) {
+		return nil, model.NewValidationError(fmt.Sprintf("field должен быть одним из: %s", strings.Join(model.HistoryFields, ", ")))
+	}
+	filter.Pagination = pagination.NewPage(filter.Page, filter.PageSize, defaultEventsPageSize, maxEventsPageSize)
+
+	events, total, err := s.reader.ListEventsPaged(ctx, filter)
+	if err != nil {
+		log.Error("Ошибка получения истории изменений из репозитория", "error", err)
+		return nil, fmt.Errorf("ошибка получения истории изменений: %w", err)
+	}
+
+	items := make([]model.SongHistoryEntry, 0, len(events))
+	for _, event := range events {
+		entry := model.SongHistoryEntry{
+			ID:         event.ID,
+			SongID:     event.SongID,
+			EventType:  event.EventType,
+			Payload:    event.Payload,
+			OccurredAt: event.OccurredAt,
+		}
+		if includeActor {
+			entry.Actor = event.Actor
+		}
+		if !full {
+			summarizeSnapshots(&entry)
+		}
+		items = append(items, entry)
+	}
+
+	log.Info("История изменений успешно получена", "count", len(items), "total", total)
+	filter.Total = int(total)
+	return &model.HistoryListResponse{
+		Items:      items,
+		Total:      total,
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		TotalPages: filter.TotalPages(),
+	}, nil
+}
+
+// ListAPICalls получает журнал изменяющих вызовов API с фильтрацией и пагинацией
+func (s *EventService) ListAPICalls(ctx context.Context, filter model.APICallFilter) ([]model.APICall, error) {
+	log := s.logger.WithContext(ctx)
+
+	log.Debug("Получение журнала вызовов API", "since", filter.Since, "actor", filter.Actor, "song_id", filter.SongID)
+
+	filter.Pagination = pagination.NewPage(filter.Page, filter.PageSize, defaultEventsPageSize, maxEventsPageSize)
+
+	calls, err := s.reader.ListAPICalls(ctx, filter)
+	if err != nil {
+		log.Error("Ошибка получения журнала вызовов API из репозитория", "error", err)
+		return nil, fmt.Errorf("ошибка получения журнала вызовов API: %w", err)
+	}
+
+	log.Info("Журнал вызовов API успешно получен", "count", len(calls))
+	return calls, nil
+}
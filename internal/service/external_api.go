@@ -7,48 +7,145 @@ import (
 	"net/http"
 	"net/url"
 	"song-library/internal/model"
+	"song-library/pkg/cache"
 	"song-library/pkg/logger"
 	"time"
 )
 
-// ExternalAPIClient клиент для работы с внешним API
+// ExternalAPIConfig настройки клиента внешнего API
+type ExternalAPIConfig struct {
+	BaseURL string
+	// CacheSize размер кэша ответов в записях (0 — без кэша), CacheTTL — время жизни записи
+	CacheSize int
+	CacheTTL  time.Duration
+	// Headers заголовки, добавляемые к каждому запросу, например X-API-Key
+	Headers map[string]string
+	// BasicUser и BasicPass учетные данные HTTP Basic Auth; пустой пользователь — без авторизации
+	BasicUser string
+	BasicPass string
+	// Transport транспорт HTTP-запросов к внешнему API; nil — http.DefaultTransport.
+	// Позволяет подменить транспорт, например для имитации задержек и отказов внешнего API
+	Transport http.RoundTripper
+}
+
+// ExternalAPIClient клиент для работы с внешним API.
+// Успешные ответы кэшируются в памяти, если размер кэша больше нуля.
 type ExternalAPIClient struct {
-	baseURL string
-	client  *http.Client
-	logger  *logger.Logger
+	baseURL   string
+	headers   http.Header
+	basicUser string
+	basicPass string
+	client    *http.Client
+	cache     *cache.LRUCache[string, model.SongDetail]
+	logger    *logger.Logger
 }
 
-// NewExternalAPIClient создает новый клиент внешнего API
-func NewExternalAPIClient(baseURL string, logger *logger.Logger) *ExternalAPIClient {
+// NewExternalAPIClient создает новый клиент внешнего API.
+// Возвращает ошибку, если базовый адрес не проходит ValidateBaseURL или Basic Auth сочетается с заголовком Authorization.
+func NewExternalAPIClient(cfg ExternalAPIConfig, logger *logger.Logger) (*ExternalAPIClient, error) {
+	if err := ValidateBaseURL(cfg.BaseURL); err != nil {
+		return nil, err
+	}
+
+	headers := make(http.Header, len(cfg.Headers))
+	for name, value := range cfg.Headers {
+		headers.Set(name, value)
+	}
+	if cfg.BasicUser != "" && headers.Get("Authorization") != "" {
+		return nil, fmt.Errorf("нельзя сочетать Basic Auth внешнего API с заголовком Authorization")
+	}
+
+	var detailsCache *cache.LRUCache[string, model.SongDetail]
+	if cfg.CacheSize > 0 {
+		detailsCache = cache.NewLRUCache[string, model.SongDetail](cfg.CacheSize, cfg.CacheTTL)
+	}
+
 	return &ExternalAPIClient{
-		baseURL: baseURL,
+		baseURL:   cfg.BaseURL,
+		headers:   headers,
+		basicUser: cfg.BasicUser,
+		basicPass: cfg.BasicPass,
 		client: &http.Client{
-			Timeout: 10 * time.Second,
+			Transport: cfg.Transport,
+			Timeout:   10 * time.Second,
 		},
+		cache:  detailsCache,
 		logger: logger,
-	}
+	}, nil
 }
 
-// GetSongDetails получает детали песни из внешнего API
-func (c *ExternalAPIClient) GetSongDetails(ctx context.Context, group, song string) (*model.SongDetail, error) {
-	log := c.logger.WithContext(ctx)
+// newRequest создает запрос к внешнему API с настроенными заголовками и Basic Auth
+func (c *ExternalAPIClient) newRequest(ctx context.Context, method, target string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range c.headers {
+		req.Header[name] = values
+	}
+	if c.basicUser != "" {
+		req.SetBasicAuth(c.basicUser, c.basicPass)
+	}
+	return req, nil
+}
 
-	log.Debug("Получение деталей песни из внешнего API", "group", group, "song", song)
+// ValidateBaseURL проверяет, что базовый адрес внешнего API — абсолютный URL со схемой http или https и хостом
+func ValidateBaseURL(rawURL string) error {
+	u, err := url.ParseRequestURI(rawURL)
+	if err != nil {
+		return fmt.Errorf("неверный адрес внешнего API %q: %w", rawURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("неверный адрес внешнего API %q: схема должна быть http или https", rawURL)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("неверный адрес внешнего API %q: не указан хост", rawURL)
+	}
+	return nil
+}
 
+// infoURL возвращает адрес запроса деталей песни
+func (c *ExternalAPIClient) infoURL(group, song string) (string, error) {
 	u, err := url.Parse(c.baseURL + "/info")
 	if err != nil {
-		log.Error("Ошибка при формировании URL", "error", err)
-		return nil, fmt.Errorf("ошибка при формировании URL: %w", err)
+		return "", err
 	}
 
 	q := u.Query()
 	q.Set("group", group)
 	q.Set("song", song)
 	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// detailsCacheKey возвращает ключ кэша для группы и песни
+func detailsCacheKey(group, song string) string {
+	return group + "|" + song
+}
+
+// GetSongDetails получает детали песни из внешнего API
+func (c *ExternalAPIClient) GetSongDetails(ctx context.Context, group, song string) (*model.SongDetail, error) {
+	log := c.logger.WithFields(ctx, "group", group, "song", song)
 
-	log.Debug("Отправка запроса к внешнему API", "url", u.String())
+	log.Debug("Получение деталей песни из внешнего API")
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	key := detailsCacheKey(group, song)
+	if c.cache != nil {
+		if detail, ok := c.cache.Get(key); ok {
+			log.Debug("Детали песни получены из кэша")
+			return &detail, nil
+		}
+	}
+
+	infoURL, err := c.infoURL(group, song)
+	if err != nil {
+		log.Error("Ошибка при формировании URL", "error", err)
+		return nil, fmt.Errorf("ошибка при формировании URL: %w", err)
+	}
+
+	log.Debug("Отправка запроса к внешнему API", "url", infoURL)
+
+	req, err := c.newRequest(ctx, http.MethodGet, infoURL)
 	if err != nil {
 		log.Error("Ошибка создания запроса", "error", err)
 		return nil, fmt.Errorf("ошибка создания запроса: %w", err)
@@ -72,6 +169,82 @@ func (c *ExternalAPIClient) GetSongDetails(ctx context.Context, group, song stri
 		return nil, fmt.Errorf("ошибка декодирования ответа: %w", err)
 	}
 
+	if c.cache != nil {
+		c.cache.Put(key, songDetail)
+	}
+
 	log.Info("Успешно получены детали песни из внешнего API")
 	return &songDetail, nil
 }
+
+// PingContext проверяет доступность внешнего API запросом HEAD к базовому адресу.
+// Любой HTTP-ответ, включая ошибку, считается признаком доступности
+func (c *ExternalAPIClient) PingContext(ctx context.Context) error {
+	req, err := c.newRequest(ctx, http.MethodHead, c.baseURL)
+	if err != nil {
+		return fmt.Errorf("ошибка создания запроса: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ошибка выполнения запроса: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// BaseURL возвращает базовый адрес внешнего API
+func (c *ExternalAPIClient) BaseURL() string {
+	return c.baseURL
+}
+
+// Ping проверяет доступность внешнего API запросом деталей песни group=ping, song=ping без кэша.
+// Ответы 2xx и 4xx, включая 404 для неизвестной песни, означают, что сервер работает;
+// ошибкой считаются только сбой соединения и ответы 5xx
+func (c *ExternalAPIClient) Ping(ctx context.Context) error {
+	infoURL, err := c.infoURL("ping", "ping")
+	if err != nil {
+		return fmt.Errorf("ошибка при формировании URL: %w", err)
+	}
+
+	req, err := c.newRequest(ctx, http.MethodGet, infoURL)
+	if err != nil {
+		return fmt.Errorf("ошибка создания запроса: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ошибка выполнения запроса: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("внешний API вернул код состояния %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Probe выполняет пробный запрос деталей песни group=test, song=test без кэша.
+// В отличие от PingContext, ошибкой считается и ответ с кодом, отличным от 200
+func (c *ExternalAPIClient) Probe(ctx context.Context) error {
+	infoURL, err := c.infoURL("test", "test")
+	if err != nil {
+		return fmt.Errorf("ошибка при формировании URL: %w", err)
+	}
+
+	req, err := c.newRequest(ctx, http.MethodGet, infoURL)
+	if err != nil {
+		return fmt.Errorf("ошибка создания запроса: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ошибка выполнения запроса: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("внешний API вернул код состояния %d", resp.StatusCode)
+	}
+	return nil
+}
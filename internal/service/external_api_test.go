@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"song-library/internal/model"
+	"song-library/internal/testutil/faulttransport"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestExternalAPIClient создает клиент внешнего API поверх transport с настройками cfg
+func newTestExternalAPIClient(t *testing.T, transport http.RoundTripper, cfg ExternalAPIConfig) *ExternalAPIClient {
+	t.Helper()
+
+	cfg.BaseURL = "http://upstream.test"
+	cfg.Transport = transport
+	client, err := NewExternalAPIClient(cfg, newTestLogger())
+	if err != nil {
+		t.Fatalf("NewExternalAPIClient() error = %v", err)
+	}
+	return client
+}
+
+func TestExternalAPIClientGetSongDetails(t *testing.T) {
+	tests := []struct {
+		name        string
+		step        faulttransport.Step
+		wantText    string
+		wantErrText string
+	}{
+		{"успешный ответ", faulttransport.OK(detailJSON), "Ooh baby", ""},
+		{"ошибка сервера", faulttransport.Status(http.StatusServiceUnavailable), "", "внешний API вернул код состояния 503"},
+		{"песня не найдена", faulttransport.Status(http.StatusNotFound), "", "внешний API вернул код состояния 404"},
+		{"обрыв соединения", faulttransport.Error(errors.New("connection reset by peer")), "", "connection reset by peer"},
+		{"некорректный JSON", faulttransport.OK(`{"text":`), "", "ошибка декодирования ответа"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := faulttransport.New().Script(infoKey("Muse", "Uprising"), tt.step)
+			client := newTestExternalAPIClient(t, transport, ExternalAPIConfig{})
+
+			detail, err := client.GetSongDetails(context.Background(), "Muse", "Uprising")
+			if tt.wantErrText != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrText) {
+					t.Fatalf("GetSongDetails() error = %v, want containing %q", err, tt.wantErrText)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetSongDetails() error = %v", err)
+			}
+			if detail.Text != tt.wantText {
+				t.Errorf("GetSongDetails().Text = %q, want %q", detail.Text, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestExternalAPIClientCache(t *testing.T) {
+	key := infoKey("Muse", "Uprising")
+	transport := faulttransport.New().Script(key,
+		faulttransport.Status(http.StatusServiceUnavailable),
+		faulttransport.OK(detailJSON),
+		faulttransport.OK(`{"text":"updated"}`),
+	)
+	client := newTestExternalAPIClient(t, transport, ExternalAPIConfig{CacheSize: 10, CacheTTL: 50 * time.Millisecond})
+
+	// Ошибка не кэшируется: следующий вызов снова обращается к внешнему API
+	if _, err := client.GetSongDetails(context.Background(), "Muse", "Uprising"); err == nil {
+		t.Fatal("GetSongDetails() error = nil, want 503")
+	}
+	for i := range 3 {
+		detail, err := client.GetSongDetails(context.Background(), "Muse", "Uprising")
+		if err != nil {
+			t.Fatalf("GetSongDetails() #%d error = %v", i, err)
+		}
+		if detail.Text != "Ooh baby" {
+			t.Errorf("GetSongDetails() #%d Text = %q, want %q", i, detail.Text, "Ooh baby")
+		}
+	}
+	if got := transport.Calls(key); got != 2 {
+		t.Errorf("запросов к внешнему API = %d, want 2", got)
+	}
+
+	// После истечения TTL детали запрашиваются заново
+	time.Sleep(60 * time.Millisecond)
+	detail, err := client.GetSongDetails(context.Background(), "Muse", "Uprising")
+	if err != nil {
+		t.Fatalf("GetSongDetails() после TTL error = %v", err)
+	}
+	if detail.Text != "updated" {
+		t.Errorf("GetSongDetails() после TTL Text = %q, want %q", detail.Text, "updated")
+	}
+	if got := transport.Calls(key); got != 3 {
+		t.Errorf("запросов к внешнему API после TTL = %d, want 3", got)
+	}
+}
+
+func TestExternalAPIClientCacheHits(t *testing.T) {
+	songs := []string{"Hysteria", "Uprising", "Starlight"}
+
+	tests := []struct {
+		name      string
+		cacheSize int
+		requests  []string
+		wantCalls map[string]int
+	}{
+		{"повторные запросы из кэша", 10, []string{"Hysteria", "Hysteria", "Uprising", "Hysteria", "Uprising"},
+			map[string]int{"Hysteria": 1, "Uprising": 1}},
+		// Кэш на две записи: Starlight вытесняет давно не использованную Uprising, Hysteria остается после обращения
+		{"вытеснение давно не использованной записи", 2, []string{"Hysteria", "Uprising", "Hysteria", "Starlight", "Hysteria", "Uprising"},
+			map[string]int{"Hysteria": 1, "Uprising": 2, "Starlight": 1}},
+		{"кэш выключен", 0, []string{"Hysteria", "Hysteria", "Uprising"},
+			map[string]int{"Hysteria": 2, "Uprising": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := faulttransport.New()
+			for _, song := range songs {
+				transport.Script(infoKey("Muse", song), faulttransport.OK(`{"text":"`+song+`"}`))
+			}
+			client := newTestExternalAPIClient(t, transport, ExternalAPIConfig{CacheSize: tt.cacheSize, CacheTTL: time.Minute})
+
+			for _, song := range tt.requests {
+				detail, err := client.GetSongDetails(context.Background(), "Muse", song)
+				if err != nil {
+					t.Fatalf("GetSongDetails(%s) error = %v", song, err)
+				}
+				if detail.Text != song {
+					t.Errorf("GetSongDetails(%s).Text = %q, want %q", song, detail.Text, song)
+				}
+			}
+			for _, song := range songs {
+				if got := transport.Calls(infoKey("Muse", song)); got != tt.wantCalls[song] {
+					t.Errorf("запросов %s к внешнему API = %d, want %d", song, got, tt.wantCalls[song])
+				}
+			}
+		})
+	}
+}
+
+func TestExternalAPIClientCacheReturnsCopies(t *testing.T) {
+	transport := faulttransport.New().Script(infoKey("Muse", "Uprising"), faulttransport.OK(detailJSON))
+	client := newTestExternalAPIClient(t, transport, ExternalAPIConfig{CacheSize: 10, CacheTTL: time.Minute})
+
+	first, err := client.GetSongDetails(context.Background(), "Muse", "Uprising")
+	if err != nil {
+		t.Fatalf("GetSongDetails() error = %v", err)
+	}
+	first.Text = "changed by caller"
+
+	second, err := client.GetSongDetails(context.Background(), "Muse", "Uprising")
+	if err != nil {
+		t.Fatalf("GetSongDetails() из кэша error = %v", err)
+	}
+	if second.Text != "Ooh baby" {
+		t.Errorf("GetSongDetails() из кэша Text = %q, want %q", second.Text, "Ooh baby")
+	}
+}
+
+func TestExternalAPIClientCancellation(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     func() (context.Context, context.CancelFunc)
+		wantErr error
+	}{
+		{"истек срок контекста", func() (context.Context, context.CancelFunc) {
+			return context.WithTimeout(context.Background(), 20*time.Millisecond)
+		}, context.DeadlineExceeded},
+		{"контекст отменен", func() (context.Context, context.CancelFunc) {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(20*time.Millisecond, cancel)
+			return ctx, cancel
+		}, context.Canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := infoKey("Muse", "Uprising")
+			transport := faulttransport.New().Script(key, faulttransport.Timeout(), faulttransport.OK(detailJSON))
+			client := newTestExternalAPIClient(t, transport, ExternalAPIConfig{CacheSize: 10, CacheTTL: time.Minute})
+
+			ctx, cancel := tt.ctx()
+			defer cancel()
+			if _, err := client.GetSongDetails(ctx, "Muse", "Uprising"); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetSongDetails() error = %v, want %v", err, tt.wantErr)
+			}
+
+			// Прерванный запрос не попадает в кэш: следующий вызов получает второй шаг сценария
+			if _, err := client.GetSongDetails(context.Background(), "Muse", "Uprising"); err != nil {
+				t.Fatalf("GetSongDetails() после отмены error = %v", err)
+			}
+			if got := transport.Calls(key); got != 2 {
+				t.Errorf("запросов к внешнему API = %d, want 2", got)
+			}
+		})
+	}
+}
+
+func TestExternalAPIClientRequestHeaders(t *testing.T) {
+	key := infoKey("Muse", "Uprising")
+	transport := faulttransport.New().Script(key, faulttransport.OK(detailJSON))
+	client := newTestExternalAPIClient(t, transport, ExternalAPIConfig{
+		Headers:   map[string]string{"x-api-key": "secret"},
+		BasicUser: "user",
+		BasicPass: "pass",
+	})
+
+	if _, err := client.GetSongDetails(context.Background(), "Muse", "Uprising"); err != nil {
+		t.Fatalf("GetSongDetails() error = %v", err)
+	}
+
+	req := transport.Requests(key)[0]
+	if got := req.Header.Get("X-Api-Key"); got != "secret" {
+		t.Errorf("X-Api-Key = %q, want %q", got, "secret")
+	}
+	if user, pass, ok := req.BasicAuth(); !ok || user != "user" || pass != "pass" {
+		t.Errorf("BasicAuth() = %q, %q, %v, want user, pass, true", user, pass, ok)
+	}
+}
+
+func TestFetchSongDetailsBudget(t *testing.T) {
+	tests := []struct {
+		name    string
+		budget  time.Duration
+		cancel  bool
+		wantErr error
+	}{
+		{"бюджет истек", 20 * time.Millisecond, false, model.ErrUpstreamTimeout},
+		{"вызывающий отменил запрос раньше бюджета", time.Minute, true, context.Canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := faulttransport.New().Script(infoKey("Muse", "Uprising"), faulttransport.Timeout())
+			svc := newCreateTestService(t, transport, newMemoryRepository())
+			svc.cfg.ExternalAPIBudget = tt.budget
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancel {
+				go func() {
+					<-transport.Entered()
+					cancel()
+				}()
+			}
+
+			_, err := svc.fetchSongDetails(ctx, model.SongInput{Group: "Muse", Song: "Uprising"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("fetchSongDetails() error = %v, want %v", err, tt.wantErr)
+			}
+			if errors.Is(err, model.ErrUpstreamFailed) {
+				t.Errorf("fetchSongDetails() error = %v, таймаут не должен считаться ошибкой внешнего API", err)
+			}
+		})
+	}
+}
+
+func TestExternalAPIClientPing(t *testing.T) {
+	tests := []struct {
+		name    string
+		step    faulttransport.Step
+		wantErr bool
+	}{
+		{"доступен", faulttransport.Status(http.StatusOK), false},
+		{"ответ с ошибкой считается доступностью", faulttransport.Status(http.StatusInternalServerError), false},
+		{"недоступен", faulttransport.Error(errors.New("connection refused")), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := faulttransport.New().Script("HEAD /", tt.step)
+			client := newTestExternalAPIClient(t, transport, ExternalAPIConfig{})
+
+			if err := client.PingContext(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("PingContext() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
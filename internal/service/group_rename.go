@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"song-library/internal/model"
+)
+
+// RenameGroup переименовывает группу у всех ее песен в одной транзакции.
+// Если в новой группе уже есть песни с такими же названиями, без mergeMode возвращается
+// GroupRenameConflictError со списком конфликтов и ничего не изменяется. С mergeMode=skip
+// конфликтующие песни остаются под старым названием, с mergeMode=overwrite песни новой группы удаляются.
+// Сведения о группе переносятся на новое название, если у новой группы их еще нет.
+func (s *SongService) RenameGroup(ctx context.Context, name, newName, mergeMode string) (*model.GroupRenameResult, error) {
+	log := s.logger.WithFields(ctx, "group", name, "newName", newName)
+
+	log.Debug("Переименование группы", "merge", mergeMode)
+
+	var err error
+	if newName, err = s.sanitizeString("newName", newName); err != nil {
+		return nil, err
+	}
+	newName = NormalizeName(newName)
+	if newName == "" {
+		return nil, model.NewValidationError("newName не может быть пустым")
+	}
+	if newName == name {
+		return nil, model.NewValidationError("newName совпадает с текущим названием группы")
+	}
+	switch mergeMode {
+	case "", model.RenameMergeSkip, model.RenameMergeOverwrite:
+	default:
+		return nil, model.NewValidationError("неизвестный режим merge: " + mergeMode)
+	}
+
+	result := &model.GroupRenameResult{}
+	var renamedIDs, overwrittenIDs []int64
+	err = s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		conflicts, err := s.repo.FindGroupRenameConflicts(ctx, name, newName)
+		if err != nil {
+			return err
+		}
+
+		if len(conflicts) == 0 {
+			_, existing, err := s.repo.GetGroupSongs(ctx, newName, model.GroupSongsSortName, model.Pagination{Page: 1, PageSize: 1})
+			if err != nil {
+				return err
+			}
+			if existing > 0 {
+				log.Warn("Группа переименовывается в уже существующую, песни групп будут объединены", "existingSongs", existing)
+			}
+		}
+
+		var skipped []int64
+		if len(conflicts) > 0 {
+			switch mergeMode {
+			case model.RenameMergeSkip:
+				for _, conflict := range conflicts {
+					skipped = append(skipped, conflict.SongID)
+				}
+			case model.RenameMergeOverwrite:
+				for _, conflict := range conflicts {
+					if err = s.repo.DeleteSong(ctx, conflict.ExistingID); err != nil {
+						return err
+					}
+					if err = s.logEvent(ctx, model.EventSongDeleted, &conflict.ExistingID, map[string]interface{}{
+						"reason":      "group_rename",
+						"replaced_by": conflict.SongID,
+					}); err != nil {
+						return err
+					}
+					overwrittenIDs = append(overwrittenIDs, conflict.ExistingID)
+				}
+			default:
+				return &model.GroupRenameConflictError{Conflicts: conflicts}
+			}
+		}
+
+		renamedIDs, err = s.repo.RenameGroup(ctx, name, newName, skipped)
+		if err != nil {
+			return err
+		}
+		for _, id := range renamedIDs {
+			if err = s.logEvent(ctx, model.EventSongGroupRenamed, &id, map[string]interface{}{"from": name, "to": newName}); err != nil {
+				return err
+			}
+		}
+		if err = s.repo.RenameGroupInfo(ctx, name, newName); err != nil {
+			return err
+		}
+
+		result.Renamed = len(renamedIDs)
+		result.Skipped = len(skipped)
+		result.Overwritten = len(overwrittenIDs)
+		result.RenamedIDs = renamedIDs
+		return nil
+	})
+	if err != nil {
+		log.Error("Ошибка переименования группы", "error", err)
+		return nil, fmt.Errorf("ошибка переименования группы: %w", err)
+	}
+	s.invalidateSongs(append(renamedIDs, overwrittenIDs...)...)
+
+	log.Info("Группа успешно переименована", "renamed", result.Renamed, "skipped", result.Skipped, "overwritten", result.Overwritten)
+	return result, nil
+}
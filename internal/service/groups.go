@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"song-library/internal/model"
+	"song-library/pkg/pagination"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// GetGroupInfo получает сведения о группе. Если сведений нет, возвращается model.ErrGroupInfoNotFound.
+func (s *SongService) GetGroupInfo(ctx context.Context, name string) (*model.GroupInfo, error) {
+	name = NormalizeName(name)
+	log := s.logger.WithFields(ctx, "group", name)
+
+	log.Debug("Получение сведений о группе")
+
+	info, err := s.repo.GetGroupInfo(ctx, name)
+	if err != nil {
+		log.Error("Ошибка получения сведений о группе из репозитория", "error", err)
+		return nil, fmt.Errorf("ошибка получения сведений о группе: %w", err)
+	}
+	if info == nil {
+		log.Info("Сведения о группе не найдены")
+		return nil, model.ErrGroupInfoNotFound
+	}
+
+	log.Info("Сведения о группе успешно получены")
+	return info, nil
+}
+
+// GetSongsForGroup получает страницу песен группы и их общее количество. Песни упорядочены по названию,
+// а с sortBy=release_date — по дате выхода; сортировка общего списка песен на этот порядок не влияет
+func (s *SongService) GetSongsForGroup(ctx context.Context, group, sortBy string, page, size int) ([]*model.Song, int64, error) {
+	group = NormalizeName(group)
+	log := s.logger.WithFields(ctx, "group", group)
+
+	log.Debug("Получение песен группы", "sort_by", sortBy, "page", page, "pageSize", size)
+
+	switch sortBy {
+	case "":
+		sortBy = model.GroupSongsSortName
+	case model.GroupSongsSortName, model.GroupSongsSortReleaseDate:
+	default:
+		return nil, 0, model.NewValidationError(fmt.Sprintf("sort_by должен быть %s или %s",
+			model.GroupSongsSortName, model.GroupSongsSortReleaseDate))
+	}
+
+	window := pagination.NewPage(page, size, s.cfg.DefaultSongsPageSize, s.cfg.MaxSongsPageSize)
+	if err := s.checkOffset(ctx, window); err != nil {
+		return nil, 0, err
+	}
+
+	songs, total, err := s.repo.GetGroupSongs(ctx, group, sortBy, window)
+	if err != nil {
+		log.Error("Ошибка получения песен группы из репозитория", "error", err)
+		return nil, 0, fmt.Errorf("ошибка получения песен группы: %w", err)
+	}
+
+	log.Info("Песни группы успешно получены", "count", len(songs), "total", total)
+	return songs, total, nil
+}
+
+// GetTopSongsByGroup получает до limit (от 1 до model.MaxTopSongsLimit) песен группы с наибольшим значением метрики:
+// просмотров (views, по умолчанию), времени изменения (updated_at) или добавления (created_at)
+func (s *SongService) GetTopSongsByGroup(ctx context.Context, group, metric string, limit int) ([]*model.Song, error) {
+	group = NormalizeName(group)
+	log := s.logger.WithFields(ctx, "group", group)
+
+	log.Debug("Получение рейтинга песен группы", "metric", metric, "limit", limit)
+
+	switch metric {
+	case "":
+		metric = model.TopSongsMetricViews
+	case model.TopSongsMetricViews, model.TopSongsMetricUpdatedAt, model.TopSongsMetricCreatedAt:
+	default:
+		return nil, model.NewValidationError(fmt.Sprintf("metric должен быть %s, %s или %s",
+			model.TopSongsMetricViews, model.TopSongsMetricUpdatedAt, model.TopSongsMetricCreatedAt))
+	}
+	if limit <= 0 || limit > model.MaxTopSongsLimit {
+		return nil, model.NewValidationError(fmt.Sprintf("limit должен быть от 1 до %d", model.MaxTopSongsLimit))
+	}
+
+	songs, err := s.repo.GetTopSongsByGroup(ctx, group, metric, limit)
+	if err != nil {
+		log.Error("Ошибка получения рейтинга песен группы из репозитория", "error", err)
+		return nil, fmt.Errorf("ошибка получения рейтинга песен группы: %w", err)
+	}
+
+	log.Info("Рейтинг песен группы успешно получен", "count", len(songs))
+	return songs, nil
+}
+
+// GetGroupStats получает сводную статистику песен группы. Если у группы нет песен, возвращается model.ErrGroupNotFound.
+func (s *SongService) GetGroupStats(ctx context.Context, group string) (*model.GroupStats, error) {
+	group = NormalizeName(group)
+	log := s.logger.WithFields(ctx, "group", group)
+
+	log.Debug("Получение статистики группы")
+
+	stats, err := s.repo.GetGroupStats(ctx, group)
+	if err != nil {
+		log.Error("Ошибка получения статистики группы из репозитория", "error", err)
+		return nil, fmt.Errorf("ошибка получения статистики группы: %w", err)
+	}
+	if stats.SongCount == 0 {
+		log.Info("У группы нет песен")
+		return nil, model.ErrGroupNotFound
+	}
+
+	log.Info("Статистика группы успешно получена", "song_count", stats.SongCount)
+	return stats, nil
+}
+
+// ExportGroupSongbook передает fn песни группы по одной в порядке названий для сборника,
+// не загружая их в память целиком. Если у группы нет песен, возвращается model.ErrGroupNotFound.
+func (s *SongService) ExportGroupSongbook(ctx context.Context, group string, fn func(song *model.Song) error) error {
+	group = NormalizeName(group)
+	log := s.logger.WithFields(ctx, "group", group)
+
+	log.Debug("Экспорт сборника группы")
+
+	count := 0
+	err := s.repo.IterateGroupSongs(ctx, group, func(song *model.Song) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(song); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		log.Error("Экспорт сборника группы прерван", "error", err, "count", count)
+		return fmt.Errorf("ошибка экспорта сборника группы: %w", err)
+	}
+	if count == 0 {
+		log.Info("У группы нет песен")
+		return model.ErrGroupNotFound
+	}
+
+	log.Info("Сборник группы успешно экспортирован", "count", count)
+	return nil
+}
+
+// PutGroupInfo создает или заменяет сведения о группе целиком.
+// Возвращает сохраненные сведения и true, если они были созданы.
+func (s *SongService) PutGroupInfo(ctx context.Context, name string, input model.GroupInfoInput) (*model.GroupInfo, bool, error) {
+	name = NormalizeName(name)
+	log := s.logger.WithFields(ctx, "group", name)
+
+	log.Debug("Сохранение сведений о группе")
+
+	if name == "" {
+		return nil, false, model.NewValidationError("название группы не может быть пустым")
+	}
+	info, err := s.groupInfoFromInput(name, input)
+	if err != nil {
+		log.Info("Неверные сведения о группе", "error", err)
+		return nil, false, err
+	}
+
+	saved, created, err := s.repo.UpsertGroupInfo(ctx, info)
+	if err != nil {
+		log.Error("Ошибка сохранения сведений о группе в репозитории", "error", err)
+		return nil, false, fmt.Errorf("ошибка сохранения сведений о группе: %w", err)
+	}
+
+	log.Info("Сведения о группе успешно сохранены", "created", created)
+	return saved, created, nil
+}
+
+// groupInfoFromInput проверяет и нормализует сведения о группе из запроса
+func (s *SongService) groupInfoFromInput(name string, input model.GroupInfoInput) (*model.GroupInfo, error) {
+	description, err := s.sanitizeString("description", strings.TrimSpace(input.Description))
+	if err != nil {
+		return nil, err
+	}
+	country, err := s.sanitizeString("country", NormalizeName(input.Country))
+	if err != nil {
+		return nil, err
+	}
+	if utf8.RuneCountInString(country) > model.MaxGroupCountryLength {
+		return nil, model.NewValidationError(fmt.Sprintf("country не может быть длиннее %d символов", model.MaxGroupCountryLength))
+	}
+	if input.FormedYear != nil && (*input.FormedYear < model.MinFormedYear || *input.FormedYear > time.Now().Year()) {
+		return nil, model.NewValidationError(fmt.Sprintf("formed_year должен быть в диапазоне %d–%d", model.MinFormedYear, time.Now().Year()))
+	}
+	if len(input.Links) > model.MaxGroupLinks {
+		return nil, model.NewValidationError(fmt.Sprintf("links не может содержать больше %d ссылок", model.MaxGroupLinks))
+	}
+
+	links := make([]string, 0, len(input.Links))
+	for _, link := range input.Links {
+		link = strings.TrimSpace(link)
+		if !validGroupLink(link) {
+			return nil, model.NewValidationError("ссылка должна быть абсолютным адресом http или https: " + link)
+		}
+		if s.cfg.MaxLinkLength > 0 && len(link) > s.cfg.MaxLinkLength {
+			return nil, &model.LimitError{Field: "links", Limit: s.cfg.MaxLinkLength}
+		}
+		links = append(links, link)
+	}
+
+	return &model.GroupInfo{
+		Name:        name,
+		Description: description,
+		Country:     country,
+		FormedYear:  input.FormedYear,
+		Links:       links,
+	}, nil
+}
+
+// validGroupLink сообщает, является ли ссылка абсолютным адресом http или https
+func validGroupLink(link string) bool {
+	u, err := url.Parse(link)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
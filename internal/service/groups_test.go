@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"song-library/internal/model"
+	"testing"
+)
+
+// groupSongsRepository запоминает аргументы GetGroupSongs и возвращает заранее заданные песни
+type groupSongsRepository struct {
+	SongRepository
+
+	group  string
+	sortBy string
+	page   model.Pagination
+	calls  int
+	songs  []*model.Song
+	total  int64
+	err    error
+}
+
+// GetGroupSongs запоминает аргументы вызова
+func (r *groupSongsRepository) GetGroupSongs(_ context.Context, group, sortBy string, page model.Pagination) ([]*model.Song, int64, error) {
+	r.calls++
+	r.group, r.sortBy, r.page = group, sortBy, page
+	return r.songs, r.total, r.err
+}
+
+func TestGetSongsForGroup(t *testing.T) {
+	tests := []struct {
+		name       string
+		group      string
+		sortBy     string
+		page       int
+		size       int
+		wantGroup  string
+		wantSortBy string
+		wantPage   model.Pagination
+		wantErr    error
+	}{
+		{"по умолчанию сортировка по названию", "Muse", "", 0, 0, "Muse", model.GroupSongsSortName,
+			model.Pagination{Page: 1, PageSize: 10}, nil},
+		{"сортировка по дате выхода", "Muse", model.GroupSongsSortReleaseDate, 2, 5, "Muse", model.GroupSongsSortReleaseDate,
+			model.Pagination{Page: 2, PageSize: 5}, nil},
+		{"имя группы нормализуется", "  Red Hot \t Chili Peppers.", model.GroupSongsSortName, 1, 10, "Red Hot Chili Peppers",
+			model.GroupSongsSortName, model.Pagination{Page: 1, PageSize: 10}, nil},
+		{"размер страницы ограничен", "Muse", "", 1, 500, "Muse", model.GroupSongsSortName,
+			model.Pagination{Page: 1, PageSize: 100}, nil},
+		{"неизвестная сортировка", "Muse", "views", 1, 10, "", "", model.Pagination{}, model.ErrValidation},
+		{"смещение больше допустимого", "Muse", "", 200, 10, "", "", model.Pagination{}, model.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &groupSongsRepository{songs: []*model.Song{{ID: 1, Group: "Muse", Song: "Hysteria"}}, total: 1}
+			svc := NewSongService(repo, nil, nil, nopEventLogger{}, ServiceConfig{
+				DefaultSongsPageSize: 10,
+				MaxSongsPageSize:     100,
+				MaxPaginationOffset:  1000,
+			}, newTestLogger())
+
+			songs, total, err := svc.GetSongsForGroup(context.Background(), tt.group, tt.sortBy, tt.page, tt.size)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetSongsForGroup() error = %v, want %v", err, tt.wantErr)
+				}
+				if repo.calls != 0 {
+					t.Errorf("вызовов GetGroupSongs = %d, want 0", repo.calls)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetSongsForGroup() error = %v", err)
+			}
+			if len(songs) != 1 || total != 1 {
+				t.Errorf("GetSongsForGroup() = %d песен, total %d, want 1, 1", len(songs), total)
+			}
+			if repo.group != tt.wantGroup || repo.sortBy != tt.wantSortBy || repo.page != tt.wantPage {
+				t.Errorf("GetGroupSongs(%q, %q, %+v), want (%q, %q, %+v)",
+					repo.group, repo.sortBy, repo.page, tt.wantGroup, tt.wantSortBy, tt.wantPage)
+			}
+		})
+	}
+}
+
+func TestGetSongsForGroupRepositoryError(t *testing.T) {
+	errDatabase := errors.New("connection refused")
+	repo := &groupSongsRepository{err: errDatabase}
+	svc := NewSongService(repo, nil, nil, nopEventLogger{}, ServiceConfig{DefaultSongsPageSize: 10}, newTestLogger())
+
+	if _, _, err := svc.GetSongsForGroup(context.Background(), "Muse", "", 1, 10); !errors.Is(err, errDatabase) {
+		t.Errorf("GetSongsForGroup() error = %v, want %v", err, errDatabase)
+	}
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"encoding/json"
+	"song-library/internal/model"
+)
+
+// summarizeSnapshots заменяет снимки песни в записи истории диффом текста и парами старое/новое значение.
+// Снимки хранятся в событии song.updated (before и after) и в событиях изменения куплетов (previous_text и text).
+// Записи без снимков и с неразборчивым содержимым не меняются.
+func summarizeSnapshots(entry *model.SongHistoryEntry) {
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(entry.Payload, &payload); err != nil {
+		return
+	}
+
+	var before, after model.SongSnapshot
+	var previousText, text string
+	switch {
+	case decodeField(payload, "before", &before) && decodeField(payload, "after", &after):
+		entry.TextDiff = unifiedDiff(before.Text, after.Text)
+		entry.Changes = fieldChanges(before, after)
+		delete(payload, "before")
+		delete(payload, "after")
+	case decodeField(payload, "previous_text", &previousText) && decodeField(payload, "text", &text):
+		entry.TextDiff = unifiedDiff(previousText, text)
+		delete(payload, "previous_text")
+		delete(payload, "text")
+	default:
+		return
+	}
+
+	if data, err := json.Marshal(payload); err == nil {
+		entry.Payload = data
+	}
+}
+
+// decodeField разбирает поле содержимого события в dst и сообщает, удалось ли это
+func decodeField(payload map[string]json.RawMessage, key string, dst interface{}) bool {
+	raw, ok := payload[key]
+	return ok && json.Unmarshal(raw, dst) == nil
+}
+
+// fieldChanges возвращает измененные поля песни, кроме текста, с их старыми и новыми значениями
+func fieldChanges(before, after model.SongSnapshot) map[string]model.FieldChange {
+	changes := map[string]model.FieldChange{}
+	add := func(field, oldValue, newValue string) {
+		if oldValue != newValue {
+			changes[field] = model.FieldChange{Old: oldValue, New: newValue}
+		}
+	}
+	add("group", before.Group, after.Group)
+	add("song", before.Song, after.Song)
+	add("releaseDate", before.ReleaseDate, after.ReleaseDate)
+	add("link", before.Link, after.Link)
+
+	if len(changes) == 0 {
+		return nil
+	}
+	return changes
+}
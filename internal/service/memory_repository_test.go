@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"io"
+	"log/slog"
+	"slices"
+	"song-library/internal/model"
+	"song-library/pkg/logger"
+	"sync"
+)
+
+// memoryRepository хранит песни в памяти для тестов сервиса.
+// Методы, которые не переопределены, паникуют через встроенный nil-интерфейс SongRepository.
+type memoryRepository struct {
+	SongRepository
+
+	mu      sync.Mutex
+	nextID  int64
+	songs   map[int64]*model.Song
+	creates int
+	// filters фильтры вызовов GetSongs в порядке вызова
+	filters []model.SongFilter
+	// versesPages параметры вызовов GetSongVerses в порядке вызова
+	versesPages []model.VersesPagination
+}
+
+// newMemoryRepository создает пустой репозиторий в памяти
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{songs: make(map[int64]*model.Song)}
+}
+
+// CreateSong сохраняет копию песни и, как PostgreSQL, отклоняет активный дубликат группы и названия
+func (r *memoryRepository) CreateSong(_ context.Context, song *model.Song) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.creates++
+	for _, existing := range r.songs {
+		if existing.DeletedAt == nil && existing.Group == song.Group && existing.Song == song.Song {
+			return 0, model.ErrSongAlreadyExists
+		}
+	}
+
+	r.nextID++
+	song.ID = r.nextID
+	song.PublicID = uuid.NewString()
+	stored := song.Clone()
+	stored.ComputeTextStats()
+	r.songs[stored.ID] = stored
+	return stored.ID, nil
+}
+
+// GetSongByID возвращает копию активной песни или nil
+func (r *memoryRepository) GetSongByID(_ context.Context, id int64) (*model.Song, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.activeSong(id), nil
+}
+
+// GetSongByIDPrimary возвращает копию активной песни или nil
+func (r *memoryRepository) GetSongByIDPrimary(_ context.Context, id int64) (*model.Song, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.activeSong(id), nil
+}
+
+// GetSongByIDForUpdate возвращает копию активной песни или nil
+func (r *memoryRepository) GetSongByIDForUpdate(_ context.Context, id int64) (*model.Song, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.activeSong(id), nil
+}
+
+// GetSongs запоминает фильтр и возвращает копии всех активных песен от новых к старым без учета фильтра
+func (r *memoryRepository) GetSongs(_ context.Context, filter model.SongFilter) ([]*model.Song, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.filters = append(r.filters, filter)
+	songs := []*model.Song{}
+	for id := r.nextID; id > 0; id-- {
+		if song := r.activeSong(id); song != nil {
+			songs = append(songs, song)
+		}
+	}
+	return songs, nil
+}
+
+// GetSongVerses запоминает параметры выборки и возвращает пустой список куплетов
+func (r *memoryRepository) GetSongVerses(_ context.Context, _ int64, pagination model.VersesPagination) ([]string, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.versesPages = append(r.versesPages, pagination)
+	return []string{}, 0, nil
+}
+
+// UpdateSong заменяет редактируемые поля песни, сохраняя служебные
+func (r *memoryRepository) UpdateSong(_ context.Context, song *model.Song) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.songs[song.ID]
+	if !ok || stored.DeletedAt != nil {
+		return model.NewNotFoundError(song.ID)
+	}
+	stored.Group = song.Group
+	stored.Song = song.Song
+	stored.ReleaseDate = song.ReleaseDate
+	stored.Text = song.Text
+	stored.Link = song.Link
+	stored.Duration = song.Duration
+	stored.BPM = song.BPM
+	stored.Provenance = song.Provenance
+	stored.ComputeTextStats()
+	stored.ComputeContentHash()
+	return nil
+}
+
+// RecordAccess не ведет журнал обращений
+func (r *memoryRepository) RecordAccess(context.Context, int64, string) {}
+
+// WithinTransaction выполняет fn без транзакции
+func (r *memoryRepository) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// put сохраняет копию песни как есть, минуя проверки CreateSong
+func (r *memoryRepository) put(song *model.Song) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if song.ID > r.nextID {
+		r.nextID = song.ID
+	}
+	r.songs[song.ID] = song.Clone()
+}
+
+// createCount возвращает количество вызовов CreateSong
+func (r *memoryRepository) createCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.creates
+}
+
+// getSongsFilters возвращает фильтры вызовов GetSongs
+func (r *memoryRepository) getSongsFilters() []model.SongFilter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.filters)
+}
+
+// getSongVersesPages возвращает параметры вызовов GetSongVerses
+func (r *memoryRepository) getSongVersesPages() []model.VersesPagination {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.versesPages)
+}
+
+// activeSong возвращает копию неудаленной песни; вызывается под r.mu
+func (r *memoryRepository) activeSong(id int64) *model.Song {
+	song, ok := r.songs[id]
+	if !ok || song.DeletedAt != nil {
+		return nil
+	}
+	return song.Clone()
+}
+
+// nopEventLogger отбрасывает события журнала изменений
+type nopEventLogger struct{}
+
+// LogEvent отбрасывает событие
+func (nopEventLogger) LogEvent(context.Context, *model.SongEvent) error { return nil }
+
+// recordingEventLogger запоминает события журнала изменений
+type recordingEventLogger struct {
+	mu     sync.Mutex
+	events []*model.SongEvent
+}
+
+// LogEvent запоминает событие
+func (l *recordingEventLogger) LogEvent(_ context.Context, event *model.SongEvent) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.events = append(l.events, event)
+	return nil
+}
+
+// eventTypes возвращает типы записанных событий в порядке записи
+func (l *recordingEventLogger) eventTypes() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	types := make([]string, 0, len(l.events))
+	for _, event := range l.events {
+		types = append(types, event.EventType)
+	}
+	return types
+}
+
+// newTestLogger возвращает логгер, который ничего не выводит
+func newTestLogger() *logger.Logger {
+	return &logger.Logger{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"song-library/internal/model"
+	"strings"
+	"unicode"
+)
+
+// NormalizeName приводит название группы или песни к каноничному виду для хранения и проверки уникальности:
+// удаляет пробелы, знаки препинания и невидимые символы форматирования (например, U+200B) по краям
+// и схлопывает внутренние пробельные последовательности, включая табуляцию и U+00A0, в один пробел
+// ("...Nirvana..." → "Nirvana"). Регистр и диакритика сохраняются, в отличие от model.NormalizeName для поиска.
+func NormalizeName(s string) string {
+	trimmed := strings.TrimFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.Is(unicode.Cf, r)
+	})
+	return strings.Join(strings.Fields(trimmed), " ")
+}
+
+// normalizeSongNames нормализует группу и название песни и проверяет, что они не стали пустыми
+func normalizeSongNames(song *model.Song) error {
+	song.Group = NormalizeName(song.Group)
+	song.Song = NormalizeName(song.Song)
+	return validateSongNames(song.Group, song.Song)
+}
+
+// validateSongNames проверяет, что нормализованные группа и название песни не пусты
+func validateSongNames(group, song string) error {
+	if group == "" {
+		return model.NewValidationError("group не может быть пустым")
+	}
+	if song == "" {
+		return model.NewValidationError("song не может быть пустым")
+	}
+	return nil
+}
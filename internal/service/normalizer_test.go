@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"song-library/internal/model"
+	"song-library/internal/testutil/faulttransport"
+	"testing"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"без изменений", "Muse", "Muse"},
+		{"пробелы по краям", "  Muse \t", "Muse"},
+		{"знаки препинания по краям", "...Nirvana...", "Nirvana"},
+		{"знаки препинания внутри сохраняются", "AC/DC", "AC/DC"},
+		{"невидимые символы по краям", "\u200bMuse\ufeff", "Muse"},
+		{"внутренние пробелы схлопываются", "Red Hot \t Chili\n\nPeppers", "Red Hot Chili Peppers"},
+		{"неразрывный пробел", "Muse UK", "Muse UK"},
+		{"регистр и диакритика сохраняются", " Motörhead ", "Motörhead"},
+		{"только знаки препинания", " ... ", ""},
+		{"пустая строка", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeName(tt.value); got != tt.want {
+				t.Errorf("NormalizeName(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeSongNames(t *testing.T) {
+	tests := []struct {
+		name      string
+		song      model.Song
+		wantGroup string
+		wantSong  string
+		wantErr   bool
+	}{
+		{"имена нормализуются", model.Song{Group: " Muse ", Song: "Hysteria!"}, "Muse", "Hysteria", false},
+		{"пустая группа", model.Song{Group: "\u200b", Song: "Hysteria"}, "", "", true},
+		{"пустое название", model.Song{Group: "Muse", Song: "?!"}, "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			song := tt.song
+			err := normalizeSongNames(&song)
+			if tt.wantErr {
+				if !errors.Is(err, model.ErrValidation) {
+					t.Errorf("normalizeSongNames() error = %v, want %v", err, model.ErrValidation)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("normalizeSongNames() error = %v", err)
+			}
+			if song.Group != tt.wantGroup || song.Song != tt.wantSong {
+				t.Errorf("normalizeSongNames() = %q, %q, want %q, %q", song.Group, song.Song, tt.wantGroup, tt.wantSong)
+			}
+		})
+	}
+}
+
+func TestCreateSongNormalizesNames(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     model.SongInput
+		existing  bool
+		wantErr   error
+		wantCalls int
+	}{
+		{"имена нормализуются до запроса к внешнему API", model.SongInput{Group: " Muse ", Song: "...Hysteria..."}, false, nil, 1},
+		{"нормализованный дубликат отклоняется", model.SongInput{Group: "Muse ", Song: "Hysteria."}, true, model.ErrSongAlreadyExists, 1},
+		{"пустое после нормализации название", model.SongInput{Group: "Muse", Song: " ... "}, false, model.ErrValidation, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := infoKey("Muse", "Hysteria")
+			transport := faulttransport.New().Script(key, faulttransport.OK(detailJSON))
+			repo := newMemoryRepository()
+			if tt.existing {
+				repo.put(&model.Song{ID: 1, Group: "Muse", Song: "Hysteria"})
+			}
+			svc := newCreateTestService(t, transport, repo)
+
+			ref, err := svc.CreateSong(context.Background(), tt.input)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("CreateSong() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("CreateSong() error = %v", err)
+			} else if song := repo.activeSong(ref.ID); song == nil || song.Group != "Muse" || song.Song != "Hysteria" {
+				t.Errorf("сохраненная песня = %+v, want Muse/Hysteria", song)
+			}
+			if got := transport.Calls(key); got != tt.wantCalls {
+				t.Errorf("запросов к внешнему API = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
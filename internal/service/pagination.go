@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"song-library/internal/model"
+)
+
+// checkOffset проверяет смещение страницы: выше MaxPaginationOffset запрос отклоняется,
+// выше PaginationWarnOffset логируется предупреждение и смещение отмечается в model.PaginationNotice
+func (s *SongService) checkOffset(ctx context.Context, pagination model.Pagination) error {
+	offset := pagination.Offset()
+	if s.cfg.MaxPaginationOffset > 0 && offset > s.cfg.MaxPaginationOffset {
+		s.logger.WithContext(ctx).Info("Смещение страницы превышает допустимое", "offset", offset, "limit", s.cfg.MaxPaginationOffset)
+		return model.NewValidationError(fmt.Sprintf("смещение страницы не может превышать %d записей, сузьте выборку фильтрами", s.cfg.MaxPaginationOffset))
+	}
+	if s.cfg.PaginationWarnOffset > 0 && offset > s.cfg.PaginationWarnOffset {
+		s.logger.WithContext(ctx).Warn("Глубокое смещение страницы", "offset", offset, "threshold", s.cfg.PaginationWarnOffset)
+		model.MarkDeepOffset(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"song-library/internal/model"
+	"testing"
+)
+
+func TestPageSizeFromConfig(t *testing.T) {
+	cfg := ServiceConfig{
+		DefaultSongsPageSize:  7,
+		MaxSongsPageSize:      30,
+		DefaultVersesPageSize: 3,
+		MaxVersesPageSize:     12,
+	}
+
+	tests := []struct {
+		name           string
+		page, pageSize int
+		wantSongs      model.Pagination
+		wantVerses     model.Pagination
+	}{
+		{"page и page_size не заданы", 0, 0, model.Pagination{Page: 1, PageSize: 7}, model.Pagination{Page: 1, PageSize: 3}},
+		{"отрицательные значения", -1, -5, model.Pagination{Page: 1, PageSize: 7}, model.Pagination{Page: 1, PageSize: 3}},
+		{"размер в пределах максимума", 2, 10, model.Pagination{Page: 2, PageSize: 10}, model.Pagination{Page: 2, PageSize: 10}},
+		{"размер больше максимума", 3, 100, model.Pagination{Page: 3, PageSize: 30}, model.Pagination{Page: 3, PageSize: 12}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newMemoryRepository()
+			svc := NewSongService(repo, nil, nil, nopEventLogger{}, cfg, newTestLogger())
+
+			filter := model.SongFilter{Pagination: model.Pagination{Page: tt.page, PageSize: tt.pageSize}}
+			if _, err := svc.GetSongs(ctx, filter); err != nil {
+				t.Fatalf("GetSongs() error = %v", err)
+			}
+			if filters := repo.getSongsFilters(); len(filters) != 1 || filters[0].Pagination != tt.wantSongs {
+				t.Errorf("GetSongs() передал в репозиторий %+v, want %+v", filters, tt.wantSongs)
+			}
+
+			opts := model.VersesPagination{Pagination: model.Pagination{Page: tt.page, PageSize: tt.pageSize}}
+			if _, _, err := svc.GetSongVerses(ctx, 1, opts); err != nil {
+				t.Fatalf("GetSongVerses() error = %v", err)
+			}
+			if pages := repo.getSongVersesPages(); len(pages) != 1 || pages[0].Pagination != tt.wantVerses {
+				t.Errorf("GetSongVerses() передал в репозиторий %+v, want %+v", pages, tt.wantVerses)
+			}
+		})
+	}
+}
+
+func TestCheckOffset(t *testing.T) {
+	cfg := ServiceConfig{
+		DefaultSongsPageSize: 10,
+		MaxSongsPageSize:     100,
+		PaginationWarnOffset: 1000,
+		MaxPaginationOffset:  5000,
+	}
+
+	tests := []struct {
+		name           string
+		cfg            ServiceConfig
+		page           int
+		wantDeepOffset bool
+		wantErr        bool
+	}{
+		{"ниже порога предупреждения", cfg, 100, false, false},
+		{"на пороге предупреждения", cfg, 101, false, false},
+		{"выше порога предупреждения", cfg, 102, true, false},
+		{"на жестком ограничении", cfg, 501, true, false},
+		{"выше жесткого ограничения", cfg, 502, false, true},
+		{"пороги не заданы", ServiceConfig{DefaultSongsPageSize: 10}, 100000, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			svc := NewSongService(repo, nil, nil, nopEventLogger{}, tt.cfg, newTestLogger())
+			ctx, notice := model.WithPaginationNotice(context.Background())
+
+			_, err := svc.GetSongs(ctx, model.SongFilter{Pagination: model.Pagination{Page: tt.page}})
+			if tt.wantErr {
+				if !errors.Is(err, model.ErrValidation) {
+					t.Errorf("GetSongs() error = %v, want %v", err, model.ErrValidation)
+				}
+				if filters := repo.getSongsFilters(); len(filters) != 0 {
+					t.Errorf("репозиторий вызван %d раз, want 0", len(filters))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetSongs() error = %v", err)
+			}
+			if notice.DeepOffset() != tt.wantDeepOffset {
+				t.Errorf("DeepOffset() = %v, want %v", notice.DeepOffset(), tt.wantDeepOffset)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNormalizeNameForSimilarity(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"регистр и знаки препинания", "Hysteria!", "hysteria"},
+		{"слова сортируются", "Beatles, The", "beatles the"},
+		{"артикль в начале", "The Beatles", "beatles the"},
+		{"цифры сохраняются", "Blink-182", "182 blink"},
+		{"кириллица", "Кино.", "кино"},
+		{"только знаки препинания", "...", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeName(tt.value); got != tt.want {
+				t.Errorf("normalizeName(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJaroWinkler(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want float64
+	}{
+		{"одинаковые строки", "hysteria", "hysteria", 1},
+		{"обе строки пустые", "", "", 1},
+		{"одна строка пустая", "muse", "", 0},
+		{"нет общих символов", "abc", "xyz", 0},
+		{"перестановка соседних символов", "martha", "marhta", 0.9611},
+		{"общий префикс из одного символа", "dwayne", "duane", 0.84},
+		{"разная длина", "dixon", "dicksonx", 0.8133},
+		{"кириллица по символам, а не байтам", "кино", "кина", 0.8833},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := jaroWinkler(tt.a, tt.b)
+			if math.Abs(got-tt.want) > 0.0001 {
+				t.Errorf("jaroWinkler(%q, %q) = %.4f, want %.4f", tt.a, tt.b, got, tt.want)
+			}
+			if reversed := jaroWinkler(tt.b, tt.a); math.Abs(reversed-got) > 1e-9 {
+				t.Errorf("jaroWinkler(%q, %q) = %.4f, не равно обратному порядку %.4f", tt.b, tt.a, reversed, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"song-library/internal/model"
+	"song-library/pkg/logger"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// snapshotFilePrefix префикс имени файла снимка
+	snapshotFilePrefix = "songs-"
+	// snapshotFileSuffix расширение файла снимка: одна строка JSON на песню
+	snapshotFileSuffix = ".jsonl"
+	// snapshotTimeLayout формат времени в имени файла снимка
+	snapshotTimeLayout = "20060102T150405Z"
+)
+
+// LibraryExporter источник песен для снимка библиотеки
+type LibraryExporter interface {
+	ExportLibrary(ctx context.Context, fn func(song *model.Song) error) error
+}
+
+// SnapshotConfig настройки плановых снимков библиотеки
+type SnapshotConfig struct {
+	// Dir каталог для файлов снимков
+	Dir string
+	// Interval период между снимками
+	Interval time.Duration
+	// RetentionDays количество дней, после которых снимки удаляются
+	RetentionDays int
+}
+
+// SnapshotScheduler периодически сохраняет библиотеку в файл JSONL в формате переносимых документов песен
+// и удаляет устаревшие снимки. Если предыдущий снимок еще создается, очередной запуск пропускается.
+type SnapshotScheduler struct {
+	exporter LibraryExporter
+	cfg      SnapshotConfig
+	logger   *logger.Logger
+
+	mu      sync.Mutex
+	status  model.SnapshotStatus
+	running sync.WaitGroup
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// NewSnapshotScheduler создает планировщик снимков библиотеки
+func NewSnapshotScheduler(exporter LibraryExporter, cfg SnapshotConfig, logger *logger.Logger) *SnapshotScheduler {
+	return &SnapshotScheduler{
+		exporter: exporter,
+		cfg:      cfg,
+		logger:   logger,
+		status:   model.SnapshotStatus{Enabled: true, Interval: cfg.Interval.String()},
+	}
+}
+
+// Start запускает планировщик в отдельной горутине. Первый снимок создается через Interval после запуска.
+func (s *SnapshotScheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.done = make(chan struct{})
+	go s.run(ctx)
+}
+
+// Shutdown останавливает планировщик и ожидает завершения текущего снимка или истечения ctx.
+// Незавершенный снимок прерывается, и его временный файл удаляется.
+func (s *SnapshotScheduler) Shutdown(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.cancel()
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("снимок библиотеки не завершился: %w", ctx.Err())
+	}
+}
+
+// Status возвращает состояние последнего снимка
+func (s *SnapshotScheduler) Status() model.SnapshotStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.status
+}
+
+// run запускает снимки по таймеру до отмены ctx
+func (s *SnapshotScheduler) run(ctx context.Context) {
+	defer close(s.done)
+	defer s.running.Wait()
+
+	s.logger.Info("Запуск плановых снимков библиотеки", "interval", s.cfg.Interval.String(), "dir", s.cfg.Dir,
+		"retention_days", s.cfg.RetentionDays)
+
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Плановые снимки библиотеки остановлены")
+			return
+		case <-ticker.C:
+		}
+
+		if !s.begin() {
+			s.logger.Warn("Предыдущий снимок библиотеки еще создается, запуск пропущен")
+			continue
+		}
+		s.running.Add(1)
+		go func() {
+			defer s.running.Done()
+			s.snapshot(ctx)
+		}()
+	}
+}
+
+// begin отмечает начало снимка; возвращает false, если предыдущий снимок еще не завершен
+func (s *SnapshotScheduler) begin() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.status.Running {
+		s.status.Skipped++
+		return false
+	}
+	now := time.Now().UTC()
+	s.status.Running = true
+	s.status.LastRunAt = &now
+	return true
+}
+
+// snapshot создает снимок, удаляет устаревшие и записывает результат в состояние
+func (s *SnapshotScheduler) snapshot(ctx context.Context) {
+	started := time.Now().UTC()
+	name, count, err := s.writeSnapshot(ctx, started)
+	if err == nil {
+		err = s.prune(started)
+	}
+	duration := time.Since(started)
+
+	s.mu.Lock()
+	s.status.Running = false
+	s.status.LastDuration = duration.String()
+	s.status.LastError = ""
+	if err != nil {
+		s.status.LastError = err.Error()
+	}
+	if name != "" {
+		finished := started.Add(duration)
+		s.status.LastSuccessAt = &finished
+		s.status.LastFile = name
+		s.status.LastCount = count
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		s.logger.Error("Ошибка планового снимка библиотеки", "error", err, "duration", duration.String())
+		return
+	}
+	s.logger.Info("Плановый снимок библиотеки создан", "file", name, "count", count, "duration", duration.String())
+}
+
+// writeSnapshot записывает библиотеку во временный файл и переименовывает его после успешной записи,
+// чтобы в каталоге не оставалось неполных снимков. Возвращает имя файла и количество песен.
+func (s *SnapshotScheduler) writeSnapshot(ctx context.Context, at time.Time) (string, int, error) {
+	if err := os.MkdirAll(s.cfg.Dir, 0o755); err != nil {
+		return "", 0, fmt.Errorf("ошибка создания каталога снимков: %w", err)
+	}
+
+	name := snapshotFilePrefix + at.Format(snapshotTimeLayout) + snapshotFileSuffix
+	path := filepath.Join(s.cfg.Dir, name)
+	tmp, err := os.CreateTemp(s.cfg.Dir, name+".*.tmp")
+	if err != nil {
+		return "", 0, fmt.Errorf("ошибка создания файла снимка: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	w := bufio.NewWriter(tmp)
+	encoder := json.NewEncoder(w)
+	count := 0
+	err = s.exporter.ExportLibrary(ctx, func(song *model.Song) error {
+		count++
+		return encoder.Encode(songDocument(song))
+	})
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("ошибка записи снимка: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return "", 0, fmt.Errorf("ошибка записи снимка: %w", err)
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return "", 0, fmt.Errorf("ошибка сохранения снимка: %w", err)
+	}
+	return name, count, nil
+}
+
+// prune удаляет снимки, созданные раньше чем за RetentionDays дней до now.
+// Время снимка берется из имени файла; файлы с другими именами не затрагиваются.
+func (s *SnapshotScheduler) prune(now time.Time) error {
+	entries, err := os.ReadDir(s.cfg.Dir)
+	if err != nil {
+		return fmt.Errorf("ошибка чтения каталога снимков: %w", err)
+	}
+
+	cutoff := now.AddDate(0, 0, -s.cfg.RetentionDays)
+	var errs []error
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, snapshotFilePrefix) || !strings.HasSuffix(name, snapshotFileSuffix) {
+			continue
+		}
+		created, err := time.Parse(snapshotTimeLayout, strings.TrimSuffix(strings.TrimPrefix(name, snapshotFilePrefix), snapshotFileSuffix))
+		if err != nil || !created.Before(cutoff) {
+			continue
+		}
+		if err = os.Remove(filepath.Join(s.cfg.Dir, name)); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		s.logger.Info("Устаревший снимок библиотеки удален", "file", name)
+	}
+	if err = errors.Join(errs...); err != nil {
+		return fmt.Errorf("ошибка удаления устаревших снимков: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	// snippetLength примерная длина фрагмента текста вокруг совпадения в символах
+	snippetLength = 150
+	// snippetMatchStart и snippetMatchEnd обрамляют совпадение во фрагменте
+	snippetMatchStart = "<mark>"
+	snippetMatchEnd   = "</mark>"
+	// snippetEllipsis обозначает, что фрагмент обрезан
+	snippetEllipsis = "…"
+)
+
+// TextSnippet возвращает фрагмент текста около snippetLength символов вокруг первого вхождения query без учета регистра.
+// Фрагмент экранируется для HTML, совпадение обрамляется тегом mark. Текст режется только по границам символов.
+// Если совпадения нет, возвращается пустая строка
+func TextSnippet(text, query string) string {
+	if query == "" {
+		return ""
+	}
+
+	runes := []rune(text)
+	start := indexFold(runes, []rune(query))
+	if start < 0 {
+		return ""
+	}
+	end := start + len([]rune(query))
+
+	around := max(snippetLength-(end-start), 0) / 2
+	from := max(start-around, 0)
+	to := min(end+around, len(runes))
+	// Контекст, не поместившийся с одной стороны совпадения, отдается другой стороне
+	if shortage := around - (start - from); shortage > 0 {
+		to = min(to+shortage, len(runes))
+	}
+	if shortage := around - (to - end); shortage > 0 {
+		from = max(from-shortage, 0)
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString(snippetEllipsis)
+	}
+	b.WriteString(html.EscapeString(string(runes[from:start])))
+	b.WriteString(snippetMatchStart)
+	b.WriteString(html.EscapeString(string(runes[start:end])))
+	b.WriteString(snippetMatchEnd)
+	b.WriteString(html.EscapeString(string(runes[end:to])))
+	if to < len(runes) {
+		b.WriteString(snippetEllipsis)
+	}
+	return b.String()
+}
+
+// indexFold возвращает индекс первого вхождения needle в runes без учета регистра или -1.
+// Сравнение идет по символам, поэтому индекс не зависит от длины символов в байтах после смены регистра
+func indexFold(runes, needle []rune) int {
+	if len(needle) == 0 {
+		return -1
+	}
+	for i := 0; i+len(needle) <= len(runes); i++ {
+		matched := true
+		for j, r := range needle {
+			if unicode.ToLower(runes[i+j]) != unicode.ToLower(r) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return i
+		}
+	}
+	return -1
+}
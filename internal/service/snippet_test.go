@@ -0,0 +1,102 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTextSnippet(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		query string
+		want  string
+	}{
+		{"совпадение без учета регистра", "Paranoia is in bloom", "BLOOM", "Paranoia is in <mark>bloom</mark>"},
+		{"исходный регистр сохраняется", "Paranoia is in bloom", "paranoia", "<mark>Paranoia</mark> is in bloom"},
+		{"первое вхождение", "bloom, bloom", "bloom", "<mark>bloom</mark>, bloom"},
+		{"кириллица", "Группа крови на рукаве", "КРОВИ", "Группа <mark>крови</mark> на рукаве"},
+		{"HTML экранируется", `<b>"Tom" & Jerry</b>`, "tom", `&lt;b&gt;&#34;<mark>Tom</mark>&#34; &amp; Jerry&lt;/b&gt;`},
+		{"нет совпадения", "Paranoia is in bloom", "uprising", ""},
+		{"пустой запрос", "Paranoia is in bloom", "", ""},
+		{"пустой текст", "", "bloom", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TextSnippet(tt.text, tt.query); got != tt.want {
+				t.Errorf("TextSnippet(%q, %q) = %q, want %q", tt.text, tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextSnippetTrimsLongText(t *testing.T) {
+	before := strings.Repeat("а", 300)
+	after := strings.Repeat("б", 300)
+
+	tests := []struct {
+		name       string
+		text       string
+		wantPrefix bool
+		wantSuffix bool
+		wantBefore int
+		wantAfter  int
+	}{
+		{"совпадение в середине", before + "match" + after, true, true, 72, 72},
+		// Контекст, которого нет перед совпадением, переходит в текст после него
+		{"совпадение в начале", "match" + after, false, true, 0, 144},
+		{"совпадение в конце", before + "match", true, false, 144, 0},
+		{"короткий текст целиком", "а match б", false, false, 2, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TextSnippet(tt.text, "MATCH")
+
+			if !utf8.ValidString(got) {
+				t.Fatalf("TextSnippet() вернул некорректный UTF-8: %q", got)
+			}
+			if strings.HasPrefix(got, snippetEllipsis) != tt.wantPrefix {
+				t.Errorf("многоточие в начале = %v, want %v: %q", !tt.wantPrefix, tt.wantPrefix, got)
+			}
+			if strings.HasSuffix(got, snippetEllipsis) != tt.wantSuffix {
+				t.Errorf("многоточие в конце = %v, want %v: %q", !tt.wantSuffix, tt.wantSuffix, got)
+			}
+
+			body := strings.TrimSuffix(strings.TrimPrefix(got, snippetEllipsis), snippetEllipsis)
+			head, tail, ok := strings.Cut(body, snippetMatchStart+"match"+snippetMatchEnd)
+			if !ok {
+				t.Fatalf("TextSnippet() = %q, совпадение не отмечено", got)
+			}
+			if gotBefore, gotAfter := utf8.RuneCountInString(head), utf8.RuneCountInString(tail); gotBefore != tt.wantBefore || gotAfter != tt.wantAfter {
+				t.Errorf("символов до и после совпадения = %d, %d, want %d, %d", gotBefore, gotAfter, tt.wantBefore, tt.wantAfter)
+			}
+		})
+	}
+}
+
+func TestIndexFold(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		needle string
+		want   int
+	}{
+		{"в начале", "Hysteria", "hys", 0},
+		{"в конце", "Hysteria", "RIA", 5},
+		{"индекс в символах, а не байтах", "Ёлка и ёж", "ЁЖ", 7},
+		{"нет вхождения", "Hysteria", "muse", -1},
+		{"иголка длиннее текста", "Muse", "Muses", -1},
+		{"пустая иголка", "Muse", "", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := indexFold([]rune(tt.text), []rune(tt.needle)); got != tt.want {
+				t.Errorf("indexFold(%q, %q) = %d, want %d", tt.text, tt.needle, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"song-library/internal/model"
+)
+
+var (
+	inProcessCacheHits = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "in_process_cache_hits_total",
+		Help: "Количество песен, найденных в кэше процесса",
+	})
+	inProcessCacheMisses = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "in_process_cache_misses_total",
+		Help: "Количество песен, не найденных в кэше процесса",
+	})
+)
+
+// cachedSong возвращает копию песни из кэша процесса, если кэш включен и песня в нем есть
+func (s *SongService) cachedSong(id int64) (*model.Song, bool) {
+	if s.songCache == nil {
+		return nil, false
+	}
+
+	song, ok := s.songCache.Get(id)
+	if !ok {
+		inProcessCacheMisses.Inc()
+		return nil, false
+	}
+	inProcessCacheHits.Inc()
+	return &song, true
+}
+
+// cacheSong сохраняет копию песни в кэш процесса
+func (s *SongService) cacheSong(song *model.Song) {
+	if s.songCache != nil {
+		s.songCache.Put(song.ID, *song)
+	}
+}
+
+// invalidateSongs удаляет песни из кэша процесса
+func (s *SongService) invalidateSongs(ids ...int64) {
+	if s.songCache == nil {
+		return
+	}
+	for _, id := range ids {
+		s.songCache.Delete(id)
+	}
+}
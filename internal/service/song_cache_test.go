@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"song-library/internal/model"
+	"testing"
+	"time"
+)
+
+// laggingReplicaRepository отдает из GetSongByID устаревшую версию песни, как отстающая реплика
+type laggingReplicaRepository struct {
+	*memoryRepository
+	stale *model.Song
+}
+
+// GetSongByID возвращает устаревшую копию песни
+func (r *laggingReplicaRepository) GetSongByID(context.Context, int64) (*model.Song, error) {
+	return r.stale.Clone(), nil
+}
+
+func TestGetSongByIDFillsCacheFromPrimary(t *testing.T) {
+	tests := []struct {
+		name      string
+		cacheSize int
+		wantText  string
+	}{
+		{"кэш включен: чтение из основной базы", 10, "fresh"},
+		{"кэш выключен: чтение из реплики", 0, "stale"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := newMemoryRepository()
+			primary.put(&model.Song{ID: 1, Group: "Muse", Song: "Hysteria", Text: "fresh"})
+			repo := &laggingReplicaRepository{
+				memoryRepository: primary,
+				stale:            &model.Song{ID: 1, Group: "Muse", Song: "Hysteria", Text: "stale"},
+			}
+			svc := NewSongService(repo, nil, nil, nopEventLogger{}, ServiceConfig{
+				InProcessCacheSize: tt.cacheSize,
+				InProcessCacheTTL:  time.Minute,
+			}, newTestLogger())
+
+			// Второе чтение при включенном кэше берется из кэша и не должно вернуть устаревшую версию
+			for range 2 {
+				song, err := svc.GetSongByID(context.Background(), 1)
+				if err != nil {
+					t.Fatalf("GetSongByID() error = %v", err)
+				}
+				if song.Text != tt.wantText {
+					t.Errorf("GetSongByID().Text = %q, want %q", song.Text, tt.wantText)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateSongRefreshesCacheFromPrimary(t *testing.T) {
+	primary := newMemoryRepository()
+	primary.put(&model.Song{ID: 1, Group: "Muse", Song: "Hysteria", Text: "old"})
+	repo := &laggingReplicaRepository{
+		memoryRepository: primary,
+		stale:            &model.Song{ID: 1, Group: "Muse", Song: "Hysteria", Text: "old"},
+	}
+	svc := NewSongService(repo, nil, nil, nopEventLogger{}, ServiceConfig{
+		InProcessCacheSize: 10,
+		InProcessCacheTTL:  time.Minute,
+	}, newTestLogger())
+
+	if _, err := svc.GetSongByID(context.Background(), 1); err != nil {
+		t.Fatalf("GetSongByID() error = %v", err)
+	}
+	if _, _, err := svc.UpdateSong(context.Background(), &model.Song{ID: 1, Group: "Muse", Song: "Hysteria", Text: "new"}, model.UpdateOptions{}); err != nil {
+		t.Fatalf("UpdateSong() error = %v", err)
+	}
+
+	song, err := svc.GetSongByID(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("GetSongByID() error = %v", err)
+	}
+	if song.Text != "new" {
+		t.Errorf("GetSongByID().Text после обновления = %q, want %q", song.Text, "new")
+	}
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"song-library/internal/model"
+	"testing"
+	"time"
+)
+
+// exportedTestSong возвращает песню, заполненную всеми полями переносимого документа
+func exportedTestSong() *model.Song {
+	duration, bpm, copyright := 366, int16(120), "© 2006 Warner Music UK Limited"
+	fetchedAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	return &model.Song{
+		ID:              7,
+		PublicID:        "0b6a4f1e-8c2d-4f7a-9e3b-5d1c2a7f9e40",
+		Group:           "Muse",
+		Song:            "Knights of Cydonia",
+		ReleaseDate:     "16.07.2006",
+		Text:            "Come ride with me\n\nThrough the veins of history",
+		Link:            "https://www.youtube.com/watch?v=G_sBOsh-vyI",
+		Duration:        &duration,
+		BPM:             &bpm,
+		Copyright:       &copyright,
+		FeaturedArtists: model.Artists{"Matthew Bellamy", "Dominic Howard"},
+		Provenance: model.Provenance{
+			model.FieldReleaseDate: {Provider: "external_api", FetchedAt: fetchedAt},
+			model.FieldText:        {Provider: "external_api", FetchedAt: fetchedAt},
+		},
+	}
+}
+
+func TestExportImportSongRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryRepository()
+	original := exportedTestSong()
+	repo.put(original)
+	// Без клиента внешнего API любое обращение к нему завершится паникой
+	svc := NewSongService(repo, nil, nil, nopEventLogger{}, ServiceConfig{}, newTestLogger())
+
+	exported, err := svc.ExportSong(ctx, original.ID)
+	if err != nil {
+		t.Fatalf("ExportSong() error = %v", err)
+	}
+	// Документ переносится между инсталляциями в JSON
+	data, err := json.Marshal(exported)
+	if err != nil {
+		t.Fatalf("ошибка кодирования документа: %v", err)
+	}
+	var document model.SongDocument
+	if err = json.Unmarshal(data, &document); err != nil {
+		t.Fatalf("ошибка разбора документа: %v", err)
+	}
+
+	// Импорт в другую инсталляцию
+	target := newMemoryRepository()
+	svc = NewSongService(target, nil, nil, nopEventLogger{}, ServiceConfig{}, newTestLogger())
+	ref, err := svc.ImportSong(ctx, document)
+	if err != nil {
+		t.Fatalf("ImportSong() error = %v", err)
+	}
+
+	imported := target.activeSong(ref.ID)
+	if imported == nil {
+		t.Fatalf("песня %d не сохранена", ref.ID)
+	}
+	if imported.PublicID != ref.PublicID || imported.PublicID == original.PublicID {
+		t.Errorf("PublicID = %q, ref = %q, want новый идентификатор", imported.PublicID, ref.PublicID)
+	}
+	fields := []struct {
+		name      string
+		got, want any
+	}{
+		{"Group", imported.Group, original.Group},
+		{"Song", imported.Song, original.Song},
+		{"ReleaseDate", imported.ReleaseDate, original.ReleaseDate},
+		{"Text", imported.Text, original.Text},
+		{"Link", imported.Link, original.Link},
+		{"Duration", imported.Duration, original.Duration},
+		{"BPM", imported.BPM, original.BPM},
+		{"Copyright", imported.Copyright, original.Copyright},
+		{"FeaturedArtists", imported.FeaturedArtists, original.FeaturedArtists},
+		{"Provenance", imported.Provenance, original.Provenance},
+	}
+	for _, field := range fields {
+		if !reflect.DeepEqual(field.got, field.want) {
+			t.Errorf("%s = %v, want %v", field.name, field.got, field.want)
+		}
+	}
+
+	// Повторный импорт того же документа конфликтует с уже импортированной песней
+	if _, err = svc.ImportSong(ctx, document); !errors.Is(err, model.ErrSongAlreadyExists) {
+		t.Errorf("повторный ImportSong() error = %v, want %v", err, model.ErrSongAlreadyExists)
+	}
+	if got := target.createCount(); got != 2 {
+		t.Errorf("CreateSong вызван %d раз, want 2", got)
+	}
+}
+
+func TestImportSongRejectsInvalidDocument(t *testing.T) {
+	tooManyArtists := make(model.Artists, model.MaxFeaturedArtists+1)
+	for i := range tooManyArtists {
+		tooManyArtists[i] = string(rune('A' + i))
+	}
+
+	tests := []struct {
+		name   string
+		modify func(document *model.SongDocument)
+	}{
+		{"нулевая версия формата", func(document *model.SongDocument) { document.FormatVersion = 0 }},
+		{"версия формата новее поддерживаемой", func(document *model.SongDocument) {
+			document.FormatVersion = model.SongDocumentFormatVersion + 1
+		}},
+		{"слишком много исполнителей", func(document *model.SongDocument) { document.FeaturedArtists = tooManyArtists }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			svc := NewSongService(repo, nil, nil, nopEventLogger{}, ServiceConfig{}, newTestLogger())
+			document := songDocument(exportedTestSong())
+			tt.modify(&document)
+
+			if _, err := svc.ImportSong(context.Background(), document); !errors.Is(err, model.ErrValidation) {
+				t.Errorf("ImportSong() error = %v, want %v", err, model.ErrValidation)
+			}
+			if got := repo.createCount(); got != 0 {
+				t.Errorf("CreateSong вызван %d раз, want 0", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"song-library/internal/model"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// blockingReadRepository задерживает GetSongByID до закрытия release и считает чтения и учтенные обращения
+type blockingReadRepository struct {
+	*memoryRepository
+
+	entered  chan struct{}
+	release  chan struct{}
+	err      error
+	reads    atomic.Int32
+	accesses atomic.Int32
+	// canceled признак отмены контекста, с которым выполнялось последнее чтение
+	canceled atomic.Bool
+}
+
+// newBlockingReadRepository создает репозиторий с песней 1
+func newBlockingReadRepository() *blockingReadRepository {
+	repo := &blockingReadRepository{
+		memoryRepository: newMemoryRepository(),
+		entered:          make(chan struct{}, 1),
+		release:          make(chan struct{}),
+	}
+	repo.put(&model.Song{ID: 1, Group: "Muse", Song: "Hysteria", Text: "It's bugging me", Provenance: model.Provenance{
+		model.FieldText: {Provider: model.ProviderExternalAPI},
+	}})
+	return repo
+}
+
+// GetSongByID сообщает о начале чтения и ожидает закрытия release
+func (r *blockingReadRepository) GetSongByID(ctx context.Context, id int64) (*model.Song, error) {
+	r.reads.Add(1)
+	select {
+	case r.entered <- struct{}{}:
+	default:
+	}
+	<-r.release
+	r.canceled.Store(ctx.Err() != nil)
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.memoryRepository.GetSongByID(ctx, id)
+}
+
+// RecordAccess считает учтенные сервисом обращения
+func (r *blockingReadRepository) RecordAccess(context.Context, int64, string) {
+	r.accesses.Add(1)
+}
+
+func TestGetSongByIDCoalescesReads(t *testing.T) {
+	const callers = 5
+
+	repo := newBlockingReadRepository()
+	svc := NewSongService(repo, nil, nil, nopEventLogger{}, ServiceConfig{}, newTestLogger())
+
+	songs := make([]*model.Song, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			songs[i], errs[i] = svc.GetSongByID(context.Background(), 1)
+		}()
+	}
+	<-repo.entered
+	// Остальные вызывающие успевают присоединиться к выполняющемуся чтению
+	time.Sleep(50 * time.Millisecond)
+	close(repo.release)
+	wg.Wait()
+
+	if got := repo.reads.Load(); got != 1 {
+		t.Errorf("чтений из репозитория = %d, want 1", got)
+	}
+	// Первое обращение учитывает репозиторий, остальные — сервис
+	if got := repo.accesses.Load(); got != callers-1 {
+		t.Errorf("обращений, учтенных сервисом = %d, want %d", got, callers-1)
+	}
+	for i := range callers {
+		if errs[i] != nil {
+			t.Fatalf("GetSongByID() error = %v", errs[i])
+		}
+	}
+
+	// Каждый вызывающий получает отдельную копию, включая вложенные карты
+	songs[0].Text = "changed"
+	songs[0].Provenance[model.FieldText] = model.FieldSource{Provider: "manual"}
+	for i := 1; i < callers; i++ {
+		if songs[i] == songs[0] {
+			t.Fatalf("вызывающие %d и 0 получили один указатель", i)
+		}
+		if songs[i].Text != "It's bugging me" || songs[i].Provenance[model.FieldText].Provider != model.ProviderExternalAPI {
+			t.Errorf("изменение копии вызывающего 0 видно вызывающему %d: %+v", i, songs[i])
+		}
+	}
+}
+
+func TestGetSongByIDDoesNotRememberErrors(t *testing.T) {
+	repo := newBlockingReadRepository()
+	close(repo.release)
+	repo.err = errors.New("connection refused")
+	svc := NewSongService(repo, nil, nil, nopEventLogger{}, ServiceConfig{}, newTestLogger())
+
+	if _, err := svc.GetSongByID(context.Background(), 1); !errors.Is(err, repo.err) {
+		t.Fatalf("GetSongByID() error = %v, want %v", err, repo.err)
+	}
+	repo.err = nil
+	if _, err := svc.GetSongByID(context.Background(), 1); err != nil {
+		t.Fatalf("повторный GetSongByID() error = %v", err)
+	}
+	if got := repo.reads.Load(); got != 2 {
+		t.Errorf("чтений из репозитория = %d, want 2", got)
+	}
+}
+
+func TestGetSongByIDIgnoresLeaderCancellation(t *testing.T) {
+	repo := newBlockingReadRepository()
+	svc := NewSongService(repo, nil, nil, nopEventLogger{}, ServiceConfig{}, newTestLogger())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := svc.GetSongByID(ctx, 1)
+		done <- err
+	}()
+	<-repo.entered
+	// Отключение первого клиента не прерывает чтение, к которому присоединяются другие
+	cancel()
+	close(repo.release)
+
+	if err := <-done; err != nil {
+		t.Errorf("GetSongByID() error = %v", err)
+	}
+	if repo.canceled.Load() {
+		t.Error("контекст чтения из репозитория отменен")
+	}
+}
+
+// slowReadRepository считает чтения песни и задерживает каждое, как запрос к базе
+type slowReadRepository struct {
+	*memoryRepository
+	reads atomic.Int64
+}
+
+// GetSongByID считает чтение и возвращает песню после задержки
+func (r *slowReadRepository) GetSongByID(ctx context.Context, id int64) (*model.Song, error) {
+	r.reads.Add(1)
+	time.Sleep(200 * time.Microsecond)
+	return r.memoryRepository.GetSongByID(ctx, id)
+}
+
+// BenchmarkGetSongByIDConcurrent выполняет параллельные чтения одной песни и сообщает
+// среднее количество запросов к репозиторию на одно чтение (reads/op); без объединения оно равно 1
+func BenchmarkGetSongByIDConcurrent(b *testing.B) {
+	repo := &slowReadRepository{memoryRepository: newMemoryRepository()}
+	repo.put(&model.Song{ID: 1, Group: "Muse", Song: "Hysteria", Text: "It's bugging me"})
+	svc := NewSongService(repo, nil, nil, nopEventLogger{}, ServiceConfig{}, newTestLogger())
+
+	b.SetParallelism(16)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := svc.GetSongByID(context.Background(), 1); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+	b.ReportMetric(float64(repo.reads.Load())/float64(b.N), "reads/op")
+}
@@ -2,43 +2,243 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"golang.org/x/sync/singleflight"
+	"slices"
 	"song-library/internal/model"
+	"song-library/pkg/cache"
 	"song-library/pkg/logger"
+	"song-library/pkg/pagination"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 )
 
 // SongRepository интерфейс репозитория песен
 type SongRepository interface {
 	CreateSong(ctx context.Context, song *model.Song) (int64, error)
+	BulkInsertSongs(ctx context.Context, songs []*model.Song) (int64, error)
 	GetSongs(ctx context.Context, filter model.SongFilter) ([]*model.Song, error)
 	GetSongByID(ctx context.Context, id int64) (*model.Song, error)
+	GetSongByIDPrimary(ctx context.Context, id int64) (*model.Song, error)
+	GetSongsByIDs(ctx context.Context, ids []int64) ([]*model.Song, error)
+	GetSongSummaries(ctx context.Context, limit int) ([]*model.Song, error)
+	IterateSongs(ctx context.Context, fn func(song *model.Song) error) error
+	IterateGroupSongs(ctx context.Context, group string, fn func(song *model.Song) error) error
+	GetSongByIDForUpdate(ctx context.Context, id int64) (*model.Song, error)
+	GetSongIDByPublicID(ctx context.Context, publicID string) (int64, error)
 	UpdateSong(ctx context.Context, song *model.Song) error
+	UpdateSongDuration(ctx context.Context, id int64, duration *int) error
+	UpdateSongBPM(ctx context.Context, id int64, bpm *int16) error
+	UpdateSongChart(ctx context.Context, id int64, position int16, date string) error
+	GetChartToppers(ctx context.Context, date string) ([]*model.Song, error)
+	UpdateSongCopyright(ctx context.Context, id int64, copyright *string) error
+	UpdateFeaturedArtists(ctx context.Context, id int64, artists model.Artists) error
+	GetTotalDuration(ctx context.Context, group string) (int64, error)
 	DeleteSong(ctx context.Context, id int64) error
-	GetSongVerses(ctx context.Context, id int64, pagination model.VersesPagination) ([]string, error)
+	MarkSongMerged(ctx context.Context, id, targetID int64) error
+	GetDeletedSongs(ctx context.Context, page model.Pagination) ([]*model.Song, error)
+	GetDeletedSongByIDForUpdate(ctx context.Context, id int64) (*model.Song, error)
+	FindActiveSongID(ctx context.Context, group, song string) (int64, error)
+	RestoreSong(ctx context.Context, id int64) error
+	FindGroupRenameConflicts(ctx context.Context, oldName, newName string) ([]model.RenameConflict, error)
+	RenameGroup(ctx context.Context, oldName, newName string, excludeIDs []int64) ([]int64, error)
+	GetSongVerses(ctx context.Context, id int64, pagination model.VersesPagination) ([]string, int, error)
+	GetAccessLog(ctx context.Context, songID int64, from, to *time.Time) ([]model.AccessLogEntry, error)
+	GetMostAccessedSongs(ctx context.Context, since time.Time, limit int) ([]*model.MostAccessedSong, error)
+	GetTrendingSongs(ctx context.Context, period string, limit int) ([]*model.Song, error)
+	GetTempoDistribution(ctx context.Context) ([]model.TempoBucket, error)
+	RecordAccess(ctx context.Context, songID int64, action string)
+	AddVerseBookmark(ctx context.Context, clientID string, songID int64, position int, note string) error
+	RemoveVerseBookmark(ctx context.Context, clientID string, songID int64, position int) (bool, error)
+	ListVerseBookmarks(ctx context.Context, clientID string) ([]model.VerseBookmark, error)
+	GetGroupInfo(ctx context.Context, name string) (*model.GroupInfo, error)
+	UpsertGroupInfo(ctx context.Context, info *model.GroupInfo) (*model.GroupInfo, bool, error)
+	RenameGroupInfo(ctx context.Context, oldName, newName string) error
+	GetGroupSongs(ctx context.Context, group, sortBy string, page model.Pagination) ([]*model.Song, int64, error)
+	GetTopSongsByGroup(ctx context.Context, group, metric string, limit int) ([]*model.Song, error)
+	GetGroupStats(ctx context.Context, group string) (*model.GroupStats, error)
+	PurgeDeletedSongs(ctx context.Context, cutoff time.Time, limit int, keepHistory bool) (int64, error)
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// ServiceConfig настройки сервиса песен
+type ServiceConfig struct {
+	// ExternalAPIBudget максимальное время ожидания внешнего API при создании песни
+	ExternalAPIBudget time.Duration
+	// MaxTextLength максимальная длина текста песни в байтах (0 — без ограничения)
+	MaxTextLength int
+	// MaxLinkLength максимальная длина ссылки в байтах (0 — без ограничения)
+	MaxLinkLength int
+	// StrictUTF8 отклоняет данные с некорректным UTF-8 вместо замены некорректных последовательностей
+	StrictUTF8 bool
+	// DefaultSongsPageSize размер страницы списка песен, если клиент его не указал
+	DefaultSongsPageSize int
+	// MaxSongsPageSize максимальный размер страницы списка песен
+	MaxSongsPageSize int
+	// DefaultVersesPageSize размер страницы куплетов, если клиент его не указал
+	DefaultVersesPageSize int
+	// MaxVersesPageSize максимальный размер страницы куплетов
+	MaxVersesPageSize int
+	// PaginationWarnOffset смещение списка песен, выше которого ответ помечается предупреждением (0 — без предупреждения)
+	PaginationWarnOffset int
+	// MaxPaginationOffset максимальное смещение списка песен (0 — без ограничения)
+	MaxPaginationOffset int
+	// MaxSongsForDuplicateCheck максимальный размер библиотеки для поиска дубликатов
+	MaxSongsForDuplicateCheck int
+	// InProcessCacheSize количество песен в кэше процесса для GetSongByID (0 — кэш выключен)
+	InProcessCacheSize int
+	// InProcessCacheTTL время хранения песни в кэше процесса
+	InProcessCacheTTL time.Duration
+	// TrashPurgeBatchSize количество песен, удаляемых из корзины одним запросом
+	TrashPurgeBatchSize int
+	// TrashPurgeMaxPerRun максимальное количество песен, удаляемых из корзины за один вызов
+	TrashPurgeMaxPerRun int
 }
 
 // SongService сервис для работы с песнями
 type SongService struct {
 	repo      SongRepository
 	apiClient *ExternalAPIClient
+	analyzer  *TextAnalyzer
+	events    EventLogger
+	cfg       ServiceConfig
 	logger    *logger.Logger
+	// createsMu защищает creates
+	createsMu sync.Mutex
+	// creates выполняющиеся создания песен по ключу createKey
+	creates map[string]*createCall
+	// reads объединяет одновременные чтения одной песни по идентификатору
+	reads singleflight.Group
+	// songCache кэш песен в памяти процесса; nil, если кэш выключен
+	songCache *cache.LRUCache[int64, model.Song]
+	// trendingCache кэш списков трендовых песен по периоду и количеству
+	trendingCache *cache.LRUCache[string, []*model.Song]
 }
 
 // NewSongService создает новый сервис для работы с песнями
-func NewSongService(repo SongRepository, apiClient *ExternalAPIClient, logger *logger.Logger) *SongService {
-	return &SongService{repo: repo, apiClient: apiClient, logger: logger}
+func NewSongService(repo SongRepository, apiClient *ExternalAPIClient, analyzer *TextAnalyzer, events EventLogger, cfg ServiceConfig, logger *logger.Logger) *SongService {
+	s := &SongService{repo: repo, apiClient: apiClient, analyzer: analyzer, events: events, cfg: cfg, logger: logger}
+	s.creates = make(map[string]*createCall)
+	s.trendingCache = cache.NewLRUCache[string, []*model.Song](trendingCacheSize, trendingCacheTTL)
+	if cfg.InProcessCacheSize > 0 {
+		s.songCache = cache.NewLRUCache[int64, model.Song](cfg.InProcessCacheSize, cfg.InProcessCacheTTL)
+	}
+	return s
 }
 
-// CreateSong создает новую песню
-func (s *SongService) CreateSong(ctx context.Context, input model.SongInput) (int64, error) {
-	log := s.logger.WithContext(ctx)
+// createKey формирует ключ для объединения одновременных запросов на создание одной и той же песни.
+// Ожидает имена после NormalizeName; регистр не сворачивается, поэтому «Muse» и «muse» — разные песни.
+func createKey(input model.SongInput) string {
+	return input.Group + "\x00" + input.Song
+}
+
+// createCall общее создание песни, которого ожидают один или несколько запросов
+type createCall struct {
+	done   chan struct{}
+	ref    model.SongRef
+	err    error
+	cancel context.CancelFunc
+	// waiters количество запросов, ожидающих результата; защищено SongService.createsMu
+	waiters int
+}
+
+// CreateSong создает новую песню.
+// Одновременные запросы на создание одной и той же песни объединяются: внешний API
+// и вставка в базу выполняются один раз, а все вызывающие получают одни и те же идентификаторы.
+// Общий вызов ограничен ExternalAPIBudget и отменяется, когда его перестает ожидать последний
+// вызывающий, поэтому отключение клиента или истечение его дедлайна сразу прерывает запрос к внешнему API.
+func (s *SongService) CreateSong(ctx context.Context, input model.SongInput) (model.SongRef, error) {
+	log := s.logger.WithFields(ctx, "group", input.Group, "song", input.Song)
+
+	log.Debug("Создание песни")
+
+	var err error
+	if input.Group, err = s.sanitizeString("group", input.Group); err != nil {
+		return model.SongRef{}, err
+	}
+	if input.Song, err = s.sanitizeString("song", input.Song); err != nil {
+		return model.SongRef{}, err
+	}
+	input.Group = NormalizeName(input.Group)
+	input.Song = NormalizeName(input.Song)
+	if err = validateSongNames(input.Group, input.Song); err != nil {
+		return model.SongRef{}, err
+	}
+
+	key := createKey(input)
+	call, shared := s.joinCreate(ctx, key, input)
+	if shared {
+		log.Debug("Запрос на создание песни объединен с параллельным")
+	}
+
+	select {
+	case <-ctx.Done():
+		s.leaveCreate(key, call)
+		log.Info("Ожидание создания песни прервано отменой запроса", "error", ctx.Err())
+		return model.SongRef{}, ctx.Err()
+	case <-call.done:
+		if call.err != nil {
+			return model.SongRef{}, call.err
+		}
+		return call.ref, nil
+	}
+}
 
-	log.Debug("Создание песни", "group", input.Group, "song", input.Song)
+// joinCreate присоединяет вызывающего к выполняющемуся созданию песни с ключом key или запускает новое.
+// Общий вызов сохраняет значения контекста первого вызывающего, но не его отмену.
+func (s *SongService) joinCreate(ctx context.Context, key string, input model.SongInput) (*createCall, bool) {
+	s.createsMu.Lock()
+	defer s.createsMu.Unlock()
 
-	details, err := s.apiClient.GetSongDetails(ctx, input.Group, input.Song)
+	if call, ok := s.creates[key]; ok {
+		call.waiters++
+		return call, true
+	}
+
+	callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	call := &createCall{done: make(chan struct{}), cancel: cancel, waiters: 1}
+	s.creates[key] = call
+	go func() {
+		defer cancel()
+		call.ref, call.err = s.createSong(callCtx, input)
+		s.createsMu.Lock()
+		if s.creates[key] == call {
+			delete(s.creates, key)
+		}
+		s.createsMu.Unlock()
+		close(call.done)
+	}()
+	return call, false
+}
+
+// leaveCreate отмечает, что вызывающий больше не ожидает создания песни.
+// Когда уходит последний ожидающий, общий вызов отменяется, а следующий запрос запустит новый.
+func (s *SongService) leaveCreate(key string, call *createCall) {
+	s.createsMu.Lock()
+	defer s.createsMu.Unlock()
+
+	call.waiters--
+	if call.waiters > 0 {
+		return
+	}
+	call.cancel()
+	if s.creates[key] == call {
+		delete(s.creates, key)
+	}
+}
+
+// createSong получает данные песни из внешнего API и сохраняет ее в репозитории
+func (s *SongService) createSong(ctx context.Context, input model.SongInput) (model.SongRef, error) {
+	log := s.logger.WithFields(ctx, "group", input.Group, "song", input.Song)
+
+	details, err := s.fetchSongDetails(ctx, input)
 	if err != nil {
 		log.Error("Ошибка получения данных из внешнего API", "error", err)
-		return 0, fmt.Errorf("ошибка получения данных песни: %w", err)
+		return model.SongRef{}, fmt.Errorf("ошибка получения данных песни: %w", err)
 	}
 
 	song := &model.Song{
@@ -47,18 +247,284 @@ func (s *SongService) CreateSong(ctx context.Context, input model.SongInput) (in
 		ReleaseDate: details.ReleaseDate,
 		Text:        details.Text,
 		Link:        details.Link,
+		Duration:    details.DurationSeconds,
+	}
+	if details.BPM != nil {
+		if validBPM(*details.BPM) {
+			bpm := int16(*details.BPM)
+			song.BPM = &bpm
+		} else {
+			log.Warn("Внешний API вернул темп вне допустимого диапазона, значение пропущено", "bpm", *details.BPM)
+		}
+	}
+	if copyright := strings.TrimSpace(details.Copyright); copyright != "" {
+		song.Copyright = &copyright
 	}
+	if song.FeaturedArtists, err = s.normalizeArtists(details.FeaturedArtists); err != nil {
+		log.Error("Данные из внешнего API содержат некорректный UTF-8", "error", err)
+		return model.SongRef{}, err
+	}
+	if len(song.FeaturedArtists) > model.MaxFeaturedArtists {
+		log.Warn("Внешний API вернул слишком много приглашенных исполнителей, лишние пропущены", "count", len(song.FeaturedArtists))
+		song.FeaturedArtists = song.FeaturedArtists[:model.MaxFeaturedArtists]
+	}
+	enrichedAt := time.Now()
+	song.Provenance = detailsProvenance(song, model.ProviderExternalAPI, enrichedAt)
+	song.EnrichmentStatus = model.EnrichmentStatusOK
+	song.EnrichedAt = &enrichedAt
+	if err = s.sanitizeSong(song); err != nil {
+		log.Error("Данные из внешнего API содержат некорректный UTF-8", "error", err)
+		return model.SongRef{}, err
+	}
+	s.truncateSongLimits(ctx, song)
 
 	id, err := s.repo.CreateSong(ctx, song)
 	if err != nil {
 		log.Error("Ошибка создания песни в репозитории", "error", err)
-		return 0, fmt.Errorf("ошибка создания песни: %w", err)
+		return model.SongRef{}, fmt.Errorf("ошибка создания песни: %w", err)
 	}
 
-	log.Info("Песня успешно создана", "id", id)
-	return id, nil
+	_ = s.logEvent(ctx, model.EventSongCreated, &id, map[string]interface{}{
+		"group": song.Group, "song": song.Song, "public_id": song.PublicID,
+	})
+
+	log.Info("Песня успешно создана", "id", id, "public_id", song.PublicID)
+	return model.SongRef{ID: id, PublicID: song.PublicID}, nil
+}
+
+// detailsProvenance отмечает заполненные поставщиком поля песни
+func detailsProvenance(song *model.Song, provider string, fetchedAt time.Time) model.Provenance {
+	provenance := make(model.Provenance)
+	source := model.FieldSource{Provider: provider, FetchedAt: fetchedAt}
+	if song.ReleaseDate != "" {
+		provenance[model.FieldReleaseDate] = source
+	}
+	if song.Text != "" {
+		provenance[model.FieldText] = source
+	}
+	if song.Link != "" {
+		provenance[model.FieldLink] = source
+	}
+	if song.Duration != nil {
+		provenance[model.FieldDuration] = source
+	}
+	if song.BPM != nil {
+		provenance[model.FieldBPM] = source
+	}
+	if song.Copyright != nil {
+		provenance[model.FieldCopyright] = source
+	}
+	if len(song.FeaturedArtists) > 0 {
+		provenance[model.FieldFeaturedArtists] = source
+	}
+	return provenance
+}
+
+// BulkCreateSongs создает набор песен без обращения к внешнему API.
+// Все песни вставляются одной транзакцией: при ошибке не сохраняется ни одна.
+func (s *SongService) BulkCreateSongs(ctx context.Context, inputs []model.SongImport) (int64, error) {
+	log := s.logger.WithContext(ctx)
+
+	log.Debug("Массовое создание песен", "count", len(inputs))
+
+	songs := make([]*model.Song, 0, len(inputs))
+	for i, input := range inputs {
+		if input.Duration != nil && *input.Duration < 0 {
+			return 0, model.NewValidationError(fmt.Sprintf("песня %d: длительность не может быть отрицательной", i))
+		}
+		if input.BPM != nil && !validBPM(int(*input.BPM)) {
+			return 0, model.NewValidationError(fmt.Sprintf("песня %d: %s", i, bpmRangeMessage()))
+		}
+
+		song := &model.Song{
+			Group:       input.Group,
+			Song:        input.Song,
+			ReleaseDate: input.ReleaseDate,
+			Text:        input.Text,
+			Link:        input.Link,
+			Duration:    input.Duration,
+			BPM:         input.BPM,
+		}
+		if err := s.sanitizeSong(song); err != nil {
+			return 0, err
+		}
+		if err := normalizeSongNames(song); err != nil {
+			return 0, model.NewValidationError(fmt.Sprintf("песня %d: %s", i, err))
+		}
+		if err := s.validateSongLimits(song); err != nil {
+			return 0, err
+		}
+		songs = append(songs, song)
+	}
+
+	inserted, err := s.repo.BulkInsertSongs(ctx, songs)
+	if err != nil {
+		log.Error("Ошибка массового создания песен в репозитории", "error", err)
+		return 0, fmt.Errorf("ошибка массового создания песен: %w", err)
+	}
+
+	_ = s.logEvent(ctx, model.EventSongsBulkCreated, nil, map[string]interface{}{"count": inserted})
+
+	log.Info("Песни успешно созданы", "count", inserted)
+	return inserted, nil
+}
+
+// ExportSong возвращает песню в виде переносимого документа
+func (s *SongService) ExportSong(ctx context.Context, id int64) (*model.SongDocument, error) {
+	log := s.logger.WithFields(ctx, "id", id)
+
+	log.Debug("Экспорт песни")
+
+	song, err := s.GetSongByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Песня успешно экспортирована")
+	document := songDocument(song)
+	return &document, nil
+}
+
+// songDocument возвращает переносимый документ песни
+func songDocument(song *model.Song) model.SongDocument {
+	return model.SongDocument{
+		FormatVersion: model.SongDocumentFormatVersion,
+		SongImport: model.SongImport{
+			Group:       song.Group,
+			Song:        song.Song,
+			ReleaseDate: song.ReleaseDate,
+			Text:        song.Text,
+			Link:        song.Link,
+			Duration:    song.Duration,
+			BPM:         song.BPM,
+		},
+		Copyright:       song.Copyright,
+		FeaturedArtists: song.FeaturedArtists,
+		Provenance:      song.Provenance,
+	}
+}
+
+// ExportLibrary передает fn все песни библиотеки по одной, не загружая их в память целиком.
+// Ошибка fn или отмена ctx, например при отключении клиента, прерывает экспорт.
+func (s *SongService) ExportLibrary(ctx context.Context, fn func(song *model.Song) error) error {
+	log := s.logger.WithContext(ctx)
+
+	log.Debug("Экспорт библиотеки")
+
+	count := 0
+	err := s.repo.IterateSongs(ctx, func(song *model.Song) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(song); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		log.Error("Экспорт библиотеки прерван", "error", err, "count", count)
+		return fmt.Errorf("ошибка экспорта библиотеки: %w", err)
+	}
+
+	log.Info("Библиотека успешно экспортирована", "count", count)
+	return nil
+}
+
+// ImportSong создает песню из переносимого документа без обращения к внешнему API.
+// Если песня с такой группой и названием уже существует, возвращается ErrSongAlreadyExists.
+func (s *SongService) ImportSong(ctx context.Context, document model.SongDocument) (model.SongRef, error) {
+	log := s.logger.WithFields(ctx, "group", document.Group, "song", document.Song)
+
+	log.Debug("Импорт песни", "formatVersion", document.FormatVersion)
+
+	if document.FormatVersion < 1 || document.FormatVersion > model.SongDocumentFormatVersion {
+		return model.SongRef{}, model.NewValidationError(fmt.Sprintf("неподдерживаемая версия формата документа: %d", document.FormatVersion))
+	}
+	if document.Duration != nil && *document.Duration < 0 {
+		return model.SongRef{}, model.NewValidationError("длительность не может быть отрицательной")
+	}
+	if document.BPM != nil && !validBPM(int(*document.BPM)) {
+		return model.SongRef{}, model.NewValidationError(bpmRangeMessage())
+	}
+	if len(document.FeaturedArtists) > model.MaxFeaturedArtists {
+		return model.SongRef{}, model.NewValidationError(fmt.Sprintf("featuredArtists не может содержать больше %d исполнителей", model.MaxFeaturedArtists))
+	}
+
+	song := &model.Song{
+		Group:       document.Group,
+		Song:        document.Song,
+		ReleaseDate: document.ReleaseDate,
+		Text:        document.Text,
+		Link:        document.Link,
+		Duration:    document.Duration,
+		BPM:         document.BPM,
+		Provenance:  document.Provenance,
+	}
+	if document.Copyright != nil {
+		if copyright := strings.TrimSpace(*document.Copyright); copyright != "" {
+			song.Copyright = &copyright
+		}
+	}
+	if err := s.sanitizeSong(song); err != nil {
+		return model.SongRef{}, err
+	}
+	var err error
+	if song.FeaturedArtists, err = s.normalizeArtists(document.FeaturedArtists); err != nil {
+		return model.SongRef{}, err
+	}
+	if err := normalizeSongNames(song); err != nil {
+		return model.SongRef{}, err
+	}
+	if err := s.validateSongLimits(song); err != nil {
+		return model.SongRef{}, err
+	}
+
+	id, err := s.repo.CreateSong(ctx, song)
+	if err != nil {
+		log.Error("Ошибка импорта песни в репозиторий", "error", err)
+		return model.SongRef{}, fmt.Errorf("ошибка импорта песни: %w", err)
+	}
+
+	_ = s.logEvent(ctx, model.EventSongImported, &id, map[string]interface{}{
+		"group":         song.Group,
+		"song":          song.Song,
+		"formatVersion": document.FormatVersion,
+		"public_id":     song.PublicID,
+	})
+
+	log.Info("Песня успешно импортирована", "id", id, "public_id", song.PublicID)
+	return model.SongRef{ID: id, PublicID: song.PublicID}, nil
+}
+
+// fetchSongDetails получает детали песни из внешнего API в пределах ExternalAPIBudget.
+// Превышение бюджета возвращает ErrUpstreamTimeout, прочие ошибки внешнего API — ErrUpstreamFailed.
+// Отмена контекста вызывающей стороной прерывает запрос и возвращается как есть.
+func (s *SongService) fetchSongDetails(ctx context.Context, input model.SongInput) (*model.SongDetail, error) {
+	callCtx := ctx
+	if s.cfg.ExternalAPIBudget > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, s.cfg.ExternalAPIBudget)
+		defer cancel()
+	}
+
+	details, err := s.apiClient.GetSongDetails(callCtx, input.Group, input.Song)
+	if err == nil {
+		return details, nil
+	}
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%w: %w", model.ErrUpstreamTimeout, err)
+	}
+	return nil, fmt.Errorf("%w: %w", model.ErrUpstreamFailed, err)
 }
 
+// minTextFilterLength минимальная длина фильтра по тексту: более короткие подстроки не используют триграммный индекс
+const minTextFilterLength = 3
+
 // GetSongs получает список песен с фильтрами
 func (s *SongService) GetSongs(ctx context.Context, filter model.SongFilter) ([]*model.Song, error) {
 	log := s.logger.WithContext(ctx)
@@ -66,96 +532,988 @@ func (s *SongService) GetSongs(ctx context.Context, filter model.SongFilter) ([]
 	log.Debug("Получение списка песен с фильтром",
 		"group", filter.Group,
 		"song", filter.SongName,
+		"text", filter.Text,
+		"q", filter.QuickSearch,
 		"page", filter.Page,
 		"pageSize", filter.PageSize)
 
-	if filter.Page <= 0 {
-		filter.Page = 1
+	if filter.QuickSearch != "" && (filter.Group != "" || filter.SongName != "" || filter.Text != "" ||
+		len(filter.Groups) > 0 || len(filter.SongNames) > 0) {
+		log.Info("Быстрый поиск передан вместе с фильтрами по полям")
+		return nil, model.NewValidationError("conflicting filters")
 	}
-	if filter.PageSize <= 0 {
-		filter.PageSize = 10
+
+	if err := validateListFilter("group", "groups", filter.Group, filter.Groups); err != nil {
+		log.Info("Неверный фильтр по группам", "error", err)
+		return nil, err
+	}
+	if err := validateListFilter("song", "songs", filter.SongName, filter.SongNames); err != nil {
+		log.Info("Неверный фильтр по названиям песен", "error", err)
+		return nil, err
+	}
+
+	if err := validateDurationRange(filter.DurationMin, filter.DurationMax); err != nil {
+		log.Info("Неверный диапазон длительности", "error", err)
+		return nil, err
+	}
+
+	if err := validateBPMRange(filter.BPMMin, filter.BPMMax); err != nil {
+		log.Info("Неверный диапазон темпа", "error", err)
+		return nil, err
+	}
+
+	if filter.CreatedAtFrom != nil && filter.CreatedAtTo != nil && filter.CreatedAtFrom.After(*filter.CreatedAtTo) {
+		log.Info("Неверный диапазон времени добавления", "from", filter.CreatedAtFrom, "to", filter.CreatedAtTo)
+		return nil, model.NewValidationError("created_at_from не может быть позже created_at_to")
+	}
+
+	for _, field := range filter.MissingFields {
+		if !slices.Contains(model.MissingFilterFields, field) {
+			log.Info("Неизвестное поле в missing_fields", "field", field)
+			return nil, model.NewValidationError(fmt.Sprintf("неизвестное поле в missing_fields: %s (допустимы: %s)",
+				field, strings.Join(model.MissingFilterFields, ", ")))
+		}
+	}
+
+	if filter.EnrichmentStatus != "" && !slices.Contains(model.EnrichmentStatuses, filter.EnrichmentStatus) {
+		log.Info("Неизвестный статус получения данных", "enrichmentStatus", filter.EnrichmentStatus)
+		return nil, model.NewValidationError(fmt.Sprintf("enrichmentStatus должен быть одним из: %s",
+			strings.Join(model.EnrichmentStatuses, ", ")))
 	}
 
-	songs, err := s.repo.GetSongs(ctx, filter)
+	if filter.Text != "" && utf8.RuneCountInString(strings.TrimSpace(filter.Text)) < minTextFilterLength {
+		log.Info("Слишком короткий фильтр по тексту", "text", filter.Text)
+		return nil, model.NewValidationError(fmt.Sprintf("text должен содержать не меньше %d символов", minTextFilterLength))
+	}
+
+	filter.Pagination = pagination.NewPage(filter.Page, filter.PageSize, s.cfg.DefaultSongsPageSize, s.cfg.MaxSongsPageSize)
+	if err := s.checkOffset(ctx, filter.Pagination); err != nil {
+		return nil, err
+	}
+
+	// Фильтры по группе и названию сравниваются с нормализованными колонками без учета регистра и диакритики
+	filter.Group = model.NormalizeName(filter.Group)
+	filter.SongName = model.NormalizeName(filter.SongName)
+
+	// Фрагменты строятся по тексту песни, поэтому при поиске текст запрашивается и без includeText
+	query := snippetQuery(filter)
+	repoFilter := filter
+	if query != "" {
+		repoFilter.OmitText = false
+	}
+
+	songs, err := s.repo.GetSongs(ctx, repoFilter)
 	if err != nil {
 		log.Error("Ошибка получения списка песен из репозитория", "error", err)
 		return nil, fmt.Errorf("ошибка получения списка песен: %w", err)
 	}
+	if songs == nil {
+		songs = []*model.Song{}
+	}
+	if query != "" {
+		for _, song := range songs {
+			song.Snippet = TextSnippet(song.Text, query)
+			if filter.OmitText {
+				song.Text = ""
+			}
+		}
+	}
 
 	log.Info("Список песен успешно получен", "count", len(songs))
 	return songs, nil
 }
 
+// snippetQuery возвращает поисковую строку, по которой строятся фрагменты текста, или пустую строку без поиска по тексту
+func snippetQuery(filter model.SongFilter) string {
+	if filter.QuickSearch != "" {
+		return filter.QuickSearch
+	}
+	return filter.Text
+}
+
 // GetSongByID получает песню по идентификатору
 func (s *SongService) GetSongByID(ctx context.Context, id int64) (*model.Song, error) {
-	log := s.logger.WithContext(ctx)
+	log := s.logger.WithFields(ctx, "id", id)
+
+	log.Debug("Получение песни по ID")
+
+	if song, ok := s.cachedSong(id); ok {
+		// Обращение учитывается в журнале так же, как при чтении из базы
+		s.repo.RecordAccess(ctx, id, model.AccessActionView)
+		log.Info("Песня получена из кэша")
+		return song, nil
+	}
 
-	log.Debug("Получение песни по ID", "id", id)
-	song, err := s.repo.GetSongByID(ctx, id)
+	// Одновременные чтения одной песни выполняют один запрос к репозиторию. Ошибка не запоминается:
+	// следующий запрос после завершения текущего снова обращается к репозиторию.
+	// Запрос выполняется без отмены, чтобы отключение первого клиента не прерывало чтение для остальных.
+	// Кэш процесса заполняется только из основной базы: отстающая реплика после записи и инвалидации
+	// вернула бы прежнюю версию, и она оставалась бы в кэше до истечения TTL.
+	leader := false
+	result, err, shared := s.reads.Do(strconv.FormatInt(id, 10), func() (interface{}, error) {
+		leader = true
+		if s.songCache == nil {
+			return s.repo.GetSongByID(context.WithoutCancel(ctx), id)
+		}
+		song, err := s.repo.GetSongByIDPrimary(context.WithoutCancel(ctx), id)
+		if err == nil && song != nil {
+			s.cacheSong(song)
+		}
+		return song, err
+	})
 	if err != nil {
 		log.Error("Ошибка получения песни из репозитория", "error", err)
 		return nil, fmt.Errorf("ошибка получения песни: %w", err)
 	}
 
+	song := result.(*model.Song)
 	if song == nil {
-		log.Info("Песня не найдена", "id", id)
-		return nil, fmt.Errorf("песня с id %d не найдена", id)
+		log.Info("Песня не найдена")
+		return nil, model.NewNotFoundError(id)
+	}
+	if !leader {
+		// Репозиторий учел обращение только для первого запроса
+		s.repo.RecordAccess(ctx, id, model.AccessActionView)
+	}
+	if shared {
+		log.Debug("Чтение песни объединено с параллельным")
 	}
 
-	log.Info("Песня успешно получена", "id", id)
-	return song, nil
+	log.Info("Песня успешно получена")
+	// Каждый вызывающий получает свою копию, чтобы изменения одного не затрагивали других
+	return song.Clone(), nil
+}
+
+// ResolvePublicID возвращает идентификатор песни, включая удаленные в корзину, по публичному UUID.
+// Если песни нет, возвращается model.ErrSongNotFound.
+func (s *SongService) ResolvePublicID(ctx context.Context, publicID string) (int64, error) {
+	log := s.logger.WithFields(ctx, "public_id", publicID)
+
+	id, err := s.repo.GetSongIDByPublicID(ctx, publicID)
+	if err != nil {
+		log.Error("Ошибка получения ID песни по публичному идентификатору из репозитория", "error", err)
+		return 0, fmt.Errorf("ошибка получения ID песни по публичному идентификатору: %w", err)
+	}
+	if id == 0 {
+		log.Info("Песня с публичным идентификатором не найдена")
+		return 0, fmt.Errorf("%w: public_id %s", model.ErrSongNotFound, publicID)
+	}
+	return id, nil
 }
 
-// UpdateSong обновляет данные песни
-func (s *SongService) UpdateSong(ctx context.Context, song *model.Song) error {
+// GetSongsByIDs получает песни по списку идентификаторов
+func (s *SongService) GetSongsByIDs(ctx context.Context, ids []int64) ([]*model.Song, error) {
 	log := s.logger.WithContext(ctx)
 
-	log.Debug("Обновление песни", "id", song.ID)
+	log.Debug("Получение песен по списку ID", "count", len(ids))
+	if len(ids) == 0 {
+		return []*model.Song{}, nil
+	}
+
+	songs, err := s.repo.GetSongsByIDs(ctx, ids)
+	if err != nil {
+		log.Error("Ошибка получения песен по списку ID из репозитория", "error", err)
+		return nil, fmt.Errorf("ошибка получения песен по списку ID: %w", err)
+	}
+
+	log.Info("Песни по списку ID успешно получены", "count", len(songs))
+	return songs, nil
+}
+
+// UpdateSong обновляет данные песни.
+// Возвращает актуальное состояние песни и признак того, была ли она изменена.
+// Если данные не отличаются от сохраненных, запись в базу не выполняется.
+func (s *SongService) UpdateSong(ctx context.Context, song *model.Song, opts model.UpdateOptions) (*model.Song, bool, error) {
+	log := s.logger.WithFields(ctx, "id", song.ID)
+
+	log.Debug("Обновление песни")
+
+	if err := s.sanitizeSong(song); err != nil {
+		log.Info("Данные песни содержат некорректный UTF-8", "error", err)
+		return nil, false, err
+	}
+	if err := normalizeSongNames(song); err != nil {
+		log.Info("Пустые группа или название песни после нормализации", "error", err)
+		return nil, false, err
+	}
+	if err := s.validateSongLimits(song); err != nil {
+		log.Info("Данные песни превышают ограничения", "error", err)
+		return nil, false, err
+	}
+	if song.BPM != nil && !validBPM(int(*song.BPM)) {
+		log.Info("Темп песни вне допустимого диапазона", "bpm", *song.BPM)
+		return nil, false, model.NewValidationError(bpmRangeMessage())
+	}
+
+	var (
+		result  *model.Song
+		changed bool
+	)
+	err := s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		existing, err := s.repo.GetSongByIDForUpdate(ctx, song.ID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return model.NewNotFoundError(song.ID)
+		}
+
+		if songsEqual(existing, song) {
+			result = existing
+			return nil
+		}
 
-	err := s.repo.UpdateSong(ctx, song)
+		changedFields := changedProviderFields(existing, song)
+		if opts.ProtectEnriched && !opts.Force {
+			for _, field := range changedFields {
+				if _, ok := existing.Provenance[field]; ok {
+					return fmt.Errorf("%w: %s", model.ErrEnrichedFieldProtected, field)
+				}
+			}
+		}
+		song.Provenance = withoutFields(existing.Provenance, changedFields)
+
+		// Сведения об авторских правах и приглашенные исполнители меняются только через отдельные методы
+		song.CreatedAt = existing.CreatedAt
+		song.Copyright = existing.Copyright
+		song.FeaturedArtists = existing.FeaturedArtists
+		if err = s.repo.UpdateSong(ctx, song); err != nil {
+			return err
+		}
+
+		// Ответ строится по сохраненной записи: служебные поля (публичный ID, обогащение, чарт, хеш содержимого)
+		// принадлежат серверу и не берутся из тела запроса
+		updated, err := s.repo.GetSongByIDForUpdate(ctx, song.ID)
+		if err != nil {
+			return err
+		}
+		if updated == nil {
+			return model.NewNotFoundError(song.ID)
+		}
+
+		if err = s.logEvent(ctx, model.EventSongUpdated, &song.ID, map[string]interface{}{
+			"group": updated.Group, "song": updated.Song,
+			"before": model.NewSongSnapshot(existing), "after": model.NewSongSnapshot(updated),
+		}); err != nil {
+			return err
+		}
+
+		result = updated
+		changed = true
+		return nil
+	})
 	if err != nil {
 		log.Error("Ошибка обновления песни в репозитории", "error", err)
-		return fmt.Errorf("ошибка обновления песни: %w", err)
+		return nil, false, fmt.Errorf("ошибка обновления песни: %w", err)
+	}
+	s.invalidateSongs(song.ID)
+
+	if !changed {
+		log.Info("Данные песни не изменились, обновление пропущено")
+		return result, false, nil
+	}
+
+	log.Info("Песня успешно обновлена")
+	return result, true, nil
+}
+
+// songsEqual сравнивает редактируемые поля песен без учета служебных временных меток
+func songsEqual(a, b *model.Song) bool {
+	return a.Group == b.Group &&
+		a.Song == b.Song &&
+		a.ReleaseDate == b.ReleaseDate &&
+		a.Link == b.Link &&
+		equalPtr(a.Duration, b.Duration) &&
+		equalPtr(a.BPM, b.BPM) &&
+		normalizeWhitespace(a.Text) == normalizeWhitespace(b.Text)
+}
+
+// changedProviderFields возвращает поля, которые может заполнять поставщик данных и которые отличаются у песен
+func changedProviderFields(existing, updated *model.Song) []string {
+	var fields []string
+	if existing.ReleaseDate != updated.ReleaseDate {
+		fields = append(fields, model.FieldReleaseDate)
+	}
+	if normalizeWhitespace(existing.Text) != normalizeWhitespace(updated.Text) {
+		fields = append(fields, model.FieldText)
+	}
+	if existing.Link != updated.Link {
+		fields = append(fields, model.FieldLink)
+	}
+	if !equalPtr(existing.Duration, updated.Duration) {
+		fields = append(fields, model.FieldDuration)
+	}
+	if !equalPtr(existing.BPM, updated.BPM) {
+		fields = append(fields, model.FieldBPM)
+	}
+	return fields
+}
+
+// withoutFields возвращает копию происхождения без указанных полей
+func withoutFields(provenance model.Provenance, fields []string) model.Provenance {
+	result := make(model.Provenance, len(provenance))
+	for field, source := range provenance {
+		result[field] = source
+	}
+	for _, field := range fields {
+		delete(result, field)
+	}
+	return result
+}
+
+// equalPtr сравнивает значения необязательных полей
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// normalizeWhitespace приводит пробельные символы текста к каноничному виду:
+// окончания строк к \n, пробелы внутри строки к одному, без пробелов по краям строк и текста
+func normalizeWhitespace(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// UpdateSongDuration обновляет длительность песни в секундах. nil очищает значение
+func (s *SongService) UpdateSongDuration(ctx context.Context, id int64, duration *int, opts model.UpdateOptions) error {
+	log := s.logger.WithFields(ctx, "id", id)
+
+	log.Debug("Обновление длительности песни")
+
+	if duration != nil && *duration < 0 {
+		return model.NewValidationError("длительность не может быть отрицательной")
+	}
+
+	err := s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		if opts.ProtectEnriched && !opts.Force {
+			existing, err := s.repo.GetSongByIDForUpdate(ctx, id)
+			if err != nil {
+				return err
+			}
+			if existing == nil {
+				return model.NewNotFoundError(id)
+			}
+			if _, ok := existing.Provenance[model.FieldDuration]; ok && !equalPtr(existing.Duration, duration) {
+				return fmt.Errorf("%w: %s", model.ErrEnrichedFieldProtected, model.FieldDuration)
+			}
+		}
+		return s.repo.UpdateSongDuration(ctx, id, duration)
+	})
+	if err != nil {
+		log.Error("Ошибка обновления длительности песни в репозитории", "error", err)
+		return fmt.Errorf("ошибка обновления длительности песни: %w", err)
+	}
+	s.invalidateSongs(id)
+
+	_ = s.logEvent(ctx, model.EventSongDurationUpdated, &id, map[string]interface{}{"duration": duration})
+
+	log.Info("Длительность песни успешно обновлена")
+	return nil
+}
+
+// UpdateSongBPM обновляет темп песни в ударах в минуту. nil очищает значение
+func (s *SongService) UpdateSongBPM(ctx context.Context, id int64, bpm *int, opts model.UpdateOptions) error {
+	log := s.logger.WithFields(ctx, "id", id)
+
+	log.Debug("Обновление темпа песни")
+
+	if bpm != nil && !validBPM(*bpm) {
+		return model.NewValidationError(bpmRangeMessage())
+	}
+	var value *int16
+	if bpm != nil {
+		converted := int16(*bpm)
+		value = &converted
+	}
+
+	err := s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		if opts.ProtectEnriched && !opts.Force {
+			existing, err := s.repo.GetSongByIDForUpdate(ctx, id)
+			if err != nil {
+				return err
+			}
+			if existing == nil {
+				return model.NewNotFoundError(id)
+			}
+			if _, ok := existing.Provenance[model.FieldBPM]; ok && !equalPtr(existing.BPM, value) {
+				return fmt.Errorf("%w: %s", model.ErrEnrichedFieldProtected, model.FieldBPM)
+			}
+		}
+		return s.repo.UpdateSongBPM(ctx, id, value)
+	})
+	if err != nil {
+		log.Error("Ошибка обновления темпа песни в репозитории", "error", err)
+		return fmt.Errorf("ошибка обновления темпа песни: %w", err)
+	}
+	s.invalidateSongs(id)
+
+	_ = s.logEvent(ctx, model.EventSongBPMUpdated, &id, map[string]interface{}{"bpm": bpm})
+
+	log.Info("Темп песни успешно обновлен")
+	return nil
+}
+
+// UpdateSongCopyright обновляет сведения об авторских правах песни. Пустое значение или nil очищает их
+func (s *SongService) UpdateSongCopyright(ctx context.Context, id int64, copyright *string, opts model.UpdateOptions) error {
+	log := s.logger.WithFields(ctx, "id", id)
+
+	log.Debug("Обновление сведений об авторских правах песни")
+
+	var value *string
+	if copyright != nil {
+		sanitized, err := s.sanitizeString(model.FieldCopyright, strings.TrimSpace(*copyright))
+		if err != nil {
+			log.Info("Сведения об авторских правах содержат некорректный UTF-8", "error", err)
+			return err
+		}
+		if utf8.RuneCountInString(sanitized) > model.MaxCopyrightLength {
+			return model.NewValidationError(fmt.Sprintf("copyright не должен превышать %d символов", model.MaxCopyrightLength))
+		}
+		if sanitized != "" {
+			value = &sanitized
+		}
+	}
+
+	err := s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		if opts.ProtectEnriched && !opts.Force {
+			existing, err := s.repo.GetSongByIDForUpdate(ctx, id)
+			if err != nil {
+				return err
+			}
+			if existing == nil {
+				return model.NewNotFoundError(id)
+			}
+			if _, ok := existing.Provenance[model.FieldCopyright]; ok && !equalPtr(existing.Copyright, value) {
+				return fmt.Errorf("%w: %s", model.ErrEnrichedFieldProtected, model.FieldCopyright)
+			}
+		}
+		return s.repo.UpdateSongCopyright(ctx, id, value)
+	})
+	if err != nil {
+		log.Error("Ошибка обновления сведений об авторских правах в репозитории", "error", err)
+		return fmt.Errorf("ошибка обновления сведений об авторских правах: %w", err)
+	}
+	s.invalidateSongs(id)
+
+	_ = s.logEvent(ctx, model.EventSongCopyrightUpdated, &id, map[string]interface{}{"copyright": value})
+
+	log.Info("Сведения об авторских правах песни успешно обновлены")
+	return nil
+}
+
+// UpdateFeaturedArtists заменяет список приглашенных исполнителей песни. Пустой список или nil очищает его.
+// Имена очищаются от пробелов по краям, пустые и повторяющиеся без учета регистра имена пропускаются
+func (s *SongService) UpdateFeaturedArtists(ctx context.Context, id int64, artists []string, opts model.UpdateOptions) error {
+	log := s.logger.WithFields(ctx, "id", id)
+
+	log.Debug("Обновление приглашенных исполнителей песни")
+
+	value, err := s.normalizeArtists(artists)
+	if err != nil {
+		log.Info("Имена приглашенных исполнителей содержат некорректный UTF-8", "error", err)
+		return err
+	}
+	if len(value) > model.MaxFeaturedArtists {
+		return model.NewValidationError(fmt.Sprintf("artists не может содержать больше %d исполнителей", model.MaxFeaturedArtists))
+	}
+
+	err = s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		if opts.ProtectEnriched && !opts.Force {
+			existing, err := s.repo.GetSongByIDForUpdate(ctx, id)
+			if err != nil {
+				return err
+			}
+			if existing == nil {
+				return model.NewNotFoundError(id)
+			}
+			if _, ok := existing.Provenance[model.FieldFeaturedArtists]; ok && !slices.Equal(existing.FeaturedArtists, value) {
+				return fmt.Errorf("%w: %s", model.ErrEnrichedFieldProtected, model.FieldFeaturedArtists)
+			}
+		}
+		return s.repo.UpdateFeaturedArtists(ctx, id, value)
+	})
+	if err != nil {
+		log.Error("Ошибка обновления приглашенных исполнителей в репозитории", "error", err)
+		return fmt.Errorf("ошибка обновления приглашенных исполнителей: %w", err)
+	}
+	s.invalidateSongs(id)
+
+	_ = s.logEvent(ctx, model.EventSongArtistsUpdated, &id, map[string]interface{}{"artists": value})
+
+	log.Info("Приглашенные исполнители песни успешно обновлены", "count", len(value))
+	return nil
+}
+
+// normalizeArtists очищает имена исполнителей: проверяет UTF-8, удаляет пробелы по краям,
+// пропускает пустые имена и повторы без учета регистра с сохранением порядка
+func (s *SongService) normalizeArtists(names []string) (model.Artists, error) {
+	artists := model.Artists{}
+	seen := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		name, err := s.sanitizeString(model.FieldFeaturedArtists, strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		key := strings.ToLower(name)
+		if _, dup := seen[key]; dup || name == "" {
+			continue
+		}
+		seen[key] = struct{}{}
+		artists = append(artists, name)
+	}
+	return artists, nil
+}
+
+// GetSongsByCopyright получает песни, в сведениях об авторских правах которых встречается holder
+func (s *SongService) GetSongsByCopyright(ctx context.Context, holder string, page, size int) ([]*model.Song, error) {
+	log := s.logger.WithFields(ctx, "holder", holder)
+
+	log.Debug("Получение песен правообладателя")
+
+	holder = strings.TrimSpace(holder)
+	if holder == "" {
+		return nil, model.NewValidationError("holder обязателен")
+	}
+
+	return s.GetSongs(ctx, model.SongFilter{CopyrightContains: holder, Pagination: model.Pagination{Page: page, PageSize: size}})
+}
+
+// GetTempoDistribution возвращает гистограмму темпа песен по всем интервалам, включая пустые
+func (s *SongService) GetTempoDistribution(ctx context.Context) ([]model.TempoBucket, error) {
+	log := s.logger.WithContext(ctx)
+
+	log.Debug("Получение распределения песен по темпу")
+
+	buckets, err := s.repo.GetTempoDistribution(ctx)
+	if err != nil {
+		log.Error("Ошибка получения распределения по темпу из репозитория", "error", err)
+		return nil, fmt.Errorf("ошибка получения распределения песен по темпу: %w", err)
+	}
+
+	counts := make(map[string]int64, len(buckets))
+	for _, bucket := range buckets {
+		counts[bucket.Range] = bucket.Count
+	}
+	ranges := model.TempoRanges()
+	result := make([]model.TempoBucket, 0, len(ranges))
+	for _, r := range ranges {
+		result = append(result, model.TempoBucket{Range: r.Label(), Count: counts[r.Label()]})
+	}
+
+	return result, nil
+}
+
+// GetTotalDuration возвращает суммарную длительность песен группы
+func (s *SongService) GetTotalDuration(ctx context.Context, group string) (*model.TotalDuration, error) {
+	log := s.logger.WithFields(ctx, "group", group)
+
+	log.Debug("Получение суммарной длительности песен")
+
+	total, err := s.repo.GetTotalDuration(ctx, model.NormalizeName(group))
+	if err != nil {
+		log.Error("Ошибка получения суммарной длительности из репозитория", "error", err)
+		return nil, fmt.Errorf("ошибка получения суммарной длительности: %w", err)
+	}
+
+	return &model.TotalDuration{TotalSeconds: total, Formatted: formatDuration(total)}, nil
+}
+
+// validateVerseRange проверяет диапазон куплетов: границы положительны, from не больше to,
+// и диапазон не сочетается с page и page_size
+func validateVerseRange(pagination model.VersesPagination) error {
+	if pagination.Page != 0 || pagination.PageSize != 0 {
+		return model.NewValidationError("from и to нельзя сочетать с page и page_size")
+	}
+	if (pagination.From != nil && *pagination.From <= 0) || (pagination.To != nil && *pagination.To <= 0) {
+		return model.NewValidationError("from и to должны быть положительными")
+	}
+	if pagination.From != nil && pagination.To != nil && *pagination.From > *pagination.To {
+		return model.NewValidationError("from не может быть больше to")
+	}
+	return nil
+}
+
+// validateDurationRange проверяет, что границы длительности неотрицательны и min не больше max
+func validateDurationRange(min, max *int) error {
+	if (min != nil && *min < 0) || (max != nil && *max < 0) {
+		return model.NewValidationError("длительность не может быть отрицательной")
+	}
+	if min != nil && max != nil && *min > *max {
+		return model.NewValidationError("duration_min не может быть больше duration_max")
+	}
+	return nil
+}
+
+// validBPM сообщает, входит ли темп в допустимый диапазон
+func validBPM(bpm int) bool {
+	return bpm >= model.MinBPM && bpm <= model.MaxBPM
+}
+
+// bpmRangeMessage возвращает сообщение об ошибке для темпа вне допустимого диапазона
+func bpmRangeMessage() string {
+	return fmt.Sprintf("темп должен быть от %d до %d", model.MinBPM, model.MaxBPM)
+}
+
+// validateListFilter проверяет списочный фильтр list: он не сочетается с одиночным фильтром single
+// по тому же полю и содержит не больше model.MaxFilterListItems значений
+func validateListFilter(singleParam, listParam, single string, list []string) error {
+	if single != "" && len(list) > 0 {
+		return model.NewValidationError(fmt.Sprintf("%s и %s нельзя указывать одновременно", singleParam, listParam))
+	}
+	if len(list) > model.MaxFilterListItems {
+		return model.NewValidationError(fmt.Sprintf("%s должен содержать не больше %d значений", listParam, model.MaxFilterListItems))
+	}
+	return nil
+}
+
+// validateBPMRange проверяет границы фильтра по темпу
+func validateBPMRange(min, max *int) error {
+	if (min != nil && !validBPM(*min)) || (max != nil && !validBPM(*max)) {
+		return model.NewValidationError(bpmRangeMessage())
 	}
+	if min != nil && max != nil && *min > *max {
+		return model.NewValidationError("bpm_min не может быть больше bpm_max")
+	}
+	return nil
+}
+
+// formatDuration форматирует длительность в секундах как "3h 27m 15s"
+func formatDuration(totalSeconds int64) string {
+	hours := totalSeconds / 3600
+	minutes := totalSeconds % 3600 / 60
+	seconds := totalSeconds % 60
+	return fmt.Sprintf("%dh %dm %ds", hours, minutes, seconds)
+}
+
+// MergeSongs объединяет исходную песню с целевой по выбранной стратегии.
+// Целевая песня обновляется, исходная помечается удаленной со ссылкой на целевую.
+func (s *SongService) MergeSongs(ctx context.Context, sourceID, targetID int64, strategy string) error {
+	log := s.logger.WithFields(ctx, "source_id", sourceID, "target_id", targetID)
+
+	log.Debug("Слияние песен", "strategy", strategy)
+
+	if sourceID == targetID {
+		return model.NewValidationError("source_id и target_id должны различаться")
+	}
+	switch strategy {
+	case model.MergeAppendVerses, model.MergeReplaceText, model.MergeKeepTarget:
+	default:
+		return model.NewValidationError("неизвестная стратегия слияния: " + strategy)
+	}
+
+	err := s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Строки блокируются в порядке возрастания ID, чтобы встречные слияния не приводили к взаимоблокировке
+		first, second := sourceID, targetID
+		if first > second {
+			first, second = second, first
+		}
+		locked := make(map[int64]*model.Song, 2)
+		for _, id := range []int64{first, second} {
+			song, err := s.repo.GetSongByIDForUpdate(ctx, id)
+			if err != nil {
+				return err
+			}
+			if song == nil {
+				return model.NewNotFoundError(id)
+			}
+			locked[id] = song
+		}
+		source, target := locked[sourceID], locked[targetID]
+
+		switch strategy {
+		case model.MergeAppendVerses:
+			if target.Text == "" {
+				target.Text = source.Text
+				inheritProvenance(target, source, model.FieldText)
+			} else if source.Text != "" {
+				target.Text += model.VerseDelimiter + source.Text
+				target.Provenance = withoutFields(target.Provenance, []string{model.FieldText})
+			}
+		case model.MergeReplaceText:
+			target.Text = source.Text
+			inheritProvenance(target, source, model.FieldText)
+		case model.MergeKeepTarget:
+			mergeSongMetadata(target, source)
+		}
 
-	log.Info("Песня успешно обновлена", "id", song.ID)
+		if err := s.validateSongLimits(target); err != nil {
+			return err
+		}
+		if err := s.repo.UpdateSong(ctx, target); err != nil {
+			return err
+		}
+		if err := s.repo.MarkSongMerged(ctx, sourceID, targetID); err != nil {
+			return err
+		}
+		return s.logEvent(ctx, model.EventSongMerged, &targetID, map[string]interface{}{"source_id": sourceID, "strategy": strategy})
+	})
+	if err != nil {
+		log.Error("Ошибка слияния песен", "error", err)
+		return fmt.Errorf("ошибка слияния песен: %w", err)
+	}
+	s.invalidateSongs(sourceID, targetID)
+
+	log.Info("Песни успешно объединены", "strategy", strategy)
 	return nil
 }
 
+// mergeSongMetadata переносит в целевую песню заполненные метаданные исходной
+func mergeSongMetadata(target, source *model.Song) {
+	if source.ReleaseDate != "" {
+		target.ReleaseDate = source.ReleaseDate
+		inheritProvenance(target, source, model.FieldReleaseDate)
+	}
+	if source.Link != "" {
+		target.Link = source.Link
+		inheritProvenance(target, source, model.FieldLink)
+	}
+	if source.Duration != nil {
+		target.Duration = source.Duration
+		inheritProvenance(target, source, model.FieldDuration)
+	}
+	if source.BPM != nil {
+		target.BPM = source.BPM
+		inheritProvenance(target, source, model.FieldBPM)
+	}
+}
+
+// inheritProvenance переносит в целевую песню происхождение поля, значение которого взято из исходной
+func inheritProvenance(target, source *model.Song, field string) {
+	target.Provenance = withoutFields(target.Provenance, []string{field})
+	if fieldSource, ok := source.Provenance[field]; ok {
+		target.Provenance[field] = fieldSource
+	}
+}
+
 // DeleteSong удаляет песню
 func (s *SongService) DeleteSong(ctx context.Context, id int64) error {
-	log := s.logger.WithContext(ctx)
+	log := s.logger.WithFields(ctx, "id", id)
 
-	log.Debug("Удаление песни", "id", id)
+	log.Debug("Удаление песни")
 
 	err := s.repo.DeleteSong(ctx, id)
 	if err != nil {
 		log.Error("Ошибка удаления песни из репозитория", "error", err)
 		return fmt.Errorf("ошибка удаления песни: %w", err)
 	}
+	s.invalidateSongs(id)
+
+	_ = s.logEvent(ctx, model.EventSongDeleted, &id, nil)
 
-	log.Info("Песня успешно удалена", "id", id)
+	log.Info("Песня успешно удалена")
 	return nil
 }
 
-// GetSongVerses получает куплеты песни с пагинацией
-func (s *SongService) GetSongVerses(ctx context.Context, id int64, pagination model.VersesPagination) ([]string, error) {
+// GetDeletedSongs возвращает удаленные песни, начиная с удаленных последними
+func (s *SongService) GetDeletedSongs(ctx context.Context, page, size int) ([]*model.Song, error) {
 	log := s.logger.WithContext(ctx)
 
-	log.Debug("Получение куплетов песни", "id", id, "page", pagination.Page, "pageSize", pagination.PageSize)
-	if pagination.Page <= 0 {
-		pagination.Page = 1
+	log.Debug("Получение удаленных песен", "page", page, "pageSize", size)
+
+	window := pagination.NewPage(page, size, s.cfg.DefaultSongsPageSize, s.cfg.MaxSongsPageSize)
+	if err := s.checkOffset(ctx, window); err != nil {
+		return nil, err
+	}
+
+	songs, err := s.repo.GetDeletedSongs(ctx, window)
+	if err != nil {
+		log.Error("Ошибка получения удаленных песен из репозитория", "error", err)
+		return nil, fmt.Errorf("ошибка получения удаленных песен: %w", err)
 	}
-	if pagination.PageSize <= 0 {
-		pagination.PageSize = 5
+
+	log.Info("Удаленные песни успешно получены", "count", len(songs))
+	return songs, nil
+}
+
+// RestoreSong восстанавливает удаленную песню и возвращает ее.
+// Если группа и название песни заняты другой активной песней, возвращается RestoreConflictError с идентификаторами обеих песен,
+// в том числе когда песня с такими группой и названием создается одновременно с восстановлением.
+func (s *SongService) RestoreSong(ctx context.Context, id int64) (*model.Song, error) {
+	log := s.logger.WithFields(ctx, "id", id)
+
+	log.Debug("Восстановление песни")
+
+	var restored, deleted *model.Song
+	err := s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		song, err := s.repo.GetDeletedSongByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if song == nil {
+			return model.NewNotFoundError(id)
+		}
+		deleted = song
+
+		conflictingID, err := s.repo.FindActiveSongID(ctx, song.Group, song.Song)
+		if err != nil {
+			return err
+		}
+		if conflictingID != 0 {
+			return &model.RestoreConflictError{SongID: id, ConflictingID: conflictingID}
+		}
+
+		if err = s.repo.RestoreSong(ctx, id); err != nil {
+			return err
+		}
+		song.DeletedAt = nil
+
+		if err = s.logEvent(ctx, model.EventSongRestored, &id, nil); err != nil {
+			return err
+		}
+
+		restored = song
+		return nil
+	})
+	var conflictErr *model.RestoreConflictError
+	if errors.Is(err, model.ErrSongAlreadyExists) && !errors.As(err, &conflictErr) && deleted != nil {
+		// Песня создана после проверки и восстановление нарушило уникальный индекс: транзакция прервана,
+		// поэтому конфликтующая песня ищется уже вне ее
+		if conflictingID, findErr := s.repo.FindActiveSongID(ctx, deleted.Group, deleted.Song); findErr == nil && conflictingID != 0 {
+			err = &model.RestoreConflictError{SongID: id, ConflictingID: conflictingID}
+		}
+	}
+	if err != nil {
+		log.Error("Ошибка восстановления песни", "error", err)
+		return nil, fmt.Errorf("ошибка восстановления песни: %w", err)
+	}
+
+	log.Info("Песня успешно восстановлена")
+	return restored, nil
+}
+
+// GetSongVerses получает куплеты песни с пагинацией и общее количество куплетов
+func (s *SongService) GetSongVerses(ctx context.Context, id int64, opts model.VersesPagination) ([]string, int, error) {
+	log := s.logger.WithFields(ctx, "id", id)
+
+	log.Debug("Получение куплетов песни", "page", opts.Page, "pageSize", opts.PageSize,
+		"from", opts.From, "to", opts.To, "descending", opts.Descending)
+
+	if opts.HasRange() {
+		if err := validateVerseRange(opts); err != nil {
+			log.Info("Неверный диапазон куплетов", "error", err)
+			return nil, 0, err
+		}
+	} else {
+		opts.Pagination = pagination.NewPage(opts.Page, opts.PageSize, s.cfg.DefaultVersesPageSize, s.cfg.MaxVersesPageSize)
 	}
 
-	verses, err := s.repo.GetSongVerses(ctx, id, pagination)
+	verses, total, err := s.repo.GetSongVerses(ctx, id, opts)
 	if err != nil {
 		log.Error("Ошибка получения куплетов песни из репозитория", "error", err)
-		return nil, fmt.Errorf("ошибка получения куплетов песни: %w", err)
+		return nil, 0, fmt.Errorf("ошибка получения куплетов песни: %w", err)
+	}
+	if verses == nil {
+		verses = []string{}
+	}
+
+	log.Info("Куплеты песни успешно получены", "count", len(verses), "total", total)
+	return verses, total, nil
+}
+
+// mostAccessedLimit количество песен в списке самых популярных
+const mostAccessedLimit = 10
+
+// GetAccessLog получает журнал обращений к песне за период с агрегацией по действиям
+func (s *SongService) GetAccessLog(ctx context.Context, id int64, from, to *time.Time) (*model.AccessLog, error) {
+	log := s.logger.WithFields(ctx, "id", id)
+
+	log.Debug("Получение журнала обращений к песне")
+
+	if from != nil && to != nil && from.After(*to) {
+		return nil, model.NewValidationError("from не может быть позже to")
+	}
+
+	entries, err := s.repo.GetAccessLog(ctx, id, from, to)
+	if err != nil {
+		log.Error("Ошибка получения журнала обращений из репозитория", "error", err)
+		return nil, fmt.Errorf("ошибка получения журнала обращений: %w", err)
+	}
+
+	counts := make(map[string]int)
+	for _, entry := range entries {
+		counts[entry.Action]++
+	}
+
+	log.Info("Журнал обращений к песне успешно получен", "count", len(entries))
+	return &model.AccessLog{Total: len(entries), Counts: counts, Entries: entries}, nil
+}
+
+// GetMostAccessedSongs получает самые популярные песни за период day или week
+func (s *SongService) GetMostAccessedSongs(ctx context.Context, period string) ([]*model.MostAccessedSong, error) {
+	log := s.logger.WithContext(ctx)
+
+	log.Debug("Получение самых популярных песен", "period", period)
+
+	var window time.Duration
+	switch period {
+	case "", "day":
+		window = 24 * time.Hour
+	case "week":
+		window = 7 * 24 * time.Hour
+	default:
+		return nil, model.NewValidationError("period должен быть day или week")
+	}
+
+	songs, err := s.repo.GetMostAccessedSongs(ctx, time.Now().Add(-window), mostAccessedLimit)
+	if err != nil {
+		log.Error("Ошибка получения самых популярных песен из репозитория", "error", err)
+		return nil, fmt.Errorf("ошибка получения самых популярных песен: %w", err)
+	}
+
+	log.Info("Самые популярные песни успешно получены", "count", len(songs))
+	return songs, nil
+}
+
+// maxWordFrequencyTop максимальное количество слов в статистике частоты
+const maxWordFrequencyTop = 100
+
+// GetWordFrequency возвращает top самых частых слов в тексте песни
+func (s *SongService) GetWordFrequency(ctx context.Context, id int64, top int) ([]model.WordCount, error) {
+	log := s.logger.WithFields(ctx, "id", id)
+
+	log.Debug("Получение частоты слов песни", "top", top)
+
+	if top <= 0 || top > maxWordFrequencyTop {
+		return nil, model.NewValidationError(fmt.Sprintf("top должен быть от 1 до %d", maxWordFrequencyTop))
+	}
+
+	song, err := s.GetSongByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	words := s.analyzer.WordFrequency(song.Text)
+	if len(words) > top {
+		words = words[:top]
+	}
+
+	log.Info("Частота слов песни успешно получена", "count", len(words))
+	return words, nil
+}
+
+// GetFormattedText возвращает текст песни с переносом строк по ширине width и отступом куплетов indent
+func (s *SongService) GetFormattedText(ctx context.Context, id int64, width, indent int) (string, error) {
+	log := s.logger.WithFields(ctx, "id", id)
+
+	log.Debug("Форматирование текста песни", "width", width, "indent", indent)
+
+	if width < minFormatWidth || width > maxFormatWidth {
+		return "", model.NewValidationError(fmt.Sprintf("width должен быть от %d до %d", minFormatWidth, maxFormatWidth))
+	}
+	if indent < 0 || indent > maxFormatIndent {
+		return "", model.NewValidationError(fmt.Sprintf("indent должен быть от 0 до %d", maxFormatIndent))
+	}
+
+	song, err := s.GetSongByID(ctx, id)
+	if err != nil {
+		return "", err
 	}
 
-	log.Info("Куплеты песни успешно получены", "count", len(verses))
-	return verses, nil
+	log.Info("Текст песни успешно отформатирован")
+	return FormatText(song.Text, width, indent), nil
 }
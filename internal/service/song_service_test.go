@@ -0,0 +1,473 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"song-library/internal/model"
+	"song-library/internal/testutil/faulttransport"
+	"sync"
+	"testing"
+	"time"
+)
+
+// detailJSON ответ внешнего API с деталями песни
+const detailJSON = `{"releaseDate":"16.07.2006","text":"Ooh baby"}`
+
+// infoKey возвращает ключ сценария faulttransport для запроса деталей песни
+func infoKey(group, song string) string {
+	return "GET /info?" + url.Values{"group": {group}, "song": {song}}.Encode()
+}
+
+// newCreateTestService создает сервис с репозиторием в памяти и клиентом внешнего API поверх transport
+func newCreateTestService(t *testing.T, transport http.RoundTripper, repo *memoryRepository) *SongService {
+	t.Helper()
+
+	client, err := NewExternalAPIClient(ExternalAPIConfig{BaseURL: "http://upstream.test", Transport: transport}, newTestLogger())
+	if err != nil {
+		t.Fatalf("NewExternalAPIClient() error = %v", err)
+	}
+	return NewSongService(repo, client, nil, nopEventLogger{}, ServiceConfig{ExternalAPIBudget: 5 * time.Second}, newTestLogger())
+}
+
+// waitFor ожидает выполнения условия не дольше двух секунд
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("условие не выполнено за отведенное время")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestCreateKey(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b model.SongInput
+		same bool
+	}{
+		{"одинаковые имена", model.SongInput{Group: "Muse", Song: "Hysteria"}, model.SongInput{Group: "Muse", Song: "Hysteria"}, true},
+		{"разный регистр группы", model.SongInput{Group: "Muse", Song: "Hysteria"}, model.SongInput{Group: "muse", Song: "Hysteria"}, false},
+		{"разный регистр названия", model.SongInput{Group: "Muse", Song: "Hysteria"}, model.SongInput{Group: "Muse", Song: "HYSTERIA"}, false},
+		{"граница группы и названия", model.SongInput{Group: "ab", Song: "c"}, model.SongInput{Group: "a", Song: "bc"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := createKey(tt.a) == createKey(tt.b); got != tt.same {
+				t.Errorf("createKey(%+v) == createKey(%+v) = %v, want %v", tt.a, tt.b, got, tt.same)
+			}
+		})
+	}
+}
+
+func TestCreateSongCoalescesConcurrentRequests(t *testing.T) {
+	const callers = 16
+
+	release := make(chan struct{})
+	key := infoKey("Muse", "Supermassive Black Hole")
+	transport := faulttransport.New().Script(key, faulttransport.After(release, faulttransport.OK(detailJSON)))
+	repo := newMemoryRepository()
+	svc := newCreateTestService(t, transport, repo)
+
+	var (
+		ready sync.WaitGroup
+		done  sync.WaitGroup
+		refs  [callers]model.SongRef
+		errs  [callers]error
+	)
+	ready.Add(callers)
+	done.Add(callers)
+	for i := range callers {
+		go func() {
+			defer done.Done()
+			ready.Done()
+			// Пробелы отличаются, но после NormalizeName ключ объединения один и тот же
+			refs[i], errs[i] = svc.CreateSong(context.Background(), model.SongInput{Group: " Muse ", Song: "Supermassive  Black Hole"})
+		}()
+	}
+	ready.Wait()
+	<-transport.Entered()
+	// Даем оставшимся вызывающим присоединиться к выполняющемуся запросу
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	done.Wait()
+
+	for i := range callers {
+		if errs[i] != nil {
+			t.Fatalf("CreateSong() #%d error = %v", i, errs[i])
+		}
+		if refs[i] != refs[0] {
+			t.Errorf("CreateSong() #%d = %+v, want %+v", i, refs[i], refs[0])
+		}
+	}
+	if got := transport.Calls(key); got != 1 {
+		t.Errorf("запросов к внешнему API = %d, want 1", got)
+	}
+	if got := repo.createCount(); got != 1 {
+		t.Errorf("вызовов CreateSong репозитория = %d, want 1", got)
+	}
+}
+
+func TestCreateSongDoesNotCoalesceDifferentCase(t *testing.T) {
+	transport := faulttransport.New().
+		Script(infoKey("Muse", "Uprising"), faulttransport.OK(detailJSON)).
+		Script(infoKey("muse", "Uprising"), faulttransport.OK(detailJSON))
+	repo := newMemoryRepository()
+	svc := newCreateTestService(t, transport, repo)
+
+	first, err := svc.CreateSong(context.Background(), model.SongInput{Group: "Muse", Song: "Uprising"})
+	if err != nil {
+		t.Fatalf("CreateSong(Muse) error = %v", err)
+	}
+	second, err := svc.CreateSong(context.Background(), model.SongInput{Group: "muse", Song: "Uprising"})
+	if err != nil {
+		t.Fatalf("CreateSong(muse) error = %v", err)
+	}
+	if first.ID == second.ID {
+		t.Errorf("песни с разным регистром группы получили один ID %d", first.ID)
+	}
+}
+
+func TestCreateSongCallerCancellationAbortsUpstream(t *testing.T) {
+	entered := make(chan struct{})
+	upstreamCanceled := make(chan struct{})
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		select {
+		case <-r.Context().Done():
+			close(upstreamCanceled)
+		case <-time.After(5 * time.Second):
+			_, _ = w.Write([]byte(detailJSON))
+		}
+	}))
+	defer upstream.Close()
+
+	client, err := NewExternalAPIClient(ExternalAPIConfig{BaseURL: upstream.URL}, newTestLogger())
+	if err != nil {
+		t.Fatalf("NewExternalAPIClient() error = %v", err)
+	}
+	repo := newMemoryRepository()
+	svc := NewSongService(repo, client, nil, nopEventLogger{}, ServiceConfig{ExternalAPIBudget: 5 * time.Second}, newTestLogger())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := svc.CreateSong(ctx, model.SongInput{Group: "Muse", Song: "Starlight"})
+		errCh <- err
+	}()
+
+	<-entered
+	cancel()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("CreateSong() error = %v, want context.Canceled", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("CreateSong() не вернулся после отмены контекста")
+	}
+	// Единственный ожидающий ушел, поэтому запрос к внешнему API отменяется, не дожидаясь ответа
+	select {
+	case <-upstreamCanceled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("контекст запроса к внешнему API не отменен")
+	}
+	if got := repo.createCount(); got != 0 {
+		t.Errorf("вызовов CreateSong репозитория = %d, want 0", got)
+	}
+}
+
+func TestCreateSongContinuesWhileCallersWait(t *testing.T) {
+	release := make(chan struct{})
+	key := infoKey("Muse", "Starlight")
+	transport := faulttransport.New().Script(key, faulttransport.After(release, faulttransport.OK(detailJSON)))
+	repo := newMemoryRepository()
+	svc := newCreateTestService(t, transport, repo)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	canceledErr := make(chan error, 1)
+	go func() {
+		_, err := svc.CreateSong(ctx, model.SongInput{Group: "Muse", Song: "Starlight"})
+		canceledErr <- err
+	}()
+	<-transport.Entered()
+
+	type result struct {
+		ref model.SongRef
+		err error
+	}
+	waiting := make(chan result, 1)
+	go func() {
+		ref, err := svc.CreateSong(context.Background(), model.SongInput{Group: "Muse", Song: "Starlight"})
+		waiting <- result{ref, err}
+	}()
+	waitFor(t, func() bool {
+		svc.createsMu.Lock()
+		defer svc.createsMu.Unlock()
+		call := svc.creates[createKey(model.SongInput{Group: "Muse", Song: "Starlight"})]
+		return call != nil && call.waiters == 2
+	})
+
+	cancel()
+	if err := <-canceledErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("CreateSong() error = %v, want context.Canceled", err)
+	}
+
+	// Второй вызывающий еще ждет, поэтому общий вызов не отменяется и песня сохраняется один раз
+	close(release)
+	res := <-waiting
+	if res.err != nil {
+		t.Fatalf("CreateSong() error = %v", res.err)
+	}
+	if res.ref.ID == 0 {
+		t.Errorf("CreateSong() = %+v, want ненулевой ID", res.ref)
+	}
+	if got := transport.Calls(key); got != 1 {
+		t.Errorf("запросов к внешнему API = %d, want 1", got)
+	}
+	if got := repo.createCount(); got != 1 {
+		t.Errorf("вызовов CreateSong репозитория = %d, want 1", got)
+	}
+}
+
+func TestCreateSongUpstreamFaults(t *testing.T) {
+	key := infoKey("Muse", "Map of the Problematique")
+	transport := faulttransport.New().Script(key,
+		faulttransport.Timeout(),
+		faulttransport.Status(http.StatusServiceUnavailable),
+		faulttransport.Error(errors.New("connection reset by peer")),
+		faulttransport.OK(`{"text":`),
+		faulttransport.OK(detailJSON),
+	)
+	repo := newMemoryRepository()
+	svc := newCreateTestService(t, transport, repo)
+	svc.cfg.ExternalAPIBudget = 50 * time.Millisecond
+
+	// Клиент не повторяет запросы: каждый вызов CreateSong расходует ровно один шаг сценария
+	wantErrs := []error{model.ErrUpstreamTimeout, model.ErrUpstreamFailed, model.ErrUpstreamFailed, model.ErrUpstreamFailed, nil}
+	for i, wantErr := range wantErrs {
+		_, err := svc.CreateSong(context.Background(), model.SongInput{Group: "Muse", Song: "Map of the Problematique"})
+		if !errors.Is(err, wantErr) {
+			t.Fatalf("CreateSong() #%d error = %v, want %v", i, err, wantErr)
+		}
+		if got := transport.Calls(key); got != i+1 {
+			t.Errorf("запросов к внешнему API после вызова #%d = %d, want %d", i, got, i+1)
+		}
+	}
+	if got := repo.createCount(); got != 1 {
+		t.Errorf("вызовов CreateSong репозитория = %d, want 1", got)
+	}
+}
+
+func TestGetSongsCreatedAtRange(t *testing.T) {
+	from := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	later := from.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		from    *time.Time
+		to      *time.Time
+		wantErr bool
+	}{
+		{"без ограничений", nil, nil, false},
+		{"только начало", &from, nil, false},
+		{"только конец", nil, &from, false},
+		{"начало раньше конца", &from, &later, false},
+		{"начало равно концу", &from, &from, false},
+		{"начало позже конца", &later, &from, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			svc := NewSongService(repo, nil, nil, nopEventLogger{}, ServiceConfig{DefaultSongsPageSize: 10, MaxSongsPageSize: 100}, newTestLogger())
+
+			_, err := svc.GetSongs(context.Background(), model.SongFilter{CreatedAtFrom: tt.from, CreatedAtTo: tt.to})
+			filters := repo.getSongsFilters()
+			if tt.wantErr {
+				if !errors.Is(err, model.ErrValidation) {
+					t.Errorf("GetSongs() error = %v, want %v", err, model.ErrValidation)
+				}
+				if len(filters) != 0 {
+					t.Errorf("репозиторий вызван с %+v при неверном диапазоне", filters)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetSongs() error = %v", err)
+			}
+			if len(filters) != 1 || filters[0].CreatedAtFrom != tt.from || filters[0].CreatedAtTo != tt.to {
+				t.Errorf("фильтр репозитория = %+v, want диапазон %v — %v", filters, tt.from, tt.to)
+			}
+		})
+	}
+}
+
+func TestGetSongsListFilters(t *testing.T) {
+	tooMany := make([]string, model.MaxFilterListItems+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("Group %d", i)
+	}
+
+	tests := []struct {
+		name    string
+		filter  model.SongFilter
+		wantErr string
+	}{
+		{"список групп", model.SongFilter{Groups: []string{"Muse", "Queen"}}, ""},
+		{"список названий", model.SongFilter{SongNames: []string{"Hysteria"}}, ""},
+		{"список групп и одиночное название", model.SongFilter{Groups: []string{"Muse"}, SongName: "Hysteria"}, ""},
+		{"максимальный размер списка", model.SongFilter{Groups: tooMany[:model.MaxFilterListItems]}, ""},
+		{"группа и список групп", model.SongFilter{Group: "Muse", Groups: []string{"Queen"}},
+			"group и groups нельзя указывать одновременно"},
+		{"название и список названий", model.SongFilter{SongName: "Hysteria", SongNames: []string{"Uprising"}},
+			"song и songs нельзя указывать одновременно"},
+		{"слишком длинный список групп", model.SongFilter{Groups: tooMany}, "groups должен содержать не больше 50 значений"},
+		{"слишком длинный список названий", model.SongFilter{SongNames: tooMany}, "songs должен содержать не больше 50 значений"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			svc := NewSongService(repo, nil, nil, nopEventLogger{}, ServiceConfig{DefaultSongsPageSize: 10, MaxSongsPageSize: 100}, newTestLogger())
+
+			_, err := svc.GetSongs(context.Background(), tt.filter)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("GetSongs() error = %v", err)
+				}
+				filters := repo.getSongsFilters()
+				if len(filters) != 1 || !reflect.DeepEqual(filters[0].Groups, tt.filter.Groups) ||
+					!reflect.DeepEqual(filters[0].SongNames, tt.filter.SongNames) {
+					t.Errorf("фильтр репозитория = %+v, want списки %v и %v", filters, tt.filter.Groups, tt.filter.SongNames)
+				}
+				return
+			}
+
+			var validationErr *model.ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Message != tt.wantErr {
+				t.Errorf("GetSongs() error = %v, want %q", err, tt.wantErr)
+			}
+			if filters := repo.getSongsFilters(); len(filters) != 0 {
+				t.Errorf("репозиторий вызван с %+v при неверном фильтре", filters)
+			}
+		})
+	}
+}
+
+func TestGetSongsQuickSearch(t *testing.T) {
+	tests := []struct {
+		name        string
+		filter      model.SongFilter
+		wantErr     bool
+		wantSnippet string
+		wantText    bool
+	}{
+		{"поиск с текстом", model.SongFilter{QuickSearch: "Bloom"}, false, "Paranoia is in <mark>bloom</mark>", true},
+		// Текст запрашивается у репозитория для фрагмента, но в ответ не попадает
+		{"поиск без текста", model.SongFilter{QuickSearch: "paranoia", OmitText: true}, false, "<mark>Paranoia</mark> is in bloom", false},
+		{"совпадение только в названии", model.SongFilter{QuickSearch: "uprising"}, false, "", true},
+		{"вместе с группой", model.SongFilter{QuickSearch: "bloom", Group: "Muse"}, true, "", false},
+		{"вместе с названием", model.SongFilter{QuickSearch: "bloom", SongName: "Uprising"}, true, "", false},
+		{"вместе с текстом", model.SongFilter{QuickSearch: "bloom", Text: "paranoia"}, true, "", false},
+		{"вместе со списком групп", model.SongFilter{QuickSearch: "bloom", Groups: []string{"Muse"}}, true, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			repo.put(&model.Song{ID: 1, Group: "Muse", Song: "Uprising", Text: "Paranoia is in bloom"})
+			svc := NewSongService(repo, nil, nil, nopEventLogger{}, ServiceConfig{DefaultSongsPageSize: 10, MaxSongsPageSize: 100}, newTestLogger())
+
+			songs, err := svc.GetSongs(context.Background(), tt.filter)
+			if tt.wantErr {
+				if !errors.Is(err, model.ErrValidation) {
+					t.Errorf("GetSongs() error = %v, want %v", err, model.ErrValidation)
+				}
+				if filters := repo.getSongsFilters(); len(filters) != 0 {
+					t.Errorf("репозиторий вызван с %+v при неверном фильтре", filters)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetSongs() error = %v", err)
+			}
+
+			filters := repo.getSongsFilters()
+			if len(filters) != 1 || filters[0].QuickSearch != tt.filter.QuickSearch || filters[0].OmitText {
+				t.Errorf("фильтр репозитория = %+v, want QuickSearch %q с текстом", filters, tt.filter.QuickSearch)
+			}
+			if len(songs) != 1 {
+				t.Fatalf("GetSongs() вернул %d песен, want 1", len(songs))
+			}
+			if songs[0].Snippet != tt.wantSnippet {
+				t.Errorf("Snippet = %q, want %q", songs[0].Snippet, tt.wantSnippet)
+			}
+			if (songs[0].Text != "") != tt.wantText {
+				t.Errorf("Text = %q, want текст %v", songs[0].Text, tt.wantText)
+			}
+		})
+	}
+}
+
+// nilListRepository возвращает пустые списки как nil, как sqlx без найденных строк
+type nilListRepository struct {
+	SongRepository
+}
+
+// GetSongs возвращает nil-список песен
+func (nilListRepository) GetSongs(context.Context, model.SongFilter) ([]*model.Song, error) {
+	return nil, nil
+}
+
+// GetSongVerses возвращает nil-список куплетов
+func (nilListRepository) GetSongVerses(context.Context, int64, model.VersesPagination) ([]string, int, error) {
+	return nil, 0, nil
+}
+
+func TestEmptyListsEncodeAsArrays(t *testing.T) {
+	svc := NewSongService(nilListRepository{}, nil, nil, nopEventLogger{}, ServiceConfig{
+		DefaultSongsPageSize:  10,
+		MaxSongsPageSize:      100,
+		DefaultVersesPageSize: 10,
+		MaxVersesPageSize:     100,
+	}, newTestLogger())
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		list func() (any, error)
+	}{
+		{"список песен", func() (any, error) { return svc.GetSongs(ctx, model.SongFilter{}) }},
+		{"куплеты песни", func() (any, error) {
+			verses, _, err := svc.GetSongVerses(ctx, 1, model.VersesPagination{})
+			return verses, err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := tt.list()
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			data, err := json.Marshal(list)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if string(data) != "[]" {
+				t.Errorf("JSON пустого списка = %s, want []", data)
+			}
+		})
+	}
+}
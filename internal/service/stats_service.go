@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"song-library/internal/model"
+	"song-library/pkg/cache"
+	"song-library/pkg/logger"
+	"time"
+)
+
+const (
+	// statsCacheTTL время хранения результатов статистики в памяти
+	statsCacheTTL = time.Minute
+	// maxGrowthBuckets максимальное количество интервалов в динамике роста
+	maxGrowthBuckets = 366
+	// maxTopGroupsLimit максимальный размер рейтинга групп
+	maxTopGroupsLimit = 100
+)
+
+// Показатели рейтинга групп
+const (
+	TopGroupsBySongs = "songs"
+	TopGroupsByPlays = "plays"
+)
+
+// StatsRepository интерфейс репозитория для статистики библиотеки
+type StatsRepository interface {
+	GetSongGrowth(ctx context.Context, interval string, from, to time.Time) ([]model.GrowthBucket, error)
+	GetTopGroupsBySongs(ctx context.Context, limit int) ([]model.GroupStat, error)
+	GetTopGroupsByPlays(ctx context.Context, limit int) ([]model.GroupStat, error)
+	GetEnrichmentStatusCounts(ctx context.Context) ([]model.EnrichmentStatusCount, error)
+}
+
+// StatsService сервис статистики для дашбордов.
+// Результаты кэшируются в памяти на statsCacheTTL; еще staleWindow после этого они отдаются сразу
+// и обновляются в фоне, поэтому тяжелые агрегирующие запросы выполняются с ожиданием только при холодном кэше.
+type StatsService struct {
+	repo   StatsRepository
+	logger *logger.Logger
+
+	growth     *cache.SWRCache[[]model.GrowthBucket]
+	topGroups  *cache.SWRCache[[]model.GroupStat]
+	enrichment *cache.SWRCache[[]model.EnrichmentStatusCount]
+}
+
+// NewStatsService создает новый сервис статистики с окном отдачи устаревших результатов staleWindow (0 — не отдавать)
+func NewStatsService(repo StatsRepository, staleWindow time.Duration, logger *logger.Logger) *StatsService {
+	onError := func(key string, err error) {
+		logger.Warn("Ошибка фонового обновления статистики, отдается прежний результат", "key", key, "error", err)
+	}
+	return &StatsService{
+		repo:       repo,
+		logger:     logger,
+		growth:     cache.NewSWRCache[[]model.GrowthBucket](statsCacheTTL, staleWindow, onError),
+		topGroups:  cache.NewSWRCache[[]model.GroupStat](statsCacheTTL, staleWindow, onError),
+		enrichment: cache.NewSWRCache[[]model.EnrichmentStatusCount](statsCacheTTL, staleWindow, onError),
+	}
+}
+
+// GetGrowth возвращает количество добавленных песен по интервалам day, week или month в диапазоне [from, to).
+// Интервалы без новых песен заполняются нулями. По умолчанию to — конец текущего дня,
+// from — 30 дней, 12 недель или 12 месяцев до to в зависимости от интервала.
+// Вместе с результатом возвращается его возраст в кэше.
+func (s *StatsService) GetGrowth(ctx context.Context, interval string, fromParam, toParam *time.Time) ([]model.GrowthBucket, time.Duration, error) {
+	log := s.logger.WithContext(ctx)
+
+	log.Debug("Получение динамики роста библиотеки", "interval", interval, "from", fromParam, "to", toParam)
+
+	if interval == "" {
+		interval = model.GrowthIntervalDay
+	}
+
+	var to time.Time
+	if toParam != nil {
+		to = *toParam
+	} else {
+		now := time.Now()
+		to = time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+	}
+
+	var from time.Time
+	if fromParam != nil {
+		from = *fromParam
+	} else {
+		switch interval {
+		case model.GrowthIntervalWeek:
+			from = to.AddDate(0, 0, -12*7)
+		case model.GrowthIntervalMonth:
+			from = to.AddDate(0, -12, 0)
+		default:
+			from = to.AddDate(0, 0, -30)
+		}
+	}
+
+	if !from.Before(to) {
+		return nil, 0, model.NewValidationError("from должен быть раньше to")
+	}
+
+	starts, err := growthBucketStarts(interval, from, to)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	key := fmt.Sprintf("%s:%d:%d", interval, from.Unix(), to.Unix())
+	result, age, err := s.growth.Get(ctx, key, func(ctx context.Context) ([]model.GrowthBucket, error) {
+		counts, err := s.repo.GetSongGrowth(ctx, interval, starts[0], to)
+		if err != nil {
+			return nil, err
+		}
+
+		byBucket := make(map[string]int64, len(counts))
+		for _, bucket := range counts {
+			byBucket[bucket.Bucket] = bucket.Count
+		}
+
+		result := make([]model.GrowthBucket, 0, len(starts))
+		for _, start := range starts {
+			day := start.Format(time.DateOnly)
+			result = append(result, model.GrowthBucket{Bucket: day, Count: byBucket[day]})
+		}
+		return result, nil
+	})
+	if err != nil {
+		log.Error("Ошибка получения динамики роста из репозитория", "error", err)
+		return nil, 0, fmt.Errorf("ошибка получения динамики роста: %w", err)
+	}
+
+	log.Info("Динамика роста библиотеки успешно получена", "buckets", len(result), "age", age)
+	return result, age, nil
+}
+
+// growthBucketStarts возвращает начала интервалов, пересекающихся с [from, to), выровненные так же, как date_trunc
+func growthBucketStarts(interval string, from, to time.Time) ([]time.Time, error) {
+	var (
+		start time.Time
+		next  func(time.Time) time.Time
+	)
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	switch interval {
+	case model.GrowthIntervalDay:
+		start = day
+		next = func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }
+	case model.GrowthIntervalWeek:
+		// date_trunc('week') выравнивает по понедельнику
+		start = day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		next = func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }
+	case model.GrowthIntervalMonth:
+		start = time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, from.Location())
+		next = func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }
+	default:
+		return nil, model.NewValidationError("interval должен быть day, week или month")
+	}
+
+	var starts []time.Time
+	for t := start; t.Before(to); t = next(t) {
+		if len(starts) == maxGrowthBuckets {
+			return nil, model.NewValidationError(fmt.Sprintf("диапазон содержит больше %d интервалов", maxGrowthBuckets))
+		}
+		starts = append(starts, t)
+	}
+	return starts, nil
+}
+
+// GetTopGroups возвращает рейтинг групп по количеству песен (songs) или обращений (plays)
+// и возраст результата в кэше
+func (s *StatsService) GetTopGroups(ctx context.Context, by string, limit int) ([]model.GroupStat, time.Duration, error) {
+	log := s.logger.WithContext(ctx)
+
+	log.Debug("Получение рейтинга групп", "by", by, "limit", limit)
+
+	if limit <= 0 || limit > maxTopGroupsLimit {
+		return nil, 0, model.NewValidationError(fmt.Sprintf("limit должен быть от 1 до %d", maxTopGroupsLimit))
+	}
+
+	var fetch func(ctx context.Context, limit int) ([]model.GroupStat, error)
+	switch by {
+	case TopGroupsBySongs:
+		fetch = s.repo.GetTopGroupsBySongs
+	case TopGroupsByPlays:
+		fetch = s.repo.GetTopGroupsByPlays
+	default:
+		return nil, 0, model.NewValidationError("by должен быть songs или plays")
+	}
+
+	key := fmt.Sprintf("%s:%d", by, limit)
+	groups, age, err := s.topGroups.Get(ctx, key, func(ctx context.Context) ([]model.GroupStat, error) {
+		return fetch(ctx, limit)
+	})
+	if err != nil {
+		log.Error("Ошибка получения рейтинга групп из репозитория", "error", err)
+		return nil, 0, fmt.Errorf("ошибка получения рейтинга групп: %w", err)
+	}
+
+	log.Info("Рейтинг групп успешно получен", "count", len(groups), "age", age)
+	return groups, age, nil
+}
+
+// GetEnrichmentStatuses возвращает количество активных песен по каждому статусу получения данных от поставщика,
+// включая статусы без песен, и возраст результата в кэше
+func (s *StatsService) GetEnrichmentStatuses(ctx context.Context) ([]model.EnrichmentStatusCount, time.Duration, error) {
+	log := s.logger.WithContext(ctx)
+
+	log.Debug("Получение количества песен по статусам получения данных")
+
+	counts, age, err := s.enrichment.Get(ctx, "all", s.repo.GetEnrichmentStatusCounts)
+	if err != nil {
+		log.Error("Ошибка получения количества песен по статусам получения данных из репозитория", "error", err)
+		return nil, 0, fmt.Errorf("ошибка получения количества песен по статусам получения данных: %w", err)
+	}
+
+	byStatus := make(map[string]int64, len(counts))
+	for _, count := range counts {
+		byStatus[count.Status] = count.Count
+	}
+	result := make([]model.EnrichmentStatusCount, 0, len(model.EnrichmentStatuses))
+	for _, status := range model.EnrichmentStatuses {
+		result = append(result, model.EnrichmentStatusCount{Status: status, Count: byStatus[status]})
+	}
+
+	log.Info("Количество песен по статусам получения данных успешно получено", "age", age)
+	return result, age, nil
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// diffContextLines количество неизмененных строк вокруг изменений в унифицированном диффе
+	diffContextLines = 3
+	// maxDiffLines максимальное количество строк диффа; остальные заменяются пометкой об обрезке
+	maxDiffLines = 500
+	// maxDiffCells предельный размер таблицы LCS. Если измененный фрагмент больше,
+	// дифф показывает его как удаление всех старых строк и добавление всех новых
+	maxDiffCells = 1 << 20
+)
+
+// diffLine строка диффа: op — ' ' без изменений, '-' удалена, '+' добавлена
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// unifiedDiff возвращает построчный дифф между oldText и newText в унифицированном формате
+// или пустую строку, если тексты совпадают. Длинный дифф обрезается до maxDiffLines строк.
+func unifiedDiff(oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+
+	out := formatUnified(diffLines(splitLines(oldText), splitLines(newText)))
+	if len(out) > maxDiffLines {
+		skipped := len(out) - maxDiffLines
+		out = append(out[:maxDiffLines], fmt.Sprintf("... дифф обрезан, пропущено строк: %d", skipped))
+	}
+	return strings.Join(out, "\n")
+}
+
+// splitLines разбивает текст на строки; пустой текст не содержит строк
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// diffLines строит последовательность строк диффа. Общие начало и конец текстов отбрасываются
+// до сравнения, поэтому правка в длинном тексте обходится таблицей по размеру измененного фрагмента.
+func diffLines(a, b []string) []diffLine {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]diffLine, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		lines = append(lines, diffLine{' ', line})
+	}
+	lines = append(lines, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		lines = append(lines, diffLine{' ', line})
+	}
+	return lines
+}
+
+// diffMiddle сравнивает измененный фрагмент по наибольшей общей подпоследовательности строк
+func diffMiddle(a, b []string) []diffLine {
+	n, m := len(a), len(b)
+	lines := make([]diffLine, 0, n+m)
+	if n*m > maxDiffCells {
+		for _, line := range a {
+			lines = append(lines, diffLine{'-', line})
+		}
+		for _, line := range b {
+			lines = append(lines, diffLine{'+', line})
+		}
+		return lines
+	}
+
+	// lcs[i*(m+1)+j] — длина общей подпоследовательности a[i:] и b[j:]
+	lcs := make([]int32, (n+1)*(m+1))
+	at := func(i, j int) int32 { return lcs[i*(m+1)+j] }
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i*(m+1)+j] = at(i+1, j+1) + 1
+			} else {
+				lcs[i*(m+1)+j] = max(at(i+1, j), at(i, j+1))
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, diffLine{' ', a[i]})
+			i++
+			j++
+		case at(i+1, j) >= at(i, j+1):
+			lines = append(lines, diffLine{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		lines = append(lines, diffLine{'-', a[i]})
+	}
+	for ; j < m; j++ {
+		lines = append(lines, diffLine{'+', b[j]})
+	}
+	return lines
+}
+
+// formatUnified группирует изменения в блоки с diffContextLines строками контекста
+func formatUnified(lines []diffLine) []string {
+	// oldPos[k] и newPos[k] — количество строк старого и нового текста перед lines[k]
+	oldPos := make([]int, len(lines)+1)
+	newPos := make([]int, len(lines)+1)
+	for k, line := range lines {
+		oldPos[k+1], newPos[k+1] = oldPos[k], newPos[k]
+		if line.op != '+' {
+			oldPos[k+1]++
+		}
+		if line.op != '-' {
+			newPos[k+1]++
+		}
+	}
+
+	out := []string{"--- before", "+++ after"}
+	for k := 0; k < len(lines); {
+		if lines[k].op == ' ' {
+			k++
+			continue
+		}
+
+		start := max(k-diffContextLines, 0)
+		last := k
+		for j := k; j < len(lines) && j-last <= 2*diffContextLines; j++ {
+			if lines[j].op != ' ' {
+				last = j
+			}
+		}
+		end := min(last+diffContextLines+1, len(lines))
+
+		out = append(out, fmt.Sprintf("@@ -%s +%s @@",
+			hunkRange(oldPos[start], oldPos[end]), hunkRange(newPos[start], newPos[end])))
+		for _, line := range lines[start:end] {
+			out = append(out, string(line.op)+line.text)
+		}
+		k = end
+	}
+	return out
+}
+
+// hunkRange возвращает диапазон строк блока в формате start,count; пустой блок указывает на строку перед ним
+func hunkRange(from, to int) string {
+	if to == from {
+		return fmt.Sprintf("%d,0", from)
+	}
+	return fmt.Sprintf("%d,%d", from+1, to-from)
+}
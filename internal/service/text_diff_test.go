@@ -0,0 +1,101 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// numberedLines возвращает строки "1".."n", в которых строки с номерами из replace заменены значениями
+func numberedLines(n int, replace map[int]string) string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprint(i + 1)
+		if value, ok := replace[i+1]; ok {
+			lines[i] = value
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldText string
+		newText string
+		want    string
+	}{
+		{"тексты совпадают", "Ooh baby", "Ooh baby", ""},
+		{"изменена единственная строка", "Ooh baby", "Oh baby",
+			"--- before\n+++ after\n@@ -1,1 +1,1 @@\n-Ooh baby\n+Oh baby"},
+		{"текст добавлен", "", "a\nb",
+			"--- before\n+++ after\n@@ -0,0 +1,2 @@\n+a\n+b"},
+		{"текст удален", "a\nb", "",
+			"--- before\n+++ after\n@@ -1,2 +0,0 @@\n-a\n-b"},
+		{"строка вставлена", "a\nc", "a\nb\nc",
+			"--- before\n+++ after\n@@ -1,2 +1,3 @@\n a\n+b\n c"},
+		{"три строки контекста", numberedLines(10, nil), numberedLines(10, map[int]string{5: "X"}),
+			"--- before\n+++ after\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+X\n 6\n 7\n 8"},
+		{"далекие изменения в разных блоках", numberedLines(20, nil), numberedLines(20, map[int]string{2: "A", 18: "B"}),
+			"--- before\n+++ after\n@@ -1,5 +1,5 @@\n 1\n-2\n+A\n 3\n 4\n 5\n" +
+				"@@ -15,6 +15,6 @@\n 15\n 16\n 17\n-18\n+B\n 19\n 20"},
+		{"близкие изменения в одном блоке", numberedLines(12, nil), numberedLines(12, map[int]string{2: "A", 8: "B"}),
+			"--- before\n+++ after\n@@ -1,11 +1,11 @@\n 1\n-2\n+A\n 3\n 4\n 5\n 6\n 7\n-8\n+B\n 9\n 10\n 11"},
+		{"изменение посередине по LCS", "a\nb\nc\nd", "a\nc\nb\nd",
+			"--- before\n+++ after\n@@ -1,4 +1,4 @@\n a\n-b\n c\n+b\n d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff(tt.oldText, tt.newText); got != tt.want {
+				t.Errorf("unifiedDiff() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiffTruncated(t *testing.T) {
+	oldText := numberedLines(400, nil)
+	newText := strings.ReplaceAll(oldText, "\n", "x\n") + "x"
+
+	lines := strings.Split(unifiedDiff(oldText, newText), "\n")
+	// Заголовки, заголовок блока и по строке удаления и добавления на каждую из 400 строк
+	const total = 2 + 1 + 800
+	if len(lines) != maxDiffLines+1 {
+		t.Fatalf("строк диффа = %d, want %d", len(lines), maxDiffLines+1)
+	}
+	if want := fmt.Sprintf("... дифф обрезан, пропущено строк: %d", total-maxDiffLines); lines[maxDiffLines] != want {
+		t.Errorf("последняя строка = %q, want %q", lines[maxDiffLines], want)
+	}
+}
+
+func TestDiffMiddleFallback(t *testing.T) {
+	// Таблица LCS для фрагментов 1100×1100 больше maxDiffCells: общие строки не ищутся
+	const n = 1100
+	a := make([]string, n)
+	b := make([]string, n)
+	for i := range n {
+		a[i] = fmt.Sprint(i)
+		b[i] = fmt.Sprint(i)
+	}
+	b[0] = "changed"
+
+	lines := diffMiddle(a, b)
+	if len(lines) != 2*n {
+		t.Fatalf("строк = %d, want %d", len(lines), 2*n)
+	}
+	for k, line := range lines {
+		want := byte('-')
+		if k >= n {
+			want = '+'
+		}
+		if line.op != want {
+			t.Fatalf("lines[%d].op = %q, want %q", k, line.op, want)
+		}
+	}
+
+	// Общие начало и конец отбрасываются до сравнения, поэтому та же правка дает один блок
+	if got := diffLines(a, b); len(got) != n+1 {
+		t.Errorf("diffLines() строк = %d, want %d", len(got), n+1)
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"bufio"
+	"song-library/internal/model"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// minFormatWidth минимальная ширина строки форматированного текста
+	minFormatWidth = 20
+	// maxFormatWidth максимальная ширина строки форматированного текста
+	maxFormatWidth = 200
+	// maxFormatIndent максимальный отступ куплета
+	maxFormatIndent = 20
+)
+
+// FormatText переносит строки текста песни по границам слов так, чтобы они не превышали width символов,
+// и добавляет к каждой строке куплета indent пробелов. Слова длиннее width не разрываются и занимают
+// отдельную строку. Разделители куплетов и пустые строки сохраняются.
+func FormatText(text string, width, indent int) string {
+	prefix := strings.Repeat(" ", indent)
+
+	verses := strings.Split(text, model.VerseDelimiter)
+	for i, verse := range verses {
+		var lines []string
+		scanner := bufio.NewScanner(strings.NewReader(verse))
+		scanner.Buffer(make([]byte, 0, 64*1024), len(verse)+1)
+		for scanner.Scan() {
+			for _, line := range wrapLine(scanner.Text(), width) {
+				if line != "" {
+					line = prefix + line
+				}
+				lines = append(lines, line)
+			}
+		}
+		verses[i] = strings.Join(lines, "\n")
+	}
+
+	return strings.Join(verses, model.VerseDelimiter)
+}
+
+// wrapLine разбивает строку на части не длиннее width символов по границам слов.
+// Пустая строка возвращается как одна пустая строка.
+func wrapLine(line string, width int) []string {
+	words := strings.Fields(line)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	var (
+		lines   []string
+		current strings.Builder
+		length  int
+	)
+	for _, word := range words {
+		wordLength := utf8.RuneCountInString(word)
+		if length > 0 && length+1+wordLength > width {
+			lines = append(lines, current.String())
+			current.Reset()
+			length = 0
+		}
+		if length > 0 {
+			current.WriteByte(' ')
+			length++
+		}
+		current.WriteString(word)
+		length += wordLength
+	}
+	return append(lines, current.String())
+}
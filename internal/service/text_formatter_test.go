@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"song-library/internal/model"
+	"testing"
+)
+
+func TestFormatText(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		width  int
+		indent int
+		want   string
+	}{
+		{"пустой текст", "", 20, 2, ""},
+		{"короткие строки без изменений", "Ooh baby\nYou set my soul alight", 40, 0, "Ooh baby\nYou set my soul alight"},
+		{"перенос по границе слов", "one two three four five", 9, 0, "one two\nthree\nfour five"},
+		{"отступ у каждой строки", "one two three", 8, 2, "  one two\n  three"},
+		{"разделители куплетов сохраняются", "one two three\n\nfour five six", 8, 1, " one two\n three\n\n four\n five six"},
+		{"пустая строка внутри куплета без отступа", "one\n\n\nsix", 20, 2, "  one\n\n\n  six"},
+		{"длинное слово занимает отдельную строку", "a supercalifragilistic b", 5, 0, "a\nsupercalifragilistic\nb"},
+		{"ширина считается в символах", "ёлка ёлка ёлка", 9, 0, "ёлка ёлка\nёлка"},
+		{"лишние пробелы схлопываются", "  one   two  ", 20, 0, "one two"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatText(tt.text, tt.width, tt.indent); got != tt.want {
+				t.Errorf("FormatText(%q, %d, %d) = %q, want %q", tt.text, tt.width, tt.indent, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		width int
+		want  []string
+	}{
+		{"пустая строка", "", 10, []string{""}},
+		{"только пробелы", "   ", 10, []string{""}},
+		{"ровно по ширине", "one two", 7, []string{"one two"}},
+		{"на символ шире", "one two", 6, []string{"one", "two"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wrapLine(tt.line, tt.width); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("wrapLine(%q, %d) = %q, want %q", tt.line, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetFormattedText(t *testing.T) {
+	repo := newMemoryRepository()
+	repo.put(&model.Song{ID: 1, Group: "Muse", Song: "Hysteria", Text: "It's bugging me grating me\n\nAnd twisting me around"})
+	svc := NewSongService(repo, nil, nil, nopEventLogger{}, ServiceConfig{}, newTestLogger())
+
+	tests := []struct {
+		name    string
+		id      int64
+		width   int
+		indent  int
+		want    string
+		wantErr error
+	}{
+		{"форматирование", 1, 20, 2, "  It's bugging me\n  grating me\n\n  And twisting me\n  around", nil},
+		{"ширина меньше минимума", 1, minFormatWidth - 1, 0, "", model.ErrValidation},
+		{"ширина больше максимума", 1, maxFormatWidth + 1, 0, "", model.ErrValidation},
+		{"отрицательный отступ", 1, 40, -1, "", model.ErrValidation},
+		{"отступ больше максимума", 1, 40, maxFormatIndent + 1, "", model.ErrValidation},
+		{"песня не найдена", 7, 40, 0, "", model.ErrSongNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.GetFormattedText(context.Background(), tt.id, tt.width, tt.indent)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetFormattedText() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetFormattedText() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetFormattedText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
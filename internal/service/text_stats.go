@@ -0,0 +1,109 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"song-library/internal/model"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// defaultStopwords встроенный список частых русских и английских слов, исключаемых из статистики
+var defaultStopwords = []string{
+	"и", "в", "во", "не", "что", "он", "на", "я", "с", "со", "как", "а", "то", "все", "она", "так", "его",
+	"но", "да", "ты", "к", "у", "же", "вы", "за", "бы", "по", "только", "ее", "мне", "было", "вот", "от",
+	"меня", "еще", "нет", "о", "из", "ему", "теперь", "когда", "даже", "ну", "ли", "если", "уже", "или",
+	"ни", "быть", "был", "него", "до", "вас", "нибудь", "опять", "уж", "вам", "ведь", "там", "потом",
+	"себя", "ничего", "ей", "может", "они", "тут", "где", "есть", "надо", "ней", "для", "мы", "тебя",
+	"их", "чем", "была", "сам", "чтоб", "без", "будто", "чего", "раз", "тоже", "себе", "под", "будет",
+	"ж", "тогда", "кто", "этот", "того", "потому", "этого", "какой", "совсем", "ним", "здесь", "этом",
+	"один", "почти", "мой", "тем", "чтобы", "нее", "были", "куда", "зачем", "всех", "никогда", "можно",
+	"при", "наконец", "два", "об", "другой", "хоть", "после", "над", "больше", "тот", "через", "эти",
+	"нас", "про", "всего", "них", "какая", "много", "разве", "эту", "моя", "впрочем", "хорошо", "свою",
+	"этой", "перед", "иногда", "лучше", "чуть", "том", "нельзя", "такой", "им", "более", "всегда",
+	"конечно", "всю", "между",
+	"a", "an", "the", "and", "or", "but", "if", "of", "at", "by", "for", "with", "about", "to", "from",
+	"in", "on", "up", "out", "off", "over", "under", "is", "are", "was", "were", "be", "been", "being",
+	"am", "have", "has", "had", "do", "does", "did", "i", "me", "my", "you", "your", "he", "him", "his",
+	"she", "her", "it", "its", "we", "us", "our", "they", "them", "their", "this", "that", "these",
+	"those", "so", "not", "no", "can", "will", "just", "all", "as", "what", "when", "where", "who",
+	"how", "then", "there", "here", "too", "very", "oh", "yeah",
+}
+
+// TextAnalyzer вычисляет статистику по тексту песен
+type TextAnalyzer struct {
+	stopwords map[string]struct{}
+}
+
+// NewTextAnalyzer создает анализатор текста. Если stopwordsFile не пуст, список стоп-слов
+// загружается из файла (по одному слову в строке) вместо встроенного
+func NewTextAnalyzer(stopwordsFile string) (*TextAnalyzer, error) {
+	words := defaultStopwords
+	if stopwordsFile != "" {
+		loaded, err := loadStopwords(stopwordsFile)
+		if err != nil {
+			return nil, err
+		}
+		words = loaded
+	}
+
+	stopwords := make(map[string]struct{}, len(words))
+	for _, word := range words {
+		stopwords[strings.ToLower(word)] = struct{}{}
+	}
+	return &TextAnalyzer{stopwords: stopwords}, nil
+}
+
+// loadStopwords читает стоп-слова из файла, пропуская пустые строки
+func loadStopwords(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия файла стоп-слов: %w", err)
+	}
+	defer file.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if word := strings.TrimSpace(scanner.Text()); word != "" {
+			words = append(words, word)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения файла стоп-слов: %w", err)
+	}
+
+	return words, nil
+}
+
+// WordFrequency подсчитывает частоту слов в тексте без учета регистра, знаков препинания и стоп-слов.
+// Результат отсортирован по убыванию частоты, при равенстве — по алфавиту
+func (a *TextAnalyzer) WordFrequency(text string) []model.WordCount {
+	counts := make(map[string]int)
+	for _, token := range strings.Fields(text) {
+		word := strings.ToLower(strings.TrimFunc(token, unicode.IsPunct))
+		if word == "" {
+			continue
+		}
+		if _, stop := a.stopwords[word]; stop {
+			continue
+		}
+		counts[word]++
+	}
+
+	result := make([]model.WordCount, 0, len(counts))
+	for word, count := range counts {
+		result = append(result, model.WordCount{Word: word, Count: count})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Word < result[j].Word
+	})
+
+	return result
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"song-library/internal/model"
+	"testing"
+)
+
+func TestWordFrequency(t *testing.T) {
+	analyzer, err := NewTextAnalyzer("")
+	if err != nil {
+		t.Fatalf("NewTextAnalyzer() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want []model.WordCount
+	}{
+		{"пустой текст", "", []model.WordCount{}},
+		{"только стоп-слова", "and the I и в не", []model.WordCount{}},
+		{"регистр и знаки препинания", "Baby, baby! BABY? love... (love)", []model.WordCount{{Word: "baby", Count: 3}, {Word: "love", Count: 2}}},
+		{"равная частота по алфавиту", "zebra apple mango apple zebra", []model.WordCount{{Word: "apple", Count: 2}, {Word: "zebra", Count: 2}, {Word: "mango", Count: 1}}},
+		{"кириллица и стоп-слова", "Ёлка в лесу, ёлка у дома\n\nЁЛКА", []model.WordCount{{Word: "ёлка", Count: 3}, {Word: "дома", Count: 1}, {Word: "лесу", Count: 1}}},
+		{"пунктуация внутри слова сохраняется", "don't rock'n'roll -- ...", []model.WordCount{{Word: "don't", Count: 1}, {Word: "rock'n'roll", Count: 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := analyzer.WordFrequency(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WordFrequency() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTextAnalyzerStopwordsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stopwords.txt")
+	if err := os.WriteFile(path, []byte("Baby\n\n  love  \n"), 0o600); err != nil {
+		t.Fatalf("ошибка записи файла стоп-слов: %v", err)
+	}
+
+	analyzer, err := NewTextAnalyzer(path)
+	if err != nil {
+		t.Fatalf("NewTextAnalyzer() error = %v", err)
+	}
+	// Список из файла заменяет встроенный: the больше не стоп-слово
+	want := []model.WordCount{{Word: "the", Count: 1}, {Word: "world", Count: 1}}
+	if got := analyzer.WordFrequency("baby love the world"); !reflect.DeepEqual(got, want) {
+		t.Errorf("WordFrequency() = %v, want %v", got, want)
+	}
+
+	if _, err = NewTextAnalyzer(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("NewTextAnalyzer() отсутствующего файла error = nil")
+	}
+}
+
+func TestGetWordFrequency(t *testing.T) {
+	analyzer, err := NewTextAnalyzer("")
+	if err != nil {
+		t.Fatalf("NewTextAnalyzer() error = %v", err)
+	}
+	repo := newMemoryRepository()
+	repo.put(&model.Song{ID: 1, Group: "Muse", Song: "Hysteria", Text: "mind mind mind soul soul heart"})
+	svc := NewSongService(repo, nil, analyzer, nopEventLogger{}, ServiceConfig{}, newTestLogger())
+
+	tests := []struct {
+		name    string
+		id      int64
+		top     int
+		want    []model.WordCount
+		wantErr error
+	}{
+		{"первые слова", 1, 2, []model.WordCount{{Word: "mind", Count: 3}, {Word: "soul", Count: 2}}, nil},
+		{"top больше количества слов", 1, 100, []model.WordCount{{Word: "mind", Count: 3}, {Word: "soul", Count: 2}, {Word: "heart", Count: 1}}, nil},
+		{"нулевой top", 1, 0, nil, model.ErrValidation},
+		{"top больше максимума", 1, maxWordFrequencyTop + 1, nil, model.ErrValidation},
+		{"песня не найдена", 7, 10, nil, model.ErrSongNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.GetWordFrequency(context.Background(), tt.id, tt.top)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetWordFrequency() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetWordFrequency() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetWordFrequency() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"song-library/internal/model"
+	"time"
+)
+
+// PurgeTrash окончательно удаляет песни, помеченные удаленными раньше чем olderThan назад.
+// Песни удаляются пачками по TrashPurgeBatchSize, каждая пачка фиксируется отдельно;
+// за вызов удаляется не больше TrashPurgeMaxPerRun песен. С keepHistory события песен сохраняются.
+func (s *SongService) PurgeTrash(ctx context.Context, olderThan time.Duration, keepHistory bool) (*model.TrashPurgeResult, error) {
+	log := s.logger.WithContext(ctx)
+
+	log.Debug("Очистка корзины", "older_than", olderThan.String(), "keep_history", keepHistory)
+
+	if olderThan <= 0 {
+		return nil, model.NewValidationError("срок хранения должен быть положительным")
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	result := &model.TrashPurgeResult{}
+	for {
+		limit := min(s.cfg.TrashPurgeBatchSize, s.cfg.TrashPurgeMaxPerRun-int(result.Purged))
+		if limit <= 0 {
+			result.Truncated = true
+			break
+		}
+
+		purged, err := s.repo.PurgeDeletedSongs(ctx, cutoff, limit, keepHistory)
+		if err != nil {
+			log.Error("Ошибка удаления песен из корзины", "error", err, "purged", result.Purged)
+			return nil, fmt.Errorf("ошибка очистки корзины: %w", err)
+		}
+		result.Purged += purged
+		if purged < int64(limit) {
+			break
+		}
+	}
+
+	if result.Purged > 0 {
+		_ = s.logEvent(ctx, model.EventTrashPurged, nil, map[string]interface{}{
+			"purged": result.Purged, "older_than": olderThan.String(), "keep_history": keepHistory,
+		})
+	}
+
+	log.Info("Корзина очищена", "purged", result.Purged, "truncated", result.Truncated)
+	return result, nil
+}
+
+// RunTrashPurge периодически удаляет из корзины песни старше retention и блокируется до отмены ctx.
+// События удаленных песен сохраняются. Пока paused возвращает true (режим только для чтения), очистка пропускается
+func (s *SongService) RunTrashPurge(ctx context.Context, interval, retention time.Duration, paused func() bool) {
+	s.logger.Info("Запуск очистки корзины", "interval", interval.String(), "retention", retention.String())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Очистка корзины остановлена")
+			return
+		case <-ticker.C:
+		}
+		if paused() {
+			s.logger.Debug("Очистка корзины пропущена в режиме только для чтения")
+			continue
+		}
+
+		if _, err := s.PurgeTrash(ctx, retention, true); err != nil && ctx.Err() == nil {
+			s.logger.Error("Ошибка плановой очистки корзины", "error", err)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"song-library/internal/model"
+	"strconv"
+	"time"
+)
+
+const (
+	// maxTrendingLimit максимальное количество трендовых песен в одном запросе
+	maxTrendingLimit = 100
+	// trendingCacheTTL время хранения списка трендовых песен в кэше: журнал обращений большой, а тренды меняются медленно
+	trendingCacheTTL = 5 * time.Minute
+	// trendingCacheSize количество сочетаний периода и количества песен в кэше
+	trendingCacheSize = 64
+)
+
+// GetTrendingSongs получает песни с наибольшим количеством обращений за период day, week или month.
+// Результат кэшируется в памяти процесса на trendingCacheTTL.
+func (s *SongService) GetTrendingSongs(ctx context.Context, period string, limit int) ([]*model.Song, error) {
+	log := s.logger.WithContext(ctx)
+
+	log.Debug("Получение трендовых песен", "period", period, "limit", limit)
+
+	switch period {
+	case "":
+		period = model.TrendingPeriodDay
+	case model.TrendingPeriodDay, model.TrendingPeriodWeek, model.TrendingPeriodMonth:
+	default:
+		return nil, model.NewValidationError("period должен быть day, week или month")
+	}
+	if limit <= 0 || limit > maxTrendingLimit {
+		return nil, model.NewValidationError(fmt.Sprintf("limit должен быть от 1 до %d", maxTrendingLimit))
+	}
+
+	key := period + ":" + strconv.Itoa(limit)
+	if songs, ok := s.trendingCache.Get(key); ok {
+		log.Info("Трендовые песни получены из кэша", "count", len(songs))
+		return songs, nil
+	}
+
+	songs, err := s.repo.GetTrendingSongs(ctx, period, limit)
+	if err != nil {
+		log.Error("Ошибка получения трендовых песен из репозитория", "error", err)
+		return nil, fmt.Errorf("ошибка получения трендовых песен: %w", err)
+	}
+	s.trendingCache.Put(key, songs)
+
+	log.Info("Трендовые песни успешно получены", "count", len(songs))
+	return songs, nil
+}
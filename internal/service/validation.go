@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"golang.org/x/text/unicode/norm"
+	"song-library/internal/model"
+	"strings"
+	"unicode/utf8"
+)
+
+// validateSongLimits проверяет, что текст и ссылка песни не превышают настроенные ограничения
+func (s *SongService) validateSongLimits(song *model.Song) error {
+	if s.cfg.MaxTextLength > 0 && len(song.Text) > s.cfg.MaxTextLength {
+		return &model.LimitError{Field: "text", Limit: s.cfg.MaxTextLength}
+	}
+	if s.cfg.MaxLinkLength > 0 && len(song.Link) > s.cfg.MaxLinkLength {
+		return &model.LimitError{Field: "link", Limit: s.cfg.MaxLinkLength}
+	}
+	return nil
+}
+
+// truncateSongLimits обрезает текст и ссылку, полученные из внешнего API, до настроенных ограничений
+func (s *SongService) truncateSongLimits(ctx context.Context, song *model.Song) {
+	log := s.logger.WithContext(ctx)
+
+	if s.cfg.MaxTextLength > 0 && len(song.Text) > s.cfg.MaxTextLength {
+		log.Warn("Текст песни из внешнего API превышает ограничение и будет обрезан",
+			"group", song.Group, "song", song.Song, "length", len(song.Text), "limit", s.cfg.MaxTextLength)
+		song.Text = truncateUTF8(song.Text, s.cfg.MaxTextLength)
+	}
+	if s.cfg.MaxLinkLength > 0 && len(song.Link) > s.cfg.MaxLinkLength {
+		log.Warn("Ссылка на песню из внешнего API превышает ограничение и будет обрезана",
+			"group", song.Group, "song", song.Song, "length", len(song.Link), "limit", s.cfg.MaxLinkLength)
+		song.Link = truncateUTF8(song.Link, s.cfg.MaxLinkLength)
+	}
+}
+
+// truncateUTF8 обрезает строку до maxBytes байт, не разрывая многобайтовые символы
+func truncateUTF8(value string, maxBytes int) string {
+	if len(value) <= maxBytes {
+		return value
+	}
+
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(value[cut]) {
+		cut--
+	}
+	return value[:cut]
+}
+
+// sanitizeString проверяет, что значение поля является корректной строкой UTF-8, и нормализует его в NFC.
+// Некорректные последовательности заменяются символом U+FFFD, а в строгом режиме приводят к EncodingError.
+func (s *SongService) sanitizeString(field, value string) (string, error) {
+	if !utf8.ValidString(value) {
+		if s.cfg.StrictUTF8 {
+			return "", &model.EncodingError{Field: field}
+		}
+		value = strings.ToValidUTF8(value, string(utf8.RuneError))
+	}
+	return norm.NFC.String(value), nil
+}
+
+// sanitizeSong применяет sanitizeString ко всем текстовым полям песни
+func (s *SongService) sanitizeSong(song *model.Song) error {
+	fields := []struct {
+		name  string
+		value *string
+	}{
+		{"group", &song.Group},
+		{"song", &song.Song},
+		{"releaseDate", &song.ReleaseDate},
+		{"text", &song.Text},
+		{"link", &song.Link},
+	}
+
+	for _, field := range fields {
+		sanitized, err := s.sanitizeString(field.name, *field.value)
+		if err != nil {
+			return err
+		}
+		*field.value = sanitized
+	}
+	if song.Copyright != nil {
+		sanitized, err := s.sanitizeString(model.FieldCopyright, *song.Copyright)
+		if err != nil {
+			return err
+		}
+		song.Copyright = &sanitized
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"errors"
+	"song-library/internal/model"
+	"testing"
+)
+
+func TestSanitizeString(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		strict  bool
+		want    string
+		wantErr bool
+	}{
+		{"ASCII без изменений", "Hysteria", false, "Hysteria", false},
+		{"NFD приводится к NFC", "Moto\u0308rhead", false, "Motörhead", false},
+		{"NFC без изменений", "Motörhead", true, "Motörhead", false},
+		{"некорректная последовательность заменяется", "Mot\xffrhead", false, "Mot�rhead", false},
+		{"подряд идущие некорректные байты заменяются одним символом", "a\xff\xfeb", false, "a�b", false},
+		{"строгий режим отклоняет некорректную последовательность", "Mot\xffrhead", true, "", true},
+		{"пустая строка", "", true, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &SongService{cfg: ServiceConfig{StrictUTF8: tt.strict}}
+
+			got, err := svc.sanitizeString("text", tt.value)
+			if tt.wantErr {
+				var encodingErr *model.EncodingError
+				if !errors.As(err, &encodingErr) || encodingErr.Field != "text" {
+					t.Errorf("sanitizeString() error = %v, want EncodingError поля text", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("sanitizeString() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("sanitizeString() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeSong(t *testing.T) {
+	copyright := "\xff 2003 Taste Media"
+	tests := []struct {
+		name      string
+		song      model.Song
+		strict    bool
+		want      model.Song
+		wantField string
+	}{
+		{
+			name: "все текстовые поля нормализуются",
+			song: model.Song{Group: "Mo\u0308tley Cru\u0308e", Song: "Kickstart", ReleaseDate: "01.01.1985",
+				Text: "Cafe\u0301\n\nBar", Link: "https://example.com/cafe\u0301"},
+			want: model.Song{Group: "Mötley Crüe", Song: "Kickstart", ReleaseDate: "01.01.1985",
+				Text: "Café\n\nBar", Link: "https://example.com/café"},
+		},
+		{
+			name: "авторские права заменяются в нестрогом режиме",
+			song: model.Song{Group: "Muse", Song: "Hysteria", Copyright: &copyright},
+			want: model.Song{Group: "Muse", Song: "Hysteria", Copyright: stringPtr("� 2003 Taste Media")},
+		},
+		{
+			name:      "строгий режим указывает поле",
+			song:      model.Song{Group: "Muse", Song: "Hysteria", Text: "ok", Link: "\xff"},
+			strict:    true,
+			wantField: "link",
+		},
+		{
+			name:      "строгий режим проверяет авторские права",
+			song:      model.Song{Group: "Muse", Song: "Hysteria", Copyright: &copyright},
+			strict:    true,
+			wantField: model.FieldCopyright,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &SongService{cfg: ServiceConfig{StrictUTF8: tt.strict}}
+			song := tt.song
+
+			err := svc.sanitizeSong(&song)
+			if tt.wantField != "" {
+				var encodingErr *model.EncodingError
+				if !errors.As(err, &encodingErr) || encodingErr.Field != tt.wantField {
+					t.Errorf("sanitizeSong() error = %v, want EncodingError поля %s", err, tt.wantField)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("sanitizeSong() error = %v", err)
+			}
+			if song.Group != tt.want.Group || song.Song != tt.want.Song || song.ReleaseDate != tt.want.ReleaseDate ||
+				song.Text != tt.want.Text || song.Link != tt.want.Link {
+				t.Errorf("sanitizeSong() = %+v, want %+v", song, tt.want)
+			}
+			if (song.Copyright == nil) != (tt.want.Copyright == nil) ||
+				(song.Copyright != nil && *song.Copyright != *tt.want.Copyright) {
+				t.Errorf("sanitizeSong() Copyright = %v, want %v", song.Copyright, tt.want.Copyright)
+			}
+		})
+	}
+}
+
+func TestTruncateUTF8(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		maxBytes int
+		want     string
+	}{
+		{"короче ограничения", "Muse", 10, "Muse"},
+		{"ровно по ограничению", "Muse", 4, "Muse"},
+		{"ASCII обрезается по байтам", "Hysteria", 4, "Hyst"},
+		{"многобайтовый символ не разрывается", "Ёлка", 3, "Ё"},
+		{"граница символа", "Ёлка", 4, "Ёл"},
+		{"ограничение меньше первого символа", "Ёлка", 1, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncateUTF8(tt.value, tt.maxBytes); got != tt.want {
+				t.Errorf("truncateUTF8(%q, %d) = %q, want %q", tt.value, tt.maxBytes, got, tt.want)
+			}
+		})
+	}
+}
+
+func stringPtr(v string) *string { return &v }
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"song-library/internal/model"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxBookmarkNoteLength максимальная длина заметки к закладке в символах
+const maxBookmarkNoteLength = 500
+
+// AddVerseBookmark добавляет закладку куплета position песни songID для клиента clientID.
+// Куплет должен существовать в текущем тексте песни; повторное добавление заменяет заметку.
+func (s *SongService) AddVerseBookmark(ctx context.Context, clientID string, songID int64, position int, note string) error {
+	log := s.logger.WithFields(ctx, "song_id", songID, "verse_position", position)
+
+	log.Debug("Добавление закладки куплета")
+
+	if position <= 0 {
+		return model.NewValidationError("номер куплета должен быть положительным")
+	}
+	note, err := s.sanitizeString("note", strings.TrimSpace(note))
+	if err != nil {
+		return err
+	}
+	if utf8.RuneCountInString(note) > maxBookmarkNoteLength {
+		return model.NewValidationError(fmt.Sprintf("заметка не может быть длиннее %d символов", maxBookmarkNoteLength))
+	}
+
+	err = s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		song, err := s.repo.GetSongByIDForUpdate(ctx, songID)
+		if err != nil {
+			return err
+		}
+		if song == nil {
+			return model.NewNotFoundError(songID)
+		}
+		if _, ok := verseAt(song.Text, position); !ok {
+			return model.NewValidationError(fmt.Sprintf("в песне нет куплета %d", position))
+		}
+		return s.repo.AddVerseBookmark(ctx, clientID, songID, position, note)
+	})
+	if err != nil {
+		log.Error("Ошибка добавления закладки куплета", "error", err)
+		return fmt.Errorf("ошибка добавления закладки куплета: %w", err)
+	}
+
+	log.Info("Закладка куплета успешно добавлена")
+	return nil
+}
+
+// RemoveVerseBookmark удаляет закладку куплета. Если закладки нет, возвращается model.ErrBookmarkNotFound.
+func (s *SongService) RemoveVerseBookmark(ctx context.Context, clientID string, songID int64, position int) error {
+	log := s.logger.WithFields(ctx, "song_id", songID, "verse_position", position)
+
+	log.Debug("Удаление закладки куплета")
+
+	removed, err := s.repo.RemoveVerseBookmark(ctx, clientID, songID, position)
+	if err != nil {
+		log.Error("Ошибка удаления закладки куплета из репозитория", "error", err)
+		return fmt.Errorf("ошибка удаления закладки куплета: %w", err)
+	}
+	if !removed {
+		log.Info("Закладка куплета не найдена")
+		return model.ErrBookmarkNotFound
+	}
+
+	log.Info("Закладка куплета успешно удалена")
+	return nil
+}
+
+// ListVerseBookmarks получает закладки куплетов клиента с текущим текстом куплетов.
+// Закладки на куплеты, которых больше нет в тексте, помечаются Stale.
+func (s *SongService) ListVerseBookmarks(ctx context.Context, clientID string) ([]model.VerseBookmark, error) {
+	log := s.logger.WithContext(ctx)
+
+	log.Debug("Получение закладок куплетов")
+
+	bookmarks, err := s.repo.ListVerseBookmarks(ctx, clientID)
+	if err != nil {
+		log.Error("Ошибка получения закладок куплетов из репозитория", "error", err)
+		return nil, fmt.Errorf("ошибка получения закладок куплетов: %w", err)
+	}
+
+	stale := 0
+	for i := range bookmarks {
+		verse, ok := verseAt(bookmarks[i].Text, bookmarks[i].VersePosition)
+		bookmarks[i].Verse = verse
+		bookmarks[i].Stale = !ok
+		bookmarks[i].Text = ""
+		if !ok {
+			stale++
+		}
+	}
+
+	log.Info("Закладки куплетов успешно получены", "count", len(bookmarks), "stale", stale)
+	return bookmarks, nil
+}
+
+// verseAt возвращает куплет текста с номером position (с 1) и false, если такого куплета нет
+func verseAt(text string, position int) (string, bool) {
+	if text == "" || position <= 0 {
+		return "", false
+	}
+	verses := strings.Split(text, model.VerseDelimiter)
+	if position > len(verses) {
+		return "", false
+	}
+	return verses[position-1], true
+}
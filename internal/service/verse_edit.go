@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"song-library/internal/model"
+	"strings"
+)
+
+// UpdateVerse заменяет куплет песни с номером index (с 1) текстом text и возвращает количество куплетов
+// и признак изменения текста. Если куплета нет, возвращается model.ErrVerseNotFound. Прежний текст сохраняется в журнале событий.
+func (s *SongService) UpdateVerse(ctx context.Context, id int64, index int, text string) (int, bool, error) {
+	log := s.logger.WithFields(ctx, "id", id, "verse_index", index)
+
+	log.Debug("Изменение куплета песни")
+
+	text, err := s.sanitizeString("text", text)
+	if err != nil {
+		return 0, false, err
+	}
+	text = normalizeWhitespace(text)
+	if text == "" {
+		return 0, false, model.NewValidationError("текст куплета не может быть пустым")
+	}
+	if strings.Contains(text, model.VerseDelimiter) {
+		return 0, false, model.NewValidationError("текст куплета не может содержать пустых строк")
+	}
+
+	total, changed, err := s.editVerses(ctx, id, index, model.EventSongVerseUpdated, func(verses []string) []string {
+		verses[index-1] = text
+		return verses
+	})
+	if err != nil {
+		log.Error("Ошибка изменения куплета песни", "error", err)
+		return 0, false, fmt.Errorf("ошибка изменения куплета песни: %w", err)
+	}
+
+	log.Info("Куплет песни успешно изменен", "verse_count", total, "changed", changed)
+	return total, changed, nil
+}
+
+// DeleteVerse удаляет куплет песни с номером index (с 1), сдвигая следующие куплеты, и возвращает
+// оставшееся количество куплетов. Единственный куплет удалить нельзя: текст песни не может быть пустым.
+func (s *SongService) DeleteVerse(ctx context.Context, id int64, index int) (int, error) {
+	log := s.logger.WithFields(ctx, "id", id, "verse_index", index)
+
+	log.Debug("Удаление куплета песни")
+
+	total, _, err := s.editVerses(ctx, id, index, model.EventSongVerseDeleted, func(verses []string) []string {
+		return append(verses[:index-1], verses[index:]...)
+	})
+	if err != nil {
+		log.Error("Ошибка удаления куплета песни", "error", err)
+		return 0, fmt.Errorf("ошибка удаления куплета песни: %w", err)
+	}
+
+	log.Info("Куплет песни успешно удален", "verse_count", total)
+	return total, nil
+}
+
+// editVerses изменяет куплеты песни функцией edit в транзакции и возвращает количество куплетов после изменения
+// и признак изменения текста. Номер index проверяется до вызова edit; пустой после изменения текст отклоняется.
+func (s *SongService) editVerses(ctx context.Context, id int64, index int, eventType string, edit func(verses []string) []string) (int, bool, error) {
+	var (
+		total   int
+		changed bool
+	)
+	err := s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		existing, err := s.repo.GetSongByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return model.NewNotFoundError(id)
+		}
+
+		var verses []string
+		if existing.Text != "" {
+			verses = strings.Split(existing.Text, model.VerseDelimiter)
+		}
+		if index < 1 || index > len(verses) {
+			return fmt.Errorf("%w: %d из %d", model.ErrVerseNotFound, index, len(verses))
+		}
+
+		verses = edit(verses)
+		total = len(verses)
+		text := normalizeWhitespace(strings.Join(verses, model.VerseDelimiter))
+		if text == "" {
+			return model.NewValidationError("нельзя удалить единственный куплет: текст песни не может быть пустым")
+		}
+
+		updated := *existing
+		updated.Text = text
+		if err = s.validateSongLimits(&updated); err != nil {
+			return err
+		}
+		if text == existing.Text {
+			return nil
+		}
+
+		changed = true
+		updated.Provenance = withoutFields(existing.Provenance, []string{model.FieldText})
+		if err = s.repo.UpdateSong(ctx, &updated); err != nil {
+			return err
+		}
+
+		return s.logEvent(ctx, eventType, &id, map[string]interface{}{
+			"index": index, "previous_text": existing.Text, "text": text,
+		})
+	})
+	if err != nil {
+		return 0, false, err
+	}
+	s.invalidateSongs(id)
+	return total, changed, nil
+}
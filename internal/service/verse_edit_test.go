@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"song-library/internal/model"
+	"testing"
+)
+
+// verseEditText текст песни из трех куплетов для тестов изменения куплетов
+const verseEditText = "first verse\nline two\n\nsecond verse\n\nthird verse"
+
+// newVerseEditService создает сервис с единственной песней 1 с текстом text
+func newVerseEditService(text string) (*SongService, *memoryRepository, *recordingEventLogger) {
+	repo := newMemoryRepository()
+	repo.put(&model.Song{ID: 1, Group: "Muse", Song: "Hysteria", Text: text})
+	events := &recordingEventLogger{}
+	return NewSongService(repo, nil, nil, events, ServiceConfig{}, newTestLogger()), repo, events
+}
+
+func TestUpdateVerse(t *testing.T) {
+	tests := []struct {
+		name        string
+		index       int
+		text        string
+		wantTotal   int
+		wantChanged bool
+		wantText    string
+		wantErr     error
+	}{
+		{"куплет заменяется", 2, "new  second\nverse ", 3, true, "first verse\nline two\n\nnew second\nverse\n\nthird verse", nil},
+		{"тот же текст не меняет песню", 3, "third verse", 3, false, verseEditText, nil},
+		{"номер ноль", 0, "text", 0, false, verseEditText, model.ErrVerseNotFound},
+		{"номер больше количества куплетов", 4, "text", 0, false, verseEditText, model.ErrVerseNotFound},
+		{"пустой текст", 1, " \n ", 0, false, verseEditText, model.ErrValidation},
+		{"текст из нескольких куплетов", 1, "one\n\ntwo", 0, false, verseEditText, model.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, events := newVerseEditService(verseEditText)
+
+			total, changed, err := svc.UpdateVerse(context.Background(), 1, tt.index, tt.text)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("UpdateVerse() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("UpdateVerse() error = %v", err)
+			}
+			if total != tt.wantTotal || changed != tt.wantChanged {
+				t.Errorf("UpdateVerse() = %d, %v, want %d, %v", total, changed, tt.wantTotal, tt.wantChanged)
+			}
+			if got := repo.activeSong(1).Text; got != tt.wantText {
+				t.Errorf("текст песни = %q, want %q", got, tt.wantText)
+			}
+
+			var wantEvents []string
+			if tt.wantChanged {
+				wantEvents = []string{model.EventSongVerseUpdated}
+			}
+			if got := events.eventTypes(); !slices.Equal(got, wantEvents) {
+				t.Errorf("события = %v, want %v", got, wantEvents)
+			}
+		})
+	}
+}
+
+func TestDeleteVerse(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		index     int
+		wantTotal int
+		wantText  string
+		wantErr   error
+	}{
+		{"первый куплет", verseEditText, 1, 2, "second verse\n\nthird verse", nil},
+		{"средний куплет сдвигает следующие", verseEditText, 2, 2, "first verse\nline two\n\nthird verse", nil},
+		{"последний куплет", verseEditText, 3, 2, "first verse\nline two\n\nsecond verse", nil},
+		{"номер больше количества куплетов", verseEditText, 4, 0, verseEditText, model.ErrVerseNotFound},
+		{"единственный куплет", "only verse", 1, 0, "only verse", model.ErrValidation},
+		{"песня без текста", "", 1, 0, "", model.ErrVerseNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, events := newVerseEditService(tt.text)
+
+			total, err := svc.DeleteVerse(context.Background(), 1, tt.index)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("DeleteVerse() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("DeleteVerse() error = %v", err)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("DeleteVerse() = %d, want %d", total, tt.wantTotal)
+			}
+			if got := repo.activeSong(1).Text; got != tt.wantText {
+				t.Errorf("текст песни = %q, want %q", got, tt.wantText)
+			}
+
+			var wantEvents []string
+			if tt.wantErr == nil {
+				wantEvents = []string{model.EventSongVerseDeleted}
+			}
+			if got := events.eventTypes(); !slices.Equal(got, wantEvents) {
+				t.Errorf("события = %v, want %v", got, wantEvents)
+			}
+		})
+	}
+}
+
+func TestEditVerseNotFound(t *testing.T) {
+	svc, _, _ := newVerseEditService(verseEditText)
+
+	if _, _, err := svc.UpdateVerse(context.Background(), 42, 1, "text"); !errors.Is(err, model.ErrSongNotFound) {
+		t.Errorf("UpdateVerse() error = %v, want %v", err, model.ErrSongNotFound)
+	}
+	if _, err := svc.DeleteVerse(context.Background(), 42, 1); !errors.Is(err, model.ErrSongNotFound) {
+		t.Errorf("DeleteVerse() error = %v, want %v", err, model.ErrSongNotFound)
+	}
+}
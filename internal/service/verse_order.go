@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"song-library/internal/model"
+	"strings"
+)
+
+// ReorderVerses переставляет куплеты песни в порядке order — перестановке номеров куплетов, начиная с 1 —
+// и возвращает количество куплетов. Прежний текст сохраняется в журнале событий.
+func (s *SongService) ReorderVerses(ctx context.Context, id int64, order []int) (int, error) {
+	log := s.logger.WithFields(ctx, "id", id)
+
+	log.Debug("Перестановка куплетов песни", "order", order)
+
+	var verseCount int
+	err := s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		existing, err := s.repo.GetSongByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return model.NewNotFoundError(id)
+		}
+
+		var verses []string
+		if existing.Text != "" {
+			verses = strings.Split(existing.Text, model.VerseDelimiter)
+		}
+		if err = validateVerseOrder(order, len(verses)); err != nil {
+			return err
+		}
+		verseCount = len(verses)
+
+		reordered := make([]string, len(order))
+		for i, position := range order {
+			reordered[i] = strings.Trim(verses[position-1], "\n")
+		}
+		text := normalizeWhitespace(strings.Join(reordered, model.VerseDelimiter))
+		if text == existing.Text {
+			return nil
+		}
+
+		updated := *existing
+		updated.Text = text
+		updated.Provenance = withoutFields(existing.Provenance, []string{model.FieldText})
+		if err = s.repo.UpdateSong(ctx, &updated); err != nil {
+			return err
+		}
+
+		return s.logEvent(ctx, model.EventSongVersesReordered, &id, map[string]interface{}{
+			"order": order, "previous_text": existing.Text, "text": text,
+		})
+	})
+	if err != nil {
+		log.Error("Ошибка перестановки куплетов песни", "error", err)
+		return 0, fmt.Errorf("ошибка перестановки куплетов песни: %w", err)
+	}
+	s.invalidateSongs(id)
+
+	log.Info("Куплеты песни успешно переставлены", "verse_count", verseCount)
+	return verseCount, nil
+}
+
+// validateVerseOrder проверяет, что order — перестановка номеров куплетов от 1 до count
+func validateVerseOrder(order []int, count int) error {
+	if len(order) != count {
+		return model.NewVerseOrderError(fmt.Sprintf("order должен содержать %d номеров куплетов, передано %d", count, len(order)))
+	}
+
+	seen := make([]bool, count)
+	for _, position := range order {
+		if position < 1 || position > count {
+			return model.NewVerseOrderError(fmt.Sprintf("номер куплета %d вне диапазона от 1 до %d", position, count))
+		}
+		if seen[position-1] {
+			return model.NewVerseOrderError(fmt.Sprintf("номер куплета %d указан больше одного раза", position))
+		}
+		seen[position-1] = true
+	}
+	return nil
+}
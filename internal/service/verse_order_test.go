@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"song-library/internal/model"
+	"testing"
+)
+
+func TestValidateVerseOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		order   []int
+		count   int
+		wantErr string
+	}{
+		{"тождественная перестановка", []int{1, 2, 3}, 3, ""},
+		{"обратный порядок", []int{3, 2, 1}, 3, ""},
+		{"пустая песня", []int{}, 0, ""},
+		{"меньше номеров", []int{2, 1}, 3, "order должен содержать 3 номеров куплетов, передано 2"},
+		{"больше номеров", []int{1, 2, 3, 1}, 3, "order должен содержать 3 номеров куплетов, передано 4"},
+		{"номер ноль", []int{0, 1, 2}, 3, "номер куплета 0 вне диапазона от 1 до 3"},
+		{"номер больше количества", []int{1, 2, 4}, 3, "номер куплета 4 вне диапазона от 1 до 3"},
+		{"повтор номера", []int{1, 1, 2}, 3, "номер куплета 1 указан больше одного раза"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateVerseOrder(tt.order, tt.count)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateVerseOrder() error = %v, want nil", err)
+				}
+				return
+			}
+			var orderErr *model.VerseOrderError
+			if !errors.As(err, &orderErr) {
+				t.Fatalf("validateVerseOrder() error = %v, want *model.VerseOrderError", err)
+			}
+			if orderErr.Message != tt.wantErr {
+				t.Errorf("validateVerseOrder() error = %q, want %q", orderErr.Message, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReorderVerses(t *testing.T) {
+	const text = "first verse\nline two\n\nsecond verse\n\nthird verse"
+
+	tests := []struct {
+		name           string
+		order          []int
+		wantCount      int
+		wantText       string
+		wantProvenance bool
+		wantErr        bool
+	}{
+		{"куплеты переставляются", []int{3, 1, 2}, 3, "third verse\n\nfirst verse\nline two\n\nsecond verse", false, false},
+		{"прежний порядок не меняет песню", []int{1, 2, 3}, 3, text, true, false},
+		{"неверный порядок", []int{1, 1, 2}, 0, text, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			repo.put(&model.Song{ID: 1, Group: "Muse", Song: "Hysteria", Text: text, Provenance: model.Provenance{
+				model.FieldText:        {Provider: model.ProviderExternalAPI},
+				model.FieldReleaseDate: {Provider: model.ProviderExternalAPI},
+			}})
+			svc := NewSongService(repo, nil, nil, nopEventLogger{}, ServiceConfig{}, newTestLogger())
+
+			count, err := svc.ReorderVerses(context.Background(), 1, tt.order)
+			if tt.wantErr {
+				var orderErr *model.VerseOrderError
+				if !errors.As(err, &orderErr) {
+					t.Errorf("ReorderVerses() error = %v, want *model.VerseOrderError", err)
+				}
+			} else if err != nil {
+				t.Fatalf("ReorderVerses() error = %v", err)
+			}
+			if count != tt.wantCount {
+				t.Errorf("ReorderVerses() = %d, want %d", count, tt.wantCount)
+			}
+
+			song := repo.activeSong(1)
+			if song.Text != tt.wantText {
+				t.Errorf("текст песни = %q, want %q", song.Text, tt.wantText)
+			}
+			// Переставленный вручную текст больше не считается полученным из внешнего API
+			if _, ok := song.Provenance[model.FieldText]; ok != tt.wantProvenance {
+				t.Errorf("происхождение текста сохранено = %v, want %v", ok, tt.wantProvenance)
+			}
+			if _, ok := song.Provenance[model.FieldReleaseDate]; !ok {
+				t.Error("происхождение даты выпуска потеряно")
+			}
+		})
+	}
+}
+
+func TestReorderVersesNotFound(t *testing.T) {
+	svc := NewSongService(newMemoryRepository(), nil, nil, nopEventLogger{}, ServiceConfig{}, newTestLogger())
+
+	if _, err := svc.ReorderVerses(context.Background(), 42, []int{1}); !errors.Is(err, model.ErrSongNotFound) {
+		t.Errorf("ReorderVerses() error = %v, want %v", err, model.ErrSongNotFound)
+	}
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"song-library/internal/model"
+	"song-library/pkg/logger"
+	"sync"
+)
+
+var (
+	workerPoolQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "worker_pool_queue_depth",
+		Help: "Количество задач, ожидающих свободного обработчика",
+	})
+	workerPoolInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "worker_pool_in_flight",
+		Help: "Количество выполняющихся фоновых задач",
+	})
+	workerPoolRejected = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "worker_pool_rejected_total",
+		Help: "Количество задач, отклоненных из-за переполнения очереди",
+	})
+)
+
+// poolTask фоновая задача с контекстом, в котором она была поставлена
+type poolTask struct {
+	ctx context.Context
+	fn  func(ctx context.Context)
+}
+
+// WorkerPool ограниченный пул обработчиков фоновых задач.
+// Вызывающий выбирает поведение при заполненной очереди: Submit ожидает места, TrySubmit сразу отказывает.
+type WorkerPool struct {
+	tasks  chan poolTask
+	wg     sync.WaitGroup
+	mu     sync.RWMutex
+	closed bool
+	logger *logger.Logger
+}
+
+// NewWorkerPool запускает size обработчиков с очередью на queueSize задач
+func NewWorkerPool(size, queueSize int, logger *logger.Logger) *WorkerPool {
+	p := &WorkerPool{
+		tasks:  make(chan poolTask, queueSize),
+		logger: logger,
+	}
+
+	p.wg.Add(size)
+	for i := 0; i < size; i++ {
+		go p.work()
+	}
+	return p
+}
+
+// work выполняет задачи из очереди до ее закрытия
+func (p *WorkerPool) work() {
+	defer p.wg.Done()
+
+	for task := range p.tasks {
+		workerPoolQueueDepth.Dec()
+		workerPoolInFlight.Inc()
+		p.run(task)
+		workerPoolInFlight.Dec()
+	}
+}
+
+// run выполняет задачу; паника в задаче логируется и не останавливает обработчик
+func (p *WorkerPool) run(task poolTask) {
+	defer func() {
+		if r := recover(); r != nil {
+			p.logger.WithContext(task.ctx).Error("Паника в фоновой задаче", "panic", fmt.Sprint(r))
+		}
+	}()
+	task.fn(task.ctx)
+}
+
+// Submit ставит задачу в очередь, ожидая свободного места до отмены ctx
+func (p *WorkerPool) Submit(ctx context.Context, fn func(ctx context.Context)) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return fmt.Errorf("%w: пул фоновых задач остановлен", model.ErrServiceBusy)
+	}
+
+	select {
+	case p.tasks <- poolTask{ctx: ctx, fn: fn}:
+		workerPoolQueueDepth.Inc()
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// TrySubmit ставит задачу в очередь или сразу возвращает ErrServiceBusy, если очередь заполнена
+func (p *WorkerPool) TrySubmit(ctx context.Context, fn func(ctx context.Context)) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return fmt.Errorf("%w: пул фоновых задач остановлен", model.ErrServiceBusy)
+	}
+
+	select {
+	case p.tasks <- poolTask{ctx: ctx, fn: fn}:
+		workerPoolQueueDepth.Inc()
+		return nil
+	default:
+		workerPoolRejected.Inc()
+		return fmt.Errorf("%w: очередь фоновых задач заполнена", model.ErrServiceBusy)
+	}
+}
+
+// Shutdown прекращает прием задач и ожидает выполнения уже поставленных до отмены ctx
+func (p *WorkerPool) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.tasks)
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("фоновые задачи не завершились: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"song-library/internal/model"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// poolContextKey ключ значения контекста, которое задача должна получить от вызывающего
+type poolContextKey struct{}
+
+// blockingTask возвращает задачу, которая сообщает о старте в started и ожидает закрытия release
+func blockingTask(started chan<- struct{}, release <-chan struct{}) func(context.Context) {
+	return func(context.Context) {
+		started <- struct{}{}
+		<-release
+	}
+}
+
+func TestWorkerPoolLimitsConcurrency(t *testing.T) {
+	const (
+		workers = 3
+		tasks   = 12
+	)
+
+	pool := NewWorkerPool(workers, tasks, newTestLogger())
+	var inFlight, peak atomic.Int32
+	var done sync.WaitGroup
+	done.Add(tasks)
+	for range tasks {
+		err := pool.Submit(context.Background(), func(context.Context) {
+			defer done.Done()
+			current := inFlight.Add(1)
+			for {
+				old := peak.Load()
+				if current <= old || peak.CompareAndSwap(old, current) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			inFlight.Add(-1)
+		})
+		if err != nil {
+			t.Fatalf("Submit() error = %v", err)
+		}
+	}
+	done.Wait()
+
+	if got := peak.Load(); got > workers {
+		t.Errorf("одновременно выполнялось задач = %d, want не больше %d", got, workers)
+	}
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown() error = %v", err)
+	}
+}
+
+func TestWorkerPoolFullQueue(t *testing.T) {
+	tests := []struct {
+		name    string
+		submit  func(pool *WorkerPool) error
+		wantErr error
+	}{
+		{"TrySubmit сразу отказывает", func(pool *WorkerPool) error {
+			return pool.TrySubmit(context.Background(), func(context.Context) {})
+		}, model.ErrServiceBusy},
+		{"Submit ожидает до отмены контекста", func(pool *WorkerPool) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+			return pool.Submit(ctx, func(context.Context) {})
+		}, context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := NewWorkerPool(1, 1, newTestLogger())
+			started := make(chan struct{}, 1)
+			release := make(chan struct{})
+
+			// Единственный обработчик занят, единственное место в очереди тоже
+			if err := pool.Submit(context.Background(), blockingTask(started, release)); err != nil {
+				t.Fatalf("Submit() error = %v", err)
+			}
+			<-started
+			if err := pool.TrySubmit(context.Background(), func(context.Context) {}); err != nil {
+				t.Fatalf("TrySubmit() в свободную очередь error = %v", err)
+			}
+
+			if err := tt.submit(pool); !errors.Is(err, tt.wantErr) {
+				t.Errorf("постановка в заполненную очередь error = %v, want %v", err, tt.wantErr)
+			}
+
+			close(release)
+			if err := pool.Shutdown(context.Background()); err != nil {
+				t.Errorf("Shutdown() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestWorkerPoolShutdown(t *testing.T) {
+	pool := NewWorkerPool(2, 10, newTestLogger())
+	var completed atomic.Int32
+	for range 5 {
+		err := pool.Submit(context.Background(), func(context.Context) {
+			time.Sleep(5 * time.Millisecond)
+			completed.Add(1)
+		})
+		if err != nil {
+			t.Fatalf("Submit() error = %v", err)
+		}
+	}
+
+	// Shutdown дожидается уже поставленных задач
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if got := completed.Load(); got != 5 {
+		t.Errorf("выполнено задач до возврата Shutdown() = %d, want 5", got)
+	}
+
+	if err := pool.Submit(context.Background(), func(context.Context) {}); !errors.Is(err, model.ErrServiceBusy) {
+		t.Errorf("Submit() после Shutdown() error = %v, want %v", err, model.ErrServiceBusy)
+	}
+	if err := pool.TrySubmit(context.Background(), func(context.Context) {}); !errors.Is(err, model.ErrServiceBusy) {
+		t.Errorf("TrySubmit() после Shutdown() error = %v, want %v", err, model.ErrServiceBusy)
+	}
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Errorf("повторный Shutdown() error = %v", err)
+	}
+}
+
+func TestWorkerPoolShutdownTimeout(t *testing.T) {
+	pool := NewWorkerPool(1, 1, newTestLogger())
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	defer close(release)
+
+	if err := pool.Submit(context.Background(), blockingTask(started, release)); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := pool.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() с зависшей задачей error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestWorkerPoolRecoversFromPanic(t *testing.T) {
+	pool := NewWorkerPool(1, 2, newTestLogger())
+	got := make(chan any, 1)
+
+	if err := pool.Submit(context.Background(), func(context.Context) { panic("enrichment failed") }); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	// Задача получает контекст, в котором была поставлена, и выполняется тем же обработчиком после паники
+	ctx := context.WithValue(context.Background(), poolContextKey{}, "request-1")
+	if err := pool.Submit(ctx, func(ctx context.Context) { got <- ctx.Value(poolContextKey{}) }); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	select {
+	case value := <-got:
+		if value != "request-1" {
+			t.Errorf("значение контекста задачи = %v, want request-1", value)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("задача после паники не выполнена")
+	}
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown() error = %v", err)
+	}
+}
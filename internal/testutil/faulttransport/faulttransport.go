@@ -0,0 +1,176 @@
+// Package faulttransport предоставляет http.RoundTripper для тестов, отвечающий по заранее заданным сценариям.
+// Для каждого ключа запроса задается последовательность ответов, например «таймаут, 503, успех»;
+// каждый запрос с этим ключом получает следующий шаг сценария, последний шаг повторяется.
+package faulttransport
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Step один шаг сценария: ошибка транспорта, ответ с кодом состояния и телом, с необязательной задержкой
+type Step struct {
+	// Hang задерживает ответ до отмены контекста запроса; запрос завершается ошибкой контекста
+	Hang bool
+	// Wait задерживает ответ до закрытия канала или отмены контекста запроса
+	Wait <-chan struct{}
+	// Err ошибка, которую вернет RoundTrip
+	Err error
+	// Status код состояния ответа
+	Status int
+	// Body тело ответа
+	Body string
+}
+
+// Timeout возвращает шаг, который не отвечает до отмены контекста запроса, как зависший сервер
+func Timeout() Step {
+	return Step{Hang: true}
+}
+
+// Status возвращает шаг с ответом с кодом состояния code и пустым телом
+func Status(code int) Step {
+	return Step{Status: code}
+}
+
+// OK возвращает шаг с ответом 200 и телом body
+func OK(body string) Step {
+	return Step{Status: http.StatusOK, Body: body}
+}
+
+// Error возвращает шаг с ошибкой транспорта err, например обрывом соединения
+func Error(err error) Step {
+	return Step{Err: err}
+}
+
+// After возвращает шаг step, который выполняется только после закрытия канала release
+func After(release <-chan struct{}, step Step) Step {
+	step.Wait = release
+	return step
+}
+
+// KeyFunc возвращает ключ сценария для запроса
+type KeyFunc func(req *http.Request) string
+
+// MethodAndURI ключ сценария по умолчанию: метод и путь с параметрами запроса, например "GET /info?group=Muse&song=Uprising"
+func MethodAndURI(req *http.Request) string {
+	return req.Method + " " + req.URL.RequestURI()
+}
+
+// Transport http.RoundTripper, отвечающий по сценариям. Безопасен для одновременного использования
+type Transport struct {
+	key KeyFunc
+
+	mu       sync.Mutex
+	scripts  map[string][]Step
+	requests map[string][]*http.Request
+	entered  chan string
+}
+
+// New создает транспорт без сценариев с ключом MethodAndURI
+func New() *Transport {
+	return &Transport{
+		key:      MethodAndURI,
+		scripts:  make(map[string][]Step),
+		requests: make(map[string][]*http.Request),
+		entered:  make(chan string, 1024),
+	}
+}
+
+// WithKey задает функцию ключа сценария
+func (t *Transport) WithKey(key KeyFunc) *Transport {
+	t.key = key
+	return t
+}
+
+// Script задает последовательность шагов для ключа key, заменяя прежний сценарий
+func (t *Transport) Script(key string, steps ...Step) *Transport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.scripts[key] = steps
+	return t
+}
+
+// Calls возвращает количество запросов с ключом key
+func (t *Transport) Calls(key string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return len(t.requests[key])
+}
+
+// Requests возвращает запросы с ключом key в порядке поступления
+func (t *Transport) Requests(key string) []*http.Request {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]*http.Request(nil), t.requests[key]...)
+}
+
+// Entered возвращает канал, в который передается ключ каждого поступившего запроса до выполнения его шага
+func (t *Transport) Entered() <-chan string {
+	return t.entered
+}
+
+// RoundTrip выполняет следующий шаг сценария для ключа запроса.
+// Запрос без сценария завершается ошибкой, чтобы тест не обращался к сети незаметно
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := t.key(req)
+
+	t.mu.Lock()
+	steps, ok := t.scripts[key]
+	call := len(t.requests[key])
+	t.requests[key] = append(t.requests[key], req)
+	t.mu.Unlock()
+
+	select {
+	case t.entered <- key:
+	default:
+	}
+
+	if !ok || len(steps) == 0 {
+		return nil, fmt.Errorf("faulttransport: нет сценария для %q", key)
+	}
+	step := steps[min(call, len(steps)-1)]
+
+	if err := wait(req.Context(), step); err != nil {
+		return nil, err
+	}
+	if step.Err != nil {
+		return nil, step.Err
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", step.Status, http.StatusText(step.Status)),
+		StatusCode:    step.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(strings.NewReader(step.Body)),
+		ContentLength: int64(len(step.Body)),
+		Request:       req,
+	}, nil
+}
+
+// wait выполняет задержку шага; возвращает ошибку контекста, если запрос отменен раньше
+func wait(ctx context.Context, step Step) error {
+	switch {
+	case step.Hang:
+		<-ctx.Done()
+		return ctx.Err()
+	case step.Wait != nil:
+		select {
+		case <-step.Wait:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,118 @@
+// Package testutil содержит вспомогательные функции для тестов HTTP-обработчиков
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"song-library/internal/api"
+	"song-library/internal/api/handler"
+	"song-library/pkg/logger"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// NewTestRouter создает маршрутизатор API в тестовом режиме gin с маршрутами обработчика handler
+func NewTestRouter(handler *handler.SongHandler, logger *logger.Logger) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := api.NewRouter(handler, logger)
+	router.SetupRoutes()
+	return router.GetEngine()
+}
+
+// DoRequest выполняет запрос method к path и возвращает записанный ответ.
+// body передается как есть, если это string или []byte, nil означает запрос без тела,
+// остальные значения кодируются в JSON. Запрос с телом получает Content-Type application/json
+func DoRequest(t *testing.T, router http.Handler, method, path string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case string:
+		reader = strings.NewReader(b)
+	case []byte:
+		reader = bytes.NewReader(b)
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			t.Fatalf("ошибка кодирования тела запроса: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	if reader != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+// AssertStatus проверяет код состояния ответа
+func AssertStatus(t *testing.T, recorder *httptest.ResponseRecorder, expected int) {
+	t.Helper()
+
+	if recorder.Code != expected {
+		t.Errorf("status = %d, want %d; body = %s", recorder.Code, expected, recorder.Body.String())
+	}
+}
+
+// DecodeJSON декодирует тело ответа в значение типа T; при ошибке тест завершается
+func DecodeJSON[T any](t *testing.T, recorder *httptest.ResponseRecorder) T {
+	t.Helper()
+
+	var value T
+	if err := json.Unmarshal(recorder.Body.Bytes(), &value); err != nil {
+		t.Fatalf("ошибка декодирования ответа %s: %v", recorder.Body.String(), err)
+	}
+	return value
+}
+
+// AssertJSONField проверяет значение поля JSON-ответа по пути jsonPath.
+// Путь состоит из ключей объектов и индексов массивов через точку, например "errors.0.field".
+// expectedValue сравнивается после кодирования в JSON, поэтому 404 и float64(404) равны
+func AssertJSONField(t *testing.T, recorder *httptest.ResponseRecorder, jsonPath string, expectedValue any) {
+	t.Helper()
+
+	got := DecodeJSON[any](t, recorder)
+	for _, key := range strings.Split(jsonPath, ".") {
+		switch node := got.(type) {
+		case map[string]any:
+			value, ok := node[key]
+			if !ok {
+				t.Errorf("поле %s отсутствует в ответе %s", jsonPath, recorder.Body.String())
+				return
+			}
+			got = value
+		case []any:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(node) {
+				t.Errorf("элемент %s отсутствует в ответе %s", jsonPath, recorder.Body.String())
+				return
+			}
+			got = node[index]
+		default:
+			t.Errorf("поле %s отсутствует в ответе %s", jsonPath, recorder.Body.String())
+			return
+		}
+	}
+
+	data, err := json.Marshal(expectedValue)
+	if err != nil {
+		t.Fatalf("ошибка кодирования ожидаемого значения %s: %v", jsonPath, err)
+	}
+	var want any
+	if err = json.Unmarshal(data, &want); err != nil {
+		t.Fatalf("ошибка декодирования ожидаемого значения %s: %v", jsonPath, err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s = %v, want %v", jsonPath, got, want)
+	}
+}
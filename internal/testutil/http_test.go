@@ -0,0 +1,78 @@
+package testutil
+
+import (
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"testing"
+)
+
+// newEchoRouter создает маршрутизатор, возвращающий тело и Content-Type запроса в JSON
+func newEchoRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Any("/echo", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.JSON(http.StatusOK, gin.H{
+			"method":      c.Request.Method,
+			"body":        string(body),
+			"contentType": c.GetHeader("Content-Type"),
+		})
+	})
+	return router
+}
+
+func TestDoRequest(t *testing.T) {
+	tests := []struct {
+		name            string
+		body            any
+		wantBody        string
+		wantContentType string
+	}{
+		{"без тела", nil, "", ""},
+		{"строка", `{"group":"Muse"}`, `{"group":"Muse"}`, "application/json"},
+		{"байты", []byte(`{"group":"Muse"}`), `{"group":"Muse"}`, "application/json"},
+		{"значение кодируется в JSON", map[string]string{"group": "Muse"}, `{"group":"Muse"}`, "application/json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := DoRequest(t, newEchoRouter(), http.MethodPost, "/echo", tt.body)
+
+			AssertStatus(t, recorder, http.StatusOK)
+			AssertJSONField(t, recorder, "method", http.MethodPost)
+			AssertJSONField(t, recorder, "body", tt.wantBody)
+			AssertJSONField(t, recorder, "contentType", tt.wantContentType)
+		})
+	}
+}
+
+func TestAssertJSONField(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/songs", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"data": []gin.H{{"id": 1, "group": "Muse"}, {"id": 2, "group": "Queen"}},
+			"meta": gin.H{"total": 2, "next": nil},
+		})
+	})
+	recorder := DoRequest(t, router, http.MethodGet, "/songs", nil)
+
+	// Ожидаемые значения сравниваются после кодирования в JSON, поэтому int равен числу ответа
+	AssertJSONField(t, recorder, "data.1.group", "Queen")
+	AssertJSONField(t, recorder, "data.0.id", 1)
+	AssertJSONField(t, recorder, "meta.total", int64(2))
+	AssertJSONField(t, recorder, "meta.next", nil)
+	AssertJSONField(t, recorder, "data.0", map[string]any{"id": 1, "group": "Muse"})
+
+	type song struct {
+		ID    int64  `json:"id"`
+		Group string `json:"group"`
+	}
+	response := DecodeJSON[struct {
+		Data []song `json:"data"`
+	}](t, recorder)
+	if len(response.Data) != 2 || response.Data[1] != (song{ID: 2, Group: "Queen"}) {
+		t.Errorf("DecodeJSON() = %+v", response)
+	}
+}
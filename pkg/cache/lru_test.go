@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLRUCacheEviction(t *testing.T) {
+	tests := []struct {
+		name     string
+		ops      func(c *LRUCache[string, int])
+		wantKeys map[string]int
+		gone     []string
+	}{
+		{"вытесняется давно добавленная запись", func(c *LRUCache[string, int]) {
+			c.Put("a", 1)
+			c.Put("b", 2)
+			c.Put("c", 3)
+			c.Put("d", 4)
+		}, map[string]int{"b": 2, "c": 3, "d": 4}, []string{"a"}},
+		{"чтение продлевает жизнь записи", func(c *LRUCache[string, int]) {
+			c.Put("a", 1)
+			c.Put("b", 2)
+			c.Put("c", 3)
+			c.Get("a")
+			c.Put("d", 4)
+		}, map[string]int{"a": 1, "c": 3, "d": 4}, []string{"b"}},
+		{"перезапись обновляет значение и порядок", func(c *LRUCache[string, int]) {
+			c.Put("a", 1)
+			c.Put("b", 2)
+			c.Put("c", 3)
+			c.Put("a", 10)
+			c.Put("d", 4)
+		}, map[string]int{"a": 10, "c": 3, "d": 4}, []string{"b"}},
+		{"удаление освобождает место", func(c *LRUCache[string, int]) {
+			c.Put("a", 1)
+			c.Put("b", 2)
+			c.Put("c", 3)
+			c.Delete("b")
+			c.Delete("missing")
+			c.Put("d", 4)
+		}, map[string]int{"a": 1, "c": 3, "d": 4}, []string{"b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewLRUCache[string, int](3, time.Minute)
+			tt.ops(c)
+
+			for key, want := range tt.wantKeys {
+				if got, ok := c.Get(key); !ok || got != want {
+					t.Errorf("Get(%q) = %d, %v, want %d, true", key, got, ok, want)
+				}
+			}
+			for _, key := range tt.gone {
+				if got, ok := c.Get(key); ok {
+					t.Errorf("Get(%q) = %d, true, want запись отсутствует", key, got)
+				}
+			}
+		})
+	}
+}
+
+func TestLRUCacheExpiry(t *testing.T) {
+	c := NewLRUCache[string, int](3, 20*time.Millisecond)
+	c.Put("a", 1)
+
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("Get() свежей записи = false")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := c.Get("a"); ok {
+		t.Error("Get() устаревшей записи = true")
+	}
+	// Устаревшая запись удалена при обращении и не занимает место
+	if got := c.order.Len(); got != 0 {
+		t.Errorf("записей после обращения к устаревшей = %d, want 0", got)
+	}
+
+	c.Put("a", 2)
+	if got, ok := c.Get("a"); !ok || got != 2 {
+		t.Errorf("Get() после повторной записи = %d, %v, want 2, true", got, ok)
+	}
+}
+
+func TestLRUCacheConcurrentAccess(t *testing.T) {
+	const capacity = 16
+	c := NewLRUCache[string, int](capacity, time.Minute)
+
+	var wg sync.WaitGroup
+	for worker := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 1000 {
+				key := fmt.Sprint((worker + i) % 32)
+				c.Put(key, i)
+				c.Get(key)
+				if i%10 == 0 {
+					c.Delete(key)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := c.order.Len(); got > capacity || got != len(c.items) {
+		t.Errorf("записей в списке = %d, в индексе = %d, want одинаково и не больше %d", got, len(c.items), capacity)
+	}
+}
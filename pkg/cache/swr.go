@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"context"
+	"golang.org/x/sync/singleflight"
+	"sync"
+	"time"
+)
+
+// SWRCache потокобезопасный кэш в режиме stale-while-revalidate. Свежее значение отдается сразу;
+// устаревшее не более чем на staleWindow тоже отдается сразу, а обновляется в фоне.
+// Загрузка с ожиданием выполняется только при отсутствии значения или если оно устарело сильнее.
+// Одновременные загрузки одного ключа объединяются.
+type SWRCache[V any] struct {
+	mu          sync.Mutex
+	ttl         time.Duration
+	staleWindow time.Duration
+	items       map[string]swrEntry[V]
+	refreshing  map[string]bool
+	loads       singleflight.Group
+	onError     func(key string, err error)
+}
+
+// swrEntry запись кэша с временем загрузки значения
+type swrEntry[V any] struct {
+	value    V
+	loadedAt time.Time
+}
+
+// NewSWRCache создает кэш со временем свежести ttl и окном отдачи устаревших значений staleWindow.
+// onError вызывается при ошибке фонового обновления, после которой продолжает отдаваться прежнее значение; может быть nil.
+func NewSWRCache[V any](ttl, staleWindow time.Duration, onError func(key string, err error)) *SWRCache[V] {
+	return &SWRCache[V]{
+		ttl:         ttl,
+		staleWindow: staleWindow,
+		items:       make(map[string]swrEntry[V]),
+		refreshing:  make(map[string]bool),
+		onError:     onError,
+	}
+}
+
+// Get возвращает значение ключа и его возраст, при необходимости загружая его через load.
+// Фоновое обновление выполняется с контекстом ctx без отмены, чтобы завершение запроса его не прерывало.
+func (c *SWRCache[V]) Get(ctx context.Context, key string, load func(ctx context.Context) (V, error)) (V, time.Duration, error) {
+	c.mu.Lock()
+	entry, ok := c.items[key]
+	age := time.Since(entry.loadedAt)
+	stale := ok && age > c.ttl && age <= c.ttl+c.staleWindow
+	startRefresh := stale && !c.refreshing[key]
+	if startRefresh {
+		c.refreshing[key] = true
+	}
+	c.mu.Unlock()
+
+	if startRefresh {
+		go c.refresh(context.WithoutCancel(ctx), key, load)
+	}
+	if ok && (age <= c.ttl || stale) {
+		return entry.value, age, nil
+	}
+
+	value, err := c.load(ctx, key, load)
+	if err != nil {
+		var zero V
+		return zero, 0, err
+	}
+	return value, 0, nil
+}
+
+// refresh обновляет значение в фоне, по одному обновлению на ключ; ошибка передается onError, а прежнее значение остается в кэше
+func (c *SWRCache[V]) refresh(ctx context.Context, key string, load func(ctx context.Context) (V, error)) {
+	defer func() {
+		c.mu.Lock()
+		delete(c.refreshing, key)
+		c.mu.Unlock()
+	}()
+
+	if _, err := c.load(ctx, key, load); err != nil && c.onError != nil {
+		c.onError(key, err)
+	}
+}
+
+// load загружает значение, объединяя одновременные загрузки ключа, и сохраняет его в кэш
+func (c *SWRCache[V]) load(ctx context.Context, key string, load func(ctx context.Context) (V, error)) (V, error) {
+	result, err, _ := c.loads.Do(key, func() (interface{}, error) {
+		value, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.store(key, value)
+		return value, nil
+	})
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	return result.(V), nil
+}
+
+// store сохраняет значение и удаляет записи, устаревшие сильнее окна отдачи
+func (c *SWRCache[V]) store(key string, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for k, entry := range c.items {
+		if now.Sub(entry.loadedAt) > c.ttl+c.staleWindow {
+			delete(c.items, k)
+		}
+	}
+	c.items[key] = swrEntry[V]{value: value, loadedAt: now}
+}
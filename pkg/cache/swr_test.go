@@ -0,0 +1,226 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingLoader возвращает загрузчик, который считает вызовы и отдает номер вызова или ошибку err
+func countingLoader(calls *atomic.Int32, err *atomic.Value) func(context.Context) (int, error) {
+	return func(context.Context) (int, error) {
+		n := calls.Add(1)
+		if e, _ := err.Load().(error); e != nil {
+			return 0, e
+		}
+		return int(n), nil
+	}
+}
+
+// waitUntil ожидает выполнения условия не дольше секунды
+func waitUntil(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("условие не выполнено за отведенное время")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSWRCacheFreshAndMiss(t *testing.T) {
+	var calls atomic.Int32
+	var loadErr atomic.Value
+	c := NewSWRCache[int](time.Minute, time.Minute, nil)
+	load := countingLoader(&calls, &loadErr)
+
+	value, age, err := c.Get(context.Background(), "muse", load)
+	if err != nil || value != 1 || age != 0 {
+		t.Fatalf("Get() при отсутствии значения = %d, %v, %v, want 1, 0, nil", value, age, err)
+	}
+	value, _, err = c.Get(context.Background(), "muse", load)
+	if err != nil || value != 1 {
+		t.Errorf("Get() свежего значения = %d, %v, want 1, nil", value, err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("загрузок = %d, want 1", got)
+	}
+
+	// Ключи хранятся независимо
+	if value, _, _ = c.Get(context.Background(), "queen", load); value != 2 {
+		t.Errorf("Get() другого ключа = %d, want 2", value)
+	}
+}
+
+func TestSWRCacheStaleWhileRevalidate(t *testing.T) {
+	var calls atomic.Int32
+	var loadErr atomic.Value
+	c := NewSWRCache[int](10*time.Millisecond, time.Minute, nil)
+	load := countingLoader(&calls, &loadErr)
+
+	if _, _, err := c.Get(context.Background(), "muse", load); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	// Устаревшее значение отдается сразу вместе с возрастом, обновление идет в фоне
+	value, age, err := c.Get(context.Background(), "muse", load)
+	if err != nil || value != 1 || age < 10*time.Millisecond {
+		t.Fatalf("Get() устаревшего значения = %d, %v, %v, want 1, больше 10ms, nil", value, age, err)
+	}
+	waitUntil(t, func() bool {
+		value, _, _ := c.Get(context.Background(), "muse", load)
+		return value == 2
+	})
+	if got := calls.Load(); got != 2 {
+		t.Errorf("загрузок = %d, want 2", got)
+	}
+}
+
+func TestSWRCacheExpiredBeyondStaleWindow(t *testing.T) {
+	var calls atomic.Int32
+	var loadErr atomic.Value
+	c := NewSWRCache[int](5*time.Millisecond, 5*time.Millisecond, nil)
+	load := countingLoader(&calls, &loadErr)
+
+	if _, _, err := c.Get(context.Background(), "muse", load); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	// Значение старше окна отдачи не отдается: загрузка выполняется с ожиданием
+	value, age, err := c.Get(context.Background(), "muse", load)
+	if err != nil || value != 2 || age != 0 {
+		t.Errorf("Get() сильно устаревшего значения = %d, %v, %v, want 2, 0, nil", value, age, err)
+	}
+}
+
+func TestSWRCacheRefreshError(t *testing.T) {
+	var calls atomic.Int32
+	var loadErr atomic.Value
+	errUpstream := errors.New("upstream unavailable")
+	reported := make(chan error, 1)
+	c := NewSWRCache[int](10*time.Millisecond, time.Minute, func(key string, err error) {
+		if key == "muse" {
+			reported <- err
+		}
+	})
+	load := countingLoader(&calls, &loadErr)
+
+	if _, _, err := c.Get(context.Background(), "muse", load); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	loadErr.Store(errUpstream)
+
+	if value, _, err := c.Get(context.Background(), "muse", load); err != nil || value != 1 {
+		t.Fatalf("Get() устаревшего значения = %d, %v, want 1, nil", value, err)
+	}
+	select {
+	case err := <-reported:
+		if !errors.Is(err, errUpstream) {
+			t.Errorf("onError() error = %v, want %v", err, errUpstream)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("onError() не вызван")
+	}
+
+	// После неудачного обновления продолжает отдаваться прежнее значение
+	waitUntil(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return !c.refreshing["muse"]
+	})
+	if value, _, err := c.Get(context.Background(), "muse", load); err != nil || value != 1 {
+		t.Errorf("Get() после ошибки обновления = %d, %v, want 1, nil", value, err)
+	}
+}
+
+func TestSWRCacheLoadErrorNotCached(t *testing.T) {
+	var calls atomic.Int32
+	var loadErr atomic.Value
+	errUpstream := errors.New("upstream unavailable")
+	c := NewSWRCache[int](time.Minute, time.Minute, nil)
+	failing := countingLoader(&calls, &loadErr)
+	loadErr.Store(errUpstream)
+
+	if _, _, err := c.Get(context.Background(), "muse", failing); !errors.Is(err, errUpstream) {
+		t.Fatalf("Get() error = %v, want %v", err, errUpstream)
+	}
+	// Ошибка не запоминается: следующий вызов снова загружает значение
+	if value, _, err := c.Get(context.Background(), "muse", func(context.Context) (int, error) { return 2, nil }); err != nil || value != 2 {
+		t.Errorf("Get() после ошибки = %d, %v, want 2, nil", value, err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("загрузок с ошибкой = %d, want 1", got)
+	}
+}
+
+func TestSWRCacheCoalescesLoads(t *testing.T) {
+	const callers = 8
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	c := NewSWRCache[int](time.Minute, time.Minute, nil)
+	load := func(context.Context) (int, error) {
+		calls.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	var wg sync.WaitGroup
+	values := make([]int, callers)
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			values[i], _, _ = c.Get(context.Background(), "muse", load)
+		}()
+	}
+	waitUntil(t, func() bool { return calls.Load() == 1 })
+	// Остальные вызывающие успевают присоединиться к выполняющейся загрузке
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("загрузок = %d, want 1", got)
+	}
+	for i, value := range values {
+		if value != 42 {
+			t.Errorf("values[%d] = %d, want 42", i, value)
+		}
+	}
+}
+
+func TestSWRCacheRefreshOutlivesRequest(t *testing.T) {
+	c := NewSWRCache[int](10*time.Millisecond, time.Minute, nil)
+	if _, _, err := c.Get(context.Background(), "muse", func(context.Context) (int, error) { return 1, nil }); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	refreshed := make(chan error, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	c.Get(ctx, "muse", func(ctx context.Context) (int, error) {
+		time.Sleep(10 * time.Millisecond)
+		refreshed <- ctx.Err()
+		return 2, nil
+	})
+	// Запрос завершился раньше фонового обновления
+	cancel()
+
+	select {
+	case err := <-refreshed:
+		if err != nil {
+			t.Errorf("контекст фонового обновления отменен: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("фоновое обновление не выполнено")
+	}
+}
@@ -0,0 +1,190 @@
+// Package client — типизированный клиент HTTP API библиотеки песен для других сервисов на Go
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// apiPrefix префикс маршрутов API
+	apiPrefix = "/api/v1"
+	// apiKeyHeader заголовок ключа административного API
+	apiKeyHeader = "X-Admin-API-Key"
+	// defaultTimeout время ожидания одной попытки запроса по умолчанию
+	defaultTimeout = 10 * time.Second
+	// defaultMaxRetries количество повторных попыток по умолчанию
+	defaultMaxRetries = 3
+	// retryBaseDelay задержка перед первой повторной попыткой; каждая следующая вдвое дольше
+	retryBaseDelay = 100 * time.Millisecond
+	// maxRetryDelay максимальная задержка между попытками, в том числе по заголовку Retry-After
+	maxRetryDelay = 5 * time.Second
+)
+
+// Client клиент API библиотеки песен. Безопасен для одновременного использования
+type Client struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+	maxRetries int
+}
+
+// Option настраивает клиент при создании
+type Option func(*Client)
+
+// WithAPIKey задает ключ административного API, передаваемый в каждом запросе
+func WithAPIKey(apiKey string) Option {
+	return func(c *Client) {
+		c.apiKey = apiKey
+	}
+}
+
+// WithTimeout задает время ожидания одной попытки запроса
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.httpClient.Timeout = timeout
+	}
+}
+
+// WithHTTPClient заменяет HTTP-клиент, например для собственного транспорта; WithTimeout применяется к нему
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithMaxRetries задает количество повторных попыток при ответах 429 и 5xx (0 — без повторов)
+func WithMaxRetries(maxRetries int) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+	}
+}
+
+// New создает клиент API по адресу сервиса baseURL, например http://localhost:8080
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.ParseRequestURI(baseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("неверный адрес API %q: ожидается абсолютный URL со схемой http или https", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		maxRetries: defaultMaxRetries,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// do выполняет запрос к маршруту path API и разбирает тело успешного ответа в out, если out не nil.
+// Ответы 429 и 503 повторяются для любых запросов, остальные 5xx — только для GET, PUT и DELETE:
+// POST мог быть выполнен сервером до ошибки
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("ошибка сериализации запроса: %w", err)
+		}
+	}
+
+	target := c.baseURL + apiPrefix + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := c.attempt(ctx, method, target, payload, out)
+		if err == nil || attempt >= c.maxRetries || !retryable(method, err) {
+			return err
+		}
+
+		delay := min(retryBaseDelay<<attempt, maxRetryDelay)
+		if retryAfter > 0 {
+			delay = min(retryAfter, maxRetryDelay)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// attempt выполняет одну попытку запроса и возвращает задержку из заголовка Retry-After, если она указана
+func (c *Client) attempt(ctx context.Context, method, target string, payload []byte, out interface{}) (time.Duration, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка создания запроса: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set(apiKeyHeader, c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка выполнения запроса: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		var errorBody struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&errorBody) == nil {
+			apiErr.Message = errorBody.Error
+		}
+		return parseRetryAfter(resp.Header.Get("Retry-After")), apiErr
+	}
+
+	if out != nil {
+		if err = json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return 0, fmt.Errorf("ошибка декодирования ответа: %w", err)
+		}
+	}
+	return 0, nil
+}
+
+// retryable сообщает, нужно ли повторить запрос method после ошибки err
+func retryable(method string, err error) bool {
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		return false
+	}
+	switch {
+	case apiErr.StatusCode == http.StatusTooManyRequests, apiErr.StatusCode == http.StatusServiceUnavailable:
+		return true
+	case apiErr.StatusCode >= http.StatusInternalServerError:
+		return method != http.MethodPost
+	default:
+		return false
+	}
+}
+
+// parseRetryAfter читает задержку из заголовка Retry-After в секундах; 0, если заголовка нет или он в другом формате
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,392 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"song-library/internal/api"
+	"song-library/internal/api/handler"
+	"song-library/internal/model"
+	"song-library/internal/service"
+	"song-library/pkg/client"
+	"song-library/pkg/logger"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// memoryRepository хранит песни в памяти, чтобы SDK проверялся на настоящем маршрутизаторе и сервисе.
+// Методы, которые не переопределены, паникуют через встроенный nil-интерфейс SongRepository.
+type memoryRepository struct {
+	service.SongRepository
+
+	mu     sync.Mutex
+	nextID int64
+	songs  map[int64]*model.Song
+}
+
+// newMemoryRepository создает пустой репозиторий в памяти
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{songs: make(map[int64]*model.Song)}
+}
+
+// CreateSong сохраняет копию песни и, как PostgreSQL, отклоняет активный дубликат группы и названия
+func (r *memoryRepository) CreateSong(_ context.Context, song *model.Song) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.songs {
+		if existing.Group == song.Group && existing.Song == song.Song {
+			return 0, model.ErrSongAlreadyExists
+		}
+	}
+
+	r.nextID++
+	song.ID = r.nextID
+	song.PublicID = uuid.NewString()
+	song.CreatedAt = time.Now()
+	song.UpdatedAt = song.CreatedAt
+	stored := song.Clone()
+	stored.ComputeTextStats()
+	stored.ComputeContentHash()
+	r.songs[stored.ID] = stored
+	return stored.ID, nil
+}
+
+// GetSongByID возвращает копию песни или nil
+func (r *memoryRepository) GetSongByID(_ context.Context, id int64) (*model.Song, error) {
+	return r.song(id), nil
+}
+
+// GetSongByIDPrimary возвращает копию песни или nil
+func (r *memoryRepository) GetSongByIDPrimary(_ context.Context, id int64) (*model.Song, error) {
+	return r.song(id), nil
+}
+
+// GetSongByIDForUpdate возвращает копию песни или nil
+func (r *memoryRepository) GetSongByIDForUpdate(_ context.Context, id int64) (*model.Song, error) {
+	return r.song(id), nil
+}
+
+// GetSongs возвращает страницу песен от новых к старым; из фильтров учитывается только нормализованная группа
+func (r *memoryRepository) GetSongs(_ context.Context, filter model.SongFilter) ([]*model.Song, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	songs := []*model.Song{}
+	for id := r.nextID; id > 0; id-- {
+		song, ok := r.songs[id]
+		if ok && (filter.Group == "" || model.NormalizeName(song.Group) == filter.Group) {
+			songs = append(songs, song.Clone())
+		}
+	}
+	start := min(filter.Offset(), len(songs))
+	return songs[start:min(start+filter.PageSize, len(songs))], nil
+}
+
+// UpdateSong заменяет редактируемые поля песни
+func (r *memoryRepository) UpdateSong(_ context.Context, song *model.Song) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.songs[song.ID]
+	if !ok {
+		return model.NewNotFoundError(song.ID)
+	}
+	stored.Group = song.Group
+	stored.Song = song.Song
+	stored.ReleaseDate = song.ReleaseDate
+	stored.Text = song.Text
+	stored.Link = song.Link
+	stored.Duration = song.Duration
+	stored.BPM = song.BPM
+	stored.Provenance = song.Provenance
+	stored.UpdatedAt = time.Now()
+	stored.ComputeTextStats()
+	stored.ComputeContentHash()
+	return nil
+}
+
+// DeleteSong удаляет песню
+func (r *memoryRepository) DeleteSong(_ context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.songs[id]; !ok {
+		return model.NewNotFoundError(id)
+	}
+	delete(r.songs, id)
+	return nil
+}
+
+// GetSongVerses возвращает страницу или диапазон куплетов песни и общее количество куплетов
+func (r *memoryRepository) GetSongVerses(_ context.Context, id int64, pagination model.VersesPagination) ([]string, int, error) {
+	song := r.song(id)
+	if song == nil {
+		return nil, 0, model.NewNotFoundError(id)
+	}
+
+	verses := strings.Split(song.Text, model.VerseDelimiter)
+	start, end := pagination.Offset(), pagination.Offset()+pagination.PageSize
+	if pagination.HasRange() {
+		start, end = 0, len(verses)
+		if pagination.From != nil {
+			start = *pagination.From - 1
+		}
+		if pagination.To != nil {
+			end = *pagination.To
+		}
+	}
+	start, end = min(start, len(verses)), min(end, len(verses))
+	selected := slices.Clone(verses[start:max(start, end)])
+	if pagination.Descending {
+		slices.Reverse(selected)
+	}
+	return selected, len(verses), nil
+}
+
+// RecordAccess не ведет журнал обращений
+func (r *memoryRepository) RecordAccess(context.Context, int64, string) {}
+
+// WithinTransaction выполняет fn без транзакции
+func (r *memoryRepository) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// song возвращает копию песни или nil
+func (r *memoryRepository) song(id int64) *model.Song {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	song, ok := r.songs[id]
+	if !ok {
+		return nil
+	}
+	return song.Clone()
+}
+
+// nopEventLogger отбрасывает события журнала изменений
+type nopEventLogger struct{}
+
+// LogEvent ничего не делает
+func (nopEventLogger) LogEvent(context.Context, *model.SongEvent) error { return nil }
+
+// newTestServer запускает настоящий маршрутизатор API поверх репозитория в памяти
+// и внешнего API, который отдает детали любой песни
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"releaseDate":"16.07.2006","text":"Ooh baby\n\nYou set my soul alight\n\nGlaciers melting","link":"https://example.com"}`)
+	}))
+	t.Cleanup(upstream.Close)
+
+	log := &logger.Logger{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	apiClient, err := service.NewExternalAPIClient(service.ExternalAPIConfig{BaseURL: upstream.URL}, log)
+	if err != nil {
+		t.Fatalf("NewExternalAPIClient() error = %v", err)
+	}
+	songService := service.NewSongService(newMemoryRepository(), apiClient, nil, nopEventLogger{}, service.ServiceConfig{
+		ExternalAPIBudget:     5 * time.Second,
+		DefaultSongsPageSize:  10,
+		MaxSongsPageSize:      100,
+		DefaultVersesPageSize: 10,
+		MaxVersesPageSize:     100,
+	}, log)
+
+	gin.SetMode(gin.TestMode)
+	router := api.NewRouter(handler.NewSongHandler(songService, log), log)
+	router.SetupRoutes()
+
+	server := httptest.NewServer(router.GetEngine())
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newTestClient создает клиент SDK для server
+func newTestClient(t *testing.T, server *httptest.Server, opts ...client.Option) *client.Client {
+	t.Helper()
+
+	c, err := client.New(server.URL, opts...)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return c
+}
+
+func TestClientAgainstRouter(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t, newTestServer(t))
+
+	id, err := c.CreateSong(ctx, "Muse", "Starlight")
+	if err != nil {
+		t.Fatalf("CreateSong() error = %v", err)
+	}
+	if id == 0 {
+		t.Fatal("CreateSong() = 0, want идентификатор новой песни")
+	}
+	if _, err = c.CreateSong(ctx, "Queen", "Bicycle Race"); err != nil {
+		t.Fatalf("CreateSong() error = %v", err)
+	}
+
+	songs, err := c.GetSongs(ctx, client.SongFilter{Group: "Muse"})
+	if err != nil {
+		t.Fatalf("GetSongs() error = %v", err)
+	}
+	if len(songs) != 1 || songs[0].ID != id || songs[0].Song != "Starlight" {
+		t.Errorf("GetSongs(Muse) = %+v, want песню %d", songs, id)
+	}
+
+	song, err := c.GetSongByID(ctx, id)
+	if err != nil {
+		t.Fatalf("GetSongByID() error = %v", err)
+	}
+	if song.Group != "Muse" || song.ReleaseDate != "16.07.2006" || song.VerseCount != 3 || song.PublicID == "" {
+		t.Errorf("GetSongByID() = %+v", song)
+	}
+
+	verses, err := c.GetVerses(ctx, id, client.VersesOptions{Page: 1, PageSize: 2, Order: "desc"})
+	if err != nil {
+		t.Fatalf("GetVerses() error = %v", err)
+	}
+	if want := []string{"You set my soul alight", "Ooh baby"}; !slices.Equal(verses.Verses, want) || verses.TotalVerses != 3 {
+		t.Errorf("GetVerses() = %+v, want %q из 3", verses, want)
+	}
+
+	duration := 240
+	result, err := c.UpdateSong(ctx, id, client.SongUpdate{
+		Group: "Muse", Song: "Starlight", ReleaseDate: "16.07.2006", Text: "Far away", Link: "https://example.com", Duration: &duration,
+	})
+	if err != nil {
+		t.Fatalf("UpdateSong() error = %v", err)
+	}
+	if !result.Changed || result.Song == nil || result.Song.Text != "Far away" || result.Song.Duration == nil || *result.Song.Duration != duration {
+		t.Errorf("UpdateSong() = %+v", result)
+	}
+
+	if err = c.DeleteSong(ctx, id); err != nil {
+		t.Fatalf("DeleteSong() error = %v", err)
+	}
+	if _, err = c.GetSongByID(ctx, id); !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("GetSongByID() после удаления error = %v, want %v", err, client.ErrNotFound)
+	}
+}
+
+func TestClientErrorMapping(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t, newTestServer(t))
+
+	if _, err := c.GetSongByID(ctx, 42); !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("GetSongByID(42) error = %v, want %v", err, client.ErrNotFound)
+	}
+	if err := c.DeleteSong(ctx, 42); !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("DeleteSong(42) error = %v, want %v", err, client.ErrNotFound)
+	}
+
+	_, err := c.CreateSong(ctx, "Muse", "")
+	if !errors.Is(err, client.ErrInvalidRequest) {
+		t.Fatalf("CreateSong() без названия error = %v, want %v", err, client.ErrInvalidRequest)
+	}
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) || apiErr.Message == "" {
+		t.Errorf("CreateSong() error = %#v, want *APIError с сообщением сервера", err)
+	}
+
+	if _, err = c.CreateSong(ctx, "Muse", "Starlight"); err != nil {
+		t.Fatalf("CreateSong() error = %v", err)
+	}
+	if _, err = c.CreateSong(ctx, "Muse", "Starlight"); !errors.Is(err, client.ErrConflict) {
+		t.Errorf("повторный CreateSong() error = %v, want %v", err, client.ErrConflict)
+	}
+}
+
+func TestClientRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		responses    []int
+		retryAfter   string
+		wantAttempts int32
+		wantErr      error
+		minElapsed   time.Duration
+	}{
+		{"429 с Retry-After повторяется", http.MethodGet, []int{http.StatusTooManyRequests, http.StatusOK}, "1", 2, nil, time.Second},
+		{"503 с Retry-After повторяется для POST", http.MethodPost, []int{http.StatusServiceUnavailable, http.StatusCreated}, "1", 2, nil, time.Second},
+		{"500 повторяется для GET", http.MethodGet, []int{http.StatusInternalServerError, http.StatusOK}, "", 2, nil, 0},
+		{"500 не повторяется для POST", http.MethodPost, []int{http.StatusInternalServerError, http.StatusCreated}, "", 1, client.ErrServer, 0},
+		{"404 не повторяется", http.MethodGet, []int{http.StatusNotFound, http.StatusOK}, "", 1, client.ErrNotFound, 0},
+		{"попытки исчерпаны", http.MethodGet, []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway}, "", 3, client.ErrUnavailable, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := tt.responses[min(int(attempts.Add(1)), len(tt.responses))-1]
+				if status >= http.StatusBadRequest && tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(status)
+				if status >= http.StatusBadRequest {
+					_, _ = io.WriteString(w, `{"error":"failure"}`)
+					return
+				}
+				_, _ = io.WriteString(w, `{"id":7,"group":"Muse","song":"Starlight"}`)
+			}))
+			defer server.Close()
+			c := newTestClient(t, server, client.WithMaxRetries(2))
+
+			start := time.Now()
+			var err error
+			if tt.method == http.MethodPost {
+				_, err = c.CreateSong(context.Background(), "Muse", "Starlight")
+			} else {
+				_, err = c.GetSongByID(context.Background(), 7)
+			}
+			elapsed := time.Since(start)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+			if got := attempts.Load(); got != tt.wantAttempts {
+				t.Errorf("попыток = %d, want %d", got, tt.wantAttempts)
+			}
+			if elapsed < tt.minElapsed {
+				t.Errorf("повтор через %v, want не раньше Retry-After %v", elapsed, tt.minElapsed)
+			}
+		})
+	}
+}
+
+func TestClientDoesNotRetryPostNetworkError(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		// Соединение обрывается без ответа: сервер мог успеть создать песню
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}))
+	defer server.Close()
+	c := newTestClient(t, server, client.WithMaxRetries(3))
+
+	_, err := c.CreateSong(context.Background(), "Muse", "Starlight")
+	var apiErr *client.APIError
+	if err == nil || errors.As(err, &apiErr) {
+		t.Errorf("CreateSong() error = %v, want сетевую ошибку", err)
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("попыток = %d, want 1", got)
+	}
+}
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	// ErrInvalidRequest возвращается на ответы 400, 413 и 422: запрос не прошел проверку сервером
+	ErrInvalidRequest = errors.New("неверный запрос")
+	// ErrUnauthorized возвращается на ответы 401 и 403
+	ErrUnauthorized = errors.New("доступ запрещен")
+	// ErrNotFound возвращается на ответ 404
+	ErrNotFound = errors.New("не найдено")
+	// ErrConflict возвращается на ответ 409, например если песня уже существует
+	ErrConflict = errors.New("конфликт")
+	// ErrRateLimited возвращается на ответ 429, если повторные попытки не помогли
+	ErrRateLimited = errors.New("слишком много запросов")
+	// ErrUnavailable возвращается на ответы 502, 503 и 504, если повторные попытки не помогли
+	ErrUnavailable = errors.New("сервис недоступен")
+	// ErrServer возвращается на остальные ответы с ошибкой
+	ErrServer = errors.New("ошибка сервера")
+)
+
+// APIError ответ API с кодом ошибки. errors.Is сопоставляет его с одной из ошибок Err* по коду состояния
+type APIError struct {
+	StatusCode int
+	// Message сообщение из поля error ответа; пустое, если тело ответа не удалось разобрать
+	Message string
+}
+
+// Error возвращает код состояния и сообщение сервера
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("API вернул код состояния %d", e.StatusCode)
+	}
+	return fmt.Sprintf("API вернул код состояния %d: %s", e.StatusCode, e.Message)
+}
+
+// Unwrap возвращает ошибку Err*, соответствующую коду состояния
+func (e *APIError) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity:
+		return ErrInvalidRequest
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return ErrConflict
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return ErrUnavailable
+	default:
+		return ErrServer
+	}
+}
@@ -0,0 +1,194 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Song песня в ответах API
+type Song struct {
+	ID              int64     `json:"id"`
+	PublicID        string    `json:"publicId"`
+	Group           string    `json:"group"`
+	Song            string    `json:"song"`
+	ReleaseDate     string    `json:"releaseDate"`
+	Text            string    `json:"text"`
+	Link            string    `json:"link"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+	VerseCount      int       `json:"verseCount"`
+	TextLength      int       `json:"textLength"`
+	Duration        *int      `json:"duration"`
+	BPM             *int16    `json:"bpm"`
+	Copyright       *string   `json:"copyright,omitempty"`
+	ContentHash     string    `json:"contentHash"`
+	FeaturedArtists []string  `json:"featuredArtists"`
+}
+
+// SongUpdate новые данные песни для UpdateSong
+type SongUpdate struct {
+	Group       string `json:"group"`
+	Song        string `json:"song"`
+	ReleaseDate string `json:"releaseDate"`
+	Text        string `json:"text"`
+	Link        string `json:"link"`
+	Duration    *int   `json:"duration,omitempty"`
+	BPM         *int16 `json:"bpm,omitempty"`
+}
+
+// SongFilter фильтры и пагинация списка песен; пустые поля не передаются
+type SongFilter struct {
+	Group          string
+	Song           string
+	Text           string
+	Query          string
+	FeaturedArtist string
+	Groups         []string
+	SongNames      []string
+	DurationMin    *int
+	DurationMax    *int
+	BPMMin         *int
+	BPMMax         *int
+	// OmitText исключает текст песен из ответа
+	OmitText bool
+	Page     int
+	PageSize int
+}
+
+// values возвращает параметры запроса списка песен
+func (f SongFilter) values() url.Values {
+	q := url.Values{}
+	setString := func(key, value string) {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+	setInt := func(key string, value *int) {
+		if value != nil {
+			q.Set(key, strconv.Itoa(*value))
+		}
+	}
+
+	setString("group", f.Group)
+	setString("song", f.Song)
+	setString("text", f.Text)
+	setString("q", f.Query)
+	setString("featured_artist", f.FeaturedArtist)
+	setString("groups", strings.Join(f.Groups, ","))
+	setString("songs", strings.Join(f.SongNames, ","))
+	setInt("duration_min", f.DurationMin)
+	setInt("duration_max", f.DurationMax)
+	setInt("bpm_min", f.BPMMin)
+	setInt("bpm_max", f.BPMMax)
+	if f.OmitText {
+		q.Set("includeText", "false")
+	}
+	if f.Page > 0 {
+		q.Set("page", strconv.Itoa(f.Page))
+	}
+	if f.PageSize > 0 {
+		q.Set("page_size", strconv.Itoa(f.PageSize))
+	}
+	return q
+}
+
+// UpdateResult результат обновления песни
+type UpdateResult struct {
+	// Changed false, если данные песни совпали с текущими и запись не изменялась
+	Changed bool  `json:"changed"`
+	Song    *Song `json:"song"`
+}
+
+// VersesOptions пагинация и порядок куплетов; пустые поля не передаются
+type VersesOptions struct {
+	Page     int
+	PageSize int
+	// From и To ограничивают диапазон номеров куплетов (с 1)
+	From int
+	To   int
+	// Order порядок куплетов: asc или desc
+	Order string
+}
+
+// Verses страница куплетов песни
+type Verses struct {
+	Verses []string `json:"verses"`
+	// TotalVerses общее количество куплетов песни, независимо от страницы
+	TotalVerses int `json:"total_verses"`
+}
+
+// CreateSong добавляет песню и возвращает ее идентификатор. Если песня уже есть, возвращается ErrConflict
+func (c *Client) CreateSong(ctx context.Context, group, song string) (int64, error) {
+	body := struct {
+		Group string `json:"group"`
+		Song  string `json:"song"`
+	}{Group: group, Song: song}
+
+	var resp struct {
+		ID int64 `json:"id"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/songs", nil, body, &resp); err != nil {
+		return 0, err
+	}
+	return resp.ID, nil
+}
+
+// GetSongs возвращает страницу песен, подходящих под фильтр
+func (c *Client) GetSongs(ctx context.Context, filter SongFilter) ([]Song, error) {
+	var songs []Song
+	if err := c.do(ctx, http.MethodGet, "/songs", filter.values(), nil, &songs); err != nil {
+		return nil, err
+	}
+	return songs, nil
+}
+
+// GetSongByID возвращает песню по идентификатору. Если песни нет, возвращается ErrNotFound
+func (c *Client) GetSongByID(ctx context.Context, id int64) (*Song, error) {
+	var song Song
+	if err := c.do(ctx, http.MethodGet, songPath(id), nil, nil, &song); err != nil {
+		return nil, err
+	}
+	return &song, nil
+}
+
+// UpdateSong заменяет данные песни
+func (c *Client) UpdateSong(ctx context.Context, id int64, update SongUpdate) (*UpdateResult, error) {
+	var result UpdateResult
+	if err := c.do(ctx, http.MethodPut, songPath(id), nil, update, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteSong удаляет песню. Если песни нет, возвращается ErrNotFound
+func (c *Client) DeleteSong(ctx context.Context, id int64) error {
+	return c.do(ctx, http.MethodDelete, songPath(id), nil, nil, nil)
+}
+
+// GetVerses возвращает страницу куплетов песни
+func (c *Client) GetVerses(ctx context.Context, id int64, opts VersesOptions) (*Verses, error) {
+	q := url.Values{}
+	for key, value := range map[string]int{"page": opts.Page, "page_size": opts.PageSize, "from": opts.From, "to": opts.To} {
+		if value > 0 {
+			q.Set(key, strconv.Itoa(value))
+		}
+	}
+	if opts.Order != "" {
+		q.Set("order", opts.Order)
+	}
+
+	var verses Verses
+	if err := c.do(ctx, http.MethodGet, songPath(id)+"/verses", q, nil, &verses); err != nil {
+		return nil, err
+	}
+	return &verses, nil
+}
+
+// songPath возвращает маршрут песни id
+func songPath(id int64) string {
+	return "/songs/" + strconv.FormatInt(id, 10)
+}
@@ -0,0 +1,47 @@
+package events
+
+import (
+	"context"
+	"sync"
+)
+
+// Handler обработчик события
+type Handler func(ctx context.Context, event Event)
+
+// Event событие, передаваемое через шину
+type Event struct {
+	Topic   string
+	Payload any
+}
+
+// Bus простая внутрипроцессная шина событий.
+// Обработчики вызываются синхронно в порядке подписки.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+// NewBus создает новую шину событий
+func NewBus() *Bus {
+	return &Bus{handlers: make(map[string][]Handler)}
+}
+
+// Subscribe подписывает обработчик на события с указанной темой
+func (b *Bus) Subscribe(topic string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers[topic] = append(b.handlers[topic], handler)
+}
+
+// Publish публикует событие всем подписчикам темы
+func (b *Bus) Publish(ctx context.Context, topic string, payload any) {
+	b.mu.RLock()
+	handlers := append([]Handler(nil), b.handlers[topic]...)
+	b.mu.RUnlock()
+
+	event := Event{Topic: topic, Payload: payload}
+	for _, handler := range handlers {
+		handler(ctx, event)
+	}
+}
@@ -11,8 +11,9 @@ type Logger struct {
 	*slog.Logger
 }
 
-// NewLogger создает и настраивает новый экземпляр логгера
-func NewLogger(level string) *Logger {
+// NewLogger создает и настраивает новый экземпляр логгера.
+// format "text" включает текстовый вывод, иначе используется JSON.
+func NewLogger(level, format string) *Logger {
 	var logLevel slog.Level
 	switch level {
 	case "debug":
@@ -27,7 +28,11 @@ func NewLogger(level string) *Logger {
 		logLevel = slog.LevelInfo
 	}
 
-	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: logLevel})
+	options := &slog.HandlerOptions{Level: logLevel}
+	var handler slog.Handler = slog.NewJSONHandler(os.Stdout, options)
+	if format == "text" {
+		handler = slog.NewTextHandler(os.Stdout, options)
+	}
 	logger := slog.New(handler)
 	return &Logger{logger}
 }
@@ -36,3 +41,8 @@ func NewLogger(level string) *Logger {
 func (l *Logger) WithContext(ctx context.Context) *slog.Logger {
 	return l.Logger.With("requestID", ctx.Value("requestID"))
 }
+
+// WithFields добавляет к логгеру контекст запроса и дополнительные атрибуты в формате ключ-значение
+func (l *Logger) WithFields(ctx context.Context, fields ...any) *slog.Logger {
+	return l.WithContext(ctx).With(fields...)
+}
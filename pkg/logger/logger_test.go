@@ -0,0 +1,28 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestWithFields(t *testing.T) {
+	var buf bytes.Buffer
+	l := &Logger{slog.New(slog.NewJSONHandler(&buf, nil))}
+	ctx := context.WithValue(context.Background(), "requestID", "req-42")
+
+	l.WithFields(ctx, "song_id", 1, "group", "Muse").Info("Песня получена")
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("ошибка разбора записи %q: %v", buf.String(), err)
+	}
+	want := map[string]any{"requestID": "req-42", "song_id": float64(1), "group": "Muse", "msg": "Песня получена"}
+	for key, value := range want {
+		if record[key] != value {
+			t.Errorf("record[%q] = %v, want %v", key, record[key], value)
+		}
+	}
+}
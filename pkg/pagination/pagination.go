@@ -0,0 +1,50 @@
+package pagination
+
+// Page номер страницы (с 1), размер страницы и общее количество записей выборки.
+// Нулевые Page и PageSize означают значения по умолчанию, которые подставляет NewPage.
+type Page struct {
+	Page     int
+	PageSize int
+	Total    int
+}
+
+// NewPage возвращает страницу с допустимыми значениями: неположительный номер страницы заменяется на 1,
+// неположительный размер — на defaultSize, размер больше maxSize — на maxSize (maxSize 0 — без ограничения)
+func NewPage(page, pageSize, defaultSize, maxSize int) Page {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = defaultSize
+	}
+	if maxSize > 0 && pageSize > maxSize {
+		pageSize = maxSize
+	}
+	return Page{Page: page, PageSize: pageSize}
+}
+
+// Offset возвращает количество записей, пропускаемых до начала страницы
+func (p Page) Offset() int {
+	if p.Page <= 1 || p.PageSize <= 0 {
+		return 0
+	}
+	return (p.Page - 1) * p.PageSize
+}
+
+// TotalPages возвращает количество страниц для Total записей; при неположительном размере страницы — 0
+func (p Page) TotalPages() int {
+	if p.PageSize <= 0 || p.Total <= 0 {
+		return 0
+	}
+	return (p.Total + p.PageSize - 1) / p.PageSize
+}
+
+// HasNext сообщает, есть ли страница после текущей
+func (p Page) HasNext() bool {
+	return max(p.Page, 1) < p.TotalPages()
+}
+
+// HasPrev сообщает, есть ли страница перед текущей
+func (p Page) HasPrev() bool {
+	return p.Page > 1
+}
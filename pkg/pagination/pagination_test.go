@@ -0,0 +1,87 @@
+package pagination
+
+import "testing"
+
+func TestNewPage(t *testing.T) {
+	tests := []struct {
+		name        string
+		page        int
+		pageSize    int
+		defaultSize int
+		maxSize     int
+		want        Page
+	}{
+		{"значения в пределах", 3, 20, 10, 100, Page{Page: 3, PageSize: 20}},
+		{"нулевой номер страницы", 0, 20, 10, 100, Page{Page: 1, PageSize: 20}},
+		{"отрицательный номер страницы", -2, 20, 10, 100, Page{Page: 1, PageSize: 20}},
+		{"нулевой размер", 1, 0, 10, 100, Page{Page: 1, PageSize: 10}},
+		{"отрицательный размер", 1, -5, 10, 100, Page{Page: 1, PageSize: 10}},
+		{"размер больше максимального", 1, 500, 10, 100, Page{Page: 1, PageSize: 100}},
+		{"размер равен максимальному", 1, 100, 10, 100, Page{Page: 1, PageSize: 100}},
+		{"размер по умолчанию больше максимального", 1, 0, 50, 20, Page{Page: 1, PageSize: 20}},
+		{"без максимального размера", 1, 500, 10, 0, Page{Page: 1, PageSize: 500}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewPage(tt.page, tt.pageSize, tt.defaultSize, tt.maxSize); got != tt.want {
+				t.Errorf("NewPage(%d, %d, %d, %d) = %+v, want %+v", tt.page, tt.pageSize, tt.defaultSize, tt.maxSize, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageOffset(t *testing.T) {
+	tests := []struct {
+		name string
+		page Page
+		want int
+	}{
+		{"первая страница", Page{Page: 1, PageSize: 10}, 0},
+		{"третья страница", Page{Page: 3, PageSize: 10}, 20},
+		{"нулевой номер страницы", Page{Page: 0, PageSize: 10}, 0},
+		{"нулевой размер", Page{Page: 3, PageSize: 0}, 0},
+		{"нулевая страница", Page{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.page.Offset(); got != tt.want {
+				t.Errorf("%+v.Offset() = %d, want %d", tt.page, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageTotalPages(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     Page
+		want     int
+		wantNext bool
+		wantPrev bool
+	}{
+		{"нет записей", Page{Page: 1, PageSize: 10, Total: 0}, 0, false, false},
+		{"неполная страница", Page{Page: 1, PageSize: 10, Total: 5}, 1, false, false},
+		{"ровно две страницы", Page{Page: 1, PageSize: 10, Total: 20}, 2, true, false},
+		{"неполная последняя страница", Page{Page: 2, PageSize: 10, Total: 21}, 3, true, true},
+		{"последняя страница", Page{Page: 3, PageSize: 10, Total: 21}, 3, false, true},
+		{"страница за пределами выборки", Page{Page: 5, PageSize: 10, Total: 21}, 3, false, true},
+		{"нулевой номер страницы считается первой", Page{Page: 0, PageSize: 10, Total: 21}, 3, true, false},
+		{"нулевой размер", Page{Page: 1, PageSize: 0, Total: 21}, 0, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.page.TotalPages(); got != tt.want {
+				t.Errorf("%+v.TotalPages() = %d, want %d", tt.page, got, tt.want)
+			}
+			if got := tt.page.HasNext(); got != tt.wantNext {
+				t.Errorf("%+v.HasNext() = %v, want %v", tt.page, got, tt.wantNext)
+			}
+			if got := tt.page.HasPrev(); got != tt.wantPrev {
+				t.Errorf("%+v.HasPrev() = %v, want %v", tt.page, got, tt.wantPrev)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package readonly
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"sync/atomic"
+)
+
+var readOnlyMode = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "read_only_mode",
+	Help: "Режим только для чтения (1 — изменения отклоняются, 0 — обычная работа)",
+})
+
+// Switch переключатель режима только для чтения, безопасный для одновременного использования
+type Switch struct {
+	enabled atomic.Bool
+}
+
+// NewSwitch создает переключатель в начальном состоянии enabled
+func NewSwitch(enabled bool) *Switch {
+	s := &Switch{}
+	s.Set(enabled)
+	return s
+}
+
+// Enabled сообщает, включен ли режим только для чтения
+func (s *Switch) Enabled() bool {
+	return s.enabled.Load()
+}
+
+// Set включает или выключает режим только для чтения и возвращает предыдущее состояние
+func (s *Switch) Set(enabled bool) bool {
+	previous := s.enabled.Swap(enabled)
+	if enabled {
+		readOnlyMode.Set(1)
+	} else {
+		readOnlyMode.Set(0)
+	}
+	return previous
+}
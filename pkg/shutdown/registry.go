@@ -0,0 +1,72 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"song-library/pkg/logger"
+	"sync"
+	"time"
+)
+
+// ShutdownFunc шаг остановки компонента
+type ShutdownFunc func(ctx context.Context) error
+
+// step именованный шаг остановки
+type step struct {
+	name string
+	fn   ShutdownFunc
+}
+
+// Registry реестр шагов остановки компонентов.
+// Шаги выполняются в порядке, обратном регистрации: компоненты, созданные позже,
+// останавливаются раньше тех, от которых они зависят.
+type Registry struct {
+	mu     sync.Mutex
+	steps  []step
+	logger *logger.Logger
+}
+
+// NewRegistry создает пустой реестр шагов остановки
+func NewRegistry(logger *logger.Logger) *Registry {
+	return &Registry{logger: logger}
+}
+
+// Register добавляет шаг остановки с именем name
+func (r *Registry) Register(name string, fn ShutdownFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.steps = append(r.steps, step{name: name, fn: fn})
+}
+
+// Shutdown выполняет шаги в обратном порядке регистрации и возвращает все ошибки, объединенные errors.Join.
+// Ошибка шага не прерывает остановку; после истечения ctx оставшиеся шаги пропускаются и попадают в ошибку.
+func (r *Registry) Shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	steps := append([]step(nil), r.steps...)
+	r.mu.Unlock()
+
+	log := r.logger.WithContext(ctx)
+
+	var errs []error
+	for i := len(steps) - 1; i >= 0; i-- {
+		s := steps[i]
+		if err := ctx.Err(); err != nil {
+			log.Error("Шаг остановки пропущен", "step", s.name, "error", err)
+			errs = append(errs, fmt.Errorf("%s: шаг пропущен: %w", s.name, err))
+			continue
+		}
+
+		started := time.Now()
+		err := s.fn(ctx)
+		duration := time.Since(started)
+		if err != nil {
+			log.Error("Ошибка шага остановки", "step", s.name, "duration", duration, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+			continue
+		}
+		log.Info("Шаг остановки выполнен", "step", s.name, "duration", duration)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,87 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"reflect"
+	"song-library/pkg/logger"
+	"strings"
+	"testing"
+)
+
+// newTestLogger возвращает логгер, который ничего не выводит
+func newTestLogger() *logger.Logger {
+	return &logger.Logger{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+}
+
+func TestRegistryShutdown(t *testing.T) {
+	errFlush := errors.New("flush failed")
+	errClose := errors.New("close failed")
+
+	tests := []struct {
+		name      string
+		failing   map[string]error
+		cancelAt  string
+		wantOrder []string
+		wantErrs  []string
+	}{
+		{"обратный порядок регистрации", nil, "", []string{"http", "workers", "db"}, nil},
+		{"ошибка шага не прерывает остановку", map[string]error{"workers": errFlush, "db": errClose},
+			"", []string{"http", "workers", "db"}, []string{"workers: flush failed", "db: close failed"}},
+		// Шаг http исчерпывает время остановки: оставшиеся шаги не выполняются, но попадают в ошибку
+		{"истечение контекста пропускает шаги", nil, "http", []string{"http"},
+			[]string{"workers: шаг пропущен: context canceled", "db: шаг пропущен: context canceled"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			registry := NewRegistry(newTestLogger())
+			var order []string
+			for _, name := range []string{"db", "workers", "http"} {
+				registry.Register(name, func(context.Context) error {
+					order = append(order, name)
+					if name == tt.cancelAt {
+						cancel()
+					}
+					return tt.failing[name]
+				})
+			}
+
+			err := registry.Shutdown(ctx)
+			if !reflect.DeepEqual(order, tt.wantOrder) {
+				t.Errorf("порядок шагов = %v, want %v", order, tt.wantOrder)
+			}
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Errorf("Shutdown() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Shutdown() error = nil, want %v", tt.wantErrs)
+			}
+			if got := strings.Split(err.Error(), "\n"); !reflect.DeepEqual(got, tt.wantErrs) {
+				t.Errorf("Shutdown() error = %q, want %q", got, tt.wantErrs)
+			}
+			for _, target := range tt.failing {
+				if !errors.Is(err, target) {
+					t.Errorf("errors.Is(Shutdown(), %v) = false", target)
+				}
+			}
+			if tt.cancelAt != "" && !errors.Is(err, context.Canceled) {
+				t.Errorf("errors.Is(Shutdown(), context.Canceled) = false")
+			}
+		})
+	}
+}
+
+func TestRegistryShutdownEmpty(t *testing.T) {
+	if err := NewRegistry(newTestLogger()).Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown() пустого реестра error = %v", err)
+	}
+}